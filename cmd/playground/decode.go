@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+// prevoutList collects repeated -prevout flags of the form "value:scriptPubKeyHex"
+type prevoutList []*tx.TxOut
+
+func (p *prevoutList) String() string {
+	return fmt.Sprintf("%d prevouts", len(*p))
+}
+
+func (p *prevoutList) Set(s string) error {
+	value, script, ok := strings.Cut(s, ":")
+	if !ok {
+		return errors.New("expected value:scriptPubKeyHex")
+	}
+	amount, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid value: %w", err)
+	}
+	pkScript, err := hex.DecodeString(script)
+	if err != nil {
+		return fmt.Errorf("invalid script: %w", err)
+	}
+	*p = append(*p, &tx.TxOut{Value: amount, PkScript: pkScript})
+	return nil
+}
+
+// runDecode implements "playground decode [-network name] [-json] [-prevout v:script ...] [hex]"
+//
+// The raw transaction is read from the first argument, or from stdin if omitted.
+func runDecode(args []string) error {
+	fs := flag.NewFlagSet("decode", flag.ContinueOnError)
+	network := fs.String("network", "mainnet", "network used to render addresses ("+strings.Join(chaincfg.Networks(), ", ")+")")
+	testnet := fs.Bool("testnet", false, "shorthand for -network testnet")
+	asJSON := fs.Bool("json", false, "print the explanation as JSON")
+	var prevouts prevoutList
+	fs.Var(&prevouts, "prevout", "spent output as value:scriptPubKeyHex (repeat once per input, in order)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Step 1: Read the raw transaction hex
+	var rawHex string
+	if fs.NArg() > 0 {
+		rawHex = fs.Arg(0)
+	} else {
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		rawHex = string(input)
+	}
+
+	transaction, err := tx.DeserializeHex(strings.TrimSpace(rawHex))
+	if err != nil {
+		return err
+	}
+
+	// Step 2: Explain it for the selected network
+	if *testnet {
+		*network = "testnet"
+	}
+	params, err := chaincfg.ParamsForName(*network)
+	if err != nil {
+		return err
+	}
+	var spent []*tx.TxOut
+	if len(prevouts) > 0 {
+		spent = prevouts
+	}
+	explanation, err := tx.Explain(transaction, spent, params)
+	if err != nil {
+		return err
+	}
+
+	// Step 3: Print as text or JSON
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(explanation)
+	}
+	fmt.Print(explanation)
+	return nil
+}
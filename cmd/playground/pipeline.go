@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/wif"
+)
+
+// maxRecordSize is the longest JSONL line accepted (1 MiB); longer lines become error results
+const maxRecordSize = 1 << 20
+
+// maxInFlightPerWorker bounds the records read but not yet written, so that one
+// slow record cannot make every later result wait in memory
+const maxInFlightPerWorker = 4
+
+// pipelineRecord is one JSONL input line
+//
+// The message is given as text ("message") or hex ("message_hex"); an empty
+// message ("") is valid, only a missing one is an error. Signing references a
+// key by name from the -keys file; verification uses either a named key or an
+// explicit x-only public key.
+type pipelineRecord struct {
+	ID         string  `json:"id,omitempty"`
+	Op         string  `json:"op,omitempty"` // "sign" or "verify"; defaults to -op
+	Message    *string `json:"message,omitempty"`
+	MessageHex *string `json:"message_hex,omitempty"`
+	Key        string  `json:"key,omitempty"`
+	PubKey     string  `json:"pubkey,omitempty"` // x-only, hex
+	Signature  string  `json:"signature,omitempty"`
+}
+
+// pipelineResult is one JSONL output line, in the same order as the input
+type pipelineResult struct {
+	Line      int    `json:"line"`
+	ID        string `json:"id,omitempty"`
+	Op        string `json:"op,omitempty"`
+	PubKey    string `json:"pubkey,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Valid     *bool  `json:"valid,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// pipelineJob is a raw input line waiting for a worker
+type pipelineJob struct {
+	seq     int // Position among non-blank lines, used to restore input order
+	line    int
+	data    []byte
+	tooLong bool // The line exceeded maxRecordSize and was skipped
+}
+
+// pipelineOutput is a result tagged with its job's sequence number
+type pipelineOutput struct {
+	seq    int
+	result pipelineResult
+}
+
+// runPipeline implements "playground pipeline [-keys file] [-op sign|verify] [-workers n]"
+//
+// Records are read from stdin as JSONL and results are written to stdout as
+// JSONL in input order. A bad record produces an error result rather than
+// stopping the pipeline; a summary is printed to stderr.
+//
+// Example:
+//
+//	echo '{"id":"1","message":"hello","key":"alice"}' | playground pipeline -keys keys.json
+//	// {"line":1,"id":"1","op":"sign","pubkey":"...","signature":"..."}
+func runPipeline(args []string) error {
+	fs := flag.NewFlagSet("pipeline", flag.ContinueOnError)
+	keysPath := fs.String("keys", "", "JSON file mapping key names to WIF private keys")
+	op := fs.String("op", "sign", "default operation for records without \"op\" (sign or verify)")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of concurrent workers")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *op != "sign" && *op != "verify" {
+		return fmt.Errorf("unknown operation %q", *op)
+	}
+	if *workers < 1 {
+		return errors.New("workers must be at least 1")
+	}
+
+	keys := map[string]*btcec.PrivateKey{}
+	if *keysPath != "" {
+		var err error
+		if keys, err = loadKeys(*keysPath); err != nil {
+			return err
+		}
+	}
+
+	processed, failed, err := pipeline(os.Stdin, os.Stdout, keys, *op, *workers)
+	fmt.Fprintf(os.Stderr, "pipeline: %d records, %d errors\n", processed, failed)
+	return err
+}
+
+// loadKeys reads a {"name": "WIF"} file
+func loadKeys(path string) (map[string]*btcec.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var encoded map[string]string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("invalid key file: %w", err)
+	}
+
+	keys := make(map[string]*btcec.PrivateKey, len(encoded))
+	for name, w := range encoded {
+		priv, _, _, err := wif.DecodeToPrivKey(w)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", name, err)
+		}
+		keys[name] = priv
+	}
+	return keys, nil
+}
+
+// pipeline fans records out to workers and writes results back in input order
+func pipeline(in io.Reader, out io.Writer, keys map[string]*btcec.PrivateKey, defaultOp string, workers int) (int, int, error) {
+	jobs := make(chan pipelineJob, workers*2)
+	results := make(chan pipelineOutput, workers*2)
+
+	// Step 1: Workers process records independently
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				results <- pipelineOutput{seq: job.seq, result: processRecord(job, keys, defaultOp)}
+			}
+		}()
+	}
+
+	// Step 2: The reader feeds lines to the workers, waiting for a slot in inFlight
+	// so that at most maxInFlightPerWorker records per worker are pending
+	inFlight := make(chan struct{}, workers*maxInFlightPerWorker)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(jobs)
+		r := bufio.NewReaderSize(in, 64*1024)
+		line, seq := 0, 0
+		for {
+			data, tooLong, err := readRecord(r)
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				readErr <- err
+				return
+			}
+			line++
+			if len(data) == 0 && !tooLong {
+				continue
+			}
+			inFlight <- struct{}{}
+			jobs <- pipelineJob{seq: seq, line: line, data: data, tooLong: tooLong}
+			seq++
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Step 3: Reorder results so the output matches the input order
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+	pending := make(map[int]pipelineResult)
+	next, processed, failed := 0, 0, 0
+	var writeErr error
+	for output := range results {
+		pending[output.seq] = output.result
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			processed++
+			if r.Error != "" {
+				failed++
+			}
+			if writeErr == nil {
+				writeErr = enc.Encode(r)
+			}
+			<-inFlight
+		}
+	}
+
+	if err := w.Flush(); writeErr == nil {
+		writeErr = err
+	}
+	if err := <-readErr; err != nil {
+		return processed, failed, fmt.Errorf("reading input: %w", err)
+	}
+	return processed, failed, writeErr
+}
+
+// readRecord reads one line without its line ending
+//
+// A line longer than maxRecordSize is consumed to its end but not kept, and
+// reported as tooLong so the pipeline can emit an error result and go on.
+func readRecord(r *bufio.Reader) ([]byte, bool, error) {
+	var line []byte
+	tooLong := false
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			return nil, false, err
+		}
+		if !tooLong && len(line)+len(chunk) > maxRecordSize {
+			tooLong, line = true, nil
+		}
+		if !tooLong {
+			line = append(line, chunk...)
+		}
+		if !isPrefix {
+			return line, tooLong, nil
+		}
+	}
+}
+
+// processRecord signs or verifies one record
+func processRecord(job pipelineJob, keys map[string]*btcec.PrivateKey, defaultOp string) pipelineResult {
+	result := pipelineResult{Line: job.line}
+	if job.tooLong {
+		result.Error = fmt.Sprintf("record is longer than %d bytes", maxRecordSize)
+		return result
+	}
+
+	var rec pipelineRecord
+	if err := json.Unmarshal(job.data, &rec); err != nil {
+		result.Error = fmt.Sprintf("invalid record: %v", err)
+		return result
+	}
+	result.ID = rec.ID
+	result.Op = rec.Op
+	if result.Op == "" {
+		result.Op = defaultOp
+	}
+
+	msg, err := recordMessage(&rec)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	// Hash here rather than in SignBIP340, which refuses empty messages
+	digest := sha256.Sum256(msg)
+
+	switch result.Op {
+	case "sign":
+		priv, ok := keys[rec.Key]
+		if !ok {
+			result.Error = fmt.Sprintf("unknown key %q", rec.Key)
+			return result
+		}
+		sig, err := schnorr.SignDigest(digest, priv)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		xOnly := schnorr.XOnlyFromPub(priv.PubKey())
+		result.PubKey = hex.EncodeToString(xOnly[:])
+		result.Signature = hex.EncodeToString(sig[:])
+
+	case "verify":
+		xOnly, err := recordPubKey(&rec, keys)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		sigBytes, err := hex.DecodeString(rec.Signature)
+		if err != nil || len(sigBytes) != 64 {
+			result.Error = "signature must be 64 bytes of hex"
+			return result
+		}
+		pub, err := schnorr.ParseXOnly(xOnly)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		valid := schnorr.VerifyDigest(digest, pub, [64]byte(sigBytes))
+		result.PubKey = hex.EncodeToString(xOnly[:])
+		result.Valid = &valid
+
+	default:
+		result.Error = fmt.Sprintf("unknown operation %q", result.Op)
+	}
+	return result
+}
+
+// recordMessage returns the message bytes of a record
+func recordMessage(rec *pipelineRecord) ([]byte, error) {
+	switch {
+	case rec.Message != nil && rec.MessageHex != nil:
+		return nil, errors.New("give either message or message_hex, not both")
+	case rec.MessageHex != nil:
+		msg, err := hex.DecodeString(*rec.MessageHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message_hex: %w", err)
+		}
+		return msg, nil
+	case rec.Message != nil:
+		return []byte(*rec.Message), nil
+	default:
+		return nil, errors.New("record has no message")
+	}
+}
+
+// recordPubKey returns the x-only key to verify against: explicit, or from a named key
+func recordPubKey(rec *pipelineRecord, keys map[string]*btcec.PrivateKey) ([32]byte, error) {
+	var xOnly [32]byte
+	if rec.PubKey != "" {
+		pub, err := hex.DecodeString(rec.PubKey)
+		if err != nil || len(pub) != 32 {
+			return xOnly, errors.New("pubkey must be 32 bytes of hex (x-only)")
+		}
+		copy(xOnly[:], pub)
+		return xOnly, nil
+	}
+	priv, ok := keys[rec.Key]
+	if !ok {
+		return xOnly, fmt.Errorf("unknown key %q", rec.Key)
+	}
+	return schnorr.XOnlyFromPub(priv.PubKey()), nil
+}
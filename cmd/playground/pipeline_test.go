@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+	"github.com/neverDefined/cryptography-playground/pkg/base58"
+)
+
+// keyOneWIF is the compressed mainnet WIF of the private key 1
+const keyOneWIF = "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn"
+
+// runTestPipeline feeds input through pipeline and decodes the JSONL output
+func runTestPipeline(t *testing.T, input string, keys map[string]*btcec.PrivateKey, op string, workers int) ([]pipelineResult, int, int) {
+	t.Helper()
+	var out bytes.Buffer
+	processed, failed, err := pipeline(strings.NewReader(input), &out, keys, op, workers)
+	if err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+
+	var results []pipelineResult
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var r pipelineResult
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("Invalid output line %q: %v", scanner.Text(), err)
+		}
+		results = append(results, r)
+	}
+	return results, processed, failed
+}
+
+func testKeys(t *testing.T) map[string]*btcec.PrivateKey {
+	t.Helper()
+	alice, _ := btcec.PrivKeyFromBytes([]byte{0x01})
+	bob, _ := btcec.PrivKeyFromBytes([]byte{0x02})
+	return map[string]*btcec.PrivateKey{"alice": alice, "bob": bob}
+}
+
+// TestPipelineOrder checks that many workers still write results in input order
+func TestPipelineOrder(t *testing.T) {
+	keys := testKeys(t)
+
+	// Every third record is bad, in a different way each time; blank lines are skipped
+	var input strings.Builder
+	badRecords := []string{
+		`{"id":"%d","message":"m","key":"carol"}`,
+		`{"id":"%d","message":"m","message_hex":"00","key":"alice"}`,
+		`{"id":"%d","key":"alice"}`,
+		`{"id":"%d","message_hex":"zz","key":"alice"}`,
+		`{"id":"%d","op":"encrypt","message":"m","key":"alice"}`,
+		`{"id":"%d","op":"verify","message":"m","key":"alice","signature":"00"}`,
+	}
+	const records = 300
+	expectedFailed := 0
+	for i := 0; i < records; i++ {
+		if i%3 == 2 {
+			fmt.Fprintf(&input, badRecords[(i/3)%len(badRecords)]+"\n", i)
+			expectedFailed++
+		} else {
+			fmt.Fprintf(&input, `{"id":"%d","message":"record %d","key":"bob"}`+"\n", i, i)
+		}
+		if i%50 == 0 {
+			input.WriteString("\n")
+		}
+	}
+	input.WriteString("not json\n")
+	expectedFailed++
+
+	results, processed, failed := runTestPipeline(t, input.String(), keys, "sign", 8)
+	if processed != records+1 || len(results) != records+1 {
+		t.Fatalf("Expected %d results, got %d (%d written)", records+1, processed, len(results))
+	}
+	if failed != expectedFailed {
+		t.Errorf("Expected %d errors, got %d", expectedFailed, failed)
+	}
+
+	line := 0
+	for i, r := range results[:records] {
+		if r.ID != fmt.Sprint(i) {
+			t.Fatalf("Result %d has id %q: output is out of order", i, r.ID)
+		}
+		if r.Line <= line {
+			t.Errorf("Result %d has line %d after line %d", i, r.Line, line)
+		}
+		line = r.Line
+		if bad := i%3 == 2; bad != (r.Error != "") {
+			t.Errorf("Result %d: error = %q", i, r.Error)
+		}
+	}
+	if last := results[records]; last.Error == "" || !strings.HasPrefix(last.Error, "invalid record") {
+		t.Errorf("Expected an invalid record error for the last line, got %+v", last)
+	}
+}
+
+// TestPipelineLongRecord checks that an oversized line is one error result, not the end of the run
+func TestPipelineLongRecord(t *testing.T) {
+	keys := testKeys(t)
+	padding := strings.Repeat("x", maxRecordSize)
+	input := `{"id":"before","message":"a","key":"alice"}` + "\n" +
+		`{"id":"long","message":"` + padding + `","key":"alice"}` + "\n" +
+		`{"id":"limit","message":"` + padding[:maxRecordSize-41] + `","key":"alice"}` + "\n" + // exactly maxRecordSize bytes
+		`{"id":"after","message":"b","key":"bob"}`
+
+	results, processed, failed := runTestPipeline(t, input, keys, "sign", 2)
+	if processed != 4 || failed != 1 {
+		t.Fatalf("Expected 4 results with 1 error, got %d with %d errors", processed, failed)
+	}
+	if r := results[1]; r.Line != 2 || !strings.Contains(r.Error, "longer than") {
+		t.Errorf("Expected a too-long error for line 2, got %+v", r)
+	}
+	for _, i := range []int{0, 2, 3} {
+		if r := results[i]; r.Error != "" || r.Line != i+1 || r.Signature == "" {
+			t.Errorf("Expected line %d to be signed, got %+v", i+1, r)
+		}
+	}
+}
+
+// TestPipelineSignVerify signs records and feeds the signatures back for verification
+func TestPipelineSignVerify(t *testing.T) {
+	keys := testKeys(t)
+	input := `{"id":"text","message":"hello","key":"alice"}
+{"id":"hex","message_hex":"deadbeef","key":"bob"}
+{"id":"empty","message":"","key":"alice"}
+{"id":"empty hex","message_hex":"","key":"bob"}
+`
+	signed, _, failed := runTestPipeline(t, input, keys, "sign", 3)
+	if failed != 0 {
+		t.Fatalf("Expected no errors, got %+v", signed)
+	}
+
+	// Verify against the returned x-only key, then against a tampered message
+	var verify strings.Builder
+	messages := []string{`"message":"hello"`, `"message_hex":"deadbeef"`, `"message":""`, `"message_hex":""`}
+	for i, r := range signed {
+		fmt.Fprintf(&verify, `{"id":%q,%s,"pubkey":%q,"signature":%q}`+"\n", r.ID, messages[i], r.PubKey, r.Signature)
+		fmt.Fprintf(&verify, `{"id":%q,"message":"other","pubkey":%q,"signature":%q}`+"\n", r.ID, r.PubKey, r.Signature)
+	}
+	results, processed, failed := runTestPipeline(t, verify.String(), keys, "verify", 4)
+	if processed != 2*len(signed) || failed != 0 {
+		t.Fatalf("Expected %d results without errors, got %d with %d errors", 2*len(signed), processed, failed)
+	}
+	for i, r := range results {
+		expected := i%2 == 0
+		if r.Op != "verify" || r.Valid == nil || *r.Valid != expected {
+			t.Errorf("Result %d (%s): expected valid = %v, got %+v", i, r.ID, expected, r)
+		}
+	}
+}
+
+// TestLoadKeys checks that keys outside [1, n-1] are rejected instead of reduced
+func TestLoadKeys(t *testing.T) {
+	writeKeys := func(keys map[string]string) string {
+		data, _ := json.Marshal(keys)
+		path := filepath.Join(t.TempDir(), "keys.json")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		return path
+	}
+	compressedWIF := func(key []byte) string {
+		return base58.Base58CheckEncode(0x80, append(key, 0x01))
+	}
+
+	keys, err := loadKeys(writeKeys(map[string]string{"alice": keyOneWIF}))
+	if err != nil {
+		t.Fatalf("loadKeys failed: %v", err)
+	}
+	if keys["alice"].Key != testKeys(t)["alice"].Key {
+		t.Error("Expected the WIF to decode to the key 1")
+	}
+
+	for name, key := range map[string][]byte{
+		"zero":  make([]byte, 32),
+		"order": arithmetic.GetCurveOrder().FillBytes(make([]byte, 32)),
+	} {
+		if _, err := loadKeys(writeKeys(map[string]string{name: compressedWIF(key)})); err == nil {
+			t.Errorf("Expected loadKeys to reject the %s key", name)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/neverDefined/cryptography-playground/pkg/vectors"
+)
+
+// runVectors implements "playground vectors [-run suite.json]"
+//
+// Without flags, the module's interop vector suite is written to stdout.
+// With -run, an external suite is checked against this module's decoders.
+func runVectors(args []string) error {
+	fs := flag.NewFlagSet("vectors", flag.ContinueOnError)
+	suitePath := fs.String("run", "", "check the vectors in this JSON suite instead of exporting")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *suitePath == "" {
+		return vectors.Export().WriteJSON(os.Stdout)
+	}
+
+	file, err := os.Open(*suitePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	suite, err := vectors.ReadSuite(file)
+	if err != nil {
+		return err
+	}
+
+	report := vectors.Run(suite)
+	for _, f := range report.Failures {
+		fmt.Printf("FAIL #%d %s %q: %v\n", f.Index, f.Vector.Kind, f.Vector.Encoded, f.Err)
+	}
+	fmt.Printf("%d passed, %d failed, %d skipped\n", report.Passed, len(report.Failures), report.Skipped)
+	if !report.OK() {
+		return fmt.Errorf("%d vectors failed", len(report.Failures))
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package common
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/base58"
+	"github.com/neverDefined/cryptography-playground/pkg/bech32"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/wif"
+)
+
+// Shared example participants
+//
+// Every example program uses the same named people, derived from fixed seeds,
+// so their output is reproducible and one example can refer to values printed
+// by another ("Alice's P2TR address from examples/schnorr"). The seeds are
+// public: never send real funds to these keys.
+//
+//	seed = SHA256("cryptography-playground/examples/" + lowercase(name))
+
+// seedPrefix is hashed together with the participant's name
+const seedPrefix = "cryptography-playground/examples/"
+
+// Participant is a named, deterministic key holder
+type Participant struct {
+	Name       string
+	Seed       [32]byte
+	PrivateKey *btcec.PrivateKey
+	PublicKey  *btcec.PublicKey
+	XOnly      [32]byte // BIP340 x-only public key
+}
+
+// Addresses are a participant's single-key addresses on one network
+type Addresses struct {
+	WIF    string // Compressed WIF private key
+	P2PKH  string
+	P2WPKH string
+	P2TR   string // BIP86 key path only
+}
+
+var (
+	// Alice is the first participant in every example
+	Alice = NewParticipant("Alice")
+	// Bob is the second participant
+	Bob = NewParticipant("Bob")
+	// Carol is the third participant
+	Carol = NewParticipant("Carol")
+)
+
+// All returns Alice, Bob and Carol in that order
+func All() []*Participant {
+	return []*Participant{Alice, Bob, Carol}
+}
+
+// NewParticipant derives a participant from a name
+//
+// The seed is rehashed in the (negligible) case it is not a valid private key.
+//
+// Example:
+//
+//	dave := common.NewParticipant("Dave")
+//	fmt.Printf("%x\n", dave.XOnly)
+func NewParticipant(name string) *Participant {
+	seed := sha256.Sum256([]byte(seedPrefix + strings.ToLower(name)))
+	key := seed
+	var scalar btcec.ModNScalar
+	for overflow := scalar.SetBytes(&key); overflow != 0 || scalar.IsZero(); overflow = scalar.SetBytes(&key) {
+		key = sha256.Sum256(key[:])
+	}
+	priv := btcec.PrivKeyFromScalar(&scalar)
+	return &Participant{
+		Name:       name,
+		Seed:       seed,
+		PrivateKey: priv,
+		PublicKey:  priv.PubKey(),
+		XOnly:      [32]byte(btcschnorr.SerializePubKey(priv.PubKey())),
+	}
+}
+
+// Addresses returns the participant's WIF and addresses on a network
+//
+// Example:
+//
+//	addrs, _ := common.Alice.Addresses(&chaincfg.RegressionNetParams)
+//	fmt.Println(addrs.P2WPKH)
+//	// Result: "bcrt1q..."
+func (p *Participant) Addresses(params *chaincfg.Params) (*Addresses, error) {
+	if params == nil {
+		return nil, fmt.Errorf("network params are required")
+	}
+	key := p.PrivateKey.Key.Bytes()
+	w, err := wif.EncodeWithParams(key[:], true, params)
+	if err != nil {
+		return nil, err
+	}
+	pubKeyHash := hash.Hash160(p.PublicKey.SerializeCompressed())
+	addrs := &Addresses{
+		WIF:   w,
+		P2PKH: base58.Base58CheckEncode(params.PubKeyHashAddrID, pubKeyHash[:]),
+	}
+	if !params.SupportsSegwit() {
+		return addrs, nil
+	}
+	if addrs.P2WPKH, err = bech32.SegWitAddressEncode(params.Bech32HRPSegwit, 0, pubKeyHash[:]); err != nil {
+		return nil, err
+	}
+	outputKey, _, err := schnorr.TweakPubKey(p.PublicKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	if addrs.P2TR, err = bech32.SegWitAddressEncode(params.Bech32HRPSegwit, 1, outputKey[:]); err != nil {
+		return nil, err
+	}
+	return addrs, nil
+}
+
+// String returns the participant's name and x-only key
+func (p *Participant) String() string {
+	return fmt.Sprintf("%s (%x)", p.Name, p.XOnly)
+}
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/bip322"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+)
+
+// TestParticipantsPinned pins the values the example outputs refer to
+func TestParticipantsPinned(t *testing.T) {
+	tests := []struct {
+		p                        *Participant
+		xOnly, wif, p2wpkh, p2tr string
+	}{
+		{Alice, "c62481d6859e5653948a265b4c5186f0101eb377c4a3f49719288241ca6a45d4",
+			"KyBmeP5gp9tk6EL1BR4LLHnD8gWrmA4gGwDmXYWcKvVoqFomBMwV",
+			"bc1q0xrg3jn22tlw52lrgmmgatwj2v5tmzkth92cqy",
+			"bc1ple68ckj8je4sjz0ncpuvn8nseq33wu5s3e6ms0yh67q9k6zfcjsqqlfv3k"},
+		{Bob, "c0cf89340902336fab62f7855a1b14a44d074a4957caae5f3bc41563dbdcc40d",
+			"KxxVrbtbtaqpVAAKzup2nN2A6UNSqDHcW4ZXYV8EXghpp9uvgZKr",
+			"bc1qd93nm05cyupudkk7mj604cmzhv9qpmyu8rh4sy",
+			"bc1p4dz9w82vvsdymsrk9t3sswmu4k2hmrah9uul7svp6c5l2ks0azkqfagmqe"},
+		{Carol, "9f305bdb7f63b1bada39a92003fc23bc3a94de723cd667facbcfddf8c993dd38",
+			"L3AjEFS3z6psVfQ5VAKBqyFeSzgkX38uRhbZKYnj1pELLBHyWa8Z",
+			"bc1qepxvhupesaxpmp23yh8ltem47naeta4k4ewf32",
+			"bc1pdv7qxnmrmw0vp5g2tjsjamdusggvl69kpyc894rf69hnw04nr9pqlj0qxz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.p.Name, func(t *testing.T) {
+			if got := hex.EncodeToString(tt.p.XOnly[:]); got != tt.xOnly {
+				t.Errorf("Expected x-only key %s, got %s", tt.xOnly, got)
+			}
+			addrs, err := tt.p.Addresses(&chaincfg.MainNetParams)
+			if err != nil {
+				t.Fatalf("Addresses failed: %v", err)
+			}
+			if addrs.WIF != tt.wif || addrs.P2WPKH != tt.p2wpkh || addrs.P2TR != tt.p2tr {
+				t.Errorf("Unexpected addresses %+v", addrs)
+			}
+		})
+	}
+}
+
+// TestAddressesSpendable checks the addresses against an independent signer
+func TestAddressesSpendable(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	addrs, err := Bob.Addresses(params)
+	if err != nil {
+		t.Fatalf("Addresses failed: %v", err)
+	}
+	msg := []byte("Bob owns this address")
+	for _, addr := range []string{addrs.P2WPKH, addrs.P2TR} {
+		sig, err := bip322.Sign(msg, addr, Bob.PrivateKey, params)
+		if err != nil {
+			t.Fatalf("bip322.Sign(%s) failed: %v", addr, err)
+		}
+		if err := bip322.Verify(msg, addr, sig, params); err != nil {
+			t.Errorf("Expected %s to belong to Bob: %v", addr, err)
+		}
+	}
+}
+
+func TestNewParticipant(t *testing.T) {
+	if NewParticipant("alice").XOnly != Alice.XOnly {
+		t.Error("Expected names to be case-insensitive")
+	}
+	if NewParticipant("Dave").XOnly == Alice.XOnly {
+		t.Error("Expected different names to give different keys")
+	}
+	if _, err := Alice.Addresses(nil); err == nil {
+		t.Error("Expected error for nil params")
+	}
+}
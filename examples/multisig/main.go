@@ -6,7 +6,7 @@ import (
 	"log"
 	"math/big"
 
-	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/examples/common"
 	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
 	"github.com/neverDefined/cryptography-playground/pkg/multisig"
 )
@@ -14,20 +14,17 @@ import (
 func main() {
 	fmt.Println("=== Bitcoin Multisignature Example ===")
 
-	// 1) Generate multiple key pairs for participants
-	fmt.Println("1) Generating key pairs for participants...")
+	// 1) Alice, Bob and Carol (deterministic keys shared by all examples)
+	fmt.Println("1) Loading key pairs for participants...")
 	participants := make([]*multisig.Participant, 3)
-	for i := range participants {
-		priv, err := btcec.NewPrivateKey()
-		if err != nil {
-			log.Fatal(err)
-		}
+	for i, p := range common.All() {
 		participants[i] = &multisig.Participant{
-			PrivateKey: priv,
-			PublicKey:  priv.PubKey(),
+			PrivateKey: p.PrivateKey,
+			PublicKey:  p.PublicKey,
 			Index:      i,
+			Label:      p.Name,
 		}
-		fmt.Printf("   Participant %d: %x\n", i, priv.Key.Bytes())
+		fmt.Printf("   Participant %d: %s\n", i, p)
 	}
 
 	// 2) Create a 2-of-3 multisignature setup
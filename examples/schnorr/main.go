@@ -5,28 +5,30 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/examples/common"
 	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
 )
 
 func main() {
 	fmt.Println("=== Bitcoin Taproot Schnorr Signature Example ===")
 
-	// 1) Key generation
-	fmt.Println("1) Generating Bitcoin key pair...")
-	priv, err := btcec.NewPrivateKey()
+	// 1) Key generation (Alice's deterministic key, shared by all examples)
+	fmt.Println("1) Using Alice's key pair...")
+	keyPair, err := schnorr.NewKeyPair(common.Alice.PrivateKey)
 	if err != nil {
 		log.Fatal(err)
 	}
-	pub := priv.PubKey()
+	priv := keyPair.PrivateKey()
+	pub := keyPair.PubKey()
 
-	fmt.Printf("   Private key: %x\n", priv.Key.Bytes())
-	fmt.Printf("   Public key (compressed): %x\n", pub.SerializeCompressed())
+	// KeyPair never prints the private scalar
+	fmt.Printf("   Key pair: %v\n", keyPair)
+	fmt.Printf("   Public key (compressed): %x\n", keyPair.Compressed())
 
 	// 2) Extract x-only public key (BIP340 uses 32-byte x)
 	fmt.Println("\n2) Extracting x-only public key...")
 	xonly := schnorr.XOnlyFromPub(pub)
-	fmt.Printf("   X-only public key: %x\n", xonly)
+	fmt.Printf("   X-only public key: %s\n", xonly)
 	fmt.Printf("   Size: %d bytes (vs %d bytes for compressed)\n", len(xonly), len(pub.SerializeCompressed()))
 
 	// 3) Sign arbitrary message bytes (BIP340 takes arbitrary-length msg)
@@ -37,7 +39,7 @@ func main() {
 		log.Fatal(err)
 	}
 	fmt.Printf("   Message: %q\n", string(msg))
-	fmt.Printf("   Signature (64B): %x\n", sig[:])
+	fmt.Printf("   Signature (64B): %s\n", sig)
 
 	// 4) Verify using full public key
 	fmt.Println("\n4) Verifying with full public key...")
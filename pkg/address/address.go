@@ -0,0 +1,110 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/neverDefined/cryptography-playground/pkg/base58"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+)
+
+// Pay-to-script-hash (BIP16)
+//
+// A P2SH output commits to a script by its hash instead of spelling it out:
+//
+//	scriptPubKey  OP_HASH160 <HASH160(redeemScript)> OP_EQUAL
+//	address       Base58Check(ScriptHashAddrID, HASH160(redeemScript))  "3..." on mainnet, "2..." on testnets
+//
+// The spender reveals the redeem script as the last push of the scriptSig,
+// followed by whatever it needs (signatures for a multisig script, the
+// witness program for nested segwit). Because it is a single push, the
+// redeem script can be at most 520 bytes, which is why P2SH multisig stops
+// at 15 compressed keys. Nothing checks this when the address is made, so a
+// too-large script gives an address whose coins can never be spent;
+// P2SHFromScript refuses it instead.
+
+// MaxRedeemScriptSize is the largest redeem script that fits in a single push (MAX_SCRIPT_ELEMENT_SIZE)
+const MaxRedeemScriptSize = 520
+
+var (
+	// ErrScriptTooLarge is returned for redeem scripts longer than MaxRedeemScriptSize
+	ErrScriptTooLarge = errors.New("redeem script too large")
+	// ErrNotP2SH is returned when decoding an address that is not a P2SH address of the network
+	ErrNotP2SH = errors.New("not a P2SH address")
+)
+
+// P2SHFromScript returns the P2SH address paying to redeemScript on the network of params
+//
+// Example:
+//
+//	// P2SH-P2WPKH (nested segwit) for the key 1: the redeem script is the P2WPKH program
+//	redeemScript, _ := hex.DecodeString("0014751e76e8199196d454941c45d1b3a323f1433bd6")
+//	addr, err := P2SHFromScript(redeemScript, &chaincfg.MainNetParams)
+//	// Result: "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN"
+func P2SHFromScript(redeemScript []byte, params *chaincfg.Params) (string, error) {
+	// Step 1: Validate the inputs
+	if params == nil {
+		return "", errors.New("network params are required")
+	}
+	if len(redeemScript) == 0 {
+		return "", errors.New("redeem script is empty")
+	}
+	if len(redeemScript) > MaxRedeemScriptSize {
+		return "", fmt.Errorf("%w: %d bytes, at most %d can be pushed", ErrScriptTooLarge, len(redeemScript), MaxRedeemScriptSize)
+	}
+
+	// Step 2: Commit to the script by its HASH160
+	scriptHash := hash.Hash160(redeemScript)
+	return P2SHFromHash(scriptHash, params), nil
+}
+
+// P2SHFromHash returns the P2SH address of an already hashed redeem script
+func P2SHFromHash(scriptHash [20]byte, params *chaincfg.Params) string {
+	return base58.Base58CheckEncode(params.ScriptHashAddrID, scriptHash[:])
+}
+
+// DecodeP2SH returns the script hash committed to by a P2SH address
+//
+// The address must carry the ScriptHashAddrID of params; a P2PKH address or
+// a P2SH address of another network is ErrNotP2SH. The redeem script itself
+// cannot be recovered: the spender has to know it.
+//
+// Example:
+//
+//	scriptHash, err := DecodeP2SH("3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN", &chaincfg.MainNetParams)
+//	// Result: bcfeb728b584253d5f3f70bcb780e9ef218a68f4
+func DecodeP2SH(addr string, params *chaincfg.Params) ([20]byte, error) {
+	var scriptHash [20]byte
+	if params == nil {
+		return scriptHash, errors.New("network params are required")
+	}
+
+	// Step 1: Decode and verify the checksum
+	payload, version, err := base58.Base58CheckDecode(addr)
+	if err != nil {
+		return scriptHash, err
+	}
+
+	// Step 2: Check the version byte and the hash length
+	if version != params.ScriptHashAddrID {
+		return scriptHash, fmt.Errorf("%w: version 0x%02x, %s uses 0x%02x", ErrNotP2SH, version, params.Name, params.ScriptHashAddrID)
+	}
+	if len(payload) != len(scriptHash) {
+		return scriptHash, fmt.Errorf("%w: payload is %d bytes, expected %d", ErrNotP2SH, len(payload), len(scriptHash))
+	}
+	copy(scriptHash[:], payload)
+	return scriptHash, nil
+}
+
+// MatchesScript reports whether addr is the P2SH address of redeemScript on the network of params
+//
+// Example:
+//
+//	if !MatchesScript(addr, redeemScript, params) {
+//		return errors.New("redeem script does not belong to this address")
+//	}
+func MatchesScript(addr string, redeemScript []byte, params *chaincfg.Params) bool {
+	scriptHash, err := DecodeP2SH(addr, params)
+	return err == nil && scriptHash == hash.Hash160(redeemScript)
+}
@@ -0,0 +1,120 @@
+package address
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/base58"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+// p2wpkhKeyOne is the P2WPKH program of the key 1, the redeem script of its nested segwit address
+var p2wpkhKeyOne, _ = hex.DecodeString("0014751e76e8199196d454941c45d1b3a323f1433bd6")
+
+// TestP2SHFromScript tests the nested segwit address of the key 1 on several networks
+func TestP2SHFromScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   *chaincfg.Params
+		expected string
+	}{
+		{"mainnet", &chaincfg.MainNetParams, "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN"},
+		{"testnet", &chaincfg.TestNet3Params, "2NAUYAHhujozruyzpsFRP63mbrdaU5wnEpN"},
+		{"regtest", &chaincfg.RegressionNetParams, "2NAUYAHhujozruyzpsFRP63mbrdaU5wnEpN"},
+		{"litecoin", &chaincfg.LitecoinMainNetParams, "MR8UQSBr5ULwWheBHznrHk2jxyxkHQu8vB"},
+		{"dogecoin", &chaincfg.DogecoinMainNetParams, "A9faqPqnCRNQcZjkcFTviEQiLrkLPctjsJ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := P2SHFromScript(p2wpkhKeyOne, tt.params)
+			if err != nil {
+				t.Fatalf("P2SHFromScript failed: %v", err)
+			}
+			if addr != tt.expected {
+				t.Errorf("P2SHFromScript() = %s, expected %s", addr, tt.expected)
+			}
+
+			scriptHash, err := DecodeP2SH(addr, tt.params)
+			if err != nil {
+				t.Fatalf("DecodeP2SH failed: %v", err)
+			}
+			if hex.EncodeToString(scriptHash[:]) != "bcfeb728b584253d5f3f70bcb780e9ef218a68f4" {
+				t.Errorf("DecodeP2SH() = %x, expected bcfeb728b584253d5f3f70bcb780e9ef218a68f4", scriptHash)
+			}
+			if P2SHFromHash(scriptHash, tt.params) != addr {
+				t.Error("Expected P2SHFromHash to give the same address")
+			}
+			if !MatchesScript(addr, p2wpkhKeyOne, tt.params) {
+				t.Error("Expected the address to match its redeem script")
+			}
+		})
+	}
+}
+
+// TestP2SHScriptPubKey tests that the address pays to OP_HASH160 <hash> OP_EQUAL
+func TestP2SHScriptPubKey(t *testing.T) {
+	addr, err := P2SHFromScript(p2wpkhKeyOne, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("P2SHFromScript failed: %v", err)
+	}
+	script, err := tx.AddressScript(addr, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("AddressScript failed: %v", err)
+	}
+	if tx.ClassifyScript(script) != tx.ScriptHash {
+		t.Errorf("Expected a P2SH scriptPubKey, got %x", script)
+	}
+	scriptHash, _ := DecodeP2SH(addr, &chaincfg.MainNetParams)
+	if !bytes.Equal(script[2:22], scriptHash[:]) {
+		t.Errorf("scriptPubKey %x does not commit to %x", script, scriptHash)
+	}
+}
+
+// TestP2SHFromScriptErrors tests the redeem script limits
+func TestP2SHFromScriptErrors(t *testing.T) {
+	if _, err := P2SHFromScript(make([]byte, MaxRedeemScriptSize), &chaincfg.MainNetParams); err != nil {
+		t.Errorf("Expected a %d-byte redeem script to be accepted, got %v", MaxRedeemScriptSize, err)
+	}
+	if _, err := P2SHFromScript(make([]byte, MaxRedeemScriptSize+1), &chaincfg.MainNetParams); !errors.Is(err, ErrScriptTooLarge) {
+		t.Errorf("Expected ErrScriptTooLarge, got %v", err)
+	}
+	if _, err := P2SHFromScript(nil, &chaincfg.MainNetParams); err == nil {
+		t.Error("Expected an error for an empty redeem script")
+	}
+	if _, err := P2SHFromScript(p2wpkhKeyOne, nil); err == nil {
+		t.Error("Expected an error without network params")
+	}
+}
+
+// TestDecodeP2SHErrors tests addresses that are not P2SH addresses of the network
+func TestDecodeP2SHErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		addr   string
+		params *chaincfg.Params
+		target error
+	}{
+		{"P2PKH address", "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", &chaincfg.MainNetParams, ErrNotP2SH},
+		{"testnet address on mainnet", "2NAUYAHhujozruyzpsFRP63mbrdaU5wnEpN", &chaincfg.MainNetParams, ErrNotP2SH},
+		{"mainnet address on litecoin", "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN", &chaincfg.LitecoinMainNetParams, ErrNotP2SH},
+		{"short payload", base58.Base58CheckEncode(0x05, make([]byte, 19)), &chaincfg.MainNetParams, ErrNotP2SH},
+		{"bad checksum", "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLM", &chaincfg.MainNetParams, base58.ErrChecksumMismatch},
+		{"invalid character", "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGL0", &chaincfg.MainNetParams, base58.ErrInvalidCharacter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeP2SH(tt.addr, tt.params); !errors.Is(err, tt.target) {
+				t.Errorf("DecodeP2SH() error = %v, expected %v", err, tt.target)
+			}
+			if MatchesScript(tt.addr, p2wpkhKeyOne, tt.params) {
+				t.Error("Expected MatchesScript to be false")
+			}
+		})
+	}
+	if _, err := DecodeP2SH("3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN", nil); err == nil {
+		t.Error("Expected an error without network params")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"math/big"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/bigpool"
 )
 
 var (
@@ -46,35 +47,119 @@ func ModN(x *big.Int) *big.Int {
 	return x
 }
 
+// Option configures where the arithmetic helpers allocate their results
+type Option func(*options)
+
+type options struct {
+	pool *bigpool.Pool
+}
+
+// WithPool takes results from pool instead of allocating them
+//
+// Callers doing bulk work must return each result with pool.Release once it is
+// no longer needed; results must not be used after release.
+//
+// Example:
+//
+//	pool := bigpool.New()
+//	for _, e := range challenges {
+//		s := arithmetic.MulModN(e, d, arithmetic.WithPool(pool))
+//		consume(s)
+//		pool.Release(s)
+//	}
+func WithPool(pool *bigpool.Pool) Option {
+	return func(o *options) {
+		o.pool = pool
+	}
+}
+
+// newInt returns a zero big.Int from the configured pool, or a fresh one
+func newInt(opts []Option) *big.Int {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.pool == nil {
+		return new(big.Int)
+	}
+	return o.pool.Get()
+}
+
 // AddModN adds two big integers modulo N
-func AddModN(a, b *big.Int) *big.Int {
-	out := new(big.Int).Add(a, b)
+func AddModN(a, b *big.Int, opts ...Option) *big.Int {
+	out := newInt(opts).Add(a, b)
 	return ModN(out)
 }
 
 // MulModN multiplies two big integers modulo N
-func MulModN(a, b *big.Int) *big.Int {
-	out := new(big.Int).Mul(a, b)
+func MulModN(a, b *big.Int, opts ...Option) *big.Int {
+	out := newInt(opts).Mul(a, b)
 	return ModN(out)
 }
 
 // NegModN negates a big integer modulo N
-func NegModN(a *big.Int) *big.Int {
-	out := new(big.Int).Sub(N, a)
+func NegModN(a *big.Int, opts ...Option) *big.Int {
+	out := newInt(opts).Sub(N, a)
 	return ModN(out)
 }
 
+// SubModN subtracts b from a modulo N
+//
+// Like ModN, the result is in [0, N) even when b > a.
+func SubModN(a, b *big.Int, opts ...Option) *big.Int {
+	out := newInt(opts).Sub(a, b)
+	return ModN(out)
+}
+
+// ExpModN raises a to the power e modulo N
+//
+// A negative e raises the inverse of a, as big.Int.Exp does; the result is
+// then nil if a ≡ 0 (mod N), which has no inverse.
+//
+// Example:
+//
+//	ExpModN(a, new(big.Int).Sub(N, big.NewInt(2))) // a⁻¹ by Fermat's little theorem
+func ExpModN(a, e *big.Int, opts ...Option) *big.Int {
+	base := ModN(new(big.Int).Set(a))
+	return newInt(opts).Exp(base, e, N)
+}
+
+// InvModN returns the multiplicative inverse of a modulo N
+func InvModN(a *big.Int, opts ...Option) (*big.Int, error) {
+	reduced := ModN(new(big.Int).Set(a))
+	if reduced.Sign() == 0 {
+		return nil, ErrNotInvertible
+	}
+	return newInt(opts).ModInverse(reduced, N), nil
+}
+
+// DivModN divides a by b modulo N, i.e. returns a·b⁻¹
+//
+// Example:
+//
+//	// Lagrange coefficient of party 1 among {1, 2}: 2 / (2 - 1)
+//	lambda, err := DivModN(big.NewInt(2), SubModN(big.NewInt(2), big.NewInt(1)))
+func DivModN(a, b *big.Int, opts ...Option) (*big.Int, error) {
+	inv, err := InvModN(b)
+	if err != nil {
+		return nil, err
+	}
+	return MulModN(a, inv, opts...), nil
+}
+
 // RandScalar generates a random scalar (private key) for the secp256k1 curve
 //
 // This function generates a cryptographically secure random number that is
 // suitable for use as a private key or nonce in cryptographic operations.
-func RandScalar() (*big.Int, error) {
+func RandScalar(opts ...Option) (*big.Int, error) {
+	k := newInt(opts)
 	for {
 		var buf [32]byte
 		if _, err := rand.Read(buf[:]); err != nil {
 			return nil, err
 		}
-		k := new(big.Int).SetBytes(buf[:])
+		k.SetBytes(buf[:])
+		ZeroBytes(buf[:])
 		k.Mod(k, N)
 		if k.Sign() != 0 {
 			return k, nil
@@ -1,8 +1,11 @@
 package arithmetic
 
 import (
+	"errors"
 	"math/big"
 	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/bigpool"
 )
 
 // TestToBytes32 tests the ToBytes32 function
@@ -241,6 +244,133 @@ func TestNegModN(t *testing.T) {
 	}
 }
 
+// TestSubModN tests the SubModN function
+func TestSubModN(t *testing.T) {
+	N := GetCurveOrder()
+
+	testCases := []struct {
+		name     string
+		a        *big.Int
+		b        *big.Int
+		expected *big.Int
+	}{
+		{
+			name:     "Simple subtraction",
+			a:        big.NewInt(10),
+			b:        big.NewInt(3),
+			expected: big.NewInt(7),
+		},
+		{
+			name:     "Negative difference wraps",
+			a:        big.NewInt(3),
+			b:        big.NewInt(10),
+			expected: new(big.Int).Sub(N, big.NewInt(7)),
+		},
+		{
+			name:     "Negative operand",
+			a:        big.NewInt(-5),
+			b:        big.NewInt(5),
+			expected: new(big.Int).Sub(N, big.NewInt(10)),
+		},
+		{
+			name:     "Equal values",
+			a:        new(big.Int).Set(N),
+			b:        big.NewInt(0),
+			expected: big.NewInt(0),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := SubModN(tc.a, tc.b)
+			if result.Cmp(tc.expected) != 0 {
+				t.Errorf("Expected %s, got %s", tc.expected.String(), result.String())
+			}
+		})
+	}
+}
+
+// TestExpModN tests the ExpModN function
+func TestExpModN(t *testing.T) {
+	N := GetCurveOrder()
+	nMinusTwo := new(big.Int).Sub(N, big.NewInt(2))
+
+	testCases := []struct {
+		name     string
+		a        *big.Int
+		e        *big.Int
+		expected *big.Int
+	}{
+		{"Small power", big.NewInt(3), big.NewInt(4), big.NewInt(81)},
+		{"Zero exponent", big.NewInt(12345), big.NewInt(0), big.NewInt(1)},
+		{"Negative base", big.NewInt(-2), big.NewInt(3), new(big.Int).Sub(N, big.NewInt(8))},
+		{"Fermat inverse", big.NewInt(2), nMinusTwo, new(big.Int).Rsh(new(big.Int).Add(N, big.NewInt(1)), 1)},
+		{"Negative exponent", big.NewInt(2), big.NewInt(-1), new(big.Int).Rsh(new(big.Int).Add(N, big.NewInt(1)), 1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := ExpModN(tc.a, tc.e)
+			if result.Cmp(tc.expected) != 0 {
+				t.Errorf("Expected %s, got %s", tc.expected.String(), result.String())
+			}
+		})
+	}
+
+	if result := ExpModN(big.NewInt(0), big.NewInt(-1)); result != nil {
+		t.Errorf("Expected nil for a negative power of 0, got %s", result.String())
+	}
+}
+
+// TestInvDivModN tests the InvModN and DivModN functions
+func TestInvDivModN(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		a, err := RandScalar()
+		if err != nil {
+			t.Fatalf("RandScalar failed: %v", err)
+		}
+		b, err := RandScalar()
+		if err != nil {
+			t.Fatalf("RandScalar failed: %v", err)
+		}
+
+		inv, err := InvModN(a)
+		if err != nil {
+			t.Fatalf("InvModN failed: %v", err)
+		}
+		if MulModN(a, inv).Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("Expected a * InvModN(a) = 1 for a = %s", a.String())
+		}
+
+		quotient, err := DivModN(a, b)
+		if err != nil {
+			t.Fatalf("DivModN failed: %v", err)
+		}
+		if MulModN(quotient, b).Cmp(a) != 0 {
+			t.Errorf("Expected DivModN(a, b) * b = a for a = %s, b = %s", a.String(), b.String())
+		}
+	}
+
+	// 0 and multiples of N have no inverse
+	for _, zero := range []*big.Int{big.NewInt(0), GetCurveOrder()} {
+		if _, err := InvModN(zero); !errors.Is(err, ErrNotInvertible) {
+			t.Errorf("Expected ErrNotInvertible from InvModN, got %v", err)
+		}
+		if _, err := DivModN(big.NewInt(1), zero); !errors.Is(err, ErrNotInvertible) {
+			t.Errorf("Expected ErrNotInvertible from DivModN, got %v", err)
+		}
+	}
+
+	// Subtraction and division undo addition and multiplication
+	a, b := big.NewInt(-7), big.NewInt(11)
+	if SubModN(AddModN(a, b), b).Cmp(ModN(new(big.Int).Set(a))) != 0 {
+		t.Error("Expected (a + b) - b = a")
+	}
+	if q, _ := DivModN(MulModN(a, b), b); q.Cmp(ModN(new(big.Int).Set(a))) != 0 {
+		t.Error("Expected (a * b) / b = a")
+	}
+}
+
 // TestRandScalar tests the RandScalar function
 func TestRandScalar(t *testing.T) {
 	N := GetCurveOrder()
@@ -371,3 +501,38 @@ func BenchmarkRandScalar(b *testing.B) {
 		RandScalar()
 	}
 }
+
+// TestWithPool tests that pooled results match allocated ones
+func TestWithPool(t *testing.T) {
+	pool := bigpool.New()
+	a := new(big.Int).Sub(N, big.NewInt(5))
+	b := big.NewInt(12345)
+
+	for i := 0; i < 3; i++ {
+		sum := AddModN(a, b, WithPool(pool))
+		if expected := AddModN(a, b); sum.Cmp(expected) != 0 {
+			t.Errorf("AddModN(WithPool) = %s, expected %s", sum, expected)
+		}
+		product := MulModN(a, b, WithPool(pool))
+		if expected := MulModN(a, b); product.Cmp(expected) != 0 {
+			t.Errorf("MulModN(WithPool) = %s, expected %s", product, expected)
+		}
+		neg := NegModN(b, WithPool(pool))
+		if expected := NegModN(b); neg.Cmp(expected) != 0 {
+			t.Errorf("NegModN(WithPool) = %s, expected %s", neg, expected)
+		}
+		k, err := RandScalar(WithPool(pool))
+		if err != nil {
+			t.Fatalf("RandScalar(WithPool) failed: %v", err)
+		}
+		if k.Sign() <= 0 || k.Cmp(N) >= 0 {
+			t.Errorf("RandScalar(WithPool) out of range: %s", k)
+		}
+		pool.Release(sum, product, neg, k)
+	}
+
+	// Inputs are never modified
+	if a.Cmp(new(big.Int).Sub(N, big.NewInt(5))) != 0 || b.Int64() != 12345 {
+		t.Error("Pooled operations modified their inputs")
+	}
+}
@@ -0,0 +1,92 @@
+package arithmetic
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Field arithmetic
+//
+// secp256k1 has two moduli that are easy to confuse because both are close
+// to 2²⁵⁶:
+//
+//	P = 2²⁵⁶ - 2³² - 977  the field prime: point coordinates x and y live mod P
+//	N = the group order   the number of points: scalars (keys, nonces, s) live mod N
+//
+// Adding two public keys is arithmetic mod P inside the point formulas;
+// adding two private keys is arithmetic mod N. Mixing them up gives results
+// that look fine and are wrong, so the field helpers get their own names.
+//
+// P ≡ 3 (mod 4), which makes square roots cheap: if a has one, it is
+// a^((P+1)/4). That is how a compressed key or an x-only key is
+// decompressed: y = √(x³ + 7).
+
+var (
+	// P is the prime of the secp256k1 base field
+	P = CURVE.P
+
+	// sqrtExp is (P + 1) / 4, the exponent of SqrtModP
+	sqrtExp = new(big.Int).Rsh(new(big.Int).Add(P, big.NewInt(1)), 2)
+)
+
+var (
+	// ErrNoSquareRoot is returned by SqrtModP for quadratic non-residues
+	ErrNoSquareRoot = errors.New("value has no square root mod p")
+	// ErrNotInvertible is returned by InvModP, InvModN and DivModN for values ≡ 0 modulo the prime
+	ErrNotInvertible = errors.New("value is not invertible")
+)
+
+// ModP reduces x modulo the field prime P in place
+//
+// Like ModN, the result is always in [0, P), even for negative x.
+func ModP(x *big.Int) *big.Int {
+	x.Mod(x, P)
+	if x.Sign() < 0 {
+		x.Add(x, P)
+	}
+	return x
+}
+
+// AddModP adds two big integers modulo P
+func AddModP(a, b *big.Int, opts ...Option) *big.Int {
+	out := newInt(opts).Add(a, b)
+	return ModP(out)
+}
+
+// MulModP multiplies two big integers modulo P
+func MulModP(a, b *big.Int, opts ...Option) *big.Int {
+	out := newInt(opts).Mul(a, b)
+	return ModP(out)
+}
+
+// SqrtModP returns a square root of a modulo P
+//
+// Every non-zero square has two roots, r and P - r; SqrtModP returns either,
+// and callers that need a particular one (even y for BIP340, the parity byte
+// of a compressed key) choose by checking r.Bit(0).
+//
+// Example:
+//
+//	ySquared := AddModP(MulModP(MulModP(x, x), x), big.NewInt(7))
+//	y, err := SqrtModP(ySquared) // err is ErrNoSquareRoot if x is not on the curve
+func SqrtModP(a *big.Int, opts ...Option) (*big.Int, error) {
+	// Step 1: Candidate root a^((P+1)/4)
+	reduced := ModP(new(big.Int).Set(a))
+	root := newInt(opts).Exp(reduced, sqrtExp, P)
+
+	// Step 2: It is a root only if a is a square
+	check := new(big.Int).Mul(root, root)
+	if ModP(check).Cmp(reduced) != 0 {
+		return nil, ErrNoSquareRoot
+	}
+	return root, nil
+}
+
+// InvModP returns the multiplicative inverse of a modulo P
+func InvModP(a *big.Int, opts ...Option) (*big.Int, error) {
+	reduced := ModP(new(big.Int).Set(a))
+	if reduced.Sign() == 0 {
+		return nil, ErrNotInvertible
+	}
+	return newInt(opts).ModInverse(reduced, P), nil
+}
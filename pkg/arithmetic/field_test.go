@@ -0,0 +1,123 @@
+package arithmetic
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/bigpool"
+)
+
+// TestModP tests reduction modulo P
+func TestModP(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    *big.Int
+		expected *big.Int
+	}{
+		{"Zero", big.NewInt(0), big.NewInt(0)},
+		{"Small", big.NewInt(42), big.NewInt(42)},
+		{"P", new(big.Int).Set(P), big.NewInt(0)},
+		{"P + 1", new(big.Int).Add(P, big.NewInt(1)), big.NewInt(1)},
+		{"-1", big.NewInt(-1), new(big.Int).Sub(P, big.NewInt(1))},
+		{"N is below P", new(big.Int).Set(N), N},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := ModP(tc.input)
+			if result.Cmp(tc.expected) != 0 {
+				t.Errorf("ModP() = %x, expected %x", result, tc.expected)
+			}
+		})
+	}
+}
+
+// TestAddMulModP tests addition and multiplication modulo P
+func TestAddMulModP(t *testing.T) {
+	pMinusOne := new(big.Int).Sub(P, big.NewInt(1))
+
+	if result := AddModP(pMinusOne, big.NewInt(2)); result.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("AddModP(P-1, 2) = %x, expected 1", result)
+	}
+	if result := MulModP(pMinusOne, pMinusOne); result.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("MulModP(-1, -1) = %x, expected 1", result)
+	}
+
+	// The same inputs give different results mod P and mod N
+	nMinusOne := new(big.Int).Sub(N, big.NewInt(1))
+	if AddModP(nMinusOne, big.NewInt(2)).Cmp(AddModN(nMinusOne, big.NewInt(2))) == 0 {
+		t.Error("Expected AddModP and AddModN to differ above N")
+	}
+}
+
+// TestSqrtModP tests square roots, including decompressing the generator
+func TestSqrtModP(t *testing.T) {
+	// y² = x³ + 7 for G
+	x := CURVE.Gx
+	ySquared := AddModP(MulModP(MulModP(x, x), x), big.NewInt(7))
+	y, err := SqrtModP(ySquared)
+	if err != nil {
+		t.Fatalf("SqrtModP failed: %v", err)
+	}
+	if y.Cmp(CURVE.Gy) != 0 && new(big.Int).Sub(P, y).Cmp(CURVE.Gy) != 0 {
+		t.Errorf("SqrtModP(Gx³ + 7) = %x, expected ±%x", y, CURVE.Gy)
+	}
+
+	// Small squares and non-squares (-1 is not a square since P ≡ 3 mod 4)
+	root, err := SqrtModP(big.NewInt(9))
+	if err != nil || MulModP(root, root).Cmp(big.NewInt(9)) != 0 {
+		t.Errorf("SqrtModP(9) = %v, %v, expected a root of 9", root, err)
+	}
+	if _, err := SqrtModP(big.NewInt(-1)); !errors.Is(err, ErrNoSquareRoot) {
+		t.Errorf("Expected ErrNoSquareRoot for -1, got %v", err)
+	}
+	if _, err := SqrtModP(big.NewInt(5)); !errors.Is(err, ErrNoSquareRoot) {
+		t.Errorf("Expected ErrNoSquareRoot for 5, got %v", err)
+	}
+	if root, err := SqrtModP(big.NewInt(0)); err != nil || root.Sign() != 0 {
+		t.Errorf("SqrtModP(0) = %v, %v, expected 0", root, err)
+	}
+}
+
+// TestInvModP tests modular inverses
+func TestInvModP(t *testing.T) {
+	for _, a := range []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(-3), CURVE.Gx, N} {
+		inv, err := InvModP(a)
+		if err != nil {
+			t.Fatalf("InvModP(%x) failed: %v", a, err)
+		}
+		if result := MulModP(a, inv); result.Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("a * InvModP(a) = %x for a = %x, expected 1", result, a)
+		}
+	}
+
+	for _, a := range []*big.Int{big.NewInt(0), P} {
+		if _, err := InvModP(a); !errors.Is(err, ErrNotInvertible) {
+			t.Errorf("Expected ErrNotInvertible for %x, got %v", a, err)
+		}
+	}
+}
+
+// TestFieldWithPool tests that the field helpers take results from a pool
+func TestFieldWithPool(t *testing.T) {
+	pool := bigpool.New()
+	a := big.NewInt(4)
+
+	sum := AddModP(a, a, WithPool(pool))
+	product := MulModP(a, a, WithPool(pool))
+	root, err := SqrtModP(a, WithPool(pool))
+	if err != nil {
+		t.Fatalf("SqrtModP failed: %v", err)
+	}
+	inv, err := InvModP(a, WithPool(pool))
+	if err != nil {
+		t.Fatalf("InvModP failed: %v", err)
+	}
+	if sum.Int64() != 8 || product.Int64() != 16 || MulModP(root, root).Int64() != 4 || MulModP(inv, a).Int64() != 1 {
+		t.Error("Expected pooled results to match unpooled ones")
+	}
+	for _, x := range []*big.Int{sum, product, root, inv} {
+		pool.Release(x)
+	}
+}
@@ -0,0 +1,179 @@
+package arithmetic
+
+import (
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// GLV endomorphism and wNAF
+//
+// secp256k1 has an efficiently computable endomorphism (Gallant, Lambert,
+// Vanstone): for a cube root of unity β mod P and the matching cube root of
+// unity λ mod N,
+//
+//	φ(x, y) = (β·x, y) = λ·(x, y)
+//
+// so multiplying by λ costs one field multiplication. Any scalar can be
+// split as k ≡ k₁ + k₂·λ (mod N) with |k₁|, |k₂| < 2¹²⁸ (SplitScalar), and
+// then k·P = k₁·P + k₂·φ(P): two 128-bit multiplications that share their
+// doublings, half as many as for one 256-bit scalar.
+//
+// The additions are cut down with the width-w non-adjacent form (WNAF):
+// signed odd digits in (-2^(w-1), 2^(w-1)), with at least w-1 zeros between
+// two non-zero digits, so only about one bit in w+1 costs an addition, and
+// negative digits are free because -P is (x, -y). ScalarMultGLV combines
+// both; multi-scalar code such as schnorr's batch verification uses the
+// split directly to halve the length of its scalars.
+
+var (
+	// Lambda is the cube root of unity mod N with λ·P = (β·x, y)
+	Lambda, _ = new(big.Int).SetString("5363ad4cc05c30e0a5261c028812645a122e22ea20816678df02967c1b23bd72", 16)
+	// Beta is the cube root of unity mod P matching Lambda
+	Beta, _ = new(big.Int).SetString("7ae96a2b657c07106e64479eac3434e99cf0497512f58995c1396c28719501ee", 16)
+
+	// The short lattice basis (a1, b1), (a2, b2) of {(x, y) : x + y·λ ≡ 0 mod N}
+	glvA1, _  = new(big.Int).SetString("3086d221a7d46bcde86c90e49284eb15", 16)
+	glvB1, _  = new(big.Int).SetString("-e4437ed6010e88286f547fa90abfe4c3", 16)
+	glvA2, _  = new(big.Int).SetString("114ca50f7a8e2f3f657c1108d9d44cfd8", 16)
+	glvB2     = glvA1
+	glvHalfN  = new(big.Int).Rsh(N, 1)
+	betaField = func() btcec.FieldVal {
+		var f btcec.FieldVal
+		f.SetByteSlice(Beta.Bytes())
+		return f
+	}()
+)
+
+// glvWindow is the wNAF width of ScalarMultGLV: 2^(w-2) = 8 precomputed odd multiples per point
+const glvWindow = 5
+
+// SplitScalar returns k₁, k₂ with k ≡ k₁ + k₂·λ (mod N) and |k₁|, |k₂| < 2¹²⁸
+//
+// k is reduced mod N first. The halves may be negative; callers multiply
+// by |kᵢ| and negate the point instead.
+//
+// Example:
+//
+//	k1, k2 := SplitScalar(k)
+//	// k·P == k1·P + k2·Endomorphism(P)
+func SplitScalar(k *big.Int) (*big.Int, *big.Int) {
+	// Step 1: (c1, c2) = round((b2·k, -b1·k) / N), the closest lattice point
+	k = ModN(new(big.Int).Set(k))
+	c1 := roundDiv(new(big.Int).Mul(glvB2, k))
+	c2 := roundDiv(new(big.Int).Mul(new(big.Int).Neg(glvB1), k))
+
+	// Step 2: (k1, k2) = (k, 0) - c1·(a1, b1) - c2·(a2, b2)
+	k1 := new(big.Int).Sub(k, new(big.Int).Mul(c1, glvA1))
+	k1.Sub(k1, new(big.Int).Mul(c2, glvA2))
+	k2 := new(big.Int).Neg(new(big.Int).Mul(c1, glvB1))
+	k2.Sub(k2, new(big.Int).Mul(c2, glvB2))
+	return k1, k2
+}
+
+// roundDiv returns x / N rounded to the nearest integer, for x ≥ 0
+func roundDiv(x *big.Int) *big.Int {
+	x.Add(x, glvHalfN)
+	return x.Div(x, N)
+}
+
+// Endomorphism returns φ(p) = (β·x, y), which equals λ·p
+func Endomorphism(p *Point) *Point {
+	r := &Point{p: p.p}
+	endomorphism(&r.p)
+	return r
+}
+
+// endomorphism replaces p by φ(p); it also works on Jacobian points, as x = X/Z²
+func endomorphism(p *btcec.JacobianPoint) {
+	p.X.Mul(&betaField).Normalize()
+}
+
+// WNAF returns the width-w non-adjacent form of k ≥ 0, least significant digit first
+//
+// Every digit is 0 or odd with |d| < 2^(w-1), and of any w consecutive
+// digits at most one is non-zero. k = Σ digits[i]·2^i.
+//
+// Example:
+//
+//	WNAF(big.NewInt(7), 3) // [-1, 0, 0, 1]: 7 = -1 + 8
+func WNAF(k *big.Int, w uint) []int8 {
+	// Step 1: Work on a copy; the digits consume it from the bottom
+	n := new(big.Int).Abs(k)
+	digits := make([]int8, 0, n.BitLen()+1)
+	window := int64(1) << w
+	mask := big.NewInt(window - 1)
+	digit := new(big.Int)
+
+	// Step 2: An odd n gives the signed residue mod 2^w, which clears the next w-1 bits
+	for n.Sign() > 0 {
+		var d int64
+		if n.Bit(0) == 1 {
+			d = digit.And(n, mask).Int64()
+			if d >= window/2 {
+				d -= window
+			}
+			n.Sub(n, big.NewInt(d))
+		}
+		digits = append(digits, int8(d))
+		n.Rsh(n, 1)
+	}
+	return digits
+}
+
+// ScalarMultGLV returns k·p like PointScalarMult, using the GLV split and wNAF
+//
+// Both halves run in one double-and-add loop (Shamir's trick) of about 129
+// doublings, adding a precomputed odd multiple of p or φ(p) at each
+// non-zero digit. Like the other point operations it is variable-time.
+//
+// Example:
+//
+//	q := ScalarMultGLV(p, k)
+//	// q.Equal(PointScalarMult(p, k)) == true
+func ScalarMultGLV(p *Point, k *big.Int) *Point {
+	// Step 1: Split the scalar
+	k1, k2 := SplitScalar(k)
+	if p.IsInfinity() || (k1.Sign() == 0 && k2.Sign() == 0) {
+		return &Point{}
+	}
+	naf1, naf2 := WNAF(k1, glvWindow), WNAF(k2, glvWindow)
+
+	// Step 2: Odd multiples 1·p, 3·p, ..., 15·p and their images under φ
+	var table, tablePhi [1 << (glvWindow - 2)]btcec.JacobianPoint
+	var twoP btcec.JacobianPoint
+	table[0] = p.p
+	btcec.DoubleNonConst(&table[0], &twoP)
+	for i := 1; i < len(table); i++ {
+		btcec.AddNonConst(&table[i-1], &twoP, &table[i])
+	}
+	for i := range table {
+		tablePhi[i] = table[i]
+		endomorphism(&tablePhi[i])
+	}
+
+	// Step 3: Double once per digit, add the table entry for each non-zero digit
+	var acc btcec.JacobianPoint
+	for i := max(len(naf1), len(naf2)) - 1; i >= 0; i-- {
+		btcec.DoubleNonConst(&acc, &acc)
+		if i < len(naf1) && naf1[i] != 0 {
+			addDigit(&acc, &table, naf1[i], k1.Sign() < 0)
+		}
+		if i < len(naf2) && naf2[i] != 0 {
+			addDigit(&acc, &tablePhi, naf2[i], k2.Sign() < 0)
+		}
+	}
+	return toAffine(&Point{p: acc})
+}
+
+// addDigit adds ±|d|·P from a table of odd multiples, negated if d and neg disagree
+func addDigit(acc *btcec.JacobianPoint, table *[1 << (glvWindow - 2)]btcec.JacobianPoint, d int8, neg bool) {
+	if d < 0 {
+		d, neg = -d, !neg
+	}
+	term := table[d/2]
+	if neg {
+		term.Y.Normalize().Negate(1).Normalize()
+	}
+	btcec.AddNonConst(acc, &term, acc)
+}
@@ -0,0 +1,162 @@
+package arithmetic
+
+import (
+	"math/big"
+	"testing"
+)
+
+// scalarMultDoubleAdd is textbook left-to-right double-and-add, the reference for the benchmarks
+func scalarMultDoubleAdd(p *Point, k *big.Int) *Point {
+	k = ModN(new(big.Int).Set(k))
+	acc := &Point{}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		acc = PointAdd(acc, acc)
+		if k.Bit(i) == 1 {
+			acc = PointAdd(acc, p)
+		}
+	}
+	return acc
+}
+
+// TestEndomorphismConstants tests that β and λ are cube roots of unity and φ(P) = λ·P
+func TestEndomorphismConstants(t *testing.T) {
+	one := big.NewInt(1)
+	if new(big.Int).Exp(Lambda, big.NewInt(3), N).Cmp(one) != 0 || Lambda.Cmp(one) == 0 {
+		t.Error("Expected λ to be a non-trivial cube root of unity mod N")
+	}
+	if new(big.Int).Exp(Beta, big.NewInt(3), P).Cmp(one) != 0 || Beta.Cmp(one) == 0 {
+		t.Error("Expected β to be a non-trivial cube root of unity mod P")
+	}
+
+	g := Generator()
+	if !Endomorphism(g).Equal(PointScalarMult(g, Lambda)) {
+		t.Error("Expected φ(G) = λ·G")
+	}
+	if !Endomorphism(&Point{}).IsInfinity() {
+		t.Error("Expected φ(infinity) = infinity")
+	}
+}
+
+// TestSplitScalar tests k ≡ k1 + k2·λ with 128-bit halves
+func TestSplitScalar(t *testing.T) {
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		new(big.Int).Sub(N, big.NewInt(1)),
+		new(big.Int).Set(Lambda),
+		new(big.Int).Set(glvHalfN),
+		big.NewInt(-5),
+	}
+	for i := 0; i < 100; i++ {
+		k, err := RandScalar()
+		if err != nil {
+			t.Fatalf("RandScalar failed: %v", err)
+		}
+		scalars = append(scalars, k)
+	}
+
+	for _, k := range scalars {
+		k1, k2 := SplitScalar(k)
+		recombined := AddModN(k1, MulModN(k2, Lambda))
+		if recombined.Cmp(ModN(new(big.Int).Set(k))) != 0 {
+			t.Errorf("k1 + k2·λ = %x, expected %x", recombined, k)
+		}
+		if k1.BitLen() > 128 || k2.BitLen() > 128 {
+			t.Errorf("SplitScalar(%x) = (%x, %x), expected halves of at most 128 bits", k, k1, k2)
+		}
+	}
+}
+
+// TestWNAF tests the digit constraints and that the digits sum back to k
+func TestWNAF(t *testing.T) {
+	if got := WNAF(big.NewInt(7), 3); len(got) != 4 || got[0] != -1 || got[1] != 0 || got[2] != 0 || got[3] != 1 {
+		t.Errorf("WNAF(7, 3) = %v, expected [-1 0 0 1]", got)
+	}
+	if got := WNAF(big.NewInt(0), 4); len(got) != 0 {
+		t.Errorf("WNAF(0, 4) = %v, expected no digits", got)
+	}
+
+	for _, w := range []uint{2, 4, 5, 7} {
+		for i := 0; i < 20; i++ {
+			k, err := RandScalar()
+			if err != nil {
+				t.Fatalf("RandScalar failed: %v", err)
+			}
+			digits := WNAF(k, w)
+			sum := new(big.Int)
+			lastNonZero := -int(w)
+			for j := len(digits) - 1; j >= 0; j-- {
+				sum.Lsh(sum, 1).Add(sum, big.NewInt(int64(digits[j])))
+			}
+			for j, d := range digits {
+				if d == 0 {
+					continue
+				}
+				if d%2 == 0 || d >= 1<<(w-1) || d <= -(1<<(w-1)) {
+					t.Errorf("WNAF(k, %d) digit %d = %d, expected odd with |d| < %d", w, j, d, 1<<(w-1))
+				}
+				if j-lastNonZero < int(w) {
+					t.Errorf("WNAF(k, %d) has non-zero digits %d apart, expected at least %d", w, j-lastNonZero, w)
+				}
+				lastNonZero = j
+			}
+			if sum.Cmp(k) != 0 {
+				t.Errorf("WNAF(k, %d) sums to %x, expected %x", w, sum, k)
+			}
+		}
+	}
+}
+
+// TestScalarMultGLV tests ScalarMultGLV against btcec and the textbook method
+func TestScalarMultGLV(t *testing.T) {
+	g := Generator()
+	scalars := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2), big.NewInt(-1), new(big.Int).Set(N), new(big.Int).Sub(N, big.NewInt(1))}
+	for i := 0; i < 20; i++ {
+		k, err := RandScalar()
+		if err != nil {
+			t.Fatalf("RandScalar failed: %v", err)
+		}
+		scalars = append(scalars, k)
+	}
+
+	base := ScalarBaseMult(big.NewInt(123456789))
+	for _, p := range []*Point{g, base} {
+		for _, k := range scalars {
+			got := ScalarMultGLV(p, k)
+			if want := PointScalarMult(p, k); !got.Equal(want) {
+				t.Errorf("ScalarMultGLV(%s, %x) = %s, expected %s", p, k, got, want)
+			}
+		}
+	}
+	if !ScalarMultGLV(&Point{}, big.NewInt(7)).IsInfinity() {
+		t.Error("Expected 7·infinity to be the point at infinity")
+	}
+
+	k, _ := RandScalar()
+	if !scalarMultDoubleAdd(base, k).Equal(ScalarMultGLV(base, k)) {
+		t.Error("Expected the textbook method and ScalarMultGLV to agree")
+	}
+}
+
+func BenchmarkScalarMult(b *testing.B) {
+	p := ScalarBaseMult(big.NewInt(123456789))
+	k, err := RandScalar()
+	if err != nil {
+		b.Fatalf("RandScalar failed: %v", err)
+	}
+	b.Run("double-and-add", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			scalarMultDoubleAdd(p, k)
+		}
+	})
+	b.Run("glv-wnaf", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ScalarMultGLV(p, k)
+		}
+	})
+	b.Run("btcec", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			PointScalarMult(p, k)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package arithmetic
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// Jacobian coordinates
+//
+// An affine point (x, y) is stored in Jacobian coordinates as (X, Y, Z) with
+// x = X/Z² and y = Y/Z³. Adding and doubling in this form needs only field
+// multiplications, while getting back to affine form needs a field
+// inversion, which costs about as much as a hundred multiplications. Point
+// normalizes after every operation so that its coordinates are always
+// readable; code that chains many operations (sums of nonces, linear
+// combinations, batch checks) should stay in JacobianPoint and convert once
+// at the end, or convert many results together with ToAffineBatch.
+//
+// The methods follow math/big: the receiver is set to the result and
+// returned, so expressions chain and temporaries can be reused:
+//
+//	var acc JacobianPoint
+//	for _, r := range nonces {
+//		acc.Add(&acc, r.Jacobian())
+//	}
+//	R := acc.ToAffine()
+
+// ErrLengthMismatch is returned by MultiScalarMult when points and scalars differ in length
+var ErrLengthMismatch = errors.New("points and scalars must have the same length")
+
+// JacobianPoint is a secp256k1 point in Jacobian coordinates; the zero value is the point at infinity
+type JacobianPoint struct {
+	p btcec.JacobianPoint
+}
+
+// Jacobian returns p in Jacobian coordinates (Z = 1)
+func (p *Point) Jacobian() *JacobianPoint {
+	return &JacobianPoint{p: p.p}
+}
+
+// Set sets j to a and returns j
+func (j *JacobianPoint) Set(a *JacobianPoint) *JacobianPoint {
+	j.p = a.p
+	return j
+}
+
+// Add sets j to a + b and returns j
+func (j *JacobianPoint) Add(a, b *JacobianPoint) *JacobianPoint {
+	btcec.AddNonConst(&a.p, &b.p, &j.p)
+	return j
+}
+
+// Double sets j to 2·a and returns j
+func (j *JacobianPoint) Double(a *JacobianPoint) *JacobianPoint {
+	btcec.DoubleNonConst(&a.p, &j.p)
+	return j
+}
+
+// Neg sets j to -a and returns j
+func (j *JacobianPoint) Neg(a *JacobianPoint) *JacobianPoint {
+	j.p = a.p
+	j.p.Y.Normalize().Negate(1).Normalize()
+	return j
+}
+
+// ScalarMult sets j to k·a and returns j; k is reduced mod N
+func (j *JacobianPoint) ScalarMult(a *JacobianPoint, k *big.Int) *JacobianPoint {
+	s := toScalar(k)
+	btcec.ScalarMultNonConst(&s, &a.p, &j.p)
+	return j
+}
+
+// ScalarBaseMult sets j to k·G and returns j; k is reduced mod N
+func (j *JacobianPoint) ScalarBaseMult(k *big.Int) *JacobianPoint {
+	s := toScalar(k)
+	btcec.ScalarBaseMultNonConst(&s, &j.p)
+	return j
+}
+
+// IsInfinity reports whether j is the point at infinity
+func (j *JacobianPoint) IsInfinity() bool {
+	q := j.p
+	q.X.Normalize()
+	q.Y.Normalize()
+	return q.Z.Normalize().IsZero() || (q.X.IsZero() && q.Y.IsZero())
+}
+
+// ToAffine returns j as a Point, leaving j unchanged
+func (j *JacobianPoint) ToAffine() *Point {
+	return toAffine(&Point{p: j.p})
+}
+
+// ToAffineBatch converts many points with a single field inversion
+//
+// Montgomery's trick: invert the product of all Z, then peel off each 1/Zᵢ
+// with two multiplications by the running prefix products. Points at
+// infinity become the zero Point.
+//
+// Example:
+//
+//	affine := ToAffineBatch(partialNonces) // one inversion instead of len(partialNonces)
+func ToAffineBatch(points []*JacobianPoint) []*Point {
+	// Step 1: Infinity has no inverse; only finite points take part
+	out := make([]*Point, len(points))
+	finite := make([]int, 0, len(points))
+	for i, j := range points {
+		out[i] = &Point{}
+		if !j.IsInfinity() {
+			finite = append(finite, i)
+		}
+	}
+	if len(finite) == 0 {
+		return out
+	}
+
+	// Step 2: prefix[i] = Z_0 · ... · Z_i over the finite points
+	prefix := make([]btcec.FieldVal, len(finite))
+	prefix[0].Set(&points[finite[0]].p.Z)
+	for i := 1; i < len(finite); i++ {
+		prefix[i].Mul2(&prefix[i-1], &points[finite[i]].p.Z)
+	}
+
+	// Step 3: Walk back from 1/(Z_0 · ... · Z_n)
+	var inv btcec.FieldVal
+	inv.Set(&prefix[len(finite)-1]).Inverse()
+	for i := len(finite) - 1; i >= 0; i-- {
+		src := &points[finite[i]].p
+		var zInv, zInv2, zInv3 btcec.FieldVal
+		if i > 0 {
+			zInv.Mul2(&inv, &prefix[i-1])
+			inv.Mul(&src.Z)
+		} else {
+			zInv.Set(&inv)
+		}
+		zInv2.SquareVal(&zInv)
+		zInv3.Mul2(&zInv2, &zInv)
+
+		dst := &out[finite[i]].p
+		dst.X.Mul2(&src.X, &zInv2).Normalize()
+		dst.Y.Mul2(&src.Y, &zInv3).Normalize()
+		dst.Z.SetInt(1)
+	}
+	return out
+}
+
+// SumPoints returns the sum of points with a single conversion to affine form
+//
+// Example:
+//
+//	R := SumPoints(R1, R2, R3) // aggregated nonce
+func SumPoints(points ...*Point) *Point {
+	var acc JacobianPoint
+	for _, p := range points {
+		btcec.AddNonConst(&acc.p, &p.p, &acc.p)
+	}
+	return acc.ToAffine()
+}
+
+// MultiScalarMult returns Σ scalars[i]·points[i], accumulated in Jacobian coordinates
+//
+// Example:
+//
+//	// BIP340 check for one signature: s·G - e·P == R
+//	check, err := MultiScalarMult([]*Point{Generator(), P}, []*big.Int{s, NegModN(e)})
+func MultiScalarMult(points []*Point, scalars []*big.Int) (*Point, error) {
+	if len(points) != len(scalars) {
+		return nil, ErrLengthMismatch
+	}
+	var acc, term JacobianPoint
+	for i, p := range points {
+		term.ScalarMult(p.Jacobian(), scalars[i])
+		acc.Add(&acc, &term)
+	}
+	return acc.ToAffine(), nil
+}
@@ -0,0 +1,158 @@
+package arithmetic
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// randomPoints returns n points k·G for random k
+func randomPoints(tb testing.TB, n int) []*Point {
+	points := make([]*Point, n)
+	for i := range points {
+		k, err := RandScalar()
+		if err != nil {
+			tb.Fatalf("RandScalar failed: %v", err)
+		}
+		points[i] = ScalarBaseMult(k)
+	}
+	return points
+}
+
+// TestJacobianPoint tests the Jacobian operations against the affine ones
+func TestJacobianPoint(t *testing.T) {
+	g := Generator()
+	k := big.NewInt(77)
+
+	testCases := []struct {
+		name     string
+		result   *JacobianPoint
+		expected *Point
+	}{
+		{"Add", new(JacobianPoint).Add(g.Jacobian(), ScalarBaseMult(big.NewInt(2)).Jacobian()), ScalarBaseMult(big.NewInt(3))},
+		{"Double", new(JacobianPoint).Double(g.Jacobian()), ScalarBaseMult(big.NewInt(2))},
+		{"Neg", new(JacobianPoint).Neg(g.Jacobian()), Negate(g)},
+		{"ScalarMult", new(JacobianPoint).ScalarMult(new(JacobianPoint).Double(g.Jacobian()), k), ScalarBaseMult(big.NewInt(154))},
+		{"ScalarBaseMult", new(JacobianPoint).ScalarBaseMult(k), ScalarBaseMult(k)},
+		{"Set", new(JacobianPoint).Set(g.Jacobian()), g},
+		{"Add infinity", new(JacobianPoint).Add(g.Jacobian(), &JacobianPoint{}), g},
+		{"Add negation", new(JacobianPoint).Add(g.Jacobian(), Negate(g).Jacobian()), &Point{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.result.ToAffine(); !got.Equal(tc.expected) {
+				t.Errorf("%s = %s, expected %s", tc.name, got, tc.expected)
+			}
+		})
+	}
+}
+
+// TestJacobianChain tests a long chain of additions with a single conversion at the end
+func TestJacobianChain(t *testing.T) {
+	g := Generator()
+	var acc JacobianPoint
+	for i := 0; i < 100; i++ {
+		acc.Add(&acc, g.Jacobian())
+	}
+	if !acc.ToAffine().Equal(ScalarBaseMult(big.NewInt(100))) {
+		t.Error("Expected 100 additions of G to equal 100·G")
+	}
+	if acc.IsInfinity() {
+		t.Error("Expected 100·G not to be the point at infinity")
+	}
+	if !new(JacobianPoint).ScalarBaseMult(N).IsInfinity() {
+		t.Error("Expected N·G to be the point at infinity")
+	}
+}
+
+// TestToAffineBatch tests batch conversion, including points at infinity
+func TestToAffineBatch(t *testing.T) {
+	points := randomPoints(t, 10)
+	jacobian := make([]*JacobianPoint, 0, len(points)+2)
+	expected := make([]*Point, 0, len(points)+2)
+	for i, p := range points {
+		// Double to get Z != 1
+		jacobian = append(jacobian, new(JacobianPoint).Double(p.Jacobian()))
+		expected = append(expected, PointAdd(p, p))
+		if i == 3 || i == 7 {
+			jacobian = append(jacobian, &JacobianPoint{})
+			expected = append(expected, &Point{})
+		}
+	}
+
+	got := ToAffineBatch(jacobian)
+	for i := range got {
+		if !got[i].Equal(expected[i]) {
+			t.Errorf("ToAffineBatch()[%d] = %s, expected %s", i, got[i], expected[i])
+		}
+	}
+	if len(ToAffineBatch(nil)) != 0 || !ToAffineBatch([]*JacobianPoint{{}})[0].IsInfinity() {
+		t.Error("Expected empty and all-infinity batches to work")
+	}
+}
+
+// TestSumPoints tests SumPoints and MultiScalarMult against PointAdd
+func TestSumPoints(t *testing.T) {
+	points := randomPoints(t, 8)
+	expected := &Point{}
+	for _, p := range points {
+		expected = PointAdd(expected, p)
+	}
+	if got := SumPoints(points...); !got.Equal(expected) {
+		t.Errorf("SumPoints() = %s, expected %s", got, expected)
+	}
+	if !SumPoints().IsInfinity() {
+		t.Error("Expected the empty sum to be the point at infinity")
+	}
+
+	scalars := []*big.Int{big.NewInt(3), big.NewInt(-2)}
+	got, err := MultiScalarMult([]*Point{Generator(), points[0]}, scalars)
+	if err != nil {
+		t.Fatalf("MultiScalarMult failed: %v", err)
+	}
+	want := PointAdd(ScalarBaseMult(big.NewInt(3)), PointScalarMult(points[0], big.NewInt(-2)))
+	if !got.Equal(want) {
+		t.Errorf("MultiScalarMult() = %s, expected %s", got, want)
+	}
+	if _, err := MultiScalarMult(points, scalars); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("Expected ErrLengthMismatch, got %v", err)
+	}
+}
+
+func BenchmarkSumPoints(b *testing.B) {
+	points := randomPoints(b, 64)
+	b.Run("affine", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			acc := &Point{}
+			for _, p := range points {
+				acc = PointAdd(acc, p)
+			}
+		}
+	})
+	b.Run("jacobian", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			SumPoints(points...)
+		}
+	})
+}
+
+func BenchmarkToAffine(b *testing.B) {
+	points := randomPoints(b, 64)
+	jacobian := make([]*JacobianPoint, len(points))
+	for i, p := range points {
+		jacobian[i] = new(JacobianPoint).Double(p.Jacobian())
+	}
+	b.Run("one-by-one", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, j := range jacobian {
+				j.ToAffine()
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ToAffineBatch(jacobian)
+		}
+	})
+}
@@ -0,0 +1,235 @@
+package arithmetic
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// Points
+//
+// Scalars are integers mod N; public keys, nonces and commitments are points
+// on the curve y² = x³ + 7 over the field of integers mod P. The two worlds
+// meet in scalar multiplication: d·G turns a private key into a public key,
+// and the homomorphism (a + b)·G = a·G + b·G is what makes key and nonce
+// aggregation work. Point wraps btcec's implementation so that protocols can
+// be written with the same operations as their papers:
+//
+//	R := PointAdd(ScalarBaseMult(k1), ScalarBaseMult(k2)) // R = k₁·G + k₂·G
+//
+// BIP340 x-only keys are handled directly on points: LiftX turns an x
+// coordinate into the even-Y point, HasEvenY and Negate pick the member of a
+// ±P pair, with no detour through 0x02/0x03 prefixed encodings.
+//
+// Scalars are reduced mod N first, so negative and oversized values behave
+// as their residues. The operations are variable-time, like the btcec
+// functions they use; fine for public values and for this playground.
+
+// ErrInvalidPoint is returned for coordinates or encodings that are not on the curve
+var ErrInvalidPoint = errors.New("invalid secp256k1 point")
+
+// Point is an affine secp256k1 point; the zero value is the point at infinity
+type Point struct {
+	p btcec.JacobianPoint // Z = 1, or Z = 0 for infinity
+}
+
+// Generator returns the base point G
+func Generator() *Point {
+	return ScalarBaseMult(big.NewInt(1))
+}
+
+// NewPoint returns the point (x, y), which must be on the curve
+func NewPoint(x, y *big.Int) (*Point, error) {
+	if x.Sign() < 0 || y.Sign() < 0 || !CURVE.IsOnCurve(x, y) {
+		return nil, ErrInvalidPoint
+	}
+	return pointFromCoords(x, y), nil
+}
+
+// LiftX returns the point with x coordinate x and an even y, per BIP340's lift_x
+//
+// This is how an x-only key becomes a point: y is the square root of x³ + 7,
+// and of the two roots y and P - y the even one is taken.
+//
+// Example:
+//
+//	p, err := LiftX(new(big.Int).SetBytes(xOnly[:]))
+//	// err is ErrInvalidPoint if x ≥ P or x³ + 7 is not a square
+func LiftX(x *big.Int) (*Point, error) {
+	// Step 1: x must be a field element
+	if x.Sign() < 0 || x.Cmp(P) >= 0 {
+		return nil, ErrInvalidPoint
+	}
+
+	// Step 2: y² = x³ + 7 must have a root
+	ySquared := AddModP(MulModP(MulModP(x, x), x), big.NewInt(7))
+	y, err := SqrtModP(ySquared)
+	if err != nil {
+		return nil, ErrInvalidPoint
+	}
+
+	// Step 3: Take the even root
+	if y.Bit(0) == 1 {
+		y.Sub(P, y)
+	}
+	return pointFromCoords(x, y), nil
+}
+
+// HasEvenY reports whether p has an even y coordinate; false for infinity
+//
+// BIP340 keys and nonces are the even-Y member of each ±P pair, so signers
+// negate their secret whenever the full point fails this check.
+func HasEvenY(p *Point) bool {
+	return !p.IsInfinity() && !p.p.Y.IsOdd()
+}
+
+// Negate returns -p, the point with the same x and y replaced by P - y
+func Negate(p *Point) *Point {
+	if p.IsInfinity() {
+		return &Point{}
+	}
+	r := &Point{p: p.p}
+	r.p.Y.Negate(1).Normalize()
+	return r
+}
+
+// ParsePoint parses a 33-byte compressed or 65-byte uncompressed encoding
+func ParsePoint(b []byte) (*Point, error) {
+	pub, err := btcec.ParsePubKey(b)
+	if err != nil {
+		return nil, ErrInvalidPoint
+	}
+	return PointFromPubKey(pub), nil
+}
+
+// PointFromPubKey converts a btcec public key
+func PointFromPubKey(pub *btcec.PublicKey) *Point {
+	r := &Point{}
+	pub.AsJacobian(&r.p)
+	return toAffine(r)
+}
+
+// PubKey converts the point to a btcec public key; infinity has no public key
+func (p *Point) PubKey() (*btcec.PublicKey, error) {
+	if p.IsInfinity() {
+		return nil, ErrInvalidPoint
+	}
+	return btcec.NewPublicKey(&p.p.X, &p.p.Y), nil
+}
+
+// X returns the x coordinate, or nil for the point at infinity
+func (p *Point) X() *big.Int {
+	if p.IsInfinity() {
+		return nil
+	}
+	return fieldToInt(&p.p.X)
+}
+
+// Y returns the y coordinate, or nil for the point at infinity
+func (p *Point) Y() *big.Int {
+	if p.IsInfinity() {
+		return nil
+	}
+	return fieldToInt(&p.p.Y)
+}
+
+// IsInfinity reports whether p is the identity element
+func (p *Point) IsInfinity() bool {
+	return p.p.Z.IsZero()
+}
+
+// Equal reports whether p and q are the same point
+func (p *Point) Equal(q *Point) bool {
+	if p.IsInfinity() || q.IsInfinity() {
+		return p.IsInfinity() == q.IsInfinity()
+	}
+	return p.p.X.Equals(&q.p.X) && p.p.Y.Equals(&q.p.Y)
+}
+
+// Bytes returns the 33-byte compressed encoding, or 33 zero bytes for infinity
+func (p *Point) Bytes() []byte {
+	if p.IsInfinity() {
+		return make([]byte, 33)
+	}
+	return btcec.NewPublicKey(&p.p.X, &p.p.Y).SerializeCompressed()
+}
+
+// String returns the compressed encoding in hex
+func (p *Point) String() string {
+	return hex.EncodeToString(p.Bytes())
+}
+
+// PointAdd returns a + b
+//
+// Example:
+//
+//	two := PointAdd(Generator(), Generator())
+//	// two.Equal(ScalarBaseMult(big.NewInt(2))) == true
+func PointAdd(a, b *Point) *Point {
+	r := &Point{}
+	btcec.AddNonConst(&a.p, &b.p, &r.p)
+	return toAffine(r)
+}
+
+// PointScalarMult returns k·p
+func PointScalarMult(p *Point, k *big.Int) *Point {
+	s := toScalar(k)
+	r := &Point{}
+	btcec.ScalarMultNonConst(&s, &p.p, &r.p)
+	return toAffine(r)
+}
+
+// ScalarBaseMult returns k·G
+//
+// Example:
+//
+//	pub := ScalarBaseMult(d) // the public key of the private key d
+func ScalarBaseMult(k *big.Int) *Point {
+	s := toScalar(k)
+	r := &Point{}
+	btcec.ScalarBaseMultNonConst(&s, &r.p)
+	return toAffine(r)
+}
+
+// pointFromCoords converts coordinates already known to be on the curve
+func pointFromCoords(x, y *big.Int) *Point {
+	r := &Point{}
+	r.p.X.SetByteSlice(x.Bytes())
+	r.p.Y.SetByteSlice(y.Bytes())
+	r.p.Z.SetInt(1)
+	return toAffine(r)
+}
+
+// toAffine normalizes r to Z = 1, or to the zero value for infinity
+//
+// btcec marks infinity with Z = 0 or with X = Y = 0, and its ToAffine turns
+// the former into (0, 0, 1), so both are mapped to Z = 0 here.
+func toAffine(r *Point) *Point {
+	r.p.X.Normalize()
+	r.p.Y.Normalize()
+	if r.p.Z.Normalize().IsZero() || (r.p.X.IsZero() && r.p.Y.IsZero()) {
+		r.p = btcec.JacobianPoint{}
+		return r
+	}
+	r.p.ToAffine()
+	return r
+}
+
+// toScalar reduces k mod N into a btcec scalar
+func toScalar(k *big.Int) btcec.ModNScalar {
+	var buf [32]byte
+	ModN(new(big.Int).Set(k)).FillBytes(buf[:])
+	var s btcec.ModNScalar
+	s.SetBytes(&buf)
+	ZeroBytes(buf[:])
+	return s
+}
+
+// fieldToInt converts a normalized field element
+func fieldToInt(f *btcec.FieldVal) *big.Int {
+	var buf [32]byte
+	f.PutBytes(&buf)
+	return new(big.Int).SetBytes(buf[:])
+}
@@ -0,0 +1,241 @@
+package arithmetic
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// generatorHex is the compressed encoding of G
+const generatorHex = "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+
+// TestGenerator tests that Generator matches the curve parameters
+func TestGenerator(t *testing.T) {
+	g := Generator()
+	if g.X().Cmp(CURVE.Gx) != 0 || g.Y().Cmp(CURVE.Gy) != 0 {
+		t.Errorf("Generator() = (%x, %x), expected (%x, %x)", g.X(), g.Y(), CURVE.Gx, CURVE.Gy)
+	}
+	if g.String() != generatorHex {
+		t.Errorf("Generator().String() = %s, expected %s", g.String(), generatorHex)
+	}
+}
+
+// TestPointAdd tests point addition against scalar multiplication
+func TestPointAdd(t *testing.T) {
+	g := Generator()
+	two := ScalarBaseMult(big.NewInt(2))
+	three := ScalarBaseMult(big.NewInt(3))
+
+	testCases := []struct {
+		name     string
+		a, b     *Point
+		expected *Point
+	}{
+		{"G + G (doubling)", g, g, two},
+		{"G + 2G", g, two, three},
+		{"2G + G (commutative)", two, g, three},
+		{"G + infinity", g, &Point{}, g},
+		{"infinity + G", &Point{}, g, g},
+		{"G + (-G)", g, ScalarBaseMult(big.NewInt(-1)), &Point{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := PointAdd(tc.a, tc.b)
+			if !result.Equal(tc.expected) {
+				t.Errorf("PointAdd() = %s, expected %s", result, tc.expected)
+			}
+		})
+	}
+}
+
+// TestScalarMult tests the homomorphism (a + b)·G = a·G + b·G and k·(m·G) = (k·m)·G
+func TestScalarMult(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		a, err := RandScalar()
+		if err != nil {
+			t.Fatalf("RandScalar failed: %v", err)
+		}
+		b, err := RandScalar()
+		if err != nil {
+			t.Fatalf("RandScalar failed: %v", err)
+		}
+
+		sum := ScalarBaseMult(AddModN(a, b))
+		if !sum.Equal(PointAdd(ScalarBaseMult(a), ScalarBaseMult(b))) {
+			t.Errorf("(a + b)·G != a·G + b·G for a=%x b=%x", a, b)
+		}
+
+		product := ScalarBaseMult(MulModN(a, b))
+		if !product.Equal(PointScalarMult(ScalarBaseMult(b), a)) {
+			t.Errorf("(a·b)·G != a·(b·G) for a=%x b=%x", a, b)
+		}
+	}
+}
+
+// TestScalarReduction tests that scalars are reduced mod N
+func TestScalarReduction(t *testing.T) {
+	if !ScalarBaseMult(N).IsInfinity() {
+		t.Error("Expected N·G to be the point at infinity")
+	}
+	if !ScalarBaseMult(big.NewInt(0)).IsInfinity() {
+		t.Error("Expected 0·G to be the point at infinity")
+	}
+	nPlusOne := new(big.Int).Add(N, big.NewInt(1))
+	if !ScalarBaseMult(nPlusOne).Equal(Generator()) {
+		t.Error("Expected (N+1)·G to equal G")
+	}
+	minusOne := ScalarBaseMult(big.NewInt(-1))
+	if minusOne.X().Cmp(CURVE.Gx) != 0 || minusOne.Y().Cmp(new(big.Int).Sub(CURVE.P, CURVE.Gy)) != 0 {
+		t.Error("Expected -1·G to be (Gx, P - Gy)")
+	}
+	if !PointScalarMult(&Point{}, big.NewInt(5)).IsInfinity() {
+		t.Error("Expected 5·infinity to be the point at infinity")
+	}
+}
+
+// TestNewPoint tests constructing points from coordinates
+func TestNewPoint(t *testing.T) {
+	p, err := NewPoint(CURVE.Gx, CURVE.Gy)
+	if err != nil {
+		t.Fatalf("NewPoint(G) failed: %v", err)
+	}
+	if !p.Equal(Generator()) {
+		t.Errorf("NewPoint(G) = %s, expected %s", p, Generator())
+	}
+
+	offCurve := new(big.Int).Add(CURVE.Gy, big.NewInt(1))
+	if _, err := NewPoint(CURVE.Gx, offCurve); !errors.Is(err, ErrInvalidPoint) {
+		t.Errorf("Expected ErrInvalidPoint for a point off the curve, got %v", err)
+	}
+	if _, err := NewPoint(big.NewInt(0), big.NewInt(0)); !errors.Is(err, ErrInvalidPoint) {
+		t.Errorf("Expected ErrInvalidPoint for (0, 0), got %v", err)
+	}
+}
+
+// TestParsePoint tests round trips through the compressed and uncompressed encodings
+func TestParsePoint(t *testing.T) {
+	k, err := RandScalar()
+	if err != nil {
+		t.Fatalf("RandScalar failed: %v", err)
+	}
+	p := ScalarBaseMult(k)
+
+	parsed, err := ParsePoint(p.Bytes())
+	if err != nil {
+		t.Fatalf("ParsePoint(compressed) failed: %v", err)
+	}
+	if !parsed.Equal(p) {
+		t.Errorf("ParsePoint(compressed) = %s, expected %s", parsed, p)
+	}
+
+	pub, err := p.PubKey()
+	if err != nil {
+		t.Fatalf("PubKey failed: %v", err)
+	}
+	parsed, err = ParsePoint(pub.SerializeUncompressed())
+	if err != nil {
+		t.Fatalf("ParsePoint(uncompressed) failed: %v", err)
+	}
+	if !parsed.Equal(p) {
+		t.Errorf("ParsePoint(uncompressed) = %s, expected %s", parsed, p)
+	}
+
+	invalid, _ := hex.DecodeString("02" + "0000000000000000000000000000000000000000000000000000000000000005")
+	if _, err := ParsePoint(invalid); !errors.Is(err, ErrInvalidPoint) {
+		t.Errorf("Expected ErrInvalidPoint for x with no square root, got %v", err)
+	}
+	if _, err := ParsePoint([]byte{0x02}); !errors.Is(err, ErrInvalidPoint) {
+		t.Errorf("Expected ErrInvalidPoint for a truncated encoding, got %v", err)
+	}
+}
+
+// TestInfinity tests the point at infinity
+func TestInfinity(t *testing.T) {
+	var inf Point
+	if !inf.IsInfinity() {
+		t.Error("Expected the zero Point to be the point at infinity")
+	}
+	if inf.X() != nil || inf.Y() != nil {
+		t.Error("Expected nil coordinates for the point at infinity")
+	}
+	if _, err := inf.PubKey(); !errors.Is(err, ErrInvalidPoint) {
+		t.Errorf("Expected ErrInvalidPoint from PubKey, got %v", err)
+	}
+	if inf.Equal(Generator()) || Generator().Equal(&inf) {
+		t.Error("Expected infinity and G to differ")
+	}
+}
+
+// TestLiftX tests BIP340 lift_x on the generator and on random points
+func TestLiftX(t *testing.T) {
+	// Gy is even, so lifting Gx gives G itself
+	p, err := LiftX(CURVE.Gx)
+	if err != nil {
+		t.Fatalf("LiftX(Gx) failed: %v", err)
+	}
+	if !p.Equal(Generator()) {
+		t.Errorf("LiftX(Gx) = %s, expected %s", p, Generator())
+	}
+
+	for i := 0; i < 10; i++ {
+		k, err := RandScalar()
+		if err != nil {
+			t.Fatalf("RandScalar failed: %v", err)
+		}
+		q := ScalarBaseMult(k)
+		lifted, err := LiftX(q.X())
+		if err != nil {
+			t.Fatalf("LiftX failed: %v", err)
+		}
+		if !HasEvenY(lifted) {
+			t.Errorf("Expected LiftX(%x) to have an even Y", q.X())
+		}
+		if HasEvenY(q) && !lifted.Equal(q) || !HasEvenY(q) && !lifted.Equal(Negate(q)) {
+			t.Errorf("LiftX(x(Q)) = %s, expected the even-Y one of ±%s", lifted, q)
+		}
+	}
+
+	testCases := []struct {
+		name string
+		x    *big.Int
+	}{
+		{"Not on the curve", big.NewInt(5)},
+		{"Equal to P", P},
+		{"Negative", big.NewInt(-1)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LiftX(tc.x); !errors.Is(err, ErrInvalidPoint) {
+				t.Errorf("Expected ErrInvalidPoint, got %v", err)
+			}
+		})
+	}
+}
+
+// TestNegate tests point negation and Y parity
+func TestNegate(t *testing.T) {
+	g := Generator()
+	negG := Negate(g)
+	if !negG.Equal(ScalarBaseMult(big.NewInt(-1))) {
+		t.Errorf("Negate(G) = %s, expected %s", negG, ScalarBaseMult(big.NewInt(-1)))
+	}
+	if !PointAdd(g, negG).IsInfinity() {
+		t.Error("Expected G + Negate(G) to be the point at infinity")
+	}
+	if !Negate(negG).Equal(g) {
+		t.Error("Expected Negate(Negate(G)) to equal G")
+	}
+	if !HasEvenY(g) || HasEvenY(negG) {
+		t.Error("Expected G to have an even Y and -G an odd Y")
+	}
+	if g.Bytes()[0] != 0x02 || negG.Bytes()[0] != 0x03 {
+		t.Error("Expected HasEvenY to match the compressed prefix")
+	}
+
+	var inf Point
+	if !Negate(&inf).IsInfinity() || HasEvenY(&inf) {
+		t.Error("Expected -infinity = infinity without an even Y")
+	}
+}
@@ -0,0 +1,98 @@
+package arithmetic
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// Deterministic nonces (RFC 6979)
+//
+// A signature nonce must be secret, uniform and never reused: two ECDSA or
+// Schnorr signatures with the same k under one key reveal the key. Instead
+// of trusting a random number generator at signing time, RFC 6979 derives k
+// from the private key and the message hash with HMAC-DRBG:
+//
+//	V = 0x01 × 32, K = 0x00 × 32
+//	K = HMAC_K(V || 0x00 || int2octets(d) || bits2octets(h) || extra)
+//	V = HMAC_K(V)
+//	K = HMAC_K(V || 0x01 || int2octets(d) || bits2octets(h) || extra)
+//	V = HMAC_K(V)
+//	repeat: V = HMAC_K(V); k = bits2int(V); if 1 ≤ k < N return k
+//	        K = HMAC_K(V || 0x00); V = HMAC_K(V)
+//
+// The same key and hash always give the same k, and different messages give
+// unrelated ones. extra is the "additional data" of section 3.6: protocols
+// use it to get a different nonce for the same message, e.g. fresh
+// randomness as a hedge against fault attacks, or a counter to grind for a
+// low-R signature. With HMAC-SHA256 and a 256-bit N, the output matches
+// btcec.NonceRFC6979 with a 32-byte extra and no version.
+
+// ErrInvalidPrivateKey is returned for private keys outside [1, N-1]
+var ErrInvalidPrivateKey = errors.New("private key must be in [1, N-1]")
+
+// RFC6979Nonce returns the deterministic nonce for signing hash with priv
+//
+// hash is the message digest; like in ECDSA, only its leftmost 256 bits are
+// used. extra may be nil.
+//
+// Example:
+//
+//	digest := sha256.Sum256([]byte("Satoshi Nakamoto"))
+//	k, err := RFC6979Nonce(big.NewInt(1), digest[:], nil)
+//	// Result: k = 0x8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15
+func RFC6979Nonce(priv *big.Int, hash []byte, extra []byte) (*big.Int, error) {
+	// Step 1: Validate the key and encode the inputs as 32-byte strings
+	if priv.Sign() <= 0 || priv.Cmp(N) >= 0 {
+		return nil, ErrInvalidPrivateKey
+	}
+	var x, h1 [32]byte
+	priv.FillBytes(x[:])
+	defer ZeroBytes(x[:])
+	ModN(bits2int(hash)).FillBytes(h1[:])
+
+	// Step 2: Seed the DRBG
+	v := make([]byte, sha256.Size)
+	k := make([]byte, sha256.Size)
+	for i := range v {
+		v[i] = 0x01
+	}
+	defer func() { ZeroBytes(k) }()
+	for _, sep := range []byte{0x00, 0x01} {
+		mac := hmac.New(sha256.New, k)
+		mac.Write(v)
+		mac.Write([]byte{sep})
+		mac.Write(x[:])
+		mac.Write(h1[:])
+		mac.Write(extra)
+		k = mac.Sum(k[:0])
+		v = hmacSHA256(k, v)
+	}
+
+	// Step 3: Draw candidates until one is a valid scalar
+	for {
+		v = hmacSHA256(k, v)
+		nonce := bits2int(v)
+		if nonce.Sign() > 0 && nonce.Cmp(N) < 0 {
+			return nonce, nil
+		}
+		k = hmacSHA256(k, append(v, 0x00))
+		v = hmacSHA256(k, v)
+	}
+}
+
+// bits2int interprets the leftmost 256 bits of b as an integer (RFC 6979 section 2.3.2)
+func bits2int(b []byte) *big.Int {
+	if len(b) > 32 {
+		b = b[:32]
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+// hmacSHA256 returns HMAC-SHA256(key, data) in a new slice
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
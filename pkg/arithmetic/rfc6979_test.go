@@ -0,0 +1,107 @@
+package arithmetic
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// TestRFC6979Nonce tests known secp256k1/SHA-256 vectors
+func TestRFC6979Nonce(t *testing.T) {
+	testCases := []struct {
+		name     string
+		priv     string
+		message  string
+		expected string
+	}{
+		{
+			name:     "Key 1",
+			priv:     "1",
+			message:  "Satoshi Nakamoto",
+			expected: "8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15",
+		},
+		{
+			name:     "Key N-1",
+			priv:     "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+			message:  "Satoshi Nakamoto",
+			expected: "33a19b60e25fb6f4435af53a3d42d493644827367e6453928554f43e49aa6f90",
+		},
+		{
+			name:     "Key 1, other message",
+			priv:     "1",
+			message:  "All those moments will be lost in time, like tears in rain. Time to die...",
+			expected: "38aa22d72376b4dbc472e06c3ba403ee0a394da63fc58d88686c611aba98d6b3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			priv, _ := new(big.Int).SetString(tc.priv, 16)
+			digest := sha256.Sum256([]byte(tc.message))
+			k, err := RFC6979Nonce(priv, digest[:], nil)
+			if err != nil {
+				t.Fatalf("RFC6979Nonce failed: %v", err)
+			}
+			if got := k.Text(16); got != tc.expected {
+				t.Errorf("RFC6979Nonce() = %s, expected %s", got, tc.expected)
+			}
+		})
+	}
+}
+
+// TestRFC6979NonceMatchesBtcec tests against btcec's implementation, with and without extra data
+func TestRFC6979NonceMatchesBtcec(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		priv, err := RandScalar()
+		if err != nil {
+			t.Fatalf("RandScalar failed: %v", err)
+		}
+		digest := sha256.Sum256([]byte{byte(i)})
+		extra := sha256.Sum256([]byte{byte(i), 0xEE})
+		privBytes := ToBytes32(priv.Bytes())
+
+		for _, data := range [][]byte{nil, extra[:]} {
+			k, err := RFC6979Nonce(priv, digest[:], data)
+			if err != nil {
+				t.Fatalf("RFC6979Nonce failed: %v", err)
+			}
+			expected := btcec.NonceRFC6979(privBytes[:], digest[:], data, nil, 0).Bytes()
+			if got := ToBytes32(k.Bytes()); !bytes.Equal(got[:], expected[:]) {
+				t.Errorf("RFC6979Nonce() = %x, expected %x", got, expected)
+			}
+		}
+	}
+}
+
+// TestRFC6979NonceProperties tests determinism, extra data and key validation
+func TestRFC6979NonceProperties(t *testing.T) {
+	priv := big.NewInt(42)
+	digest := sha256.Sum256([]byte("message"))
+
+	k1, _ := RFC6979Nonce(priv, digest[:], nil)
+	k2, _ := RFC6979Nonce(priv, digest[:], nil)
+	if k1.Cmp(k2) != 0 {
+		t.Error("Expected the same nonce for the same key and hash")
+	}
+	k3, _ := RFC6979Nonce(priv, digest[:], []byte{1})
+	if k1.Cmp(k3) == 0 {
+		t.Error("Expected extra data to change the nonce")
+	}
+
+	// Only the leftmost 256 bits of a longer hash are used
+	long := append(digest[:], 0xAB, 0xCD)
+	k4, _ := RFC6979Nonce(priv, long, nil)
+	if k1.Cmp(k4) != 0 {
+		t.Error("Expected a hash longer than 256 bits to be truncated")
+	}
+
+	for _, invalid := range []*big.Int{big.NewInt(0), big.NewInt(-1), N} {
+		if _, err := RFC6979Nonce(invalid, digest[:], nil); !errors.Is(err, ErrInvalidPrivateKey) {
+			t.Errorf("Expected ErrInvalidPrivateKey for %x, got %v", invalid, err)
+		}
+	}
+}
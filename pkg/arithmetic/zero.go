@@ -0,0 +1,48 @@
+package arithmetic
+
+import (
+	"math/big"
+	"runtime"
+)
+
+// Zeroization
+//
+// Private keys, nonces and their inverses should not outlive the signature
+// they were used for: a memory dump, a core file or a swapped-out page would
+// otherwise reveal them. Clearing them is not as simple as it looks:
+//
+//	x.SetInt64(0)  sets the length to zero but leaves the old words in the backing array
+//	clear(buf)     may be dropped by the compiler if buf is never read again
+//
+// ZeroBigInt overwrites the whole backing array of a big.Int, and both
+// helpers keep the buffer alive until the writes are done. This is best
+// effort: the garbage collector and big.Int's own arithmetic may already
+// have copied the value elsewhere, so secrets should also be kept in as
+// few temporaries as possible.
+
+// ZeroBytes overwrites b with zeros
+//
+// Example:
+//
+//	var seed [32]byte
+//	defer ZeroBytes(seed[:])
+func ZeroBytes(b []byte) {
+	clear(b)
+	runtime.KeepAlive(b)
+}
+
+// ZeroBigInt overwrites the digits of x and sets it to 0; nil is ignored
+//
+// Example:
+//
+//	k, err := RandScalar()
+//	defer ZeroBigInt(k)
+func ZeroBigInt(x *big.Int) {
+	if x == nil {
+		return
+	}
+	words := x.Bits()
+	clear(words[:cap(words)])
+	x.SetInt64(0)
+	runtime.KeepAlive(words)
+}
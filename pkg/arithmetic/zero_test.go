@@ -0,0 +1,43 @@
+package arithmetic
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestZeroBytes tests that every byte is cleared
+func TestZeroBytes(t *testing.T) {
+	b := []byte{1, 2, 3, 4, 5}
+	ZeroBytes(b)
+	for i, v := range b {
+		if v != 0 {
+			t.Errorf("Expected byte %d to be zero, got %d", i, v)
+		}
+	}
+	ZeroBytes(nil)
+}
+
+// TestZeroBigInt tests that the backing array is cleared, not only the length
+func TestZeroBigInt(t *testing.T) {
+	x, err := RandScalar()
+	if err != nil {
+		t.Fatalf("RandScalar failed: %v", err)
+	}
+	words := x.Bits()
+
+	ZeroBigInt(x)
+	if x.Sign() != 0 {
+		t.Errorf("Expected 0, got %x", x)
+	}
+	for i, w := range words[:cap(words)] {
+		if w != 0 {
+			t.Errorf("Expected word %d of the backing array to be zero, got %x", i, w)
+		}
+	}
+
+	// A zeroed value is still usable
+	if x.Add(x, big.NewInt(7)).Int64() != 7 {
+		t.Error("Expected a zeroed big.Int to be reusable")
+	}
+	ZeroBigInt(nil)
+}
@@ -0,0 +1,215 @@
+package armor
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ASCII armor for binary artifacts
+//
+// Keys, signatures, PSBTs and backups are binary; pasted into an email or a
+// chat they get rewrapped, quoted and trimmed. Armor wraps them the way
+// OpenPGP does:
+//
+//	-----BEGIN PLAYGROUND MESSAGE-----
+//	Comment: for Bob
+//
+//	c2VhbGVkIGJ5dGVzIGZyb20gZXhjaGFuZ2UuU2VhbCwgd3JhcHBlZCBhdCBzaXh0
+//	eS1mb3VyIGNoYXJhY3RlcnMgcGVyIGxpbmU=
+//	=SN8W
+//	-----END PLAYGROUND MESSAGE-----
+//
+// The body is base64 wrapped at 64 characters, and the "=" line is the base64
+// of a CRC-24 of the data, which catches the lines a copy-paste lost. The CRC
+// is not a security check: anything that must not be modified needs a
+// signature or a MAC inside the data.
+
+// Block types used by the repository
+const (
+	TypeMessage    = "MESSAGE"     // exchange.Seal output
+	TypeSignature  = "SIGNATURE"   // Detached signatures and proofs
+	TypePrivateKey = "PRIVATE KEY" // Raw or encrypted private keys
+	TypePSBT       = "PSBT"        // Partially signed transactions
+	TypeBackup     = "BACKUP"      // Backup bundles and checkpoints
+)
+
+const (
+	// beginPrefix and endPrefix surround the block type on the first and last lines
+	beginPrefix = "-----BEGIN PLAYGROUND "
+	endPrefix   = "-----END PLAYGROUND "
+	dashes      = "-----"
+
+	// lineWidth is the base64 line length
+	lineWidth = 64
+
+	// crc24Init and crc24Poly are the OpenPGP CRC-24 parameters (RFC 4880)
+	crc24Init = 0xb704ce
+	crc24Poly = 0x1864cfb
+)
+
+var (
+	// ErrNoArmor is returned when the text contains no armored block
+	ErrNoArmor = errors.New("no armored block found")
+	// ErrChecksum is returned when the body does not match its CRC-24
+	ErrChecksum = errors.New("armor checksum mismatch")
+)
+
+// Block is a decoded armored block
+type Block struct {
+	Type    string            // e.g. TypeMessage
+	Headers map[string]string // Optional "Key: Value" lines
+	Bytes   []byte
+}
+
+// Encode armors data under a block type
+//
+// Headers are written sorted by key; they are not covered by the checksum.
+//
+// Example:
+//
+//	text := armor.Encode(armor.TypeSignature, map[string]string{"Comment": "release v1.2"}, sig[:])
+func Encode(blockType string, headers map[string]string, data []byte) string {
+	var b strings.Builder
+
+	// Step 1: Begin line and headers
+	b.WriteString(beginPrefix + blockType + dashes + "\n")
+	if len(headers) > 0 {
+		keys := make([]string, 0, len(headers))
+		for k := range headers {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			b.WriteString(k + ": " + headers[k] + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	// Step 2: Wrapped base64 body
+	body := base64.StdEncoding.EncodeToString(data)
+	for len(body) > lineWidth {
+		b.WriteString(body[:lineWidth] + "\n")
+		body = body[lineWidth:]
+	}
+	if body != "" {
+		b.WriteString(body + "\n")
+	}
+
+	// Step 3: Checksum and end line
+	crc := crc24(data)
+	b.WriteString("=" + base64.StdEncoding.EncodeToString([]byte{byte(crc >> 16), byte(crc >> 8), byte(crc)}) + "\n")
+	b.WriteString(endPrefix + blockType + dashes + "\n")
+	return b.String()
+}
+
+// Decode finds and decodes the first armored block in text
+//
+// Text around the block, CRLF line endings, indentation and "> " quote
+// prefixes are ignored.
+//
+// Example:
+//
+//	block, err := armor.Decode(pastedText)
+//	if err == nil && block.Type == armor.TypeSignature {
+//		// use block.Bytes
+//	}
+func Decode(text string) (*Block, error) {
+	// Step 1: Find the begin line
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(lines[i]), ">"))
+	}
+	start := -1
+	var blockType string
+	for i, line := range lines {
+		if strings.HasPrefix(line, beginPrefix) && strings.HasSuffix(line, dashes) && len(line) > len(beginPrefix)+len(dashes) {
+			start, blockType = i, line[len(beginPrefix):len(line)-len(dashes)]
+			break
+		}
+	}
+	if start < 0 {
+		return nil, ErrNoArmor
+	}
+
+	// Step 2: Headers, body and checksum up to the matching end line
+	block := &Block{Type: blockType}
+	endLine := endPrefix + blockType + dashes
+	var body strings.Builder
+	var checksum string
+	inHeaders, ended := true, false
+	for _, line := range lines[start+1:] {
+		if line == endLine {
+			ended = true
+			break
+		}
+		if inHeaders {
+			if key, value, ok := strings.Cut(line, ": "); ok && !strings.ContainsAny(key, " =") {
+				if block.Headers == nil {
+					block.Headers = make(map[string]string)
+				}
+				block.Headers[key] = value
+				continue
+			}
+			inHeaders = false
+		}
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "="):
+			checksum = line[1:]
+		default:
+			body.WriteString(line)
+		}
+	}
+	if !ended {
+		return nil, fmt.Errorf("armored %s block is not terminated", blockType)
+	}
+
+	// Step 3: Decode and check the CRC
+	data, err := base64.StdEncoding.DecodeString(body.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid armored body: %w", err)
+	}
+	crc, err := base64.StdEncoding.DecodeString(checksum)
+	if err != nil || len(crc) != 3 {
+		return nil, fmt.Errorf("%w: missing or malformed checksum line", ErrChecksum)
+	}
+	if uint32(crc[0])<<16|uint32(crc[1])<<8|uint32(crc[2]) != crc24(data) {
+		return nil, ErrChecksum
+	}
+	block.Bytes = data
+	return block, nil
+}
+
+// DecodeType decodes the first block and checks its type
+//
+// Example:
+//
+//	sealed, err := armor.DecodeType(pastedText, armor.TypeMessage)
+func DecodeType(text, blockType string) ([]byte, error) {
+	block, err := Decode(text)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != blockType {
+		return nil, fmt.Errorf("expected an armored %s, got %s", blockType, block.Type)
+	}
+	return block.Bytes, nil
+}
+
+// crc24 computes the OpenPGP CRC-24 of data
+func crc24(data []byte) uint32 {
+	crc := uint32(crc24Init)
+	for _, b := range data {
+		crc ^= uint32(b) << 16
+		for i := 0; i < 8; i++ {
+			crc <<= 1
+			if crc&0x1000000 != 0 {
+				crc ^= crc24Poly
+			}
+		}
+	}
+	return crc & 0xffffff
+}
@@ -0,0 +1,126 @@
+package armor
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestCRC24 tests the OpenPGP CRC-24 against known values
+func TestCRC24(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected uint32
+	}{
+		{"", 0xb704ce},
+		{"123456789", 0x21cf02},
+	}
+	for _, tt := range tests {
+		if got := crc24([]byte(tt.data)); got != tt.expected {
+			t.Errorf("crc24(%q): expected %06x, got %06x", tt.data, tt.expected, got)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		headers map[string]string
+		data    []byte
+	}{
+		{"empty", TypeSignature, nil, nil},
+		{"short", TypePrivateKey, nil, []byte{0x01, 0x02, 0x03}},
+		{"exactly one line", TypeBackup, nil, bytes.Repeat([]byte{0xaa}, 48)},
+		{"several lines", TypePSBT, map[string]string{"Comment": "for Bob", "Version": "1"}, bytes.Repeat([]byte("psbt"), 100)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := Encode(tt.typ, tt.headers, tt.data)
+			for _, line := range strings.Split(text, "\n") {
+				if len(line) > lineWidth && !strings.HasPrefix(line, "-----") {
+					t.Errorf("Expected lines of at most %d characters, got %q", lineWidth, line)
+				}
+			}
+
+			block, err := Decode(text)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if block.Type != tt.typ || !bytes.Equal(block.Bytes, tt.data) {
+				t.Errorf("Expected %s block with %x, got %s block with %x", tt.typ, tt.data, block.Type, block.Bytes)
+			}
+			if len(block.Headers) != len(tt.headers) {
+				t.Errorf("Expected headers %v, got %v", tt.headers, block.Headers)
+			}
+			for k, v := range tt.headers {
+				if block.Headers[k] != v {
+					t.Errorf("Expected header %s: %s, got %q", k, v, block.Headers[k])
+				}
+			}
+		})
+	}
+}
+
+// TestDecodeMangled tests text as it comes back from mail and chat clients
+func TestDecodeMangled(t *testing.T) {
+	data := bytes.Repeat([]byte("backup"), 30)
+	text := Encode(TypeBackup, map[string]string{"Comment": "keep offline"}, data)
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"surrounding text", "Here is the backup:\n\n" + text + "\n-- \nAlice"},
+		{"CRLF", strings.ReplaceAll(text, "\n", "\r\n")},
+		{"quoted", "> " + strings.ReplaceAll(text, "\n", "\n> ")},
+		{"indented", "    " + strings.ReplaceAll(text, "\n", "\n    ")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeType(tt.text, TypeBackup)
+			if err != nil {
+				t.Fatalf("DecodeType failed: %v", err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Error("Expected the original data back")
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	data := bytes.Repeat([]byte("signature"), 20)
+	text := Encode(TypeSignature, nil, data)
+	lines := strings.Split(text, "\n")
+
+	// A lost body line is caught by the checksum (or by base64 itself)
+	dropped := strings.Join(append(append([]string{}, lines[:2]...), lines[3:]...), "\n")
+	if _, err := Decode(dropped); err == nil {
+		t.Error("Expected error for a missing line")
+	}
+
+	// Changed data with a valid encoding fails the checksum
+	changed := strings.Replace(text, lines[1], "A"+lines[1][1:], 1)
+	if lines[1][0] == 'A' {
+		changed = strings.Replace(text, lines[1], "B"+lines[1][1:], 1)
+	}
+	if _, err := Decode(changed); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Expected ErrChecksum, got %v", err)
+	}
+
+	noChecksum := strings.Replace(text, "\n=", "\n", 1)
+	if _, err := Decode(noChecksum); err == nil {
+		t.Error("Expected error for a missing checksum")
+	}
+	if _, err := Decode("plain text"); !errors.Is(err, ErrNoArmor) {
+		t.Errorf("Expected ErrNoArmor, got %v", err)
+	}
+	if _, err := Decode(strings.Join(lines[:len(lines)-2], "\n")); err == nil {
+		t.Error("Expected error for a missing end line")
+	}
+	if _, err := DecodeType(text, TypePSBT); err == nil {
+		t.Error("Expected error for the wrong block type")
+	}
+}
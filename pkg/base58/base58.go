@@ -1,24 +1,144 @@
 package base58
 
 import (
-	"bytes"
-	"crypto/sha256"
 	"fmt"
 	"math/big"
-	"strings"
+
+	"github.com/neverDefined/cryptography-playground/pkg/bigpool"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
 )
 
-// The alphabet that we are going to use for encoding and decoding Base58 strings.
-// The alphabet consists of the following characters:
-// 123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz
-const alphabet string = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+// Option configures Encode and Decode
+type Option func(*options)
+
+type options struct {
+	pool      *bigpool.Pool
+	maxLength int // 0 means unlimited
+	workers   int // EncodeAll goroutines; 0 means 1
+}
+
+// WithPool draws the big.Int temporaries from pool instead of bigpool.Default
+//
+// Bulk callers can give each worker its own pool to avoid contention.
+//
+// Example:
+//
+//	pool := bigpool.New()
+//	for _, payload := range payloads {
+//		out = append(out, base58.Encode(payload, base58.WithPool(pool)))
+//	}
+func WithPool(pool *bigpool.Pool) Option {
+	return func(o *options) {
+		o.pool = pool
+	}
+}
+
+// WithMaxLength makes Decode, DecodeInto and CheckDecode reject strings longer than n
+// characters with ErrTooLong, before doing any arithmetic. Encode ignores it.
+//
+// Decoding costs time quadratic in the length of the string, so input from an
+// untrusted source (a request body, a QR code, a file) should be bounded by the
+// longest value it can legitimately hold: 35 characters for a P2PKH address,
+// 52 for a WIF key, 111 for an extended key.
+//
+// Example:
+//
+//	payload, version, err := base58.Base58CheckDecode(untrusted, base58.WithMaxLength(111))
+func WithMaxLength(n int) Option {
+	return func(o *options) {
+		o.maxLength = n
+	}
+}
+
+// checkLength fails fast on strings longer than the configured limit
+func (o options) checkLength(data string) error {
+	if o.maxLength > 0 && len(data) > o.maxLength {
+		return fmt.Errorf("%w: %d characters, limit %d", ErrTooLong, len(data), o.maxLength)
+	}
+	return nil
+}
+
+func newOptions(opts []Option) options {
+	// Without options, skip the setup below: passing &o to an Option moves o to the heap
+	if len(opts) == 0 {
+		return options{pool: bigpool.Default}
+	}
+	o := options{pool: bigpool.Default}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.pool == nil {
+		o.pool = bigpool.Default
+	}
+	return o
+}
+
+// MaxEncodedLen returns the largest length of the Base58 encoding of n bytes
+//
+// Base58 needs at most ~1.37 characters per byte (log(256)/log(58)).
+func MaxEncodedLen(n int) int {
+	return n*138/100 + 1
+}
 
 // Encode encodes a byte slice into a Base58 string.
-func Encode(data []byte) string {
-	num := new(big.Int).SetBytes(data)
-	base := big.NewInt(58)
-	zero := big.NewInt(0)
-	result := ""
+func Encode(data []byte, opts ...Option) string {
+	return BitcoinEncoding.Encode(data, opts...)
+}
+
+// AppendEncode appends the Base58 encoding of data to dst and returns the extended buffer.
+// When dst has room for MaxEncodedLen(len(data)) more bytes and no options are given,
+// nothing is allocated, so bulk encoders can reuse one buffer for every call.
+//
+// Example:
+//
+//	buf := make([]byte, 0, 64)
+//	for _, payload := range payloads {
+//		buf = base58.AppendEncode(buf[:0], payload)
+//		w.Write(buf)
+//	}
+func AppendEncode(dst, data []byte, opts ...Option) []byte {
+	return BitcoinEncoding.AppendEncode(dst, data, opts...)
+}
+
+// Decode decodes a Base58 string into a byte slice.
+//
+// For untrusted input, pass WithMaxLength: the work grows with the square of the length.
+func Decode(data string, opts ...Option) ([]byte, error) {
+	return BitcoinEncoding.Decode(data, opts...)
+}
+
+// DecodeInto decodes a Base58 string into dst and returns the number of bytes written.
+// A decoded string is never longer than the string itself, so len(dst) >= len(data)
+// always suffices. Without options nothing is allocated, and dst is left unchanged on error.
+//
+// Example:
+//
+//	var buf [25]byte
+//	n, err := base58.DecodeInto(buf[:], address)
+//	payload := buf[:n]
+func DecodeInto(dst []byte, data string, opts ...Option) (int, error) {
+	return BitcoinEncoding.DecodeInto(dst, data, opts...)
+}
+
+// Encode encodes a byte slice into a string in the alphabet of enc
+func (enc *Encoding) Encode(data []byte, opts ...Option) string {
+	return string(enc.AppendEncode(make([]byte, 0, enc.MaxEncodedLen(len(data))), data, opts...))
+}
+
+// AppendEncode appends the encoding of data to dst, like the package-level AppendEncode
+func (enc *Encoding) AppendEncode(dst, data []byte, opts ...Option) []byte {
+	return enc.appendEncode(dst, data, newOptions(opts).pool)
+}
+
+// appendEncode is AppendEncode with the options already applied
+func (enc *Encoding) appendEncode(dst, data []byte, pool *bigpool.Pool) []byte {
+	num := pool.Get().SetBytes(data)
+	base := pool.Get().SetInt64(enc.base)
+	mod := pool.Get()
+	defer pool.Release(num, base, mod)
+
+	// The encoding is built in place after the existing contents of dst
+	start := len(dst)
 
 	// Example: encoding [0x1A, 0x2B] (26, 43 in decimal)
 	// Step 1: num = 26*256 + 43 = 6699 (big-endian: 26 is MSB, 43 is LSB)
@@ -26,44 +146,96 @@ func Encode(data []byte) string {
 	// Step 3: 115 ÷ 58 = 1 remainder 57 → alphabet[57] = 'z'
 	// Step 4: 1 ÷ 58 = 0 remainder 1 → alphabet[1] = '2'
 	// Result: "2zW" (reading remainders in reverse order)
-	for num.Cmp(zero) > 0 {
-		mod := new(big.Int)
-		num.DivMod(num, base, mod)                      // divide num by 58 and store the remainder in mod
-		result = string(alphabet[mod.Int64()]) + result // add the character to the result, prepend it to the result
+	for num.Sign() > 0 {
+		num.QuoRem(num, base, mod)                   // divide num by 58 (the base) and store the remainder in mod
+		dst = append(dst, enc.alphabet[mod.Int64()]) // collect characters least significant first
 	}
 
-	// Handle leading zeros (add '1' for each 0x00 byte)
+	// Handle leading zeros (add alphabet[0], '1' for Bitcoin, for each 0x00 byte)
 	// Example: encoding [0x00, 0x00, 0x1A, 0x2B]
 	// The main loop would convert [0x1A, 0x2B] to "2zW"
 	// But we need to preserve the two leading zeros
 	// So we add "11" (two '1' characters) to the front
 	// Final result: "112zW"
-	leadingZeroCount := 0
 	for _, b := range data {
-		if b == 0x00 {
-			leadingZeroCount++
-		} else {
+		if b != 0x00 {
 			break
 		}
+		dst = append(dst, enc.alphabet[0])
 	}
 
-	// Add '1' characters for each leading zero
-	for i := 0; i < leadingZeroCount; i++ {
-		result = "1" + result
+	// Reverse into most-significant-first order
+	for i, j := start, len(dst)-1; i < j; i, j = i+1, j-1 {
+		dst[i], dst[j] = dst[j], dst[i]
 	}
 
-	return result
+	return dst
 }
 
-// Decode decodes a Base58 string into a byte slice.
-func Decode(data string) ([]byte, error) {
+// Decode decodes a string in the alphabet of enc into a byte slice
+func (enc *Encoding) Decode(data string, opts ...Option) ([]byte, error) {
 	// Handle empty string
 	if data == "" {
 		return []byte{}, nil
 	}
 
-	num := big.NewInt(0)
-	base := big.NewInt(58)
+	o := newOptions(opts)
+	if err := o.checkLength(data); err != nil {
+		return nil, err
+	}
+	pool := o.pool
+	num := pool.Get()
+	defer pool.Release(num)
+	leadingZeroCount, err := enc.parse(data, num, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the result byte slice with: [leadingZeroCount] + [decodedBytes]
+	// FillBytes writes the number straight into the result without a temporary slice
+	result := make([]byte, leadingZeroCount+(num.BitLen()+7)/8)
+	num.FillBytes(result[leadingZeroCount:])
+
+	return result, nil
+}
+
+// DecodeInto decodes into dst, like the package-level DecodeInto
+func (enc *Encoding) DecodeInto(dst []byte, data string, opts ...Option) (int, error) {
+	o := newOptions(opts)
+	if err := o.checkLength(data); err != nil {
+		return 0, err
+	}
+	pool := o.pool
+	num := pool.Get()
+	defer pool.Release(num)
+	leadingZeroCount, err := enc.parse(data, num, pool)
+	if err != nil {
+		return 0, err
+	}
+
+	// Same layout as Decode: [leadingZeroCount zeros] + [decodedBytes]
+	n := leadingZeroCount + (num.BitLen()+7)/8
+	if n > len(dst) {
+		return 0, fmt.Errorf("destination too small: need %d bytes, have %d", n, len(dst))
+	}
+	clear(dst[:leadingZeroCount])
+	num.FillBytes(dst[leadingZeroCount:n])
+
+	return n, nil
+}
+
+// parse reads the Base58 digits of data into num and counts the leading zero characters
+func (enc *Encoding) parse(data string, num *big.Int, pool *bigpool.Pool) (int, error) {
+	// Validate every character before any arithmetic, so garbage is rejected in linear time
+	for i, char := range data {
+		if enc.index(char) == -1 {
+			return 0, &InvalidCharacterError{Char: char, Position: i}
+		}
+	}
+
+	base := pool.Get().SetInt64(enc.base)
+	digit := pool.Get()
+	defer pool.Release(base, digit)
 
 	// Example: decoding "2zW"
 	// Step 1: '2' = alphabet[1] = 1 → num = 0*58 + 1 = 1
@@ -71,37 +243,28 @@ func Decode(data string) ([]byte, error) {
 	// Step 3: 'W' = alphabet[29] = 29 → num = 115*58 + 29 = 6699
 	// Result: 6699 in decimal = [0x1A, 0x2B] in bytes
 	for _, char := range data {
-		// Find the position of the character in the alphabet
-		pos := strings.IndexRune(alphabet, char)
-		if pos == -1 {
-			return nil, fmt.Errorf("invalid character: %c", char)
-		}
+		// Find the position of the character in the alphabet (already validated above)
+		pos := enc.index(char)
 
-		// Multiply current number by 58 and add the new digit
+		// Multiply current number by 58 (the base) and add the new digit
 		num.Mul(num, base)
-		num.Add(num, big.NewInt(int64(pos)))
+		num.Add(num, digit.SetInt64(int64(pos)))
 	}
 
-	// Convert the big int to a byte slice
-	decodedBytes := num.Bytes()
-
-	// Handle leading '1' characters (which represent 0x00 bytes)
+	// Handle leading '1' characters (alphabet[0], which represent 0x00 bytes)
 	// Example: decoding "112zW"
 	// We found 2 leading '1' characters, so we need to add 2 leading 0x00 bytes
 	// result = [0x1A, 0x2B] becomes [0x00, 0x00, 0x1A, 0x2B]
 	leadingZeroCount := 0
 	for _, char := range data {
-		if char == '1' {
+		if char == rune(enc.alphabet[0]) {
 			leadingZeroCount++
 		} else {
 			break
 		}
 	}
 
-	// Create the result byte slice with: [leadingZeroCount] + [decodedBytes]
-	result := append(make([]byte, leadingZeroCount), decodedBytes...)
-
-	return result, nil
+	return leadingZeroCount, nil
 }
 
 // Base58CheckEncode creates a Base58Check encoded string with version byte and checksum.
@@ -115,30 +278,33 @@ func Decode(data string) ([]byte, error) {
 // - version 0x00, payload [0x60, 0x23, 0xBD, ...] → "19mLgd5RjgEcyGfnj5ra4gW8FjtvLc2Adr"
 // - version 0x6F, payload [0x60, 0x23, 0xBD, ...] → "mpHHygAQYhfskP9QSepwtbiT7jVdFgjEA8"
 func Base58CheckEncode(version byte, payload []byte) string {
+	return BitcoinEncoding.CheckEncode(version, payload)
+}
+
+// CheckEncode is Base58CheckEncode in the alphabet of enc
+//
+// Example:
+//
+//	address := base58.RippleEncoding.CheckEncode(0x00, accountID) // "r..."
+func (enc *Encoding) CheckEncode(version byte, payload []byte) string {
 	// Step 1: Combine version byte with payload
 	// Example: version 0x00, payload [0x1A, 0x2B] → data = [0x00, 0x1A, 0x2B]
 	// The version byte indicates the type of address (0x00 = Bitcoin mainnet, 0x6F = Bitcoin testnet)
 	data := append([]byte{version}, payload...)
 
-	// Step 2: Calculate double SHA256 hash of the data
-	// Example: SHA256([0x00, 0x1A, 0x2B]) → SHA256(result) → [0x12, 0x34, 0x56, 0x78, ...]
-	// This provides cryptographic integrity checking
-	hash256 := sha256.Sum256(data)
-	hash256d := sha256.Sum256(hash256[:])
-
-	// Step 3: Extract first 4 bytes as checksum
-	// Example: checksum = [0x12, 0x34, 0x56, 0x78]
+	// Step 2: Calculate the checksum: the first 4 bytes of SHA256D(data)
+	// Example: SHA256(SHA256([0x00, 0x1A, 0x2B])) → checksum = [0x95, 0x8B, 0xF0, 0x05]
 	// The checksum allows detection of typos or corruption
-	checksum := hash256d[:4]
+	checksum := hash.Checksum4(data)
 
-	// Step 4: Combine original data with checksum
-	// Example: [0x00, 0x1A, 0x2B, 0x12, 0x34, 0x56, 0x78]
+	// Step 3: Combine original data with checksum
+	// Example: [0x00, 0x1A, 0x2B, 0x95, 0x8B, 0xF0, 0x05]
 	// Final format: [version][payload][checksum]
-	dataToEncode := append(data, checksum...)
+	dataToEncode := append(data, checksum[:]...)
 
-	// Step 5: Encode the complete data to Base58
-	// Example: [0x00, 0x1A, 0x2B, 0x12, 0x34, 0x56, 0x78] → "1E2riae4C"
-	return Encode(dataToEncode)
+	// Step 4: Encode the complete data to Base58
+	// Example: [0x00, 0x1A, 0x2B, 0x95, 0x8B, 0xF0, 0x05] → "1E2riae4C"
+	return enc.Encode(dataToEncode)
 }
 
 // Base58CheckDecode decodes a Base58Check string and returns the payload, version, and validates the checksum.
@@ -157,10 +323,15 @@ func Base58CheckEncode(version byte, payload []byte) string {
 // - "1Wh4bh" → (payload: [], version: 0x00)
 // - "19mLgd5RjgEcyGfnj5ra4gW8FjtvLc2Adr" → (payload: [0x60, 0x23, ...], version: 0x00)
 // - "mpHHygAQYhfskP9QSepwtbiT7jVdFgjEA8" → (payload: [0x60, 0x23, ...], version: 0x6F)
-func Base58CheckDecode(data string) ([]byte, byte, error) {
+func Base58CheckDecode(data string, opts ...Option) ([]byte, byte, error) {
+	return BitcoinEncoding.CheckDecode(data, opts...)
+}
+
+// CheckDecode is Base58CheckDecode in the alphabet of enc
+func (enc *Encoding) CheckDecode(data string, opts ...Option) ([]byte, byte, error) {
 	// Step 1: Decode the Base58 string to get the raw bytes
-	// Example: "1E2riae4C" → [0x00, 0x1A, 0x2B, 0x12, 0x34, 0x56, 0x78]
-	decoded, err := Decode(data)
+	// Example: "1E2riae4C" → [0x00, 0x1A, 0x2B, 0x95, 0x8B, 0xF0, 0x05]
+	decoded, err := enc.Decode(data, opts...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("invalid Base58 string: %w", err)
 	}
@@ -168,7 +339,7 @@ func Base58CheckDecode(data string) ([]byte, byte, error) {
 	// Step 2: Validate minimum length (version + payload + checksum)
 	// Minimum: 1 byte version + 0 bytes payload + 4 bytes checksum = 5 bytes
 	if len(decoded) < 5 {
-		return nil, 0, fmt.Errorf("Base58Check string too short: expected at least 5 bytes, got %d", len(decoded))
+		return nil, 0, fmt.Errorf("%w: expected at least 5 bytes, got %d", ErrTooShort, len(decoded))
 	}
 
 	// Step 3: Extract version, payload, and checksum
@@ -178,15 +349,10 @@ func Base58CheckDecode(data string) ([]byte, byte, error) {
 	checksum := decoded[len(decoded)-4:]   // Last 4 bytes are checksum
 
 	// Step 4: Recalculate checksum and validate
-	// Example: payload [0x1A, 0x2B] → double SHA256 → [0x12, 0x34, 0x56, 0x78]
-	// Compare with stored checksum [0x12, 0x34, 0x56, 0x78]
-	dataToHash := append([]byte{version}, payload...)
-	hash256 := sha256.Sum256(dataToHash)
-	hash256d := sha256.Sum256(hash256[:])
-	expectedChecksum := hash256d[:4]
-
-	if !bytes.Equal(checksum, expectedChecksum) {
-		return nil, 0, fmt.Errorf("checksum validation failed")
+	// Example: [0x00, 0x1A, 0x2B] → Checksum4 → [0x95, 0x8B, 0xF0, 0x05]
+	// Compare with stored checksum [0x95, 0x8B, 0xF0, 0x05]
+	if expected := hash.Checksum4(decoded[:len(decoded)-4]); [4]byte(checksum) != expected {
+		return nil, 0, &ChecksumError{Expected: expected, Actual: [4]byte(checksum)}
 	}
 
 	return payload, version, nil
@@ -2,8 +2,11 @@ package base58
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/bigpool"
 )
 
 func TestEncode(t *testing.T) {
@@ -95,7 +98,7 @@ func TestBase58CheckEncode(t *testing.T) {
 
 			// Check that it's valid Base58 (only contains alphabet characters)
 			for _, char := range result {
-				if !strings.ContainsRune(alphabet, char) {
+				if !strings.ContainsRune(BitcoinAlphabet, char) {
 					t.Errorf("Invalid Base58 character: %c", char)
 				}
 			}
@@ -218,3 +221,125 @@ func TestBase58CheckEncodeDecodeRoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestWithPool(t *testing.T) {
+	pool := bigpool.New()
+	inputs := [][]byte{
+		{},
+		{0x00, 0x00, 0x1A, 0x2B},
+		{0x00, 0x60, 0x23, 0xBD, 0x3F, 0x2B, 0x3B, 0xE1, 0x3C, 0x4F, 0x5A, 0x49, 0xFD, 0x7E, 0x08, 0x10, 0xA8, 0xE4, 0x3D, 0x81, 0x26},
+	}
+
+	// Pooled and default results match, and reused temporaries do not leak between calls
+	for round := 0; round < 3; round++ {
+		for _, input := range inputs {
+			encoded := Encode(input, WithPool(pool))
+			if expected := Encode(input); encoded != expected {
+				t.Errorf("Encode(WithPool) = %s, expected %s", encoded, expected)
+			}
+			decoded, err := Decode(encoded, WithPool(pool))
+			if err != nil {
+				t.Fatalf("Decode(WithPool) error = %v", err)
+			}
+			if !bytes.Equal(decoded, input) {
+				t.Errorf("Decode(WithPool) = %x, expected %x", decoded, input)
+			}
+		}
+	}
+
+	// A nil pool falls back to the default pool
+	if got := Encode([]byte{0x1A, 0x2B}, WithPool(nil)); got != "2zW" {
+		t.Errorf("Encode(WithPool(nil)) = %s, expected 2zW", got)
+	}
+}
+
+func TestAppendEncode(t *testing.T) {
+	address := []byte{0x00, 0x60, 0x23, 0xBD, 0x3F, 0x2B, 0x3B, 0xE1, 0x3C, 0x4F, 0x5A, 0x49, 0xFD, 0x7E, 0x08, 0x10, 0xA8, 0xE4, 0x3D, 0x81, 0x26}
+	inputs := [][]byte{{}, {0x00}, {0x00, 0x00, 0x1A, 0x2B}, address}
+
+	// Appending keeps the existing contents and matches Encode
+	for _, input := range inputs {
+		got := AppendEncode([]byte("prefix:"), input)
+		if expected := "prefix:" + Encode(input); string(got) != expected {
+			t.Errorf("AppendEncode() = %s, expected %s", got, expected)
+		}
+		if len(Encode(input)) > MaxEncodedLen(len(input)) {
+			t.Errorf("Expected MaxEncodedLen(%d) to bound %q", len(input), Encode(input))
+		}
+	}
+
+	// A reused buffer with enough capacity is never reallocated
+	buf := make([]byte, 0, MaxEncodedLen(len(address)))
+	buf = AppendEncode(buf[:0], address) // warm up the pool
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = AppendEncode(buf[:0], address)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %.1f", allocs)
+	}
+	if string(buf) != "12LghLnSJct2kpP9M29HeRUP3uS4u" {
+		t.Errorf("AppendEncode() = %s, expected 12LghLnSJct2kpP9M29HeRUP3uS4u", buf)
+	}
+}
+
+func TestDecodeInto(t *testing.T) {
+	for _, s := range []string{"", "1", "112zW", "12LghLnSJct2kpP9M29HeRUP3uS4u"} {
+		expected, _ := Decode(s)
+		dst := bytes.Repeat([]byte{0xff}, len(s)) // len(s) always suffices; stale bytes must be overwritten
+		n, err := DecodeInto(dst, s)
+		if err != nil {
+			t.Fatalf("DecodeInto(%q) error = %v", s, err)
+		}
+		if !bytes.Equal(dst[:n], expected) {
+			t.Errorf("DecodeInto(%q) = %x, expected %x", s, dst[:n], expected)
+		}
+	}
+
+	// Errors leave dst untouched
+	dst := []byte{0xaa, 0xbb}
+	if _, err := DecodeInto(dst, "112zW"); err == nil {
+		t.Error("Expected an error for a short destination")
+	}
+	if _, err := DecodeInto(dst, "0OIl"); err == nil {
+		t.Error("Expected an error for invalid characters")
+	}
+	if !bytes.Equal(dst, []byte{0xaa, 0xbb}) {
+		t.Errorf("Expected dst to be unchanged, got %x", dst)
+	}
+
+	// No allocations once the pool is warm
+	var buf [25]byte
+	_, _ = DecodeInto(buf[:], "12LghLnSJct2kpP9M29HeRUP3uS4u")
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = DecodeInto(buf[:], "12LghLnSJct2kpP9M29HeRUP3uS4u")
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %.1f", allocs)
+	}
+}
+
+func TestWithMaxLength(t *testing.T) {
+	address := "19mLgd5RjgEcyGfnj5ra4gW8FjtvLc2Adr"
+
+	// At the limit decoding works
+	if _, _, err := Base58CheckDecode(address, WithMaxLength(len(address))); err != nil {
+		t.Errorf("Expected an address at the limit to decode, got %v", err)
+	}
+
+	// Beyond it every decoder fails with ErrTooLong
+	limit := WithMaxLength(len(address) - 1)
+	if _, err := Decode(address, limit); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Decode() error = %v, expected ErrTooLong", err)
+	}
+	if _, err := DecodeInto(make([]byte, 64), address, limit); !errors.Is(err, ErrTooLong) {
+		t.Errorf("DecodeInto() error = %v, expected ErrTooLong", err)
+	}
+	if _, _, err := Base58CheckDecode(address, limit); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Base58CheckDecode() error = %v, expected ErrTooLong", err)
+	}
+
+	// Encode ignores the limit
+	if got := Encode([]byte{0x1A, 0x2B}, WithMaxLength(1)); got != "2zW" {
+		t.Errorf("Encode() = %s, expected 2zW", got)
+	}
+}
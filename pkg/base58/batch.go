@@ -0,0 +1,119 @@
+package base58
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/neverDefined/cryptography-playground/pkg/bigpool"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+)
+
+// Batch encoding
+//
+// A block explorer renders every output address of a block, thousands of
+// Base58Check strings at once. Encoding them one by one pays for a fresh
+// result buffer each time and, with several goroutines, contends on
+// bigpool.Default. EncodeAll and CheckEncodeAll reuse one buffer per worker
+// and give each worker its own big.Int pool (unless WithPool is set), so the
+// only allocation per item is the returned string.
+//
+// Work is split into chunks of batchChunkSize items that workers claim in
+// order, so slow items do not leave other workers idle. The result is in the
+// order of the input whatever the number of workers.
+
+// batchChunkSize is the number of items a worker claims at a time
+const batchChunkSize = 64
+
+// WithWorkers makes EncodeAll and CheckEncodeAll use n goroutines
+//
+// The default is 1, encoding on the calling goroutine; n <= 0 means
+// runtime.GOMAXPROCS(0). Other functions ignore it.
+//
+// Example:
+//
+//	addresses := base58.Base58CheckEncodeAll(0x00, hashes, base58.WithWorkers(0))
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		if n <= 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		o.workers = n
+	}
+}
+
+// EncodeAll encodes every byte slice of data, in order
+//
+// Example:
+//
+//	encoded := base58.EncodeAll([][]byte{{0x1A, 0x2B}, {0x00, 0x1A, 0x2B}})
+//	// encoded = ["2zW", "12zW"]
+func EncodeAll(data [][]byte, opts ...Option) []string {
+	return BitcoinEncoding.EncodeAll(data, opts...)
+}
+
+// Base58CheckEncodeAll is Base58CheckEncode of every payload with the same version byte
+func Base58CheckEncodeAll(version byte, payloads [][]byte, opts ...Option) []string {
+	return BitcoinEncoding.CheckEncodeAll(version, payloads, opts...)
+}
+
+// EncodeAll is the package-level EncodeAll in the alphabet of enc
+func (enc *Encoding) EncodeAll(data [][]byte, opts ...Option) []string {
+	return enc.encodeAll(data, newOptions(opts), func(dst, _, item []byte, pool *bigpool.Pool) ([]byte, []byte) {
+		return enc.appendEncode(dst, item, pool), nil
+	})
+}
+
+// CheckEncodeAll is Base58CheckEncodeAll in the alphabet of enc
+func (enc *Encoding) CheckEncodeAll(version byte, payloads [][]byte, opts ...Option) []string {
+	return enc.encodeAll(payloads, newOptions(opts), func(dst, scratch, payload []byte, pool *bigpool.Pool) ([]byte, []byte) {
+		// Same layout as CheckEncode, built in the worker's scratch buffer: [version][payload][checksum]
+		scratch = append(append(scratch[:0], version), payload...)
+		checksum := hash.Checksum4(scratch)
+		scratch = append(scratch, checksum[:]...)
+		return enc.appendEncode(dst, scratch, pool), scratch
+	})
+}
+
+// encodeAll runs encode on every item, with one output and one scratch buffer per worker
+func (enc *Encoding) encodeAll(items [][]byte, o options, encode func(dst, scratch, item []byte, pool *bigpool.Pool) ([]byte, []byte)) []string {
+	out := make([]string, len(items))
+	chunks := (len(items) + batchChunkSize - 1) / batchChunkSize
+	workers := min(max(o.workers, 1), chunks)
+
+	// Step 1: Each worker claims chunks in order until none are left
+	var next atomic.Int64
+	work := func(pool *bigpool.Pool) {
+		var dst, scratch []byte
+		for {
+			c := int(next.Add(1) - 1)
+			if c >= chunks {
+				return
+			}
+			for i := c * batchChunkSize; i < min((c+1)*batchChunkSize, len(items)); i++ {
+				dst, scratch = encode(dst[:0], scratch, items[i], pool)
+				out[i] = string(dst)
+			}
+		}
+	}
+
+	// Step 2: Run on the calling goroutine, or on a pool of workers with their own big.Int pools
+	if workers <= 1 {
+		work(o.pool)
+		return out
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		pool := o.pool
+		if pool == bigpool.Default {
+			pool = bigpool.New()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work(pool)
+		}()
+	}
+	wg.Wait()
+	return out
+}
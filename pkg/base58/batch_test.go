@@ -0,0 +1,61 @@
+package base58
+
+import (
+	"crypto/sha256"
+	"testing"
+)
+
+func TestEncodeAll(t *testing.T) {
+	// Enough items for several chunks, including empty and zero-prefixed ones
+	payloads := make([][]byte, 3*batchChunkSize+5)
+	for i := range payloads {
+		h := sha256.Sum256([]byte{byte(i), byte(i >> 8)})
+		payloads[i] = h[:i%21]
+	}
+	payloads[1] = []byte{0x00, 0x00, 0x1A, 0x2B}
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"sequential", nil},
+		{"four workers", []Option{WithWorkers(4)}},
+		{"GOMAXPROCS workers", []Option{WithWorkers(0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := EncodeAll(payloads, tt.opts...)
+			checked := Base58CheckEncodeAll(0x00, payloads, tt.opts...)
+			if len(encoded) != len(payloads) || len(checked) != len(payloads) {
+				t.Fatalf("Expected %d results, got %d and %d", len(payloads), len(encoded), len(checked))
+			}
+			for i, payload := range payloads {
+				if expected := Encode(payload); encoded[i] != expected {
+					t.Errorf("EncodeAll()[%d] = %s, expected %s", i, encoded[i], expected)
+				}
+				if expected := Base58CheckEncode(0x00, payload); checked[i] != expected {
+					t.Errorf("Base58CheckEncodeAll()[%d] = %s, expected %s", i, checked[i], expected)
+				}
+			}
+		})
+	}
+
+	if got := RippleEncoding.CheckEncodeAll(0x00, [][]byte{make([]byte, 20)}, WithWorkers(2)); got[0] != "rrrrrrrrrrrrrrrrrrrrrhoLvTp" {
+		t.Errorf("CheckEncodeAll() = %v, expected [rrrrrrrrrrrrrrrrrrrrrhoLvTp]", got)
+	}
+	if got := EncodeAll(nil, WithWorkers(4)); len(got) != 0 {
+		t.Errorf("Expected no results for no input, got %v", got)
+	}
+}
+
+func BenchmarkBase58CheckEncodeAll(b *testing.B) {
+	payloads := make([][]byte, 1000)
+	for i := range payloads {
+		h := sha256.Sum256([]byte{byte(i), byte(i >> 8)})
+		payloads[i] = h[:20]
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		Base58CheckEncodeAll(0x00, payloads, WithWorkers(0))
+	}
+}
@@ -0,0 +1,168 @@
+package base58
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Alphabets
+//
+// Base58 is not one encoding but a family: the digits 0-57 can be written
+// with any 58 characters. Bitcoin drops 0, O, I and l to avoid look-alikes;
+// Ripple shuffles the same characters so XRP addresses start with 'r';
+// Flickr swaps the case order for its short URLs. The arithmetic is the same,
+// so an Encoding only carries the alphabet, like encoding/base32.Encoding.
+//
+// The digit 0 is always alphabet[0], which is also the character that stands
+// for each leading 0x00 byte ('1' in Bitcoin, 'r' in Ripple).
+//
+// Nothing in that arithmetic depends on 58 either. NewBaseXEncoding accepts
+// an alphabet of any length from 2 to 128, and its length is the base: the
+// same code then reads a number in base 36 or base 62, with the same
+// leading-zero rule. That is the difference from encoding/base32 and
+// encoding/base64, which cut the bits into fixed 5- or 6-bit groups: base 58
+// is not a power of two, so Base58 has to treat the whole input as one big
+// number, and each output character depends on every input byte.
+
+const (
+	// BitcoinAlphabet is used by Bitcoin addresses, WIF keys and extended keys
+	BitcoinAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	// RippleAlphabet is used by XRP Ledger addresses and seeds
+	RippleAlphabet = "rpshnaf39wBUDNEGHJKLM4PQRST7VWXYZ2bcdeCg65jkm8oFqi1tuvAxyz"
+	// FlickrAlphabet is used by Flickr short URLs
+	FlickrAlphabet = "123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+
+	// Base32Alphabet is Crockford's Base32, designed for writing numbers (no I, L, O or U)
+	Base32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// Base36Alphabet is the digits and lowercase letters, as used by strconv.FormatInt(x, 36)
+	Base36Alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+	// Base62Alphabet is the digits, uppercase and lowercase letters, as used by URL shorteners
+	Base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// Encoding is a positional encoding defined by its alphabet: Base58, or any
+// other base made with NewBaseXEncoding
+type Encoding struct {
+	alphabet     string
+	base         int64     // len(alphabet)
+	charsPerByte float64   // log(256) / log(base), for MaxEncodedLen
+	decodeMap    [256]byte // Digit value of each byte, or invalidDigit
+}
+
+// invalidDigit marks bytes that are not in the alphabet
+const invalidDigit = 0xFF
+
+var (
+	// BitcoinEncoding is the encoding used by the package-level functions
+	BitcoinEncoding = NewEncoding(BitcoinAlphabet)
+	// RippleEncoding decodes and encodes XRP Ledger addresses
+	RippleEncoding = NewEncoding(RippleAlphabet)
+	// FlickrEncoding is the alphabet of Flickr short URLs
+	FlickrEncoding = NewEncoding(FlickrAlphabet)
+
+	// Base32Encoding writes numbers in Crockford's Base32. It is positional, so it
+	// differs from encoding/base32 (RFC 4648) which encodes 5-bit groups.
+	Base32Encoding = NewBaseXEncoding(Base32Alphabet)
+	// Base36Encoding writes numbers in base 36
+	Base36Encoding = NewBaseXEncoding(Base36Alphabet)
+	// Base62Encoding writes numbers in base 62
+	Base62Encoding = NewBaseXEncoding(Base62Alphabet)
+)
+
+// encodings is the registry behind LookupEncoding
+var encodings = map[string]*Encoding{
+	"bitcoin": BitcoinEncoding,
+	"ripple":  RippleEncoding,
+	"flickr":  FlickrEncoding,
+}
+
+// NewEncoding returns an Encoding for the given alphabet
+//
+// The alphabet must be 58 distinct ASCII characters; NewEncoding panics
+// otherwise, like encoding/base32.NewEncoding.
+//
+// Example:
+//
+//	enc := base58.NewEncoding(base58.RippleAlphabet)
+//	payload, version, err := enc.CheckDecode("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+func NewEncoding(alphabet string) *Encoding {
+	if len(alphabet) != 58 {
+		panic(fmt.Sprintf("base58: alphabet must be 58 characters, got %d", len(alphabet)))
+	}
+	return NewBaseXEncoding(alphabet)
+}
+
+// NewBaseXEncoding returns an Encoding whose base is the length of alphabet
+//
+// The alphabet must be 2 to 128 distinct ASCII characters; NewBaseXEncoding
+// panics otherwise. Every method of Encoding works in that base, including
+// CheckEncode and CheckDecode.
+//
+// Example:
+//
+//	hex := base58.NewBaseXEncoding("0123456789abcdef")
+//	s := hex.Encode([]byte{0x00, 0x1A, 0x2B}) // "01a2b": one '0' for the zero byte, then 0x1a2b
+func NewBaseXEncoding(alphabet string) *Encoding {
+	if len(alphabet) < 2 || len(alphabet) > 128 {
+		panic(fmt.Sprintf("base58: alphabet must be 2 to 128 characters, got %d", len(alphabet)))
+	}
+	enc := &Encoding{
+		alphabet:     alphabet,
+		base:         int64(len(alphabet)),
+		charsPerByte: math.Log(256) / math.Log(float64(len(alphabet))),
+	}
+	for i := range enc.decodeMap {
+		enc.decodeMap[i] = invalidDigit
+	}
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if c >= 0x80 {
+			panic(fmt.Sprintf("base58: alphabet contains non-ASCII byte %#x", c))
+		}
+		if enc.decodeMap[c] != invalidDigit {
+			panic(fmt.Sprintf("base58: alphabet contains %q twice", c))
+		}
+		enc.decodeMap[c] = byte(i)
+	}
+	return enc
+}
+
+// LookupEncoding returns a registered encoding by name: "bitcoin", "ripple" or "flickr"
+func LookupEncoding(name string) (*Encoding, bool) {
+	enc, ok := encodings[name]
+	return enc, ok
+}
+
+// EncodingNames lists the names accepted by LookupEncoding, sorted
+func EncodingNames() []string {
+	names := make([]string, 0, len(encodings))
+	for name := range encodings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Alphabet returns the characters of enc, digit 0 first
+func (enc *Encoding) Alphabet() string {
+	return enc.alphabet
+}
+
+// Base returns the number of characters in the alphabet
+func (enc *Encoding) Base() int {
+	return int(enc.base)
+}
+
+// MaxEncodedLen returns the largest length of the encoding of n bytes in the base of enc
+func (enc *Encoding) MaxEncodedLen(n int) int {
+	return int(float64(n)*enc.charsPerByte) + 1
+}
+
+// index returns the digit value of char, or -1 if it is not in the alphabet
+func (enc *Encoding) index(char rune) int {
+	if char < 0 || char >= 0x80 || enc.decodeMap[char] == invalidDigit {
+		return -1
+	}
+	return int(enc.decodeMap[char])
+}
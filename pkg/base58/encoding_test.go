@@ -0,0 +1,154 @@
+package base58
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// TestRippleEncoding decodes XRP Ledger addresses: Base58Check with version 0 in the Ripple alphabet
+func TestRippleEncoding(t *testing.T) {
+	tests := []struct {
+		address   string
+		accountID string
+	}{
+		{"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "b5f762798a53d543a014caf8b297cff8f2f937e8"}, // Genesis account
+		{"rrrrrrrrrrrrrrrrrrrrrhoLvTp", "0000000000000000000000000000000000000000"},        // ACCOUNT_ZERO
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			payload, version, err := RippleEncoding.CheckDecode(tt.address)
+			if err != nil {
+				t.Fatalf("CheckDecode() error = %v", err)
+			}
+			if version != 0 || hex.EncodeToString(payload) != tt.accountID {
+				t.Errorf("CheckDecode() = %x (version %d), expected %s (version 0)", payload, version, tt.accountID)
+			}
+			if got := RippleEncoding.CheckEncode(version, payload); got != tt.address {
+				t.Errorf("CheckEncode() = %s, expected %s", got, tt.address)
+			}
+		})
+	}
+
+	// A Ripple address is not valid Bitcoin Base58Check
+	if _, _, err := Base58CheckDecode("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"); err == nil {
+		t.Error("Expected the Bitcoin alphabet to reject a Ripple address")
+	}
+}
+
+func TestEncodingAlphabets(t *testing.T) {
+	data := []byte{0x00, 0x00, 0x1A, 0x2B}
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"bitcoin", "112zW"},
+		{"flickr", "112Zv"},
+		{"ripple", "rrpzW"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, ok := LookupEncoding(tt.name)
+			if !ok {
+				t.Fatalf("LookupEncoding(%q) not found", tt.name)
+			}
+			if got := enc.Encode(data); got != tt.expected {
+				t.Errorf("Encode() = %s, expected %s", got, tt.expected)
+			}
+			decoded, err := enc.Decode(tt.expected)
+			if err != nil || !bytes.Equal(decoded, data) {
+				t.Errorf("Decode() = %x, %v, expected %x", decoded, err, data)
+			}
+		})
+	}
+
+	if _, ok := LookupEncoding("base64"); ok {
+		t.Error("Expected an unknown name not to be found")
+	}
+	if names := EncodingNames(); !slices.Equal(names, []string{"bitcoin", "flickr", "ripple"}) {
+		t.Errorf("EncodingNames() = %v", names)
+	}
+	if BitcoinEncoding.Encode(data) != Encode(data) {
+		t.Error("Expected the package-level functions to use the Bitcoin alphabet")
+	}
+}
+
+func TestNewEncodingPanics(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet string
+	}{
+		{"too short", BitcoinAlphabet[1:]},
+		{"duplicate", "1" + BitcoinAlphabet[:57]},
+		{"non-ASCII", "é" + BitcoinAlphabet[2:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected NewEncoding to panic")
+				}
+			}()
+			NewEncoding(tt.alphabet)
+		})
+	}
+}
+
+// TestBaseXEncoding checks other bases against math/big, which writes numbers the same way
+func TestBaseXEncoding(t *testing.T) {
+	inputs := [][]byte{{0x01}, {0x3D}, {0x3E}, {0xFF, 0xFF}, []byte("Hello World!")}
+	hexEnc := NewBaseXEncoding("0123456789abcdef")
+	for _, input := range inputs {
+		num := new(big.Int).SetBytes(input)
+		if got := Base36Encoding.Encode(input); got != num.Text(36) {
+			t.Errorf("Base36 Encode(%x) = %s, expected %s", input, got, num.Text(36))
+		}
+		// math/big puts lowercase before uppercase in base 62
+		if got := Base62Encoding.Encode(input); swapCase(got) != num.Text(62) {
+			t.Errorf("Base62 Encode(%x) = %s, expected %s with swapped case", input, got, num.Text(62))
+		}
+		if got := hexEnc.Encode(input); got != num.Text(16) {
+			t.Errorf("Base16 Encode(%x) = %s, expected %s", input, got, num.Text(16))
+		}
+
+		for _, enc := range []*Encoding{Base32Encoding, Base36Encoding, Base62Encoding, BitcoinEncoding} {
+			encoded := enc.Encode(input)
+			if len(encoded) > enc.MaxEncodedLen(len(input)) {
+				t.Errorf("Expected MaxEncodedLen(%d) to bound %q in base %d", len(input), encoded, enc.Base())
+			}
+			decoded, err := enc.Decode(encoded)
+			if err != nil || !bytes.Equal(decoded, input) {
+				t.Errorf("Base %d round trip of %x = %x, %v", enc.Base(), input, decoded, err)
+			}
+		}
+	}
+
+	// Leading zero bytes become alphabet[0] in every base
+	if got := Base32Encoding.Encode([]byte{0x00, 0x00, 0x1F}); got != "00Z" {
+		t.Errorf("Base32 Encode() = %s, expected 00Z", got)
+	}
+
+	// A base needs at least two digits
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected NewBaseXEncoding to panic for a one-character alphabet")
+		}
+	}()
+	NewBaseXEncoding("0")
+}
+
+func swapCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsUpper(r):
+			return unicode.ToLower(r)
+		case unicode.IsLower(r):
+			return unicode.ToUpper(r)
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,61 @@
+package base58
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Decode errors
+//
+// Every decoding failure matches one of the sentinels below with errors.Is.
+// Invalid characters and checksum mismatches are also typed errors carrying
+// the details, so a wallet can point at the mistyped character or tell a typo
+// from a string of the wrong kind. Use errors.As:
+//
+//	var charErr *base58.InvalidCharacterError
+//	if errors.As(err, &charErr) {
+//		fmt.Printf("unexpected %q at position %d\n", charErr.Char, charErr.Position)
+//	}
+
+var (
+	// ErrInvalidCharacter is returned for a character outside the alphabet (see InvalidCharacterError)
+	ErrInvalidCharacter = errors.New("invalid character")
+	// ErrChecksumMismatch is returned when a Base58Check checksum does not match (see ChecksumError)
+	ErrChecksumMismatch = errors.New("checksum validation failed")
+	// ErrTooShort is returned for Base58Check data shorter than a version byte and a checksum
+	ErrTooShort = errors.New("Base58Check string too short")
+	// ErrTooLong is returned when a string exceeds the limit set with WithMaxLength
+	ErrTooLong = errors.New("base58 string too long")
+)
+
+// InvalidCharacterError reports the first character that is not in the alphabet
+type InvalidCharacterError struct {
+	Char     rune
+	Position int // Byte offset in the decoded string
+}
+
+// Error describes the character and where it is
+func (e *InvalidCharacterError) Error() string {
+	return fmt.Sprintf("%v %q at position %d", ErrInvalidCharacter, e.Char, e.Position)
+}
+
+// Unwrap makes errors.Is(err, ErrInvalidCharacter) match
+func (e *InvalidCharacterError) Unwrap() error {
+	return ErrInvalidCharacter
+}
+
+// ChecksumError reports a Base58Check checksum that does not match the data
+type ChecksumError struct {
+	Expected [4]byte // Checksum4 of the version and payload
+	Actual   [4]byte // Last four bytes of the decoded string
+}
+
+// Error shows both checksums in hex
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%v: expected %x, got %x", ErrChecksumMismatch, e.Expected, e.Actual)
+}
+
+// Unwrap makes errors.Is(err, ErrChecksumMismatch) match
+func (e *ChecksumError) Unwrap() error {
+	return ErrChecksumMismatch
+}
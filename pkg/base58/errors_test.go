@@ -0,0 +1,72 @@
+package base58
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInvalidCharacterError(t *testing.T) {
+	tests := []struct {
+		input    string
+		char     rune
+		position int
+	}{
+		{"0abc", '0', 0},
+		{"2zWl", 'l', 3},
+		{"1E2riae4C!@#", '!', 9},
+		{"2€O", '€', 1},
+		{"z€0", '€', 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, _, err := Base58CheckDecode(tt.input)
+			var charErr *InvalidCharacterError
+			if !errors.As(err, &charErr) {
+				t.Fatalf("Expected an InvalidCharacterError, got %v", err)
+			}
+			if charErr.Char != tt.char || charErr.Position != tt.position {
+				t.Errorf("Got %q at %d, expected %q at %d", charErr.Char, charErr.Position, tt.char, tt.position)
+			}
+		})
+	}
+}
+
+func TestChecksumError(t *testing.T) {
+	// "1E2riae4C" is [0x00, 0x1A, 0x2B] with checksum 958bf005; corrupt the checksum
+	corrupted := Encode([]byte{0x00, 0x1A, 0x2B, 0x95, 0x8B, 0xF0, 0x06})
+	_, _, err := Base58CheckDecode(corrupted)
+	var sumErr *ChecksumError
+	if !errors.As(err, &sumErr) {
+		t.Fatalf("Expected a ChecksumError, got %v", err)
+	}
+	if sumErr.Expected != [4]byte{0x95, 0x8B, 0xF0, 0x05} || sumErr.Actual != [4]byte{0x95, 0x8B, 0xF0, 0x06} {
+		t.Errorf("Got expected %x, actual %x", sumErr.Expected, sumErr.Actual)
+	}
+	if err.Error() != "checksum validation failed: expected 958bf005, got 958bf006" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected error
+	}{
+		{"invalid character", "1E2riae4C!@#", ErrInvalidCharacter},
+		{"checksum mismatch", "1E2riae4D", ErrChecksumMismatch},
+		{"too short", "1234", ErrTooShort},
+		{"empty", "", ErrTooShort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Base58CheckDecode(tt.input); !errors.Is(err, tt.expected) {
+				t.Errorf("Base58CheckDecode(%q) error = %v, expected %v", tt.input, err, tt.expected)
+			}
+		})
+	}
+
+	if _, err := Decode("0OIl"); !errors.Is(err, ErrInvalidCharacter) {
+		t.Errorf("Decode() error = %v, expected ErrInvalidCharacter", err)
+	}
+}
@@ -0,0 +1,302 @@
+package bech32
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The alphabet used by Bech32 and Bech32m (BIP173/BIP350).
+// Each character encodes 5 bits; the characters "1", "b", "i" and "o" are excluded.
+const charset string = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Variant selects the checksum constant used by the encoding
+type Variant int
+
+const (
+	// Bech32 is the original BIP173 checksum, used by segwit v0 addresses
+	Bech32 Variant = iota
+	// Bech32m is the BIP350 checksum, used by segwit v1+ (taproot) addresses
+	Bech32m
+)
+
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+
+	// maxLength is the maximum length of a Bech32 string (BIP173)
+	maxLength = 90
+	// checksumLength is the number of 5-bit checksum characters
+	checksumLength = 6
+)
+
+// String returns the name of the variant
+func (v Variant) String() string {
+	if v == Bech32m {
+		return "bech32m"
+	}
+	return "bech32"
+}
+
+// polymod computes the BCH checksum over a sequence of 5-bit values
+func polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// hrpExpand expands the human-readable part for checksum computation
+// Format: [high bits of each char][0][low bits of each char]
+func hrpExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// variantConst returns the checksum constant of a variant
+func variantConst(v Variant) uint32 {
+	if v == Bech32m {
+		return bech32mConst
+	}
+	return bech32Const
+}
+
+// createChecksum computes the 6-character checksum for hrp and data
+func createChecksum(hrp string, data []byte, v Variant) []byte {
+	values := append(hrpExpand(hrp), data...)
+	values = append(values, make([]byte, checksumLength)...)
+	mod := polymod(values) ^ variantConst(v)
+	out := make([]byte, checksumLength)
+	for i := 0; i < checksumLength; i++ {
+		out[i] = byte((mod >> uint(5*(5-i))) & 31)
+	}
+	return out
+}
+
+// Encode encodes a human-readable part and 5-bit data values into a Bech32 or Bech32m string
+//
+// Example:
+//
+//	s, err := Encode("a", []byte{}, Bech32)
+//	// Result: "a12uel5l"
+func Encode(hrp string, data []byte, v Variant) (string, error) {
+	// Step 1: Validate the human-readable part
+	if len(hrp) == 0 {
+		return "", errors.New("human-readable part cannot be empty")
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", fmt.Errorf("invalid human-readable part character: %q", hrp[i])
+		}
+	}
+	hrp = strings.ToLower(hrp)
+
+	// Step 2: Validate the data values (each must fit in 5 bits)
+	for _, d := range data {
+		if d > 31 {
+			return "", fmt.Errorf("invalid data value: %d", d)
+		}
+	}
+	if len(hrp)+1+len(data)+checksumLength > maxLength {
+		return "", fmt.Errorf("encoded string exceeds %d characters", maxLength)
+	}
+
+	// Step 3: Append the checksum and map every value to the alphabet
+	// Format: [hrp]["1"][data][checksum]
+	combined := append(append([]byte{}, data...), createChecksum(hrp, data, v)...)
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, c := range combined {
+		sb.WriteByte(charset[c])
+	}
+	return sb.String(), nil
+}
+
+// Decode decodes a Bech32 or Bech32m string into its human-readable part and 5-bit data values
+//
+// The checksum variant is detected and returned. Mixed-case strings are rejected.
+//
+// Example:
+//
+//	hrp, data, variant, err := Decode("A12UEL5L")
+//	// Result: hrp = "a", data = [], variant = Bech32
+func Decode(s string) (string, []byte, Variant, error) {
+	// Step 1: Validate length and case
+	if len(s) > maxLength {
+		return "", nil, 0, fmt.Errorf("string exceeds %d characters", maxLength)
+	}
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, 0, errors.New("mixed case string")
+	}
+	s = strings.ToLower(s)
+
+	// Step 2: Split at the last '1' separator
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+checksumLength+1 > len(s) {
+		return "", nil, 0, errors.New("invalid separator position")
+	}
+	hrp := s[:pos]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, 0, fmt.Errorf("invalid human-readable part character: %q", hrp[i])
+		}
+	}
+
+	// Step 3: Map the data characters back to 5-bit values
+	data := make([]byte, 0, len(s)-pos-1)
+	for i := pos + 1; i < len(s); i++ {
+		d := strings.IndexByte(charset, s[i])
+		if d == -1 {
+			return "", nil, 0, fmt.Errorf("invalid character: %c", s[i])
+		}
+		data = append(data, byte(d))
+	}
+
+	// Step 4: Verify the checksum and detect the variant
+	var variant Variant
+	switch polymod(append(hrpExpand(hrp), data...)) {
+	case bech32Const:
+		variant = Bech32
+	case bech32mConst:
+		variant = Bech32m
+	default:
+		return "", nil, 0, errors.New("checksum validation failed")
+	}
+
+	return hrp, data[:len(data)-checksumLength], variant, nil
+}
+
+// ConvertBits regroups a byte slice from one bit width to another
+//
+// This is used to convert 8-bit bytes into 5-bit Bech32 values and back.
+// When pad is false, leftover bits must be zero and fewer than fromBits.
+//
+// Example:
+//
+//	fiveBit, _ := ConvertBits([]byte{0xff}, 8, 5, true)
+//	// Result: [31, 28]
+func ConvertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc := uint32(0)
+	bits := uint(0)
+	maxv := uint32(1)<<toBits - 1
+	out := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, value := range data {
+		if uint32(value)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid data value: %d", value)
+		}
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return out, nil
+}
+
+// SegWitAddressEncode creates a segwit address from a witness version and program
+//
+// Version 0 uses Bech32 (BIP173); versions 1 to 16 use Bech32m (BIP350).
+//
+// Verified examples from tests:
+// - hrp "bc", version 0, program 751e76e8... → "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+// - hrp "bc", version 1, program 79be667e... → "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"
+func SegWitAddressEncode(hrp string, version byte, program []byte) (string, error) {
+	// Step 1: Validate the witness version and program length
+	if err := validateWitnessProgram(version, program); err != nil {
+		return "", err
+	}
+
+	// Step 2: Convert the program to 5-bit values and prepend the version
+	conv, err := ConvertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	data := append([]byte{version}, conv...)
+
+	// Step 3: Choose the checksum variant from the version
+	variant := Bech32
+	if version > 0 {
+		variant = Bech32m
+	}
+	return Encode(hrp, data, variant)
+}
+
+// SegWitAddressDecode decodes a segwit address and returns its witness version and program
+//
+// The human-readable part must match hrp (e.g. "bc" for mainnet, "tb" for testnet).
+//
+// Example:
+//
+//	version, program, err := SegWitAddressDecode("bc", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4")
+//	// Result: version = 0, program = 751e76e8199196d454941c45d1b3a323f1433bd6
+func SegWitAddressDecode(hrp, addr string) (byte, []byte, error) {
+	// Step 1: Decode the Bech32 string and check the network prefix
+	gotHRP, data, variant, err := Decode(addr)
+	if err != nil {
+		return 0, nil, err
+	}
+	if gotHRP != strings.ToLower(hrp) {
+		return 0, nil, fmt.Errorf("unexpected human-readable part: expected %q, got %q", hrp, gotHRP)
+	}
+	if len(data) == 0 {
+		return 0, nil, errors.New("missing witness version")
+	}
+
+	// Step 2: Extract the version and convert the program back to bytes
+	version := data[0]
+	program, err := ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := validateWitnessProgram(version, program); err != nil {
+		return 0, nil, err
+	}
+
+	// Step 3: Enforce the checksum variant required by the version (BIP350)
+	if version == 0 && variant != Bech32 {
+		return 0, nil, errors.New("witness version 0 must use bech32")
+	}
+	if version != 0 && variant != Bech32m {
+		return 0, nil, errors.New("witness version 1+ must use bech32m")
+	}
+
+	return version, program, nil
+}
+
+// validateWitnessProgram checks the version and program length rules of BIP141
+func validateWitnessProgram(version byte, program []byte) error {
+	if version > 16 {
+		return fmt.Errorf("invalid witness version: %d", version)
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return fmt.Errorf("invalid witness program length: %d", len(program))
+	}
+	if version == 0 && len(program) != 20 && len(program) != 32 {
+		return fmt.Errorf("invalid witness v0 program length: %d", len(program))
+	}
+	return nil
+}
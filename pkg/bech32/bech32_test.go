@@ -0,0 +1,163 @@
+package bech32
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestDecodeValidChecksums(t *testing.T) {
+	// Valid strings from BIP173 and BIP350
+	tests := []struct {
+		input   string
+		variant Variant
+	}{
+		{"A12UEL5L", Bech32},
+		{"a12uel5l", Bech32},
+		{"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", Bech32},
+		{"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", Bech32},
+		{"?1ezyfcl", Bech32},
+		{"A1LQFN3A", Bech32m},
+		{"a1lqfn3a", Bech32m},
+		{"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", Bech32m},
+		{"split1checkupstagehandshakeupstreamerranterredcaperredlc445v", Bech32m},
+		{"?1v759aa", Bech32m},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			hrp, data, variant, err := Decode(tt.input)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if variant != tt.variant {
+				t.Errorf("Decode() variant = %s, expected %s", variant, tt.variant)
+			}
+
+			// Round trip must reproduce the lowercase input
+			encoded, err := Encode(hrp, data, variant)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if encoded != strings.ToLower(tt.input) {
+				t.Errorf("Encode() = %s, expected %s", encoded, strings.ToLower(tt.input))
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"no separator", "pzry9x0s0muk"},
+		{"empty hrp", "1pzry9x0s0muk"},
+		{"invalid data character", "x1b4n0q5v"},
+		{"checksum too short", "li1dgmt3"},
+		{"mixed case", "A1LqfN3A"},
+		{"bad checksum", "a12uel5m"},
+		{"too long", "an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := Decode(tt.input); err == nil {
+				t.Errorf("Decode(%q) expected error", tt.input)
+			}
+		})
+	}
+}
+
+func TestSegWitAddress(t *testing.T) {
+	// Valid addresses from BIP173 and BIP350
+	tests := []struct {
+		hrp          string
+		addr         string
+		scriptPubKey string
+	}{
+		{"bc", "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"tb", "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		{"bc", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+		{"tb", "tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c", "5120000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			version, program, err := SegWitAddressDecode(tt.hrp, tt.addr)
+			if err != nil {
+				t.Fatalf("SegWitAddressDecode() error = %v", err)
+			}
+
+			// scriptPubKey: [OP_n][program length][program]
+			opVersion := version
+			if version > 0 {
+				opVersion = version + 0x50
+			}
+			script := append([]byte{opVersion, byte(len(program))}, program...)
+			if hex.EncodeToString(script) != tt.scriptPubKey {
+				t.Errorf("scriptPubKey = %x, expected %s", script, tt.scriptPubKey)
+			}
+
+			encoded, err := SegWitAddressEncode(tt.hrp, version, program)
+			if err != nil {
+				t.Fatalf("SegWitAddressEncode() error = %v", err)
+			}
+			if encoded != strings.ToLower(tt.addr) {
+				t.Errorf("SegWitAddressEncode() = %s, expected %s", encoded, strings.ToLower(tt.addr))
+			}
+		})
+	}
+}
+
+func TestSegWitAddressInvalid(t *testing.T) {
+	// Invalid addresses from BIP350
+	tests := []struct {
+		name string
+		hrp  string
+		addr string
+	}{
+		{"wrong hrp", "bc", "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7"},
+		{"v1 with bech32 checksum", "bc", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd"},
+		{"v0 with bech32m checksum", "bc", "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KEMEYZU"},
+		{"program too short", "bc", "bc1pw5dgrnzv"},
+		{"invalid v0 program length", "bc", "BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := SegWitAddressDecode(tt.hrp, tt.addr); err == nil {
+				t.Errorf("SegWitAddressDecode(%q) expected error", tt.addr)
+			}
+		})
+	}
+
+	if _, err := SegWitAddressEncode("bc", 0, make([]byte, 21)); err == nil {
+		t.Error("Expected error for invalid v0 program length")
+	}
+	if _, err := SegWitAddressEncode("bc", 17, make([]byte, 32)); err == nil {
+		t.Error("Expected error for invalid witness version")
+	}
+}
+
+func TestConvertBits(t *testing.T) {
+	fiveBit, err := ConvertBits([]byte{0xff}, 8, 5, true)
+	if err != nil {
+		t.Fatalf("ConvertBits() error = %v", err)
+	}
+	if len(fiveBit) != 2 || fiveBit[0] != 31 || fiveBit[1] != 28 {
+		t.Errorf("ConvertBits() = %v, expected [31 28]", fiveBit)
+	}
+
+	back, err := ConvertBits(fiveBit, 5, 8, false)
+	if err != nil {
+		t.Fatalf("ConvertBits() error = %v", err)
+	}
+	if len(back) != 1 || back[0] != 0xff {
+		t.Errorf("ConvertBits() = %v, expected [255]", back)
+	}
+
+	if _, err := ConvertBits([]byte{32}, 5, 8, false); err == nil {
+		t.Error("Expected error for value exceeding source width")
+	}
+}
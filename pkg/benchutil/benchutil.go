@@ -0,0 +1,178 @@
+package benchutil
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/base58"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// Benchmark helpers and allocation budgets for the hashing/encoding hot paths
+//
+// The B-prefixed helpers are ordinary benchmark bodies that downstream
+// projects can call from their own Benchmark functions:
+//
+//	func BenchmarkSHA256D(b *testing.B) { benchutil.BSHA256D(b, 80) }
+//
+// AssertAllocs and AuditHotPaths turn allocation counts into test failures,
+// so a change that makes SHA256D allocate is caught by CI, not by a profiler.
+
+// allocRuns is the number of runs testing.AllocsPerRun averages over
+const allocRuns = 100
+
+// AssertAllocs fails the test if f allocates more than budget times per call
+//
+// Example:
+//
+//	benchutil.AssertAllocs(t, "SHA256D", 0, func() { hash.SHA256D(data) })
+func AssertAllocs(t testing.TB, name string, budget float64, f func()) {
+	t.Helper()
+	if allocs := testing.AllocsPerRun(allocRuns, f); allocs > budget {
+		t.Errorf("%s: %.1f allocs/op, budget is %.1f", name, allocs, budget)
+	}
+}
+
+// HotPath is a function with an allocation budget
+type HotPath struct {
+	Name   string
+	Budget float64 // Maximum allocations per call
+	Run    func()
+}
+
+// HotPaths returns the audited hot paths with their allocation budgets
+//
+// Budgets are upper bounds measured on the current implementation; lowering a
+// budget after an optimization locks the improvement in.
+func HotPaths() []HotPath {
+	header := make([]byte, 80)
+	payload := make([]byte, 25)
+	for i := range payload {
+		payload[i] = byte(i + 1)
+	}
+	encoded := base58.Encode(payload)
+	msg, pub, sig := verifyInputs()
+
+	return []HotPath{
+		{Name: "hash.SHA256", Budget: 0, Run: func() { hash.SHA256(header) }},
+		{Name: "hash.SHA256D", Budget: 0, Run: func() { hash.SHA256D(header) }},
+		{Name: "hash.Hash160", Budget: 1, Run: func() { hash.Hash160(header[:33]) }},
+		{Name: "base58.Encode", Budget: 3, Run: func() { base58.Encode(payload) }},
+		{Name: "base58.Decode", Budget: 2, Run: func() { _, _ = base58.Decode(encoded) }},
+		{Name: "schnorr.VerifyBIP340", Budget: 19, Run: func() { schnorr.VerifyBIP340(msg, pub, sig) }},
+	}
+}
+
+// AuditHotPaths checks every hot path against its allocation budget
+//
+// Example:
+//
+//	func TestAllocations(t *testing.T) { benchutil.AuditHotPaths(t) }
+func AuditHotPaths(t *testing.T) {
+	t.Helper()
+	for _, hp := range HotPaths() {
+		t.Run(hp.Name, func(t *testing.T) {
+			AssertAllocs(t, hp.Name, hp.Budget, hp.Run)
+		})
+	}
+}
+
+// verifyInputs creates a fixed key, message and signature for verification benchmarks
+func verifyInputs() ([]byte, *btcec.PublicKey, [64]byte) {
+	priv, pub := btcec.PrivKeyFromBytes([]byte{0x01})
+	msg := []byte("benchmark message")
+	sig, err := schnorr.SignBIP340(msg, priv)
+	if err != nil {
+		panic(fmt.Sprintf("benchutil: failed to sign: %v", err))
+	}
+	return msg, pub, sig
+}
+
+// BSHA256 benchmarks hash.SHA256 over size-byte inputs
+func BSHA256(b *testing.B, size int) {
+	data := make([]byte, size)
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hash.SHA256(data)
+	}
+}
+
+// BSHA256D benchmarks hash.SHA256D over size-byte inputs (80 = block header)
+func BSHA256D(b *testing.B, size int) {
+	data := make([]byte, size)
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hash.SHA256D(data)
+	}
+}
+
+// BHash160 benchmarks hash.Hash160 over a 33-byte compressed public key
+func BHash160(b *testing.B) {
+	data := make([]byte, 33)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hash.Hash160(data)
+	}
+}
+
+// BBase58Encode benchmarks base58.Encode over size-byte inputs (25 = address payload)
+func BBase58Encode(b *testing.B, size int) {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i + 1)
+	}
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		base58.Encode(data)
+	}
+}
+
+// BBase58Decode benchmarks base58.Decode of an encoded size-byte input
+func BBase58Decode(b *testing.B, size int) {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i + 1)
+	}
+	encoded := base58.Encode(data)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := base58.Decode(encoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BSignBIP340 benchmarks schnorr.SignBIP340
+func BSignBIP340(b *testing.B) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{0x01})
+	msg := []byte("benchmark message")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := schnorr.SignBIP340(msg, priv); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BVerifyBIP340 benchmarks schnorr.VerifyBIP340
+func BVerifyBIP340(b *testing.B) {
+	msg, pub, sig := verifyInputs()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !schnorr.VerifyBIP340(msg, pub, sig) {
+			b.Fatal("signature did not verify")
+		}
+	}
+}
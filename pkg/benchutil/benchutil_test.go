@@ -0,0 +1,37 @@
+package benchutil
+
+import "testing"
+
+func TestAuditHotPaths(t *testing.T) {
+	AuditHotPaths(t)
+}
+
+// recordingTB captures failures instead of failing the enclosing test
+type recordingTB struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingTB) Helper()                           {}
+func (r *recordingTB) Errorf(format string, args ...any) { r.failed = true }
+
+var sink []byte
+
+func TestAssertAllocs(t *testing.T) {
+	// A function that allocates must fail a zero budget
+	inner := &recordingTB{TB: t}
+	AssertAllocs(inner, "allocating", 0, func() { sink = make([]byte, 64) })
+	if !inner.failed {
+		t.Error("Expected AssertAllocs to fail an allocating function with a zero budget")
+	}
+
+	AssertAllocs(t, "non-allocating", 0, func() {})
+}
+
+func BenchmarkSHA256D(b *testing.B)      { BSHA256D(b, 80) }
+func BenchmarkSHA256(b *testing.B)       { BSHA256(b, 64) }
+func BenchmarkHash160(b *testing.B)      { BHash160(b) }
+func BenchmarkBase58Encode(b *testing.B) { BBase58Encode(b, 25) }
+func BenchmarkBase58Decode(b *testing.B) { BBase58Decode(b, 25) }
+func BenchmarkSignBIP340(b *testing.B)   { BSignBIP340(b) }
+func BenchmarkVerifyBIP340(b *testing.B) { BVerifyBIP340(b) }
@@ -0,0 +1,62 @@
+package bigpool
+
+import (
+	"math/big"
+	"sync"
+)
+
+// A sync.Pool of *big.Int values for allocation-heavy code paths
+//
+// Every big.Int operation that needs a temporary normally allocates it (and
+// its backing word slice) on the heap. Bulk workloads such as encoding
+// thousands of addresses spend much of their time in the garbage collector
+// freeing those temporaries. A Pool hands out previously released values
+// instead, whose word slices already have the right capacity.
+//
+// Values must be released exactly once, and must not be used after release:
+//
+//	x := pool.Get()
+//	defer pool.Release(x)
+
+// Pool is a pool of reusable *big.Int values
+//
+// The zero value is not usable; create pools with New.
+type Pool struct {
+	pool sync.Pool
+}
+
+// Default is the pool used by this module when no pool is given
+var Default = New()
+
+// New creates an empty pool
+func New() *Pool {
+	p := &Pool{}
+	p.pool.New = func() any { return new(big.Int) }
+	return p
+}
+
+// Get returns a big.Int with value zero
+//
+// Example:
+//
+//	x := bigpool.Default.Get()
+//	defer bigpool.Default.Release(x)
+//	x.SetBytes(data)
+func (p *Pool) Get() *big.Int {
+	return p.pool.Get().(*big.Int)
+}
+
+// Release wipes the values and returns them to the pool
+//
+// The backing words are zeroed, so released values never leak scalars or key
+// material to the next user. Nil values are ignored.
+func (p *Pool) Release(xs ...*big.Int) {
+	for _, x := range xs {
+		if x == nil {
+			continue
+		}
+		clear(x.Bits())
+		x.SetInt64(0)
+		p.pool.Put(x)
+	}
+}
@@ -0,0 +1,56 @@
+package bigpool
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestGetRelease(t *testing.T) {
+	pool := New()
+
+	x := pool.Get()
+	if x.Sign() != 0 {
+		t.Errorf("Get() = %s, expected 0", x)
+	}
+
+	secret, _ := new(big.Int).SetString("deadbeefdeadbeefdeadbeefdeadbeef", 16)
+	x.Set(secret)
+	words := x.Bits()
+	pool.Release(x, nil)
+
+	// The backing words are wiped, not just the length
+	for i, w := range words[:cap(words)] {
+		if w != 0 {
+			t.Errorf("Word %d not wiped after Release: %x", i, w)
+		}
+	}
+
+	// Values from the pool always start at zero
+	for i := 0; i < 10; i++ {
+		y := pool.Get()
+		if y.Sign() != 0 {
+			t.Fatalf("Get() = %s after Release, expected 0", y)
+		}
+		y.SetInt64(int64(i + 1))
+		pool.Release(y)
+	}
+}
+
+func TestPoolReducesAllocations(t *testing.T) {
+	pool := New()
+	data := make([]byte, 64)
+	for i := range data {
+		data[i] = 0xff
+	}
+
+	// Warm the pool, then a Get/SetBytes/Release cycle should not allocate
+	pool.Release(pool.Get().SetBytes(data))
+	allocs := testing.AllocsPerRun(100, func() {
+		x := pool.Get()
+		x.SetBytes(data)
+		pool.Release(x)
+	})
+	if allocs > 0 {
+		t.Errorf("Pooled cycle allocates %.1f times, expected 0", allocs)
+	}
+}
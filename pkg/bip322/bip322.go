@@ -0,0 +1,254 @@
+package bip322
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+// BIP322 generic signed messages
+//
+// A message is signed by "spending" a virtual output locked to the address:
+//
+//	to_spend: 1 input  (null outpoint, scriptSig OP_0 <MessageHash(msg)>)
+//	          1 output (0 sat, scriptPubKey of the address)
+//	to_sign:  1 input  (spends to_spend:0, carries the witness)
+//	          1 output (0 sat, OP_RETURN)
+//
+// The signature is the witness of to_sign, so anything a wallet can spend it
+// can sign for. This package implements the "simple" format (base64 of the
+// serialized witness) for P2WPKH and P2TR key path addresses.
+
+// messageTag is the BIP340 tag of the message hash
+const messageTag = "BIP0322-signed-message"
+
+var (
+	// ErrUnsupportedAddress is returned for address types without a simple signature form here
+	ErrUnsupportedAddress = errors.New("only P2WPKH and P2TR addresses are supported")
+	// ErrInvalidSignature is returned when a signature does not prove control of the address
+	ErrInvalidSignature = errors.New("invalid BIP322 signature")
+)
+
+// MessageHash computes the BIP322 message hash: the tagged hash of msg with tag "BIP0322-signed-message"
+//
+// Example:
+//
+//	h := MessageHash([]byte("Hello World"))
+//	// Result: f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a
+func MessageHash(msg []byte) [32]byte {
+	return hash.TaggedSHA256(messageTag, msg)
+}
+
+// ToSpend builds the virtual to_spend transaction for a scriptPubKey and message
+func ToSpend(scriptPubKey, msg []byte) *tx.Transaction {
+	msgHash := MessageHash(msg)
+	return &tx.Transaction{
+		Version: 0,
+		Inputs: []*tx.TxIn{{
+			PreviousOutPoint: tx.OutPoint{Index: 0xffffffff},
+			SignatureScript:  append([]byte{tx.OP_0, 0x20}, msgHash[:]...),
+			Sequence:         0,
+		}},
+		Outputs: []*tx.TxOut{{Value: 0, PkScript: scriptPubKey}},
+	}
+}
+
+// ToSign builds the virtual to_sign transaction spending toSpend, without a witness
+func ToSign(toSpend *tx.Transaction) *tx.Transaction {
+	return &tx.Transaction{
+		Version: 0,
+		Inputs: []*tx.TxIn{{
+			PreviousOutPoint: tx.OutPoint{Hash: toSpend.TxID(), Index: 0},
+			Sequence:         0,
+		}},
+		Outputs: []*tx.TxOut{{Value: 0, PkScript: []byte{tx.OP_RETURN}}},
+	}
+}
+
+// Sign produces a simple BIP322 signature proving control of address
+//
+// P2WPKH addresses are signed with ECDSA (SIGHASH_ALL), P2TR addresses with a
+// key path Schnorr signature (SIGHASH_DEFAULT) for the key tweaked without a
+// script tree, as a single-key wallet would.
+//
+// Example:
+//
+//	sig, err := Sign([]byte("Hello World"), "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l", priv, &chaincfg.MainNetParams)
+//	// Result: base64 witness, e.g. "AkcwRAIg..."
+func Sign(msg []byte, address string, priv *btcec.PrivateKey, params *chaincfg.Params) (string, error) {
+	if priv == nil {
+		return "", errors.New("private key cannot be nil")
+	}
+
+	// Step 1: Build the virtual transactions for the address
+	scriptPubKey, err := tx.AddressScript(address, params)
+	if err != nil {
+		return "", err
+	}
+	toSpend := ToSpend(scriptPubKey, msg)
+	toSign := ToSign(toSpend)
+
+	// Step 2: Produce the witness for the address type
+	var witness [][]byte
+	switch tx.ClassifyScript(scriptPubKey) {
+	case tx.WitnessV0PubKeyHash:
+		pubKey := priv.PubKey().SerializeCompressed()
+		keyHash := hash.Hash160(pubKey)
+		if !bytes.Equal(keyHash[:], scriptPubKey[2:]) {
+			return "", errors.New("private key does not match address")
+		}
+		digest, err := tx.WitnessV0SigHash(toSign, 0, tx.P2PKHScript(keyHash[:]), 0, tx.SigHashAll)
+		if err != nil {
+			return "", err
+		}
+		sig := ecdsa.Sign(priv, digest[:])
+		witness = [][]byte{append(sig.Serialize(), tx.SigHashAll), pubKey}
+
+	case tx.WitnessV1Taproot:
+		tweaked, err := schnorr.TweakPrivKey(priv, nil)
+		if err != nil {
+			return "", err
+		}
+		defer tweaked.Zero()
+		outputKey := btcschnorr.SerializePubKey(tweaked.PubKey())
+		if !bytes.Equal(outputKey, scriptPubKey[2:]) {
+			return "", errors.New("private key does not match address")
+		}
+		digest, err := tx.TaprootSigHash(toSign, 0, toSpend.Outputs, tx.SigHashDefault)
+		if err != nil {
+			return "", err
+		}
+		sig, err := schnorr.SignDigest(digest, tweaked)
+		if err != nil {
+			return "", err
+		}
+		witness = [][]byte{sig[:]}
+
+	default:
+		return "", ErrUnsupportedAddress
+	}
+
+	// Step 3: Simple format = base64 of the serialized witness stack
+	return base64.StdEncoding.EncodeToString(encodeWitness(witness)), nil
+}
+
+// Verify checks a simple BIP322 signature for address and message
+//
+// Returns nil if the signature is valid, ErrInvalidSignature if it is not.
+//
+// Example:
+//
+//	err := Verify([]byte("Hello World"), address, sig, &chaincfg.MainNetParams)
+//	// Result: nil if the signer controls address
+func Verify(msg []byte, address, signature string, params *chaincfg.Params) error {
+	// Step 1: Decode the address and witness
+	scriptPubKey, err := tx.AddressScript(address, params)
+	if err != nil {
+		return err
+	}
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: not base64", ErrInvalidSignature)
+	}
+	witness, err := decodeWitness(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	toSpend := ToSpend(scriptPubKey, msg)
+	toSign := ToSign(toSpend)
+
+	// Step 2: Check the witness as the script would
+	switch tx.ClassifyScript(scriptPubKey) {
+	case tx.WitnessV0PubKeyHash:
+		if len(witness) != 2 || len(witness[0]) == 0 {
+			return fmt.Errorf("%w: P2WPKH witness must be <signature> <pubkey>", ErrInvalidSignature)
+		}
+		keyHash := hash.Hash160(witness[1])
+		if !bytes.Equal(keyHash[:], scriptPubKey[2:]) {
+			return fmt.Errorf("%w: public key does not match address", ErrInvalidSignature)
+		}
+		pubKey, err := btcec.ParsePubKey(witness[1])
+		if err != nil || len(witness[1]) != btcec.PubKeyBytesLenCompressed {
+			return fmt.Errorf("%w: invalid public key", ErrInvalidSignature)
+		}
+		der, hashType := witness[0][:len(witness[0])-1], witness[0][len(witness[0])-1]
+		sig, err := ecdsa.ParseDERSignature(der)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+		digest, err := tx.WitnessV0SigHash(toSign, 0, tx.P2PKHScript(keyHash[:]), 0, hashType)
+		if err != nil {
+			return err
+		}
+		if !sig.Verify(digest[:], pubKey) {
+			return ErrInvalidSignature
+		}
+
+	case tx.WitnessV1Taproot:
+		if len(witness) != 1 || (len(witness[0]) != 64 && len(witness[0]) != 65) {
+			return fmt.Errorf("%w: P2TR key path witness must be a single signature", ErrInvalidSignature)
+		}
+		hashType := byte(tx.SigHashDefault)
+		if len(witness[0]) == 65 {
+			if hashType = witness[0][64]; hashType == tx.SigHashDefault {
+				return fmt.Errorf("%w: explicit SIGHASH_DEFAULT byte", ErrInvalidSignature)
+			}
+		}
+		outputKey, err := btcschnorr.ParsePubKey(scriptPubKey[2:])
+		if err != nil {
+			return fmt.Errorf("%w: invalid output key", ErrInvalidSignature)
+		}
+		digest, err := tx.TaprootSigHash(toSign, 0, toSpend.Outputs, hashType)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+		if !schnorr.VerifyDigest(digest, outputKey, [64]byte(witness[0][:64])) {
+			return ErrInvalidSignature
+		}
+
+	default:
+		return ErrUnsupportedAddress
+	}
+	return nil
+}
+
+// encodeWitness serializes a witness stack as [count][item]...
+func encodeWitness(witness [][]byte) []byte {
+	var buf bytes.Buffer
+	tx.WriteVarInt(&buf, uint64(len(witness)))
+	for _, item := range witness {
+		tx.WriteVarBytes(&buf, item)
+	}
+	return buf.Bytes()
+}
+
+// decodeWitness parses a serialized witness stack, rejecting trailing bytes
+func decodeWitness(raw []byte) ([][]byte, error) {
+	r := bytes.NewReader(raw)
+	count, err := tx.ReadVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if count > uint64(len(raw)) {
+		return nil, errors.New("witness item count exceeds data")
+	}
+	witness := make([][]byte, count)
+	for i := range witness {
+		if witness[i], err = tx.ReadVarBytes(r); err != nil {
+			return nil, err
+		}
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("trailing bytes after witness")
+	}
+	return witness, nil
+}
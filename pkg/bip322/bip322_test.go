@@ -0,0 +1,169 @@
+package bip322
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/bech32"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+// Test vectors from BIP322
+const (
+	vectorP2WPKH = "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l"
+	vectorP2TR   = "bc1ppv609nr0vr25u07u95waq5lucwfm6tde4nydujnu8npg4q75mr5sxq8lt3"
+)
+
+// testKey returns a fixed private key with its P2WPKH and P2TR addresses
+func testKey(t *testing.T) (*btcec.PrivateKey, string, string) {
+	t.Helper()
+	priv, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{0x11}, 32))
+	keyHash := hash.Hash160(priv.PubKey().SerializeCompressed())
+	p2wpkh, err := bech32.SegWitAddressEncode("bc", 0, keyHash[:])
+	if err != nil {
+		t.Fatalf("SegWitAddressEncode failed: %v", err)
+	}
+	outputKey, _, err := schnorr.TweakPubKey(priv.PubKey(), nil)
+	if err != nil {
+		t.Fatalf("TweakPubKey failed: %v", err)
+	}
+	p2tr, err := bech32.SegWitAddressEncode("bc", 1, outputKey[:])
+	if err != nil {
+		t.Fatalf("SegWitAddressEncode failed: %v", err)
+	}
+	return priv, p2wpkh, p2tr
+}
+
+func TestMessageHash(t *testing.T) {
+	tests := []struct {
+		msg      string
+		expected string
+	}{
+		{"", "c90c269c4f8fcbe6880f72a721ddfbf1914268a794cbb21cfafee13770ae19f1"},
+		{"Hello World", "f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a"},
+	}
+	for _, tt := range tests {
+		h := MessageHash([]byte(tt.msg))
+		if got := hex.EncodeToString(h[:]); got != tt.expected {
+			t.Errorf("MessageHash(%q) = %s, expected %s", tt.msg, got, tt.expected)
+		}
+	}
+}
+
+func TestTransactionIDs(t *testing.T) {
+	script, err := tx.AddressScript(vectorP2WPKH, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("AddressScript failed: %v", err)
+	}
+
+	tests := []struct {
+		msg     string
+		toSpend string
+		toSign  string
+	}{
+		{"", "c5680aa69bb8d860bf82d4e9cd3504b55dde018de765a91bb566283c545a99a7", "1e9654e951a5ba44c8604c4de6c67fd78a27e81dcadcfe1edf638ba3aaebaed6"},
+		{"Hello World", "b79d196740ad5217771c1098fc4a4b51e0535c32236c71f1ea4d61a2d603352b", "88737ae86f2077145f93cc4b153ae9a1cb8d56afa511988c149c5c8c9d93bddf"},
+	}
+	for _, tt := range tests {
+		toSpend := ToSpend(script, []byte(tt.msg))
+		if got := toSpend.TxIDHex(); got != tt.toSpend {
+			t.Errorf("to_spend(%q) = %s, expected %s", tt.msg, got, tt.toSpend)
+		}
+		if got := ToSign(toSpend).TxIDHex(); got != tt.toSign {
+			t.Errorf("to_sign(%q) = %s, expected %s", tt.msg, got, tt.toSign)
+		}
+	}
+}
+
+// TestVerifyVectors checks the P2WPKH and P2TR signatures from BIP322
+func TestVerifyVectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     string
+		address string
+		sig     string
+	}{
+		{"p2wpkh empty", "", vectorP2WPKH, "AkcwRAIgM2gBAQqvZX15ZiysmKmQpDrG83avLIT492QBzLnQIxYCIBaTpOaD20qRlEylyxFSeEA2ba9YOixpX8z46TSDtS40ASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI="},
+		{"p2wpkh hello", "Hello World", vectorP2WPKH, "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI="},
+		{"p2tr hello", "Hello World", vectorP2TR, "AUHd69PrJQEv+oKTfZ8l+WROBHuy9HKrbFCJu7U1iK2iiEy1vMU5EfMtjc+VSHM7aU0SDbak5IUZRVno2P5mjSafAQ=="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify([]byte(tt.msg), tt.address, tt.sig, &chaincfg.MainNetParams); err != nil {
+				t.Errorf("Verify failed: %v", err)
+			}
+		})
+	}
+}
+
+// TestSignAndVerify tests signing round trips for both address types
+func TestSignAndVerify(t *testing.T) {
+	priv, p2wpkh, p2tr := testKey(t)
+	for _, address := range []string{p2wpkh, p2tr} {
+		for _, msg := range []string{"", "Hello World"} {
+			sig, err := Sign([]byte(msg), address, priv, &chaincfg.MainNetParams)
+			if err != nil {
+				t.Fatalf("Sign(%s) failed: %v", address, err)
+			}
+			if err := Verify([]byte(msg), address, sig, &chaincfg.MainNetParams); err != nil {
+				t.Errorf("Verify(%s, %q) failed: %v", address, msg, err)
+			}
+		}
+	}
+
+	// ECDSA signatures are deterministic (RFC6979)
+	a, _ := Sign([]byte("x"), p2wpkh, priv, &chaincfg.MainNetParams)
+	b, _ := Sign([]byte("x"), p2wpkh, priv, &chaincfg.MainNetParams)
+	if a != b {
+		t.Error("Expected deterministic P2WPKH signatures")
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	priv, p2wpkh, p2tr := testKey(t)
+	params := &chaincfg.MainNetParams
+	sig, _ := Sign([]byte("Hello World"), p2wpkh, priv, params)
+	trSig, _ := Sign([]byte("Hello World"), p2tr, priv, params)
+
+	tests := []struct {
+		name    string
+		msg     string
+		address string
+		sig     string
+	}{
+		{"wrong message", "Hello World!", p2wpkh, sig},
+		{"wrong address", "Hello World", vectorP2WPKH, sig},
+		{"wrong taproot message", "Hello World!", p2tr, trSig},
+		{"taproot sig on segwit v0", "Hello World", p2wpkh, trSig},
+		{"segwit v0 sig on taproot", "Hello World", p2tr, sig},
+		{"not base64", "Hello World", p2wpkh, "!!"},
+		{"empty witness", "Hello World", p2wpkh, "AA=="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify([]byte(tt.msg), tt.address, tt.sig, params); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+
+	// Legacy addresses have no simple signature
+	if _, err := Sign([]byte("x"), "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", priv, params); !errors.Is(err, ErrUnsupportedAddress) {
+		t.Errorf("Expected ErrUnsupportedAddress, got %v", err)
+	}
+
+	// The key must control the address
+	other, _ := btcec.NewPrivateKey()
+	if _, err := Sign([]byte("x"), vectorP2WPKH, other, params); err == nil {
+		t.Error("Expected error signing with a key that does not match the address")
+	}
+	if _, err := Sign([]byte("x"), vectorP2TR, other, params); err == nil {
+		t.Error("Expected error signing with a key that does not match the address")
+	}
+}
@@ -0,0 +1,499 @@
+package ceremony
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Key ceremony engine
+//
+// A Runbook is an ordered list of steps (generate entropy, derive keys, split
+// shares, verify shares, destroy material, or anything custom). The Engine runs
+// them one at a time:
+//
+//	confirm (operator approves, if the step asks for it) -> run -> check
+//
+// A step only counts as done once its Check passes, so every step ends at a
+// machine-checked checkpoint. Every event is appended to a hash-chained
+// transcript (h_i = SHA256(h_{i-1} || record_i)); publishing the final hash
+// lets anyone holding the transcript confirm it was not edited afterwards.
+// Secrets never enter the transcript.
+//
+// A ceremony can be interrupted and resumed: Checkpoint returns an encrypted
+// snapshot (material, transcript, position) and Resume continues from it.
+
+var (
+	// ErrDeclined is returned when the operator does not confirm a step
+	ErrDeclined = errors.New("operator declined the step")
+	// ErrDone is returned when a step is run after the last one completed
+	ErrDone = errors.New("ceremony already complete")
+	// ErrTranscriptTampered is returned when the transcript hash chain does not verify
+	ErrTranscriptTampered = errors.New("transcript hash chain broken")
+	// ErrInvalidCheckpoint is returned when a checkpoint cannot be decrypted or does not match the runbook
+	ErrInvalidCheckpoint = errors.New("invalid checkpoint")
+)
+
+// Transcript events
+const (
+	EventConfirm    = "confirm"
+	EventRun        = "run"
+	EventCheck      = "check"
+	EventLog        = "log"
+	EventFail       = "fail"
+	EventCheckpoint = "checkpoint"
+	EventResume     = "resume"
+)
+
+// Step is one unit of a runbook
+type Step struct {
+	Name    string
+	Prompt  string // Shown to the operator when Confirm is set
+	Confirm bool   // Require operator confirmation before running
+	Run     func(*Context) error
+	Check   func(*Context) error // Optional checkpoint; must pass before the ceremony moves on
+}
+
+// Runbook is a named, ordered list of steps
+type Runbook struct {
+	Name  string
+	Steps []Step
+}
+
+// Operator confirms steps on behalf of a person
+type Operator interface {
+	// Confirm asks to approve a step and returns the name of whoever approved it
+	Confirm(step, prompt string) (string, error)
+}
+
+// OperatorFunc adapts a function to the Operator interface
+type OperatorFunc func(step, prompt string) (string, error)
+
+// Confirm calls f
+func (f OperatorFunc) Confirm(step, prompt string) (string, error) {
+	return f(step, prompt)
+}
+
+// AutoConfirm approves every step as name (for rehearsals and tests)
+func AutoConfirm(name string) Operator {
+	return OperatorFunc(func(string, string) (string, error) { return name, nil })
+}
+
+// Record is one transcript entry
+type Record struct {
+	Seq    int       `json:"seq"`
+	Step   string    `json:"step"`
+	Event  string    `json:"event"`
+	Detail string    `json:"detail,omitempty"`
+	Time   time.Time `json:"time"`
+	Hash   string    `json:"hash"` // hex SHA256(previous hash || record without Hash)
+}
+
+// item is one piece of ceremony material
+type item struct {
+	value  []byte
+	secret bool
+}
+
+// Context gives steps access to ceremony material and the transcript
+type Context struct {
+	engine   *Engine
+	step     string
+	material map[string]*item
+}
+
+// Put stores material under name, replacing (and wiping) any previous value
+func (c *Context) Put(name string, value []byte, secret bool) {
+	c.Destroy(name)
+	c.material[name] = &item{value: append([]byte(nil), value...), secret: secret}
+}
+
+// Get returns the material stored under name
+func (c *Context) Get(name string) ([]byte, bool) {
+	it, ok := c.material[name]
+	if !ok {
+		return nil, false
+	}
+	return it.value, true
+}
+
+// Destroy wipes and removes material
+func (c *Context) Destroy(name string) {
+	if it, ok := c.material[name]; ok {
+		clear(it.value)
+		delete(c.material, name)
+	}
+}
+
+// Names returns the names of all material, sorted
+func (c *Context) Names() []string {
+	names := make([]string, 0, len(c.material))
+	for name := range c.material {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Rand returns the engine's randomness source
+func (c *Context) Rand() io.Reader {
+	return c.engine.options.rand
+}
+
+// Logf appends a log record to the transcript; never log secrets
+func (c *Context) Logf(format string, args ...any) {
+	c.engine.record(c.step, EventLog, fmt.Sprintf(format, args...))
+}
+
+// engineOptions holds the settings selected with Option
+type engineOptions struct {
+	now  func() time.Time
+	rand io.Reader
+}
+
+// Option configures an Engine
+type Option func(*engineOptions)
+
+// WithClock replaces time.Now for transcript timestamps
+func WithClock(now func() time.Time) Option {
+	return func(o *engineOptions) {
+		o.now = now
+	}
+}
+
+// WithRandReader replaces crypto/rand for the built-in steps (rehearsals only)
+func WithRandReader(r io.Reader) Option {
+	return func(o *engineOptions) {
+		o.rand = r
+	}
+}
+
+// Engine runs a runbook step by step
+type Engine struct {
+	runbook  *Runbook
+	operator Operator
+	options  engineOptions
+	ctx      *Context
+	next     int
+	records  []Record
+	head     [32]byte
+}
+
+// New creates an engine at the first step of runbook
+//
+// Example:
+//
+//	runbook := &ceremony.Runbook{Name: "cold key 2024", Steps: []ceremony.Step{
+//		ceremony.GenerateEntropy("entropy", 32),
+//		ceremony.DeriveKey("entropy", "key"),
+//		ceremony.SplitShares("key", 2, 3),
+//		ceremony.VerifyShares("key", 2, 3),
+//		ceremony.Destroy("entropy", "key"),
+//	}}
+//	engine, _ := ceremony.New(runbook, operator)
+//	err := engine.Run()
+//	fmt.Printf("transcript %x\n", engine.TranscriptHash())
+func New(runbook *Runbook, operator Operator, opts ...Option) (*Engine, error) {
+	if err := validateRunbook(runbook); err != nil {
+		return nil, err
+	}
+	if operator == nil {
+		return nil, errors.New("operator cannot be nil")
+	}
+
+	options := engineOptions{now: time.Now, rand: rand.Reader}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	e := &Engine{runbook: runbook, operator: operator, options: options}
+	e.ctx = &Context{engine: e, material: make(map[string]*item)}
+	return e, nil
+}
+
+// validateRunbook checks that steps are named uniquely and runnable
+func validateRunbook(runbook *Runbook) error {
+	if runbook == nil || runbook.Name == "" {
+		return errors.New("runbook must have a name")
+	}
+	if len(runbook.Steps) == 0 {
+		return errors.New("runbook has no steps")
+	}
+	seen := make(map[string]bool)
+	for i, step := range runbook.Steps {
+		if step.Name == "" || step.Run == nil {
+			return fmt.Errorf("step %d must have a name and a Run function", i)
+		}
+		if seen[step.Name] {
+			return fmt.Errorf("duplicate step name %q", step.Name)
+		}
+		seen[step.Name] = true
+	}
+	return nil
+}
+
+// Done reports whether every step completed
+func (e *Engine) Done() bool {
+	return e.next == len(e.runbook.Steps)
+}
+
+// NextStep returns the name of the step RunStep would run next
+func (e *Engine) NextStep() (string, bool) {
+	if e.Done() {
+		return "", false
+	}
+	return e.runbook.Steps[e.next].Name, true
+}
+
+// RunStep confirms, runs and checks the next step
+//
+// On failure the engine stays at the same step, so it can be retried (or the
+// ceremony checkpointed and resumed later).
+func (e *Engine) RunStep() error {
+	if e.Done() {
+		return ErrDone
+	}
+	step := e.runbook.Steps[e.next]
+	e.ctx.step = step.Name
+
+	// Step 1: Operator confirmation
+	if step.Confirm {
+		who, err := e.operator.Confirm(step.Name, step.Prompt)
+		if err != nil {
+			e.record(step.Name, EventFail, err.Error())
+			return fmt.Errorf("step %q: %w", step.Name, err)
+		}
+		e.record(step.Name, EventConfirm, "confirmed by "+who)
+	}
+
+	// Step 2: Run
+	if err := step.Run(e.ctx); err != nil {
+		e.record(step.Name, EventFail, err.Error())
+		return fmt.Errorf("step %q: %w", step.Name, err)
+	}
+	e.record(step.Name, EventRun, "")
+
+	// Step 3: Checkpoint
+	if step.Check != nil {
+		if err := step.Check(e.ctx); err != nil {
+			e.record(step.Name, EventFail, "check: "+err.Error())
+			return fmt.Errorf("step %q check: %w", step.Name, err)
+		}
+		e.record(step.Name, EventCheck, "passed")
+	}
+	e.next++
+	return nil
+}
+
+// Run runs the remaining steps, stopping at the first failure
+func (e *Engine) Run() error {
+	for !e.Done() {
+		if err := e.RunStep(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Public returns non-secret material, such as a public key or share commitments
+func (e *Engine) Public(name string) ([]byte, bool) {
+	it, ok := e.ctx.material[name]
+	if !ok || it.secret {
+		return nil, false
+	}
+	return append([]byte(nil), it.value...), true
+}
+
+// Transcript returns a copy of the transcript
+func (e *Engine) Transcript() []Record {
+	return append([]Record(nil), e.records...)
+}
+
+// TranscriptHash returns the hash of the last transcript record
+func (e *Engine) TranscriptHash() [32]byte {
+	return e.head
+}
+
+// record appends an event to the hash-chained transcript
+func (e *Engine) record(step, event, detail string) {
+	r := Record{
+		Seq:    len(e.records),
+		Step:   step,
+		Event:  event,
+		Detail: detail,
+		Time:   e.options.now().UTC(),
+	}
+	e.head = chainHash(e.head, r)
+	r.Hash = hex.EncodeToString(e.head[:])
+	e.records = append(e.records, r)
+}
+
+// chainHash computes SHA256(previous || JSON(record without Hash))
+func chainHash(previous [32]byte, r Record) [32]byte {
+	r.Hash = ""
+	encoded, _ := json.Marshal(r)
+	h := sha256.New()
+	h.Write(previous[:])
+	h.Write(encoded)
+	var out [32]byte
+	h.Sum(out[:0])
+	return out
+}
+
+// VerifyTranscript recomputes the hash chain of a transcript and returns its final hash
+//
+// Example:
+//
+//	head, err := ceremony.VerifyTranscript(records)
+//	// compare head with the hash announced at the end of the ceremony
+func VerifyTranscript(records []Record) ([32]byte, error) {
+	var head [32]byte
+	for i, r := range records {
+		if r.Seq != i {
+			return [32]byte{}, fmt.Errorf("%w: record %d has sequence %d", ErrTranscriptTampered, i, r.Seq)
+		}
+		head = chainHash(head, r)
+		if r.Hash != hex.EncodeToString(head[:]) {
+			return [32]byte{}, fmt.Errorf("%w: at record %d", ErrTranscriptTampered, i)
+		}
+	}
+	return head, nil
+}
+
+// checkpointVersion is the first byte of every checkpoint
+const checkpointVersion = 0x01
+
+// checkpointAAD is bound to every checkpoint as additional authenticated data
+const checkpointAAD = "ceremony/checkpoint/v1"
+
+// snapshot is the serialized state of an engine
+type snapshot struct {
+	Runbook  string                  `json:"runbook"`
+	Steps    []string                `json:"steps"`
+	Next     int                     `json:"next"`
+	Records  []Record                `json:"records"`
+	Material map[string]snapshotItem `json:"material"`
+}
+
+type snapshotItem struct {
+	Value  []byte `json:"value"`
+	Secret bool   `json:"secret"`
+}
+
+// Checkpoint returns the encrypted state of the ceremony so it can be resumed
+//
+// The checkpoint contains all secret material; key must be kept apart from it.
+// Format: version || nonce || AES-256-GCM(state).
+func (e *Engine) Checkpoint(key [32]byte) ([]byte, error) {
+	step, _ := e.NextStep()
+	e.record(step, EventCheckpoint, fmt.Sprintf("%d of %d steps done", e.next, len(e.runbook.Steps)))
+
+	// Step 1: Snapshot the state
+	snap := snapshot{
+		Runbook:  e.runbook.Name,
+		Steps:    stepNames(e.runbook),
+		Next:     e.next,
+		Records:  e.records,
+		Material: make(map[string]snapshotItem, len(e.ctx.material)),
+	}
+	for name, it := range e.ctx.material {
+		snap.Material[name] = snapshotItem{Value: it.value, Secret: it.secret}
+	}
+	plaintext, err := json.Marshal(&snap)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(plaintext)
+
+	// Step 2: Encrypt
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 1+aead.NonceSize(), 1+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	out[0] = checkpointVersion
+	if _, err := rand.Read(out[1:]); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, out[1:], plaintext, []byte(checkpointAAD)), nil
+}
+
+// Resume restores an engine from a checkpoint of the same runbook
+//
+// The runbook must have the same name and step names as when the checkpoint was
+// taken, and the transcript must verify.
+func Resume(runbook *Runbook, operator Operator, checkpoint []byte, key [32]byte, opts ...Option) (*Engine, error) {
+	e, err := New(runbook, operator, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 1: Decrypt
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(checkpoint) < 1+aead.NonceSize()+aead.Overhead() || checkpoint[0] != checkpointVersion {
+		return nil, ErrInvalidCheckpoint
+	}
+	nonce := checkpoint[1 : 1+aead.NonceSize()]
+	plaintext, err := aead.Open(nil, nonce, checkpoint[1+aead.NonceSize():], []byte(checkpointAAD))
+	if err != nil {
+		return nil, ErrInvalidCheckpoint
+	}
+	defer clear(plaintext)
+	var snap snapshot
+	if err := json.Unmarshal(plaintext, &snap); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCheckpoint, err)
+	}
+
+	// Step 2: Check it belongs to this runbook
+	names := stepNames(runbook)
+	if snap.Runbook != runbook.Name || len(snap.Steps) != len(names) || snap.Next < 0 || snap.Next > len(names) {
+		return nil, fmt.Errorf("%w: runbook does not match", ErrInvalidCheckpoint)
+	}
+	for i := range names {
+		if snap.Steps[i] != names[i] {
+			return nil, fmt.Errorf("%w: step %d is %q, expected %q", ErrInvalidCheckpoint, i, names[i], snap.Steps[i])
+		}
+	}
+	head, err := VerifyTranscript(snap.Records)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Restore
+	e.next = snap.Next
+	e.records = snap.Records
+	e.head = head
+	for name, it := range snap.Material {
+		e.ctx.material[name] = &item{value: it.Value, secret: it.Secret}
+	}
+	step, _ := e.NextStep()
+	e.record(step, EventResume, fmt.Sprintf("%d of %d steps done", e.next, len(names)))
+	return e, nil
+}
+
+func stepNames(runbook *Runbook) []string {
+	names := make([]string, len(runbook.Steps))
+	for i, step := range runbook.Steps {
+		names[i] = step.Name
+	}
+	return names
+}
+
+// newAEAD creates the AES-256-GCM cipher for checkpoints
+func newAEAD(key [32]byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,231 @@
+package ceremony
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neverDefined/cryptography-playground/pkg/fixtures"
+	"github.com/neverDefined/cryptography-playground/pkg/shamir"
+)
+
+// testRunbook is a 2-of-3 cold key ceremony
+func testRunbook() *Runbook {
+	return &Runbook{Name: "test ceremony", Steps: []Step{
+		GenerateEntropy("entropy", 32),
+		DeriveKey("entropy", "key"),
+		SplitShares("key", 2, 3),
+		VerifyShares("key", 2, 3),
+		Destroy("entropy", "key"),
+	}}
+}
+
+func testOptions() []Option {
+	now := time.Unix(1700000000, 0)
+	return []Option{
+		WithClock(func() time.Time { now = now.Add(time.Second); return now }),
+		WithRandReader(fixtures.DeterministicReader("ceremony")),
+	}
+}
+
+func TestRunCeremony(t *testing.T) {
+	engine, err := New(testRunbook(), AutoConfirm("alice"), testOptions()...)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := engine.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !engine.Done() {
+		t.Fatal("Expected the ceremony to be done")
+	}
+	if err := engine.RunStep(); !errors.Is(err, ErrDone) {
+		t.Errorf("Expected ErrDone, got %v", err)
+	}
+
+	// Secrets are gone, public material is available
+	if _, ok := engine.ctx.Get("key"); ok {
+		t.Error("Expected the key to be destroyed")
+	}
+	if _, ok := engine.Public(ShareName("key", 1)); ok {
+		t.Error("Expected Public to refuse secret material")
+	}
+	pub, ok := engine.Public("key.pub")
+	if !ok || len(pub) != 33 {
+		t.Fatal("Expected a public key")
+	}
+
+	// Two of the remaining shares recover the key
+	var shares []shamir.Share
+	for _, i := range []int{1, 3} {
+		value, _ := engine.ctx.Get(ShareName("key", i))
+		shares = append(shares, shamir.Share{Index: i, Value: [32]byte(value)})
+	}
+	secret, err := shamir.Combine(shares)
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if secret == ([32]byte{}) {
+		t.Error("Expected a recovered key")
+	}
+
+	// The transcript verifies and never contains secrets
+	head, err := VerifyTranscript(engine.Transcript())
+	if err != nil {
+		t.Fatalf("VerifyTranscript failed: %v", err)
+	}
+	if head != engine.TranscriptHash() {
+		t.Error("Expected the verified head to match TranscriptHash")
+	}
+	for _, r := range engine.Transcript() {
+		for _, i := range []int{1, 2, 3} {
+			value, _ := engine.ctx.Get(ShareName("key", i))
+			if len(value) > 0 && strings.Contains(r.Detail, hex.EncodeToString(value)) {
+				t.Errorf("Transcript record %d contains share %d", r.Seq, i)
+			}
+		}
+	}
+}
+
+func TestCeremonyDeterministic(t *testing.T) {
+	run := func() [32]byte {
+		engine, _ := New(testRunbook(), AutoConfirm("alice"), testOptions()...)
+		if err := engine.Run(); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		return engine.TranscriptHash()
+	}
+	if run() != run() {
+		t.Error("Expected identical transcripts from identical rehearsals")
+	}
+}
+
+func TestTranscriptTamper(t *testing.T) {
+	engine, _ := New(testRunbook(), AutoConfirm("alice"), testOptions()...)
+	if err := engine.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	records := engine.Transcript()
+	records[2].Detail = "confirmed by mallory"
+	if _, err := VerifyTranscript(records); !errors.Is(err, ErrTranscriptTampered) {
+		t.Errorf("Expected ErrTranscriptTampered for an edited record, got %v", err)
+	}
+	records = engine.Transcript()
+	if _, err := VerifyTranscript(append(records[:3:3], records[4:]...)); !errors.Is(err, ErrTranscriptTampered) {
+		t.Errorf("Expected ErrTranscriptTampered for a dropped record, got %v", err)
+	}
+}
+
+func TestDeclineAndRetry(t *testing.T) {
+	declined := false
+	operator := OperatorFunc(func(step, prompt string) (string, error) {
+		if !declined && step == "split key" {
+			declined = true
+			return "", ErrDeclined
+		}
+		return "bob", nil
+	})
+	engine, _ := New(testRunbook(), operator, testOptions()...)
+
+	if err := engine.Run(); !errors.Is(err, ErrDeclined) {
+		t.Fatalf("Expected ErrDeclined, got %v", err)
+	}
+	if step, _ := engine.NextStep(); step != "split key" {
+		t.Errorf("Expected to stay at the declined step, got %q", step)
+	}
+	if err := engine.Run(); err != nil {
+		t.Fatalf("Retry failed: %v", err)
+	}
+}
+
+func TestCheckpointResume(t *testing.T) {
+	key := [32]byte{0x42}
+	engine, _ := New(testRunbook(), AutoConfirm("alice"), testOptions()...)
+	for i := 0; i < 3; i++ {
+		if err := engine.RunStep(); err != nil {
+			t.Fatalf("RunStep %d failed: %v", i, err)
+		}
+	}
+	checkpoint, err := engine.Checkpoint(key)
+	if err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+
+	resumed, err := Resume(testRunbook(), AutoConfirm("carol"), checkpoint, key, testOptions()...)
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if step, _ := resumed.NextStep(); step != "verify key shares" {
+		t.Errorf("Expected to resume at verify, got %q", step)
+	}
+	if err := resumed.Run(); err != nil {
+		t.Fatalf("Run after resume failed: %v", err)
+	}
+	if _, err := VerifyTranscript(resumed.Transcript()); err != nil {
+		t.Errorf("Expected the resumed transcript to verify: %v", err)
+	}
+	if len(resumed.Transcript()) <= len(engine.Transcript()) {
+		t.Error("Expected the resumed transcript to extend the original")
+	}
+
+	// Wrong key, tampered checkpoint, different runbook
+	if _, err := Resume(testRunbook(), AutoConfirm("carol"), checkpoint, [32]byte{0x43}); !errors.Is(err, ErrInvalidCheckpoint) {
+		t.Errorf("Expected ErrInvalidCheckpoint for a wrong key, got %v", err)
+	}
+	tampered := append([]byte(nil), checkpoint...)
+	tampered[len(tampered)-1] ^= 0x01
+	if _, err := Resume(testRunbook(), AutoConfirm("carol"), tampered, key); !errors.Is(err, ErrInvalidCheckpoint) {
+		t.Errorf("Expected ErrInvalidCheckpoint for a tampered checkpoint, got %v", err)
+	}
+	other := testRunbook()
+	other.Steps[3] = Destroy("entropy")
+	if _, err := Resume(other, AutoConfirm("carol"), checkpoint, key); !errors.Is(err, ErrInvalidCheckpoint) {
+		t.Errorf("Expected ErrInvalidCheckpoint for a changed runbook, got %v", err)
+	}
+}
+
+func TestFailedCheck(t *testing.T) {
+	runbook := &Runbook{Name: "bad entropy", Steps: []Step{GenerateEntropy("entropy", 32)}}
+	engine, _ := New(runbook, AutoConfirm("alice"), WithRandReader(zeroReader{}))
+	if err := engine.Run(); err == nil {
+		t.Fatal("Expected the entropy check to fail for all-zero randomness")
+	}
+	records := engine.Transcript()
+	if last := records[len(records)-1]; last.Event != EventFail {
+		t.Errorf("Expected a fail record, got %q", last.Event)
+	}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestNewErrors(t *testing.T) {
+	run := func(*Context) error { return nil }
+	tests := []struct {
+		name    string
+		runbook *Runbook
+	}{
+		{"nil runbook", nil},
+		{"no name", &Runbook{Steps: []Step{{Name: "a", Run: run}}}},
+		{"no steps", &Runbook{Name: "empty"}},
+		{"missing run", &Runbook{Name: "r", Steps: []Step{{Name: "a"}}}},
+		{"duplicate step", &Runbook{Name: "r", Steps: []Step{{Name: "a", Run: run}, {Name: "a", Run: run}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.runbook, AutoConfirm("alice")); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+	if _, err := New(testRunbook(), nil); err == nil {
+		t.Error("Expected error for nil operator")
+	}
+}
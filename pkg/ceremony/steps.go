@@ -0,0 +1,267 @@
+package ceremony
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/shamir"
+)
+
+// Built-in steps
+//
+// Material naming: a key "key" is stored as the secret "key" with its public
+// key in "key.pub"; its shares are the secrets "key.share.1".."key.share.N"
+// and the Feldman commitments are the public "key.commitments".
+
+// deriveKeyTag separates key derivation from any other use of the entropy
+const deriveKeyTag = "cryptography-playground/ceremony/derive-key"
+
+// ShareName returns the material name of share i (1-based) of key
+func ShareName(key string, i int) string {
+	return fmt.Sprintf("%s.share.%d", key, i)
+}
+
+// GenerateEntropy returns a step that stores size random bytes as the secret name
+//
+// Example:
+//
+//	ceremony.GenerateEntropy("entropy", 32)
+func GenerateEntropy(name string, size int) Step {
+	return Step{
+		Name:    "generate " + name,
+		Prompt:  fmt.Sprintf("Generate %d bytes of entropy into %q on the air-gapped machine?", size, name),
+		Confirm: true,
+		Run: func(c *Context) error {
+			if size < 16 {
+				return fmt.Errorf("entropy size %d is below 16 bytes", size)
+			}
+			buf := make([]byte, size)
+			defer clear(buf)
+			if _, err := io.ReadFull(c.Rand(), buf); err != nil {
+				return fmt.Errorf("failed to read randomness: %w", err)
+			}
+			c.Put(name, buf, true)
+			c.Logf("generated %d bytes", size)
+			return nil
+		},
+		Check: func(c *Context) error {
+			value, ok := c.Get(name)
+			if !ok || len(value) != size {
+				return fmt.Errorf("expected %d bytes in %q", size, name)
+			}
+			if bytes.Count(value, value[:1]) == len(value) {
+				return errors.New("entropy is a repeated byte")
+			}
+			return nil
+		},
+	}
+}
+
+// DeriveKey returns a step that derives a secp256k1 private key from entropy
+//
+// key = SHA256(tag || counter || entropy) for the first counter that gives a
+// valid scalar. The public key is published as key+".pub".
+//
+// Example:
+//
+//	ceremony.DeriveKey("entropy", "key")
+func DeriveKey(from, key string) Step {
+	return Step{
+		Name: "derive " + key,
+		Run: func(c *Context) error {
+			entropy, ok := c.Get(from)
+			if !ok {
+				return fmt.Errorf("missing material %q", from)
+			}
+			priv := deriveKey(entropy)
+			defer priv.Zero()
+			secret := priv.Key.Bytes()
+			defer clear(secret[:])
+			pub := priv.PubKey().SerializeCompressed()
+			c.Put(key, secret[:], true)
+			c.Put(key+".pub", pub, false)
+			c.Logf("public key %x", pub)
+			return nil
+		},
+		Check: func(c *Context) error {
+			secret, ok := c.Get(key)
+			if !ok {
+				return fmt.Errorf("missing material %q", key)
+			}
+			pub, _ := c.Get(key + ".pub")
+			priv, _ := btcec.PrivKeyFromBytes(secret)
+			defer priv.Zero()
+			if !bytes.Equal(priv.PubKey().SerializeCompressed(), pub) {
+				return errors.New("public key does not match the private key")
+			}
+			return nil
+		},
+	}
+}
+
+// deriveKey hashes entropy to a valid private key
+func deriveKey(entropy []byte) *btcec.PrivateKey {
+	for counter := uint32(0); ; counter++ {
+		var ctr [4]byte
+		binary.BigEndian.PutUint32(ctr[:], counter)
+		digest := schnorr.TaggedHash(deriveKeyTag, ctr[:], entropy)
+
+		var scalar btcec.ModNScalar
+		if overflow := scalar.SetBytes(&digest); overflow == 0 && !scalar.IsZero() {
+			clear(digest[:])
+			return btcec.PrivKeyFromScalar(&scalar)
+		}
+	}
+}
+
+// SplitShares returns a step that splits key into total Shamir shares, threshold of which recover it
+//
+// Example:
+//
+//	ceremony.SplitShares("key", 2, 3)
+func SplitShares(key string, threshold, total int) Step {
+	return Step{
+		Name:    "split " + key,
+		Prompt:  fmt.Sprintf("Split %q into %d shares with threshold %d?", key, total, threshold),
+		Confirm: true,
+		Run: func(c *Context) error {
+			secret, ok := c.Get(key)
+			if !ok || len(secret) != 32 {
+				return fmt.Errorf("missing 32-byte key %q", key)
+			}
+			shares, commitments, err := shamir.Split([32]byte(secret), threshold, total, c.Rand())
+			if err != nil {
+				return err
+			}
+			for _, s := range shares {
+				c.Put(ShareName(key, s.Index), s.Value[:], true)
+				clear(s.Value[:])
+			}
+			c.Put(key+".commitments", bytes.Join(commitments, nil), false)
+			c.Logf("%d-of-%d shares, commitments %s", threshold, total, hex.EncodeToString(bytes.Join(commitments, nil)))
+			return nil
+		},
+		Check: func(c *Context) error {
+			commitments, err := commitmentsOf(c, key)
+			if err != nil {
+				return err
+			}
+			if len(commitments) != threshold {
+				return fmt.Errorf("expected %d commitments, got %d", threshold, len(commitments))
+			}
+			pub, _ := c.Get(key + ".pub")
+			if !bytes.Equal(commitments[0], pub) {
+				return errors.New("first commitment is not the public key")
+			}
+			return nil
+		},
+	}
+}
+
+// VerifyShares returns a step that checks every share against the commitments
+// and that threshold shares recover the key
+//
+// Recovery is checked for the first and the last threshold shares, without
+// reading the key itself, so it still works after the key was destroyed.
+//
+// Example:
+//
+//	ceremony.VerifyShares("key", 2, 3)
+func VerifyShares(key string, threshold, total int) Step {
+	return Step{
+		Name: "verify " + key + " shares",
+		Run: func(c *Context) error {
+			commitments, err := commitmentsOf(c, key)
+			if err != nil {
+				return err
+			}
+			pub, ok := c.Get(key + ".pub")
+			if !ok {
+				return fmt.Errorf("missing material %q", key+".pub")
+			}
+
+			// Step 1: Each share matches the commitments
+			shares := make([]shamir.Share, total)
+			defer func() {
+				for i := range shares {
+					clear(shares[i].Value[:])
+				}
+			}()
+			for i := range shares {
+				value, ok := c.Get(ShareName(key, i+1))
+				if !ok || len(value) != 32 {
+					return fmt.Errorf("missing share %q", ShareName(key, i+1))
+				}
+				shares[i] = shamir.Share{Index: i + 1, Value: [32]byte(value)}
+				if err := shamir.VerifyShare(shares[i], commitments); err != nil {
+					return fmt.Errorf("share %d: %w", i+1, err)
+				}
+			}
+
+			// Step 2: Threshold subsets recover the public key
+			for _, subset := range [][]shamir.Share{shares[:threshold], shares[total-threshold:]} {
+				secret, err := shamir.Combine(subset)
+				if err != nil {
+					return err
+				}
+				priv, _ := btcec.PrivKeyFromBytes(secret[:])
+				clear(secret[:])
+				recovered := priv.PubKey().SerializeCompressed()
+				priv.Zero()
+				if !bytes.Equal(recovered, pub) {
+					return errors.New("shares do not recover the key")
+				}
+			}
+			c.Logf("%d shares verified", total)
+			return nil
+		},
+	}
+}
+
+// commitmentsOf splits the stored commitments into 33-byte points
+func commitmentsOf(c *Context, key string) ([][]byte, error) {
+	joined, ok := c.Get(key + ".commitments")
+	if !ok || len(joined) == 0 || len(joined)%33 != 0 {
+		return nil, fmt.Errorf("missing commitments for %q", key)
+	}
+	commitments := make([][]byte, 0, len(joined)/33)
+	for i := 0; i < len(joined); i += 33 {
+		commitments = append(commitments, joined[i:i+33])
+	}
+	return commitments, nil
+}
+
+// Destroy returns a step that wipes material after operator confirmation
+//
+// Example:
+//
+//	ceremony.Destroy("entropy", "key")
+func Destroy(names ...string) Step {
+	return Step{
+		Name:    "destroy " + strings.Join(names, ", "),
+		Prompt:  fmt.Sprintf("Destroy %s? This cannot be undone.", strings.Join(names, ", ")),
+		Confirm: true,
+		Run: func(c *Context) error {
+			for _, name := range names {
+				c.Destroy(name)
+			}
+			c.Logf("destroyed %d items", len(names))
+			return nil
+		},
+		Check: func(c *Context) error {
+			for _, name := range names {
+				if _, ok := c.Get(name); ok {
+					return fmt.Errorf("%q still present", name)
+				}
+			}
+			return nil
+		},
+	}
+}
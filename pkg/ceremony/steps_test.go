@@ -0,0 +1,58 @@
+package ceremony
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/shamir"
+)
+
+func TestDeriveKeyDeterministic(t *testing.T) {
+	entropy := bytes.Repeat([]byte{0x5a, 0xa5}, 16)
+	a := deriveKey(entropy)
+	b := deriveKey(entropy)
+	if !a.PubKey().IsEqual(b.PubKey()) {
+		t.Error("Expected the same key from the same entropy")
+	}
+	if c := deriveKey(append(entropy, 0x00)); c.PubKey().IsEqual(a.PubKey()) {
+		t.Error("Expected a different key from different entropy")
+	}
+}
+
+// TestVerifySharesDetectsTampering corrupts a share between split and verify
+func TestVerifySharesDetectsTampering(t *testing.T) {
+	runbook := testRunbook()
+	runbook.Steps = runbook.Steps[:4]
+	engine, _ := New(runbook, AutoConfirm("alice"), testOptions()...)
+	for i := 0; i < 3; i++ {
+		if err := engine.RunStep(); err != nil {
+			t.Fatalf("RunStep %d failed: %v", i, err)
+		}
+	}
+
+	share, _ := engine.ctx.Get(ShareName("key", 2))
+	share[31] ^= 0x01
+	if err := engine.RunStep(); !errors.Is(err, shamir.ErrInvalidShare) {
+		t.Errorf("Expected ErrInvalidShare, got %v", err)
+	}
+}
+
+func TestStepsMissingMaterial(t *testing.T) {
+	tests := []struct {
+		name string
+		step Step
+	}{
+		{"derive without entropy", DeriveKey("entropy", "key")},
+		{"split without key", SplitShares("key", 2, 3)},
+		{"verify without commitments", VerifyShares("key", 2, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, _ := New(&Runbook{Name: "r", Steps: []Step{tt.step}}, AutoConfirm("alice"))
+			if err := engine.Run(); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
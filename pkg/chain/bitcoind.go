@@ -0,0 +1,223 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+// rpcNotFound is Bitcoin Core's RPC_INVALID_ADDRESS_OR_KEY, used for unknown blocks and transactions
+const rpcNotFound = -5
+
+// Bitcoind is a Backend for the Bitcoin Core JSON-RPC interface
+//
+// GetMerkleProof uses getrawtransaction, so the node needs -txindex for
+// transactions that are not in its wallet. GetUTXOs uses scantxoutset, which
+// scans the whole UTXO set and takes a while on mainnet.
+type Bitcoind struct {
+	url      string
+	user     string
+	password string
+	client   *http.Client
+}
+
+// NewBitcoind creates a Bitcoin Core backend; client may be nil for http.DefaultClient
+//
+// Example:
+//
+//	backend := chain.NewBitcoind("http://127.0.0.1:18443", "user", "pass", nil)
+//	txid, err := backend.BroadcastTx(ctx, signedTx)
+func NewBitcoind(url, user, password string, client *http.Client) *Bitcoind {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Bitcoind{url: url, user: user, password: password, client: client}
+}
+
+// RPCError is an error returned by the node
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("bitcoind rpc error %d: %s", e.Code, e.Message)
+}
+
+// Is makes errors.Is(err, ErrNotFound) match unknown blocks and transactions
+func (e *RPCError) Is(target error) bool {
+	return target == ErrNotFound && e.Code == rpcNotFound
+}
+
+// GetHeader implements Backend using getblockheader
+func (b *Bitcoind) GetHeader(ctx context.Context, blockHash [32]byte) (*BlockHeader, error) {
+	var raw string
+	if err := b.call(ctx, "getblockheader", []any{FormatHash(blockHash), false}, &raw); err != nil {
+		return nil, err
+	}
+	header, err := decodeHexHeader(raw)
+	if err != nil {
+		return nil, err
+	}
+	if header.BlockHash() != blockHash {
+		return nil, errors.New("bitcoind returned a header for another block")
+	}
+	return header, nil
+}
+
+// GetMerkleProof implements Backend using getrawtransaction and getblock
+//
+// The branch is computed from the block's transaction list.
+func (b *Bitcoind) GetMerkleProof(ctx context.Context, txid [32]byte) (*MerkleProof, error) {
+	// Step 1: Find the block
+	var txInfo struct {
+		BlockHash string `json:"blockhash"`
+	}
+	if err := b.call(ctx, "getrawtransaction", []any{FormatHash(txid), true}, &txInfo); err != nil {
+		return nil, err
+	}
+	if txInfo.BlockHash == "" {
+		return nil, fmt.Errorf("%w: transaction %s is unconfirmed", ErrNotFound, FormatHash(txid))
+	}
+
+	// Step 2: List its transactions
+	var block struct {
+		Hash   string   `json:"hash"`
+		Height int64    `json:"height"`
+		Tx     []string `json:"tx"`
+	}
+	if err := b.call(ctx, "getblock", []any{txInfo.BlockHash, 1}, &block); err != nil {
+		return nil, err
+	}
+	proof := &MerkleProof{TxID: txid, Height: block.Height, Position: -1}
+	var err error
+	if proof.BlockHash, err = ParseHash(block.Hash); err != nil {
+		return nil, err
+	}
+	txids := make([][32]byte, len(block.Tx))
+	for i, s := range block.Tx {
+		if txids[i], err = ParseHash(s); err != nil {
+			return nil, err
+		}
+		if txids[i] == txid {
+			proof.Position = i
+		}
+	}
+	if proof.Position < 0 {
+		return nil, fmt.Errorf("transaction %s is not in block %s", FormatHash(txid), block.Hash)
+	}
+
+	// Step 3: Branch
+	if proof.Siblings, err = MerkleBranch(txids, proof.Position); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// BroadcastTx implements Backend using sendrawtransaction
+func (b *Bitcoind) BroadcastTx(ctx context.Context, transaction *tx.Transaction) ([32]byte, error) {
+	var raw string
+	if err := b.call(ctx, "sendrawtransaction", []any{hex.EncodeToString(transaction.Serialize())}, &raw); err != nil {
+		return [32]byte{}, err
+	}
+	return ParseHash(raw)
+}
+
+// GetUTXOs implements Backend using scantxoutset with an addr() descriptor
+func (b *Bitcoind) GetUTXOs(ctx context.Context, address string) ([]UTXO, error) {
+	var result struct {
+		Success  bool `json:"success"`
+		Unspents []struct {
+			TxID   string      `json:"txid"`
+			Vout   uint32      `json:"vout"`
+			Amount json.Number `json:"amount"` // BTC
+			Height int64       `json:"height"`
+		} `json:"unspents"`
+	}
+	if err := b.call(ctx, "scantxoutset", []any{"start", []string{"addr(" + address + ")"}}, &result); err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, errors.New("scantxoutset did not complete")
+	}
+	utxos := make([]UTXO, len(result.Unspents))
+	for i, u := range result.Unspents {
+		txid, err := ParseHash(u.TxID)
+		if err != nil {
+			return nil, err
+		}
+		value, err := parseBTC(u.Amount.String())
+		if err != nil {
+			return nil, err
+		}
+		utxos[i] = UTXO{OutPoint: tx.OutPoint{Hash: txid, Index: u.Vout}, Value: value, Height: u.Height}
+	}
+	return utxos, nil
+}
+
+// parseBTC converts a decimal BTC amount such as "0.00012345" to satoshis exactly
+func parseBTC(s string) (int64, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > 8 || strings.HasPrefix(s, "-") {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	frac += strings.Repeat("0", 8-len(frac))
+	sats, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return sats, nil
+}
+
+// call performs a JSON-RPC 1.0 request and decodes the result into out
+func (b *Bitcoind) call(ctx context.Context, method string, params []any, out any) error {
+	payload, err := json.Marshal(map[string]any{
+		"jsonrpc": "1.0",
+		"id":      method,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if b.user != "" || b.password != "" {
+		req.SetBasicAuth(b.user, b.password)
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Bitcoin Core answers RPC errors with HTTP 404/500 and a JSON body
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+		Error  *RPCError       `json:"error"`
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("bitcoind %s: %s", method, resp.Status)
+	}
+	if envelope.Error != nil {
+		return envelope.Error
+	}
+	return json.Unmarshal(envelope.Result, out)
+}
@@ -0,0 +1,126 @@
+package chain
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newBitcoindServer serves a test block through the JSON-RPC methods used by Bitcoind
+func newBitcoindServer(t *testing.T, block *testBlock) *httptest.Server {
+	t.Helper()
+	blockHash := FormatHash(block.header.BlockHash())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+			http.Error(w, "", http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		var first string
+		json.Unmarshal(req.Params[0], &first)
+
+		reply := func(result any) {
+			json.NewEncoder(w).Encode(map[string]any{"result": result, "error": nil, "id": req.Method})
+		}
+		notFound := func() {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]any{"result": nil, "error": map[string]any{"code": -5, "message": "not found"}})
+		}
+		switch req.Method {
+		case "getblockheader":
+			if first != blockHash {
+				notFound()
+				return
+			}
+			reply(hex.EncodeToString(block.header.Serialize()))
+		case "getrawtransaction":
+			for _, id := range block.txids {
+				if FormatHash(id) == first {
+					reply(map[string]any{"blockhash": blockHash})
+					return
+				}
+			}
+			notFound()
+		case "getblock":
+			txids := make([]string, len(block.txids))
+			for i, id := range block.txids {
+				txids[i] = FormatHash(id)
+			}
+			reply(map[string]any{"hash": blockHash, "height": 100, "tx": txids})
+		case "sendrawtransaction":
+			reply(block.txs[0].TxIDHex())
+		case "scantxoutset":
+			w.Write([]byte(`{"result":{"success":true,"unspents":[{"txid":"` + FormatHash(block.txids[2]) +
+				`","vout":3,"amount":0.00012345,"height":100}]},"error":null}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBitcoind(t *testing.T) {
+	ctx := context.Background()
+	block := newTestBlock(7)
+	server := newBitcoindServer(t, block)
+	backend := NewBitcoind(server.URL, "user", "pass", nil)
+
+	for i := range block.txids {
+		if _, _, err := VerifyInclusion(ctx, backend, block.txids[i]); err != nil {
+			t.Errorf("VerifyInclusion(%d) failed: %v", i, err)
+		}
+	}
+
+	txid, err := backend.BroadcastTx(ctx, block.txs[0])
+	if err != nil || txid != block.txids[0] {
+		t.Errorf("BroadcastTx returned %x, %v", txid, err)
+	}
+
+	utxos, err := backend.GetUTXOs(ctx, "bcrt1qtest")
+	if err != nil {
+		t.Fatalf("GetUTXOs failed: %v", err)
+	}
+	if len(utxos) != 1 || utxos[0].Value != 12345 || utxos[0].OutPoint.Index != 3 || utxos[0].OutPoint.Hash != block.txids[2] {
+		t.Errorf("Unexpected UTXOs %+v", utxos)
+	}
+
+	_, err = backend.GetMerkleProof(ctx, [32]byte{0x01})
+	var rpcErr *RPCError
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &rpcErr) || rpcErr.Code != -5 {
+		t.Errorf("Expected an RPC not-found error, got %v", err)
+	}
+
+	wrongAuth := NewBitcoind(server.URL, "user", "wrong", nil)
+	if _, err := wrongAuth.GetHeader(ctx, block.header.BlockHash()); err == nil {
+		t.Error("Expected an authentication failure")
+	}
+}
+
+func TestParseBTC(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		ok   bool
+	}{
+		{"0.00012345", 12345, true},
+		{"1", 100000000, true},
+		{"21000000.00000000", 2100000000000000, true},
+		{"0.1", 10000000, true},
+		{"0.000000001", 0, false},
+		{"-1", 0, false},
+		{"abc", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseBTC(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseBTC(%q) = %d, %v", tt.in, got, err)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+// Chain data sources
+//
+// Features that need blockchain data (SPV inclusion checks, proof-of-funds
+// audits, broadcasting) talk to a Backend instead of a specific service. Two
+// implementations are provided:
+//
+//	NewEsplora("https://blockstream.info/api")        // Esplora REST API
+//	NewBitcoind("http://127.0.0.1:8332", user, pass)  // Bitcoin Core JSON-RPC
+//
+// All hashes in this package are in internal byte order, like tx.OutPoint;
+// they are reversed only at the API boundary, where services use display order.
+
+// ErrNotFound is returned when the backend does not know a block or transaction
+var ErrNotFound = errors.New("not found")
+
+// Backend is a source of blockchain data
+type Backend interface {
+	// GetHeader returns the header of the block with the given hash
+	GetHeader(ctx context.Context, blockHash [32]byte) (*BlockHeader, error)
+	// GetMerkleProof returns a proof that a confirmed transaction is in its block
+	GetMerkleProof(ctx context.Context, txid [32]byte) (*MerkleProof, error)
+	// BroadcastTx submits a transaction to the network and returns its txid
+	BroadcastTx(ctx context.Context, transaction *tx.Transaction) ([32]byte, error)
+	// GetUTXOs returns the unspent outputs paying to an address
+	GetUTXOs(ctx context.Context, address string) ([]UTXO, error)
+}
+
+// UTXO is an unspent output reported by a backend
+type UTXO struct {
+	OutPoint tx.OutPoint
+	Value    int64 // Satoshis
+	Height   int64 // Confirmation height; 0 if unconfirmed
+}
+
+// HeaderSize is the size of a serialized block header
+const HeaderSize = 80
+
+// BlockHeader is an 80-byte Bitcoin block header
+type BlockHeader struct {
+	Version    int32
+	PrevBlock  [32]byte // Internal byte order
+	MerkleRoot [32]byte // Internal byte order
+	Timestamp  uint32
+	Bits       uint32
+	Nonce      uint32
+}
+
+// Serialize encodes the header in its 80-byte wire format
+func (h *BlockHeader) Serialize() []byte {
+	buf := make([]byte, 0, HeaderSize)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(h.Version))
+	buf = append(buf, h.PrevBlock[:]...)
+	buf = append(buf, h.MerkleRoot[:]...)
+	buf = binary.LittleEndian.AppendUint32(buf, h.Timestamp)
+	buf = binary.LittleEndian.AppendUint32(buf, h.Bits)
+	buf = binary.LittleEndian.AppendUint32(buf, h.Nonce)
+	return buf
+}
+
+// BlockHash returns SHA256D of the serialized header (internal byte order)
+func (h *BlockHeader) BlockHash() [32]byte {
+	return hash.SHA256D(h.Serialize())
+}
+
+// ParseHeader decodes an 80-byte block header
+//
+// Example:
+//
+//	header, err := ParseHeader(raw)
+//	fmt.Printf("%x\n", hash.Reverse32(header.BlockHash()))
+func ParseHeader(raw []byte) (*BlockHeader, error) {
+	if len(raw) != HeaderSize {
+		return nil, fmt.Errorf("block header must be %d bytes, got %d", HeaderSize, len(raw))
+	}
+	h := &BlockHeader{
+		Version:   int32(binary.LittleEndian.Uint32(raw[0:4])),
+		Timestamp: binary.LittleEndian.Uint32(raw[68:72]),
+		Bits:      binary.LittleEndian.Uint32(raw[72:76]),
+		Nonce:     binary.LittleEndian.Uint32(raw[76:80]),
+	}
+	copy(h.PrevBlock[:], raw[4:36])
+	copy(h.MerkleRoot[:], raw[36:68])
+	return h, nil
+}
+
+// MerkleProof shows that a transaction is included in a block
+type MerkleProof struct {
+	TxID      [32]byte
+	BlockHash [32]byte
+	Height    int64
+	Position  int        // Index of the transaction in the block
+	Siblings  [][32]byte // Merkle branch, from the leaves up
+}
+
+// Steps converts the branch into hash.MerkleProofStep values
+func (p *MerkleProof) Steps() []hash.MerkleProofStep {
+	steps := make([]hash.MerkleProofStep, len(p.Siblings))
+	for i, sibling := range p.Siblings {
+		steps[i] = hash.MerkleProofStep{
+			Sibling:       sibling,
+			LeftIsSibling: (p.Position>>i)&1 == 1,
+		}
+	}
+	return steps
+}
+
+// Verify checks the proof against a header's merkle root
+//
+// Example:
+//
+//	if err := proof.Verify(header); err != nil {
+//		// the backend lied about the transaction or the block
+//	}
+func (p *MerkleProof) Verify(header *BlockHeader) error {
+	if header == nil {
+		return errors.New("header is required")
+	}
+	if header.BlockHash() != p.BlockHash {
+		return errors.New("header does not match the proof's block")
+	}
+	if p.Position < 0 || p.Position >= 1<<len(p.Siblings) {
+		return fmt.Errorf("position %d out of range for a branch of %d", p.Position, len(p.Siblings))
+	}
+	ok, err := hash.VerifyMerkleProof(p.TxID, p.Steps(), header.MerkleRoot)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("merkle branch does not lead to the header's root")
+	}
+	return nil
+}
+
+// MerkleBranch computes the merkle branch of txids[index]
+//
+// Odd levels duplicate their last hash, as in hash.MerkleRoot.
+//
+// Example:
+//
+//	siblings, err := MerkleBranch(blockTxids, 5)
+func MerkleBranch(txids [][32]byte, index int) ([][32]byte, error) {
+	if index < 0 || index >= len(txids) {
+		return nil, fmt.Errorf("index %d out of range for %d transactions", index, len(txids))
+	}
+	var branch [][32]byte
+	level := append([][32]byte(nil), txids...)
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+		branch = append(branch, level[index^1])
+		next := make([][32]byte, len(level)/2)
+		for i := range next {
+			next[i] = hash.SHA256D(hash.Concat(level[2*i][:], level[2*i+1][:]))
+		}
+		level = next
+		index /= 2
+	}
+	return branch, nil
+}
+
+// MerkleBlock is a BIP37 merkleblock: a header and a partial merkle tree
+//
+// Bitcoin Core's gettxoutproof returns one, hex-encoded, and SPV peers send them
+// in response to filtered block requests.
+type MerkleBlock struct {
+	Header BlockHeader
+	Tree   *hash.PartialMerkleTree
+}
+
+// ParseMerkleBlock decodes a serialized merkleblock
+//
+// Example:
+//
+//	raw, _ := hex.DecodeString(gettxoutproofResult)
+//	mb, err := ParseMerkleBlock(raw)
+//	txids, positions, err := mb.Verify()
+func ParseMerkleBlock(raw []byte) (*MerkleBlock, error) {
+	if len(raw) < HeaderSize {
+		return nil, fmt.Errorf("merkleblock must be at least %d bytes, got %d", HeaderSize, len(raw))
+	}
+	header, err := ParseHeader(raw[:HeaderSize])
+	if err != nil {
+		return nil, err
+	}
+	tree, err := hash.ParsePartialMerkleTree(raw[HeaderSize:])
+	if err != nil {
+		return nil, err
+	}
+	return &MerkleBlock{Header: *header, Tree: tree}, nil
+}
+
+// Serialize encodes the merkleblock: the header followed by the partial tree
+func (m *MerkleBlock) Serialize() []byte {
+	return append(m.Header.Serialize(), m.Tree.Serialize()...)
+}
+
+// Verify checks the partial tree against the header and returns the proven txids with their positions
+//
+// As with VerifyInclusion, checking that the header is in the most-work chain
+// is up to the caller.
+func (m *MerkleBlock) Verify() ([][32]byte, []int, error) {
+	if m.Tree == nil {
+		return nil, nil, errors.New("merkleblock has no partial merkle tree")
+	}
+	root, txids, positions, err := m.Tree.ExtractMatches()
+	if err != nil {
+		return nil, nil, err
+	}
+	if root != m.Header.MerkleRoot {
+		return nil, nil, errors.New("partial merkle tree does not lead to the header's root")
+	}
+	return txids, positions, nil
+}
+
+// VerifyInclusion fetches and checks an SPV proof that a transaction is confirmed
+//
+// The backend is trusted to report the right block, but not the merkle branch:
+// a forged branch fails against the header. Checking that the header is part of
+// the most-work chain is up to the caller.
+//
+// Example:
+//
+//	header, height, err := VerifyInclusion(ctx, backend, txid)
+func VerifyInclusion(ctx context.Context, backend Backend, txid [32]byte) (*BlockHeader, int64, error) {
+	proof, err := backend.GetMerkleProof(ctx, txid)
+	if err != nil {
+		return nil, 0, err
+	}
+	if proof.TxID != txid {
+		return nil, 0, errors.New("backend returned a proof for another transaction")
+	}
+	header, err := backend.GetHeader(ctx, proof.BlockHash)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := proof.Verify(header); err != nil {
+		return nil, 0, err
+	}
+	return header, proof.Height, nil
+}
+
+// ParseHash decodes a display-order hex hash (as shown by explorers) into internal order
+func ParseHash(s string) ([32]byte, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 32 {
+		return [32]byte{}, fmt.Errorf("invalid hash %q", s)
+	}
+	return hash.Reverse32([32]byte(b)), nil
+}
+
+// FormatHash encodes an internal-order hash as display-order hex
+func FormatHash(h [32]byte) string {
+	display := hash.Reverse32(h)
+	return hex.EncodeToString(display[:])
+}
+
+// decodeHexHeader parses a header returned as hex text
+func decodeHexHeader(s string) (*BlockHeader, error) {
+	raw, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid header hex: %w", err)
+	}
+	return ParseHeader(raw)
+}
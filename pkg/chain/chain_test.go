@@ -0,0 +1,221 @@
+package chain
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+// genesisHeader is the mainnet genesis block header
+const genesisHeader = "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c"
+
+func TestParseHeader(t *testing.T) {
+	raw, _ := hex.DecodeString(genesisHeader)
+	header, err := ParseHeader(raw)
+	if err != nil {
+		t.Fatalf("ParseHeader failed: %v", err)
+	}
+	if got := FormatHash(header.BlockHash()); got != "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f" {
+		t.Errorf("Unexpected genesis hash %s", got)
+	}
+	if got := FormatHash(header.MerkleRoot); got != "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b" {
+		t.Errorf("Unexpected merkle root %s", got)
+	}
+	if header.Timestamp != 1231006505 || header.Bits != 0x1d00ffff || header.Nonce != 2083236893 {
+		t.Errorf("Unexpected header fields %+v", header)
+	}
+	if hex.EncodeToString(header.Serialize()) != genesisHeader {
+		t.Error("Expected Serialize to round-trip")
+	}
+	if _, err := ParseHeader(raw[:79]); err == nil {
+		t.Error("Expected error for a short header")
+	}
+}
+
+// testBlock is a block of n fake transactions with a matching header
+type testBlock struct {
+	header *BlockHeader
+	txs    []*tx.Transaction
+	txids  [][32]byte
+}
+
+func newTestBlock(n int) *testBlock {
+	b := &testBlock{}
+	for i := 0; i < n; i++ {
+		t := &tx.Transaction{
+			Version: 2,
+			Inputs:  []*tx.TxIn{{PreviousOutPoint: tx.OutPoint{Hash: [32]byte{byte(i + 1)}}, Sequence: 0xffffffff}},
+			Outputs: []*tx.TxOut{{Value: int64(1000 * (i + 1)), PkScript: []byte{0x51}}},
+		}
+		b.txs = append(b.txs, t)
+		b.txids = append(b.txids, t.TxID())
+	}
+	b.header = &BlockHeader{Version: 0x20000000, MerkleRoot: hash.MerkleRoot(b.txids), Timestamp: 1700000000, Bits: 0x207fffff}
+	return b
+}
+
+func (b *testBlock) proof(i int) *MerkleProof {
+	siblings, _ := MerkleBranch(b.txids, i)
+	return &MerkleProof{TxID: b.txids[i], BlockHash: b.header.BlockHash(), Height: 100, Position: i, Siblings: siblings}
+}
+
+func TestMerkleProof(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7, 8} {
+		block := newTestBlock(n)
+		for i := 0; i < n; i++ {
+			if err := block.proof(i).Verify(block.header); err != nil {
+				t.Errorf("%d txs, position %d: %v", n, i, err)
+			}
+		}
+	}
+
+	block := newTestBlock(5)
+	tests := []struct {
+		name   string
+		mutate func(p *MerkleProof)
+	}{
+		{"wrong position", func(p *MerkleProof) { p.Position = 3 }},
+		{"wrong txid", func(p *MerkleProof) { p.TxID = block.txids[1] }},
+		{"tampered sibling", func(p *MerkleProof) { p.Siblings[1][0] ^= 0x01 }},
+		{"wrong block", func(p *MerkleProof) { p.BlockHash[0] ^= 0x01 }},
+		{"position out of range", func(p *MerkleProof) { p.Position = 8 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := block.proof(2)
+			tt.mutate(p)
+			if err := p.Verify(block.header); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+	if _, err := MerkleBranch(block.txids, 5); err == nil {
+		t.Error("Expected error for an index past the end")
+	}
+}
+
+// fakeBackend serves one test block
+type fakeBackend struct {
+	block   *testBlock
+	forgeAt int // Position of a forged proof, or -1
+}
+
+func (f *fakeBackend) GetHeader(_ context.Context, blockHash [32]byte) (*BlockHeader, error) {
+	if blockHash != f.block.header.BlockHash() {
+		return nil, ErrNotFound
+	}
+	return f.block.header, nil
+}
+
+func (f *fakeBackend) GetMerkleProof(_ context.Context, txid [32]byte) (*MerkleProof, error) {
+	for i, id := range f.block.txids {
+		if id == txid {
+			return f.block.proof(i), nil
+		}
+	}
+	if f.forgeAt >= 0 {
+		p := f.block.proof(f.forgeAt)
+		p.TxID = txid
+		return p, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (f *fakeBackend) BroadcastTx(_ context.Context, transaction *tx.Transaction) ([32]byte, error) {
+	return transaction.TxID(), nil
+}
+
+func (f *fakeBackend) GetUTXOs(context.Context, string) ([]UTXO, error) {
+	return nil, nil
+}
+
+func TestMerkleBlock(t *testing.T) {
+	// gettxoutproof for the genesis coinbase: one transaction, one hash, flag byte 0x01
+	genesisProof := genesisHeader + "01000000" + "01" +
+		"3ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a" + "0101"
+	raw, _ := hex.DecodeString(genesisProof)
+	mb, err := ParseMerkleBlock(raw)
+	if err != nil {
+		t.Fatalf("ParseMerkleBlock failed: %v", err)
+	}
+	txids, positions, err := mb.Verify()
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if len(txids) != 1 || FormatHash(txids[0]) != "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b" || positions[0] != 0 {
+		t.Errorf("Unexpected matches %x at %v", txids, positions)
+	}
+	if hex.EncodeToString(mb.Serialize()) != genesisProof {
+		t.Error("Expected Serialize to round-trip")
+	}
+
+	// A larger block with two matches
+	b := newTestBlock(11)
+	match := make([]bool, 11)
+	match[3], match[10] = true, true
+	tree, _ := hash.NewPartialMerkleTree(b.txids, match)
+	mb, err = ParseMerkleBlock((&MerkleBlock{Header: *b.header, Tree: tree}).Serialize())
+	if err != nil {
+		t.Fatalf("ParseMerkleBlock failed: %v", err)
+	}
+	txids, positions, err = mb.Verify()
+	if err != nil || len(txids) != 2 || txids[0] != b.txids[3] || txids[1] != b.txids[10] || positions[1] != 10 {
+		t.Fatalf("Unexpected result %v %v %v", txids, positions, err)
+	}
+
+	// Against another block's header
+	mb.Header.MerkleRoot[0] ^= 1
+	if _, _, err := mb.Verify(); err == nil {
+		t.Error("Expected error for a header with another merkle root")
+	}
+
+	// Malformed input
+	if _, err := ParseMerkleBlock(raw[:79]); err == nil {
+		t.Error("Expected error for a short merkleblock")
+	}
+	if _, err := ParseMerkleBlock(raw[:len(raw)-1]); err == nil {
+		t.Error("Expected error for a truncated tree")
+	}
+}
+
+func TestVerifyInclusion(t *testing.T) {
+	block := newTestBlock(6)
+	backend := &fakeBackend{block: block, forgeAt: -1}
+	header, height, err := VerifyInclusion(context.Background(), backend, block.txids[4])
+	if err != nil {
+		t.Fatalf("VerifyInclusion failed: %v", err)
+	}
+	if header.BlockHash() != block.header.BlockHash() || height != 100 {
+		t.Errorf("Unexpected header or height %d", height)
+	}
+
+	if _, _, err := VerifyInclusion(context.Background(), backend, [32]byte{0x99}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	backend.forgeAt = 1
+	if _, _, err := VerifyInclusion(context.Background(), backend, [32]byte{0x99}); err == nil {
+		t.Error("Expected a forged proof to fail")
+	}
+}
+
+func TestParseHash(t *testing.T) {
+	h, err := ParseHash("000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f")
+	if err != nil {
+		t.Fatalf("ParseHash failed: %v", err)
+	}
+	if h[31] != 0x00 || h[0] != 0x6f {
+		t.Error("Expected internal byte order")
+	}
+	if FormatHash(h) != "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f" {
+		t.Error("Expected FormatHash to round-trip")
+	}
+	for _, s := range []string{"", "00", "zz" + genesisHeader[:62]} {
+		if _, err := ParseHash(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
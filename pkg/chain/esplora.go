@@ -0,0 +1,168 @@
+package chain
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+// maxResponseSize bounds the size of a backend response body
+const maxResponseSize = 32 << 20
+
+// Esplora is a Backend for the Esplora REST API (blockstream.info, mempool.space)
+type Esplora struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewEsplora creates an Esplora backend; client may be nil for http.DefaultClient
+//
+// Example:
+//
+//	backend := chain.NewEsplora("https://mempool.space/testnet/api", nil)
+//	utxos, err := backend.GetUTXOs(ctx, "tb1q...")
+func NewEsplora(baseURL string, client *http.Client) *Esplora {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Esplora{baseURL: strings.TrimRight(baseURL, "/"), client: client}
+}
+
+// GetHeader implements Backend using GET /block/:hash/header
+func (e *Esplora) GetHeader(ctx context.Context, blockHash [32]byte) (*BlockHeader, error) {
+	body, err := e.do(ctx, http.MethodGet, "/block/"+FormatHash(blockHash)+"/header", "")
+	if err != nil {
+		return nil, err
+	}
+	header, err := decodeHexHeader(string(body))
+	if err != nil {
+		return nil, err
+	}
+	if header.BlockHash() != blockHash {
+		return nil, fmt.Errorf("esplora returned a header for another block")
+	}
+	return header, nil
+}
+
+// esploraProof is the response of /tx/:txid/merkle-proof (Electrum format)
+type esploraProof struct {
+	BlockHeight int64    `json:"block_height"`
+	Merkle      []string `json:"merkle"` // Display order
+	Pos         int      `json:"pos"`
+}
+
+// GetMerkleProof implements Backend using /tx/:txid/merkle-proof and /block-height/:height
+func (e *Esplora) GetMerkleProof(ctx context.Context, txid [32]byte) (*MerkleProof, error) {
+	// Step 1: Branch and position
+	body, err := e.do(ctx, http.MethodGet, "/tx/"+FormatHash(txid)+"/merkle-proof", "")
+	if err != nil {
+		return nil, err
+	}
+	var raw esploraProof
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("invalid merkle proof: %w", err)
+	}
+	proof := &MerkleProof{TxID: txid, Height: raw.BlockHeight, Position: raw.Pos}
+	for _, s := range raw.Merkle {
+		sibling, err := ParseHash(s)
+		if err != nil {
+			return nil, err
+		}
+		proof.Siblings = append(proof.Siblings, sibling)
+	}
+
+	// Step 2: The proof only names the height; look up the block hash
+	body, err = e.do(ctx, http.MethodGet, fmt.Sprintf("/block-height/%d", raw.BlockHeight), "")
+	if err != nil {
+		return nil, err
+	}
+	if proof.BlockHash, err = ParseHash(strings.TrimSpace(string(body))); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// BroadcastTx implements Backend using POST /tx
+func (e *Esplora) BroadcastTx(ctx context.Context, transaction *tx.Transaction) ([32]byte, error) {
+	body, err := e.do(ctx, http.MethodPost, "/tx", hex.EncodeToString(transaction.Serialize()))
+	if err != nil {
+		return [32]byte{}, err
+	}
+	txid, err := ParseHash(strings.TrimSpace(string(body)))
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if txid != transaction.TxID() {
+		return [32]byte{}, fmt.Errorf("esplora accepted txid %s, expected %s", FormatHash(txid), transaction.TxIDHex())
+	}
+	return txid, nil
+}
+
+// esploraUTXO is one entry of /address/:address/utxo
+type esploraUTXO struct {
+	TxID   string `json:"txid"`
+	Vout   uint32 `json:"vout"`
+	Value  int64  `json:"value"`
+	Status struct {
+		Confirmed   bool  `json:"confirmed"`
+		BlockHeight int64 `json:"block_height"`
+	} `json:"status"`
+}
+
+// GetUTXOs implements Backend using GET /address/:address/utxo
+func (e *Esplora) GetUTXOs(ctx context.Context, address string) ([]UTXO, error) {
+	body, err := e.do(ctx, http.MethodGet, "/address/"+url.PathEscape(address)+"/utxo", "")
+	if err != nil {
+		return nil, err
+	}
+	var raw []esploraUTXO
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("invalid utxo list: %w", err)
+	}
+	utxos := make([]UTXO, len(raw))
+	for i, u := range raw {
+		txid, err := ParseHash(u.TxID)
+		if err != nil {
+			return nil, err
+		}
+		utxos[i] = UTXO{OutPoint: tx.OutPoint{Hash: txid, Index: u.Vout}, Value: u.Value}
+		if u.Status.Confirmed {
+			utxos[i].Height = u.Status.BlockHeight
+		}
+	}
+	return utxos, nil
+}
+
+// do performs a request and returns the body of a 200 response
+func (e *Esplora) do(ctx context.Context, method, path, body string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "text/plain")
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("esplora %s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
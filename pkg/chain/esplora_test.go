@@ -0,0 +1,97 @@
+package chain
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newEsploraServer serves a test block through the Esplora endpoints
+func newEsploraServer(t *testing.T, block *testBlock) *httptest.Server {
+	t.Helper()
+	blockHash := FormatHash(block.header.BlockHash())
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /block/{hash}/header", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("hash") != blockHash {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, hex.EncodeToString(block.header.Serialize()))
+	})
+	mux.HandleFunc("GET /tx/{txid}/merkle-proof", func(w http.ResponseWriter, r *http.Request) {
+		for i, id := range block.txids {
+			if FormatHash(id) == r.PathValue("txid") {
+				p := block.proof(i)
+				merkle := make([]string, len(p.Siblings))
+				for j, s := range p.Siblings {
+					merkle[j] = FormatHash(s)
+				}
+				json.NewEncoder(w).Encode(map[string]any{"block_height": 100, "merkle": merkle, "pos": i})
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("GET /block-height/100", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, blockHash)
+	})
+	mux.HandleFunc("POST /tx", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != hex.EncodeToString(block.txs[0].Serialize()) {
+			http.Error(w, "sendrawtransaction RPC error: bad-txns", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, block.txs[0].TxIDHex())
+	})
+	mux.HandleFunc("GET /address/{addr}/utxo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"txid":%q,"vout":0,"value":1000,"status":{"confirmed":true,"block_height":100}},
+			{"txid":%q,"vout":1,"value":2500,"status":{"confirmed":false}}]`, FormatHash(block.txids[0]), FormatHash(block.txids[1]))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEsplora(t *testing.T) {
+	ctx := context.Background()
+	block := newTestBlock(5)
+	backend := NewEsplora(newEsploraServer(t, block).URL+"/", nil)
+
+	header, height, err := VerifyInclusion(ctx, backend, block.txids[3])
+	if err != nil {
+		t.Fatalf("VerifyInclusion failed: %v", err)
+	}
+	if header.BlockHash() != block.header.BlockHash() || height != 100 {
+		t.Errorf("Unexpected header or height %d", height)
+	}
+
+	txid, err := backend.BroadcastTx(ctx, block.txs[0])
+	if err != nil || txid != block.txids[0] {
+		t.Errorf("BroadcastTx returned %x, %v", txid, err)
+	}
+	if _, err := backend.BroadcastTx(ctx, block.txs[1]); err == nil {
+		t.Error("Expected a rejected broadcast to fail")
+	}
+
+	utxos, err := backend.GetUTXOs(ctx, "bcrt1qtest")
+	if err != nil {
+		t.Fatalf("GetUTXOs failed: %v", err)
+	}
+	if len(utxos) != 2 || utxos[0].OutPoint.Hash != block.txids[0] || utxos[0].Height != 100 ||
+		utxos[1].Value != 2500 || utxos[1].OutPoint.Index != 1 || utxos[1].Height != 0 {
+		t.Errorf("Unexpected UTXOs %+v", utxos)
+	}
+
+	if _, err := backend.GetHeader(ctx, [32]byte{0x01}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown block, got %v", err)
+	}
+	if _, err := backend.GetMerkleProof(ctx, [32]byte{0x01}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown transaction, got %v", err)
+	}
+}
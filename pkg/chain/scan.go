@@ -0,0 +1,206 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Gap-limit address scanning
+//
+// Restoring a wallet from its seed means finding which of its addresses were
+// used. Addresses are derived in order, and wallets hand them out in order, so
+// a scanner walks the indices and stops after GapLimit consecutive unused ones
+// (BIP44 uses 20). Scan does that against a Backend:
+//
+//	index:  0  1  2  3  4  ...  22
+//	used:   x  .  x  .  .  ...  .     3..22 are 20 unused in a row: stop, next unused index is 3
+//
+// Addresses come from an AddressFunc, so the scanner works with any derivation
+// (BIP32 path, descriptor, test fixture). Lookups run in batches of
+// WithScanConcurrency addresses; after each batch the scanner reports a ScanCheckpoint, from
+// which WithResume continues an interrupted scan.
+//
+// Backend.GetUTXOs only reports unspent outputs, so an address whose coins
+// were all spent looks unused. Use a gap limit larger than the longest run of
+// such addresses the wallet may have.
+
+const (
+	// DefaultGapLimit is the BIP44 gap limit
+	DefaultGapLimit = 20
+	// DefaultScanConcurrency is the number of backend lookups in flight
+	DefaultScanConcurrency = 4
+)
+
+// AddressFunc derives the address at an index of one chain (receive or change)
+type AddressFunc func(index uint32) (string, error)
+
+// ScanResult is a used address found by Scan
+type ScanResult struct {
+	Index   uint32
+	Address string
+	UTXOs   []UTXO
+}
+
+// ScanCheckpoint is the position of a scan, enough to resume it
+type ScanCheckpoint struct {
+	Next     uint32 `json:"next"`      // First index not yet checked
+	LastUsed int64  `json:"last_used"` // Highest used index so far, -1 if none
+}
+
+// ScanProgress is reported after every checked address
+type ScanProgress struct {
+	Index   uint32
+	Address string
+	Used    bool
+	Gap     int // Consecutive unused addresses up to and including this one
+}
+
+// ScanReport is the outcome of a scan
+type ScanReport struct {
+	Used       []ScanResult   // Used addresses found by this run, in index order
+	NextUnused uint32         // First index after the last used one: the next address to hand out
+	Checkpoint ScanCheckpoint // Final position
+}
+
+// scanOptions holds the settings selected with ScanOption
+type scanOptions struct {
+	gapLimit     int
+	concurrency  int
+	resume       ScanCheckpoint
+	onProgress   func(ScanProgress)
+	onCheckpoint func(ScanCheckpoint)
+}
+
+// ScanOption configures Scan
+type ScanOption func(*scanOptions)
+
+// WithGapLimit sets the number of consecutive unused addresses that ends the scan
+func WithGapLimit(n int) ScanOption {
+	return func(o *scanOptions) {
+		o.gapLimit = n
+	}
+}
+
+// WithScanConcurrency sets how many backend lookups run at once
+func WithScanConcurrency(n int) ScanOption {
+	return func(o *scanOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithResume continues a scan from a checkpoint
+func WithResume(checkpoint ScanCheckpoint) ScanOption {
+	return func(o *scanOptions) {
+		o.resume = checkpoint
+	}
+}
+
+// WithProgress is called after every checked address, in index order
+func WithProgress(fn func(ScanProgress)) ScanOption {
+	return func(o *scanOptions) {
+		o.onProgress = fn
+	}
+}
+
+// WithCheckpoint is called after every batch, e.g. to persist the position
+func WithCheckpoint(fn func(ScanCheckpoint)) ScanOption {
+	return func(o *scanOptions) {
+		o.onCheckpoint = fn
+	}
+}
+
+// Scan finds the used addresses of one derivation chain
+//
+// Example:
+//
+//	report, err := chain.Scan(ctx, backend, receiveAddress,
+//		chain.WithGapLimit(20),
+//		chain.WithCheckpoint(func(cp chain.ScanCheckpoint) { save(cp) }))
+//	// report.Used: addresses with funds, report.NextUnused: next receive index
+func Scan(ctx context.Context, backend Backend, derive AddressFunc, opts ...ScanOption) (*ScanReport, error) {
+	// Step 1: Validate inputs
+	if backend == nil || derive == nil {
+		return nil, errors.New("backend and address function cannot be nil")
+	}
+	options := scanOptions{
+		gapLimit:    DefaultGapLimit,
+		concurrency: DefaultScanConcurrency,
+		resume:      ScanCheckpoint{LastUsed: -1},
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.gapLimit < 1 || options.concurrency < 1 {
+		return nil, fmt.Errorf("gap limit and concurrency must be positive, got %d and %d", options.gapLimit, options.concurrency)
+	}
+	if options.resume.LastUsed < -1 || options.resume.LastUsed >= int64(options.resume.Next) {
+		return nil, fmt.Errorf("invalid checkpoint: last used %d, next %d", options.resume.LastUsed, options.resume.Next)
+	}
+
+	report := &ScanReport{Checkpoint: options.resume}
+	cp := &report.Checkpoint
+	for int64(cp.Next)-cp.LastUsed-1 < int64(options.gapLimit) {
+		// Step 2: Look up the next batch concurrently
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		batch, err := scanBatch(ctx, backend, derive, cp.Next, options.concurrency)
+		if err != nil {
+			return nil, err
+		}
+
+		// Step 3: Apply the results in index order, stopping at the gap limit
+		for _, r := range batch {
+			used := len(r.UTXOs) > 0
+			if used {
+				cp.LastUsed = int64(r.Index)
+				report.Used = append(report.Used, r)
+			}
+			cp.Next = r.Index + 1
+			gap := int(int64(cp.Next) - cp.LastUsed - 1)
+			if options.onProgress != nil {
+				options.onProgress(ScanProgress{Index: r.Index, Address: r.Address, Used: used, Gap: gap})
+			}
+			if gap >= options.gapLimit {
+				break
+			}
+		}
+		if options.onCheckpoint != nil {
+			options.onCheckpoint(*cp)
+		}
+	}
+	report.NextUnused = uint32(cp.LastUsed + 1)
+	return report, nil
+}
+
+// scanBatch derives and looks up n addresses starting at first
+func scanBatch(ctx context.Context, backend Backend, derive AddressFunc, first uint32, n int) ([]ScanResult, error) {
+	results := make([]ScanResult, n)
+	errs := make([]error, n)
+	for i := range results {
+		results[i].Index = first + uint32(i)
+		address, err := derive(results[i].Index)
+		if err != nil {
+			return nil, fmt.Errorf("deriving address %d: %w", results[i].Index, err)
+		}
+		results[i].Address = address
+	}
+
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i].UTXOs, errs[i] = backend.GetUTXOs(ctx, results[i].Address)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("looking up %s: %w", results[i].Address, err)
+		}
+	}
+	return results, nil
+}
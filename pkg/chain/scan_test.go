@@ -0,0 +1,163 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+// scanBackend reports funds on a fixed set of addresses and counts lookups
+type scanBackend struct {
+	fakeBackend
+	funded map[string]bool
+
+	mu       sync.Mutex
+	lookups  int
+	inFlight int
+	maxSeen  int
+	failAt   string
+}
+
+func (b *scanBackend) GetUTXOs(_ context.Context, address string) ([]UTXO, error) {
+	b.mu.Lock()
+	b.lookups++
+	b.inFlight++
+	if b.inFlight > b.maxSeen {
+		b.maxSeen = b.inFlight
+	}
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		b.inFlight--
+		b.mu.Unlock()
+	}()
+
+	if address == b.failAt {
+		return nil, errors.New("backend unavailable")
+	}
+	if !b.funded[address] {
+		return nil, nil
+	}
+	return []UTXO{{OutPoint: tx.OutPoint{Index: 0}, Value: 1000}}, nil
+}
+
+// testAddress is the AddressFunc of the tests
+func testAddress(index uint32) (string, error) {
+	return fmt.Sprintf("addr-%d", index), nil
+}
+
+// fundedAt returns a backend with funds on the given indices
+func fundedAt(indices ...int) *scanBackend {
+	b := &scanBackend{funded: make(map[string]bool)}
+	for _, i := range indices {
+		b.funded[fmt.Sprintf("addr-%d", i)] = true
+	}
+	return b
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name        string
+		funded      []int
+		gapLimit    int
+		concurrency int
+		used        []uint32
+		nextUnused  uint32
+		checked     uint32
+	}{
+		{"empty wallet", nil, 5, 2, nil, 0, 5},
+		{"within the gap", []int{0, 2, 6}, 5, 3, []uint32{0, 2, 6}, 7, 12},
+		{"beyond the gap is missed", []int{0, 7}, 5, 1, []uint32{0}, 1, 6},
+		{"batch larger than the gap", []int{1}, 3, 10, []uint32{1}, 2, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := fundedAt(tt.funded...)
+			var progress []ScanProgress
+			report, err := Scan(context.Background(), backend, testAddress,
+				WithGapLimit(tt.gapLimit), WithScanConcurrency(tt.concurrency),
+				WithProgress(func(p ScanProgress) { progress = append(progress, p) }))
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if len(report.Used) != len(tt.used) {
+				t.Fatalf("Expected %d used addresses, got %d", len(tt.used), len(report.Used))
+			}
+			for i, r := range report.Used {
+				if r.Index != tt.used[i] || r.Address != fmt.Sprintf("addr-%d", tt.used[i]) || len(r.UTXOs) != 1 {
+					t.Errorf("Unexpected result %d: %+v", i, r)
+				}
+			}
+			if report.NextUnused != tt.nextUnused {
+				t.Errorf("Expected next unused %d, got %d", tt.nextUnused, report.NextUnused)
+			}
+			if report.Checkpoint.Next != tt.checked || uint32(len(progress)) != tt.checked {
+				t.Errorf("Expected %d checked addresses, got checkpoint %d and %d progress calls", tt.checked, report.Checkpoint.Next, len(progress))
+			}
+			if last := progress[len(progress)-1]; last.Gap != tt.gapLimit {
+				t.Errorf("Expected the scan to end at gap %d, got %d", tt.gapLimit, last.Gap)
+			}
+			if backend.maxSeen > tt.concurrency {
+				t.Errorf("Expected at most %d lookups at once, saw %d", tt.concurrency, backend.maxSeen)
+			}
+		})
+	}
+}
+
+// TestScanResume tests that a failed scan continues from its last checkpoint
+func TestScanResume(t *testing.T) {
+	backend := fundedAt(1, 4, 9)
+	backend.failAt = "addr-8"
+
+	var checkpoints []ScanCheckpoint
+	_, err := Scan(context.Background(), backend, testAddress, WithGapLimit(5), WithScanConcurrency(2),
+		WithCheckpoint(func(cp ScanCheckpoint) { checkpoints = append(checkpoints, cp) }))
+	if err == nil {
+		t.Fatal("Expected the backend error")
+	}
+	last := checkpoints[len(checkpoints)-1]
+	if last != (ScanCheckpoint{Next: 8, LastUsed: 4}) {
+		t.Fatalf("Unexpected checkpoint %+v", last)
+	}
+
+	// Resuming only looks up the remaining addresses
+	backend.failAt = ""
+	backend.lookups = 0
+	report, err := Scan(context.Background(), backend, testAddress, WithGapLimit(5), WithScanConcurrency(2), WithResume(last))
+	if err != nil {
+		t.Fatalf("Resumed scan failed: %v", err)
+	}
+	if len(report.Used) != 1 || report.Used[0].Index != 9 || report.NextUnused != 10 {
+		t.Errorf("Expected to find index 9 and next unused 10, got %+v", report)
+	}
+	if backend.lookups != 8 {
+		t.Errorf("Expected 8 lookups (8..15 in batches of 2), got %d", backend.lookups)
+	}
+}
+
+func TestScanErrors(t *testing.T) {
+	backend := fundedAt()
+	ctx := context.Background()
+	if _, err := Scan(ctx, nil, testAddress); err == nil {
+		t.Error("Expected error for a nil backend")
+	}
+	if _, err := Scan(ctx, backend, testAddress, WithGapLimit(0)); err == nil {
+		t.Error("Expected error for a zero gap limit")
+	}
+	if _, err := Scan(ctx, backend, testAddress, WithResume(ScanCheckpoint{Next: 3, LastUsed: 3})); err == nil {
+		t.Error("Expected error for an inconsistent checkpoint")
+	}
+	failing := func(uint32) (string, error) { return "", errors.New("no key") }
+	if _, err := Scan(ctx, backend, failing); err == nil {
+		t.Error("Expected the derivation error")
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := Scan(cancelled, backend, testAddress); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
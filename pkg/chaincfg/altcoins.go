@@ -0,0 +1,68 @@
+package chaincfg
+
+// Presets for Bitcoin-derived altcoins
+//
+// Only the encoding constants are provided (address, WIF, bech32 and BIP32
+// prefixes, genesis hash, port); consensus rules are out of scope. Dogecoin has
+// no segwit, so its Bech32HRPSegwit is empty.
+
+var (
+	// LitecoinMainNetParams are the parameters of the Litecoin main network
+	LitecoinMainNetParams = Params{
+		Name:             "litecoin",
+		DefaultPort:      "9333",
+		GenesisHash:      displayHash("12a765e31ffd4059bada1e25190f6e98c99d9714d334efa41a195a7e7e04bfe2"),
+		PubKeyHashAddrID: 0x30, // starts with L
+		ScriptHashAddrID: 0x32, // starts with M
+		PrivateKeyID:     0xb0, // starts with 6 (uncompressed) or T (compressed)
+		Bech32HRPSegwit:  "ltc",
+		HDPrivateKeyID:   [4]byte{0x04, 0x88, 0xad, 0xe4}, // xprv, as used by Litecoin Core
+		HDPublicKeyID:    [4]byte{0x04, 0x88, 0xb2, 0x1e}, // xpub
+		HDCoinType:       2,
+	}
+
+	// LitecoinTestNetParams are the parameters of the Litecoin test network
+	LitecoinTestNetParams = Params{
+		Name:             "litecoin-testnet",
+		DefaultPort:      "19335",
+		GenesisHash:      displayHash("4966625a4b2851d9fdee139e56211a0d88575f59ed816ff5e6a63deb4e3e29a0"),
+		PubKeyHashAddrID: 0x6f, // starts with m or n
+		ScriptHashAddrID: 0x3a, // starts with Q
+		PrivateKeyID:     0xef,
+		Bech32HRPSegwit:  "tltc",
+		HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94}, // tprv
+		HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf}, // tpub
+		HDCoinType:       1,
+	}
+
+	// DogecoinMainNetParams are the parameters of the Dogecoin main network
+	DogecoinMainNetParams = Params{
+		Name:             "dogecoin",
+		DefaultPort:      "22556",
+		GenesisHash:      displayHash("1a91e3dace36e2be3bf030a65679fe821aa1d6ef92e7c9902eb318182c355691"),
+		PubKeyHashAddrID: 0x1e,                            // starts with D
+		ScriptHashAddrID: 0x16,                            // starts with 9 or A
+		PrivateKeyID:     0x9e,                            // starts with 6 (uncompressed) or Q (compressed)
+		HDPrivateKeyID:   [4]byte{0x02, 0xfa, 0xc3, 0x98}, // dgpv
+		HDPublicKeyID:    [4]byte{0x02, 0xfa, 0xca, 0xfd}, // dgub
+		HDCoinType:       3,
+	}
+
+	// DogecoinTestNetParams are the parameters of the Dogecoin test network
+	DogecoinTestNetParams = Params{
+		Name:             "dogecoin-testnet",
+		DefaultPort:      "44556",
+		GenesisHash:      displayHash("bb0a78264637406b6360aad926284d544d7049f45189db5664f3c4d07350559e"),
+		PubKeyHashAddrID: 0x71, // starts with n
+		ScriptHashAddrID: 0xc4, // starts with 2
+		PrivateKeyID:     0xf1,
+		HDPrivateKeyID:   [4]byte{0x04, 0x32, 0xa2, 0x43}, // tgpv
+		HDPublicKeyID:    [4]byte{0x04, 0x32, 0xa9, 0xa8}, // tgub
+		HDCoinType:       1,
+	}
+)
+
+// SupportsSegwit reports whether the network has native segwit addresses
+func (p *Params) SupportsSegwit() bool {
+	return p.Bech32HRPSegwit != ""
+}
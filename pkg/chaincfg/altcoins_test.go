@@ -0,0 +1,62 @@
+package chaincfg
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+)
+
+// TestAltcoinGenesisHashes recomputes the Litecoin and Dogecoin genesis block hashes
+//
+// Both chains use scrypt for proof of work, but block hashes are still SHA256D of the header.
+func TestAltcoinGenesisHashes(t *testing.T) {
+	tests := []struct {
+		params     *Params
+		merkleRoot string
+		timestamp  uint32
+		bits       uint32
+		nonce      uint32
+	}{
+		{&LitecoinMainNetParams, "97ddfbbae6be97fd6cdf3e7ca13232a3afff2353e29badfab7f73011edd4ced9", 1317972665, 0x1e0ffff0, 2084524493},
+		{&DogecoinMainNetParams, "5b2a3f53f605d62c53e62932dac6925e3d74afa5a4b459745c36d42d0ed26a69", 1386325540, 0x1e0ffff0, 99943},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.params.Name, func(t *testing.T) {
+			header := make([]byte, 80)
+			binary.LittleEndian.PutUint32(header[0:4], 1)
+			merkleRoot := displayHash(tt.merkleRoot)
+			copy(header[36:68], merkleRoot[:])
+			binary.LittleEndian.PutUint32(header[68:72], tt.timestamp)
+			binary.LittleEndian.PutUint32(header[72:76], tt.bits)
+			binary.LittleEndian.PutUint32(header[76:80], tt.nonce)
+
+			if got := hash.SHA256D(header); got != tt.params.GenesisHash {
+				t.Errorf("Genesis hash = %x, expected %s", hash.Reverse32(got), tt.params.GenesisHashHex())
+			}
+		})
+	}
+}
+
+// TestAltcoinRegistry tests that the presets are registered and flag segwit support
+func TestAltcoinRegistry(t *testing.T) {
+	tests := []struct {
+		name   string
+		segwit bool
+	}{
+		{"litecoin", true},
+		{"litecoin-testnet", true},
+		{"dogecoin", false},
+		{"dogecoin-testnet", false},
+	}
+	for _, tt := range tests {
+		params, err := ParamsForName(tt.name)
+		if err != nil {
+			t.Fatalf("ParamsForName(%s) error = %v", tt.name, err)
+		}
+		if params.SupportsSegwit() != tt.segwit {
+			t.Errorf("%s SupportsSegwit() = %v, expected %v", tt.name, params.SupportsSegwit(), tt.segwit)
+		}
+	}
+}
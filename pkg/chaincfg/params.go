@@ -0,0 +1,245 @@
+package chaincfg
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Params holds the constants that differ between networks
+//
+// Every encoding that embeds a network marker reads it from here: Base58Check
+// address and WIF version bytes, the bech32 human-readable part, the BIP32
+// extended key version bytes and the genesis block hash.
+//
+// Example:
+//
+//	params, _ := ParamsForName("signet")
+//	address := base58.Base58CheckEncode(params.PubKeyHashAddrID, pubKeyHash[:])
+//	// Result: "m..." or "n..." (testnet-style address)
+type Params struct {
+	// Name is the registry key, e.g. "mainnet" or "testnet4"
+	Name string
+	// DefaultPort is the P2P port of the reference implementation
+	DefaultPort string
+
+	// GenesisHash is the hash of the genesis block in internal byte order
+	// (reverse it for the hex shown by block explorers)
+	GenesisHash [32]byte
+
+	// PubKeyHashAddrID is the Base58Check version byte of P2PKH addresses
+	PubKeyHashAddrID byte
+	// ScriptHashAddrID is the Base58Check version byte of P2SH addresses
+	ScriptHashAddrID byte
+	// PrivateKeyID is the Base58Check version byte of WIF private keys
+	PrivateKeyID byte
+	// Bech32HRPSegwit is the human-readable part of segwit addresses
+	Bech32HRPSegwit string
+
+	// HDPrivateKeyID is the BIP32 version of extended private keys (xprv/tprv)
+	HDPrivateKeyID [4]byte
+	// HDPublicKeyID is the BIP32 version of extended public keys (xpub/tpub)
+	HDPublicKeyID [4]byte
+	// HDCoinType is the BIP44 coin type used in derivation paths
+	HDCoinType uint32
+}
+
+// GenesisHashHex returns the genesis block hash in display (big-endian) hex
+func (p *Params) GenesisHashHex() string {
+	display := make([]byte, 32)
+	for i := range p.GenesisHash {
+		display[i] = p.GenesisHash[31-i]
+	}
+	return hex.EncodeToString(display)
+}
+
+// displayHash parses a block hash in display hex into internal byte order
+//
+// Only used with the compile-time constants below, so invalid input panics.
+func displayHash(s string) [32]byte {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 32 {
+		panic("chaincfg: invalid hash literal " + s)
+	}
+	var out [32]byte
+	for i := range b {
+		out[i] = b[31-i]
+	}
+	return out
+}
+
+var (
+	// MainNetParams are the parameters of the Bitcoin main network
+	MainNetParams = Params{
+		Name:             "mainnet",
+		DefaultPort:      "8333",
+		GenesisHash:      displayHash("000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"),
+		PubKeyHashAddrID: 0x00, // starts with 1
+		ScriptHashAddrID: 0x05, // starts with 3
+		PrivateKeyID:     0x80, // starts with 5 (uncompressed) or K/L (compressed)
+		Bech32HRPSegwit:  "bc",
+		HDPrivateKeyID:   [4]byte{0x04, 0x88, 0xad, 0xe4}, // xprv
+		HDPublicKeyID:    [4]byte{0x04, 0x88, 0xb2, 0x1e}, // xpub
+		HDCoinType:       0,
+	}
+
+	// TestNet3Params are the parameters of the (legacy) test network, version 3
+	TestNet3Params = Params{
+		Name:             "testnet3",
+		DefaultPort:      "18333",
+		GenesisHash:      displayHash("000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943"),
+		PubKeyHashAddrID: 0x6f, // starts with m or n
+		ScriptHashAddrID: 0xc4, // starts with 2
+		PrivateKeyID:     0xef, // starts with 9 (uncompressed) or c (compressed)
+		Bech32HRPSegwit:  "tb",
+		HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94}, // tprv
+		HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf}, // tpub
+		HDCoinType:       1,
+	}
+
+	// TestNet4Params are the parameters of the test network, version 4 (BIP94)
+	TestNet4Params = Params{
+		Name:             "testnet4",
+		DefaultPort:      "48333",
+		GenesisHash:      displayHash("00000000da84f2bafbbc53dee25a72ae507ff4914b867c565be350b0da8bf043"),
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0xc4,
+		PrivateKeyID:     0xef,
+		Bech32HRPSegwit:  "tb",
+		HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94},
+		HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf},
+		HDCoinType:       1,
+	}
+
+	// SigNetParams are the parameters of the default signet (BIP325)
+	SigNetParams = Params{
+		Name:             "signet",
+		DefaultPort:      "38333",
+		GenesisHash:      displayHash("00000008819873e925422c1ff0f99f7cc9bbb232af63a077a480a3633bee1ef6"),
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0xc4,
+		PrivateKeyID:     0xef,
+		Bech32HRPSegwit:  "tb",
+		HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94},
+		HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf},
+		HDCoinType:       1,
+	}
+
+	// RegressionNetParams are the parameters of the local regression test network
+	RegressionNetParams = Params{
+		Name:             "regtest",
+		DefaultPort:      "18444",
+		GenesisHash:      displayHash("0f9188f13cb7b2c71f2a335e3a4fc328bf5beb436012afca590b1a11466e2206"),
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0xc4,
+		PrivateKeyID:     0xef,
+		Bech32HRPSegwit:  "bcrt",
+		HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94},
+		HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf},
+		HDCoinType:       1,
+	}
+)
+
+var (
+	// ErrDuplicateNet is returned when registering a network name twice
+	ErrDuplicateNet = errors.New("duplicate network")
+	// ErrUnknownNet is returned when looking up a network that is not registered
+	ErrUnknownNet = errors.New("unknown network")
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Params{}
+)
+
+func init() {
+	for _, p := range []*Params{
+		&MainNetParams, &TestNet3Params, &TestNet4Params, &SigNetParams, &RegressionNetParams,
+		&LitecoinMainNetParams, &LitecoinTestNetParams, &DogecoinMainNetParams, &DogecoinTestNetParams,
+	} {
+		if err := Register(p); err != nil {
+			panic(err)
+		}
+	}
+	// "testnet" is the conventional alias of testnet3
+	registry["testnet"] = &TestNet3Params
+}
+
+// Register adds a network to the registry so it can be looked up by name
+//
+// Example:
+//
+//	custom := chaincfg.RegressionNetParams
+//	custom.Name = "mynet"
+//	err := chaincfg.Register(&custom)
+func Register(params *Params) error {
+	if params == nil || params.Name == "" {
+		return errors.New("params must have a name")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name := strings.ToLower(params.Name)
+	if _, exists := registry[name]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateNet, params.Name)
+	}
+	registry[name] = params
+	return nil
+}
+
+// ParamsForName looks up a registered network by name (case-insensitive)
+//
+// Example:
+//
+//	params, err := ParamsForName("testnet4")
+//	// Result: params == &TestNet4Params
+func ParamsForName(name string) (*Params, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	params, ok := registry[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNet, name)
+	}
+	return params, nil
+}
+
+// Networks returns the sorted names of all registered networks
+func Networks() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParamsForPrivateKeyID returns the registered networks whose WIF version byte is id, sorted by name
+//
+// Test networks share one version byte, so there can be several; aliases
+// such as "testnet" are not repeated.
+//
+// Example:
+//
+//	nets := ParamsForPrivateKeyID(0xb0)
+//	// Result: [&LitecoinMainNetParams]
+func ParamsForPrivateKeyID(id byte) []*Params {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var matches []*Params
+	for name, params := range registry {
+		if params.PrivateKeyID == id && name == strings.ToLower(params.Name) {
+			matches = append(matches, params)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Name < matches[j].Name })
+	return matches
+}
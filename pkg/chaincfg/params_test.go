@@ -0,0 +1,134 @@
+package chaincfg
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+)
+
+// genesisHeader serializes a genesis block header (all share the same coinbase merkle root)
+func genesisHeader(timestamp, bits, nonce uint32) []byte {
+	header := make([]byte, 80)
+	binary.LittleEndian.PutUint32(header[0:4], 1)
+	// header[4:36] previous block hash = 0
+	merkleRoot := displayHash("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+	copy(header[36:68], merkleRoot[:])
+	binary.LittleEndian.PutUint32(header[68:72], timestamp)
+	binary.LittleEndian.PutUint32(header[72:76], bits)
+	binary.LittleEndian.PutUint32(header[76:80], nonce)
+	return header
+}
+
+// TestGenesisHashes recomputes the genesis block hashes from their headers
+func TestGenesisHashes(t *testing.T) {
+	tests := []struct {
+		params *Params
+		header []byte
+	}{
+		{&MainNetParams, genesisHeader(1231006505, 0x1d00ffff, 2083236893)},
+		{&TestNet3Params, genesisHeader(1296688602, 0x1d00ffff, 414098458)},
+		{&RegressionNetParams, genesisHeader(1296688602, 0x207fffff, 2)},
+		{&SigNetParams, genesisHeader(1598918400, 0x1e0377ae, 52613770)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.params.Name, func(t *testing.T) {
+			if got := hash.SHA256D(tt.header); got != tt.params.GenesisHash {
+				t.Errorf("Genesis hash = %x, expected %s", hash.Reverse32(got), tt.params.GenesisHashHex())
+			}
+		})
+	}
+
+	if MainNetParams.GenesisHashHex() != "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f" {
+		t.Errorf("Unexpected mainnet genesis hex %s", MainNetParams.GenesisHashHex())
+	}
+}
+
+// TestParamsForName tests registry lookups
+func TestParamsForName(t *testing.T) {
+	tests := map[string]*Params{
+		"mainnet":  &MainNetParams,
+		"testnet":  &TestNet3Params,
+		"testnet3": &TestNet3Params,
+		"TestNet4": &TestNet4Params,
+		"signet":   &SigNetParams,
+		"regtest":  &RegressionNetParams,
+	}
+	for name, expected := range tests {
+		params, err := ParamsForName(name)
+		if err != nil {
+			t.Fatalf("ParamsForName(%s) error = %v", name, err)
+		}
+		if params != expected {
+			t.Errorf("ParamsForName(%s) = %s, expected %s", name, params.Name, expected.Name)
+		}
+	}
+
+	if _, err := ParamsForName("nonexistent"); !errors.Is(err, ErrUnknownNet) {
+		t.Errorf("Expected ErrUnknownNet, got %v", err)
+	}
+}
+
+// TestRegister tests adding custom networks
+func TestRegister(t *testing.T) {
+	custom := RegressionNetParams
+	custom.Name = "testregister"
+	custom.Bech32HRPSegwit = "tr"
+	if err := Register(&custom); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	params, err := ParamsForName("testregister")
+	if err != nil || params.Bech32HRPSegwit != "tr" {
+		t.Errorf("Registered network not found: %v", err)
+	}
+
+	if err := Register(&custom); !errors.Is(err, ErrDuplicateNet) {
+		t.Errorf("Expected ErrDuplicateNet, got %v", err)
+	}
+	if err := Register(&Params{}); err == nil {
+		t.Error("Expected error for unnamed params")
+	}
+
+	found := false
+	for _, name := range Networks() {
+		found = found || name == "testregister"
+	}
+	if !found {
+		t.Error("Networks() does not include the registered network")
+	}
+}
+
+func TestParamsForPrivateKeyID(t *testing.T) {
+	tests := []struct {
+		id       byte
+		expected []*Params
+	}{
+		{0x80, []*Params{&MainNetParams}},
+		{0xb0, []*Params{&LitecoinMainNetParams}},
+		{0x9e, []*Params{&DogecoinMainNetParams}},
+		{0x01, nil},
+	}
+	for _, tt := range tests {
+		if got := ParamsForPrivateKeyID(tt.id); !slices.Equal(got, tt.expected) {
+			t.Errorf("ParamsForPrivateKeyID(0x%02x) = %v, expected %v", tt.id, got, tt.expected)
+		}
+	}
+
+	// The test networks share 0xef; the "testnet" alias is not listed twice
+	seen := map[string]bool{}
+	for _, params := range ParamsForPrivateKeyID(0xef) {
+		if seen[params.Name] {
+			t.Errorf("%s listed twice", params.Name)
+		}
+		seen[params.Name] = true
+	}
+	for _, name := range []string{"testnet3", "testnet4", "signet", "regtest", "litecoin-testnet"} {
+		if !seen[name] {
+			t.Errorf("Expected %s among the 0xef networks", name)
+		}
+	}
+}
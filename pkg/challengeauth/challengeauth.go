@@ -0,0 +1,278 @@
+package challengeauth
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// "Log in with your key" challenge-response authentication
+//
+// The server issues a random challenge naming its domain and an expiry; the
+// client signs it with its secp256k1 key (BIP340) and sends back the signature
+// and its x-only key; the server checks the signature and consumes the
+// challenge, so each one authenticates exactly one response:
+//
+//	statement = TaggedHash("cryptography-playground/challengeauth/v1",
+//	                       len(domain) || domain || nonce || expiry)
+//
+// The domain stops a malicious site from relaying another site's challenge to
+// the user (the client refuses to sign challenges for a domain it did not
+// expect), the expiry bounds how long an unanswered challenge is kept, and the
+// single use of every nonce stops a captured response from being replayed.
+
+// statementTag is the tagged hash domain of the signed statement
+const statementTag = "cryptography-playground/challengeauth/v1"
+
+const (
+	// DefaultChallengeTTL is how long a client has to answer a challenge
+	DefaultChallengeTTL = 2 * time.Minute
+	// DefaultMaxPending bounds the number of unanswered challenges kept in memory
+	DefaultMaxPending = 10000
+)
+
+var (
+	// ErrUnknownChallenge is returned for responses to challenges that were never issued or already used
+	ErrUnknownChallenge = errors.New("unknown or already used challenge")
+	// ErrChallengeExpired is returned for responses that arrive after the challenge expired
+	ErrChallengeExpired = errors.New("challenge expired")
+	// ErrInvalidSignature is returned when the response signature does not verify
+	ErrInvalidSignature = errors.New("invalid challenge signature")
+	// ErrWrongDomain is returned by Respond for challenges issued by another domain
+	ErrWrongDomain = errors.New("challenge is for another domain")
+	// ErrTooManyChallenges is returned when too many challenges are pending
+	ErrTooManyChallenges = errors.New("too many pending challenges")
+)
+
+// Challenge is sent by the server to the client
+type Challenge struct {
+	Domain string    `json:"domain"`
+	Nonce  []byte    `json:"nonce"` // 32 random bytes
+	Expiry time.Time `json:"expiry"`
+}
+
+// Response is sent by the client to the server
+type Response struct {
+	Nonce     []byte                 `json:"nonce"` // Challenge.Nonce
+	PubKey    schnorr.XOnlyPublicKey `json:"pubkey"`
+	Signature []byte                 `json:"signature"` // BIP340 signature over the statement
+}
+
+// Statement returns the 32-byte digest the client signs
+func (c *Challenge) Statement() [32]byte {
+	var expiry [8]byte
+	binary.BigEndian.PutUint64(expiry[:], uint64(c.Expiry.Unix()))
+	domain := binary.BigEndian.AppendUint32(nil, uint32(len(c.Domain)))
+	return schnorr.TaggedHash(statementTag, domain, []byte(c.Domain), c.Nonce, expiry[:])
+}
+
+// Respond signs a challenge with the client key
+//
+// expectedDomain is the server the client believes it is talking to; a
+// challenge for any other domain is refused.
+//
+// Example:
+//
+//	resp, err := challengeauth.Respond(challenge, "example.com", userKey)
+func Respond(challenge *Challenge, expectedDomain string, priv *btcec.PrivateKey) (*Response, error) {
+	if priv == nil {
+		return nil, errors.New("private key cannot be nil")
+	}
+	return RespondWith(challenge, expectedDomain, schnorr.NewKeySigner(priv))
+}
+
+// RespondWith signs a challenge with a schnorr.Signer, e.g. a hardware wallet
+func RespondWith(challenge *Challenge, expectedDomain string, signer schnorr.Signer) (*Response, error) {
+	// Step 1: Validate inputs
+	if challenge == nil || signer == nil {
+		return nil, errors.New("challenge and signer cannot be nil")
+	}
+	if challenge.Domain != expectedDomain {
+		return nil, fmt.Errorf("%w: got %q, expected %q", ErrWrongDomain, challenge.Domain, expectedDomain)
+	}
+	if len(challenge.Nonce) != 32 {
+		return nil, fmt.Errorf("challenge nonce must be 32 bytes, got %d", len(challenge.Nonce))
+	}
+
+	// Step 2: Sign the statement
+	sig, err := schnorr.SignDigestWith(challenge.Statement(), signer)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{
+		Nonce:     append([]byte(nil), challenge.Nonce...),
+		PubKey:    signer.PublicKey(),
+		Signature: sig[:],
+	}, nil
+}
+
+// serverOptions holds the settings selected with Option
+type serverOptions struct {
+	ttl        time.Duration
+	maxPending int
+	now        func() time.Time
+	rand       io.Reader
+}
+
+// Option configures a Server
+type Option func(*serverOptions)
+
+// WithChallengeTTL sets how long a client has to answer a challenge
+func WithChallengeTTL(ttl time.Duration) Option {
+	return func(o *serverOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithMaxPending bounds the number of unanswered challenges
+func WithMaxPending(n int) Option {
+	return func(o *serverOptions) {
+		o.maxPending = n
+	}
+}
+
+// WithClock replaces time.Now (for tests)
+func WithClock(now func() time.Time) Option {
+	return func(o *serverOptions) {
+		o.now = now
+	}
+}
+
+// WithRandReader replaces crypto/rand as the source of challenge nonces
+func WithRandReader(r io.Reader) Option {
+	return func(o *serverOptions) {
+		o.rand = r
+	}
+}
+
+// Server issues challenges for one domain and verifies the responses
+//
+// A Server is safe for concurrent use. Pending challenges live in memory, so
+// a deployment with several instances must route a response to the instance
+// that issued its challenge.
+//
+// Example:
+//
+//	server := challengeauth.NewServer("example.com")
+//	challenge, _ := server.Issue()
+//	// ... send challenge, receive resp ...
+//	pubKey, err := server.Verify(resp)
+type Server struct {
+	domain  string
+	options serverOptions
+
+	mu      sync.Mutex
+	pending map[[32]byte]time.Time // nonce -> expiry
+}
+
+// NewServer creates a server for domain
+func NewServer(domain string, opts ...Option) *Server {
+	options := serverOptions{
+		ttl:        DefaultChallengeTTL,
+		maxPending: DefaultMaxPending,
+		now:        time.Now,
+		rand:       rand.Reader,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &Server{
+		domain:  domain,
+		options: options,
+		pending: make(map[[32]byte]time.Time),
+	}
+}
+
+// Issue creates a fresh challenge and remembers it until it is used or expires
+func (s *Server) Issue() (*Challenge, error) {
+	// Step 1: Random nonce
+	var nonce [32]byte
+	if _, err := io.ReadFull(s.options.rand, nonce[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+	now := s.options.now()
+	expiry := time.Unix(now.Add(s.options.ttl).Unix(), 0).UTC() // The statement has one-second precision
+
+	// Step 2: Remember it, making room by dropping expired challenges
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) >= s.options.maxPending {
+		s.collectLocked(now)
+		if len(s.pending) >= s.options.maxPending {
+			return nil, ErrTooManyChallenges
+		}
+	}
+	s.pending[nonce] = expiry
+	return &Challenge{Domain: s.domain, Nonce: nonce[:], Expiry: expiry}, nil
+}
+
+// Verify checks a response and returns the key that signed it
+//
+// The challenge is consumed whether or not the signature is valid, so every
+// challenge allows exactly one attempt.
+func (s *Server) Verify(resp *Response) (schnorr.XOnlyPublicKey, error) {
+	// Step 1: Validate the encoding
+	if resp == nil {
+		return schnorr.XOnlyPublicKey{}, errors.New("response cannot be nil")
+	}
+	if len(resp.Nonce) != 32 || len(resp.Signature) != 64 {
+		return schnorr.XOnlyPublicKey{}, ErrUnknownChallenge
+	}
+	nonce := [32]byte(resp.Nonce)
+
+	// Step 2: Consume the challenge
+	s.mu.Lock()
+	expiry, ok := s.pending[nonce]
+	delete(s.pending, nonce)
+	s.mu.Unlock()
+	if !ok {
+		return schnorr.XOnlyPublicKey{}, ErrUnknownChallenge
+	}
+	if !s.options.now().Before(expiry) {
+		return schnorr.XOnlyPublicKey{}, ErrChallengeExpired
+	}
+
+	// Step 3: Check the signature over the challenge as issued
+	pub, err := resp.PubKey.PublicKey()
+	if err != nil {
+		return schnorr.XOnlyPublicKey{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	challenge := Challenge{Domain: s.domain, Nonce: nonce[:], Expiry: expiry}
+	if !schnorr.VerifyDigest(challenge.Statement(), pub, [64]byte(resp.Signature)) {
+		return schnorr.XOnlyPublicKey{}, ErrInvalidSignature
+	}
+	return resp.PubKey, nil
+}
+
+// Pending returns the number of unanswered challenges
+func (s *Server) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+// Collect drops expired challenges and returns how many were dropped
+func (s *Server) Collect() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.collectLocked(s.options.now())
+}
+
+// collectLocked drops expired challenges; s.mu must be held
+func (s *Server) collectLocked(now time.Time) int {
+	removed := 0
+	for nonce, expiry := range s.pending {
+		if !now.Before(expiry) {
+			delete(s.pending, nonce)
+			removed++
+		}
+	}
+	return removed
+}
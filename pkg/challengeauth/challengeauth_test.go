@@ -0,0 +1,123 @@
+package challengeauth
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// newKey returns a deterministic test key
+func newKey(seed byte) *btcec.PrivateKey {
+	priv, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{seed}, 32))
+	return priv
+}
+
+// fakeClock is a settable clock for WithClock
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestChallengeResponse(t *testing.T) {
+	user := newKey(0x01)
+	server := NewServer("example.com")
+
+	challenge, err := server.Issue()
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	resp, err := Respond(challenge, "example.com", user)
+	if err != nil {
+		t.Fatalf("Respond failed: %v", err)
+	}
+	pubKey, err := server.Verify(resp)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if pubKey != schnorr.XOnlyFromPub(user.PubKey()) {
+		t.Errorf("Expected the user's key, got %s", pubKey)
+	}
+
+	// The same response cannot be replayed
+	if _, err := server.Verify(resp); !errors.Is(err, ErrUnknownChallenge) {
+		t.Errorf("Expected ErrUnknownChallenge on replay, got %v", err)
+	}
+	if server.Pending() != 0 {
+		t.Errorf("Expected no pending challenges, got %d", server.Pending())
+	}
+}
+
+func TestChallengeRejects(t *testing.T) {
+	user, other := newKey(0x01), newKey(0x02)
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	server := NewServer("example.com", WithClock(clock.Now), WithChallengeTTL(time.Minute))
+
+	// The client refuses challenges relayed from another site
+	evil := NewServer("evil.example")
+	relayed, _ := evil.Issue()
+	if _, err := Respond(relayed, "example.com", user); !errors.Is(err, ErrWrongDomain) {
+		t.Errorf("Expected ErrWrongDomain, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(c *Challenge, r *Response)
+		advance  time.Duration
+		expected error
+	}{
+		{"valid", func(c *Challenge, r *Response) {}, 0, nil},
+		{"expired", func(c *Challenge, r *Response) {}, time.Minute, ErrChallengeExpired},
+		{"other key claimed", func(c *Challenge, r *Response) { r.PubKey = schnorr.XOnlyFromPub(other.PubKey()) }, 0, ErrInvalidSignature},
+		{"bad signature", func(c *Challenge, r *Response) { r.Signature[0] ^= 0x01 }, 0, ErrInvalidSignature},
+		{"unknown nonce", func(c *Challenge, r *Response) { r.Nonce[0] ^= 0x01 }, 0, ErrUnknownChallenge},
+		{"short signature", func(c *Challenge, r *Response) { r.Signature = r.Signature[:63] }, 0, ErrUnknownChallenge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge, err := server.Issue()
+			if err != nil {
+				t.Fatalf("Issue failed: %v", err)
+			}
+			resp, err := Respond(challenge, "example.com", user)
+			if err != nil {
+				t.Fatalf("Respond failed: %v", err)
+			}
+			tt.modify(challenge, resp)
+			clock.now = clock.now.Add(tt.advance)
+			if _, err := server.Verify(resp); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestChallengeLimits(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	server := NewServer("example.com", WithClock(clock.Now), WithMaxPending(2), WithChallengeTTL(time.Minute))
+
+	for i := 0; i < 2; i++ {
+		if _, err := server.Issue(); err != nil {
+			t.Fatalf("Issue failed: %v", err)
+		}
+	}
+	if _, err := server.Issue(); !errors.Is(err, ErrTooManyChallenges) {
+		t.Errorf("Expected ErrTooManyChallenges, got %v", err)
+	}
+
+	// Expired challenges make room again
+	clock.now = clock.now.Add(time.Minute)
+	if _, err := server.Issue(); err != nil {
+		t.Errorf("Expected Issue to succeed after expiry, got %v", err)
+	}
+	clock.now = clock.now.Add(time.Minute)
+	if n := server.Collect(); n != 1 || server.Pending() != 0 {
+		t.Errorf("Expected Collect to drop 1 challenge, dropped %d with %d left", n, server.Pending())
+	}
+
+	if _, err := NewServer("example.com", WithRandReader(bytes.NewReader(nil))).Issue(); err == nil {
+		t.Error("Expected error for a failing random source")
+	}
+}
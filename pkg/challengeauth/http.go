@@ -0,0 +1,114 @@
+package challengeauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// HTTP binding
+//
+// ChallengeHandler serves fresh challenges as JSON. The client answers by
+// sending the response on the request it wants authenticated:
+//
+//	Authorization: Schnorr <base64url(JSON Response)>
+//
+// Middleware verifies that header, rejects the request with 401 otherwise,
+// and hands the authenticated key to the next handler through the context.
+// Each challenge authenticates a single request; applications that want a
+// login session issue their own cookie or token after the first one.
+
+// authScheme is the Authorization scheme name
+const authScheme = "Schnorr"
+
+// contextKey is the type of the context key holding the authenticated key
+type contextKey struct{}
+
+// AuthorizationHeader encodes a response as an Authorization header value
+//
+// Example:
+//
+//	header, _ := challengeauth.AuthorizationHeader(resp)
+//	req.Header.Set("Authorization", header)
+func AuthorizationHeader(resp *Response) (string, error) {
+	if resp == nil {
+		return "", errors.New("response cannot be nil")
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return "", err
+	}
+	return authScheme + " " + base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// parseAuthorization decodes an Authorization header value
+func parseAuthorization(header string) (*Response, error) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, authScheme) {
+		return nil, errors.New("missing Schnorr authorization")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil {
+		return nil, errors.New("malformed authorization")
+	}
+	var resp Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, errors.New("malformed authorization")
+	}
+	return &resp, nil
+}
+
+// ChallengeHandler returns a handler that issues a challenge on every request
+//
+// Example:
+//
+//	mux.Handle("/auth/challenge", server.ChallengeHandler())
+func (s *Server) ChallengeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		challenge, err := s.Issue()
+		if err != nil {
+			http.Error(w, "cannot issue challenge", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(challenge)
+	})
+}
+
+// Middleware authenticates requests carrying a valid challenge response
+//
+// Example:
+//
+//	mux.Handle("/account", server.Middleware(accountHandler))
+//	// in accountHandler:
+//	pubKey, _ := challengeauth.PubKeyFromContext(r.Context())
+func (s *Server) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Step 1: Decode and verify the response
+		resp, err := parseAuthorization(r.Header.Get("Authorization"))
+		var pubKey schnorr.XOnlyPublicKey
+		if err == nil {
+			pubKey, err = s.Verify(resp)
+		}
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", authScheme+` realm="`+s.domain+`"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		// Step 2: Pass the key on to the handler
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, pubKey)))
+	})
+}
+
+// PubKeyFromContext returns the key authenticated by Middleware
+func PubKeyFromContext(ctx context.Context) (schnorr.XOnlyPublicKey, bool) {
+	pubKey, ok := ctx.Value(contextKey{}).(schnorr.XOnlyPublicKey)
+	return pubKey, ok
+}
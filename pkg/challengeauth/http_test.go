@@ -0,0 +1,82 @@
+package challengeauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+func TestMiddleware(t *testing.T) {
+	user := newKey(0x01)
+	server := NewServer("example.com")
+	mux := http.NewServeMux()
+	mux.Handle("/challenge", server.ChallengeHandler())
+	mux.Handle("/account", server.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pubKey, ok := PubKeyFromContext(r.Context())
+		if !ok {
+			t.Error("Expected the key in the context")
+		}
+		w.Write([]byte(pubKey.String()))
+	})))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	// Step 1: Fetch and answer a challenge
+	res, err := http.Get(ts.URL + "/challenge")
+	if err != nil {
+		t.Fatalf("GET /challenge failed: %v", err)
+	}
+	var challenge Challenge
+	if err := json.NewDecoder(res.Body).Decode(&challenge); err != nil {
+		t.Fatalf("Decoding the challenge failed: %v", err)
+	}
+	res.Body.Close()
+	resp, err := Respond(&challenge, "example.com", user)
+	if err != nil {
+		t.Fatalf("Respond failed: %v", err)
+	}
+	header, err := AuthorizationHeader(resp)
+	if err != nil {
+		t.Fatalf("AuthorizationHeader failed: %v", err)
+	}
+
+	get := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/account", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Step 2: The response authenticates one request
+	rec := get(header)
+	if rec.Code != http.StatusOK || rec.Body.String() != schnorr.XOnlyFromPub(user.PubKey()).String() {
+		t.Errorf("Expected 200 with the user's key, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	// Step 3: Replays and bad headers are refused
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{"replay", header},
+		{"missing", ""},
+		{"other scheme", "Bearer abc"},
+		{"not base64", "Schnorr !!!"},
+		{"not json", "Schnorr bm90IGpzb24"},
+	}
+	for _, tt := range tests {
+		rec := get(tt.authorization)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401, got %d", tt.name, rec.Code)
+		}
+		if rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: expected a WWW-Authenticate header", tt.name)
+		}
+	}
+}
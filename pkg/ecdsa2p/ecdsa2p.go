@@ -0,0 +1,368 @@
+package ecdsa2p
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+	"github.com/neverDefined/cryptography-playground/pkg/paillier"
+)
+
+// Two-party ECDSA (Lindell 2017, simplified)
+//
+// The private key is split multiplicatively, x = x1 * x2, so the joint public
+// key is Q = x1*x2*G. Party 1 owns a Paillier key and gives party 2 an
+// encryption of x1. To sign digest m:
+//
+//	P1 → P2:  commit(R1)                  R1 = k1*G
+//	P2 → P1:  R2                          R2 = k2*G
+//	P1 → P2:  R1 (opens the commitment)
+//	both:     R = k1*k2*G, r = R.x mod n
+//	P2 → P1:  c = Enc(ρn + k2⁻¹m) ⊕ Enc(x1)^(k2⁻¹·r·x2)
+//	P1:       s = k1⁻¹ * Dec(c) mod n  =  k⁻¹(m + r*x) mod n
+//
+// The result is an ordinary low-S ECDSA signature that any Bitcoin verifier
+// accepts. This playground version omits the zero-knowledge proofs of the full
+// protocol (discrete log of Q1/R1/R2, correctness of the Paillier key and of
+// Enc(x1)), so it is only secure against honest-but-curious parties.
+
+// DefaultPaillierBits is the Paillier modulus size used by NewParty1
+const DefaultPaillierBits = 2048
+
+// minPaillierBits keeps ρn + k2⁻¹m + v*x1 (< n³ + 2n²) from wrapping modulo N
+const minPaillierBits = 3*256 + 2
+
+var (
+	// ErrWrongState is returned when a protocol step is called out of order or twice
+	ErrWrongState = errors.New("protocol step called out of order")
+	// ErrCommitmentMismatch is returned when a revealed nonce does not match its commitment
+	ErrCommitmentMismatch = errors.New("nonce does not match commitment")
+	// ErrInvalidSignature is returned when the combined signature does not verify
+	ErrInvalidSignature = errors.New("combined signature is invalid")
+)
+
+// KeyGenMessage is party 1's key generation message to party 2
+type KeyGenMessage struct {
+	PublicShare    []byte   // Q1 = x1*G, compressed
+	PaillierN      *big.Int // Party 1's Paillier modulus
+	EncryptedShare *big.Int // Enc(x1) under party 1's Paillier key
+}
+
+// Party1 holds x1 and the Paillier private key
+type Party1 struct {
+	x1       *big.Int
+	paillier *paillier.PrivateKey
+	pubKey   *btcec.PublicKey
+}
+
+// Party2 holds x2 and Enc(x1)
+type Party2 struct {
+	x2             *big.Int
+	paillier       *paillier.PublicKey
+	encryptedShare *big.Int
+	pubKey         *btcec.PublicKey
+}
+
+// NewParty1 starts key generation for party 1
+//
+// Example:
+//
+//	p1, msg, _ := ecdsa2p.NewParty1(ecdsa2p.DefaultPaillierBits)
+//	p2, q2, _ := ecdsa2p.NewParty2(msg)
+//	_ = p1.CompleteKeyGen(q2)
+//	// p1.PublicKey() and p2.PublicKey() are the same joint key
+func NewParty1(paillierBits int) (*Party1, *KeyGenMessage, error) {
+	if paillierBits < minPaillierBits {
+		return nil, nil, fmt.Errorf("paillier modulus must be at least %d bits", minPaillierBits)
+	}
+
+	// Step 1: Secret share x1 and Q1 = x1*G
+	x1, err := arithmetic.RandScalar()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key share: %w", err)
+	}
+
+	// Step 2: Paillier key and Enc(x1)
+	sk, err := paillier.GenerateKey(rand.Reader, paillierBits)
+	if err != nil {
+		return nil, nil, err
+	}
+	encrypted, err := sk.Encrypt(rand.Reader, x1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	msg := &KeyGenMessage{
+		PublicShare:    baseMult(x1).SerializeCompressed(),
+		PaillierN:      new(big.Int).Set(sk.N),
+		EncryptedShare: encrypted,
+	}
+	return &Party1{x1: x1, paillier: sk}, msg, nil
+}
+
+// NewParty2 completes key generation for party 2
+//
+// Returns party 2's compressed public share Q2 = x2*G, which must be sent to party 1.
+func NewParty2(msg *KeyGenMessage) (*Party2, []byte, error) {
+	if msg == nil {
+		return nil, nil, errors.New("key generation message cannot be nil")
+	}
+
+	// Step 1: Validate party 1's message
+	q1, err := btcec.ParsePubKey(msg.PublicShare)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid public share: %w", err)
+	}
+	if msg.PaillierN == nil || msg.PaillierN.BitLen() < minPaillierBits {
+		return nil, nil, fmt.Errorf("paillier modulus must be at least %d bits", minPaillierBits)
+	}
+	pk, err := paillier.NewPublicKey(msg.PaillierN)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := pk.ValidateCiphertext(msg.EncryptedShare); err != nil {
+		return nil, nil, fmt.Errorf("invalid encrypted share: %w", err)
+	}
+
+	// Step 2: Secret share x2 and joint key Q = x2*Q1
+	x2, err := arithmetic.RandScalar()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key share: %w", err)
+	}
+
+	p2 := &Party2{
+		x2:             x2,
+		paillier:       pk,
+		encryptedShare: new(big.Int).Set(msg.EncryptedShare),
+		pubKey:         scalarMult(x2, q1),
+	}
+	return p2, baseMult(x2).SerializeCompressed(), nil
+}
+
+// CompleteKeyGen finishes key generation for party 1 with party 2's public share
+func (p *Party1) CompleteKeyGen(publicShare []byte) error {
+	if p.pubKey != nil {
+		return ErrWrongState
+	}
+	q2, err := btcec.ParsePubKey(publicShare)
+	if err != nil {
+		return fmt.Errorf("invalid public share: %w", err)
+	}
+	p.pubKey = scalarMult(p.x1, q2)
+	return nil
+}
+
+// PublicKey returns the joint public key, or nil before key generation completes
+func (p *Party1) PublicKey() *btcec.PublicKey {
+	return p.pubKey
+}
+
+// PublicKey returns the joint public key
+func (p *Party2) PublicKey() *btcec.PublicKey {
+	return p.pubKey
+}
+
+// Signer1 is party 1's state for signing a single digest
+type Signer1 struct {
+	party *Party1
+	hash  [32]byte
+	k1    *big.Int
+	r1    *btcec.PublicKey
+	r     *big.Int
+	done  bool
+}
+
+// Signer2 is party 2's state for signing a single digest
+type Signer2 struct {
+	party      *Party2
+	hash       [32]byte
+	commitment [32]byte
+	k2         *big.Int
+	done       bool
+}
+
+// NewSigner starts signing hash and returns the commitment to R1 for party 2
+//
+// Example:
+//
+//	s1, commitment, _ := p1.NewSigner(hash)
+//	s2, r2, _ := p2.NewSigner(hash, commitment)
+//	r1, _ := s1.Reveal(r2)
+//	c, _ := s2.PartialSignature(r1)
+//	sig, _ := s1.Finalize(c) // *ecdsa.Signature, sig.Serialize() is DER
+func (p *Party1) NewSigner(hash [32]byte) (*Signer1, [32]byte, error) {
+	if p.pubKey == nil {
+		return nil, [32]byte{}, ErrWrongState
+	}
+	k1, err := arithmetic.RandScalar()
+	if err != nil {
+		return nil, [32]byte{}, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	r1 := baseMult(k1)
+	return &Signer1{party: p, hash: hash, k1: k1, r1: r1}, nonceCommitment(r1.SerializeCompressed(), hash), nil
+}
+
+// NewSigner starts signing hash after receiving party 1's commitment, returning R2
+func (p *Party2) NewSigner(hash [32]byte, commitment [32]byte) (*Signer2, []byte, error) {
+	k2, err := arithmetic.RandScalar()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	s := &Signer2{party: p, hash: hash, commitment: commitment, k2: k2}
+	return s, baseMult(k2).SerializeCompressed(), nil
+}
+
+// Reveal receives R2, computes r, and opens the commitment to R1
+func (s *Signer1) Reveal(r2Bytes []byte) ([]byte, error) {
+	if s.done || s.r != nil {
+		return nil, ErrWrongState
+	}
+	r2, err := btcec.ParsePubKey(r2Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce: %w", err)
+	}
+
+	r := xModN(scalarMult(s.k1, r2))
+	if r.Sign() == 0 {
+		return nil, errors.New("nonce produced r = 0, restart signing")
+	}
+	s.r = r
+	return s.r1.SerializeCompressed(), nil
+}
+
+// PartialSignature checks R1 against the commitment and returns the encrypted partial signature
+//
+// The signer can only be used once; the nonce k2 is erased afterwards.
+func (s *Signer2) PartialSignature(r1Bytes []byte) (*big.Int, error) {
+	if s.done {
+		return nil, ErrWrongState
+	}
+
+	// Step 1: Open party 1's commitment
+	if nonceCommitment(r1Bytes, s.hash) != s.commitment {
+		return nil, ErrCommitmentMismatch
+	}
+	r1, err := btcec.ParsePubKey(r1Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce: %w", err)
+	}
+	s.done = true
+	defer arithmetic.ZeroBigInt(s.k2)
+
+	// Step 2: r = (k2 * R1).x mod n
+	r := xModN(scalarMult(s.k2, r1))
+	if r.Sign() == 0 {
+		return nil, errors.New("nonce produced r = 0, restart signing")
+	}
+
+	// Step 3: c1 = Enc(ρn + k2⁻¹m), with ρ < n² masking the plaintext mod n
+	n := arithmetic.GetCurveOrder()
+	k2Inv, err := arithmetic.InvModN(s.k2)
+	if err != nil {
+		return nil, err
+	}
+	defer arithmetic.ZeroBigInt(k2Inv)
+	rho, err := rand.Int(rand.Reader, new(big.Int).Mul(n, n))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate mask: %w", err)
+	}
+	defer arithmetic.ZeroBigInt(rho)
+	m := arithmetic.ModN(new(big.Int).SetBytes(s.hash[:]))
+	plain := new(big.Int).Mul(rho, n)
+	defer arithmetic.ZeroBigInt(plain)
+	plain.Add(plain, arithmetic.MulModN(k2Inv, m))
+	c1, err := s.party.paillier.Encrypt(rand.Reader, plain)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 4: c2 = Enc(x1)^(k2⁻¹ * r * x2) = Enc(k2⁻¹ * r * x)
+	k2InvR := arithmetic.MulModN(k2Inv, r)
+	defer arithmetic.ZeroBigInt(k2InvR)
+	v := arithmetic.MulModN(k2InvR, s.party.x2)
+	defer arithmetic.ZeroBigInt(v)
+	c2 := s.party.paillier.MulConst(s.party.encryptedShare, v)
+
+	return s.party.paillier.Add(c1, c2), nil
+}
+
+// Finalize decrypts the partial signature and returns the completed low-S signature
+//
+// The signature is verified against the joint public key before it is returned.
+func (s *Signer1) Finalize(partial *big.Int) (*ecdsa.Signature, error) {
+	if s.done || s.r == nil {
+		return nil, ErrWrongState
+	}
+	s.done = true
+	defer arithmetic.ZeroBigInt(s.k1)
+
+	// Step 1: s' = Dec(c) mod n = k2⁻¹(m + r*x) mod n
+	plain, err := s.party.paillier.Decrypt(partial)
+	if err != nil {
+		return nil, fmt.Errorf("invalid partial signature: %w", err)
+	}
+	defer arithmetic.ZeroBigInt(plain)
+
+	// Step 2: s = k1⁻¹ * s' mod n, normalized to the lower half (BIP62)
+	n := arithmetic.GetCurveOrder()
+	k1Inv, err := arithmetic.InvModN(s.k1)
+	if err != nil {
+		return nil, err
+	}
+	defer arithmetic.ZeroBigInt(k1Inv)
+	sig := arithmetic.MulModN(k1Inv, arithmetic.ModN(plain))
+	if sig.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		sig = arithmetic.NegModN(sig)
+	}
+
+	// Step 3: Build and check the standard signature
+	var rScalar, sScalar btcec.ModNScalar
+	rScalar.SetByteSlice(s.r.Bytes())
+	sScalar.SetByteSlice(sig.Bytes())
+	signature := ecdsa.NewSignature(&rScalar, &sScalar)
+	if !signature.Verify(s.hash[:], s.party.pubKey) {
+		return nil, ErrInvalidSignature
+	}
+	return signature, nil
+}
+
+// nonceCommitment computes SHA256("ecdsa2p/commitment" || R1 || hash)
+func nonceCommitment(r1 []byte, hash [32]byte) [32]byte {
+	var buf bytes.Buffer
+	buf.WriteString("ecdsa2p/commitment")
+	buf.Write(r1)
+	buf.Write(hash[:])
+	return sha256.Sum256(buf.Bytes())
+}
+
+// baseMult computes k*G
+func baseMult(k *big.Int) *btcec.PublicKey {
+	var scalar btcec.ModNScalar
+	scalar.SetByteSlice(k.Bytes())
+	var result btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&scalar, &result)
+	result.ToAffine()
+	return btcec.NewPublicKey(&result.X, &result.Y)
+}
+
+// scalarMult computes k*P
+func scalarMult(k *big.Int, point *btcec.PublicKey) *btcec.PublicKey {
+	var scalar btcec.ModNScalar
+	scalar.SetByteSlice(k.Bytes())
+	var p, result btcec.JacobianPoint
+	point.AsJacobian(&p)
+	btcec.ScalarMultNonConst(&scalar, &p, &result)
+	result.ToAffine()
+	return btcec.NewPublicKey(&result.X, &result.Y)
+}
+
+// xModN returns the x coordinate of a point reduced modulo n
+func xModN(point *btcec.PublicKey) *big.Int {
+	return arithmetic.ModN(new(big.Int).Set(point.X()))
+}
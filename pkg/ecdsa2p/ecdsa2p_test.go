@@ -0,0 +1,159 @@
+package ecdsa2p
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+)
+
+// testPaillierBits keeps key generation fast in tests
+const testPaillierBits = 1024
+
+// newParties runs key generation between both parties
+func newParties(t *testing.T) (*Party1, *Party2) {
+	t.Helper()
+	p1, msg, err := NewParty1(testPaillierBits)
+	if err != nil {
+		t.Fatalf("NewParty1 failed: %v", err)
+	}
+	p2, q2, err := NewParty2(msg)
+	if err != nil {
+		t.Fatalf("NewParty2 failed: %v", err)
+	}
+	if err := p1.CompleteKeyGen(q2); err != nil {
+		t.Fatalf("CompleteKeyGen failed: %v", err)
+	}
+	return p1, p2
+}
+
+// sign runs the full signing protocol for hash
+func sign(t *testing.T, p1 *Party1, p2 *Party2, hash [32]byte) *ecdsa.Signature {
+	t.Helper()
+	s1, commitment, err := p1.NewSigner(hash)
+	if err != nil {
+		t.Fatalf("Party1.NewSigner failed: %v", err)
+	}
+	s2, r2, err := p2.NewSigner(hash, commitment)
+	if err != nil {
+		t.Fatalf("Party2.NewSigner failed: %v", err)
+	}
+	r1, err := s1.Reveal(r2)
+	if err != nil {
+		t.Fatalf("Reveal failed: %v", err)
+	}
+	partial, err := s2.PartialSignature(r1)
+	if err != nil {
+		t.Fatalf("PartialSignature failed: %v", err)
+	}
+	sig, err := s1.Finalize(partial)
+	if err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+	return sig
+}
+
+// TestTwoPartySigning tests that the protocol produces standard DER signatures
+func TestTwoPartySigning(t *testing.T) {
+	p1, p2 := newParties(t)
+	if !p1.PublicKey().IsEqual(p2.PublicKey()) {
+		t.Fatal("Parties derived different joint public keys")
+	}
+
+	halfOrder := new(big.Int).Rsh(arithmetic.GetCurveOrder(), 1)
+	for _, msg := range []string{"first", "second", "third"} {
+		hash := sha256.Sum256([]byte(msg))
+		sig := sign(t, p1, p2, hash)
+
+		der := sig.Serialize()
+		parsed, err := ecdsa.ParseDERSignature(der)
+		if err != nil {
+			t.Fatalf("ParseDERSignature failed for %q: %v", msg, err)
+		}
+		if !parsed.Verify(hash[:], p1.PublicKey()) {
+			t.Errorf("Signature for %q does not verify", msg)
+		}
+
+		// Low-S: the S value is the last integer in the DER encoding
+		sLen := int(der[5+der[3]])
+		s := new(big.Int).SetBytes(der[len(der)-sLen:])
+		if s.Cmp(halfOrder) > 0 {
+			t.Errorf("Signature for %q is not low-S", msg)
+		}
+
+		other := sha256.Sum256([]byte("other"))
+		if parsed.Verify(other[:], p1.PublicKey()) {
+			t.Errorf("Signature for %q verified for another message", msg)
+		}
+	}
+}
+
+// TestProtocolErrors tests commitment checks and single-use signers
+func TestProtocolErrors(t *testing.T) {
+	p1, p2 := newParties(t)
+	hash := sha256.Sum256([]byte("message"))
+
+	// A revealed nonce that does not match the commitment is rejected
+	s1, commitment, _ := p1.NewSigner(hash)
+	other, _, _ := p1.NewSigner(hash)
+	s2, r2, _ := p2.NewSigner(hash, commitment)
+	if _, err := s1.Reveal(r2); err != nil {
+		t.Fatalf("Reveal failed: %v", err)
+	}
+	if _, err := other.Reveal(r2); err != nil {
+		t.Fatalf("Reveal failed: %v", err)
+	}
+	if _, err := s2.PartialSignature(other.r1.SerializeCompressed()); !errors.Is(err, ErrCommitmentMismatch) {
+		t.Errorf("Expected ErrCommitmentMismatch, got %v", err)
+	}
+
+	// Signers are single-use
+	r1, _ := s1.Reveal(r2)
+	if r1 != nil {
+		t.Error("Expected second Reveal to fail")
+	}
+	partial, err := s2.PartialSignature(s1.r1.SerializeCompressed())
+	if err != nil {
+		t.Fatalf("PartialSignature failed: %v", err)
+	}
+	if _, err := s2.PartialSignature(s1.r1.SerializeCompressed()); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState for a second partial signature, got %v", err)
+	}
+	if _, err := s1.Finalize(partial); err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+	if _, err := s1.Finalize(partial); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState for a second Finalize, got %v", err)
+	}
+
+	// Finalize before Reveal
+	s1, _, _ = p1.NewSigner(hash)
+	if _, err := s1.Finalize(partial); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState before Reveal, got %v", err)
+	}
+
+	// A partial signature for another digest does not produce a valid signature
+	s1, commitment, _ = p1.NewSigner(hash)
+	wrong := sha256.Sum256([]byte("wrong"))
+	s2, r2, _ = p2.NewSigner(wrong, nonceCommitment(s1.r1.SerializeCompressed(), wrong))
+	r1, _ = s1.Reveal(r2)
+	partial, _ = s2.PartialSignature(r1)
+	if _, err := s1.Finalize(partial); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+
+	// Key generation checks
+	if _, _, err := NewParty1(512); err == nil {
+		t.Error("Expected error for a too small Paillier modulus")
+	}
+	if _, _, err := NewParty2(nil); err == nil {
+		t.Error("Expected error for a nil message")
+	}
+	unfinished, _, _ := NewParty1(testPaillierBits)
+	if _, _, err := unfinished.NewSigner(hash); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState before key generation completes, got %v", err)
+	}
+}
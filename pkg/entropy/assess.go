@@ -0,0 +1,103 @@
+package entropy
+
+import (
+	"fmt"
+	"math"
+)
+
+// Quality checks
+//
+// The entropy estimate trusts that every die is fair and every roll
+// independent. Assess runs cheap statistical checks that catch the common ways
+// that goes wrong: a loaded or misread die (chi-squared against the uniform
+// distribution), suspiciously long runs of the same value, and a coin so biased
+// that von Neumann debiasing discards almost everything. Passing them does not
+// prove the input is random; failing them means it is not.
+
+// minimumBits is the entropy below which Assess always warns
+const minimumBits = 128
+
+// Assessment summarizes the collected observations
+type Assessment struct {
+	Bits     float64  // Estimated entropy, in bits
+	Samples  int      // Raw observations (rolls, flips, cards)
+	Warnings []string // Human-readable problems; empty if none were found
+}
+
+// OK reports whether no warnings were raised
+func (a Assessment) OK() bool {
+	return len(a.Warnings) == 0
+}
+
+// Assess estimates the entropy collected and checks it for obvious bias
+//
+// Example:
+//
+//	a := c.Assess()
+//	if !a.OK() {
+//		for _, w := range a.Warnings {
+//			fmt.Println("warning:", w)
+//		}
+//	}
+func (c *Collector) Assess() Assessment {
+	a := Assessment{Bits: c.Bits()}
+	for i, b := range c.batches {
+		a.Samples += b.raw
+		switch b.kind {
+		case sourceDice:
+			a.Warnings = append(a.Warnings, assessDice(i, &b)...)
+		case sourceCoins:
+			// A fair coin keeps about a quarter of the flips
+			if b.raw >= 64 && len(b.values) < b.raw/16 {
+				a.Warnings = append(a.Warnings, fmt.Sprintf("batch %d: only %d of %d coin flips survived debiasing; the coin is heavily biased", i, len(b.values), b.raw))
+			}
+		}
+	}
+	if a.Bits < minimumBits {
+		a.Warnings = append(a.Warnings, fmt.Sprintf("only %.1f bits collected, at least %d are recommended", a.Bits, minimumBits))
+	}
+	return a
+}
+
+// assessDice runs the chi-squared and longest-run checks on one batch of rolls
+func assessDice(index int, b *batch) []string {
+	var warnings []string
+	n := len(b.values)
+
+	// Step 1: Chi-squared against the uniform distribution, once every face
+	// is expected at least 5 times. The threshold is the mean plus about five
+	// standard deviations of the chi-squared distribution.
+	if n >= 5*b.sides {
+		counts := make([]int, b.sides+1)
+		for _, v := range b.values {
+			counts[v]++
+		}
+		expected := float64(n) / float64(b.sides)
+		stat := 0.0
+		for face := 1; face <= b.sides; face++ {
+			d := float64(counts[face]) - expected
+			stat += d * d / expected
+		}
+		df := float64(b.sides - 1)
+		if stat > df+5*math.Sqrt(2*df) {
+			warnings = append(warnings, fmt.Sprintf("batch %d: d%d rolls are not uniform (chi-squared %.1f with %d degrees of freedom)", index, b.sides, stat, b.sides-1))
+		}
+	}
+
+	// Step 2: Longest run of one value; a run this long happens by chance
+	// with probability below about 1/1000
+	limit := 1 + int(math.Ceil(math.Log(float64(n)*1000)/math.Log(float64(b.sides))))
+	run, longest := 0, 0
+	for i, v := range b.values {
+		if i > 0 && v == b.values[i-1] {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+	if n > 0 && longest >= limit {
+		warnings = append(warnings, fmt.Sprintf("batch %d: %d identical d%d rolls in a row", index, longest, b.sides))
+	}
+	return warnings
+}
@@ -0,0 +1,65 @@
+package entropy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssess(t *testing.T) {
+	loaded := make([]int, 300)
+	for i := range loaded {
+		loaded[i] = []int{6, 6, 1, 6, 2, 3, 6, 4, 5, 6}[i%10] // Half sixes
+	}
+	counting := make([]int, 300)
+	for i := range counting {
+		counting[i] = i%6 + 1 // Uniform but with no run longer than 1
+	}
+
+	tests := []struct {
+		name    string
+		add     func(c *Collector)
+		warning string // Expected substring; empty for no warnings
+	}{
+		{"fair dice", func(c *Collector) { c.AddDice(6, fairRolls(t, 6, 300)...) }, ""},
+		{"too little", func(c *Collector) { c.AddDice(6, 1, 2, 3) }, "bits collected"},
+		{"loaded die", func(c *Collector) { c.AddDice(6, loaded...) }, "not uniform"},
+		{"stuck die", func(c *Collector) {
+			c.AddDice(6, counting...)
+			c.AddDice(6, 4, 4, 4, 4, 4, 4, 4, 4, 4)
+		}, "in a row"},
+		{"biased coin", func(c *Collector) {
+			c.AddDice(6, fairRolls(t, 6, 100)...)
+			c.AddCoins(strings.Repeat("H", 200) + "HT")
+		}, "heavily biased"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector()
+			tt.add(c)
+			a := c.Assess()
+			if tt.warning == "" {
+				if !a.OK() {
+					t.Errorf("Expected no warnings, got %v", a.Warnings)
+				}
+				return
+			}
+			found := false
+			for _, w := range a.Warnings {
+				found = found || strings.Contains(w, tt.warning)
+			}
+			if !found {
+				t.Errorf("Expected a warning containing %q, got %v", tt.warning, a.Warnings)
+			}
+		})
+	}
+}
+
+func TestAssessSamples(t *testing.T) {
+	c := NewCollector()
+	c.AddDice(6, 1, 2, 3)
+	c.AddCoins("HHTT HT")
+	c.AddCards("AS", "KD")
+	if a := c.Assess(); a.Samples != 11 {
+		t.Errorf("Expected 11 samples, got %d", a.Samples)
+	}
+}
@@ -0,0 +1,299 @@
+package entropy
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// Physical entropy collection
+//
+// Dice, coins and shuffled cards are randomness sources anyone can audit, so
+// they let a key be generated without trusting the computer's RNG. A Collector
+// takes the raw observations, estimates how much entropy they carry, checks
+// them for obvious bias, and condenses them into key material with SHA256:
+//
+//	die with s sides:  log2(s) bits per roll
+//	coin flip:         1 bit per von Neumann output bit (see VonNeumann)
+//	k cards of 52:     log2(52·51·…·(52-k+1)) bits (a full deck is ~225.6 bits)
+//
+// The estimates assume fair, independent observations; Assess flags inputs
+// that are clearly not (a loaded die, a person typing "random" numbers).
+
+// extractTag separates entropy extraction from other uses of SHA256
+const extractTag = "cryptography-playground/entropy/v1"
+
+var (
+	// ErrInsufficientEntropy is returned when fewer bits were collected than requested
+	ErrInsufficientEntropy = errors.New("insufficient entropy")
+	// ErrInvalidObservation is returned for a roll, flip or card that cannot occur
+	ErrInvalidObservation = errors.New("invalid observation")
+	// ErrDuplicateCard is returned when the same card appears twice in a shuffle
+	ErrDuplicateCard = errors.New("duplicate card")
+)
+
+// Source kinds, also written into the extraction input
+const (
+	sourceDice  byte = 1
+	sourceCoins byte = 2
+	sourceCards byte = 3
+)
+
+// batch is one group of observations from the same source
+type batch struct {
+	kind   byte
+	sides  int   // Dice only
+	values []int // Rolls (1..sides), debiased bits (0/1) or card indices (0..51)
+	raw    int   // Observations before debiasing
+}
+
+// Collector accumulates physical observations
+//
+// Example:
+//
+//	c := entropy.NewCollector()
+//	rolls, _ := entropy.ParseRolls("3 6 1 2 5 4 ...")
+//	c.AddDice(6, rolls...)
+//	key, err := c.PrivateKey()
+type Collector struct {
+	batches []batch
+}
+
+// NewCollector creates an empty collector
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// AddDice records rolls of a die with the given number of sides
+//
+// Example:
+//
+//	err := c.AddDice(6, 3, 6, 1, 2, 5, 4)
+//	// Result: 6·log2(6) ≈ 15.5 bits
+func (c *Collector) AddDice(sides int, rolls ...int) error {
+	if sides < 2 || sides > 256 {
+		return fmt.Errorf("%w: a die must have 2 to 256 sides, got %d", ErrInvalidObservation, sides)
+	}
+	for i, r := range rolls {
+		if r < 1 || r > sides {
+			return fmt.Errorf("%w: roll %d is %d on a d%d", ErrInvalidObservation, i, r, sides)
+		}
+	}
+	c.batches = append(c.batches, batch{kind: sourceDice, sides: sides, values: append([]int(nil), rolls...), raw: len(rolls)})
+	return nil
+}
+
+// AddCoins records coin flips written as H/T (or 1/0), debiasing them with VonNeumann
+//
+// Spaces are ignored. Only the debiased bits count towards the entropy, so a
+// biased coin needs more flips but does not weaken the result.
+//
+// Example:
+//
+//	err := c.AddCoins("HTTH HHTH THTT")
+func (c *Collector) AddCoins(flips string) error {
+	var raw []int
+	for _, ch := range flips {
+		switch ch {
+		case 'H', 'h', '1':
+			raw = append(raw, 1)
+		case 'T', 't', '0':
+			raw = append(raw, 0)
+		case ' ', '\t', '\n', '\r':
+		default:
+			return fmt.Errorf("%w: coin flip %q", ErrInvalidObservation, ch)
+		}
+	}
+	c.batches = append(c.batches, batch{kind: sourceCoins, values: VonNeumann(raw), raw: len(raw)})
+	return nil
+}
+
+// AddCards records cards in the order they were drawn from a shuffled 52-card deck
+//
+// Cards are written rank then suit: A,2..9,T (or 10),J,Q,K and S,H,D,C, e.g.
+// "AS", "10h", "TD". Each card may appear once.
+//
+// Example:
+//
+//	err := c.AddCards(strings.Fields("7H KS 2C TD AS ...")...)
+func (c *Collector) AddCards(cards ...string) error {
+	seen := make(map[int]bool, len(cards))
+	values := make([]int, len(cards))
+	for i, card := range cards {
+		index, err := ParseCard(card)
+		if err != nil {
+			return err
+		}
+		if seen[index] {
+			return fmt.Errorf("%w: %s", ErrDuplicateCard, card)
+		}
+		seen[index] = true
+		values[i] = index
+	}
+	c.batches = append(c.batches, batch{kind: sourceCards, values: values, raw: len(values)})
+	return nil
+}
+
+// Bits returns the estimated entropy collected so far
+func (c *Collector) Bits() float64 {
+	total := 0.0
+	for _, b := range c.batches {
+		total += b.bits()
+	}
+	return total
+}
+
+// bits estimates the entropy of one batch
+func (b *batch) bits() float64 {
+	switch b.kind {
+	case sourceDice:
+		return float64(len(b.values)) * math.Log2(float64(b.sides))
+	case sourceCoins:
+		return float64(len(b.values))
+	case sourceCards:
+		total := 0.0
+		for i := range b.values {
+			total += math.Log2(float64(52 - i))
+		}
+		return total
+	}
+	return 0
+}
+
+// Bytes condenses the observations into size bytes of key material
+//
+// Fails with ErrInsufficientEntropy unless at least 8·size bits were collected.
+// Output block i is SHA256(tag || tag || i || observations) with the tagged
+// hash construction of BIP340.
+//
+// Example:
+//
+//	seed, err := c.Bytes(32)
+func (c *Collector) Bytes(size int) ([]byte, error) {
+	if size <= 0 {
+		return nil, errors.New("size must be positive")
+	}
+	if bits := c.Bits(); bits < float64(8*size) {
+		return nil, fmt.Errorf("%w: have %.1f bits, need %d", ErrInsufficientEntropy, bits, 8*size)
+	}
+
+	// Step 1: Canonical encoding of every observation
+	pool := c.encode()
+	defer clear(pool)
+
+	// Step 2: Expand in 32-byte blocks
+	out := make([]byte, 0, size+sha256.Size)
+	for counter := uint32(0); len(out) < size; counter++ {
+		block := schnorr.TaggedHash(extractTag, binary.BigEndian.AppendUint32(nil, counter), pool)
+		out = append(out, block[:]...)
+		clear(block[:])
+	}
+	clear(out[size:])
+	return out[:size], nil
+}
+
+// encode serializes the batches as kind || sides || count || values (2 bytes each)
+func (c *Collector) encode() []byte {
+	var pool []byte
+	for _, b := range c.batches {
+		pool = append(pool, b.kind)
+		pool = binary.BigEndian.AppendUint16(pool, uint16(b.sides))
+		pool = binary.BigEndian.AppendUint32(pool, uint32(len(b.values)))
+		for _, v := range b.values {
+			pool = binary.BigEndian.AppendUint16(pool, uint16(v))
+		}
+	}
+	return pool
+}
+
+// PrivateKey derives a secp256k1 private key from at least 256 bits of observations
+//
+// Example:
+//
+//	key, err := c.PrivateKey()
+func (c *Collector) PrivateKey() (*btcec.PrivateKey, error) {
+	seed, err := c.Bytes(32)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(seed)
+
+	// Reject the ~2^-128 chance of a value >= n or zero by rehashing
+	for {
+		var scalar btcec.ModNScalar
+		if overflow := scalar.SetByteSlice(seed); !overflow && !scalar.IsZero() {
+			return btcec.PrivKeyFromScalar(&scalar), nil
+		}
+		next := sha256.Sum256(seed)
+		copy(seed, next[:])
+	}
+}
+
+// VonNeumann removes bias from independent bits
+//
+// Bits are taken in pairs: 01 gives 0, 10 gives 1, 00 and 11 are dropped.
+// Since P(01) = P(10) = p(1-p) for any fixed bias p, the output is unbiased.
+// It expects about n·p(1-p) output bits for n input bits, at most n/4 for a
+// fair coin.
+//
+// Example:
+//
+//	VonNeumann([]int{0, 1, 1, 1, 1, 0, 0, 0})
+//	// Result: []int{0, 1}
+func VonNeumann(bits []int) []int {
+	var out []int
+	for i := 0; i+1 < len(bits); i += 2 {
+		if bits[i] != bits[i+1] {
+			out = append(out, bits[i])
+		}
+	}
+	return out
+}
+
+// ParseRolls parses dice rolls separated by spaces or commas
+//
+// Example:
+//
+//	rolls, err := ParseRolls("3, 6 1 12")
+//	// Result: []int{3, 6, 1, 12}
+func ParseRolls(s string) ([]int, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\t' || r == '\n' || r == '\r'
+	})
+	rolls := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidObservation, f)
+		}
+		rolls[i] = n
+	}
+	return rolls, nil
+}
+
+// ParseCard converts a card such as "AS" or "10h" to its index 0..51 (suit·13 + rank)
+func ParseCard(card string) (int, error) {
+	card = strings.ToUpper(strings.TrimSpace(card))
+	if len(card) < 2 {
+		return 0, fmt.Errorf("%w: card %q", ErrInvalidObservation, card)
+	}
+	rankText, suitText := card[:len(card)-1], card[len(card)-1:]
+	rank := strings.Index("A23456789TJQK", rankText)
+	if rankText == "10" {
+		rank = 9
+	} else if len(rankText) != 1 {
+		rank = -1
+	}
+	suit := strings.Index("SHDC", suitText)
+	if rank < 0 || suit < 0 {
+		return 0, fmt.Errorf("%w: card %q", ErrInvalidObservation, card)
+	}
+	return suit*13 + rank, nil
+}
@@ -0,0 +1,195 @@
+package entropy
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/fixtures"
+)
+
+// fairRolls returns n deterministic rolls of a fair die
+func fairRolls(t *testing.T, sides, n int) []int {
+	t.Helper()
+	rng := fixtures.DeterministicReader("dice")
+	rolls := make([]int, n)
+	buf := make([]byte, 1)
+	for i := range rolls {
+		for {
+			rng.Read(buf)
+			if int(buf[0]) < 256/sides*sides {
+				rolls[i] = int(buf[0])%sides + 1
+				break
+			}
+		}
+	}
+	return rolls
+}
+
+func TestEntropyEstimates(t *testing.T) {
+	fullDeck := make([]string, 0, 52)
+	for _, suit := range "SHDC" {
+		for _, rank := range "A23456789TJQK" {
+			fullDeck = append(fullDeck, string(rank)+string(suit))
+		}
+	}
+
+	tests := []struct {
+		name string
+		add  func(c *Collector) error
+		bits float64
+	}{
+		{"one d6", func(c *Collector) error { return c.AddDice(6, 4) }, math.Log2(6)},
+		{"100 d6", func(c *Collector) error { return c.AddDice(6, fairRolls(t, 6, 100)...) }, 100 * math.Log2(6)},
+		{"d20", func(c *Collector) error { return c.AddDice(20, 20, 1) }, 2 * math.Log2(20)},
+		{"coins", func(c *Collector) error { return c.AddCoins("HT TH HH TT HT") }, 3},
+		{"three cards", func(c *Collector) error { return c.AddCards("AS", "10h", "KD") }, math.Log2(52 * 51 * 50)},
+		{"full deck", func(c *Collector) error { return c.AddCards(fullDeck...) }, 225.58},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector()
+			if err := tt.add(c); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if math.Abs(c.Bits()-tt.bits) > 0.01 {
+				t.Errorf("Expected %.2f bits, got %.2f", tt.bits, c.Bits())
+			}
+		})
+	}
+}
+
+func TestInvalidObservations(t *testing.T) {
+	c := NewCollector()
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"roll above sides", c.AddDice(6, 7), ErrInvalidObservation},
+		{"roll zero", c.AddDice(6, 0), ErrInvalidObservation},
+		{"one-sided die", c.AddDice(1, 1), ErrInvalidObservation},
+		{"bad flip", c.AddCoins("HTX"), ErrInvalidObservation},
+		{"bad card", c.AddCards("1S"), ErrInvalidObservation},
+		{"bad suit", c.AddCards("AX"), ErrInvalidObservation},
+		{"duplicate card", c.AddCards("AS", "as"), ErrDuplicateCard},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, tt.err)
+		}
+	}
+	if c.Bits() != 0 {
+		t.Errorf("Expected rejected observations to add no entropy, got %.1f bits", c.Bits())
+	}
+}
+
+func TestBytesAndPrivateKey(t *testing.T) {
+	rolls := fairRolls(t, 6, 99) // 255.9 bits
+	c := NewCollector()
+	c.AddDice(6, rolls...)
+	if _, err := c.PrivateKey(); !errors.Is(err, ErrInsufficientEntropy) {
+		t.Fatalf("Expected ErrInsufficientEntropy below 256 bits, got %v", err)
+	}
+
+	c.AddDice(6, 3)
+	key, err := c.PrivateKey()
+	if err != nil {
+		t.Fatalf("PrivateKey failed: %v", err)
+	}
+
+	// Same observations, same key; one roll different, different key
+	again := NewCollector()
+	again.AddDice(6, rolls...)
+	again.AddDice(6, 3)
+	key2, _ := again.PrivateKey()
+	if !key.PubKey().IsEqual(key2.PubKey()) {
+		t.Error("Expected the same key from the same rolls")
+	}
+	other := NewCollector()
+	other.AddDice(6, rolls...)
+	other.AddDice(6, 4)
+	key3, _ := other.PrivateKey()
+	if key.PubKey().IsEqual(key3.PubKey()) {
+		t.Error("Expected a different key from different rolls")
+	}
+
+	// Longer output extends the shorter one
+	short, _ := c.Bytes(16)
+	long, err := c.Bytes(32)
+	if err != nil {
+		t.Fatalf("Bytes failed: %v", err)
+	}
+	if !bytes.Equal(short, long[:16]) {
+		t.Error("Expected Bytes(16) to be a prefix of Bytes(32)")
+	}
+	if _, err := c.Bytes(64); !errors.Is(err, ErrInsufficientEntropy) {
+		t.Errorf("Expected ErrInsufficientEntropy for 512 bits, got %v", err)
+	}
+}
+
+func TestVonNeumann(t *testing.T) {
+	tests := []struct {
+		in, want []int
+	}{
+		{[]int{0, 1, 1, 1, 1, 0, 0, 0}, []int{0, 1}},
+		{[]int{1, 1, 0, 0}, nil},
+		{[]int{1, 0, 1}, []int{1}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		got := VonNeumann(tt.in)
+		if len(got) != len(tt.want) {
+			t.Errorf("VonNeumann(%v) = %v, expected %v", tt.in, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("VonNeumann(%v) = %v, expected %v", tt.in, got, tt.want)
+			}
+		}
+	}
+
+	// A 90% biased coin still yields balanced output
+	biased := make([]int, 20000)
+	rng := fixtures.DeterministicReader("coin")
+	buf := make([]byte, 1)
+	for i := range biased {
+		rng.Read(buf)
+		if buf[0] < 230 {
+			biased[i] = 1
+		}
+	}
+	out := VonNeumann(biased)
+	ones := 0
+	for _, b := range out {
+		ones += b
+	}
+	if ratio := float64(ones) / float64(len(out)); math.Abs(ratio-0.5) > 0.05 {
+		t.Errorf("Expected about half ones after debiasing, got %.3f of %d bits", ratio, len(out))
+	}
+}
+
+func TestParseRollsAndCards(t *testing.T) {
+	rolls, err := ParseRolls("3, 6 1\n12")
+	if err != nil || len(rolls) != 4 || rolls[3] != 12 {
+		t.Errorf("Unexpected ParseRolls result %v, %v", rolls, err)
+	}
+	if _, err := ParseRolls("3 x"); !errors.Is(err, ErrInvalidObservation) {
+		t.Errorf("Expected ErrInvalidObservation, got %v", err)
+	}
+
+	for card, want := range map[string]int{"AS": 0, "KS": 12, "10H": 22, "th": 22, "2C": 40, " kc ": 51} {
+		got, err := ParseCard(card)
+		if err != nil || got != want {
+			t.Errorf("ParseCard(%q) = %d, %v; expected %d", card, got, err, want)
+		}
+	}
+	for _, card := range []string{"", "S", "11S", "AZ", strings.Repeat("A", 3)} {
+		if _, err := ParseCard(card); err == nil {
+			t.Errorf("Expected error for %q", card)
+		}
+	}
+}
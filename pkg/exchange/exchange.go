@@ -0,0 +1,208 @@
+package exchange
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/armor"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// Encrypted messages between co-signers
+//
+// Co-signers pass PSBTs and multisig session messages around over email or
+// chat. Seal encrypts such a payload to the recipient's secp256k1 key and
+// authenticates the sender, in the style of ECIES with a static sender key:
+//
+//	ee  = ECDH(e, R)                      e: fresh ephemeral key
+//	se  = ECDH(s, R)                      s: sender's long-term key
+//	key = TaggedHash("cryptography-playground/exchange/v1", ee || se || E || S || R)
+//	ct  = AES-256-GCM(key, kind || payload)
+//
+// Only the holder of r can open the message, and only the holder of s (or of
+// r) can produce one that opens as coming from S. The authentication convinces
+// the recipient, not third parties. The sender key is sent in the clear, and
+// nothing prevents an attacker from replaying an old message, so payloads
+// should be safe to receive twice (a PSBT or a nonce for a known session is).
+//
+// Armor wraps sealed messages in an armored MESSAGE block so they survive
+// copy-paste.
+
+// sealVersion is the first byte of every sealed message
+const sealVersion = 0x01
+
+// keyTag is the tagged hash domain of the encryption key
+const keyTag = "cryptography-playground/exchange/v1"
+
+// headerSize is version || ephemeral key || sender key
+const headerSize = 1 + 33 + 33
+
+// maxKindSize bounds the kind label (it is length-prefixed with one byte)
+const maxKindSize = 255
+
+// Payload kinds used by the repository
+const (
+	KindPSBT           = "psbt"
+	KindSessionMessage = "multisig-session"
+)
+
+var (
+	// ErrDecrypt is returned when a message is not for this key or was modified
+	ErrDecrypt = errors.New("message cannot be decrypted")
+	// ErrUnexpectedSender is returned by OpenFrom when another key sent the message
+	ErrUnexpectedSender = errors.New("message is from an unexpected sender")
+)
+
+// Message is an opened sealed message
+type Message struct {
+	Sender  *btcec.PublicKey
+	Kind    string // What the payload is, e.g. KindPSBT
+	Payload []byte
+}
+
+// Seal encrypts payload from sender to recipient
+//
+// Example:
+//
+//	sealed, err := exchange.Seal(alice.PrivateKey, bob.PublicKey, exchange.KindPSBT, psbt)
+//	text := exchange.Armor(sealed) // paste into an email to Bob
+func Seal(sender *btcec.PrivateKey, recipient *btcec.PublicKey, kind string, payload []byte) ([]byte, error) {
+	// Step 1: Validate inputs
+	if sender == nil || recipient == nil {
+		return nil, errors.New("sender and recipient keys are required")
+	}
+	if len(kind) > maxKindSize {
+		return nil, fmt.Errorf("kind must be at most %d bytes", maxKindSize)
+	}
+
+	// Step 2: Fresh ephemeral key
+	ephemeral, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ephemeral key: %w", err)
+	}
+	defer ephemeral.Zero()
+
+	// Step 3: Derive the key from both shared secrets
+	header := make([]byte, 0, headerSize)
+	header = append(header, sealVersion)
+	header = append(header, ephemeral.PubKey().SerializeCompressed()...)
+	header = append(header, sender.PubKey().SerializeCompressed()...)
+	aead, err := deriveAEAD(
+		btcec.GenerateSharedSecret(ephemeral, recipient),
+		btcec.GenerateSharedSecret(sender, recipient),
+		header[1:], recipient,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 4: Encrypt kind || payload, authenticating the header
+	plaintext := make([]byte, 0, 1+len(kind)+len(payload))
+	plaintext = append(plaintext, byte(len(kind)))
+	plaintext = append(plaintext, kind...)
+	plaintext = append(plaintext, payload...)
+	defer clear(plaintext)
+	nonce := make([]byte, aead.NonceSize()) // The key is never reused
+	return aead.Seal(header, nonce, plaintext, header), nil
+}
+
+// Open decrypts a message sent to recipient and reports who sent it
+//
+// The caller must check that Message.Sender is someone it expects; OpenFrom
+// does this for a single known sender.
+func Open(recipient *btcec.PrivateKey, sealed []byte) (*Message, error) {
+	// Step 1: Parse the header
+	if recipient == nil {
+		return nil, errors.New("recipient key is required")
+	}
+	if len(sealed) < headerSize || sealed[0] != sealVersion {
+		return nil, ErrDecrypt
+	}
+	ephemeral, err := btcec.ParsePubKey(sealed[1:34])
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	sender, err := btcec.ParsePubKey(sealed[34:headerSize])
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	// Step 2: Same key as the sender: ECDH(r, E) = ECDH(e, R), ECDH(r, S) = ECDH(s, R)
+	aead, err := deriveAEAD(
+		btcec.GenerateSharedSecret(recipient, ephemeral),
+		btcec.GenerateSharedSecret(recipient, sender),
+		sealed[1:headerSize], recipient.PubKey(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Decrypt and split kind || payload
+	nonce := make([]byte, aead.NonceSize())
+	plaintext, err := aead.Open(nil, nonce, sealed[headerSize:], sealed[:headerSize])
+	if err != nil || len(plaintext) < 1 || len(plaintext) < 1+int(plaintext[0]) {
+		return nil, ErrDecrypt
+	}
+	kindLen := int(plaintext[0])
+	return &Message{
+		Sender:  sender,
+		Kind:    string(plaintext[1 : 1+kindLen]),
+		Payload: plaintext[1+kindLen:],
+	}, nil
+}
+
+// OpenFrom opens a message and checks that it came from sender with the given kind
+//
+// Example:
+//
+//	psbt, err := exchange.OpenFrom(bob.PrivateKey, alice.PublicKey, exchange.KindPSBT, sealed)
+func OpenFrom(recipient *btcec.PrivateKey, sender *btcec.PublicKey, kind string, sealed []byte) ([]byte, error) {
+	msg, err := Open(recipient, sealed)
+	if err != nil {
+		return nil, err
+	}
+	if sender == nil || !msg.Sender.IsEqual(sender) {
+		return nil, ErrUnexpectedSender
+	}
+	if msg.Kind != kind {
+		return nil, fmt.Errorf("expected a %q message, got %q", kind, msg.Kind)
+	}
+	return msg.Payload, nil
+}
+
+// deriveAEAD hashes the shared secrets and public keys into an AES-256-GCM cipher
+func deriveAEAD(ee, se, ephemeralAndSender []byte, recipient *btcec.PublicKey) (cipher.AEAD, error) {
+	key := schnorr.TaggedHash(keyTag, ee, se, ephemeralAndSender, recipient.SerializeCompressed())
+	defer clear(key[:])
+	clear(ee)
+	clear(se)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Armor encodes a sealed message as copy-paste safe text (see package armor)
+//
+// Example:
+//
+//	fmt.Println(exchange.Armor(sealed))
+//	// -----BEGIN PLAYGROUND MESSAGE-----
+//	// AQJ5vmZ...
+//	// =SN8W
+//	// -----END PLAYGROUND MESSAGE-----
+func Armor(sealed []byte) string {
+	return armor.Encode(armor.TypeMessage, nil, sealed)
+}
+
+// Dearmor extracts a sealed message from text produced by Armor
+//
+// Text around the armor (a mail quote, a chat nickname) and line endings
+// mangled by the transport are ignored.
+func Dearmor(text string) ([]byte, error) {
+	return armor.DecodeType(text, armor.TypeMessage)
+}
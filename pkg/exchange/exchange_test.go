@@ -0,0 +1,127 @@
+package exchange
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// newKey returns a deterministic test key
+func newKey(seed byte) *btcec.PrivateKey {
+	priv, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{seed}, 32))
+	return priv
+}
+
+func TestSealOpen(t *testing.T) {
+	alice, bob := newKey(0x01), newKey(0x02)
+	psbt := []byte("psbt\xff\x01\x00 not a real psbt")
+
+	sealed, err := Seal(alice, bob.PubKey(), KindPSBT, psbt)
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	msg, err := Open(bob, sealed)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if !msg.Sender.IsEqual(alice.PubKey()) || msg.Kind != KindPSBT || !bytes.Equal(msg.Payload, psbt) {
+		t.Errorf("Expected Alice's PSBT, got sender %x kind %q payload %q", msg.Sender.SerializeCompressed(), msg.Kind, msg.Payload)
+	}
+	if payload, err := OpenFrom(bob, alice.PubKey(), KindPSBT, sealed); err != nil || !bytes.Equal(payload, psbt) {
+		t.Errorf("Expected OpenFrom to return the PSBT, got %q (%v)", payload, err)
+	}
+
+	// Every message uses a fresh ephemeral key
+	again, _ := Seal(alice, bob.PubKey(), KindPSBT, psbt)
+	if bytes.Equal(again, sealed) {
+		t.Error("Expected two seals of the same payload to differ")
+	}
+
+	// Empty payloads and kinds are allowed
+	sealed, _ = Seal(alice, bob.PubKey(), "", nil)
+	if msg, err := Open(bob, sealed); err != nil || msg.Kind != "" || len(msg.Payload) != 0 {
+		t.Errorf("Expected an empty message, got %+v (%v)", msg, err)
+	}
+}
+
+// TestOpenRejects tests the failure modes of Open and OpenFrom
+func TestOpenRejects(t *testing.T) {
+	alice, bob, carol := newKey(0x01), newKey(0x02), newKey(0x03)
+	sealed, err := Seal(alice, bob.PubKey(), KindSessionMessage, []byte("nonce"))
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+
+	// Carol is not the recipient
+	if _, err := Open(carol, sealed); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Expected ErrDecrypt for the wrong recipient, got %v", err)
+	}
+
+	// Any modified byte, including the sender key in the header, is detected
+	for _, i := range []int{0, 1, 40, headerSize, len(sealed) - 1} {
+		tampered := append([]byte(nil), sealed...)
+		tampered[i] ^= 0x01
+		if _, err := Open(bob, tampered); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("Expected ErrDecrypt for byte %d modified, got %v", i, err)
+		}
+	}
+
+	// Claiming to be Alice without her key fails: swap in her key as sender
+	forged, _ := Seal(carol, bob.PubKey(), KindSessionMessage, []byte("nonce"))
+	copy(forged[34:headerSize], alice.PubKey().SerializeCompressed())
+	if _, err := Open(bob, forged); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Expected ErrDecrypt for a forged sender, got %v", err)
+	}
+
+	// A genuine message from Carol is not accepted as Alice's
+	fromCarol, _ := Seal(carol, bob.PubKey(), KindSessionMessage, []byte("nonce"))
+	if _, err := OpenFrom(bob, alice.PubKey(), KindSessionMessage, fromCarol); !errors.Is(err, ErrUnexpectedSender) {
+		t.Errorf("Expected ErrUnexpectedSender, got %v", err)
+	}
+	if _, err := OpenFrom(bob, alice.PubKey(), KindPSBT, sealed); err == nil {
+		t.Error("Expected error for the wrong kind")
+	}
+	if _, err := Open(bob, sealed[:headerSize-1]); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Expected ErrDecrypt for a truncated message, got %v", err)
+	}
+}
+
+func TestArmor(t *testing.T) {
+	alice, bob := newKey(0x01), newKey(0x02)
+	sealed, err := Seal(alice, bob.PubKey(), KindPSBT, bytes.Repeat([]byte("psbt"), 40))
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	text := Armor(sealed)
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"as is", text},
+		{"surrounding text", "Hi Bob, here it is:\n\n" + text + "\nCheers, Alice"},
+		{"CRLF", strings.ReplaceAll(text, "\n", "\r\n")},
+		{"quoted reply", "> " + strings.ReplaceAll(text, "\n", "\n> ")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := Dearmor(tt.text)
+			if err != nil {
+				t.Fatalf("Dearmor failed: %v", err)
+			}
+			if !bytes.Equal(decoded, sealed) {
+				t.Error("Expected the sealed message back")
+			}
+		})
+	}
+
+	if _, err := Dearmor("no message here"); err == nil {
+		t.Error("Expected error for text without armor")
+	}
+	if _, err := Dearmor(strings.Replace(text, "MESSAGE", "SIGNATURE", 2)); err == nil {
+		t.Error("Expected error for another block type")
+	}
+}
@@ -0,0 +1,64 @@
+package fixtures
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestDeterministicReader(t *testing.T) {
+	a, b := make([]byte, 100), make([]byte, 100)
+	if _, err := io.ReadFull(DeterministicReader("seed"), a); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if _, err := io.ReadFull(DeterministicReader("seed"), b); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Error("Same seed should produce the same stream")
+	}
+
+	// Reading in small chunks yields the same stream
+	r := DeterministicReader("seed")
+	chunked := make([]byte, 0, 100)
+	chunk := make([]byte, 7)
+	for len(chunked) < 100 {
+		n, _ := r.Read(chunk[:min(7, 100-len(chunked))])
+		chunked = append(chunked, chunk[:n]...)
+	}
+	if !bytes.Equal(a, chunked) {
+		t.Error("Chunked reads should match a single read")
+	}
+
+	c := make([]byte, 100)
+	io.ReadFull(DeterministicReader("other"), c)
+	if bytes.Equal(a, c) {
+		t.Error("Different seeds should produce different streams")
+	}
+}
+
+func TestGolden(t *testing.T) {
+	// The checked-in golden file
+	Golden(t, "example.golden", []byte("hello fixtures\n"))
+
+	var v struct {
+		Name string `json:"name"`
+	}
+	LoadJSON(t, "example.golden.json", &v)
+	if v.Name != "fixtures" {
+		t.Errorf("LoadJSON() name = %s, expected fixtures", v.Name)
+	}
+	GoldenJSON(t, "example.golden.json", v)
+}
+
+func TestStoreAndLoad(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	Store(t, "new.golden", []byte("data"))
+	if got := Load(t, "new.golden"); string(got) != "data" {
+		t.Errorf("Load() = %q, expected %q", got, "data")
+	}
+	if Path("x") != "testdata/x" {
+		t.Errorf("Path() = %s, expected testdata/x", Path("x"))
+	}
+}
@@ -0,0 +1,273 @@
+package fsm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Protocol state machines
+//
+// Interactive protocols (MuSig sessions, distributed key generation, atomic
+// swaps) move through a fixed sequence of rounds, and most bugs in them are
+// steps taken in the wrong round: signing before every nonce is known, sending
+// shares twice, redeeming before both sides locked. A Definition lists the
+// events of a protocol, each allowed in a set of states and leading to one
+// state:
+//
+//	commitments-complete: commit -> nonce
+//	add-nonce:            nonce  (stays)
+//	nonces-complete:      nonce  -> sign
+//
+// A Machine tracks one run of the protocol. It refuses events that are not
+// allowed in its current state with a *TransitionError, and can be saved as a
+// Snapshot and restored. Because every protocol states its lifecycle the same
+// way, Validate and Enabled let tests check all of them uniformly.
+
+// ErrIllegalTransition is matched by every *TransitionError
+var ErrIllegalTransition = errors.New("illegal state transition")
+
+// Event is a step of a protocol
+type Event[S comparable] struct {
+	Name string // Unique within the definition
+	From []S    // States the event is allowed in
+	To   S      // State after the event (ignored with Stay)
+	Stay bool   // The event is allowed in From but does not change the state
+}
+
+// Definition describes the states and events of a protocol
+//
+// Definitions are usually package-level variables built once and shared by
+// every Machine of the protocol.
+//
+// Example:
+//
+//	var lifecycle = &fsm.Definition[State]{
+//		Name:    "door",
+//		Initial: Closed,
+//		Events: []fsm.Event[State]{
+//			{Name: "open", From: []State{Closed}, To: Open},
+//			{Name: "close", From: []State{Open}, To: Closed},
+//		},
+//		Err: ErrWrongState,
+//	}
+type Definition[S comparable] struct {
+	Name    string     // Protocol name used in errors and snapshots
+	Initial S          // State of a new Machine
+	Final   []S        // States the protocol ends in; no event may leave them
+	Events  []Event[S] // Allowed events
+	Err     error      // Optional protocol sentinel that transition errors also match
+}
+
+// Validate checks the definition for unreachable states and dead ends
+//
+// Every event needs a unique name and at least one From state, every state
+// must be reachable from Initial, final states must have no event leaving
+// them, and every other state must have one.
+func (d *Definition[S]) Validate() error {
+	// Step 1: Event names and sources
+	names := make(map[string]bool, len(d.Events))
+	for _, ev := range d.Events {
+		if ev.Name == "" || names[ev.Name] {
+			return fmt.Errorf("%s: missing or duplicate event name %q", d.Name, ev.Name)
+		}
+		names[ev.Name] = true
+		if len(ev.From) == 0 {
+			return fmt.Errorf("%s: event %q is not allowed in any state", d.Name, ev.Name)
+		}
+	}
+
+	// Step 2: Reachability from the initial state
+	reached := map[S]bool{d.Initial: true}
+	queue := []S{d.Initial}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, ev := range d.Events {
+			if ev.Stay || !slices.Contains(ev.From, state) || reached[ev.To] {
+				continue
+			}
+			reached[ev.To] = true
+			queue = append(queue, ev.To)
+		}
+	}
+
+	// Step 3: Every state is reachable; only final states are dead ends
+	for _, state := range d.States() {
+		if !reached[state] {
+			return fmt.Errorf("%s: state %v is unreachable", d.Name, state)
+		}
+		leaves := slices.ContainsFunc(d.Events, func(ev Event[S]) bool {
+			return !ev.Stay && ev.To != state && slices.Contains(ev.From, state)
+		})
+		final := slices.Contains(d.Final, state)
+		if final && leaves {
+			return fmt.Errorf("%s: final state %v has a way out", d.Name, state)
+		}
+		if !final && !leaves {
+			return fmt.Errorf("%s: state %v is a dead end", d.Name, state)
+		}
+	}
+	return nil
+}
+
+// States returns every state of the definition, in order of first appearance
+func (d *Definition[S]) States() []S {
+	states := []S{d.Initial}
+	add := func(s S) {
+		if !slices.Contains(states, s) {
+			states = append(states, s)
+		}
+	}
+	for _, ev := range d.Events {
+		for _, from := range ev.From {
+			add(from)
+		}
+		if !ev.Stay {
+			add(ev.To)
+		}
+	}
+	for _, s := range d.Final {
+		add(s)
+	}
+	return states
+}
+
+// Enabled returns the names of the events allowed in state
+func (d *Definition[S]) Enabled(state S) []string {
+	var names []string
+	for _, ev := range d.Events {
+		if slices.Contains(ev.From, state) {
+			names = append(names, ev.Name)
+		}
+	}
+	return names
+}
+
+// event looks up an event by name
+func (d *Definition[S]) event(name string) (*Event[S], bool) {
+	for i := range d.Events {
+		if d.Events[i].Name == name {
+			return &d.Events[i], true
+		}
+	}
+	return nil, false
+}
+
+// TransitionError reports an event that is not allowed in the current state
+type TransitionError[S comparable] struct {
+	Protocol string
+	Event    string
+	State    S
+	err      error // Definition.Err
+}
+
+// Error describes the refused event
+func (e *TransitionError[S]) Error() string {
+	if e.err != nil {
+		return fmt.Sprintf("%v: %s in state %v", e.err, e.Event, e.State)
+	}
+	return fmt.Sprintf("%s: %v: %s in state %v", e.Protocol, ErrIllegalTransition, e.Event, e.State)
+}
+
+// Unwrap lets errors.Is match ErrIllegalTransition and the protocol's own sentinel
+func (e *TransitionError[S]) Unwrap() []error {
+	if e.err == nil {
+		return []error{ErrIllegalTransition}
+	}
+	return []error{ErrIllegalTransition, e.err}
+}
+
+// Machine is one run of a protocol
+//
+// A Machine is not safe for concurrent use; protocols guard it with the same
+// lock as the rest of their state.
+type Machine[S comparable] struct {
+	def   *Definition[S]
+	state S
+	steps uint64
+}
+
+// Snapshot is the serializable state of a Machine
+type Snapshot[S comparable] struct {
+	Protocol string `json:"protocol"`
+	State    S      `json:"state"`
+	Steps    uint64 `json:"steps"` // Number of state changes so far
+}
+
+// New starts a machine in the definition's initial state
+//
+// Example:
+//
+//	m := fsm.New(lifecycle)
+//	err := m.Fire("open") // m.State() == Open
+//	err = m.Fire("open")  // errors.Is(err, fsm.ErrIllegalTransition)
+func New[S comparable](def *Definition[S]) *Machine[S] {
+	return &Machine[S]{def: def, state: def.Initial}
+}
+
+// Restore continues a machine from a snapshot
+//
+// The snapshot must belong to the same protocol and name one of its states.
+func Restore[S comparable](def *Definition[S], snapshot Snapshot[S]) (*Machine[S], error) {
+	if snapshot.Protocol != def.Name {
+		return nil, fmt.Errorf("snapshot of protocol %q, expected %q", snapshot.Protocol, def.Name)
+	}
+	if !slices.Contains(def.States(), snapshot.State) {
+		return nil, fmt.Errorf("%s: unknown state %v", def.Name, snapshot.State)
+	}
+	return &Machine[S]{def: def, state: snapshot.State, steps: snapshot.Steps}, nil
+}
+
+// State returns the current state
+func (m *Machine[S]) State() S {
+	return m.state
+}
+
+// Steps returns the number of state changes since the protocol started
+func (m *Machine[S]) Steps() uint64 {
+	return m.steps
+}
+
+// Done reports whether the machine reached a final state
+func (m *Machine[S]) Done() bool {
+	return slices.Contains(m.def.Final, m.state)
+}
+
+// Can reports whether event is allowed in the current state
+func (m *Machine[S]) Can(event string) bool {
+	return m.Check(event) == nil
+}
+
+// Check returns a *TransitionError unless event is allowed in the current state
+//
+// Protocols call Check before doing the work of a step and Fire once it
+// succeeded, so a failed step leaves the state unchanged.
+func (m *Machine[S]) Check(event string) error {
+	ev, ok := m.def.event(event)
+	if !ok {
+		return fmt.Errorf("%s: unknown event %q", m.def.Name, event)
+	}
+	if !slices.Contains(ev.From, m.state) {
+		return &TransitionError[S]{Protocol: m.def.Name, Event: event, State: m.state, err: m.def.Err}
+	}
+	return nil
+}
+
+// Fire applies event, moving to its target state
+func (m *Machine[S]) Fire(event string) error {
+	if err := m.Check(event); err != nil {
+		return err
+	}
+	ev, _ := m.def.event(event)
+	if !ev.Stay && ev.To != m.state {
+		m.state = ev.To
+		m.steps++
+	}
+	return nil
+}
+
+// Snapshot returns the serializable state of the machine
+func (m *Machine[S]) Snapshot() Snapshot[S] {
+	return Snapshot[S]{Protocol: m.def.Name, State: m.state, Steps: m.steps}
+}
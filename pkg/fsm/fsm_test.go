@@ -0,0 +1,188 @@
+package fsm
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type doorState int
+
+const (
+	closed doorState = iota
+	open
+	locked
+	broken
+)
+
+var errDoor = errors.New("door cannot do that now")
+
+func doorDefinition() *Definition[doorState] {
+	return &Definition[doorState]{
+		Name:    "door",
+		Initial: closed,
+		Final:   []doorState{broken},
+		Events: []Event[doorState]{
+			{Name: "open", From: []doorState{closed}, To: open},
+			{Name: "close", From: []doorState{open}, To: closed},
+			{Name: "lock", From: []doorState{closed}, To: locked},
+			{Name: "unlock", From: []doorState{locked}, To: closed},
+			{Name: "knock", From: []doorState{closed, locked}, Stay: true},
+			{Name: "kick", From: []doorState{closed, locked}, To: broken},
+		},
+		Err: errDoor,
+	}
+}
+
+func TestMachine(t *testing.T) {
+	def := doorDefinition()
+	if err := def.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	m := New(def)
+	steps := []struct {
+		event    string
+		allowed  bool
+		expected doorState
+	}{
+		{"close", false, closed},
+		{"open", true, open},
+		{"knock", false, open},
+		{"close", true, closed},
+		{"lock", true, locked},
+		{"knock", true, locked},
+		{"open", false, locked},
+		{"kick", true, broken},
+		{"unlock", false, broken},
+	}
+	for i, step := range steps {
+		err := m.Fire(step.event)
+		if step.allowed && err != nil {
+			t.Fatalf("Step %d (%s): Expected success, got %v", i, step.event, err)
+		}
+		if !step.allowed {
+			var te *TransitionError[doorState]
+			if !errors.As(err, &te) || !errors.Is(err, ErrIllegalTransition) || !errors.Is(err, errDoor) {
+				t.Fatalf("Step %d (%s): Expected a transition error, got %v", i, step.event, err)
+			}
+			if te.Event != step.event || te.State != step.expected {
+				t.Errorf("Step %d: Unexpected error details %+v", i, te)
+			}
+		}
+		if m.State() != step.expected {
+			t.Fatalf("Step %d (%s): Expected state %d, got %d", i, step.event, step.expected, m.State())
+		}
+	}
+	if !m.Done() || m.Steps() != 4 {
+		t.Errorf("Expected a finished machine after 4 state changes, got done=%v steps=%d", m.Done(), m.Steps())
+	}
+	if err := m.Fire("fly"); err == nil || errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("Expected an unknown event error, got %v", err)
+	}
+}
+
+func TestTransitionErrorWithoutSentinel(t *testing.T) {
+	def := doorDefinition()
+	def.Err = nil
+	err := New(def).Fire("close")
+	if !errors.Is(err, ErrIllegalTransition) || !strings.HasPrefix(err.Error(), "door:") {
+		t.Errorf("Expected a protocol-prefixed transition error, got %v", err)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	def := doorDefinition()
+	m := New(def)
+	_ = m.Fire("lock")
+
+	data, err := json.Marshal(m.Snapshot())
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var snapshot Snapshot[doorState]
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	restored, err := Restore(def, snapshot)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if restored.State() != locked || restored.Steps() != 1 || !restored.Can("unlock") {
+		t.Errorf("Expected the restored machine to be locked, got %+v", restored.Snapshot())
+	}
+
+	tests := []struct {
+		name     string
+		snapshot Snapshot[doorState]
+	}{
+		{"other protocol", Snapshot[doorState]{Protocol: "window", State: closed}},
+		{"unknown state", Snapshot[doorState]{Protocol: "door", State: 42}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Restore(def, tt.snapshot); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Definition[doorState])
+	}{
+		{"duplicate event", func(d *Definition[doorState]) { d.Events[1].Name = "open" }},
+		{"unnamed event", func(d *Definition[doorState]) { d.Events[0].Name = "" }},
+		{"event without source", func(d *Definition[doorState]) { d.Events[4].From = nil }},
+		{"unreachable state", func(d *Definition[doorState]) { d.Events[2].From = []doorState{locked} }},
+		{"dead end", func(d *Definition[doorState]) { d.Final = nil }},
+		{"final state with a way out", func(d *Definition[doorState]) { d.Final = []doorState{broken, locked} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := doorDefinition()
+			tt.modify(def)
+			if err := def.Validate(); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	def := doorDefinition()
+	if got := def.States(); !slices.Equal(got, []doorState{closed, open, locked, broken}) {
+		t.Errorf("Unexpected states %v", got)
+	}
+	if got := def.Enabled(locked); !slices.Equal(got, []string{"unlock", "knock", "kick"}) {
+		t.Errorf("Unexpected events enabled when locked: %v", got)
+	}
+}
+
+// TestRandomWalk fires random events and checks the machine only moves along the definition
+func TestRandomWalk(t *testing.T) {
+	def := doorDefinition()
+	rng := rand.New(rand.NewSource(1))
+	for walk := 0; walk < 100; walk++ {
+		m := New(def)
+		for i := 0; i < 20; i++ {
+			ev := def.Events[rng.Intn(len(def.Events))]
+			before := m.State()
+			err := m.Fire(ev.Name)
+			if slices.Contains(ev.From, before) != (err == nil) {
+				t.Fatalf("Event %s in state %d: unexpected result %v", ev.Name, before, err)
+			}
+			switch {
+			case err != nil, ev.Stay:
+				if m.State() != before {
+					t.Fatalf("Event %s changed the state from %d to %d", ev.Name, before, m.State())
+				}
+			case m.State() != ev.To:
+				t.Fatalf("Event %s: Expected state %d, got %d", ev.Name, ev.To, m.State())
+			}
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package group
+
+import (
+	"math/big"
+)
+
+// edwards25519Group is the prime-order subgroup of edwards25519
+//
+// The curve is -x² + y² = 1 + d·x²·y² over GF(2^255 - 19). It has 8·L points,
+// so ParsePoint rejects anything outside the subgroup of order L generated by
+// the RFC 8032 base point. Points use extended coordinates (X : Y : Z : T) with
+// x = X/Z, y = Y/Z and x·y = T/Z, where one addition formula is complete and
+// also serves for doubling.
+type edwards25519Group struct{}
+
+// edwards25519Point is a point in extended coordinates
+type edwards25519Point struct {
+	x, y, z, t *big.Int
+}
+
+var (
+	// edP is the field prime 2^255 - 19
+	edP = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	// edL is the prime order of the base point, 2^252 + 27742317777372353535851937790883648493
+	edL, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	// edD is -121665/121666 mod p
+	edD = edFieldMul(big.NewInt(-121665), edFieldInv(big.NewInt(121666)))
+	// ed2D is 2·d, used by the addition formula
+	ed2D = edFieldMul(big.NewInt(2), edD)
+	// edSqrtM1 is a square root of -1, 2^((p-1)/4) mod p
+	edSqrtM1 = new(big.Int).Exp(big.NewInt(2), new(big.Int).Rsh(new(big.Int).Sub(edP, big.NewInt(1)), 2), edP)
+	// edBase is the RFC 8032 base point, y = 4/5 with even x
+	edBase = mustDecodeEdwards25519([]byte{
+		0x58, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+		0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+		0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+		0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+	})
+)
+
+// Edwards25519 returns the prime-order subgroup of edwards25519
+//
+// Points encode as in RFC 8032: 32 bytes holding little-endian y with the
+// low bit of x in the top bit. This is the group behind Ed25519, but Schnorr
+// signatures built on it here are not Ed25519 signatures.
+func Edwards25519() Group {
+	return edwards25519Group{}
+}
+
+// Name implements Group
+func (edwards25519Group) Name() string { return "edwards25519" }
+
+// Order implements Group
+func (edwards25519Group) Order() *big.Int { return edL }
+
+// PointSize implements Group
+func (edwards25519Group) PointSize() int { return 32 }
+
+// Generator implements Group
+func (edwards25519Group) Generator() Point { return edBase }
+
+// Identity implements Group
+func (edwards25519Group) Identity() Point { return edIdentity() }
+
+// ScalarBaseMult implements Group
+func (edwards25519Group) ScalarBaseMult(k *big.Int) Point {
+	return edBase.ScalarMult(k)
+}
+
+// ParsePoint implements Group
+//
+// Besides checking the encoding, it multiplies the point by L so that points
+// with a small-order component (the cofactor 8) are rejected.
+func (edwards25519Group) ParsePoint(b []byte) (Point, error) {
+	p, err := decodeEdwards25519(b)
+	if err != nil {
+		return nil, err
+	}
+	if p.IsIdentity() {
+		return nil, ErrIdentity
+	}
+	if !p.mul(edL).IsIdentity() {
+		return nil, ErrInvalidPoint
+	}
+	return p, nil
+}
+
+// Add implements Point
+func (a *edwards25519Point) Add(q Point) Point {
+	b := q.(*edwards25519Point)
+
+	// Step 1: A = (Y1-X1)(Y2-X2), B = (Y1+X1)(Y2+X2), C = 2d·T1·T2, D = 2·Z1·Z2
+	A := edFieldMul(new(big.Int).Sub(a.y, a.x), new(big.Int).Sub(b.y, b.x))
+	B := edFieldMul(new(big.Int).Add(a.y, a.x), new(big.Int).Add(b.y, b.x))
+	C := edFieldMul(edFieldMul(a.t, b.t), ed2D)
+	D := edFieldMul(big.NewInt(2), edFieldMul(a.z, b.z))
+
+	// Step 2: E = B-A, F = D-C, G = D+C, H = B+A
+	E := new(big.Int).Sub(B, A)
+	F := new(big.Int).Sub(D, C)
+	G := new(big.Int).Add(D, C)
+	H := new(big.Int).Add(B, A)
+
+	// Step 3: X3 = E·F, Y3 = G·H, T3 = E·H, Z3 = F·G
+	return &edwards25519Point{
+		x: edFieldMul(E, F),
+		y: edFieldMul(G, H),
+		z: edFieldMul(F, G),
+		t: edFieldMul(E, H),
+	}
+}
+
+// Neg implements Point
+func (a *edwards25519Point) Neg() Point {
+	return &edwards25519Point{
+		x: new(big.Int).Mod(new(big.Int).Neg(a.x), edP),
+		y: a.y,
+		z: a.z,
+		t: new(big.Int).Mod(new(big.Int).Neg(a.t), edP),
+	}
+}
+
+// ScalarMult implements Point
+func (a *edwards25519Point) ScalarMult(k *big.Int) Point {
+	return a.mul(new(big.Int).Mod(k, edL))
+}
+
+// mul computes k·a by double-and-add without reducing k
+func (a *edwards25519Point) mul(k *big.Int) *edwards25519Point {
+	r := edIdentity()
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		r = r.Add(r).(*edwards25519Point)
+		if k.Bit(i) == 1 {
+			r = r.Add(a).(*edwards25519Point)
+		}
+	}
+	return r
+}
+
+// Equal implements Point by cross-multiplying: X1·Z2 = X2·Z1 and Y1·Z2 = Y2·Z1
+func (a *edwards25519Point) Equal(q Point) bool {
+	b := q.(*edwards25519Point)
+	return edFieldMul(a.x, b.z).Cmp(edFieldMul(b.x, a.z)) == 0 &&
+		edFieldMul(a.y, b.z).Cmp(edFieldMul(b.y, a.z)) == 0
+}
+
+// IsIdentity implements Point: the identity is (0, 1)
+func (a *edwards25519Point) IsIdentity() bool {
+	return a.x.Sign() == 0 && a.y.Cmp(a.z) == 0
+}
+
+// Bytes implements Point: the RFC 8032 encoding
+func (a *edwards25519Point) Bytes() []byte {
+	zInv := edFieldInv(a.z)
+	x := edFieldMul(a.x, zInv)
+	y := edFieldMul(a.y, zInv)
+
+	out := make([]byte, 32)
+	y.FillBytes(out)
+	reverse(out)
+	out[31] |= byte(x.Bit(0)) << 7
+	return out
+}
+
+// decodeEdwards25519 recovers x from y and its sign bit (RFC 8032 section 5.1.3)
+func decodeEdwards25519(b []byte) (*edwards25519Point, error) {
+	if len(b) != 32 {
+		return nil, ErrInvalidPoint
+	}
+
+	// Step 1: split off the sign bit and read y little-endian
+	buf := append([]byte(nil), b...)
+	sign := uint(buf[31] >> 7)
+	buf[31] &= 0x7f
+	reverse(buf)
+	y := new(big.Int).SetBytes(buf)
+	if y.Cmp(edP) >= 0 {
+		return nil, ErrInvalidPoint
+	}
+
+	// Step 2: x² = u/v with u = y² - 1 and v = d·y² + 1
+	y2 := edFieldMul(y, y)
+	u := new(big.Int).Mod(new(big.Int).Sub(y2, big.NewInt(1)), edP)
+	v := new(big.Int).Mod(new(big.Int).Add(edFieldMul(edD, y2), big.NewInt(1)), edP)
+
+	// Step 3: candidate root x = u·v³·(u·v⁷)^((p-5)/8)
+	v3 := edFieldMul(edFieldMul(v, v), v)
+	v7 := edFieldMul(edFieldMul(v3, v3), v)
+	exp := new(big.Int).Rsh(new(big.Int).Sub(edP, big.NewInt(5)), 3)
+	x := edFieldMul(edFieldMul(u, v3), new(big.Int).Exp(edFieldMul(u, v7), exp, edP))
+
+	// Step 4: fix up by sqrt(-1) or give up if u/v is not a square
+	vx2 := edFieldMul(v, edFieldMul(x, x))
+	switch {
+	case vx2.Cmp(u) == 0:
+	case vx2.Cmp(new(big.Int).Mod(new(big.Int).Neg(u), edP)) == 0:
+		x = edFieldMul(x, edSqrtM1)
+	default:
+		return nil, ErrInvalidPoint
+	}
+
+	// Step 5: pick the root matching the sign bit; x = 0 has no negative
+	if x.Sign() == 0 && sign == 1 {
+		return nil, ErrInvalidPoint
+	}
+	if x.Bit(0) != sign {
+		x.Sub(edP, x)
+	}
+	return &edwards25519Point{x: x, y: y, z: big.NewInt(1), t: edFieldMul(x, y)}, nil
+}
+
+// mustDecodeEdwards25519 decodes a constant point
+func mustDecodeEdwards25519(b []byte) *edwards25519Point {
+	p, err := decodeEdwards25519(b)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// edIdentity returns (0 : 1 : 1 : 0)
+func edIdentity() *edwards25519Point {
+	return &edwards25519Point{x: big.NewInt(0), y: big.NewInt(1), z: big.NewInt(1), t: big.NewInt(0)}
+}
+
+// edFieldMul returns a·b mod p
+func edFieldMul(a, b *big.Int) *big.Int {
+	r := new(big.Int).Mul(a, b)
+	return r.Mod(r, edP)
+}
+
+// edFieldInv returns a^-1 mod p
+func edFieldInv(a *big.Int) *big.Int {
+	return new(big.Int).Exp(a, new(big.Int).Sub(edP, big.NewInt(2)), edP)
+}
+
+// reverse reverses b in place (big.Int is big-endian, RFC 8032 little-endian)
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
@@ -0,0 +1,57 @@
+package group
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// TestEdwards25519MatchesEd25519 derives Ed25519 public keys by hand: the
+// secret scalar is the clamped first half of SHA-512(seed), little-endian.
+func TestEdwards25519MatchesEd25519(t *testing.T) {
+	g := Edwards25519()
+	for _, fill := range []byte{0x00, 0x01, 0x9d} {
+		seed := bytes.Repeat([]byte{fill}, ed25519.SeedSize)
+		want := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+
+		h := sha512.Sum512(seed)
+		h[0] &= 248
+		h[31] &= 127
+		h[31] |= 64
+		le := h[:32]
+		reverse(le)
+		a := new(big.Int).SetBytes(le)
+
+		if got := g.ScalarBaseMult(a).Bytes(); !bytes.Equal(got, want) {
+			t.Errorf("seed %02x: Expected %x, got %x", fill, []byte(want), got)
+		}
+	}
+}
+
+func TestEdwards25519ParseErrors(t *testing.T) {
+	g := Edwards25519()
+
+	// A valid curve point of order 8, outside the prime-order subgroup
+	smallOrder, _ := hex.DecodeString("c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a")
+
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"y not reduced", append(bytes.Repeat([]byte{0xff}, 31), 0x7f)},
+		{"negative zero x", append([]byte{0x01}, append(make([]byte, 30), 0x80)...)},
+		{"small order", smallOrder},
+		{"short", make([]byte, 31)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := g.ParsePoint(tt.input); !errors.Is(err, ErrInvalidPoint) {
+				t.Errorf("Expected ErrInvalidPoint, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package group
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Prime-order groups
+//
+// Schnorr signatures, Pedersen commitments and most of the protocols in this
+// repository only need a cyclic group of prime order n with a generator G:
+// adding points, multiplying them by scalars mod n, and encoding them. Group
+// hides which curve provides those operations, so the same protocol code can
+// run over
+//
+//	Secp256k1()    Bitcoin's curve (btcec), 33-byte compressed points
+//	P256()         NIST P-256 (crypto/elliptic), 33-byte compressed points
+//	Edwards25519() the prime-order subgroup of Curve25519 in Edwards form,
+//	               32-byte RFC 8032 encoding
+//
+// and be compared across them. Scalars are *big.Int and are reduced mod n by
+// every operation. The backends favour clarity over speed: Edwards25519 is
+// written with math/big here and is far slower than a production library.
+// Nothing in this package is constant time.
+
+var (
+	// ErrInvalidPoint is returned for encodings that are not a point of the group
+	ErrInvalidPoint = errors.New("invalid point encoding")
+	// ErrIdentity is returned when parsing the encoding of the identity element
+	ErrIdentity = errors.New("point is the identity")
+)
+
+// Point is an element of a Group
+//
+// Points are immutable; every operation returns a new point. Mixing points of
+// different groups panics.
+type Point interface {
+	// Add returns p + q
+	Add(q Point) Point
+	// Neg returns -p
+	Neg() Point
+	// ScalarMult returns k·p
+	ScalarMult(k *big.Int) Point
+	// Equal reports whether p and q are the same point
+	Equal(q Point) bool
+	// IsIdentity reports whether p is the identity element
+	IsIdentity() bool
+	// Bytes returns the canonical encoding of p
+	Bytes() []byte
+}
+
+// Group is a cyclic group of prime order with a fixed generator
+type Group interface {
+	// Name identifies the group, e.g. "secp256k1"
+	Name() string
+	// Order returns the prime order n (callers must not modify it)
+	Order() *big.Int
+	// Generator returns G
+	Generator() Point
+	// Identity returns the neutral element
+	Identity() Point
+	// ScalarBaseMult returns k·G
+	ScalarBaseMult(k *big.Int) Point
+	// ParsePoint decodes a point, rejecting the identity and points outside the group
+	ParsePoint(b []byte) (Point, error)
+	// PointSize returns the size of an encoded point in bytes
+	PointSize() int
+}
+
+// ScalarSize returns the size of a big-endian scalar of the group in bytes
+func ScalarSize(g Group) int {
+	return (g.Order().BitLen() + 7) / 8
+}
@@ -0,0 +1,96 @@
+package group
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// groups lists every backend exercised by the shared tests
+var groups = []Group{Secp256k1(), P256(), Edwards25519()}
+
+// randomScalar returns a uniform scalar mod n
+func randomScalar(t *testing.T, g Group) *big.Int {
+	t.Helper()
+	k, err := rand.Int(rand.Reader, g.Order())
+	if err != nil {
+		t.Fatalf("rand.Int failed: %v", err)
+	}
+	return k
+}
+
+func TestGroupLaws(t *testing.T) {
+	for _, g := range groups {
+		t.Run(g.Name(), func(t *testing.T) {
+			a, b := randomScalar(t, g), randomScalar(t, g)
+			G := g.Generator()
+
+			// n·G is the identity
+			if !g.ScalarBaseMult(g.Order()).IsIdentity() {
+				t.Error("Expected n·G to be the identity")
+			}
+			if !G.ScalarMult(g.Order()).IsIdentity() {
+				t.Error("Expected ScalarMult by n to give the identity")
+			}
+
+			// Distributivity: (a+b)·G = a·G + b·G
+			sum := new(big.Int).Add(a, b)
+			if !g.ScalarBaseMult(sum).Equal(g.ScalarBaseMult(a).Add(g.ScalarBaseMult(b))) {
+				t.Error("Expected (a+b)·G = a·G + b·G")
+			}
+
+			// Associativity of scalars: a·(b·G) = (a·b)·G
+			prod := new(big.Int).Mul(a, b)
+			if !g.ScalarBaseMult(b).ScalarMult(a).Equal(g.ScalarBaseMult(prod)) {
+				t.Error("Expected a·(b·G) = (a·b)·G")
+			}
+
+			// Doubling through Add agrees with multiplying by 2
+			if !G.Add(G).Equal(g.ScalarBaseMult(big.NewInt(2))) {
+				t.Error("Expected G + G = 2·G")
+			}
+
+			// Identity and negation
+			A := g.ScalarBaseMult(a)
+			if !A.Add(g.Identity()).Equal(A) || !g.Identity().Add(A).Equal(A) {
+				t.Error("Expected identity to be neutral")
+			}
+			if !A.Add(A.Neg()).IsIdentity() {
+				t.Error("Expected A + (-A) to be the identity")
+			}
+			if !A.Neg().Equal(g.ScalarBaseMult(new(big.Int).Neg(a))) {
+				t.Error("Expected -A = (-a)·G")
+			}
+			if A.Equal(g.Identity()) || g.Identity().Equal(A) {
+				t.Error("Expected A to differ from the identity")
+			}
+		})
+	}
+}
+
+func TestGroupEncoding(t *testing.T) {
+	for _, g := range groups {
+		t.Run(g.Name(), func(t *testing.T) {
+			P := g.ScalarBaseMult(randomScalar(t, g))
+			enc := P.Bytes()
+			if len(enc) != g.PointSize() {
+				t.Fatalf("Expected %d-byte encoding, got %d", g.PointSize(), len(enc))
+			}
+			parsed, err := g.ParsePoint(enc)
+			if err != nil {
+				t.Fatalf("ParsePoint failed: %v", err)
+			}
+			if !parsed.Equal(P) {
+				t.Error("Expected round trip to preserve the point")
+			}
+
+			if _, err := g.ParsePoint(g.Identity().Bytes()); !errors.Is(err, ErrIdentity) {
+				t.Errorf("Expected ErrIdentity, got %v", err)
+			}
+			if _, err := g.ParsePoint(enc[1:]); !errors.Is(err, ErrInvalidPoint) {
+				t.Errorf("Expected ErrInvalidPoint for short input, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package group
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// p256Group is the NIST P-256 group
+//
+// It uses the generic point operations of crypto/elliptic, which the standard
+// library keeps for compatibility but discourages for new production code;
+// they are fine for comparing protocols across curves.
+type p256Group struct {
+	curve elliptic.Curve
+}
+
+// p256Point is an affine P-256 point; crypto/elliptic writes the identity as (0, 0)
+type p256Point struct {
+	curve elliptic.Curve
+	x, y  *big.Int
+}
+
+// P256 returns the NIST P-256 group, backed by crypto/elliptic
+func P256() Group {
+	return p256Group{curve: elliptic.P256()}
+}
+
+// Name implements Group
+func (p256Group) Name() string { return "P-256" }
+
+// Order implements Group
+func (g p256Group) Order() *big.Int { return g.curve.Params().N }
+
+// PointSize implements Group
+func (p256Group) PointSize() int { return 33 }
+
+// Generator implements Group
+func (g p256Group) Generator() Point {
+	params := g.curve.Params()
+	return &p256Point{curve: g.curve, x: params.Gx, y: params.Gy}
+}
+
+// Identity implements Group
+func (g p256Group) Identity() Point {
+	return &p256Point{curve: g.curve, x: new(big.Int), y: new(big.Int)}
+}
+
+// ScalarBaseMult implements Group
+func (g p256Group) ScalarBaseMult(k *big.Int) Point {
+	x, y := g.curve.ScalarBaseMult(p256Scalar(g.curve, k))
+	return &p256Point{curve: g.curve, x: x, y: y}
+}
+
+// ParsePoint implements Group: it accepts 33-byte compressed points only
+func (g p256Group) ParsePoint(b []byte) (Point, error) {
+	if len(b) != 33 {
+		return nil, ErrInvalidPoint
+	}
+	if isZero(b) {
+		return nil, ErrIdentity
+	}
+	x, y := elliptic.UnmarshalCompressed(g.curve, b)
+	if x == nil {
+		return nil, ErrInvalidPoint
+	}
+	return &p256Point{curve: g.curve, x: x, y: y}, nil
+}
+
+// Add implements Point
+func (a *p256Point) Add(q Point) Point {
+	b := q.(*p256Point)
+	x, y := a.curve.Add(a.x, a.y, b.x, b.y)
+	return &p256Point{curve: a.curve, x: x, y: y}
+}
+
+// Neg implements Point
+func (a *p256Point) Neg() Point {
+	if a.IsIdentity() {
+		return a
+	}
+	y := new(big.Int).Sub(a.curve.Params().P, a.y)
+	return &p256Point{curve: a.curve, x: a.x, y: y}
+}
+
+// ScalarMult implements Point
+func (a *p256Point) ScalarMult(k *big.Int) Point {
+	if a.IsIdentity() {
+		return a
+	}
+	x, y := a.curve.ScalarMult(a.x, a.y, p256Scalar(a.curve, k))
+	return &p256Point{curve: a.curve, x: x, y: y}
+}
+
+// Equal implements Point
+func (a *p256Point) Equal(q Point) bool {
+	b := q.(*p256Point)
+	return a.x.Cmp(b.x) == 0 && a.y.Cmp(b.y) == 0
+}
+
+// IsIdentity implements Point
+func (a *p256Point) IsIdentity() bool {
+	return a.x.Sign() == 0 && a.y.Sign() == 0
+}
+
+// Bytes implements Point: the compressed encoding, or 33 zero bytes for the identity
+func (a *p256Point) Bytes() []byte {
+	if a.IsIdentity() {
+		return make([]byte, 33)
+	}
+	return elliptic.MarshalCompressed(a.curve, a.x, a.y)
+}
+
+// p256Scalar reduces k mod n and encodes it as 32 big-endian bytes
+func p256Scalar(curve elliptic.Curve, k *big.Int) []byte {
+	return new(big.Int).Mod(k, curve.Params().N).FillBytes(make([]byte, 32))
+}
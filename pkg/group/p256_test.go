@@ -0,0 +1,45 @@
+package group
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestP256MatchesECDH(t *testing.T) {
+	g := P256()
+	for _, k := range []int64{1, 3, 0xcafe} {
+		priv, err := ecdh.P256().NewPrivateKey(big.NewInt(k).FillBytes(make([]byte, 32)))
+		if err != nil {
+			t.Fatalf("NewPrivateKey failed: %v", err)
+		}
+		x, y := elliptic.Unmarshal(elliptic.P256(), priv.PublicKey().Bytes())
+		want := elliptic.MarshalCompressed(elliptic.P256(), x, y)
+		if got := g.ScalarBaseMult(big.NewInt(k)).Bytes(); !bytes.Equal(got, want) {
+			t.Errorf("k=%d: Expected %x, got %x", k, want, got)
+		}
+	}
+}
+
+func TestP256ParseErrors(t *testing.T) {
+	g := P256()
+	enc := g.Generator().Bytes()
+
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"bad prefix", append([]byte{0x04}, enc[1:]...)},
+		{"x out of range", append([]byte{0x02}, bytes.Repeat([]byte{0xff}, 32)...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := g.ParsePoint(tt.input); !errors.Is(err, ErrInvalidPoint) {
+				t.Errorf("Expected ErrInvalidPoint, got %v", err)
+			}
+		})
+	}
+}
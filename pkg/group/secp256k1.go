@@ -0,0 +1,139 @@
+package group
+
+import (
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// secp256k1Group is the group of Bitcoin's curve
+type secp256k1Group struct{}
+
+// secp256k1Point is an affine secp256k1 point; the identity has X = Y = 0
+type secp256k1Point struct {
+	p btcec.JacobianPoint
+}
+
+// secp256k1Order is n of secp256k1
+var secp256k1Order = btcec.S256().N
+
+// Secp256k1 returns the secp256k1 group, backed by btcec
+//
+// Example:
+//
+//	g := group.Secp256k1()
+//	pub := g.ScalarBaseMult(priv) // 33-byte compressed encoding via pub.Bytes()
+func Secp256k1() Group {
+	return secp256k1Group{}
+}
+
+// Name implements Group
+func (secp256k1Group) Name() string { return "secp256k1" }
+
+// Order implements Group
+func (secp256k1Group) Order() *big.Int { return secp256k1Order }
+
+// PointSize implements Group
+func (secp256k1Group) PointSize() int { return 33 }
+
+// Generator implements Group
+func (g secp256k1Group) Generator() Point {
+	return g.ScalarBaseMult(big.NewInt(1))
+}
+
+// Identity implements Group
+func (secp256k1Group) Identity() Point {
+	return &secp256k1Point{}
+}
+
+// ScalarBaseMult implements Group
+func (secp256k1Group) ScalarBaseMult(k *big.Int) Point {
+	s := toModNScalar(k)
+	r := &secp256k1Point{}
+	btcec.ScalarBaseMultNonConst(&s, &r.p)
+	r.p.ToAffine()
+	return r
+}
+
+// ParsePoint implements Group: it accepts 33-byte compressed points only
+func (secp256k1Group) ParsePoint(b []byte) (Point, error) {
+	if len(b) != 33 {
+		return nil, ErrInvalidPoint
+	}
+	pub, err := btcec.ParsePubKey(b)
+	if err != nil {
+		if isZero(b) {
+			return nil, ErrIdentity
+		}
+		return nil, ErrInvalidPoint
+	}
+	r := &secp256k1Point{}
+	pub.AsJacobian(&r.p)
+	return r, nil
+}
+
+// Add implements Point
+func (a *secp256k1Point) Add(q Point) Point {
+	b := q.(*secp256k1Point)
+	r := &secp256k1Point{}
+	btcec.AddNonConst(&a.p, &b.p, &r.p)
+	r.p.ToAffine()
+	return r
+}
+
+// Neg implements Point
+func (a *secp256k1Point) Neg() Point {
+	r := &secp256k1Point{p: a.p}
+	r.p.Y.Negate(1).Normalize()
+	return r
+}
+
+// ScalarMult implements Point
+func (a *secp256k1Point) ScalarMult(k *big.Int) Point {
+	s := toModNScalar(k)
+	r := &secp256k1Point{}
+	btcec.ScalarMultNonConst(&s, &a.p, &r.p)
+	r.p.ToAffine()
+	return r
+}
+
+// Equal implements Point
+func (a *secp256k1Point) Equal(q Point) bool {
+	b := q.(*secp256k1Point)
+	if a.IsIdentity() || b.IsIdentity() {
+		return a.IsIdentity() == b.IsIdentity()
+	}
+	return a.p.X.Equals(&b.p.X) && a.p.Y.Equals(&b.p.Y)
+}
+
+// IsIdentity implements Point
+func (a *secp256k1Point) IsIdentity() bool {
+	return (a.p.X.IsZero() && a.p.Y.IsZero()) || a.p.Z.IsZero()
+}
+
+// Bytes implements Point: the compressed encoding, or 33 zero bytes for the identity
+func (a *secp256k1Point) Bytes() []byte {
+	if a.IsIdentity() {
+		return make([]byte, 33)
+	}
+	return btcec.NewPublicKey(&a.p.X, &a.p.Y).SerializeCompressed()
+}
+
+// toModNScalar reduces k mod n
+func toModNScalar(k *big.Int) btcec.ModNScalar {
+	var buf [32]byte
+	new(big.Int).Mod(k, secp256k1Order).FillBytes(buf[:])
+	var s btcec.ModNScalar
+	s.SetBytes(&buf)
+	return s
+}
+
+// isZero reports whether every byte of b is zero
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package group
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+func TestSecp256k1MatchesBtcec(t *testing.T) {
+	g := Secp256k1()
+	for _, k := range []int64{1, 2, 7, 0xdeadbeef} {
+		priv, _ := btcec.PrivKeyFromBytes(big.NewInt(k).FillBytes(make([]byte, 32)))
+		want := priv.PubKey().SerializeCompressed()
+		if got := g.ScalarBaseMult(big.NewInt(k)).Bytes(); !bytes.Equal(got, want) {
+			t.Errorf("k=%d: Expected %x, got %x", k, want, got)
+		}
+	}
+}
+
+func TestSecp256k1ParseErrors(t *testing.T) {
+	g := Secp256k1()
+	enc := g.Generator().Bytes()
+
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"uncompressed", btcec.NewPublicKey(new(btcec.FieldVal).SetInt(1), new(btcec.FieldVal).SetInt(1)).SerializeUncompressed()},
+		{"bad prefix", append([]byte{0x05}, enc[1:]...)},
+		{"x not on curve", append([]byte{0x02}, bytes.Repeat([]byte{0xff}, 32)...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := g.ParsePoint(tt.input); !errors.Is(err, ErrInvalidPoint) {
+				t.Errorf("Expected ErrInvalidPoint, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package hash
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestBlake2b(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []byte
+		blake2b256 string
+		blake2b512 string
+	}{
+		{
+			name:       "empty string",
+			input:      []byte{},
+			blake2b256: "0e5751c026e543b2e8ab2eb06099daa1d1e5df47778f7787faab45cdf12fe3a8",
+			blake2b512: "786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419" +
+				"d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce",
+		},
+		{
+			name:       "abc",
+			input:      []byte("abc"),
+			blake2b256: "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319",
+			blake2b512: "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d1" +
+				"7d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h256 := Blake2b256(tt.input)
+			if got := hex.EncodeToString(h256[:]); got != tt.blake2b256 {
+				t.Errorf("Blake2b256() = %s, expected %s", got, tt.blake2b256)
+			}
+			h512 := Blake2b512(tt.input)
+			if got := hex.EncodeToString(h512[:]); got != tt.blake2b512 {
+				t.Errorf("Blake2b512() = %s, expected %s", got, tt.blake2b512)
+			}
+		})
+	}
+}
+
+// TestBlake3 uses the official test vector inputs: byte i is i mod 251. The
+// lengths cover a single block, block and chunk boundaries, and trees whose
+// right subtree is a lone chunk or a partial chunk.
+func TestBlake3(t *testing.T) {
+	tests := []struct {
+		length   int
+		expected string
+	}{
+		{0, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+		{1, "2d3adedff11b61f14c886e35afa036736dcd87a74d27b5c1510225d0f592e213"},
+		{64, "4eed7141ea4a5cd4b788606bd23f46e212af9cacebacdc7d1f4c6dc7f2511b98"},
+		{65, "de1e5fa0be70df6d2be8fffd0e99ceaa8eb6e8c93a63f2d8d1c30ecb6b263dee"},
+		{1024, "42214739f095a406f3fc83deb889744ac00df831c10daa55189b5d121c855af7"},
+		{1025, "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444"},
+		{2049, "5f4d72f40d7a5f82b15ca2b2e44b1de3c2ef86c426c95c1af0b6879522563030"},
+		{3072, "b98cb0ff3623be03326b373de6b9095218513e64f1ee2edd2525c7ad1e5cffd2"},
+		{8193, "bab6c09cb8ce8cf459261398d2e7aef35700bf488116ceb94a36d0f5f1b7bc3b"},
+		{31744, "62b6960e1a44bcc1eb1a611a8d6235b6b4b78f32e7abc4fb4c6cdcce94895c47"},
+		{102400, "bc3e3d41a1146b069abffad3c0d44860cf664390afce4d9661f7902e7943e085"},
+	}
+
+	for _, tt := range tests {
+		input := make([]byte, tt.length)
+		for i := range input {
+			input[i] = byte(i % 251)
+		}
+		h := Blake3(input)
+		if got := hex.EncodeToString(h[:]); got != tt.expected {
+			t.Errorf("Blake3(%d bytes) = %s, expected %s", tt.length, got, tt.expected)
+		}
+	}
+}
+
+func BenchmarkHashes(b *testing.B) {
+	data := make([]byte, 16*1024)
+	b.Run("SHA256", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			SHA256(data)
+		}
+	})
+	b.Run("Blake2b256", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			Blake2b256(data)
+		}
+	})
+	b.Run("Blake3", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			Blake3(data)
+		}
+	})
+}
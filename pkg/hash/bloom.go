@@ -0,0 +1,233 @@
+package hash
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Bloom filters (BIP37)
+//
+// An SPV client sends a full node a Bloom filter (filterload) holding its
+// scripts, public keys and outpoints; the node answers with merkleblock
+// messages revealing only the transactions that match. A filter is a bit
+// array of m bits and k hash functions. Hash function i is
+//
+//	Murmur3(i·0xFBA4C795 + tweak, data) mod m
+//
+// Inserting sets the k bits, and a lookup reports a match when all k bits are
+// set. False positives are possible (that is the privacy knob), false
+// negatives are not. The flags tell the node whether to add the outpoints of
+// matched outputs to the filter so that later spends match too. Wire format:
+//
+//	compactSize(#bytes) || filter bytes || k (uint32 LE) || tweak (uint32 LE) || flags (1 byte)
+
+const (
+	// MaxBloomFilterSize is the largest filter in bytes a node accepts
+	MaxBloomFilterSize = 36000
+	// MaxBloomHashFuncs is the largest number of hash functions a node accepts
+	MaxBloomHashFuncs = 50
+)
+
+// bloomSeedMultiplier spaces out the Murmur3 seeds of the hash functions
+const bloomSeedMultiplier = 0xfba4c795
+
+// BloomUpdate selects how a node updates the filter after a match
+type BloomUpdate uint8
+
+const (
+	// BloomUpdateNone never adds outpoints to the filter
+	BloomUpdateNone BloomUpdate = 0
+	// BloomUpdateAll adds the outpoint of every output that matched
+	BloomUpdateAll BloomUpdate = 1
+	// BloomUpdateP2PubKeyOnly adds outpoints only for matched pay-to-pubkey and bare multisig outputs
+	BloomUpdateP2PubKeyOnly BloomUpdate = 2
+)
+
+// ErrInvalidBloomFilter is returned for filters that exceed the BIP37 limits or do not decode
+var ErrInvalidBloomFilter = errors.New("invalid bloom filter")
+
+// BloomFilter is a BIP37 filter as carried in a filterload message
+type BloomFilter struct {
+	Data      []byte      // Bit array, bit i is Data[i/8] >> (i%8) & 1
+	HashFuncs uint32      // Number of hash functions k
+	Tweak     uint32      // Random value mixed into every seed
+	Flags     BloomUpdate // How the node updates the filter on a match
+}
+
+// NewBloomFilter sizes a filter for elements entries at the given false positive rate
+//
+// The size and number of hash functions use Bitcoin Core's expressions and
+// are capped at MaxBloomFilterSize and MaxBloomHashFuncs, so very large
+// element counts get a higher false positive rate than requested. Like Core,
+// bits per element is an integer division, so a filter with fewer bits than
+// elements gets no hash functions and matches everything. Unlike Core, the
+// filter is at least one byte: an empty filter here matches nothing. The tweak
+// should be random so that filters of the same elements differ between
+// connections.
+//
+// Example:
+//
+//	f, err := NewBloomFilter(10, 0.0001, tweak, BloomUpdateAll)
+//	f.Insert(Hash160(pubKey))
+//	msg := f.Serialize() // filterload payload
+func NewBloomFilter(elements int, fpRate float64, tweak uint32, flags BloomUpdate) (*BloomFilter, error) {
+	// Step 1: Validate the parameters
+	if elements <= 0 {
+		return nil, fmt.Errorf("element count must be positive, got %d", elements)
+	}
+	if !(fpRate > 0 && fpRate < 1) {
+		return nil, fmt.Errorf("false positive rate must be in (0, 1), got %v", fpRate)
+	}
+
+	// Step 2: m = -n·ln(p) / ln(2)², in whole bytes
+	bitCount := -1 / (math.Ln2 * math.Ln2) * float64(elements) * math.Log(fpRate)
+	size := int(math.Min(bitCount, MaxBloomFilterSize*8) / 8)
+	size = max(size, 1)
+
+	// Step 3: k = m/n · ln(2), with m/n rounded down as in Core
+	k := uint32(math.Min(float64(size*8/elements)*math.Ln2, MaxBloomHashFuncs))
+
+	return &BloomFilter{
+		Data:      make([]byte, size),
+		HashFuncs: k,
+		Tweak:     tweak,
+		Flags:     flags,
+	}, nil
+}
+
+// Insert adds data to the filter
+func (f *BloomFilter) Insert(data []byte) {
+	if len(f.Data) == 0 {
+		return
+	}
+	for i := range f.HashFuncs {
+		bit := f.bit(i, data)
+		f.Data[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// InsertOutPoint adds an outpoint, serialized as txid (internal byte order) || vout (uint32 LE)
+func (f *BloomFilter) InsertOutPoint(txid [32]byte, vout uint32) {
+	f.Insert(binary.LittleEndian.AppendUint32(txid[:], vout))
+}
+
+// Contains reports whether data may have been inserted
+//
+// A false result is definite; a true result is wrong with roughly the false
+// positive rate the filter was sized for.
+func (f *BloomFilter) Contains(data []byte) bool {
+	if len(f.Data) == 0 {
+		return false
+	}
+	for i := range f.HashFuncs {
+		bit := f.bit(i, data)
+		if f.Data[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsOutPoint reports whether an outpoint may have been inserted
+func (f *BloomFilter) ContainsOutPoint(txid [32]byte, vout uint32) bool {
+	return f.Contains(binary.LittleEndian.AppendUint32(txid[:], vout))
+}
+
+// Serialize encodes the filter as a filterload payload
+func (f *BloomFilter) Serialize() []byte {
+	buf := appendCompactSize(nil, uint64(len(f.Data)))
+	buf = append(buf, f.Data...)
+	buf = binary.LittleEndian.AppendUint32(buf, f.HashFuncs)
+	buf = binary.LittleEndian.AppendUint32(buf, f.Tweak)
+	return append(buf, byte(f.Flags))
+}
+
+// ParseBloomFilter decodes a filterload payload, enforcing the BIP37 size limits
+func ParseBloomFilter(raw []byte) (*BloomFilter, error) {
+	// Step 1: Filter bytes
+	n, size, err := readCompactSize(raw)
+	if err != nil || n > uint64(len(raw)-size) {
+		return nil, fmt.Errorf("%w: truncated filter", ErrInvalidBloomFilter)
+	}
+	if n > MaxBloomFilterSize {
+		return nil, fmt.Errorf("%w: %d bytes exceeds %d", ErrInvalidBloomFilter, n, MaxBloomFilterSize)
+	}
+	raw = raw[size:]
+	f := &BloomFilter{Data: append([]byte(nil), raw[:n]...)}
+	raw = raw[n:]
+
+	// Step 2: Fixed-size trailer
+	if len(raw) != 9 {
+		return nil, fmt.Errorf("%w: expected 9 trailing bytes, got %d", ErrInvalidBloomFilter, len(raw))
+	}
+	f.HashFuncs = binary.LittleEndian.Uint32(raw)
+	f.Tweak = binary.LittleEndian.Uint32(raw[4:])
+	f.Flags = BloomUpdate(raw[8])
+	if f.HashFuncs > MaxBloomHashFuncs {
+		return nil, fmt.Errorf("%w: %d hash functions exceeds %d", ErrInvalidBloomFilter, f.HashFuncs, MaxBloomHashFuncs)
+	}
+	return f, nil
+}
+
+// bit returns the index of the bit set by hash function i
+func (f *BloomFilter) bit(i uint32, data []byte) uint32 {
+	return Murmur3(i*bloomSeedMultiplier+f.Tweak, data) % uint32(len(f.Data)*8)
+}
+
+// Murmur3 computes the 32-bit x86 variant of MurmurHash3, as used by BIP37
+//
+// MurmurHash3 is fast and well distributed but not cryptographic: anyone can
+// find collisions, which is acceptable for Bloom filter indexing only.
+//
+// Example:
+//
+//	h := Murmur3(0xfba4c795, []byte{0x00}) // 0xea3f0b17
+func Murmur3(seed uint32, data []byte) uint32 {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+	)
+	h := seed
+
+	// Step 1: Mix in whole 4-byte blocks
+	n := len(data) / 4 * 4
+	for i := 0; i < n; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+
+	// Step 2: Mix in the 1-3 trailing bytes
+	var k uint32
+	tail := data[n:]
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+
+	// Step 3: Finalize with the length and an avalanche
+	h ^= uint32(len(data))
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
@@ -0,0 +1,200 @@
+package hash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestMurmur3 uses the vectors from Bitcoin Core's hash_tests
+func TestMurmur3(t *testing.T) {
+	tests := []struct {
+		seed uint32
+		data string
+		want uint32
+	}{
+		{0x00000000, "", 0x00000000},
+		{0xfba4c795, "", 0x6a396f08},
+		{0xffffffff, "", 0x81f16f39},
+		{0x00000000, "00", 0x514e28b7},
+		{0xfba4c795, "00", 0xea3f0b17},
+		{0x00000000, "ff", 0xfd6cf10d},
+		{0x00000000, "0011", 0x16c6b7ab},
+		{0x00000000, "001122", 0x8eb51c3d},
+		{0x00000000, "00112233", 0xb4471bf8},
+		{0x00000000, "0011223344", 0xe2301fa8},
+		{0x00000000, "001122334455", 0xfc2e4a15},
+		{0x00000000, "00112233445566", 0xb074502c},
+		{0x00000000, "0011223344556677", 0x8034d2a0},
+		{0x00000000, "001122334455667788", 0xb4698def},
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.data)
+		if got := Murmur3(tt.seed, data); got != tt.want {
+			t.Errorf("Murmur3(%#x, %s) = %#08x, expected %#08x", tt.seed, tt.data, got, tt.want)
+		}
+	}
+}
+
+// TestBloomFilter uses the filters from Bitcoin Core's bloom_tests
+func TestBloomFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		tweak uint32
+		want  string
+	}{
+		{"no tweak", 0, "03614e9b050000000000000001"},
+		{"tweak", 2147483649, "03ce4299050000000100008001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewBloomFilter(3, 0.01, tt.tweak, BloomUpdateAll)
+			if err != nil {
+				t.Fatalf("NewBloomFilter failed: %v", err)
+			}
+
+			a, _ := hex.DecodeString("99108ad8ed9bb6274d3980bab5a85c048f0950c8")
+			f.Insert(a)
+			if !f.Contains(a) {
+				t.Error("Expected the inserted element to match")
+			}
+			// One bit flipped in the first byte
+			b, _ := hex.DecodeString("19108ad8ed9bb6274d3980bab5a85c048f0950c8")
+			if f.Contains(b) {
+				t.Error("Expected a different element not to match")
+			}
+
+			for _, s := range []string{"b5a2c786d9ef4658287ced5914b37a1b4aa32eee", "b9300670b4c5366e95b2699e8b18bc75e5f729c5"} {
+				e, _ := hex.DecodeString(s)
+				f.Insert(e)
+				if !f.Contains(e) {
+					t.Errorf("Expected %s to match", s)
+				}
+			}
+
+			raw := f.Serialize()
+			if hex.EncodeToString(raw) != tt.want {
+				t.Errorf("Serialize() = %x, expected %s", raw, tt.want)
+			}
+
+			parsed, err := ParseBloomFilter(raw)
+			if err != nil {
+				t.Fatalf("ParseBloomFilter failed: %v", err)
+			}
+			if !bytes.Equal(parsed.Serialize(), raw) || !parsed.Contains(a) {
+				t.Error("Expected the parsed filter to round trip")
+			}
+		})
+	}
+}
+
+func TestBloomFilterOutPoint(t *testing.T) {
+	f, err := NewBloomFilter(10, 0.0001, 7, BloomUpdateP2PubKeyOnly)
+	if err != nil {
+		t.Fatalf("NewBloomFilter failed: %v", err)
+	}
+	txid := SHA256D([]byte("funding"))
+	f.InsertOutPoint(txid, 1)
+
+	if !f.ContainsOutPoint(txid, 1) {
+		t.Error("Expected the inserted outpoint to match")
+	}
+	if f.ContainsOutPoint(txid, 0) {
+		t.Error("Expected another output of the same transaction not to match")
+	}
+	if !f.Contains(append(txid[:], 1, 0, 0, 0)) {
+		t.Error("Expected outpoints to be txid || vout LE")
+	}
+}
+
+func TestBloomFilterFalsePositiveRate(t *testing.T) {
+	const n = 500
+	f, err := NewBloomFilter(n, 0.01, 42, BloomUpdateNone)
+	if err != nil {
+		t.Fatalf("NewBloomFilter failed: %v", err)
+	}
+	for i := range n {
+		f.Insert(fmt.Appendf(nil, "in %d", i))
+	}
+
+	// No false negatives
+	for i := range n {
+		if !f.Contains(fmt.Appendf(nil, "in %d", i)) {
+			t.Fatalf("Expected element %d to match", i)
+		}
+	}
+
+	// False positives stay near the requested 1%
+	falsePositives := 0
+	for i := range 10000 {
+		if f.Contains(fmt.Appendf(nil, "out %d", i)) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 300 {
+		t.Errorf("Expected about 100 false positives in 10000, got %d", falsePositives)
+	}
+}
+
+func TestBloomFilterLimits(t *testing.T) {
+	f, err := NewBloomFilter(1_000_000, 0.0001, 0, BloomUpdateNone)
+	if err != nil {
+		t.Fatalf("NewBloomFilter failed: %v", err)
+	}
+	if len(f.Data) != MaxBloomFilterSize {
+		t.Errorf("Expected the filter to be capped at %d bytes, got %d", MaxBloomFilterSize, len(f.Data))
+	}
+	// Core rounds bits per element down: 288000 bits for 1000000 elements gives no hash functions
+	if f.HashFuncs != 0 || !f.Contains([]byte("x")) {
+		t.Errorf("Expected no hash functions and a filter that matches everything, got %d", f.HashFuncs)
+	}
+	f, _ = NewBloomFilter(5, 0.05, 0, BloomUpdateNone)
+	if len(f.Data) != 3 || f.HashFuncs != 2 {
+		t.Errorf("Expected 3 bytes and 2 hash functions (24/5 = 4 bits per element), got %d and %d", len(f.Data), f.HashFuncs)
+	}
+	f, _ = NewBloomFilter(1, 1e-30, 0, BloomUpdateNone)
+	if f.HashFuncs != MaxBloomHashFuncs {
+		t.Errorf("Expected %d hash functions, got %d", MaxBloomHashFuncs, f.HashFuncs)
+	}
+
+	for _, bad := range []struct {
+		elements int
+		fpRate   float64
+	}{{0, 0.01}, {10, 0}, {10, 1}} {
+		if _, err := NewBloomFilter(bad.elements, bad.fpRate, 0, BloomUpdateNone); err == nil {
+			t.Errorf("Expected an error for %d elements at rate %v", bad.elements, bad.fpRate)
+		}
+	}
+
+	// The empty filter matches nothing
+	if (&BloomFilter{HashFuncs: 3}).Contains([]byte("x")) {
+		t.Error("Expected an empty filter not to match")
+	}
+}
+
+func TestParseBloomFilterErrors(t *testing.T) {
+	oversized := appendCompactSize(nil, MaxBloomFilterSize+1)
+	oversized = append(oversized, make([]byte, MaxBloomFilterSize+1+9)...)
+
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"empty", ""},
+		{"truncated filter", "05aabb"},
+		{"missing trailer", "01ff0300000000000000"},
+		{"trailing data", "01ff03000000000000000100"},
+		{"too many hash functions", "01ff330000000000000000"},
+		{"oversized", hex.EncodeToString(oversized)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, _ := hex.DecodeString(tt.raw)
+			if _, err := ParseBloomFilter(raw); !errors.Is(err, ErrInvalidBloomFilter) {
+				t.Errorf("Expected ErrInvalidBloomFilter, got %v", err)
+			}
+		})
+	}
+}
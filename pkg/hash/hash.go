@@ -2,8 +2,9 @@ package hash
 
 import (
 	"crypto/sha256"
+	"crypto/sha512"
 
-	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/crypto/sha3"
 )
 
 // SHA256 calculates the SHA256 hash of the input data.
@@ -34,6 +35,64 @@ func SHA256D(data []byte) [32]byte {
 	return sha256.Sum256(sha256Hash[:])
 }
 
+// Checksum4 returns the first 4 bytes of SHA256D(data).
+// This is the checksum of Base58Check (addresses, WIF keys, extended keys) and of
+// Bitcoin's P2P message headers. It catches typos and corruption, not tampering:
+// anyone can recompute it.
+//
+// Example:
+//
+//	data := []byte{0x00, 0x1A, 0x2B} // version || payload
+//	checksum := Checksum4(data)
+//	encoded := append(data, checksum[:]...)
+func Checksum4(data []byte) [4]byte {
+	sum := SHA256D(data)
+	return [4]byte(sum[:4])
+}
+
+// SHA512 calculates the SHA512 hash of the input data.
+// SHA512 is the 512-bit (64-byte) member of the SHA-2 family. It runs faster than
+// SHA256 on 64-bit CPUs and is used by Ed25519, BIP32 (through HMAC) and BIP39.
+//
+// Example:
+//
+//	digest := SHA512([]byte("Hello, World!"))
+//	fmt.Printf("SHA512: %x\n", digest)
+func SHA512(data []byte) [64]byte {
+	return sha512.Sum512(data)
+}
+
+// SHA3_256 calculates the SHA3-256 hash of the input data (FIPS 202).
+// SHA3 is built on the Keccak sponge rather than the Merkle–Damgård construction
+// of SHA-2, so it is not vulnerable to length extension.
+//
+// Example:
+//
+//	digest := SHA3_256([]byte("Hello, World!"))
+//	fmt.Printf("SHA3-256: %x\n", digest)
+func SHA3_256(data []byte) [32]byte {
+	return sha3.Sum256(data)
+}
+
+// Keccak256 calculates the legacy Keccak-256 hash used by Ethereum.
+// Ethereum adopted Keccak before NIST standardized it as SHA3; the final standard
+// changed the padding byte (0x01 → 0x06), so Keccak256 and SHA3_256 give different
+// results for the same input. Ethereum addresses are the last 20 bytes of the
+// Keccak256 of the 64-byte uncompressed public key (without the 0x04 prefix).
+//
+// Example:
+//
+//	pub := priv.PubKey().SerializeUncompressed()
+//	digest := Keccak256(pub[1:])
+//	address := digest[12:] // 20-byte Ethereum address
+func Keccak256(data []byte) [32]byte {
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(data)
+	var out [32]byte
+	h.Sum(out[:0])
+	return out
+}
+
 // RIPEMD160 calculates the RIPEMD160 hash of the input data.
 // RIPEMD160 is a 160-bit (20-byte) cryptographic hash function.
 // It's used in Bitcoin addresses (P2PKH) to create shorter, more manageable addresses.
@@ -45,10 +104,10 @@ func SHA256D(data []byte) [32]byte {
 //	hash := RIPEMD160(data)
 //	fmt.Printf("RIPEMD160: %x\n", hash)
 func RIPEMD160(data []byte) [20]byte {
-	h := ripemd160.New()
+	h := NewRIPEMD160()
 	_, _ = h.Write(data)
 	var out [20]byte
-	copy(out[:], h.Sum(nil))
+	h.Sum(out[:0])
 	return out
 }
 
@@ -3,6 +3,8 @@ package hash
 import (
 	"encoding/hex"
 	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
 )
 
 func TestSHA256(t *testing.T) {
@@ -70,6 +72,32 @@ func TestSHA256D(t *testing.T) {
 	}
 }
 
+func TestChecksum4(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty string", "", "5df6e0e2"},
+		{"version only", "00", "1406e058"},
+		{"version and payload", "001a2b", "958bf005"},
+		// Mainnet P2PKH address 19mLgd5RjgEcyGfnj5ra4gW8FjtvLc2Adr
+		{"address", "006023bd3f2b3be13c4f5a49fd7e0810a8e43d8126", "4ee72995"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, _ := hex.DecodeString(tt.input)
+			result := Checksum4(input)
+			resultHex := hex.EncodeToString(result[:])
+
+			if resultHex != tt.expected {
+				t.Errorf("Checksum4() = %s, expected %s", resultHex, tt.expected)
+			}
+		})
+	}
+}
+
 func TestRIPEMD160(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -225,3 +253,59 @@ func TestHash160BitcoinExample(t *testing.T) {
 	t.Logf("Public key hash: %x", pubKeyHash)
 	t.Logf("This 20-byte hash would be Base58Check encoded to create a Bitcoin address")
 }
+
+func TestSHA512AndSHA3(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []byte
+		sha512    string
+		sha3_256  string
+		keccak256 string
+	}{
+		{
+			name:  "empty string",
+			input: []byte{},
+			sha512: "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce" +
+				"47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e",
+			sha3_256:  "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a",
+			keccak256: "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+		},
+		{
+			name:  "abc",
+			input: []byte("abc"),
+			sha512: "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a" +
+				"2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+			sha3_256:  "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532",
+			keccak256: "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sha512 := SHA512(tt.input)
+			if got := hex.EncodeToString(sha512[:]); got != tt.sha512 {
+				t.Errorf("SHA512() = %s, expected %s", got, tt.sha512)
+			}
+			sha3 := SHA3_256(tt.input)
+			if got := hex.EncodeToString(sha3[:]); got != tt.sha3_256 {
+				t.Errorf("SHA3_256() = %s, expected %s", got, tt.sha3_256)
+			}
+			keccak := Keccak256(tt.input)
+			if got := hex.EncodeToString(keccak[:]); got != tt.keccak256 {
+				t.Errorf("Keccak256() = %s, expected %s", got, tt.keccak256)
+			}
+		})
+	}
+}
+
+func TestKeccak256EthereumAddress(t *testing.T) {
+	// The Ethereum address of private key 1 is 0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf
+	priv, _ := btcec.PrivKeyFromBytes([]byte{31: 0x01})
+	pub := priv.PubKey().SerializeUncompressed()
+	digest := Keccak256(pub[1:])
+
+	expected := "7e5f4552091a69125d5dfcb7b8c2659029395bdf"
+	if got := hex.EncodeToString(digest[12:]); got != expected {
+		t.Errorf("address = %s, expected %s", got, expected)
+	}
+}
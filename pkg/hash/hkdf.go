@@ -0,0 +1,68 @@
+package hash
+
+import (
+	"crypto/hmac"
+	"fmt"
+	stdhash "hash"
+)
+
+// HKDF derives length bytes of key material from a secret (RFC 5869).
+// Raw secrets such as ECDH shared points are not uniformly random and must not
+// be used directly as keys. HKDF first extracts a pseudorandom key from the
+// secret and an optional salt, then expands it into as many bytes as needed,
+// bound to a context string (info). Different info values give independent keys.
+// newHash selects the hash function, e.g. sha256.New or sha512.New.
+//
+// Example:
+//
+//	shared := ecdhSharedSecret(priv, peerPub)
+//	keys, err := HKDF(sha256.New, shared, salt, []byte("playground/v1 enc+mac"), 64)
+//	encKey, macKey := keys[:32], keys[32:]
+func HKDF(newHash func() stdhash.Hash, secret, salt, info []byte, length int) ([]byte, error) {
+	return HKDFExpand(newHash, HKDFExtract(newHash, salt, secret), info, length)
+}
+
+// HKDFExtract computes the pseudorandom key PRK = HMAC(salt, secret).
+// An empty salt is replaced with HashLen zero bytes, as the RFC specifies.
+//
+// Example:
+//
+//	prk := HKDFExtract(sha256.New, salt, sharedSecret)
+func HKDFExtract(newHash func() stdhash.Hash, salt, secret []byte) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, newHash().Size())
+	}
+	h := hmac.New(newHash, salt)
+	_, _ = h.Write(secret)
+	return h.Sum(nil)
+}
+
+// HKDFExpand expands a pseudorandom key into length bytes bound to info.
+// The output is T(1) || T(2) || ... truncated to length, where
+// T(i) = HMAC(prk, T(i-1) || info || i). At most 255 blocks can be produced,
+// so length is limited to 255·HashLen (8160 bytes for SHA256).
+//
+// Example:
+//
+//	encKey, err := HKDFExpand(sha256.New, prk, []byte("encryption"), 32)
+//	macKey, err := HKDFExpand(sha256.New, prk, []byte("authentication"), 32)
+func HKDFExpand(newHash func() stdhash.Hash, prk, info []byte, length int) ([]byte, error) {
+	// Step 1: Validate the requested length
+	h := hmac.New(newHash, prk)
+	if length < 0 || length > 255*h.Size() {
+		return nil, fmt.Errorf("hkdf length must be between 0 and %d bytes, got %d", 255*h.Size(), length)
+	}
+
+	// Step 2: Chain T(i) = HMAC(prk, T(i-1) || info || i) until enough bytes exist
+	out := make([]byte, 0, length+h.Size())
+	var prev []byte
+	for counter := byte(1); len(out) < length; counter++ {
+		h.Reset()
+		_, _ = h.Write(prev)
+		_, _ = h.Write(info)
+		_, _ = h.Write([]byte{counter})
+		prev = h.Sum(nil)
+		out = append(out, prev...)
+	}
+	return out[:length], nil
+}
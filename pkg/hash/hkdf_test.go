@@ -0,0 +1,113 @@
+package hash
+
+import (
+	"bytes"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"testing"
+)
+
+// mustHex decodes a hex test constant
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("bad hex %q: %v", s, err)
+	}
+	return b
+}
+
+// TestHKDF checks the RFC 5869 SHA-256 test vectors
+func TestHKDF(t *testing.T) {
+	tests := []struct {
+		name   string
+		ikm    string
+		salt   string
+		info   string
+		length int
+		prk    string
+		okm    string
+	}{
+		{
+			"case 1",
+			"0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+			"000102030405060708090a0b0c",
+			"f0f1f2f3f4f5f6f7f8f9",
+			42,
+			"077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5",
+			"3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865",
+		},
+		{
+			"case 3 empty salt and info",
+			"0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+			"",
+			"",
+			42,
+			"19ef24a32c717b167f33a91d6f648bdf96596776afdb6377ac434c1c293ccb04",
+			"8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ikm, salt, info := mustHex(t, tt.ikm), mustHex(t, tt.salt), mustHex(t, tt.info)
+			if prk := HKDFExtract(sha256.New, salt, ikm); hex.EncodeToString(prk) != tt.prk {
+				t.Errorf("Expected PRK %s, got %x", tt.prk, prk)
+			}
+			okm, err := HKDF(sha256.New, ikm, salt, info, tt.length)
+			if err != nil {
+				t.Fatalf("HKDF failed: %v", err)
+			}
+			if hex.EncodeToString(okm) != tt.okm {
+				t.Errorf("Expected OKM %s, got %x", tt.okm, okm)
+			}
+		})
+	}
+}
+
+func TestHKDFSHA512(t *testing.T) {
+	secret, salt, info := []byte("shared secret"), []byte("salt"), "context"
+	got, err := HKDF(sha512.New, secret, salt, []byte(info), 100)
+	if err != nil {
+		t.Fatalf("HKDF failed: %v", err)
+	}
+	want, err := hkdf.Key(sha512.New, secret, salt, info, 100)
+	if err != nil {
+		t.Fatalf("hkdf.Key failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Expected %x, got %x", want, got)
+	}
+
+	// Shorter outputs are prefixes of longer ones for the same info
+	short, _ := HKDF(sha512.New, secret, salt, []byte(info), 10)
+	if !bytes.Equal(short, got[:10]) {
+		t.Error("Expected shorter output to be a prefix")
+	}
+}
+
+func TestHKDFLength(t *testing.T) {
+	prk := HKDFExtract(sha256.New, nil, []byte("secret"))
+	tests := []struct {
+		name    string
+		length  int
+		wantErr bool
+	}{
+		{"zero", 0, false},
+		{"max", 255 * 32, false},
+		{"too long", 255*32 + 1, true},
+		{"negative", -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := HKDFExpand(sha256.New, prk, nil, tt.length)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && len(out) != tt.length {
+				t.Errorf("Expected %d bytes, got %d", tt.length, len(out))
+			}
+		})
+	}
+}
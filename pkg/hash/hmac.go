@@ -0,0 +1,41 @@
+package hash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+)
+
+// HMACSHA256 calculates HMAC-SHA256 of data under key (RFC 2104).
+// HMAC turns a hash function into a keyed MAC: H((K ^ opad) || H((K ^ ipad) || data)).
+// Unlike SHA256(key || data), it is not vulnerable to length extension.
+// It is the building block of HKDF, PBKDF2 and RFC 6979 deterministic nonces.
+//
+// Example:
+//
+//	mac := HMACSHA256(sharedKey, message)
+//	ok := hmac.Equal(mac[:], receivedMAC)
+func HMACSHA256(key, data []byte) [32]byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write(data)
+	var out [32]byte
+	h.Sum(out[:0])
+	return out
+}
+
+// HMACSHA512 calculates HMAC-SHA512 of data under key (RFC 2104).
+// BIP32 uses it to derive keys: the master key is HMACSHA512("Bitcoin seed", seed),
+// where the left 32 bytes are the private key and the right 32 bytes the chain code.
+// Child keys are derived the same way, with the parent chain code as the key.
+//
+// Example:
+//
+//	I := HMACSHA512([]byte("Bitcoin seed"), seed)
+//	masterKey, chainCode := I[:32], I[32:]
+func HMACSHA512(key, data []byte) [64]byte {
+	h := hmac.New(sha512.New, key)
+	_, _ = h.Write(data)
+	var out [64]byte
+	h.Sum(out[:0])
+	return out
+}
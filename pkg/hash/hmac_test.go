@@ -0,0 +1,55 @@
+package hash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// TestHMAC checks the RFC 4231 test vectors
+func TestHMAC(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    []byte
+		data   []byte
+		sha256 string
+		sha512 string
+	}{
+		{
+			"case 1",
+			bytes.Repeat([]byte{0x0b}, 20),
+			[]byte("Hi There"),
+			"b0344c61d8db38535ca8afceaf0bf12b881dc200c9833da726e9376c2e32cff7",
+			"87aa7cdea5ef619d4ff0b4241a1d6cb02379f4e2ce4ec2787ad0b30545e17cde" +
+				"daa833b7d6b8a702038b274eaea3f4e4be9d914eeb61f1702e696c203a126854",
+		},
+		{
+			"case 2 short key",
+			[]byte("Jefe"),
+			[]byte("what do ya want for nothing?"),
+			"5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+			"164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea250554" +
+				"9758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737",
+		},
+		{
+			"case 6 key longer than block",
+			bytes.Repeat([]byte{0xaa}, 131),
+			[]byte("Test Using Larger Than Block-Size Key - Hash Key First"),
+			"60e431591ee0b67f0d8a26aacbf5b77f8e0bc6213728c5140546040f0ee37f54",
+			"80b24263c7c1a3ebb71493c1dd7be8b49b46d1f41b4aeec1121b013783f8f352" +
+				"6b56d037e05f2598bd0fd2215d6a1e5295e64f73f63f0aec8b915a985d786598",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got256 := HMACSHA256(tt.key, tt.data)
+			if hex.EncodeToString(got256[:]) != tt.sha256 {
+				t.Errorf("HMACSHA256: Expected %s, got %x", tt.sha256, got256)
+			}
+			got512 := HMACSHA512(tt.key, tt.data)
+			if hex.EncodeToString(got512[:]) != tt.sha512 {
+				t.Errorf("HMACSHA512: Expected %s, got %x", tt.sha512, got512)
+			}
+		})
+	}
+}
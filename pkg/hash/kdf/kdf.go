@@ -0,0 +1,180 @@
+package kdf
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Password-based key derivation
+//
+// Passwords have little entropy, so keys derived from them must be expensive
+// to compute: an attacker who steals a keystore has to pay the same cost for
+// every guess. The three functions here trade that cost differently:
+//
+//	PBKDF2-HMAC-SHA512  iterated HMAC; cheap on GPUs, but required by BIP39
+//	scrypt              memory-hard, N·r·128 bytes per derivation
+//	Argon2id            memory-hard and the RFC 9106 recommendation for new designs
+//
+// Params names the algorithm and its cost and is meant to be stored next to
+// the salt and the ciphertext (it has JSON tags), so that a file written with
+// today's defaults can still be opened after the defaults are raised.
+
+// SaltSize is the size of the salts returned by NewSalt
+const SaltSize = 16
+
+// maxMemory bounds the memory of a single derivation (1 GiB) so that a
+// keystore from an untrusted source cannot exhaust the machine
+const maxMemory = 1 << 30
+
+// maxWork bounds the memory a derivation fills in total, memory times passes
+// (16 GiB), and maxPBKDF2Iterations bounds PBKDF2 at about fifty times the
+// default, so that an untrusted keystore cannot make the derivation run for hours
+const (
+	maxWork             = 16 * maxMemory
+	maxPBKDF2Iterations = 10_000_000
+)
+
+// ErrInvalidParams is returned for unknown algorithms and out-of-range costs
+var ErrInvalidParams = errors.New("invalid kdf parameters")
+
+// Algorithm identifies a key derivation function
+type Algorithm string
+
+const (
+	// PBKDF2SHA512 is PBKDF2 with HMAC-SHA512 (RFC 8018)
+	PBKDF2SHA512 Algorithm = "pbkdf2-sha512"
+	// Scrypt is scrypt (RFC 7914)
+	Scrypt Algorithm = "scrypt"
+	// Argon2id is the hybrid Argon2 variant (RFC 9106)
+	Argon2id Algorithm = "argon2id"
+)
+
+// Params selects an algorithm and its cost; fields unused by the algorithm stay zero
+type Params struct {
+	Algorithm   Algorithm `json:"algorithm"`
+	Iterations  uint32    `json:"iterations,omitempty"`  // PBKDF2 rounds or Argon2id passes
+	Memory      uint32    `json:"memory,omitempty"`      // Argon2id memory in KiB
+	N           uint32    `json:"n,omitempty"`           // scrypt cost, a power of two
+	R           uint32    `json:"r,omitempty"`           // scrypt block size
+	Parallelism uint8     `json:"parallelism,omitempty"` // scrypt p or Argon2id lanes
+	KeyLen      uint32    `json:"keyLen"`                // Output size in bytes
+}
+
+// DefaultParams returns interactive-use defaults deriving a 32-byte key
+//
+// PBKDF2 uses 210,000 iterations and scrypt N = 2^17, r = 8, p = 1, as
+// recommended by OWASP; Argon2id uses the second RFC 9106 recommendation of
+// 3 passes over 64 MiB with 4 lanes. Each takes a fraction of a second.
+//
+// Example:
+//
+//	params, _ := DefaultParams(Argon2id)
+//	salt, _ := NewSalt()
+//	key, err := Derive([]byte(password), salt, params)
+func DefaultParams(alg Algorithm) (Params, error) {
+	switch alg {
+	case PBKDF2SHA512:
+		return Params{Algorithm: alg, Iterations: 210_000, KeyLen: 32}, nil
+	case Scrypt:
+		return Params{Algorithm: alg, N: 1 << 17, R: 8, Parallelism: 1, KeyLen: 32}, nil
+	case Argon2id:
+		return Params{Algorithm: alg, Iterations: 3, Memory: 64 * 1024, Parallelism: 4, KeyLen: 32}, nil
+	}
+	return Params{}, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidParams, alg)
+}
+
+// Validate checks that the parameters are usable and within resource limits
+func (p Params) Validate() error {
+	if p.KeyLen == 0 || p.KeyLen > 1024 {
+		return fmt.Errorf("%w: key length %d", ErrInvalidParams, p.KeyLen)
+	}
+	switch p.Algorithm {
+	case PBKDF2SHA512:
+		if p.Iterations == 0 {
+			return fmt.Errorf("%w: pbkdf2 needs at least one iteration", ErrInvalidParams)
+		}
+		if p.Iterations > maxPBKDF2Iterations {
+			return fmt.Errorf("%w: pbkdf2 %d iterations, at most %d", ErrInvalidParams, p.Iterations, maxPBKDF2Iterations)
+		}
+	case Scrypt:
+		if p.N < 2 || p.N&(p.N-1) != 0 {
+			return fmt.Errorf("%w: scrypt N = %d is not a power of two above 1", ErrInvalidParams, p.N)
+		}
+		if p.R == 0 || p.Parallelism == 0 {
+			return fmt.Errorf("%w: scrypt r and p must be positive", ErrInvalidParams)
+		}
+		if mem := 128 * uint64(p.N) * uint64(p.R); mem > maxMemory {
+			return fmt.Errorf("%w: scrypt needs %d bytes of memory", ErrInvalidParams, mem)
+		} else if mem*uint64(p.Parallelism) > maxWork {
+			return fmt.Errorf("%w: scrypt p = %d is too much work", ErrInvalidParams, p.Parallelism)
+		}
+	case Argon2id:
+		if p.Iterations == 0 || p.Parallelism == 0 {
+			return fmt.Errorf("%w: argon2id passes and lanes must be positive", ErrInvalidParams)
+		}
+		if p.Memory < 8*uint32(p.Parallelism) || uint64(p.Memory)*1024 > maxMemory {
+			return fmt.Errorf("%w: argon2id memory %d KiB", ErrInvalidParams, p.Memory)
+		}
+		if uint64(p.Memory)*1024*uint64(p.Iterations) > maxWork {
+			return fmt.Errorf("%w: argon2id %d passes is too much work", ErrInvalidParams, p.Iterations)
+		}
+	default:
+		return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidParams, p.Algorithm)
+	}
+	return nil
+}
+
+// Derive stretches password into a key of p.KeyLen bytes
+//
+// The salt should come from NewSalt and be stored with the parameters; Derive
+// accepts any salt, including empty ones, so that published test vectors and
+// BIP39 can be reproduced.
+func Derive(password, salt []byte, p Params) ([]byte, error) {
+	// Step 1: Reject parameters that are unusable or too expensive
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Step 2: Run the selected function
+	switch p.Algorithm {
+	case PBKDF2SHA512:
+		return pbkdf2.Key(sha512.New, string(password), salt, int(p.Iterations), int(p.KeyLen))
+	case Scrypt:
+		return scrypt.Key(password, salt, int(p.N), int(p.R), int(p.Parallelism), int(p.KeyLen))
+	default:
+		return argon2.IDKey(password, salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLen), nil
+	}
+}
+
+// BIP39Seed derives the 64-byte wallet seed from a mnemonic and an optional passphrase
+//
+// BIP39 runs PBKDF2-HMAC-SHA512 with 2048 iterations and the salt "mnemonic"
+// followed by the passphrase. Both strings must already be in Unicode NFKD
+// form; plain ASCII, which covers the English word list, always is.
+//
+// Example:
+//
+//	seed := BIP39Seed("abandon abandon ... about", "TREZOR")
+func BIP39Seed(mnemonic, passphrase string) [64]byte {
+	key, err := pbkdf2.Key(sha512.New, mnemonic, []byte("mnemonic"+passphrase), 2048, 64)
+	if err != nil {
+		// Only possible for out-of-range lengths, and 64 bytes is in range
+		panic(err)
+	}
+	return [64]byte(key)
+}
+
+// NewSalt returns SaltSize random bytes
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return salt, nil
+}
@@ -0,0 +1,141 @@
+package kdf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestDerive checks each algorithm against published vectors
+func TestDerive(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		salt     string
+		params   Params
+		expected string
+	}{
+		{
+			"pbkdf2-sha512 one iteration", "password", "salt",
+			Params{Algorithm: PBKDF2SHA512, Iterations: 1, KeyLen: 64},
+			"867f70cf1ade02cff3752599a3a53dc4af34c7a669815ae5d513554e1c8cf252c02d470a285a0501bad999bfe943c08f050235d7d68b1da55e63f73b60a57fce",
+		},
+		{
+			"scrypt RFC 7914 empty", "", "",
+			Params{Algorithm: Scrypt, N: 16, R: 1, Parallelism: 1, KeyLen: 64},
+			"77d6576238657b203b19ca42c18a0497f16b4844e3074ae8dfdffa3fede21442fcd0069ded0948f8326a753a0fc81f17e8d3e0fb2e0d3628cf35e20c38d18906",
+		},
+		{
+			"scrypt RFC 7914 NaCl", "password", "NaCl",
+			Params{Algorithm: Scrypt, N: 1024, R: 8, Parallelism: 16, KeyLen: 64},
+			"fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640",
+		},
+		{
+			"argon2id reference implementation", "password", "somesalt",
+			Params{Algorithm: Argon2id, Iterations: 2, Memory: 64 * 1024, Parallelism: 1, KeyLen: 32},
+			"09316115d5cf24ed5a15a31a3ba326e5cf32edc24702987c02b6566f61913cf7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := Derive([]byte(tt.password), []byte(tt.salt), tt.params)
+			if err != nil {
+				t.Fatalf("Derive failed: %v", err)
+			}
+			if hex.EncodeToString(key) != tt.expected {
+				t.Errorf("Expected %s, got %x", tt.expected, key)
+			}
+		})
+	}
+}
+
+// TestBIP39Seed uses the first Trezor vector
+func TestBIP39Seed(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	expected := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+
+	seed := BIP39Seed(mnemonic, "TREZOR")
+	if hex.EncodeToString(seed[:]) != expected {
+		t.Errorf("Expected %s, got %x", expected, seed)
+	}
+	if BIP39Seed(mnemonic, "") == seed {
+		t.Error("Expected the passphrase to change the seed")
+	}
+}
+
+func TestDefaultParams(t *testing.T) {
+	salt, err := NewSalt()
+	if err != nil {
+		t.Fatalf("NewSalt failed: %v", err)
+	}
+	if len(salt) != SaltSize {
+		t.Errorf("Expected a %d-byte salt, got %d", SaltSize, len(salt))
+	}
+
+	for _, alg := range []Algorithm{PBKDF2SHA512, Scrypt, Argon2id} {
+		t.Run(string(alg), func(t *testing.T) {
+			params, err := DefaultParams(alg)
+			if err != nil {
+				t.Fatalf("DefaultParams failed: %v", err)
+			}
+
+			// Parameters survive the JSON a keystore would store them in
+			raw, err := json.Marshal(params)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			var decoded Params
+			if err := json.Unmarshal(raw, &decoded); err != nil || decoded != params {
+				t.Fatalf("Expected %+v to round trip through %s", params, raw)
+			}
+
+			key, err := Derive([]byte("correct horse"), salt, decoded)
+			if err != nil {
+				t.Fatalf("Derive failed: %v", err)
+			}
+			if len(key) != 32 {
+				t.Errorf("Expected a 32-byte key, got %d", len(key))
+			}
+			other, _ := Derive([]byte("correct horse"), append([]byte{1}, salt[1:]...), decoded)
+			if bytes.Equal(key, other) {
+				t.Error("Expected the salt to change the key")
+			}
+		})
+	}
+
+	if _, err := DefaultParams("bcrypt"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams for an unknown algorithm, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+	}{
+		{"unknown algorithm", Params{Algorithm: "md5", KeyLen: 32}},
+		{"no key length", Params{Algorithm: PBKDF2SHA512, Iterations: 1}},
+		{"huge key length", Params{Algorithm: PBKDF2SHA512, Iterations: 1, KeyLen: 4096}},
+		{"pbkdf2 no iterations", Params{Algorithm: PBKDF2SHA512, KeyLen: 32}},
+		{"pbkdf2 too many iterations", Params{Algorithm: PBKDF2SHA512, Iterations: 4_000_000_000, KeyLen: 32}},
+		{"scrypt N not a power of two", Params{Algorithm: Scrypt, N: 1000, R: 8, Parallelism: 1, KeyLen: 32}},
+		{"scrypt N of one", Params{Algorithm: Scrypt, N: 1, R: 8, Parallelism: 1, KeyLen: 32}},
+		{"scrypt no r", Params{Algorithm: Scrypt, N: 1024, Parallelism: 1, KeyLen: 32}},
+		{"scrypt too much memory", Params{Algorithm: Scrypt, N: 1 << 22, R: 8, Parallelism: 1, KeyLen: 32}},
+		{"scrypt too much work", Params{Algorithm: Scrypt, N: 1 << 20, R: 8, Parallelism: 255, KeyLen: 32}},
+		{"argon2id no passes", Params{Algorithm: Argon2id, Memory: 1024, Parallelism: 1, KeyLen: 32}},
+		{"argon2id too little memory", Params{Algorithm: Argon2id, Iterations: 1, Memory: 16, Parallelism: 4, KeyLen: 32}},
+		{"argon2id too much memory", Params{Algorithm: Argon2id, Iterations: 1, Memory: 2 << 20, Parallelism: 1, KeyLen: 32}},
+		{"argon2id too many passes", Params{Algorithm: Argon2id, Iterations: 4_000_000_000, Memory: 64 * 1024, Parallelism: 4, KeyLen: 32}},
+		{"argon2id too much work", Params{Algorithm: Argon2id, Iterations: 17, Memory: 1 << 20, Parallelism: 4, KeyLen: 32}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Derive([]byte("pw"), []byte("salt"), tt.params); !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("Expected ErrInvalidParams, got %v", err)
+			}
+		})
+	}
+}
@@ -1,7 +1,19 @@
 package hash
 
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
 // Bitcoin Merkle trees: hash pairs of leaves together until you get one root hash
 
+// ErrMerkleMutation is returned when a tree contains an identical pair of nodes.
+// Because an odd node is paired with itself, the leaves [a, b, c] and [a, b, c, c]
+// have the same root (CVE-2012-2459). A block whose transactions hash to such a
+// pair could be the mutated copy of a valid block, so Bitcoin Core rejects it.
+var ErrMerkleMutation = errors.New("merkle tree has a duplicated pair of nodes (CVE-2012-2459)")
+
 // MerkleRoot creates a single root hash from a list of transaction IDs
 // Each transaction ID must be exactly 32 bytes
 //
@@ -57,6 +69,68 @@ func MerkleRoot(leaves [][32]byte) [32]byte {
 	return current[0]
 }
 
+// MerkleRootMutated computes the root like MerkleRoot and also reports whether the
+// tree is mutated: whether any level contains two identical hashes side by side
+// as a pair. This mirrors the mutation check of Bitcoin Core's ComputeMerkleRoot.
+//
+// Example:
+//
+//	root, mutated := MerkleRootMutated([][32]byte{a, b, c, c})
+//	// mutated == true: the same root as [a, b, c], so the leaf list is ambiguous
+func MerkleRootMutated(leaves [][32]byte) (root [32]byte, mutated bool) {
+	// Step 1: Scan each level for identical pairs before hashing it
+	current := leaves
+	for len(current) > 1 {
+		next := make([][32]byte, 0, (len(current)+1)/2)
+		for i := 0; i < len(current); i += 2 {
+			if i+1 < len(current) && current[i] == current[i+1] {
+				mutated = true
+			}
+			next = append(next, merkleParent(bitcoinNode, current, i))
+		}
+		current = next
+	}
+
+	// Step 2: The root is the last node, or the zero hash for no leaves
+	if len(current) == 1 {
+		root = current[0]
+	}
+	return root, mutated
+}
+
+// MerkleRootFromHex computes a merkle root from txids as shown by block explorers and bitcoind
+// Displayed txids and block hashes are the internal bytes reversed. The txids are
+// reversed into internal order before hashing, and the root is returned both in
+// internal order (as stored in the block header) and as display-order hex (as
+// shown by getblock's "merkleroot"), so neither side needs a manual Reverse32.
+//
+// Example:
+//
+//	root, display, err := MerkleRootFromHex([]string{
+//		"8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87",
+//		"fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4",
+//	})
+//	// display can be compared with the explorer, root with header.MerkleRoot
+func MerkleRootFromHex(txids []string) (root [32]byte, display string, err error) {
+	// Step 1: Parse each txid and reverse it into internal byte order
+	if len(txids) == 0 {
+		return root, "", errors.New("need at least one txid")
+	}
+	leaves := make([][32]byte, len(txids))
+	for i, s := range txids {
+		b, err := hex.DecodeString(s)
+		if err != nil || len(b) != 32 {
+			return root, "", fmt.Errorf("txid %d is not 64 hex characters: %q", i, s)
+		}
+		leaves[i] = Reverse32([32]byte(b))
+	}
+
+	// Step 2: Hash in internal order, then reverse the root back for display
+	root = MerkleRoot(leaves)
+	shown := Reverse32(root)
+	return root, hex.EncodeToString(shown[:]), nil
+}
+
 // MerkleProofStep is one piece of a proof path
 // Example:
 //
@@ -106,6 +180,60 @@ func VerifyMerkleProof(leaf [32]byte, steps []MerkleProofStep, wantRoot [32]byte
 	return current == wantRoot, nil
 }
 
+// VerifyMerkleProofAt checks a proof for the leaf at index in a tree of total leaves.
+// Unlike VerifyMerkleProof, it knows the shape of the tree, so it can tell a
+// legitimate self-pairing of the last odd node from a duplicated pair. It returns
+// ErrMerkleMutation when a step pairs a node with an identical sibling anywhere
+// else, and an error when the proof does not match index or total.
+//
+// Example:
+//
+//	proof, _ := tree.ProofAt(5)
+//	ok, err := VerifyMerkleProofAt(txids[5], 5, len(txids), proof, root)
+//	if errors.Is(err, ErrMerkleMutation) { /* reject the block */ }
+func VerifyMerkleProofAt(leaf [32]byte, index, total int, steps []MerkleProofStep, wantRoot [32]byte) (bool, error) {
+	// Step 1: Validate the position
+	if index < 0 || index >= total {
+		return false, fmt.Errorf("leaf index %d out of range [0, %d)", index, total)
+	}
+
+	// Step 2: Walk up the levels, checking each step against the expected shape
+	current := leaf
+	step := 0
+	for n := total; n > 1; n = (n + 1) / 2 {
+		if step == len(steps) {
+			return false, fmt.Errorf("proof has %d steps, tree of %d leaves needs more", len(steps), total)
+		}
+		s := steps[step]
+		if s.LeftIsSibling != (index%2 == 1) {
+			return false, fmt.Errorf("proof step %d has the sibling on the wrong side for index", step)
+		}
+
+		// Step 2a: Only the last node of an odd level may be paired with itself
+		selfPaired := index == n-1 && index%2 == 0
+		if selfPaired && s.Sibling != current {
+			return false, nil
+		}
+		if !selfPaired && s.Sibling == current {
+			return false, ErrMerkleMutation
+		}
+
+		if s.LeftIsSibling {
+			current = SHA256D(Concat(s.Sibling[:], current[:]))
+		} else {
+			current = SHA256D(Concat(current[:], s.Sibling[:]))
+		}
+		index /= 2
+		step++
+	}
+	if step != len(steps) {
+		return false, fmt.Errorf("proof has %d steps, tree of %d leaves needs %d", len(steps), total, step)
+	}
+
+	// Step 3: Check if we reached the expected root
+	return current == wantRoot, nil
+}
+
 // Reverse32 flips the byte order (useful for converting between formats)
 // Example:
 //
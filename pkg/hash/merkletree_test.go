@@ -2,8 +2,12 @@ package hash
 
 import (
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"log"
 	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/fixtures"
 )
 
 // helper: parse hex into [32]byte
@@ -190,3 +194,152 @@ func TestReverse32(t *testing.T) {
 		})
 	}
 }
+
+// TestMerkleRootGolden snapshots merkle roots for trees of 1..8 leaves (odd counts duplicate the last leaf)
+func TestMerkleRootGolden(t *testing.T) {
+	roots := make(map[string]string)
+	var leaves [][32]byte
+	for i := 1; i <= 8; i++ {
+		leaves = append(leaves, SHA256([]byte(fmt.Sprintf("leaf %d", i))))
+		root := MerkleRoot(leaves)
+		roots[fmt.Sprintf("%d", i)] = hex.EncodeToString(root[:])
+	}
+	fixtures.GoldenJSON(t, "merkle_roots.golden.json", roots)
+}
+
+func TestMerkleRootMutated(t *testing.T) {
+	a, b, c, d := SHA256([]byte("a")), SHA256([]byte("b")), SHA256([]byte("c")), SHA256([]byte("d"))
+	tests := []struct {
+		name    string
+		leaves  [][32]byte
+		mutated bool
+	}{
+		{"empty", nil, false},
+		{"single", [][32]byte{a}, false},
+		{"odd count", [][32]byte{a, b, c}, false},
+		{"duplicated last leaf", [][32]byte{a, b, c, c}, true},
+		{"duplicated pair at start", [][32]byte{a, a, b, c}, true},
+		{"equal leaves not paired", [][32]byte{a, b, b, c}, false},
+		{"duplicated subtree", [][32]byte{a, b, c, d, c, d}, false},
+		{"duplicated level-1 pair", [][32]byte{a, b, a, b}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, mutated := MerkleRootMutated(tt.leaves)
+			if root != MerkleRoot(tt.leaves) {
+				t.Error("Expected the same root as MerkleRoot")
+			}
+			if mutated != tt.mutated {
+				t.Errorf("Expected mutated=%t, got %t", tt.mutated, mutated)
+			}
+		})
+	}
+
+	// The classic collision: both leaf lists share a root
+	if MerkleRoot([][32]byte{a, b, c}) != MerkleRoot([][32]byte{a, b, c, c}) {
+		t.Error("Expected [a b c] and [a b c c] to share a root")
+	}
+}
+
+func TestMerkleRootFromHex(t *testing.T) {
+	tests := []struct {
+		name     string
+		txids    []string
+		expected string // Display order, as shown by explorers
+	}{
+		{
+			// Genesis block: the only txid is the root
+			"genesis",
+			[]string{"4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"},
+			"4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+		},
+		{
+			"block 100000",
+			[]string{
+				"8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87",
+				"fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4",
+				"6359f0868171b1d194cbee1af2f16ea598ae8fad666d9b012c8ed2b79a236ec4",
+				"e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1d",
+			},
+			"f3e94742aca4b5ef85488dc37c06c3282295ffec960994b2c0d5ac2a25a95766",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, display, err := MerkleRootFromHex(tt.txids)
+			if err != nil {
+				t.Fatalf("MerkleRootFromHex failed: %v", err)
+			}
+			if display != tt.expected {
+				t.Errorf("Expected display root %s, got %s", tt.expected, display)
+			}
+			if root != Reverse32(must32(tt.expected)) {
+				t.Errorf("Expected internal root to be the display root reversed, got %x", root)
+			}
+		})
+	}
+
+	for _, bad := range [][]string{
+		nil,
+		{"zz5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"},
+		{"4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda3"},
+	} {
+		if _, _, err := MerkleRootFromHex(bad); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
+
+func TestVerifyMerkleProofAt(t *testing.T) {
+	a, b, c := SHA256([]byte("a")), SHA256([]byte("b")), SHA256([]byte("c"))
+	honest := NewMerkleTree([][32]byte{a, b, c})
+	root := honest.Root()
+
+	// Every honest proof verifies, including the self-paired last leaf
+	for i, leaf := range [][32]byte{a, b, c} {
+		proof, _ := honest.ProofAt(i)
+		ok, err := VerifyMerkleProofAt(leaf, i, 3, proof, root)
+		if !ok || err != nil {
+			t.Errorf("leaf %d: Expected valid proof, got %t, %v", i, ok, err)
+		}
+	}
+
+	// The mutated tree [a b c c] has a proof for index 3 that VerifyMerkleProof accepts
+	mutated := NewMerkleTree([][32]byte{a, b, c, c})
+	proof, _ := mutated.ProofAt(3)
+	if ok, _ := VerifyMerkleProof(c, proof, root); !ok {
+		t.Fatal("Expected the mutated proof to fool VerifyMerkleProof")
+	}
+	if _, err := VerifyMerkleProofAt(c, 3, 4, proof, root); !errors.Is(err, ErrMerkleMutation) {
+		t.Errorf("Expected ErrMerkleMutation, got %v", err)
+	}
+
+	// Shape errors
+	proofC, _ := honest.ProofAt(2)
+	tests := []struct {
+		name  string
+		index int
+		total int
+		steps []MerkleProofStep
+	}{
+		{"index out of range", 3, 3, proofC},
+		{"negative index", -1, 3, proofC},
+		{"too few steps", 2, 3, proofC[:1]},
+		{"too many steps", 2, 3, append(proofC, proofC[0])},
+		{"wrong side", 1, 3, proofC},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ok, err := VerifyMerkleProofAt(c, tt.index, tt.total, tt.steps, root); ok || err == nil {
+				t.Errorf("Expected an error, got %t, %v", ok, err)
+			}
+		})
+	}
+
+	// A self-paired position with a different sibling is simply invalid
+	bad := append([]MerkleProofStep(nil), proofC...)
+	bad[0].Sibling = a
+	if ok, err := VerifyMerkleProofAt(c, 2, 3, bad, root); ok || err != nil {
+		t.Errorf("Expected false without error, got %t, %v", ok, err)
+	}
+}
@@ -0,0 +1,119 @@
+package hash
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	stdhash "hash"
+)
+
+// SHA-256 midstates
+//
+// SHA-256 absorbs its input in 64-byte blocks, carrying eight 32-bit words of
+// state from one block to the next. A block header is 80 bytes: the first
+// block (version, previous hash and most of the merkle root) is the same for
+// every nonce, and only the second block (the rest of the merkle root, time,
+// bits and nonce) changes. Miners therefore hash the first block once and
+// resume from that midstate for every nonce, halving the work of the inner
+// hash. The outer hash of SHA256D still runs in full.
+//
+// Resumption goes through the standard library's encoding.BinaryMarshaler
+// state format, so the fast SHA-256 implementation does the compression.
+
+// ErrMidstateLength is returned when the absorbed length is not a whole number of blocks
+var ErrMidstateLength = errors.New("midstate length must be a multiple of 64 bytes")
+
+// sha256StateMagic prefixes a marshaled crypto/sha256 state
+const sha256StateMagic = "sha\x03"
+
+// Midstate is the SHA-256 state after absorbing whole 64-byte blocks
+type Midstate struct {
+	State  [8]uint32 // Chaining value
+	Length uint64    // Bytes absorbed so far, a multiple of 64
+}
+
+// NewMidstate absorbs prefix, whose length must be a multiple of 64
+//
+// Example:
+//
+//	m, _ := NewMidstate(header[:64])
+//	binary.LittleEndian.PutUint32(header[76:], nonce)
+//	blockHash := m.SHA256D(header[64:])
+func NewMidstate(prefix []byte) (Midstate, error) {
+	// Step 1: Only whole blocks leave nothing buffered
+	if len(prefix)%sha256.BlockSize != 0 {
+		return Midstate{}, fmt.Errorf("%w, got %d", ErrMidstateLength, len(prefix))
+	}
+
+	// Step 2: Hash the prefix and read the chaining value out of the marshaled state
+	h := sha256.New()
+	h.Write(prefix)
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return Midstate{}, fmt.Errorf("failed to export sha256 state: %w", err)
+	}
+	m := Midstate{Length: uint64(len(prefix))}
+	for i := range m.State {
+		m.State[i] = binary.BigEndian.Uint32(state[len(sha256StateMagic)+4*i:])
+	}
+	return m, nil
+}
+
+// MidstateFromBytes rebuilds a midstate from Bytes and the number of bytes it absorbed
+func MidstateFromBytes(b [32]byte, length uint64) (Midstate, error) {
+	if length%sha256.BlockSize != 0 {
+		return Midstate{}, fmt.Errorf("%w, got %d", ErrMidstateLength, length)
+	}
+	m := Midstate{Length: length}
+	for i := range m.State {
+		m.State[i] = binary.BigEndian.Uint32(b[4*i:])
+	}
+	return m, nil
+}
+
+// Bytes returns the state words big-endian, the usual 32-byte midstate encoding
+func (m Midstate) Bytes() [32]byte {
+	var b [32]byte
+	for i, w := range m.State {
+		binary.BigEndian.PutUint32(b[4*i:], w)
+	}
+	return b
+}
+
+// New returns a SHA-256 hash.Hash resumed at the midstate
+//
+// Writing the rest of the message and calling Sum gives the same digest as
+// hashing the prefix and the rest in one go.
+func (m Midstate) New() stdhash.Hash {
+	// Step 1: Marshaled state is magic || h[0..7] || 64-byte buffer || length, big-endian
+	state := make([]byte, 0, len(sha256StateMagic)+32+sha256.BlockSize+8)
+	state = append(state, sha256StateMagic...)
+	for _, w := range m.State {
+		state = binary.BigEndian.AppendUint32(state, w)
+	}
+	state = append(state, make([]byte, sha256.BlockSize)...)
+	state = binary.BigEndian.AppendUint64(state, m.Length)
+
+	// Step 2: Load it into a fresh hash
+	h := sha256.New()
+	if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		// The state is built above in the format crypto/sha256 documents
+		panic(err)
+	}
+	return h
+}
+
+// Sum256 returns SHA256(prefix || suffix) for the prefix the midstate absorbed
+func (m Midstate) Sum256(suffix []byte) [32]byte {
+	h := m.New()
+	h.Write(suffix)
+	return [32]byte(h.Sum(nil))
+}
+
+// SHA256D returns SHA256(SHA256(prefix || suffix)) for the prefix the midstate absorbed
+func (m Midstate) SHA256D(suffix []byte) [32]byte {
+	inner := m.Sum256(suffix)
+	return sha256.Sum256(inner[:])
+}
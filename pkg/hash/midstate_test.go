@@ -0,0 +1,78 @@
+package hash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestMidstate(t *testing.T) {
+	// Genesis block header
+	header, _ := hex.DecodeString("0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c")
+
+	m, err := NewMidstate(header[:64])
+	if err != nil {
+		t.Fatalf("NewMidstate failed: %v", err)
+	}
+	if got, want := m.SHA256D(header[64:]), SHA256D(header); got != want {
+		t.Errorf("SHA256D() = %x, expected %x", got, want)
+	}
+	if got, want := m.Sum256(header[64:]), SHA256(header); got != want {
+		t.Errorf("Sum256() = %x, expected %x", got, want)
+	}
+
+	// Every nonce resumes from the same midstate
+	suffix := append([]byte(nil), header[64:]...)
+	for nonce := uint32(0); nonce < 100; nonce++ {
+		binary.LittleEndian.PutUint32(suffix[12:], nonce)
+		full := append(append([]byte(nil), header[:64]...), suffix...)
+		if m.SHA256D(suffix) != SHA256D(full) {
+			t.Fatalf("Expected the midstate hash to match for nonce %d", nonce)
+		}
+	}
+
+	// Round trip through the 32-byte encoding
+	resumed, err := MidstateFromBytes(m.Bytes(), m.Length)
+	if err != nil {
+		t.Fatalf("MidstateFromBytes failed: %v", err)
+	}
+	if resumed != m {
+		t.Errorf("Expected %+v, got %+v", m, resumed)
+	}
+}
+
+func TestMidstateLengths(t *testing.T) {
+	// The state after no blocks is the SHA-256 initial value
+	m, err := NewMidstate(nil)
+	if err != nil {
+		t.Fatalf("NewMidstate failed: %v", err)
+	}
+	iv := m.Bytes()
+	if hex.EncodeToString(iv[:]) != "6a09e667bb67ae853c6ef372a54ff53a510e527f9b05688c1f83d9ab5be0cd19" {
+		t.Errorf("Expected the SHA-256 initial value, got %x", iv)
+	}
+
+	// Several blocks, then suffixes shorter and longer than a block
+	prefix := bytes.Repeat([]byte("midstate"), 24)
+	m, err = NewMidstate(prefix)
+	if err != nil {
+		t.Fatalf("NewMidstate failed: %v", err)
+	}
+	for _, n := range []int{0, 1, 55, 56, 64, 200} {
+		suffix := bytes.Repeat([]byte{0xab}, n)
+		if m.Sum256(suffix) != SHA256(Concat(prefix, suffix)) {
+			t.Errorf("Expected the resumed hash to match with a %d-byte suffix", n)
+		}
+	}
+
+	if _, err := NewMidstate(make([]byte, 80)); !errors.Is(err, ErrMidstateLength) {
+		t.Errorf("Expected ErrMidstateLength for 80 bytes, got %v", err)
+	}
+	for _, length := range []uint64{63, 65, 100} {
+		if _, err := MidstateFromBytes(iv, length); !errors.Is(err, ErrMidstateLength) {
+			t.Errorf("Expected ErrMidstateLength for length %d, got %v", length, err)
+		}
+	}
+}
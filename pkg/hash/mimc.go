@@ -0,0 +1,87 @@
+package hash
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// MiMC
+//
+// MiMC is the simplest SNARK-friendly construction: a block cipher whose
+// rounds are x -> (x + k + c_i)^7 over a prime field, with the exponent chosen
+// coprime to p - 1 so each round is a permutation. MiMC7 is the circomlib
+// variant over the BN254 scalar field with 91 rounds. Its round constants are
+// a Keccak256 chain seeded with "mimc" (c_0 = 0, c_i = Keccak256(c_{i-1})
+// reduced mod p), so they are computed here instead of being tabulated.
+//
+// MiMC needs more constraints than Poseidon for the same security and is
+// mostly of historical interest (Tornado Cash used it); it is here to compare
+// the two.
+
+// mimc7Rounds is the number of rounds of circomlib's MiMC7
+const mimc7Rounds = 91
+
+// mimc7Constants returns the round constants, computed once
+var mimc7Constants = sync.OnceValue(func() []*big.Int {
+	constants := make([]*big.Int, mimc7Rounds)
+	constants[0] = new(big.Int)
+	c := Keccak256([]byte("mimc"))
+	for i := 1; i < mimc7Rounds; i++ {
+		c = Keccak256(c[:])
+		constants[i] = new(big.Int).Mod(new(big.Int).SetBytes(c[:]), bn254ScalarField)
+	}
+	return constants
+})
+
+// MiMC7 encrypts x under key k with circomlib's MiMC7 over the BN254 scalar field
+//
+// Example:
+//
+//	h, err := MiMC7(big.NewInt(1), big.NewInt(2))
+//	// h = 10594780656576967754230020536574539122676596303354946869887184401991294982664
+func MiMC7(x, k *big.Int) (*big.Int, error) {
+	// Step 1: Both inputs must be field elements
+	for _, v := range []*big.Int{x, k} {
+		if v.Sign() < 0 || v.Cmp(bn254ScalarField) >= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrFieldElement, v)
+		}
+	}
+
+	// Step 2: x = (x + k + c_i)^7 for every round
+	seven := big.NewInt(7)
+	r := new(big.Int).Set(x)
+	for _, c := range mimc7Constants() {
+		r.Add(r, k).Add(r, c)
+		r.Exp(r, seven, bn254ScalarField)
+	}
+
+	// Step 3: Final key addition
+	r.Add(r, k)
+	return r.Mod(r, bn254ScalarField), nil
+}
+
+// MiMC7Hash hashes field elements with circomlib's multiHash: r = r + x_i + MiMC7(x_i, r), starting from key
+func MiMC7Hash(inputs []*big.Int, key *big.Int) (*big.Int, error) {
+	r := new(big.Int).Set(key)
+	for _, x := range inputs {
+		h, err := MiMC7(x, r)
+		if err != nil {
+			return nil, err
+		}
+		r.Add(r, x).Add(r, h).Mod(r, bn254ScalarField)
+	}
+	return r, nil
+}
+
+// MiMC7Compress is MiMC7Hash of two big-endian 32-byte values with key 0, usable as a NodeHash
+//
+// Like Poseidon.Compress, values at or above the modulus are reduced mod p first.
+func MiMC7Compress(left, right [32]byte) [32]byte {
+	a := new(big.Int).SetBytes(left[:])
+	b := new(big.Int).SetBytes(right[:])
+	h, _ := MiMC7Hash([]*big.Int{a.Mod(a, bn254ScalarField), b.Mod(b, bn254ScalarField)}, new(big.Int))
+	var out [32]byte
+	h.FillBytes(out[:])
+	return out
+}
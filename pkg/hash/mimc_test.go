@@ -0,0 +1,61 @@
+package hash
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// TestMiMC7 uses circomlib's vectors
+func TestMiMC7(t *testing.T) {
+	h, err := MiMC7(big.NewInt(1), big.NewInt(2))
+	if err != nil {
+		t.Fatalf("MiMC7 failed: %v", err)
+	}
+	if h.String() != "10594780656576967754230020536574539122676596303354946869887184401991294982664" {
+		t.Errorf("MiMC7(1, 2) = %s", h)
+	}
+
+	// The first round constant after c_0 = 0 is Keccak256(Keccak256("mimc")) mod p
+	if c := mimc7Constants()[1].Text(16); c != "2e2ebbb178296b63d88ec198f0976ad98bc1d4eb0d921ddd2eb86cb7e70a98e5" {
+		t.Errorf("Expected the Keccak constant chain, got c_1 = %s", c)
+	}
+
+	if _, err := MiMC7(bn254ScalarField, big.NewInt(0)); !errors.Is(err, ErrFieldElement) {
+		t.Errorf("Expected ErrFieldElement, got %v", err)
+	}
+}
+
+func TestMiMC7Hash(t *testing.T) {
+	a, b := big.NewInt(1), big.NewInt(2)
+
+	// multiHash of one element is x + MiMC7(x, key)
+	single, err := MiMC7Hash([]*big.Int{a}, big.NewInt(2))
+	if err != nil {
+		t.Fatalf("MiMC7Hash failed: %v", err)
+	}
+	enc, _ := MiMC7(a, big.NewInt(2))
+	want := new(big.Int).Add(big.NewInt(3), enc)
+	if single.Cmp(want.Mod(want, bn254ScalarField)) != 0 {
+		t.Errorf("Expected %s, got %s", want, single)
+	}
+
+	// Compress agrees with MiMC7Hash and depends on the order
+	pair, err := MiMC7Hash([]*big.Int{a, b}, new(big.Int))
+	if err != nil {
+		t.Fatalf("MiMC7Hash failed: %v", err)
+	}
+	var one, two [32]byte
+	one[31], two[31] = 1, 2
+	got := MiMC7Compress(one, two)
+	if new(big.Int).SetBytes(got[:]).Cmp(pair) != 0 {
+		t.Errorf("Expected Compress to equal MiMC7Hash, got %x and %s", got, pair)
+	}
+	if MiMC7Compress(two, one) == got {
+		t.Error("Expected the order of the inputs to matter")
+	}
+
+	if _, err := MiMC7Hash([]*big.Int{big.NewInt(-1)}, new(big.Int)); !errors.Is(err, ErrFieldElement) {
+		t.Errorf("Expected ErrFieldElement, got %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+package hash
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// Merkle Mountain Ranges
+//
+// An MMR is an append-only list of perfect binary Merkle trees ("mountains"),
+// one for each set bit of the leaf count. Seven leaves form mountains of 4, 2
+// and 1:
+//
+//	       /\
+//	      /  \
+//	     /\  /\   /\
+//	    0  1 2  3 4  5  6
+//
+// Appending a leaf only merges equal-height mountains on the right, so old
+// nodes never change and a proof stays valid for the mountain it was made in
+// (OpenTimestamps and Grin rely on this). The root "bags" the peaks from right
+// to left and commits to the leaf count:
+//
+//	bag  = peak_k;  bag = H("MMR/bag", peak_i || bag) for i = k-1 .. 0
+//	root = H("MMR/root", count (8 bytes LE) || bag)
+//
+// Leaves and inner nodes use different tagged hashes, so a leaf can never be
+// passed off as an inner node.
+
+// ErrInvalidMMR is returned when a serialized MMR or proof is malformed
+var ErrInvalidMMR = errors.New("invalid merkle mountain range")
+
+// MMR tags
+const (
+	mmrLeafTag = "MMR/leaf"
+	mmrNodeTag = "MMR/node"
+	mmrBagTag  = "MMR/bag"
+	mmrRootTag = "MMR/root"
+)
+
+// MMR is an append-only Merkle Mountain Range
+type MMR struct {
+	levels [][][32]byte // levels[h] holds every complete subtree of height h, left to right
+}
+
+// MMRProof shows that a leaf is in an MMR with LeafCount leaves
+type MMRProof struct {
+	LeafIndex uint64
+	LeafCount uint64
+	Siblings  [][32]byte // Path inside the leaf's mountain, from the leaf up
+	Peaks     [][32]byte // All peaks, left to right
+}
+
+// NewMMR returns an empty MMR
+//
+// Example:
+//
+//	m := NewMMR()
+//	i := m.Append(SHA256(document))
+//	proof, _ := m.Prove(i)
+//	ok := VerifyMMRProof(SHA256(document), proof, m.Root())
+func NewMMR() *MMR {
+	return &MMR{levels: [][][32]byte{nil}}
+}
+
+// Append adds a leaf and returns its index
+func (m *MMR) Append(leaf [32]byte) uint64 {
+	m.appendNode(TaggedSHA256(mmrLeafTag, leaf[:]))
+	return m.Len() - 1
+}
+
+// Len returns the number of leaves
+func (m *MMR) Len() uint64 {
+	return uint64(len(m.levels[0]))
+}
+
+// Peaks returns the mountain peaks from left (highest) to right (lowest)
+func (m *MMR) Peaks() [][32]byte {
+	var peaks [][32]byte
+	for h := len(m.levels) - 1; h >= 0; h-- {
+		if level := m.levels[h]; len(level)%2 == 1 {
+			peaks = append(peaks, level[len(level)-1])
+		}
+	}
+	return peaks
+}
+
+// Root returns the bagged root committing to all peaks and the leaf count
+func (m *MMR) Root() [32]byte {
+	return bagPeaks(m.Peaks(), m.Len())
+}
+
+// Prove returns the inclusion proof of leaf i
+func (m *MMR) Prove(i uint64) (*MMRProof, error) {
+	if i >= m.Len() {
+		return nil, fmt.Errorf("leaf index %d out of range [0, %d)", i, m.Len())
+	}
+
+	// Step 1: Climb while the node has a sibling; the first node without one is a peak
+	proof := &MMRProof{LeafIndex: i, LeafCount: m.Len(), Peaks: m.Peaks()}
+	idx := i
+	for h := 0; ; h++ {
+		sibling := idx ^ 1
+		if sibling >= uint64(len(m.levels[h])) {
+			break
+		}
+		proof.Siblings = append(proof.Siblings, m.levels[h][sibling])
+		idx /= 2
+	}
+	return proof, nil
+}
+
+// VerifyMMRProof checks that leaf is at proof.LeafIndex in the MMR with the given root
+func VerifyMMRProof(leaf [32]byte, proof *MMRProof, root [32]byte) bool {
+	// Step 1: The shape must match the leaf count
+	if proof == nil || proof.LeafIndex >= proof.LeafCount || len(proof.Peaks) != bits.OnesCount64(proof.LeafCount) {
+		return false
+	}
+	peak, height, offset := mmrMountain(proof.LeafIndex, proof.LeafCount)
+	if len(proof.Siblings) != height {
+		return false
+	}
+
+	// Step 2: Climb to the peak; the offset within the mountain gives each side
+	current := TaggedSHA256(mmrLeafTag, leaf[:])
+	for _, sibling := range proof.Siblings {
+		if offset&1 == 1 {
+			current = TaggedSHA256(mmrNodeTag, sibling[:], current[:])
+		} else {
+			current = TaggedSHA256(mmrNodeTag, current[:], sibling[:])
+		}
+		offset >>= 1
+	}
+
+	// Step 3: The computed peak must be the right one, and the peaks must bag to root
+	return current == proof.Peaks[peak] && bagPeaks(proof.Peaks, proof.LeafCount) == root
+}
+
+// Serialize encodes the MMR as its leaf count and leaf nodes; inner nodes are recomputed on parse
+func (m *MMR) Serialize() []byte {
+	buf := binary.LittleEndian.AppendUint64(nil, m.Len())
+	for _, h := range m.levels[0] {
+		buf = append(buf, h[:]...)
+	}
+	return buf
+}
+
+// ParseMMR decodes an MMR produced by Serialize
+func ParseMMR(raw []byte) (*MMR, error) {
+	// Step 1: The length must match the leaf count
+	if len(raw) < 8 {
+		return nil, fmt.Errorf("%w: truncated", ErrInvalidMMR)
+	}
+	n := binary.LittleEndian.Uint64(raw)
+	if n != uint64(len(raw)-8)/32 || (len(raw)-8)%32 != 0 {
+		return nil, fmt.Errorf("%w: %d leaves in %d bytes", ErrInvalidMMR, n, len(raw))
+	}
+
+	// Step 2: Rebuild the mountains from the leaf nodes
+	m := NewMMR()
+	for i := uint64(0); i < n; i++ {
+		m.appendNode([32]byte(raw[8+32*i:]))
+	}
+	return m, nil
+}
+
+// Serialize encodes the proof: index, count (8 bytes LE each), sibling count, siblings, peaks
+func (p *MMRProof) Serialize() []byte {
+	buf := binary.LittleEndian.AppendUint64(nil, p.LeafIndex)
+	buf = binary.LittleEndian.AppendUint64(buf, p.LeafCount)
+	buf = append(buf, byte(len(p.Siblings)))
+	for _, h := range append(append([][32]byte(nil), p.Siblings...), p.Peaks...) {
+		buf = append(buf, h[:]...)
+	}
+	return buf
+}
+
+// ParseMMRProof decodes a proof produced by MMRProof.Serialize
+func ParseMMRProof(raw []byte) (*MMRProof, error) {
+	if len(raw) < 17 {
+		return nil, fmt.Errorf("%w: truncated proof", ErrInvalidMMR)
+	}
+	p := &MMRProof{
+		LeafIndex: binary.LittleEndian.Uint64(raw),
+		LeafCount: binary.LittleEndian.Uint64(raw[8:]),
+	}
+	siblings, peaks := int(raw[16]), bits.OnesCount64(p.LeafCount)
+	raw = raw[17:]
+	if len(raw) != 32*(siblings+peaks) {
+		return nil, fmt.Errorf("%w: expected %d hashes, got %d bytes", ErrInvalidMMR, siblings+peaks, len(raw))
+	}
+	for i := 0; i < siblings+peaks; i++ {
+		h := [32]byte(raw[32*i:])
+		if i < siblings {
+			p.Siblings = append(p.Siblings, h)
+		} else {
+			p.Peaks = append(p.Peaks, h)
+		}
+	}
+	return p, nil
+}
+
+// appendNode appends a hashed leaf node and merges completed pairs upwards
+func (m *MMR) appendNode(node [32]byte) {
+	// Step 1: Add the leaf node
+	m.levels[0] = append(m.levels[0], node)
+
+	// Step 2: While the newest subtree completes a pair, merge it into the level above
+	for h := 0; len(m.levels[h])%2 == 0; h++ {
+		if h+1 == len(m.levels) {
+			m.levels = append(m.levels, nil)
+		}
+		level := m.levels[h]
+		parent := TaggedSHA256(mmrNodeTag, level[len(level)-2][:], level[len(level)-1][:])
+		m.levels[h+1] = append(m.levels[h+1], parent)
+	}
+}
+
+// mmrMountain locates leaf i: the index of its peak, the mountain height and the offset inside it
+func mmrMountain(i, count uint64) (peak, height int, offset uint64) {
+	start := uint64(0)
+	for h := 63; h >= 0; h-- {
+		size := uint64(1) << h
+		if count&size == 0 {
+			continue
+		}
+		if i < start+size {
+			return peak, h, i - start
+		}
+		start += size
+		peak++
+	}
+	return peak, 0, 0
+}
+
+// bagPeaks folds the peaks from right to left and commits to the leaf count
+func bagPeaks(peaks [][32]byte, count uint64) [32]byte {
+	var bag [32]byte
+	for i := len(peaks) - 1; i >= 0; i-- {
+		if i == len(peaks)-1 {
+			bag = peaks[i]
+		} else {
+			bag = TaggedSHA256(mmrBagTag, peaks[i][:], bag[:])
+		}
+	}
+	return TaggedSHA256(mmrRootTag, binary.LittleEndian.AppendUint64(nil, count), bag[:])
+}
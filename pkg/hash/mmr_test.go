@@ -0,0 +1,141 @@
+package hash
+
+import (
+	"bytes"
+	"errors"
+	"math/bits"
+	"testing"
+)
+
+func TestMMR(t *testing.T) {
+	leaves := testLeaves(40)
+	m := NewMMR()
+	var roots [][32]byte
+	for i, leaf := range leaves {
+		if idx := m.Append(leaf); idx != uint64(i) {
+			t.Fatalf("Expected index %d, got %d", i, idx)
+		}
+		roots = append(roots, m.Root())
+
+		// One peak per set bit of the leaf count
+		if got, want := len(m.Peaks()), bits.OnesCount(uint(i+1)); got != want {
+			t.Fatalf("%d leaves: Expected %d peaks, got %d", i+1, want, got)
+		}
+
+		// Every leaf proves against the current root
+		for j := 0; j <= i; j++ {
+			proof, err := m.Prove(uint64(j))
+			if err != nil {
+				t.Fatalf("Prove(%d) failed: %v", j, err)
+			}
+			if !VerifyMMRProof(leaves[j], proof, m.Root()) {
+				t.Fatalf("%d leaves: proof for leaf %d does not verify", i+1, j)
+			}
+		}
+	}
+
+	// Every append changes the root
+	seen := map[[32]byte]bool{}
+	for _, r := range roots {
+		if seen[r] {
+			t.Fatal("Expected distinct roots")
+		}
+		seen[r] = true
+	}
+}
+
+func TestMMRPeaksAreStable(t *testing.T) {
+	// A mountain never changes once complete, so an old proof's peak is still
+	// a node of the grown MMR: the first 32 leaves form the first mountain of 40.
+	leaves := testLeaves(40)
+	m := NewMMR()
+	for _, leaf := range leaves[:32] {
+		m.Append(leaf)
+	}
+	before := m.Peaks()
+	for _, leaf := range leaves[32:] {
+		m.Append(leaf)
+	}
+	if m.Peaks()[0] != before[0] {
+		t.Error("Expected the complete mountain to keep its peak")
+	}
+}
+
+func TestVerifyMMRProofRejects(t *testing.T) {
+	leaves := testLeaves(11)
+	m := NewMMR()
+	for _, leaf := range leaves {
+		m.Append(leaf)
+	}
+	root := m.Root()
+
+	tests := []struct {
+		name   string
+		leaf   [32]byte
+		mutate func(p *MMRProof)
+	}{
+		{"wrong leaf", leaves[4], func(p *MMRProof) {}},
+		{"wrong index", leaves[5], func(p *MMRProof) { p.LeafIndex = 4 }},
+		{"index out of range", leaves[5], func(p *MMRProof) { p.LeafIndex = 11 }},
+		{"wrong count", leaves[5], func(p *MMRProof) { p.LeafCount = 12 }},
+		{"tampered sibling", leaves[5], func(p *MMRProof) { p.Siblings[0][0] ^= 1 }},
+		{"missing sibling", leaves[5], func(p *MMRProof) { p.Siblings = p.Siblings[1:] }},
+		{"tampered other peak", leaves[5], func(p *MMRProof) { p.Peaks[2][0] ^= 1 }},
+		{"leaf node passed as inner node", leaves[5], func(p *MMRProof) { p.Siblings = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proof, _ := m.Prove(5)
+			tt.mutate(proof)
+			if VerifyMMRProof(tt.leaf, proof, root) {
+				t.Error("Expected verification to fail")
+			}
+		})
+	}
+	if VerifyMMRProof(leaves[5], nil, root) {
+		t.Error("Expected nil proof to fail")
+	}
+	if _, err := m.Prove(11); err == nil {
+		t.Error("Expected Prove to fail for an index out of range")
+	}
+}
+
+func TestMMRSerialization(t *testing.T) {
+	m := NewMMR()
+	for _, leaf := range testLeaves(13) {
+		m.Append(leaf)
+	}
+
+	parsed, err := ParseMMR(m.Serialize())
+	if err != nil {
+		t.Fatalf("ParseMMR failed: %v", err)
+	}
+	if parsed.Root() != m.Root() || parsed.Len() != m.Len() {
+		t.Error("Expected the parsed MMR to have the same root and size")
+	}
+	empty, err := ParseMMR(NewMMR().Serialize())
+	if err != nil || empty.Len() != 0 {
+		t.Errorf("Expected an empty MMR to round trip: %v", err)
+	}
+
+	proof, _ := m.Prove(9)
+	parsedProof, err := ParseMMRProof(proof.Serialize())
+	if err != nil {
+		t.Fatalf("ParseMMRProof failed: %v", err)
+	}
+	if !bytes.Equal(parsedProof.Serialize(), proof.Serialize()) || !VerifyMMRProof(testLeaves(13)[9], parsedProof, m.Root()) {
+		t.Error("Expected the parsed proof to verify")
+	}
+
+	raw, rawProof := m.Serialize(), proof.Serialize()
+	for _, bad := range [][]byte{nil, raw[:7], raw[:len(raw)-1], append(append([]byte(nil), raw...), 0)} {
+		if _, err := ParseMMR(bad); !errors.Is(err, ErrInvalidMMR) {
+			t.Errorf("Expected ErrInvalidMMR for %d bytes, got %v", len(bad), err)
+		}
+	}
+	for _, bad := range [][]byte{rawProof[:16], rawProof[:len(rawProof)-1]} {
+		if _, err := ParseMMRProof(bad); !errors.Is(err, ErrInvalidMMR) {
+			t.Errorf("Expected ErrInvalidMMR for %d bytes, got %v", len(bad), err)
+		}
+	}
+}
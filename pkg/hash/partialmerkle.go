@@ -0,0 +1,281 @@
+package hash
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Partial Merkle trees (BIP37)
+//
+// A merkleblock message (and Bitcoin Core's gettxoutproof) proves that some
+// transactions are in a block without sending all its txids. It holds the
+// number of transactions, a list of hashes and a list of flag bits produced by
+// a depth-first walk of the tree:
+//
+//	flag 0: nothing below this node matches; its hash is in the hash list
+//	flag 1: a match is below; descend (or, at a leaf, the leaf is a match
+//	        and its txid is in the hash list)
+//
+// The verifier replays the same walk, consuming bits and hashes, and
+// recomputes the root. Wire format:
+//
+//	total (uint32 LE) || compactSize(#hashes) || hashes || compactSize(#bytes) || flag bytes
+//
+// Flag bits are packed least significant bit first.
+
+// maxPartialMerkleTransactions bounds the transaction count like Bitcoin Core:
+// the maximum block weight divided by the minimum transaction weight.
+const maxPartialMerkleTransactions = 4_000_000 / 240
+
+// ErrInvalidPartialMerkleTree is returned when the bits and hashes do not describe a tree
+var ErrInvalidPartialMerkleTree = errors.New("invalid partial merkle tree")
+
+// PartialMerkleTree is the BIP37 encoding of a Merkle tree with some leaves revealed
+type PartialMerkleTree struct {
+	Total  uint32     // Number of transactions in the block
+	Hashes [][32]byte // Hashes in depth-first order, internal byte order
+	Flags  []bool     // Traversal bits in depth-first order
+}
+
+// NewPartialMerkleTree builds the partial tree revealing the txids where match is true
+//
+// Example:
+//
+//	match := make([]bool, len(txids))
+//	match[5] = true
+//	pmt, err := NewPartialMerkleTree(txids, match)
+//	raw := pmt.Serialize()
+func NewPartialMerkleTree(txids [][32]byte, match []bool) (*PartialMerkleTree, error) {
+	// Step 1: Validate the inputs
+	if len(txids) == 0 || len(txids) != len(match) {
+		return nil, fmt.Errorf("need one match flag per txid, got %d txids and %d flags", len(txids), len(match))
+	}
+
+	// Step 2: Walk the tree from the root
+	p := &PartialMerkleTree{Total: uint32(len(txids))}
+	p.build(p.height(), 0, txids, match)
+	return p, nil
+}
+
+// ExtractMatches verifies the structure and returns the root with the matched txids and their positions
+//
+// The caller must compare root with the block header's merkle root.
+//
+// Example:
+//
+//	root, txids, indexes, err := pmt.ExtractMatches()
+//	if err == nil && root == header.MerkleRoot {
+//		// txids[i] is at position indexes[i] in the block
+//	}
+func (p *PartialMerkleTree) ExtractMatches() (root [32]byte, matches [][32]byte, indexes []int, err error) {
+	// Step 1: Reject impossible sizes before walking
+	if p.Total == 0 || p.Total > maxPartialMerkleTransactions {
+		return root, nil, nil, fmt.Errorf("%w: %d transactions", ErrInvalidPartialMerkleTree, p.Total)
+	}
+	if len(p.Hashes) > int(p.Total) || len(p.Flags) < len(p.Hashes) {
+		return root, nil, nil, fmt.Errorf("%w: %d hashes and %d flags for %d transactions",
+			ErrInvalidPartialMerkleTree, len(p.Hashes), len(p.Flags), p.Total)
+	}
+
+	// Step 2: Replay the walk
+	w := &partialMerkleWalk{tree: p}
+	root, err = w.extract(p.height(), 0)
+	if err != nil {
+		return [32]byte{}, nil, nil, err
+	}
+
+	// Step 3: Everything must be consumed, apart from the padding of the last flag byte
+	if (w.bits+7)/8 != (len(p.Flags)+7)/8 || w.hashes != len(p.Hashes) {
+		return [32]byte{}, nil, nil, fmt.Errorf("%w: unused flags or hashes", ErrInvalidPartialMerkleTree)
+	}
+	return root, w.matches, w.indexes, nil
+}
+
+// Serialize encodes the tree in the merkleblock wire format
+func (p *PartialMerkleTree) Serialize() []byte {
+	buf := binary.LittleEndian.AppendUint32(nil, p.Total)
+	buf = appendCompactSize(buf, uint64(len(p.Hashes)))
+	for _, h := range p.Hashes {
+		buf = append(buf, h[:]...)
+	}
+	flags := make([]byte, (len(p.Flags)+7)/8)
+	for i, f := range p.Flags {
+		if f {
+			flags[i/8] |= 1 << (i % 8)
+		}
+	}
+	buf = appendCompactSize(buf, uint64(len(flags)))
+	return append(buf, flags...)
+}
+
+// ParsePartialMerkleTree decodes the merkleblock wire format; raw must contain exactly one tree
+func ParsePartialMerkleTree(raw []byte) (*PartialMerkleTree, error) {
+	// Step 1: Transaction count
+	if len(raw) < 4 {
+		return nil, fmt.Errorf("%w: truncated", ErrInvalidPartialMerkleTree)
+	}
+	p := &PartialMerkleTree{Total: binary.LittleEndian.Uint32(raw)}
+	raw = raw[4:]
+
+	// Step 2: Hashes
+	n, size, err := readCompactSize(raw)
+	if err != nil || n > uint64(len(raw)-size)/32 {
+		return nil, fmt.Errorf("%w: truncated hash list", ErrInvalidPartialMerkleTree)
+	}
+	raw = raw[size:]
+	p.Hashes = make([][32]byte, n)
+	for i := range p.Hashes {
+		p.Hashes[i] = [32]byte(raw[32*i:])
+	}
+	raw = raw[32*n:]
+
+	// Step 3: Flag bytes, unpacked LSB first
+	n, size, err = readCompactSize(raw)
+	if err != nil || n != uint64(len(raw)-size) {
+		return nil, fmt.Errorf("%w: flag bytes do not match the remaining data", ErrInvalidPartialMerkleTree)
+	}
+	p.Flags = make([]bool, 8*n)
+	for i := range p.Flags {
+		p.Flags[i] = raw[size+i/8]>>(i%8)&1 == 1
+	}
+	return p, nil
+}
+
+// height returns the number of levels above the leaves
+func (p *PartialMerkleTree) height() int {
+	h := 0
+	for p.width(h) > 1 {
+		h++
+	}
+	return h
+}
+
+// width returns the number of nodes at a height
+func (p *PartialMerkleTree) width(height int) int {
+	return int((uint64(p.Total) + 1<<height - 1) >> height)
+}
+
+// build records the flag and, where the walk stops, the hash of the node at (height, pos)
+func (p *PartialMerkleTree) build(height, pos int, txids [][32]byte, match []bool) {
+	// Step 1: Does any leaf below this node match?
+	parentOfMatch := false
+	for i := pos << height; i < (pos+1)<<height && i < len(txids); i++ {
+		parentOfMatch = parentOfMatch || match[i]
+	}
+	p.Flags = append(p.Flags, parentOfMatch)
+
+	// Step 2: Stop at leaves and unmatched subtrees, otherwise descend
+	if height == 0 || !parentOfMatch {
+		p.Hashes = append(p.Hashes, p.nodeHash(height, pos, txids))
+		return
+	}
+	p.build(height-1, pos*2, txids, match)
+	if pos*2+1 < p.width(height-1) {
+		p.build(height-1, pos*2+1, txids, match)
+	}
+}
+
+// nodeHash computes the hash of the node at (height, pos) from all txids
+func (p *PartialMerkleTree) nodeHash(height, pos int, txids [][32]byte) [32]byte {
+	if height == 0 {
+		return txids[pos]
+	}
+	left := p.nodeHash(height-1, pos*2, txids)
+	right := left
+	if pos*2+1 < p.width(height-1) {
+		right = p.nodeHash(height-1, pos*2+1, txids)
+	}
+	return SHA256D(Concat(left[:], right[:]))
+}
+
+// partialMerkleWalk is the state of ExtractMatches
+type partialMerkleWalk struct {
+	tree    *PartialMerkleTree
+	bits    int // Flags consumed
+	hashes  int // Hashes consumed
+	matches [][32]byte
+	indexes []int
+}
+
+// extract replays the walk for the node at (height, pos) and returns its hash
+func (w *partialMerkleWalk) extract(height, pos int) ([32]byte, error) {
+	// Step 1: Read the flag of this node
+	if w.bits >= len(w.tree.Flags) {
+		return [32]byte{}, fmt.Errorf("%w: ran out of flags", ErrInvalidPartialMerkleTree)
+	}
+	flag := w.tree.Flags[w.bits]
+	w.bits++
+
+	// Step 2: Leaves and unmatched subtrees take the next hash
+	if height == 0 || !flag {
+		if w.hashes >= len(w.tree.Hashes) {
+			return [32]byte{}, fmt.Errorf("%w: ran out of hashes", ErrInvalidPartialMerkleTree)
+		}
+		h := w.tree.Hashes[w.hashes]
+		w.hashes++
+		if height == 0 && flag {
+			w.matches = append(w.matches, h)
+			w.indexes = append(w.indexes, pos)
+		}
+		return h, nil
+	}
+
+	// Step 3: Otherwise combine the children; a real right child equal to the left is a mutation
+	left, err := w.extract(height-1, pos*2)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	right := left
+	if pos*2+1 < w.tree.width(height-1) {
+		if right, err = w.extract(height-1, pos*2+1); err != nil {
+			return [32]byte{}, err
+		}
+		if right == left {
+			return [32]byte{}, ErrMerkleMutation
+		}
+	}
+	return SHA256D(Concat(left[:], right[:])), nil
+}
+
+// appendCompactSize appends Bitcoin's variable-length integer encoding
+func appendCompactSize(buf []byte, n uint64) []byte {
+	switch {
+	case n < 0xfd:
+		return append(buf, byte(n))
+	case n <= 0xffff:
+		return binary.LittleEndian.AppendUint16(append(buf, 0xfd), uint16(n))
+	case n <= 0xffffffff:
+		return binary.LittleEndian.AppendUint32(append(buf, 0xfe), uint32(n))
+	default:
+		return binary.LittleEndian.AppendUint64(append(buf, 0xff), n)
+	}
+}
+
+// readCompactSize decodes a variable-length integer and returns it with its encoded size
+func readCompactSize(b []byte) (uint64, int, error) {
+	if len(b) == 0 {
+		return 0, 0, errors.New("truncated compact size")
+	}
+	size := 1
+	switch b[0] {
+	case 0xfd:
+		size = 3
+	case 0xfe:
+		size = 5
+	case 0xff:
+		size = 9
+	}
+	if len(b) < size {
+		return 0, 0, errors.New("truncated compact size")
+	}
+	switch size {
+	case 3:
+		return uint64(binary.LittleEndian.Uint16(b[1:])), size, nil
+	case 5:
+		return uint64(binary.LittleEndian.Uint32(b[1:])), size, nil
+	case 9:
+		return binary.LittleEndian.Uint64(b[1:]), size, nil
+	}
+	return uint64(b[0]), size, nil
+}
@@ -0,0 +1,149 @@
+package hash
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPartialMerkleTree(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int
+		matches []int
+	}{
+		{"single tx matched", 1, []int{0}},
+		{"single tx unmatched", 1, nil},
+		{"first of many", 7, []int{0}},
+		{"last odd", 7, []int{6}},
+		{"several", 13, []int{1, 4, 11, 12}},
+		{"all", 9, []int{0, 1, 2, 3, 4, 5, 6, 7, 8}},
+		{"none", 16, nil},
+		{"large", 1000, []int{0, 511, 512, 999}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txids := testLeaves(tt.total)
+			match := make([]bool, tt.total)
+			var want [][32]byte
+			for _, i := range tt.matches {
+				match[i] = true
+				want = append(want, txids[i])
+			}
+
+			pmt, err := NewPartialMerkleTree(txids, match)
+			if err != nil {
+				t.Fatalf("NewPartialMerkleTree failed: %v", err)
+			}
+
+			// Round trip through the wire format
+			parsed, err := ParsePartialMerkleTree(pmt.Serialize())
+			if err != nil {
+				t.Fatalf("ParsePartialMerkleTree failed: %v", err)
+			}
+			if !bytes.Equal(parsed.Serialize(), pmt.Serialize()) {
+				t.Error("Expected serialization to round trip")
+			}
+
+			root, got, indexes, err := parsed.ExtractMatches()
+			if err != nil {
+				t.Fatalf("ExtractMatches failed: %v", err)
+			}
+			if root != MerkleRoot(txids) {
+				t.Error("Expected the root of the full tree")
+			}
+			if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(indexes, append([]int(nil), tt.matches...)) {
+				t.Errorf("Expected matches at %v, got %v", tt.matches, indexes)
+			}
+
+			// Proofs stay small: hashes grow with log(total) per match, not with total
+			if len(pmt.Hashes) > (len(tt.matches)+1)*11 {
+				t.Errorf("Expected a compact proof, got %d hashes", len(pmt.Hashes))
+			}
+		})
+	}
+}
+
+func TestPartialMerkleTreeInvalid(t *testing.T) {
+	txids := testLeaves(5)
+	pmt, _ := NewPartialMerkleTree(txids, []bool{false, false, true, false, false})
+
+	tests := []struct {
+		name   string
+		mutate func(p *PartialMerkleTree)
+	}{
+		{"zero transactions", func(p *PartialMerkleTree) { p.Total = 0 }},
+		{"too many transactions", func(p *PartialMerkleTree) { p.Total = maxPartialMerkleTransactions + 1 }},
+		{"missing hash", func(p *PartialMerkleTree) { p.Hashes = p.Hashes[:len(p.Hashes)-1] }},
+		{"extra hash", func(p *PartialMerkleTree) { p.Hashes = append(p.Hashes, [32]byte{}) }},
+		{"missing flags", func(p *PartialMerkleTree) { p.Flags = p.Flags[:2] }},
+		{"extra flag byte", func(p *PartialMerkleTree) { p.Flags = append(p.Flags, make([]bool, 8)...) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &PartialMerkleTree{
+				Total:  pmt.Total,
+				Hashes: append([][32]byte(nil), pmt.Hashes...),
+				Flags:  append([]bool(nil), pmt.Flags...),
+			}
+			tt.mutate(p)
+			if _, _, _, err := p.ExtractMatches(); !errors.Is(err, ErrInvalidPartialMerkleTree) {
+				t.Errorf("Expected ErrInvalidPartialMerkleTree, got %v", err)
+			}
+		})
+	}
+
+	// A tampered hash still parses but gives a different root
+	tampered := &PartialMerkleTree{Total: pmt.Total, Hashes: append([][32]byte(nil), pmt.Hashes...), Flags: pmt.Flags}
+	tampered.Hashes[0][0] ^= 1
+	if root, _, _, err := tampered.ExtractMatches(); err != nil || root == MerkleRoot(txids) {
+		t.Errorf("Expected a different root, got err %v", err)
+	}
+}
+
+func TestPartialMerkleTreeMutation(t *testing.T) {
+	// Reveal both children of a pair with the same hash
+	a := SHA256([]byte("a"))
+	pmt, _ := NewPartialMerkleTree([][32]byte{a, a}, []bool{true, true})
+	if _, _, _, err := pmt.ExtractMatches(); !errors.Is(err, ErrMerkleMutation) {
+		t.Errorf("Expected ErrMerkleMutation, got %v", err)
+	}
+}
+
+func TestParsePartialMerkleTreeErrors(t *testing.T) {
+	pmt, _ := NewPartialMerkleTree(testLeaves(3), []bool{true, false, false})
+	raw := pmt.Serialize()
+
+	tests := []struct {
+		name string
+		raw  []byte
+	}{
+		{"empty", nil},
+		{"only total", raw[:4]},
+		{"truncated hashes", raw[:40]},
+		{"truncated flags", raw[:len(raw)-1]},
+		{"trailing data", append(append([]byte(nil), raw...), 0x00)},
+		{"hash count overflow", append(append([]byte(nil), raw[:4]...), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePartialMerkleTree(tt.raw); !errors.Is(err, ErrInvalidPartialMerkleTree) {
+				t.Errorf("Expected ErrInvalidPartialMerkleTree, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCompactSize(t *testing.T) {
+	for _, n := range []uint64{0, 0xfc, 0xfd, 0xffff, 0x10000, 0xffffffff, 0x100000000} {
+		enc := appendCompactSize(nil, n)
+		got, size, err := readCompactSize(enc)
+		if err != nil || got != n || size != len(enc) {
+			t.Errorf("n=%d: Expected round trip, got %d (%d bytes), %v", n, got, size, err)
+		}
+		if _, _, err := readCompactSize(enc[:len(enc)-1]); len(enc) > 1 && err == nil {
+			t.Errorf("n=%d: Expected error for truncated encoding", n)
+		}
+	}
+}
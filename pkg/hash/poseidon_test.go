@@ -0,0 +1,87 @@
+package hash
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// TestPoseidonPermute uses the test vectors of the Poseidon reference implementation
+// (poseidonperm_x5_254_3 and poseidonperm_x5_255_3: the permutation of [0, 1, 2])
+func TestPoseidonPermute(t *testing.T) {
+	tests := []struct {
+		name     string
+		poseidon *Poseidon
+		expected []string
+	}{
+		{
+			"bn254", PoseidonBN254(),
+			[]string{
+				"115cc0f5e7d690413df64c6b9662e9cf2a3617f2743245519e19607a4417189a",
+				"fca49b798923ab0239de1c9e7a4a9a2210312b6a2f616d18b5a87f9b628ae29",
+				"e7ae82e40091e63cbd4f16a6d16310b3729d4b6e138fcf54110e2867045a30c",
+			},
+		},
+		{
+			"bls12-381", PoseidonBLS12381(),
+			[]string{
+				"28ce19420fc246a05553ad1e8c98f5c9d67166be2c18e9e4cb4b4e317dd2a78a",
+				"51f3e312c95343a896cfd8945ea82ba956c1118ce9b9859b6ea56637b4b1ddc4",
+				"3b2b69139b235626a0bfb56c9527ae66a7bf486ad8c11c14d1da0c69bbe0f79a",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2)}
+			out, err := tt.poseidon.Permute(in)
+			if err != nil {
+				t.Fatalf("Permute failed: %v", err)
+			}
+			for i, x := range out {
+				if x.Text(16) != tt.expected[i] {
+					t.Errorf("Permute()[%d] = %s, expected %s", i, x.Text(16), tt.expected[i])
+				}
+			}
+			if in[1].Int64() != 1 {
+				t.Error("Expected Permute not to modify its input")
+			}
+		})
+	}
+}
+
+// TestPoseidonHash checks circomlib's poseidon([1, 2]) and the [32]byte form used by trees
+func TestPoseidonHash(t *testing.T) {
+	p := PoseidonBN254()
+	h, err := p.Hash(big.NewInt(1), big.NewInt(2))
+	if err != nil {
+		t.Fatalf("Hash failed: %v", err)
+	}
+	if h.String() != "7853200120776062878684798364095072458815029376092732009249414926327459813530" {
+		t.Errorf("Hash(1, 2) = %s", h)
+	}
+
+	var one, two [32]byte
+	one[31], two[31] = 1, 2
+	got := p.Compress(one, two)
+	if fmt.Sprintf("%x", got) != fmt.Sprintf("%064x", h) {
+		t.Errorf("Compress() = %x, expected %064x", got, h)
+	}
+	if p.Compress(two, one) == got {
+		t.Error("Expected the order of the inputs to matter")
+	}
+
+	// Compress reduces mod p; Hash rejects non-canonical values
+	var wrapped [32]byte
+	new(big.Int).Add(p.Modulus(), big.NewInt(1)).FillBytes(wrapped[:])
+	if p.Compress(wrapped, two) != got {
+		t.Error("Expected Compress to reduce its inputs")
+	}
+	if _, err := p.Hash(p.Modulus(), big.NewInt(2)); !errors.Is(err, ErrFieldElement) {
+		t.Errorf("Expected ErrFieldElement, got %v", err)
+	}
+	if _, err := p.Permute([]*big.Int{big.NewInt(1)}); err == nil {
+		t.Error("Expected an error for a state of the wrong size")
+	}
+}
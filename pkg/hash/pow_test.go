@@ -0,0 +1,121 @@
+package hash
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestCompactToTarget(t *testing.T) {
+	tests := []struct {
+		bits     uint32
+		expected string // hex of the target, empty for an error
+	}{
+		{0x1d00ffff, "ffff0000000000000000000000000000000000000000000000000000"},
+		{0x207fffff, "7fffff0000000000000000000000000000000000000000000000000000000000"},
+		{0x1b0404cb, "404cb000000000000000000000000000000000000000000000000"},
+		{0x05009234, "92340000"},
+		{0x01123456, "12"},
+		{0x02123456, "1234"},
+		{0x03123456, "123456"},
+		{0x04123456, "12345600"},
+		{0x01003456, ""}, // Shifted out to zero
+		{0x00000000, ""}, // Zero
+		{0x04923456, ""}, // Negative
+		{0x21010000, ""}, // Overflows 256 bits
+		{0xff123456, ""},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%08x", tt.bits), func(t *testing.T) {
+			target, err := CompactToTarget(tt.bits)
+			if tt.expected == "" {
+				if !errors.Is(err, ErrInvalidCompact) {
+					t.Errorf("Expected ErrInvalidCompact, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CompactToTarget failed: %v", err)
+			}
+			if target.Text(16) != tt.expected {
+				t.Errorf("CompactToTarget() = %s, expected %s", target.Text(16), tt.expected)
+			}
+
+			// Canonical encodings survive the round trip
+			if got := TargetToCompact(target); got != tt.bits && tt.bits>>24 > 3 {
+				t.Errorf("TargetToCompact() = %08x, expected %08x", got, tt.bits)
+			}
+		})
+	}
+
+	// A mantissa with the top bit set moves a byte into the exponent
+	if got := TargetToCompact(big.NewInt(0x80)); got != 0x02008000 {
+		t.Errorf("TargetToCompact(0x80) = %08x, expected 02008000", got)
+	}
+}
+
+func TestMineHeader(t *testing.T) {
+	genesis, _ := hex.DecodeString("0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c")
+	const genesisNonce = 2083236893
+
+	t.Run("replays the genesis block", func(t *testing.T) {
+		header := [80]byte(genesis)
+		binary.LittleEndian.PutUint32(header[76:], genesisNonce-1000)
+
+		var reports []MineProgress
+		solved, err := MineHeader(context.Background(), header, 0x1d00ffff,
+			WithMineProgress(250, func(p MineProgress) { reports = append(reports, p) }))
+		if err != nil {
+			t.Fatalf("MineHeader failed: %v", err)
+		}
+		if solved != [80]byte(genesis) {
+			t.Errorf("Expected nonce %d, got %d", genesisNonce, binary.LittleEndian.Uint32(solved[76:]))
+		}
+		if len(reports) != 4 || reports[3].Hashes != 1000 || reports[3].Nonce != genesisNonce {
+			t.Errorf("Expected 4 reports up to nonce %d, got %+v", genesisNonce, reports)
+		}
+
+		target, _ := CompactToTarget(0x1d00ffff)
+		if !HashMeetsTarget(SHA256D(solved[:]), target) {
+			t.Error("Expected the genesis hash to meet its target")
+		}
+	})
+
+	t.Run("easy target", func(t *testing.T) {
+		header := [80]byte(genesis)
+		binary.LittleEndian.PutUint32(header[76:], 0)
+		solved, err := MineHeader(context.Background(), header, 0x207fffff)
+		if err != nil {
+			t.Fatalf("MineHeader failed: %v", err)
+		}
+		target, _ := CompactToTarget(0x207fffff)
+		if !HashMeetsTarget(SHA256D(solved[:]), target) {
+			t.Error("Expected the mined hash to meet the target")
+		}
+		if [76]byte(solved[:76]) != [76]byte(header[:76]) {
+			t.Error("Expected only the nonce to change")
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		// A target of 1 is never met in practice
+		if _, err := MineHeader(ctx, [80]byte(genesis), 0x03000001); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		if _, err := MineHeader(context.Background(), [80]byte(genesis), 0x04923456); !errors.Is(err, ErrInvalidCompact) {
+			t.Errorf("Expected ErrInvalidCompact, got %v", err)
+		}
+		if _, err := MineHeader(context.Background(), [80]byte(genesis), 0x207fffff, WithMineProgress(0, func(MineProgress) {})); err == nil {
+			t.Error("Expected an error for a zero progress interval")
+		}
+	})
+}
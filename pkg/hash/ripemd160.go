@@ -0,0 +1,190 @@
+package hash
+
+import (
+	"encoding/binary"
+	stdhash "hash"
+	"math/bits"
+)
+
+// RIPEMD-160
+//
+// RIPEMD-160 runs two independent MD4-style lines of 80 steps over each
+// 64-byte block, with different word orders, rotations and constants, and
+// mixes both into the 160-bit state at the end. Bitcoin uses it only inside
+// Hash160, where the shorter output keeps addresses compact. The
+// golang.org/x/crypto version is deprecated, so the package carries its own.
+
+// RIPEMD160Size is the size of a RIPEMD-160 digest in bytes
+const RIPEMD160Size = 20
+
+// ripemd160BlockSize is the block size of RIPEMD-160 in bytes
+const ripemd160BlockSize = 64
+
+// ripemd160Init is the initial chaining value
+var ripemd160Init = [5]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476, 0xc3d2e1f0}
+
+// Message word order of the left and right lines
+var (
+	ripemdRL = [80]uint8{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		7, 4, 13, 1, 10, 6, 15, 3, 12, 0, 9, 5, 2, 14, 11, 8,
+		3, 10, 14, 4, 9, 15, 8, 1, 2, 7, 0, 6, 13, 11, 5, 12,
+		1, 9, 11, 10, 0, 8, 12, 4, 13, 3, 7, 15, 14, 5, 6, 2,
+		4, 0, 5, 9, 7, 12, 2, 10, 14, 1, 3, 8, 11, 6, 15, 13,
+	}
+	ripemdRR = [80]uint8{
+		5, 14, 7, 0, 9, 2, 11, 4, 13, 6, 15, 8, 1, 10, 3, 12,
+		6, 11, 3, 7, 0, 13, 5, 10, 14, 15, 8, 12, 4, 9, 1, 2,
+		15, 5, 1, 3, 7, 14, 6, 9, 11, 8, 12, 2, 10, 0, 4, 13,
+		8, 6, 4, 1, 3, 11, 15, 0, 5, 12, 2, 13, 9, 7, 10, 14,
+		12, 15, 10, 4, 1, 5, 8, 7, 6, 2, 13, 14, 0, 3, 9, 11,
+	}
+)
+
+// Rotation amounts of the left and right lines
+var (
+	ripemdSL = [80]uint8{
+		11, 14, 15, 12, 5, 8, 7, 9, 11, 13, 14, 15, 6, 7, 9, 8,
+		7, 6, 8, 13, 11, 9, 7, 15, 7, 12, 15, 9, 11, 7, 13, 12,
+		11, 13, 6, 7, 14, 9, 13, 15, 14, 8, 13, 6, 5, 12, 7, 5,
+		11, 12, 14, 15, 14, 15, 9, 8, 9, 14, 5, 6, 8, 6, 5, 12,
+		9, 15, 5, 11, 6, 8, 13, 12, 5, 12, 13, 14, 11, 8, 5, 6,
+	}
+	ripemdSR = [80]uint8{
+		8, 9, 9, 11, 13, 15, 15, 5, 7, 7, 8, 11, 14, 14, 12, 6,
+		9, 13, 15, 7, 12, 8, 9, 11, 7, 7, 12, 7, 6, 15, 13, 11,
+		9, 7, 15, 11, 8, 6, 6, 14, 12, 13, 5, 14, 13, 13, 7, 5,
+		15, 5, 8, 11, 14, 14, 6, 14, 6, 9, 12, 9, 12, 5, 15, 8,
+		8, 5, 12, 9, 12, 5, 14, 6, 8, 13, 6, 5, 15, 13, 11, 11,
+	}
+)
+
+// Round constants of the left and right lines, one per group of 16 steps
+var (
+	ripemdKL = [5]uint32{0x00000000, 0x5a827999, 0x6ed9eba1, 0x8f1bbcdc, 0xa953fd4e}
+	ripemdKR = [5]uint32{0x50a28be6, 0x5c4dd124, 0x6d703ef3, 0x7a6d76e9, 0x00000000}
+)
+
+// ripemd160Digest is a streaming RIPEMD-160 state
+type ripemd160Digest struct {
+	h   [5]uint32
+	buf [ripemd160BlockSize]byte
+	nx  int    // Bytes buffered in buf
+	len uint64 // Total bytes written
+}
+
+// NewRIPEMD160 returns a streaming RIPEMD-160 hash
+//
+// Example:
+//
+//	h := NewRIPEMD160()
+//	h.Write(sha[:])
+//	digest := h.Sum(nil) // 20 bytes
+func NewRIPEMD160() stdhash.Hash {
+	d := &ripemd160Digest{}
+	d.Reset()
+	return d
+}
+
+// Reset restores the initial state
+func (d *ripemd160Digest) Reset() {
+	d.h = ripemd160Init
+	d.nx = 0
+	d.len = 0
+}
+
+// Size returns 20
+func (d *ripemd160Digest) Size() int { return RIPEMD160Size }
+
+// BlockSize returns 64
+func (d *ripemd160Digest) BlockSize() int { return ripemd160BlockSize }
+
+// Write absorbs p, compressing every full block; it never returns an error
+func (d *ripemd160Digest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.len += uint64(n)
+
+	// Step 1: Top up a partially filled buffer
+	if d.nx > 0 {
+		copied := copy(d.buf[d.nx:], p)
+		d.nx += copied
+		p = p[copied:]
+		if d.nx < ripemd160BlockSize {
+			return n, nil
+		}
+		d.block(d.buf[:])
+		d.nx = 0
+	}
+
+	// Step 2: Compress full blocks straight from p, then buffer the rest
+	for len(p) >= ripemd160BlockSize {
+		d.block(p[:ripemd160BlockSize])
+		p = p[ripemd160BlockSize:]
+	}
+	d.nx = copy(d.buf[:], p)
+	return n, nil
+}
+
+// Sum appends the digest to b without changing the state
+func (d *ripemd160Digest) Sum(b []byte) []byte {
+	// Step 1: Pad a copy: 0x80, zeros to 56 mod 64, then the bit length (little-endian)
+	c := *d
+	var pad [ripemd160BlockSize + 8]byte
+	pad[0] = 0x80
+	padLen := 56 - int(c.len%64)
+	if padLen < 1 {
+		padLen += 64
+	}
+	binary.LittleEndian.PutUint64(pad[padLen:], c.len*8)
+	_, _ = c.Write(pad[:padLen+8])
+
+	// Step 2: Serialize the state little-endian
+	for _, v := range c.h {
+		b = binary.LittleEndian.AppendUint32(b, v)
+	}
+	return b
+}
+
+// block compresses one 64-byte block into the state
+func (d *ripemd160Digest) block(p []byte) {
+	var x [16]uint32
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(p[4*i:])
+	}
+
+	// Step 1: Run the two lines side by side
+	al, bl, cl, dl, el := d.h[0], d.h[1], d.h[2], d.h[3], d.h[4]
+	ar, br, cr, dr, er := al, bl, cl, dl, el
+	for j := 0; j < 80; j++ {
+		round := j / 16
+		t := bits.RotateLeft32(al+ripemdF(j, bl, cl, dl)+x[ripemdRL[j]]+ripemdKL[round], int(ripemdSL[j])) + el
+		al, el, dl, cl, bl = el, dl, bits.RotateLeft32(cl, 10), bl, t
+
+		t = bits.RotateLeft32(ar+ripemdF(79-j, br, cr, dr)+x[ripemdRR[j]]+ripemdKR[round], int(ripemdSR[j])) + er
+		ar, er, dr, cr, br = er, dr, bits.RotateLeft32(cr, 10), br, t
+	}
+
+	// Step 2: Combine both lines with the previous state, rotating the word positions
+	t := d.h[1] + cl + dr
+	d.h[1] = d.h[2] + dl + er
+	d.h[2] = d.h[3] + el + ar
+	d.h[3] = d.h[4] + al + br
+	d.h[4] = d.h[0] + bl + cr
+	d.h[0] = t
+}
+
+// ripemdF is the boolean function of step j; the right line runs them in reverse order
+func ripemdF(j int, x, y, z uint32) uint32 {
+	switch j / 16 {
+	case 0:
+		return x ^ y ^ z
+	case 1:
+		return (x & y) | (^x & z)
+	case 2:
+		return (x | ^y) ^ z
+	case 3:
+		return (x & z) | (y & ^z)
+	default:
+		return x ^ (y | ^z)
+	}
+}
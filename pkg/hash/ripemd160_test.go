@@ -0,0 +1,67 @@
+package hash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// TestRIPEMD160Vectors checks the test vectors of the RIPEMD-160 specification
+func TestRIPEMD160Vectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "", "9c1185a5c5e9fc54612808977ee8f548b2258d31"},
+		{"a", "a", "0bdc9d2d256b3ee9daae347be6f4dc835a467ffe"},
+		{"abc", "abc", "8eb208f7e05d987a9b044a8e98c6b087f15a0bfc"},
+		{"message digest", "message digest", "5d0689ef49d2fae572b881b123a85ffa21595f36"},
+		{"alphabet", "abcdefghijklmnopqrstuvwxyz", "f71c27109c692c1b56bbdceb5b9d2865b3708dbc"},
+		{"448 bits", "abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq", "12a053384a9c0c88e405a06c27dcf49ada62eb2b"},
+		{"alphanumeric", "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789", "b0e20b6e3116640286ed3a87a5713079b21f5189"},
+		{"8 times 1234567890", strings.Repeat("1234567890", 8), "9b752e45573d4b39f4dbd3323cab82bf63326bfb"},
+		{"million a", strings.Repeat("a", 1000000), "52783243c1697bdbe16d37f97f68f08325dc1528"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RIPEMD160([]byte(tt.input))
+			if hex.EncodeToString(got[:]) != tt.expected {
+				t.Errorf("Expected %s, got %x", tt.expected, got)
+			}
+
+			// Streaming in uneven pieces gives the same digest
+			h := NewRIPEMD160()
+			for rest := []byte(tt.input); len(rest) > 0; {
+				n := min(len(rest), 13)
+				h.Write(rest[:n])
+				rest = rest[n:]
+			}
+			if !bytes.Equal(h.Sum(nil), got[:]) {
+				t.Error("Expected streaming to match the one-shot digest")
+			}
+		})
+	}
+}
+
+func TestRIPEMD160State(t *testing.T) {
+	h := NewRIPEMD160()
+	h.Write([]byte("ab"))
+	if !bytes.Equal(h.Sum(nil), h.Sum(nil)) {
+		t.Error("Expected Sum not to change the state")
+	}
+	h.Write([]byte("c"))
+	want := RIPEMD160([]byte("abc"))
+	if got := h.Sum([]byte{0x00}); !bytes.Equal(got[1:], want[:]) || got[0] != 0x00 {
+		t.Error("Expected Sum to append the digest of everything written")
+	}
+	h.Reset()
+	empty := RIPEMD160(nil)
+	if !bytes.Equal(h.Sum(nil), empty[:]) {
+		t.Error("Expected Reset to restore the initial state")
+	}
+	if h.Size() != RIPEMD160Size || h.BlockSize() != 64 {
+		t.Error("Unexpected Size or BlockSize")
+	}
+}
@@ -0,0 +1,83 @@
+package hash
+
+import (
+	"crypto/sha256"
+	stdhash "hash"
+)
+
+// Streaming hashes
+//
+// SHA256D and Hash160 take the whole input as one slice. New256D and
+// NewHash160 return a standard hash.Hash instead, so large inputs can be fed
+// in pieces (io.Copy from a file, a transaction serialized field by field)
+// without buffering them. Both are two-stage hashes: only the inner SHA256
+// sees the stream, and the outer hash runs once over its 32-byte digest in Sum.
+
+// sha256dHash computes SHA256(SHA256(stream))
+type sha256dHash struct {
+	inner stdhash.Hash
+}
+
+// hash160Hash computes RIPEMD160(SHA256(stream))
+type hash160Hash struct {
+	inner stdhash.Hash
+}
+
+// New256D returns a streaming double-SHA256 hash; Sum gives the same result as SHA256D
+//
+// Example:
+//
+//	h := New256D()
+//	io.Copy(h, file)
+//	digest := h.Sum(nil) // 32 bytes
+func New256D() stdhash.Hash {
+	return &sha256dHash{inner: sha256.New()}
+}
+
+// NewHash160 returns a streaming RIPEMD160(SHA256) hash; Sum gives the same result as Hash160
+//
+// Example:
+//
+//	h := NewHash160()
+//	h.Write(pubKey)
+//	pkh := h.Sum(nil) // 20 bytes
+func NewHash160() stdhash.Hash {
+	return &hash160Hash{inner: sha256.New()}
+}
+
+// Write adds data to the inner SHA256; it never returns an error
+func (h *sha256dHash) Write(p []byte) (int, error) { return h.inner.Write(p) }
+
+// Sum appends SHA256(SHA256(data so far)) to b without changing the state
+func (h *sha256dHash) Sum(b []byte) []byte {
+	outer := sha256.Sum256(h.inner.Sum(nil))
+	return append(b, outer[:]...)
+}
+
+// Reset clears the written data
+func (h *sha256dHash) Reset() { h.inner.Reset() }
+
+// Size returns 32
+func (h *sha256dHash) Size() int { return sha256.Size }
+
+// BlockSize returns the SHA256 block size the input is processed in
+func (h *sha256dHash) BlockSize() int { return sha256.BlockSize }
+
+// Write adds data to the inner SHA256; it never returns an error
+func (h *hash160Hash) Write(p []byte) (int, error) { return h.inner.Write(p) }
+
+// Sum appends RIPEMD160(SHA256(data so far)) to b without changing the state
+func (h *hash160Hash) Sum(b []byte) []byte {
+	outer := NewRIPEMD160()
+	_, _ = outer.Write(h.inner.Sum(nil))
+	return outer.Sum(b)
+}
+
+// Reset clears the written data
+func (h *hash160Hash) Reset() { h.inner.Reset() }
+
+// Size returns 20
+func (h *hash160Hash) Size() int { return RIPEMD160Size }
+
+// BlockSize returns the SHA256 block size the input is processed in
+func (h *hash160Hash) BlockSize() int { return sha256.BlockSize }
@@ -0,0 +1,68 @@
+package hash
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestStreamingHashes(t *testing.T) {
+	data := bytes.Repeat([]byte("streamed transaction data "), 1000)
+	want256D, want160 := SHA256D(data), Hash160(data)
+
+	tests := []struct {
+		name  string
+		chunk int
+	}{
+		{"one write", len(data)},
+		{"byte by byte", 1},
+		{"odd chunks", 37},
+		{"block sized", 64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, h := New256D(), NewHash160()
+			for i := 0; i < len(data); i += tt.chunk {
+				end := min(i+tt.chunk, len(data))
+				d.Write(data[i:end])
+				h.Write(data[i:end])
+			}
+			if got := d.Sum(nil); !bytes.Equal(got, want256D[:]) {
+				t.Errorf("New256D: Expected %x, got %x", want256D, got)
+			}
+			if got := h.Sum(nil); !bytes.Equal(got, want160[:]) {
+				t.Errorf("NewHash160: Expected %x, got %x", want160, got)
+			}
+		})
+	}
+}
+
+func TestStreamingHashState(t *testing.T) {
+	h := New256D()
+	if _, err := io.Copy(h, bytes.NewReader([]byte("abc"))); err != nil {
+		t.Fatalf("io.Copy failed: %v", err)
+	}
+
+	// Sum appends and leaves the state alone
+	prefix := []byte{0xff}
+	first := h.Sum(prefix)
+	if !bytes.Equal(first[:1], prefix) || !bytes.Equal(first[1:], h.Sum(nil)) {
+		t.Error("Expected Sum to append without changing the state")
+	}
+	h.Write([]byte("def"))
+	want := SHA256D([]byte("abcdef"))
+	if !bytes.Equal(h.Sum(nil), want[:]) {
+		t.Error("Expected writes after Sum to continue the stream")
+	}
+
+	// Reset starts over
+	h.Reset()
+	empty := SHA256D(nil)
+	if !bytes.Equal(h.Sum(nil), empty[:]) {
+		t.Error("Expected Reset to clear the state")
+	}
+
+	if New256D().Size() != 32 || NewHash160().Size() != 20 || New256D().BlockSize() != 64 || NewHash160().BlockSize() != 64 {
+		t.Error("Unexpected Size or BlockSize")
+	}
+}
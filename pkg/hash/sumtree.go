@@ -0,0 +1,163 @@
+package hash
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// Merkle sum trees
+//
+// A Merkle sum tree carries an amount next to every hash: a leaf commits to an
+// account and its balance, and a parent commits to both children and their sums:
+//
+//	leaf   = H("SumTree/leaf", value || data),                        sum = value
+//	parent = H("SumTree/node", hL || sumL || hR || sumR),             sum = sumL + sumR
+//
+// A custodian publishes the root (hash and total) as its liabilities, and each
+// user checks a proof that their balance is included. Committing to both child
+// sums rather than only their total closes the flaw in the original Maxwell
+// construction, where a custodian could shift value between siblings, and
+// unsigned sums with overflow checks rule out negative balances. An odd node is
+// paired with an empty node of sum 0; duplicating it, as Bitcoin's Merkle tree
+// does, would count its amount twice.
+
+// Merkle sum tree tags
+const (
+	sumLeafTag = "SumTree/leaf"
+	sumNodeTag = "SumTree/node"
+)
+
+// SumLeaf is an entry of a Merkle sum tree
+type SumLeaf struct {
+	Data  [32]byte // Commitment to the account, e.g. SHA256(userID || salt)
+	Value uint64   // Amount owed to the account
+}
+
+// SumNode is a node of a Merkle sum tree: a hash and the total of the leaves below it
+type SumNode struct {
+	Hash [32]byte
+	Sum  uint64
+}
+
+// SumProofStep is one sibling on the path from a leaf to the root
+type SumProofStep struct {
+	Sibling       SumNode
+	LeftIsSibling bool // True if the sibling is on the left
+}
+
+// MerkleSumTree is a Merkle tree whose nodes also carry value sums
+type MerkleSumTree struct {
+	levels [][]SumNode // levels[0] are the leaf nodes, the last level holds the root
+}
+
+// NewMerkleSumTree builds a sum tree, failing if the total overflows uint64
+//
+// Example:
+//
+//	tree, err := NewMerkleSumTree([]SumLeaf{
+//	  {Data: SHA256([]byte("alice:salt1")), Value: 1500},
+//	  {Data: SHA256([]byte("bob:salt2")), Value: 700},
+//	})
+//	root := tree.Root() // publish root.Hash and root.Sum (2200)
+func NewMerkleSumTree(leaves []SumLeaf) (*MerkleSumTree, error) {
+	// Step 1: Hash the leaves
+	level := make([]SumNode, len(leaves))
+	for i, leaf := range leaves {
+		level[i] = leaf.node()
+	}
+	t := &MerkleSumTree{levels: [][]SumNode{level}}
+
+	// Step 2: Combine pairs, padding odd levels with an empty node
+	for len(level) > 1 {
+		next := make([]SumNode, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			right := SumNode{}
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			parent, ok := sumParent(level[i], right)
+			if !ok {
+				return nil, fmt.Errorf("sum of leaves overflows uint64")
+			}
+			next = append(next, parent)
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t, nil
+}
+
+// Len returns the number of leaves
+func (t *MerkleSumTree) Len() int {
+	return len(t.levels[0])
+}
+
+// Root returns the root hash and the total of all leaves, or the zero node for an empty tree
+func (t *MerkleSumTree) Root() SumNode {
+	top := t.levels[len(t.levels)-1]
+	if len(top) == 0 {
+		return SumNode{}
+	}
+	return top[0]
+}
+
+// Prove returns the path from leaf i to the root
+func (t *MerkleSumTree) Prove(i int) ([]SumProofStep, error) {
+	if i < 0 || i >= t.Len() {
+		return nil, fmt.Errorf("leaf index %d out of range [0, %d)", i, t.Len())
+	}
+	steps := make([]SumProofStep, 0, len(t.levels)-1)
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := SumNode{}
+		if j := i ^ 1; j < len(level) {
+			sibling = level[j]
+		}
+		steps = append(steps, SumProofStep{Sibling: sibling, LeftIsSibling: i%2 == 1})
+		i /= 2
+	}
+	return steps, nil
+}
+
+// VerifySumProof checks that leaf is in the tree with the given root
+//
+// It recomputes hashes and sums together, so a proof that reaches root.Hash
+// also shows that root.Sum includes leaf.Value.
+//
+// Example:
+//
+//	steps, _ := tree.Prove(0)
+//	ok := VerifySumProof(SumLeaf{Data: myCommitment, Value: 1500}, steps, publishedRoot)
+func VerifySumProof(leaf SumLeaf, steps []SumProofStep, root SumNode) bool {
+	current := leaf.node()
+	for _, step := range steps {
+		var ok bool
+		if step.LeftIsSibling {
+			current, ok = sumParent(step.Sibling, current)
+		} else {
+			current, ok = sumParent(current, step.Sibling)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return current == root
+}
+
+// node hashes a leaf together with its value
+func (l SumLeaf) node() SumNode {
+	value := binary.LittleEndian.AppendUint64(nil, l.Value)
+	return SumNode{Hash: TaggedSHA256(sumLeafTag, value, l.Data[:]), Sum: l.Value}
+}
+
+// sumParent combines two nodes, reporting false if the sum overflows
+func sumParent(left, right SumNode) (SumNode, bool) {
+	sum, carry := bits.Add64(left.Sum, right.Sum, 0)
+	if carry != 0 {
+		return SumNode{}, false
+	}
+	h := TaggedSHA256(sumNodeTag,
+		left.Hash[:], binary.LittleEndian.AppendUint64(nil, left.Sum),
+		right.Hash[:], binary.LittleEndian.AppendUint64(nil, right.Sum))
+	return SumNode{Hash: h, Sum: sum}, true
+}
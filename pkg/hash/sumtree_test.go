@@ -0,0 +1,97 @@
+package hash
+
+import (
+	"math"
+	"testing"
+)
+
+// testSumLeaves returns n accounts with balances 1..n
+func testSumLeaves(n int) []SumLeaf {
+	leaves := make([]SumLeaf, n)
+	for i, data := range testLeaves(n) {
+		leaves[i] = SumLeaf{Data: data, Value: uint64(i + 1)}
+	}
+	return leaves
+}
+
+func TestMerkleSumTree(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7, 8, 13} {
+		leaves := testSumLeaves(n)
+		tree, err := NewMerkleSumTree(leaves)
+		if err != nil {
+			t.Fatalf("NewMerkleSumTree failed: %v", err)
+		}
+
+		// The root sum is the total of all balances
+		if want := uint64(n * (n + 1) / 2); tree.Root().Sum != want {
+			t.Errorf("n=%d: Expected total %d, got %d", n, want, tree.Root().Sum)
+		}
+
+		for i, leaf := range leaves {
+			steps, err := tree.Prove(i)
+			if err != nil {
+				t.Fatalf("Prove(%d) failed: %v", i, err)
+			}
+			if !VerifySumProof(leaf, steps, tree.Root()) {
+				t.Errorf("n=%d: proof for leaf %d does not verify", n, i)
+			}
+		}
+	}
+
+	if root := mustSumTree(t, nil).Root(); root != (SumNode{}) {
+		t.Errorf("Expected the zero node for an empty tree, got %+v", root)
+	}
+}
+
+func TestVerifySumProofRejects(t *testing.T) {
+	leaves := testSumLeaves(6)
+	tree := mustSumTree(t, leaves)
+	root := tree.Root()
+
+	tests := []struct {
+		name   string
+		leaf   SumLeaf
+		mutate func(steps []SumProofStep)
+		root   SumNode
+	}{
+		{"inflated balance", SumLeaf{Data: leaves[2].Data, Value: 100}, func([]SumProofStep) {}, root},
+		{"understated total", leaves[2], func([]SumProofStep) {}, SumNode{Hash: root.Hash, Sum: root.Sum - 1}},
+		// Shifting value between siblings keeps the total but changes the hash
+		{"shifted sibling sum", leaves[2], func(s []SumProofStep) { s[1].Sibling.Sum += 1 }, SumNode{Hash: root.Hash, Sum: root.Sum + 1}},
+		{"tampered sibling hash", leaves[2], func(s []SumProofStep) { s[0].Sibling.Hash[0] ^= 1 }, root},
+		{"overflowing sibling", leaves[2], func(s []SumProofStep) { s[0].Sibling.Sum = math.MaxUint64 }, root},
+		{"wrong side", leaves[2], func(s []SumProofStep) { s[0].LeftIsSibling = !s[0].LeftIsSibling }, root},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, _ := tree.Prove(2)
+			tt.mutate(steps)
+			if VerifySumProof(tt.leaf, steps, tt.root) {
+				t.Error("Expected verification to fail")
+			}
+		})
+	}
+}
+
+func TestMerkleSumTreeErrors(t *testing.T) {
+	huge := []SumLeaf{{Value: math.MaxUint64}, {Value: 1}}
+	if _, err := NewMerkleSumTree(huge); err == nil {
+		t.Error("Expected an overflow error")
+	}
+	tree := mustSumTree(t, testSumLeaves(3))
+	for _, i := range []int{-1, 3} {
+		if _, err := tree.Prove(i); err == nil {
+			t.Errorf("Expected Prove(%d) to fail", i)
+		}
+	}
+}
+
+// mustSumTree builds a tree or fails the test
+func mustSumTree(t *testing.T, leaves []SumLeaf) *MerkleSumTree {
+	t.Helper()
+	tree, err := NewMerkleSumTree(leaves)
+	if err != nil {
+		t.Fatalf("NewMerkleSumTree failed: %v", err)
+	}
+	return tree
+}
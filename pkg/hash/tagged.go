@@ -0,0 +1,42 @@
+package hash
+
+import (
+	"crypto/sha256"
+	"sync"
+)
+
+// tagHashes caches SHA256(tag) for tags that have been used before
+var tagHashes sync.Map // map[string][32]byte
+
+// TaggedSHA256 computes the BIP340 tagged hash of the concatenation of data.
+// The tag is hashed and prepended twice: SHA256(SHA256(tag) || SHA256(tag) || data).
+// The 64-byte prefix fills exactly one SHA256 block, so hashes under different
+// tags (and plain SHA256) cannot collide by construction. Taproot (TapLeaf,
+// TapBranch, TapTweak, TapSighash), BIP340 signatures, MuSig2 and BIP322 all
+// use it.
+//
+// Example:
+//
+//	leaf := TaggedSHA256("TapLeaf", []byte{0xc0}, compactSize, script)
+//	msgHash := TaggedSHA256("BIP0322-signed-message", []byte("Hello World"))
+func TaggedSHA256(tag string, data ...[]byte) [32]byte {
+	// Step 1: Look up or compute SHA256(tag)
+	var tagHash [32]byte
+	if cached, ok := tagHashes.Load(tag); ok {
+		tagHash = cached.([32]byte)
+	} else {
+		tagHash = sha256.Sum256([]byte(tag))
+		tagHashes.Store(tag, tagHash)
+	}
+
+	// Step 2: Hash the prefix and the data
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, d := range data {
+		h.Write(d)
+	}
+	var out [32]byte
+	h.Sum(out[:0])
+	return out
+}
@@ -0,0 +1,43 @@
+package hash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestTaggedSHA256(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      string
+		data     [][]byte
+		expected string
+	}{
+		// BIP322 message hash vectors
+		{"bip322 empty", "BIP0322-signed-message", nil, "c90c269c4f8fcbe6880f72a721ddfbf1914268a794cbb21cfafee13770ae19f1"},
+		{"bip322 hello", "BIP0322-signed-message", [][]byte{[]byte("Hello World")}, "f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a"},
+		{"bip322 split", "BIP0322-signed-message", [][]byte{[]byte("Hello"), nil, []byte(" World")}, "f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TaggedSHA256(tt.tag, tt.data...)
+			if hex.EncodeToString(got[:]) != tt.expected {
+				t.Errorf("Expected %s, got %x", tt.expected, got)
+			}
+		})
+	}
+
+	// Cached and uncached tags follow the definition
+	for _, tag := range []string{"TapLeaf", "TapLeaf", "TapBranch", ""} {
+		tagHash := SHA256([]byte(tag))
+		expected := sha256.Sum256(append(append(tagHash[:], tagHash[:]...), "data"...))
+		if got := TaggedSHA256(tag, []byte("data")); got != expected {
+			t.Errorf("Expected %x for tag %q, got %x", expected, tag, got)
+		}
+	}
+
+	// Different tags give different hashes for the same data
+	if TaggedSHA256("a", []byte("x")) == TaggedSHA256("b", []byte("x")) {
+		t.Error("Expected different tags to give different hashes")
+	}
+}
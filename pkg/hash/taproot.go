@@ -0,0 +1,43 @@
+package hash
+
+import "bytes"
+
+// TapLeafVersion is the BIP342 tapscript leaf version
+const TapLeafVersion = 0xc0
+
+// TapLeafHash calculates the BIP341 hash of a script leaf.
+// The leaf commits to its version and script:
+// TaggedSHA256("TapLeaf", version || compactSize(len(script)) || script).
+//
+// Example:
+//
+//	leaf := TapLeafHash(TapLeafVersion, script)
+func TapLeafHash(version byte, script []byte) [32]byte {
+	return TaggedSHA256("TapLeaf", []byte{version}, appendCompactSize(nil, uint64(len(script))), script)
+}
+
+// TapBranchHash calculates the BIP341 hash of an inner node of a taproot script tree.
+// The children are sorted before hashing, so the tree does not depend on the
+// order in which they are given and control blocks need no left/right flags.
+//
+// Example:
+//
+//	root := TapBranchHash(TapLeafHash(TapLeafVersion, redeem), TapLeafHash(TapLeafVersion, refund))
+func TapBranchHash(a, b [32]byte) [32]byte {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return TaggedSHA256("TapBranch", a[:], b[:])
+}
+
+// TapTweakHash calculates the BIP341 tweak of an x-only internal key.
+// merkleRoot is nil for key-path-only outputs and a 32-byte script tree root
+// otherwise; the output key is then Q = P + TapTweakHash(P, root)·G.
+//
+// Example:
+//
+//	t := TapTweakHash(internalKey, nil)       // key path only
+//	t = TapTweakHash(internalKey, root[:])    // with a script tree
+func TapTweakHash(internalKey [32]byte, merkleRoot []byte) [32]byte {
+	return TaggedSHA256("TapTweak", internalKey[:], merkleRoot)
+}
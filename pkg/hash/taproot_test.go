@@ -0,0 +1,49 @@
+package hash
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// TestTaprootHashes checks the BIP341 wallet test vectors (scriptPubKey cases 0 and 1)
+func TestTaprootHashes(t *testing.T) {
+	script, _ := hex.DecodeString("20d85a959b0290bf19bb89ed43c916be835475d013da4b362117393e25a48229b8ac")
+	leaf := TapLeafHash(TapLeafVersion, script)
+	if got := hex.EncodeToString(leaf[:]); got != "5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21" {
+		t.Errorf("Unexpected leaf hash %s", got)
+	}
+
+	tests := []struct {
+		name       string
+		internal   string
+		merkleRoot []byte
+		tweak      string
+	}{
+		{"key path only", "d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d", nil,
+			"b86e7be8f39bab32a6f2c0443abbc210f0edac0e2c53d501b36b64437d9c6c70"},
+		{"single leaf", "187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27", leaf[:],
+			"cbd8679ba636c1110ea247542cfbd964131a6be84f873f7f3b62a777528ed001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tweak := TapTweakHash(must32(tt.internal), tt.merkleRoot)
+			if got := hex.EncodeToString(tweak[:]); got != tt.tweak {
+				t.Errorf("Expected tweak %s, got %s", tt.tweak, got)
+			}
+		})
+	}
+}
+
+func TestTapBranchHash(t *testing.T) {
+	a, b := SHA256([]byte("a")), SHA256([]byte("b"))
+	if TapBranchHash(a, b) != TapBranchHash(b, a) {
+		t.Error("Expected TapBranchHash to ignore the order of its children")
+	}
+	lo, hi := a, b
+	if a[0] > b[0] {
+		lo, hi = b, a
+	}
+	if TapBranchHash(a, b) != TaggedSHA256("TapBranch", lo[:], hi[:]) {
+		t.Error("Expected the smaller child first")
+	}
+}
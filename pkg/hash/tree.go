@@ -0,0 +1,180 @@
+package hash
+
+import "fmt"
+
+// Incremental Merkle trees
+//
+// MerkleRoot rebuilds the whole tree on every call. MerkleTree keeps every
+// level in memory instead, so appending or replacing a leaf only rehashes the
+// path from that leaf to the root, log2(n) hashes, and proofs are read straight
+// from the stored levels. The tree follows Bitcoin's rules, so its root always
+// equals MerkleRoot of the same leaves and its proofs verify with
+// VerifyMerkleProof:
+//
+//	level 2:            root
+//	level 1:     h(a,b)        h(c,c)      <- an odd node is paired with itself
+//	level 0:   a       b     c
+//
+// NewMerkleTreeWith keeps the shape but swaps h for another NodeHash, such as
+// PoseidonBN254().Compress, so zero-knowledge demos can reuse the same tree
+// and check proofs with VerifyMerkleProofWith.
+
+// NodeHash combines two child nodes into their parent
+type NodeHash func(left, right [32]byte) [32]byte
+
+// MerkleTree is a Bitcoin Merkle tree that caches its intermediate levels
+type MerkleTree struct {
+	levels [][][32]byte // levels[0] are the leaves, the last level holds the root
+	node   NodeHash     // Parent hash, SHA256D(left || right) for Bitcoin trees
+}
+
+// NewMerkleTree builds a tree from leaves
+//
+// Example:
+//
+//	tree := NewMerkleTree(txids)
+//	tree.Append(newTxid)
+//	proof, err := tree.ProofAt(3)
+//	ok, _ := VerifyMerkleProof(txids[3], proof, tree.Root())
+func NewMerkleTree(leaves [][32]byte) *MerkleTree {
+	return NewMerkleTreeWith(leaves, bitcoinNode)
+}
+
+// NewMerkleTreeWith builds a tree whose parents are node(left, right) instead of SHA256D
+//
+// The odd-node rule is Bitcoin's: the last node of an odd level is paired with itself.
+//
+// Example:
+//
+//	tree := NewMerkleTreeWith(leaves, PoseidonBN254().Compress)
+//	proof, _ := tree.ProofAt(3)
+//	ok := VerifyMerkleProofWith(leaves[3], proof, tree.Root(), PoseidonBN254().Compress)
+func NewMerkleTreeWith(leaves [][32]byte, node NodeHash) *MerkleTree {
+	// Step 1: Copy the leaves
+	t := &MerkleTree{levels: [][][32]byte{append([][32]byte(nil), leaves...)}, node: node}
+
+	// Step 2: Hash each level into the next until one node is left
+	for level := t.levels[0]; len(level) > 1; level = t.levels[len(t.levels)-1] {
+		next := make([][32]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			next = append(next, merkleParent(node, level, i))
+		}
+		t.levels = append(t.levels, next)
+	}
+	return t
+}
+
+// Len returns the number of leaves
+func (t *MerkleTree) Len() int {
+	return len(t.levels[0])
+}
+
+// Leaf returns leaf i
+func (t *MerkleTree) Leaf(i int) ([32]byte, error) {
+	if i < 0 || i >= t.Len() {
+		return [32]byte{}, fmt.Errorf("leaf index %d out of range [0, %d)", i, t.Len())
+	}
+	return t.levels[0][i], nil
+}
+
+// Root returns the Merkle root, or the zero hash for an empty tree like MerkleRoot
+func (t *MerkleTree) Root() [32]byte {
+	top := t.levels[len(t.levels)-1]
+	if len(top) == 0 {
+		return [32]byte{}
+	}
+	return top[0]
+}
+
+// Append adds a leaf at the end and rehashes its path to the root
+//
+// Example:
+//
+//	tree := NewMerkleTree(nil)
+//	for _, txid := range txids {
+//	  tree.Append(txid)
+//	}
+func (t *MerkleTree) Append(leaf [32]byte) {
+	t.levels[0] = append(t.levels[0], leaf)
+	t.rehash(t.Len() - 1)
+}
+
+// Update replaces leaf i and rehashes its path to the root
+//
+// Example:
+//
+//	err := tree.Update(0, coinbaseTxid) // e.g. after changing the coinbase
+func (t *MerkleTree) Update(i int, leaf [32]byte) error {
+	if i < 0 || i >= t.Len() {
+		return fmt.Errorf("leaf index %d out of range [0, %d)", i, t.Len())
+	}
+	t.levels[0][i] = leaf
+	t.rehash(i)
+	return nil
+}
+
+// ProofAt returns the proof path for leaf i, for use with VerifyMerkleProof (or VerifyMerkleProofWith)
+func (t *MerkleTree) ProofAt(i int) ([]MerkleProofStep, error) {
+	if i < 0 || i >= t.Len() {
+		return nil, fmt.Errorf("leaf index %d out of range [0, %d)", i, t.Len())
+	}
+
+	// Step 1: Walk up the stored levels, collecting the sibling at each one
+	steps := make([]MerkleProofStep, 0, len(t.levels)-1)
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := i ^ 1
+		if sibling >= len(level) {
+			// Step 1a: The last odd node is paired with itself
+			sibling = i
+		}
+		steps = append(steps, MerkleProofStep{Sibling: level[sibling], LeftIsSibling: i%2 == 1})
+		i /= 2
+	}
+	return steps, nil
+}
+
+// rehash recomputes the ancestors of leaf i, growing the tree by one level if needed
+func (t *MerkleTree) rehash(i int) {
+	for depth := 0; len(t.levels[depth]) > 1; depth++ {
+		// Step 1: Add a level when the old root gained a sibling
+		if depth+1 == len(t.levels) {
+			t.levels = append(t.levels, nil)
+		}
+
+		// Step 2: Recompute the parent, appending it if it is new
+		parent := merkleParent(t.node, t.levels[depth], i&^1)
+		i /= 2
+		if i == len(t.levels[depth+1]) {
+			t.levels[depth+1] = append(t.levels[depth+1], parent)
+		} else {
+			t.levels[depth+1][i] = parent
+		}
+	}
+}
+
+// VerifyMerkleProofWith checks a proof from a tree built with NewMerkleTreeWith and the same node hash
+func VerifyMerkleProofWith(leaf [32]byte, steps []MerkleProofStep, wantRoot [32]byte, node NodeHash) bool {
+	current := leaf
+	for _, step := range steps {
+		if step.LeftIsSibling {
+			current = node(step.Sibling, current)
+		} else {
+			current = node(current, step.Sibling)
+		}
+	}
+	return current == wantRoot
+}
+
+// merkleParent hashes the pair starting at i, duplicating the last node of an odd level
+func merkleParent(node NodeHash, level [][32]byte, i int) [32]byte {
+	right := level[i]
+	if i+1 < len(level) {
+		right = level[i+1]
+	}
+	return node(level[i], right)
+}
+
+// bitcoinNode is the NodeHash of Bitcoin's transaction tree
+func bitcoinNode(left, right [32]byte) [32]byte {
+	return SHA256D(Concat(left[:], right[:]))
+}
@@ -0,0 +1,173 @@
+package hash
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// testLeaves returns n distinct leaves
+func testLeaves(n int) [][32]byte {
+	leaves := make([][32]byte, n)
+	for i := range leaves {
+		binary.BigEndian.PutUint32(leaves[i][:], uint32(i))
+		leaves[i] = SHA256(leaves[i][:])
+	}
+	return leaves
+}
+
+func TestMerkleTreeMatchesMerkleRoot(t *testing.T) {
+	for n := 0; n <= 33; n++ {
+		leaves := testLeaves(n)
+
+		// Built in one go
+		tree := NewMerkleTree(leaves)
+		if tree.Root() != MerkleRoot(leaves) {
+			t.Errorf("n=%d: NewMerkleTree root differs from MerkleRoot", n)
+		}
+
+		// Built by appending one leaf at a time
+		incremental := NewMerkleTree(nil)
+		for i, leaf := range leaves {
+			incremental.Append(leaf)
+			if incremental.Root() != MerkleRoot(leaves[:i+1]) {
+				t.Fatalf("n=%d: Append root differs from MerkleRoot after %d leaves", n, i+1)
+			}
+		}
+		if incremental.Len() != n {
+			t.Errorf("Expected %d leaves, got %d", n, incremental.Len())
+		}
+
+		// Every proof verifies against the root
+		for i, leaf := range leaves {
+			proof, err := tree.ProofAt(i)
+			if err != nil {
+				t.Fatalf("ProofAt(%d) failed: %v", i, err)
+			}
+			if ok, _ := VerifyMerkleProof(leaf, proof, tree.Root()); !ok {
+				t.Errorf("n=%d: proof for leaf %d does not verify", n, i)
+			}
+		}
+	}
+}
+
+func TestMerkleTreeUpdate(t *testing.T) {
+	leaves := testLeaves(13)
+	tree := NewMerkleTree(leaves)
+
+	tests := []struct {
+		name  string
+		index int
+	}{
+		{"first", 0},
+		{"middle", 6},
+		{"last odd", 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaves[tt.index] = SHA256([]byte(tt.name))
+			if err := tree.Update(tt.index, leaves[tt.index]); err != nil {
+				t.Fatalf("Update failed: %v", err)
+			}
+			if tree.Root() != MerkleRoot(leaves) {
+				t.Error("Expected root to match MerkleRoot after Update")
+			}
+			if got, _ := tree.Leaf(tt.index); got != leaves[tt.index] {
+				t.Error("Expected Leaf to return the new leaf")
+			}
+			proof, _ := tree.ProofAt(tt.index)
+			if ok, _ := VerifyMerkleProof(leaves[tt.index], proof, tree.Root()); !ok {
+				t.Error("Expected proof of the updated leaf to verify")
+			}
+		})
+	}
+}
+
+func TestMerkleTreeIndexErrors(t *testing.T) {
+	tree := NewMerkleTree(testLeaves(3))
+	for _, i := range []int{-1, 3} {
+		if err := tree.Update(i, [32]byte{}); err == nil {
+			t.Errorf("Expected Update(%d) to fail", i)
+		}
+		if _, err := tree.ProofAt(i); err == nil {
+			t.Errorf("Expected ProofAt(%d) to fail", i)
+		}
+		if _, err := tree.Leaf(i); err == nil {
+			t.Errorf("Expected Leaf(%d) to fail", i)
+		}
+	}
+}
+
+func TestMerkleTreeWith(t *testing.T) {
+	poseidon := PoseidonBN254().Compress
+	tests := []struct {
+		name string
+		node NodeHash
+	}{
+		{"bitcoin", bitcoinNode},
+		{"poseidon", poseidon},
+		{"mimc", MiMC7Compress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaves := testLeaves(5)
+
+			// Appending leaf by leaf gives the same tree as building it at once
+			tree := NewMerkleTreeWith(leaves, tt.node)
+			grown := NewMerkleTreeWith(nil, tt.node)
+			for _, leaf := range leaves {
+				grown.Append(leaf)
+			}
+			if grown.Root() != tree.Root() {
+				t.Error("Expected Append to give the same root")
+			}
+
+			// The shape is Bitcoin's: the fifth leaf is paired with itself
+			n := tt.node
+			l1 := [][32]byte{n(leaves[0], leaves[1]), n(leaves[2], leaves[3]), n(leaves[4], leaves[4])}
+			l2 := [][32]byte{n(l1[0], l1[1]), n(l1[2], l1[2])}
+			if want := n(l2[0], l2[1]); tree.Root() != want {
+				t.Errorf("Expected root %x, got %x", want, tree.Root())
+			}
+
+			for i, leaf := range leaves {
+				proof, err := tree.ProofAt(i)
+				if err != nil {
+					t.Fatalf("ProofAt(%d) failed: %v", i, err)
+				}
+				if !VerifyMerkleProofWith(leaf, proof, tree.Root(), tt.node) {
+					t.Errorf("Expected the proof of leaf %d to verify", i)
+				}
+				if VerifyMerkleProofWith(leaves[(i+1)%len(leaves)], proof, tree.Root(), tt.node) {
+					t.Errorf("Expected the proof of leaf %d to fail for another leaf", i)
+				}
+			}
+		})
+	}
+
+	// The Bitcoin node hash reproduces MerkleRoot and VerifyMerkleProof
+	leaves := testLeaves(7)
+	tree := NewMerkleTreeWith(leaves, bitcoinNode)
+	if tree.Root() != MerkleRoot(leaves) {
+		t.Error("Expected the Bitcoin node hash to give MerkleRoot")
+	}
+	if NewMerkleTreeWith(leaves, poseidon).Root() == tree.Root() {
+		t.Error("Expected Poseidon to give a different root")
+	}
+}
+
+func BenchmarkMerkleTreeUpdate(b *testing.B) {
+	leaves := testLeaves(4096)
+	b.Run("MerkleRoot", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			leaves[i%len(leaves)][0]++
+			MerkleRoot(leaves)
+		}
+	})
+	b.Run("MerkleTree.Update", func(b *testing.B) {
+		tree := NewMerkleTree(leaves)
+		for i := 0; i < b.N; i++ {
+			leaves[i%len(leaves)][0]++
+			_ = tree.Update(i%len(leaves), leaves[i%len(leaves)])
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package keylink
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/armor"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// Cross-curve key linking
+//
+// A Proof shows that one party controls both a secp256k1 key (BIP340 x-only)
+// and an ed25519 key, e.g. to tie a Bitcoin identity to an SSH or Tor key.
+// Both keys sign the same statement:
+//
+//	statement = TaggedHash("cryptography-playground/keylink/v1",
+//	                       x(P_secp) || P_ed25519 || len(context) || context)
+//
+// Because the statement names both keys, neither signature can be reused to
+// link one of the keys to a third key, and the context (an application name,
+// a date, a nonce from the verifier) stops proofs from being replayed in
+// another setting. A proof shows control of both keys at signing time; it
+// says nothing about who else may know them.
+
+// statementTag is the tagged hash domain of the signed statement
+const statementTag = "cryptography-playground/keylink/v1"
+
+// ProofSize is the encoded size of a proof without its context
+const ProofSize = 32 + ed25519.PublicKeySize + 64 + ed25519.SignatureSize + 4
+
+// maxContextSize bounds the context so proofs stay small
+const maxContextSize = 1024
+
+// ErrInvalidProof is returned when a proof's signatures do not verify
+var ErrInvalidProof = errors.New("invalid key link proof")
+
+// Proof links a secp256k1 key and an ed25519 key
+type Proof struct {
+	Secp256k1    [32]byte          // BIP340 x-only public key
+	Ed25519      ed25519.PublicKey // 32-byte ed25519 public key
+	Context      []byte            // Application-chosen binding, may be empty
+	Secp256k1Sig [64]byte          // BIP340 signature over the statement
+	Ed25519Sig   []byte            // ed25519 signature over the statement
+}
+
+// Prove links the public keys of two private keys under a context
+//
+// Example:
+//
+//	proof, err := keylink.Prove(btcKey, sshKey, []byte("example.com identity 2026"))
+//	fmt.Println(proof.Verify()) // <nil>
+func Prove(secpKey *btcec.PrivateKey, edKey ed25519.PrivateKey, context []byte) (*Proof, error) {
+	// Step 1: Validate inputs
+	if secpKey == nil {
+		return nil, errors.New("secp256k1 key cannot be nil")
+	}
+	if len(edKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("ed25519 key must be %d bytes, got %d", ed25519.PrivateKeySize, len(edKey))
+	}
+	if len(context) > maxContextSize {
+		return nil, fmt.Errorf("context must be at most %d bytes", maxContextSize)
+	}
+	proof := &Proof{
+		Secp256k1: schnorr.XOnlyFromPub(secpKey.PubKey()),
+		Ed25519:   edKey.Public().(ed25519.PublicKey),
+		Context:   append([]byte(nil), context...),
+	}
+
+	// Step 2: Both keys sign the statement naming both keys
+	statement := proof.Statement()
+	var err error
+	if proof.Secp256k1Sig, err = schnorr.SignDigest(statement, secpKey); err != nil {
+		return nil, err
+	}
+	proof.Ed25519Sig = ed25519.Sign(edKey, statement[:])
+	return proof, nil
+}
+
+// Statement returns the 32-byte digest both keys sign
+func (p *Proof) Statement() [32]byte {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(p.Context)))
+	return schnorr.TaggedHash(statementTag, p.Secp256k1[:], p.Ed25519, length[:], p.Context)
+}
+
+// Verify checks both signatures; it returns nil if the keys are linked
+//
+// Example:
+//
+//	if err := proof.Verify(); err != nil {
+//		// the keys are not shown to belong to the same party
+//	}
+func (p *Proof) Verify() error {
+	// Step 1: Validate the encoding
+	if len(p.Ed25519) != ed25519.PublicKeySize || len(p.Ed25519Sig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed ed25519 key or signature", ErrInvalidProof)
+	}
+	if len(p.Context) > maxContextSize {
+		return fmt.Errorf("%w: context too long", ErrInvalidProof)
+	}
+	pub, err := schnorr.ParseXOnly(p.Secp256k1)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+
+	// Step 2: Both signatures must cover the same statement
+	statement := p.Statement()
+	if !schnorr.VerifyDigest(statement, pub, p.Secp256k1Sig) {
+		return fmt.Errorf("%w: secp256k1 signature", ErrInvalidProof)
+	}
+	if !ed25519.Verify(p.Ed25519, statement[:], p.Ed25519Sig) {
+		return fmt.Errorf("%w: ed25519 signature", ErrInvalidProof)
+	}
+	return nil
+}
+
+// VerifyLink checks that proof links exactly the two given keys under context
+//
+// Use this rather than Proof.Verify when the keys and context come from
+// somewhere other than the proof itself.
+//
+// Example:
+//
+//	err := keylink.VerifyLink(proof, knownXOnly, knownSSHKey, []byte("example.com identity 2026"))
+func VerifyLink(proof *Proof, secp [32]byte, ed ed25519.PublicKey, context []byte) error {
+	if proof == nil {
+		return errors.New("proof cannot be nil")
+	}
+	if proof.Secp256k1 != secp || !ed.Equal(proof.Ed25519) || string(proof.Context) != string(context) {
+		return fmt.Errorf("%w: proof is for other keys or another context", ErrInvalidProof)
+	}
+	return proof.Verify()
+}
+
+// Encode serializes the proof
+//
+// Format: [secp256k1 (32)][ed25519 (32)][secp256k1 sig (64)][ed25519 sig (64)][context length (4, BE)][context]
+func (p *Proof) Encode() []byte {
+	out := make([]byte, 0, ProofSize+len(p.Context))
+	out = append(out, p.Secp256k1[:]...)
+	out = append(out, p.Ed25519...)
+	out = append(out, p.Secp256k1Sig[:]...)
+	out = append(out, p.Ed25519Sig...)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(p.Context)))
+	return append(out, p.Context...)
+}
+
+// ParseProof decodes a proof produced by Encode; it does not verify it
+func ParseProof(data []byte) (*Proof, error) {
+	if len(data) < ProofSize {
+		return nil, fmt.Errorf("proof must be at least %d bytes, got %d", ProofSize, len(data))
+	}
+	p := &Proof{}
+	copy(p.Secp256k1[:], data[0:32])
+	p.Ed25519 = append(ed25519.PublicKey(nil), data[32:64]...)
+	copy(p.Secp256k1Sig[:], data[64:128])
+	p.Ed25519Sig = append([]byte(nil), data[128:192]...)
+	length := binary.BigEndian.Uint32(data[192:196])
+	if length > maxContextSize || int(length) != len(data)-ProofSize {
+		return nil, fmt.Errorf("invalid context length %d", length)
+	}
+	p.Context = append([]byte(nil), data[ProofSize:]...)
+	return p, nil
+}
+
+// Armor encodes the proof as an armored SIGNATURE block for publishing
+//
+// Example:
+//
+//	fmt.Println(proof.Armor())
+//	// -----BEGIN PLAYGROUND SIGNATURE-----
+//	// Comment: keylink
+//	// ...
+func (p *Proof) Armor() string {
+	return armor.Encode(armor.TypeSignature, map[string]string{"Comment": "keylink"}, p.Encode())
+}
+
+// ParseArmoredProof decodes a proof produced by Proof.Armor; it does not verify it
+func ParseArmoredProof(text string) (*Proof, error) {
+	data, err := armor.DecodeType(text, armor.TypeSignature)
+	if err != nil {
+		return nil, err
+	}
+	return ParseProof(data)
+}
@@ -0,0 +1,121 @@
+package keylink
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// newKeys returns deterministic test keys on both curves
+func newKeys(t *testing.T, seed byte) (*btcec.PrivateKey, ed25519.PrivateKey) {
+	t.Helper()
+	secp, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{seed}, 32))
+	ed := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{seed}, ed25519.SeedSize))
+	return secp, ed
+}
+
+func TestProve(t *testing.T) {
+	secp, ed := newKeys(t, 0x01)
+	context := []byte("example.com identity 2026")
+
+	proof, err := Prove(secp, ed, context)
+	if err != nil {
+		t.Fatalf("Prove failed: %v", err)
+	}
+	if err := proof.Verify(); err != nil {
+		t.Errorf("Expected proof to verify, got %v", err)
+	}
+	xOnly := schnorr.XOnlyFromPub(secp.PubKey())
+	edPub := ed.Public().(ed25519.PublicKey)
+	if err := VerifyLink(proof, xOnly, edPub, context); err != nil {
+		t.Errorf("Expected VerifyLink to succeed, got %v", err)
+	}
+
+	// Round trip through the encoding
+	parsed, err := ParseProof(proof.Encode())
+	if err != nil {
+		t.Fatalf("ParseProof failed: %v", err)
+	}
+	if err := parsed.Verify(); err != nil {
+		t.Errorf("Expected parsed proof to verify, got %v", err)
+	}
+	if !bytes.Equal(parsed.Encode(), proof.Encode()) {
+		t.Error("Expected encoding to round trip")
+	}
+
+	// And through the armored text form
+	armored, err := ParseArmoredProof("Proof of my keys:\n" + proof.Armor())
+	if err != nil {
+		t.Fatalf("ParseArmoredProof failed: %v", err)
+	}
+	if err := armored.Verify(); err != nil {
+		t.Errorf("Expected armored proof to verify, got %v", err)
+	}
+}
+
+// TestProveTampering tests that every field of the proof is bound by the signatures
+func TestProveTampering(t *testing.T) {
+	secp, ed := newKeys(t, 0x01)
+	otherSecp, otherEd := newKeys(t, 0x02)
+	proof, err := Prove(secp, ed, []byte("ctx"))
+	if err != nil {
+		t.Fatalf("Prove failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(p *Proof)
+	}{
+		{"other secp256k1 key", func(p *Proof) { p.Secp256k1 = schnorr.XOnlyFromPub(otherSecp.PubKey()) }},
+		{"other ed25519 key", func(p *Proof) { p.Ed25519 = otherEd.Public().(ed25519.PublicKey) }},
+		{"other context", func(p *Proof) { p.Context = []byte("ctx2") }},
+		{"secp256k1 signature", func(p *Proof) { p.Secp256k1Sig[63] ^= 0x01 }},
+		{"ed25519 signature", func(p *Proof) { p.Ed25519Sig[0] ^= 0x01 }},
+		{"short ed25519 key", func(p *Proof) { p.Ed25519 = p.Ed25519[:31] }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered, _ := ParseProof(proof.Encode())
+			tt.tamper(tampered)
+			if err := tampered.Verify(); !errors.Is(err, ErrInvalidProof) {
+				t.Errorf("Expected ErrInvalidProof, got %v", err)
+			}
+		})
+	}
+
+	// A valid proof for other keys does not satisfy VerifyLink
+	other, _ := Prove(otherSecp, otherEd, []byte("ctx"))
+	if err := VerifyLink(other, proof.Secp256k1, proof.Ed25519, []byte("ctx")); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("Expected ErrInvalidProof for other keys, got %v", err)
+	}
+	if err := VerifyLink(proof, proof.Secp256k1, proof.Ed25519, []byte("other")); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("Expected ErrInvalidProof for another context, got %v", err)
+	}
+}
+
+func TestProofErrors(t *testing.T) {
+	secp, ed := newKeys(t, 0x01)
+
+	if _, err := Prove(nil, ed, nil); err == nil {
+		t.Error("Expected error for nil secp256k1 key")
+	}
+	if _, err := Prove(secp, ed[:32], nil); err == nil {
+		t.Error("Expected error for a short ed25519 key")
+	}
+	if _, err := Prove(secp, ed, make([]byte, maxContextSize+1)); err == nil {
+		t.Error("Expected error for an oversized context")
+	}
+
+	proof, _ := Prove(secp, ed, []byte("ctx"))
+	encoded := proof.Encode()
+	if _, err := ParseProof(encoded[:ProofSize-1]); err == nil {
+		t.Error("Expected error for a truncated proof")
+	}
+	if _, err := ParseProof(append(encoded, 0x00)); err == nil {
+		t.Error("Expected error for trailing bytes")
+	}
+}
@@ -0,0 +1,70 @@
+package multisig
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/fixtures"
+)
+
+// TestMultisigGolden snapshots addresses and MuSig signatures for a fixed 2-of-3 setup
+//
+// Session randomness comes from fixtures.DeterministicReader, so the signatures
+// are reproducible. Run with -update after intentional changes.
+func TestMultisigGolden(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 3)
+	msg := []byte("golden message")
+
+	p2wsh, err := setup.P2WSHAddress("bc")
+	if err != nil {
+		t.Fatalf("P2WSHAddress failed: %v", err)
+	}
+	out, err := setup.TaprootOutput()
+	if err != nil {
+		t.Fatalf("TaprootOutput failed: %v", err)
+	}
+	p2tr, err := out.Address("bc")
+	if err != nil {
+		t.Fatalf("Address failed: %v", err)
+	}
+
+	golden := map[string]string{
+		"p2wsh_address":  p2wsh,
+		"p2tr_address":   p2tr,
+		"internal_key":   hex.EncodeToString(out.InternalKey[:]),
+		"leaf_hash":      hex.EncodeToString(out.MerkleRoot[:]),
+		"control_block":  hex.EncodeToString(out.ControlBlock),
+		"multi_a_script": hex.EncodeToString(out.LeafScript),
+	}
+
+	// Deterministic 3-of-3 MuSig signatures in both nonce modes
+	for _, mode := range []NonceMode{NonceModeMuSig2, NonceModeCommitReveal} {
+		sessions := make([]*Session, len(setup.Participants))
+		for i := range sessions {
+			reader := fixtures.DeterministicReader(fmt.Sprintf("golden-%s-%d", mode, i))
+			s, err := NewSession(setup, i, msg, WithNonceMode(mode), WithSessionID([32]byte{0x01}), WithRandReader(reader))
+			if err != nil {
+				t.Fatalf("NewSession failed: %v", err)
+			}
+			sessions[i] = s
+		}
+		if mode == NonceModeCommitReveal {
+			exchangeCommitments(t, sessions)
+		}
+		exchangeNonces(t, sessions)
+		signAll(t, sessions)
+
+		complete, err := sessions[0].Finalize()
+		if err != nil {
+			t.Fatalf("Finalize failed: %v", err)
+		}
+		if !complete.VerifyAgainstAggregatedKey(msg, sessions[0].AggregatedKey()) {
+			t.Fatalf("%s signature does not verify", mode)
+		}
+		sig := complete.Serialize()
+		golden["signature_"+mode.String()] = hex.EncodeToString(sig[:])
+	}
+
+	fixtures.GoldenJSON(t, "multisig.golden.json", golden)
+}
@@ -0,0 +1,263 @@
+package multisig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Session manager for signing services
+//
+// A Session is not safe for concurrent use. A server that runs many signings
+// at once keeps them in a SessionManager, which looks sessions up by ID,
+// serializes access to each one, and drops sessions that have been idle longer
+// than the TTL (a peer that never sends its nonce must not pin memory or secret
+// nonces forever).
+
+// DefaultSessionTTL is how long a session may stay idle before it is collected
+const DefaultSessionTTL = 10 * time.Minute
+
+var (
+	// ErrSessionExists is returned when a session with the same ID is already managed
+	ErrSessionExists = errors.New("session already exists")
+	// ErrSessionNotFound is returned for unknown, removed or expired sessions
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// ManagerHooks are optional callbacks for metrics and logging
+//
+// Hooks are called without the manager lock held, but must not block for long.
+type ManagerHooks struct {
+	OnAdd      func(id [32]byte)
+	OnComplete func(id [32]byte, elapsed time.Duration) // Session reached StateComplete
+	OnExpire   func(id [32]byte, state SessionState)    // Session was collected while idle
+}
+
+// managerOptions holds the settings selected with ManagerOption
+type managerOptions struct {
+	ttl   time.Duration
+	hooks ManagerHooks
+	now   func() time.Time
+}
+
+// ManagerOption configures a SessionManager
+type ManagerOption func(*managerOptions)
+
+// WithSessionTTL sets how long a session may stay idle before it is collected
+func WithSessionTTL(ttl time.Duration) ManagerOption {
+	return func(o *managerOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithManagerHooks sets the metrics callbacks
+func WithManagerHooks(hooks ManagerHooks) ManagerOption {
+	return func(o *managerOptions) {
+		o.hooks = hooks
+	}
+}
+
+// WithClock replaces time.Now (for tests)
+func WithClock(now func() time.Time) ManagerOption {
+	return func(o *managerOptions) {
+		o.now = now
+	}
+}
+
+// managedSession is a session with its own lock and bookkeeping
+type managedSession struct {
+	mu        sync.Mutex
+	session   *Session
+	created   time.Time
+	lastUsed  time.Time
+	removed   bool // Set once the entry left the map; Do must not use it anymore
+	completed bool // OnComplete was reported
+}
+
+// SessionManager tracks concurrent signing sessions by ID
+//
+// Example:
+//
+//	manager := NewSessionManager(WithSessionTTL(5 * time.Minute))
+//	go manager.Run(ctx, time.Minute)
+//	manager.Add(session)
+//	// ... when a peer's nonce arrives ...
+//	err := manager.Do(id, func(s *Session) error {
+//		return s.AddPublicNonce(peer, nonce)
+//	})
+type SessionManager struct {
+	mu       sync.Mutex
+	sessions map[[32]byte]*managedSession
+	options  managerOptions
+}
+
+// NewSessionManager creates an empty manager
+func NewSessionManager(opts ...ManagerOption) *SessionManager {
+	options := managerOptions{ttl: DefaultSessionTTL, now: time.Now}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &SessionManager{
+		sessions: make(map[[32]byte]*managedSession),
+		options:  options,
+	}
+}
+
+// Add starts managing a session under its ID
+//
+// The caller must not use the session directly afterwards; go through Do.
+func (m *SessionManager) Add(s *Session) error {
+	if s == nil {
+		return errors.New("session cannot be nil")
+	}
+	id := s.ID()
+	now := m.options.now()
+
+	m.mu.Lock()
+	if _, ok := m.sessions[id]; ok {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %x", ErrSessionExists, id)
+	}
+	m.sessions[id] = &managedSession{session: s, created: now, lastUsed: now}
+	m.mu.Unlock()
+
+	if m.options.hooks.OnAdd != nil {
+		m.options.hooks.OnAdd(id)
+	}
+	return nil
+}
+
+// Do runs fn with exclusive access to a session
+//
+// Calls for different sessions run in parallel; calls for the same session are
+// serialized. Each call counts as activity for the TTL.
+func (m *SessionManager) Do(id [32]byte, fn func(*Session) error) error {
+	m.mu.Lock()
+	entry, ok := m.sessions[id]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %x", ErrSessionNotFound, id)
+	}
+
+	justCompleted, elapsed, err := m.run(id, entry, fn)
+	if justCompleted && m.options.hooks.OnComplete != nil {
+		m.options.hooks.OnComplete(id, elapsed)
+	}
+	return err
+}
+
+// run calls fn under the session lock and reports whether it completed the session
+//
+// The lock is released by defer, so a panicking fn does not leave the session
+// locked forever (Collect could then never expire it).
+func (m *SessionManager) run(id [32]byte, entry *managedSession, fn func(*Session) error) (bool, time.Duration, error) {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.removed {
+		return false, 0, fmt.Errorf("%w: %x", ErrSessionNotFound, id)
+	}
+
+	err := fn(entry.session)
+	now := m.options.now()
+	entry.lastUsed = now
+	justCompleted := !entry.completed && entry.session.State() == StateComplete
+	if justCompleted {
+		entry.completed = true
+	}
+	return justCompleted, now.Sub(entry.created), err
+}
+
+// Remove stops managing a session and erases its secret nonces
+//
+// Returns false if the session was not managed.
+func (m *SessionManager) Remove(id [32]byte) bool {
+	m.mu.Lock()
+	entry, ok := m.sessions[id]
+	delete(m.sessions, id)
+	m.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	entry.mu.Lock()
+	entry.removed = true
+	entry.session.discardNonces()
+	entry.mu.Unlock()
+	return true
+}
+
+// Len returns the number of managed sessions
+func (m *SessionManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// Collect removes every session idle for longer than the TTL
+//
+// Sessions in use by Do are never collected. Returns the number removed.
+func (m *SessionManager) Collect() int {
+	now := m.options.now()
+
+	// Step 1: Unlink idle sessions while holding the manager lock
+	type expired struct {
+		id    [32]byte
+		state SessionState
+	}
+	var removed []expired
+	m.mu.Lock()
+	for id, entry := range m.sessions {
+		if !entry.mu.TryLock() {
+			continue
+		}
+		if now.Sub(entry.lastUsed) > m.options.ttl {
+			delete(m.sessions, id)
+			entry.removed = true
+			entry.session.discardNonces()
+			removed = append(removed, expired{id, entry.session.State()})
+		}
+		entry.mu.Unlock()
+	}
+	m.mu.Unlock()
+
+	// Step 2: Report outside the lock
+	if m.options.hooks.OnExpire != nil {
+		for _, e := range removed {
+			m.options.hooks.OnExpire(e.id, e.state)
+		}
+	}
+	return len(removed)
+}
+
+// Run calls Collect every interval until ctx is done
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	go manager.Run(ctx, time.Minute)
+func (m *SessionManager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Collect()
+		}
+	}
+}
+
+// discardNonces erases the secret nonces of a session that will not sign
+//
+// The session is marked as used, so a later Sign returns ErrNonceReused.
+func (s *Session) discardNonces() {
+	for j := range s.secNonces {
+		s.secNonces[j].Zero()
+	}
+	s.secNonces = nil
+	s.nonceUsed = true
+}
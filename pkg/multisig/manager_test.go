@@ -0,0 +1,237 @@
+package multisig
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for TTL tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// TestSessionManagerConcurrent runs many 2-of-2 signings in parallel through one manager
+func TestSessionManagerConcurrent(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 2)
+	msg := []byte("parallel signing")
+
+	var added, completed atomic.Int32
+	manager := NewSessionManager(WithManagerHooks(ManagerHooks{
+		OnAdd:      func([32]byte) { added.Add(1) },
+		OnComplete: func([32]byte, time.Duration) { completed.Add(1) },
+	}))
+
+	const count = 100
+	var wg sync.WaitGroup
+	errs := make(chan error, count)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- managedSigning(manager, setup, msg, [32]byte{byte(i), byte(i >> 8), 0x01})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if added.Load() != count || completed.Load() != count {
+		t.Errorf("Expected %d OnAdd and OnComplete calls, got %d and %d", count, added.Load(), completed.Load())
+	}
+	if manager.Len() != count {
+		t.Errorf("Expected %d sessions, got %d", count, manager.Len())
+	}
+}
+
+// managedSigning signs as participant 0 through the manager and participant 1 directly
+func managedSigning(manager *SessionManager, setup *MultisigSetup, msg []byte, id [32]byte) error {
+	local, err := NewSession(setup, 0, msg, WithSessionID(id))
+	if err != nil {
+		return err
+	}
+	remote, err := NewSession(setup, 1, msg, WithSessionID(id))
+	if err != nil {
+		return err
+	}
+	if err := manager.Add(local); err != nil {
+		return err
+	}
+
+	var localNonce []byte
+	if err := manager.Do(id, func(s *Session) (err error) {
+		localNonce, err = s.PublicNonce()
+		return err
+	}); err != nil {
+		return err
+	}
+	remoteNonce, _ := remote.PublicNonce()
+	if err := remote.AddPublicNonce(0, localNonce); err != nil {
+		return err
+	}
+
+	var localPartial *PartialSignature
+	if err := manager.Do(id, func(s *Session) (err error) {
+		if err := s.AddPublicNonce(1, remoteNonce); err != nil {
+			return err
+		}
+		localPartial, err = s.Sign()
+		return err
+	}); err != nil {
+		return err
+	}
+	remotePartial, err := remote.Sign()
+	if err != nil {
+		return err
+	}
+	if err := remote.AddPartialSignature(localPartial); err != nil {
+		return err
+	}
+
+	return manager.Do(id, func(s *Session) error {
+		if err := s.AddPartialSignature(remotePartial); err != nil {
+			return err
+		}
+		complete, err := s.Finalize()
+		if err != nil {
+			return err
+		}
+		if !complete.VerifyAgainstAggregatedKey(msg, s.AggregatedKey()) {
+			return errors.New("managed session produced an invalid signature")
+		}
+		return nil
+	})
+}
+
+// TestSessionManagerExpiry tests that idle sessions are collected and their nonces erased
+func TestSessionManagerExpiry(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 2)
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+
+	var expired []SessionState
+	manager := NewSessionManager(
+		WithSessionTTL(time.Minute),
+		WithClock(clock.Now),
+		WithManagerHooks(ManagerHooks{OnExpire: func(_ [32]byte, state SessionState) {
+			expired = append(expired, state)
+		}}),
+	)
+
+	stale, _ := NewSession(setup, 0, []byte("stale"), WithSessionID([32]byte{0x01}))
+	active, _ := NewSession(setup, 0, []byte("active"), WithSessionID([32]byte{0x02}))
+	if err := manager.Add(stale); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := manager.Add(active); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	// Only the session touched within the TTL survives
+	clock.Advance(45 * time.Second)
+	if err := manager.Do([32]byte{0x02}, func(*Session) error { return nil }); err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	clock.Advance(30 * time.Second)
+	if n := manager.Collect(); n != 1 {
+		t.Fatalf("Expected 1 collected session, got %d", n)
+	}
+	if len(expired) != 1 || expired[0] != StateNonce {
+		t.Errorf("Expected one OnExpire in state nonce, got %v", expired)
+	}
+
+	err := manager.Do([32]byte{0x01}, func(*Session) error { return nil })
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound for a collected session, got %v", err)
+	}
+	if stale.secNonces != nil {
+		t.Error("Expected the collected session's secret nonces to be erased")
+	}
+	if _, err := stale.Sign(); !errors.Is(err, ErrNonceReused) {
+		t.Errorf("Expected ErrNonceReused from a collected session, got %v", err)
+	}
+	if manager.Len() != 1 {
+		t.Errorf("Expected 1 remaining session, got %d", manager.Len())
+	}
+}
+
+// TestSessionManagerPanic checks that a panicking callback does not leave the session locked
+func TestSessionManagerPanic(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 2)
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	manager := NewSessionManager(WithSessionTTL(time.Minute), WithClock(clock.Now))
+
+	id := [32]byte{0x03}
+	session, _ := NewSession(setup, 0, []byte("panic"), WithSessionID(id))
+	if err := manager.Add(session); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Expected the callback panic to propagate")
+			}
+		}()
+		manager.Do(id, func(*Session) error { panic("callback failed") })
+	}()
+
+	// The session is usable again and still expires
+	if err := manager.Do(id, func(*Session) error { return nil }); err != nil {
+		t.Fatalf("Do after a panic failed: %v", err)
+	}
+	clock.Advance(2 * time.Minute)
+	if n := manager.Collect(); n != 1 {
+		t.Errorf("Expected the session to be collected, got %d", n)
+	}
+}
+
+func TestSessionManagerErrors(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 2)
+	manager := NewSessionManager()
+	s, _ := NewSession(setup, 0, []byte("msg"), WithSessionID([32]byte{0x07}))
+
+	if err := manager.Add(s); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := manager.Add(s); !errors.Is(err, ErrSessionExists) {
+		t.Errorf("Expected ErrSessionExists, got %v", err)
+	}
+	if err := manager.Add(nil); err == nil {
+		t.Error("Expected error for nil session")
+	}
+
+	// Errors from fn are passed through
+	sentinel := errors.New("callback failed")
+	if err := manager.Do([32]byte{0x07}, func(*Session) error { return sentinel }); !errors.Is(err, sentinel) {
+		t.Errorf("Expected callback error, got %v", err)
+	}
+
+	if !manager.Remove([32]byte{0x07}) {
+		t.Error("Expected Remove to report the session")
+	}
+	if manager.Remove([32]byte{0x07}) {
+		t.Error("Expected second Remove to report nothing")
+	}
+	if err := manager.Do([32]byte{0x07}, func(*Session) error { return nil }); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
@@ -7,13 +7,16 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
 )
 
 // Participant represents a participant in a multisignature scheme
 type Participant struct {
 	PrivateKey *btcec.PrivateKey
 	PublicKey  *btcec.PublicKey
-	Index      int // Position in the multisig (0-based)
+	Index      int        // Position in the multisig (0-based)
+	Label      string     // Optional human-readable name, e.g. the signing device
+	Origin     *KeyOrigin // Optional BIP32 origin of PublicKey
 }
 
 // MultisigSetup represents the setup for a multisignature scheme
@@ -25,18 +28,18 @@ type MultisigSetup struct {
 
 // PartialSignature represents a partial signature from one participant
 type PartialSignature struct {
-	R      [32]byte // R component of the signature
-	S      [32]byte // S component of the signature
-	Index  int      // Index of the participant who created this signature
-	PubKey [32]byte // X-only public key of the participant
+	R      [32]byte               // R component of the signature
+	S      [32]byte               // S component of the signature
+	Index  int                    // Index of the participant who created this signature
+	PubKey schnorr.XOnlyPublicKey // X-only public key of the participant
 }
 
 // CompleteSignature represents a complete multisignature
 type CompleteSignature struct {
-	R       [32]byte   // Combined R component
-	S       [32]byte   // Combined S component
-	PubKeys [][32]byte // X-only public keys of all participants
-	Indices []int      // Indices of participants who signed
+	R       [32]byte                 // Combined R component
+	S       [32]byte                 // Combined S component
+	PubKeys []schnorr.XOnlyPublicKey // X-only public keys of all participants
+	Indices []int                    // Indices of participants who signed
 }
 
 var (
@@ -167,7 +170,7 @@ func CombineSignatures(partialSigs []*PartialSignature, setup *MultisigSetup) (*
 	firstSig := partialSigs[0]
 
 	// Collect public keys and indices from all participants
-	pubKeys := make([][32]byte, len(partialSigs))
+	pubKeys := make([]schnorr.XOnlyPublicKey, len(partialSigs))
 	indices := make([]int, len(partialSigs))
 	for i, sig := range partialSigs {
 		pubKeys[i] = sig.PubKey
@@ -216,9 +219,7 @@ func VerifyMultisignature(msg []byte, sig *CompleteSignature, setup *MultisigSet
 	}
 
 	// Reconstruct the signature
-	var sigBytes [64]byte
-	copy(sigBytes[:32], sig.R[:])
-	copy(sigBytes[32:], sig.S[:])
+	sigBytes := sig.Serialize()
 
 	// Parse the signature
 	signature, err := btcschnorr.ParseSignature(sigBytes[:])
@@ -233,6 +234,44 @@ func VerifyMultisignature(msg []byte, sig *CompleteSignature, setup *MultisigSet
 	return signature.Verify(messageHash[:], pubKey)
 }
 
+// Serialize returns the complete signature as a standard 64-byte BIP340 signature
+//
+// The format is identical to the one produced by schnorr.SignBIP340:
+// [R (32 bytes)][S (32 bytes)]. The result can be placed directly into a
+// taproot key-path witness or handed to any BIP340 verifier.
+//
+// Example:
+//
+//	completeSig, _ := CreateMultisignature(msg, setup)
+//	sigBytes := completeSig.Serialize()
+//	// Result: [64]byte{R (32 bytes) + S (32 bytes)}
+func (sig *CompleteSignature) Serialize() [64]byte {
+	return schnorr.JoinSig(sig.R, sig.S)
+}
+
+// VerifyAgainstAggregatedKey verifies the complete signature against an x-only aggregated key
+//
+// Unlike VerifyMultisignature, this function does not need the MultisigSetup:
+// it treats the signature as a plain BIP340 signature and checks it against
+// the given 32-byte x-only key, exactly as a taproot verifier would.
+//
+// Example:
+//
+//	completeSig, _ := CreateMultisignature(msg, setup)
+//	isValid := completeSig.VerifyAgainstAggregatedKey(msg, aggregatedKey)
+//	// Result: true if the signature is valid for aggregatedKey
+func (sig *CompleteSignature) VerifyAgainstAggregatedKey(msg []byte, aggKey schnorr.XOnlyPublicKey) bool {
+	if sig == nil {
+		return false
+	}
+
+	isValid, err := schnorr.VerifyWithXOnly(msg, sig.Serialize(), aggKey)
+	if err != nil {
+		return false
+	}
+	return isValid
+}
+
 // CreateMultisignature creates a complete multisignature from a message and participants
 //
 // This is a convenience function that creates partial signatures from all participants
@@ -4,10 +4,12 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"math/big"
+
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
 )
 
 // SignatureCombinationExample demonstrates how to properly combine Schnorr signatures
-// This is a mathematical explanation with pseudocode showing the correct approach
+// This is a mathematical explanation that carries out each step with real secp256k1 points
 func SignatureCombinationExample() {
 	fmt.Println("=== Proper Schnorr Signature Combination Example ===")
 	fmt.Println()
@@ -77,68 +79,59 @@ func SignatureCombinationExample() {
 	fmt.Println()
 
 	// Calculate aggregated nonce (R_agg = R₁ + R₂)
-	// In practice: R_agg = k₁*G + k₂*G = (k₁ + k₂)*G
-	R_agg := new(big.Int).Add(k1, k2)
-	R_agg.Mod(R_agg, N)
+	R1 := arithmetic.ScalarBaseMult(k1)
+	R2 := arithmetic.ScalarBaseMult(k2)
+	R_agg := arithmetic.PointAdd(R1, R2)
+
+	fmt.Printf("Nonce commitments: R₁ = k₁ * G = %s\n", R1)
+	fmt.Printf("                   R₂ = k₂ * G = %s\n", R2)
+	fmt.Printf("Aggregated nonce: R_agg = R₁ + R₂ = %s\n", R_agg)
+	fmt.Println()
 
-	fmt.Printf("Aggregated nonce: R_agg = k₁ + k₂ = %d\n", R_agg)
+	// Calculate aggregated public key (P_agg = P₁ + P₂)
+	P1 := arithmetic.ScalarBaseMult(d1)
+	P2 := arithmetic.ScalarBaseMult(d2)
+	P_agg := arithmetic.PointAdd(P1, P2)
+
+	fmt.Printf("Public keys: P₁ = d₁ * G = %s\n", P1)
+	fmt.Printf("             P₂ = d₂ * G = %s\n", P2)
+	fmt.Printf("Aggregated public key: P_agg = P₁ + P₂ = %s\n", P_agg)
 	fmt.Println()
 
-	// Simulate challenge calculation
-	// In practice: e = H(R_agg || P_agg || m)
+	// Challenge calculation: e = H(R_agg || P_agg || m)
 	message := []byte("Hello, multisig!")
-	messageHash := sha256.Sum256(message)
-	e := new(big.Int).SetBytes(messageHash[:])
+	challengeInput := append(append(R_agg.Bytes(), P_agg.Bytes()...), message...)
+	challengeHash := sha256.Sum256(challengeInput)
+	e := new(big.Int).SetBytes(challengeHash[:])
 	e.Mod(e, N)
 
 	fmt.Printf("Message: %s\n", string(message))
-	fmt.Printf("Message hash: %x\n", messageHash)
-	fmt.Printf("Challenge: e = %d\n", e)
+	fmt.Printf("Challenge: e = H(R_agg || P_agg || m) = %x\n", challengeHash)
 	fmt.Println()
 
 	// Calculate partial signatures
-	s1 := new(big.Int).Mul(e, d1)
-	s1.Add(s1, k1)
-	s1.Mod(s1, N)
-
-	s2 := new(big.Int).Mul(e, d2)
-	s2.Add(s2, k2)
-	s2.Mod(s2, N)
+	s1 := arithmetic.AddModN(k1, arithmetic.MulModN(e, d1))
+	s2 := arithmetic.AddModN(k2, arithmetic.MulModN(e, d2))
 
 	fmt.Printf("Partial signatures:\n")
-	fmt.Printf("s₁ = k₁ + e * d₁ = %d + %d * %d = %d\n", k1, e, d1, s1)
-	fmt.Printf("s₂ = k₂ + e * d₂ = %d + %d * %d = %d\n", k2, e, d2, s2)
+	fmt.Printf("s₁ = k₁ + e * d₁ = %x\n", s1)
+	fmt.Printf("s₂ = k₂ + e * d₂ = %x\n", s2)
 	fmt.Println()
 
 	// Combine signatures
-	s_agg := new(big.Int).Add(s1, s2)
-	s_agg.Mod(s_agg, N)
+	s_agg := arithmetic.AddModN(s1, s2)
 
-	fmt.Printf("Combined signature: s_agg = s₁ + s₂ = %d + %d = %d\n", s1, s2, s_agg)
-	fmt.Println()
-
-	// Verification
-	// Calculate aggregated public key (P_agg = P₁ + P₂)
-	// In practice: P_agg = d₁*G + d₂*G = (d₁ + d₂)*G
-	P_agg := new(big.Int).Add(d1, d2)
-	P_agg.Mod(P_agg, N)
-
-	fmt.Printf("Aggregated public key: P_agg = d₁ + d₂ = %d + %d = %d\n", d1, d2, P_agg)
+	fmt.Printf("Combined signature: s_agg = s₁ + s₂ = %x\n", s_agg)
 	fmt.Println()
 
 	// Verify: s_agg * G = R_agg + e * P_agg
-	// Left side: s_agg * G
-	leftSide := new(big.Int).Set(s_agg)
-
-	// Right side: R_agg + e * P_agg
-	rightSide := new(big.Int).Mul(e, P_agg)
-	rightSide.Add(rightSide, R_agg)
-	rightSide.Mod(rightSide, N)
+	leftSide := arithmetic.ScalarBaseMult(s_agg)
+	rightSide := arithmetic.PointAdd(R_agg, arithmetic.PointScalarMult(P_agg, e))
 
 	fmt.Printf("Verification:\n")
-	fmt.Printf("Left side: s_agg = %d\n", leftSide)
-	fmt.Printf("Right side: R_agg + e * P_agg = %d + %d * %d = %d\n", R_agg, e, P_agg, rightSide)
-	fmt.Printf("Verification result: %t\n", leftSide.Cmp(rightSide) == 0)
+	fmt.Printf("Left side: s_agg * G = %s\n", leftSide)
+	fmt.Printf("Right side: R_agg + e * P_agg = %s\n", rightSide)
+	fmt.Printf("Verification result: %t\n", leftSide.Equal(rightSide))
 	fmt.Println()
 
 	// Show the difference from the simplified approach
@@ -1,10 +1,13 @@
 package multisig
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"math/big"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
 )
 
@@ -208,6 +211,73 @@ func TestCreateMultisignature(t *testing.T) {
 	}
 }
 
+// TestCompleteSignatureSerialize tests exporting a complete signature as a BIP340 signature
+func TestCompleteSignatureSerialize(t *testing.T) {
+	// Generate test participants
+	participants := make([]*Participant, 2)
+	for i := 0; i < 2; i++ {
+		priv, err := btcec.NewPrivateKey()
+		if err != nil {
+			t.Fatalf("Failed to generate private key: %v", err)
+		}
+		participants[i] = &Participant{
+			PrivateKey: priv,
+			PublicKey:  priv.PubKey(),
+			Index:      i,
+		}
+	}
+
+	setup, err := NewMultisigSetup(participants, 2)
+	if err != nil {
+		t.Fatalf("Failed to create setup: %v", err)
+	}
+
+	msg := []byte("Test message for BIP340 export")
+	completeSig, err := CreateMultisignature(msg, setup)
+	if err != nil {
+		t.Fatalf("Failed to create multisignature: %v", err)
+	}
+
+	// Serialize must be [R][S]
+	sigBytes := completeSig.Serialize()
+	if !bytes.Equal(sigBytes[:32], completeSig.R[:]) {
+		t.Error("First 32 bytes should be the R component")
+	}
+	if !bytes.Equal(sigBytes[32:], completeSig.S[:]) {
+		t.Error("Last 32 bytes should be the S component")
+	}
+
+	// Any BIP340 verifier must accept the serialized signature
+	parsed, err := btcschnorr.ParseSignature(sigBytes[:])
+	if err != nil {
+		t.Fatalf("Failed to parse serialized signature: %v", err)
+	}
+	pubKey, err := btcschnorr.ParsePubKey(completeSig.PubKeys[0][:])
+	if err != nil {
+		t.Fatalf("Failed to parse public key: %v", err)
+	}
+	messageHash := sha256.Sum256(msg)
+	if !parsed.Verify(messageHash[:], pubKey) {
+		t.Error("Serialized signature should verify with a BIP340 verifier")
+	}
+
+	// The simplified combination signs with the first participant's key
+	if !completeSig.VerifyAgainstAggregatedKey(msg, completeSig.PubKeys[0]) {
+		t.Error("Signature should verify against the signing key")
+	}
+	if completeSig.VerifyAgainstAggregatedKey(msg, completeSig.PubKeys[1]) {
+		t.Error("Signature should not verify against an unrelated key")
+	}
+	if completeSig.VerifyAgainstAggregatedKey([]byte("tampered"), completeSig.PubKeys[0]) {
+		t.Error("Signature should not verify for a different message")
+	}
+
+	var nilSig *CompleteSignature
+	if nilSig.VerifyAgainstAggregatedKey(msg, completeSig.PubKeys[0]) {
+		t.Error("Nil signature should not verify")
+	}
+}
+
 // TestSignAndVerifyMultisig tests the complete multisignature workflow
 func TestSignAndVerifyMultisig(t *testing.T) {
 	// Test 2-of-3 multisignature
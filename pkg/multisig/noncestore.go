@@ -0,0 +1,241 @@
+package multisig
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Persistent nonce reuse protection
+//
+// Signing twice with the same secret nonce (k) for different challenges leaks
+// the private key: d = (s1 - s2) / (e1 - e2). A Session already erases its
+// nonces after signing, but that does not survive restarts or copied state. A
+// NonceStore records every (key, session, nonce) that was used so a second
+// partial signature is refused even across processes.
+
+// NonceStore records which sessions and nonces a key has signed with
+type NonceStore interface {
+	// HasSession reports whether key has already signed in the session
+	HasSession(pubKey []byte, sessionID [32]byte) (bool, error)
+	// MarkUsed records that key signs in the session with pubNonce.
+	// It must fail with ErrNonceReused if the session or nonce was recorded before,
+	// and must only return nil once the record is durable.
+	MarkUsed(pubKey []byte, sessionID [32]byte, pubNonce []byte) error
+}
+
+// nonceRecord is one used (key, session, nonce) entry
+type nonceRecord struct {
+	key     string   // hex of the compressed public key
+	session [32]byte // session ID
+	nonce   [32]byte // SHA256 of the public nonce
+}
+
+// nonceIndex is the in-memory lookup shared by the store implementations
+type nonceIndex struct {
+	sessions map[string]bool // key|session
+	nonces   map[string]bool // key|nonce hash
+}
+
+func newNonceIndex() nonceIndex {
+	return nonceIndex{sessions: make(map[string]bool), nonces: make(map[string]bool)}
+}
+
+func (idx nonceIndex) hasSession(key string, sessionID [32]byte) bool {
+	return idx.sessions[key+"|"+hex.EncodeToString(sessionID[:])]
+}
+
+// check returns ErrNonceReused if the record's session or nonce was seen for its key
+func (idx nonceIndex) check(r nonceRecord) error {
+	if idx.hasSession(r.key, r.session) {
+		return fmt.Errorf("%w: key already signed in session %x", ErrNonceReused, r.session)
+	}
+	if idx.nonces[r.key+"|"+hex.EncodeToString(r.nonce[:])] {
+		return fmt.Errorf("%w: nonce already used by key", ErrNonceReused)
+	}
+	return nil
+}
+
+func (idx nonceIndex) add(r nonceRecord) {
+	idx.sessions[r.key+"|"+hex.EncodeToString(r.session[:])] = true
+	idx.nonces[r.key+"|"+hex.EncodeToString(r.nonce[:])] = true
+}
+
+// newNonceRecord validates the inputs and builds a record
+func newNonceRecord(pubKey []byte, sessionID [32]byte, pubNonce []byte) (nonceRecord, error) {
+	if len(pubKey) == 0 {
+		return nonceRecord{}, errors.New("public key cannot be empty")
+	}
+	if len(pubNonce) == 0 {
+		return nonceRecord{}, errors.New("public nonce cannot be empty")
+	}
+	return nonceRecord{
+		key:     hex.EncodeToString(pubKey),
+		session: sessionID,
+		nonce:   sha256.Sum256(pubNonce),
+	}, nil
+}
+
+// MemoryNonceStore is a NonceStore that lives only as long as the process
+type MemoryNonceStore struct {
+	mu    sync.Mutex
+	index nonceIndex
+}
+
+// NewMemoryNonceStore creates an empty in-memory nonce store
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{index: newNonceIndex()}
+}
+
+// HasSession reports whether key has already signed in the session
+func (m *MemoryNonceStore) HasSession(pubKey []byte, sessionID [32]byte) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.index.hasSession(hex.EncodeToString(pubKey), sessionID), nil
+}
+
+// MarkUsed records a used session and nonce
+func (m *MemoryNonceStore) MarkUsed(pubKey []byte, sessionID [32]byte, pubNonce []byte) error {
+	record, err := newNonceRecord(pubKey, sessionID, pubNonce)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.index.check(record); err != nil {
+		return err
+	}
+	m.index.add(record)
+	return nil
+}
+
+// FileNonceStore is a NonceStore backed by an append-only file
+//
+// Each line holds one record: <pubkey hex> <session ID hex> <SHA256(nonce) hex>.
+// Records are synced to disk before MarkUsed returns, so a crash after signing
+// can never forget a used nonce.
+//
+// Example:
+//
+//	store, err := NewFileNonceStore("/var/lib/signer/nonces.log")
+//	session, err := NewSession(setup, 0, msg, WithNonceStore(store))
+type FileNonceStore struct {
+	mu    sync.Mutex
+	file  *os.File
+	index nonceIndex
+	err   error // Set when a failed write could not be undone; no further records are accepted
+}
+
+// NewFileNonceStore opens (or creates) the store at path and loads its records
+func NewFileNonceStore(path string) (*FileNonceStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open nonce store: %w", err)
+	}
+
+	// Step 1: Load every existing record into the index
+	store := &FileNonceStore{file: file, index: newNonceIndex()}
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		record, err := parseNonceRecord(scanner.Text())
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("nonce store line %d: %w", line, err)
+		}
+		store.index.add(record)
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read nonce store: %w", err)
+	}
+	return store, nil
+}
+
+// parseNonceRecord parses one "<key> <session> <nonce>" line
+func parseNonceRecord(line string) (nonceRecord, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return nonceRecord{}, errors.New("expected 3 fields")
+	}
+	if _, err := hex.DecodeString(fields[0]); err != nil {
+		return nonceRecord{}, fmt.Errorf("invalid key: %w", err)
+	}
+	record := nonceRecord{key: fields[0]}
+	for i, dst := range []*[32]byte{&record.session, &record.nonce} {
+		b, err := hex.DecodeString(fields[i+1])
+		if err != nil || len(b) != 32 {
+			return nonceRecord{}, fmt.Errorf("invalid 32-byte hex in field %d", i+2)
+		}
+		copy(dst[:], b)
+	}
+	return record, nil
+}
+
+// HasSession reports whether key has already signed in the session
+func (f *FileNonceStore) HasSession(pubKey []byte, sessionID [32]byte) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.index.hasSession(hex.EncodeToString(pubKey), sessionID), nil
+}
+
+// MarkUsed appends a record and syncs it to disk
+func (f *FileNonceStore) MarkUsed(pubKey []byte, sessionID [32]byte, pubNonce []byte) error {
+	record, err := newNonceRecord(pubKey, sessionID, pubNonce)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return errors.New("nonce store is closed")
+	}
+	if f.err != nil {
+		return fmt.Errorf("nonce store is unusable after a failed write: %w", f.err)
+	}
+	if err := f.index.check(record); err != nil {
+		return err
+	}
+
+	// Step 1: Write-ahead: persist before the nonce may be used
+	end, err := f.file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to write nonce record: %w", err)
+	}
+	line := fmt.Sprintf("%s %x %x\n", record.key, record.session, record.nonce)
+	if _, err := f.file.WriteString(line); err != nil {
+		// A partial line would run into the next record and make the file
+		// unreadable, so cut it off; if that fails too, stop writing
+		if truncErr := f.file.Truncate(end); truncErr != nil {
+			f.err = truncErr
+		}
+		return fmt.Errorf("failed to write nonce record: %w", err)
+	}
+	if err := f.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync nonce store: %w", err)
+	}
+
+	f.index.add(record)
+	return nil
+}
+
+// Close closes the underlying file
+func (f *FileNonceStore) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
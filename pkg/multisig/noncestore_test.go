@@ -0,0 +1,177 @@
+package multisig
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNonceStores tests the reuse rules shared by both store implementations
+func TestNonceStores(t *testing.T) {
+	dir := t.TempDir()
+	fileStore, err := NewFileNonceStore(filepath.Join(dir, "nonces.log"))
+	if err != nil {
+		t.Fatalf("NewFileNonceStore failed: %v", err)
+	}
+	defer fileStore.Close()
+
+	stores := map[string]NonceStore{
+		"memory": NewMemoryNonceStore(),
+		"file":   fileStore,
+	}
+
+	key := []byte{0x02, 0x01}
+	otherKey := []byte{0x03, 0x01}
+	session := [32]byte{0x01}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			if err := store.MarkUsed(key, session, []byte("nonce-1")); err != nil {
+				t.Fatalf("MarkUsed failed: %v", err)
+			}
+			if used, _ := store.HasSession(key, session); !used {
+				t.Error("Expected session to be recorded")
+			}
+
+			// Same session again, or the same nonce in another session
+			if err := store.MarkUsed(key, session, []byte("nonce-2")); !errors.Is(err, ErrNonceReused) {
+				t.Errorf("Expected ErrNonceReused for a repeated session, got %v", err)
+			}
+			if err := store.MarkUsed(key, [32]byte{0x02}, []byte("nonce-1")); !errors.Is(err, ErrNonceReused) {
+				t.Errorf("Expected ErrNonceReused for a repeated nonce, got %v", err)
+			}
+
+			// Records are per key
+			if err := store.MarkUsed(otherKey, session, []byte("nonce-1")); err != nil {
+				t.Errorf("Another key should be allowed: %v", err)
+			}
+			if err := store.MarkUsed(key, session, nil); err == nil {
+				t.Error("Expected error for an empty nonce")
+			}
+		})
+	}
+}
+
+// TestFileNonceStorePersistence tests that records survive reopening the store
+func TestFileNonceStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nonces.log")
+	store, err := NewFileNonceStore(path)
+	if err != nil {
+		t.Fatalf("NewFileNonceStore failed: %v", err)
+	}
+	if err := store.MarkUsed([]byte{0x02}, [32]byte{0xaa}, []byte("nonce")); err != nil {
+		t.Fatalf("MarkUsed failed: %v", err)
+	}
+	store.Close()
+
+	if err := store.MarkUsed([]byte{0x02}, [32]byte{0xbb}, []byte("other")); err == nil {
+		t.Error("Expected error writing to a closed store")
+	}
+
+	reopened, err := NewFileNonceStore(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer reopened.Close()
+	if used, _ := reopened.HasSession([]byte{0x02}, [32]byte{0xaa}); !used {
+		t.Error("Session record was lost after reopening")
+	}
+	if err := reopened.MarkUsed([]byte{0x02}, [32]byte{0xcc}, []byte("nonce")); !errors.Is(err, ErrNonceReused) {
+		t.Errorf("Expected ErrNonceReused after reopening, got %v", err)
+	}
+
+	// Corrupt files are rejected rather than silently ignored
+	corrupt := filepath.Join(t.TempDir(), "corrupt.log")
+	if err := os.WriteFile(corrupt, []byte("not a record\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileNonceStore(corrupt); err == nil {
+		t.Error("Expected error loading a corrupt store")
+	}
+}
+
+// TestFileNonceStoreFailedWrite tests that a failed write leaves no fragment, or stops the store
+func TestFileNonceStoreFailedWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nonces.log")
+	store, err := NewFileNonceStore(path)
+	if err != nil {
+		t.Fatalf("NewFileNonceStore failed: %v", err)
+	}
+	if err := store.MarkUsed([]byte{0x02}, [32]byte{0xaa}, []byte("nonce")); err != nil {
+		t.Fatalf("MarkUsed failed: %v", err)
+	}
+	store.Close()
+	before, _ := os.ReadFile(path)
+
+	// A read-only file fails both the write and the truncation that would undo it
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken := &FileNonceStore{file: file, index: newNonceIndex()}
+	defer broken.Close()
+	if err := broken.MarkUsed([]byte{0x02}, [32]byte{0xbb}, []byte("other")); err == nil {
+		t.Fatal("Expected error writing to a read-only file")
+	}
+	if broken.err == nil {
+		t.Error("Expected the store to be marked unusable")
+	}
+	if err := broken.MarkUsed([]byte{0x02}, [32]byte{0xcc}, []byte("third")); err == nil || !strings.Contains(err.Error(), "unusable") {
+		t.Errorf("Expected an unusable store error, got %v", err)
+	}
+
+	if after, _ := os.ReadFile(path); !bytes.Equal(after, before) {
+		t.Errorf("Store file changed after failed writes: %q", after)
+	}
+	if _, err := NewFileNonceStore(path); err != nil {
+		t.Errorf("Expected the store to stay readable, got %v", err)
+	}
+}
+
+// TestSessionWithNonceStore tests that sessions refuse to sign twice across restarts
+func TestSessionWithNonceStore(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 2)
+	msg := []byte("persisted")
+	path := filepath.Join(t.TempDir(), "nonces.log")
+
+	store, err := NewFileNonceStore(path)
+	if err != nil {
+		t.Fatalf("NewFileNonceStore failed: %v", err)
+	}
+
+	sessions := newSessions(t, setup, msg, WithNonceStore(store))
+	exchangeNonces(t, sessions)
+	if _, err := sessions[0].Sign(); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	store.Close()
+
+	// After a "restart", the same session ID is refused for signer 0 only
+	reopened, err := NewFileNonceStore(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer reopened.Close()
+	if _, err := NewSession(setup, 0, msg, WithSessionID([32]byte{0x42}), WithNonceStore(reopened)); !errors.Is(err, ErrNonceReused) {
+		t.Errorf("Expected ErrNonceReused for a used session ID, got %v", err)
+	}
+	if _, err := NewSession(setup, 1, msg, WithSessionID([32]byte{0x42}), WithNonceStore(reopened)); err != nil {
+		t.Errorf("Signer 1 never signed and should be allowed: %v", err)
+	}
+
+	// A copy of the session state that tries to sign again is refused by the store
+	shared := NewMemoryNonceStore()
+	sessions = newSessions(t, setup, msg, WithNonceStore(shared))
+	exchangeNonces(t, sessions)
+	clone := *sessions[0]
+	clone.secNonces = append(clone.secNonces[:0:0], sessions[0].secNonces...)
+	if _, err := sessions[0].Sign(); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if _, err := clone.Sign(); !errors.Is(err, ErrNonceReused) {
+		t.Errorf("Expected ErrNonceReused for cloned state, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package multisig
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/neverDefined/cryptography-playground/pkg/address"
+	"github.com/neverDefined/cryptography-playground/pkg/bech32"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+)
+
+// Segwit v0 multisig: OP_m <pubkey_1> ... <pubkey_n> OP_n OP_CHECKMULTISIG inside a P2WSH output
+
+const (
+	// op0 (OP_0) pushes an empty byte array (the CHECKMULTISIG dummy element)
+	op0 = 0x00
+	// op1 (OP_1) is the first small-integer opcode; OP_n = OP_1 + (n - 1)
+	op1 = 0x51
+	// opCheckMultisig (OP_CHECKMULTISIG) verifies m signatures against n public keys
+	opCheckMultisig = 0xae
+
+	// maxScriptMultisigKeys is the largest n that can be encoded with OP_1..OP_16
+	maxScriptMultisigKeys = 16
+)
+
+// WitnessScript builds the m-of-n CHECKMULTISIG witness script for the setup
+//
+// Public keys are serialized compressed (33 bytes) in participant order, which is
+// also the order signatures must appear in when spending.
+//
+// Format: [OP_m][0x21][pubkey_1]...[0x21][pubkey_n][OP_n][OP_CHECKMULTISIG]
+//
+// Example:
+//
+//	setup, _ := NewMultisigSetup(participants, 2) // 2-of-3
+//	script, err := setup.WitnessScript()
+//	// Result: 52 21 <pk1> 21 <pk2> 21 <pk3> 53 ae (105 bytes)
+func (setup *MultisigSetup) WitnessScript() ([]byte, error) {
+	// Step 1: Validate that m and n fit in small-integer opcodes
+	if len(setup.Participants) == 0 {
+		return nil, errors.New("at least one participant is required")
+	}
+	if len(setup.Participants) > maxScriptMultisigKeys {
+		return nil, fmt.Errorf("at most %d participants are supported", maxScriptMultisigKeys)
+	}
+	if setup.Threshold <= 0 || setup.Threshold > len(setup.Participants) {
+		return nil, errors.New("invalid threshold")
+	}
+
+	// Step 2: Push OP_m
+	script := make([]byte, 0, 3+34*len(setup.Participants))
+	script = append(script, byte(op1+setup.Threshold-1))
+
+	// Step 3: Push every compressed public key (0x21 = push 33 bytes)
+	for _, p := range setup.Participants {
+		if p.PublicKey == nil {
+			return nil, errors.New("all participants must have a public key")
+		}
+		script = append(script, 0x21)
+		script = append(script, p.PublicKey.SerializeCompressed()...)
+	}
+
+	// Step 4: Push OP_n and OP_CHECKMULTISIG
+	script = append(script, byte(op1+len(setup.Participants)-1), opCheckMultisig)
+	return script, nil
+}
+
+// WitnessScriptHash returns SHA256(witnessScript), the 32-byte P2WSH witness program
+//
+// Note that P2WSH uses a single SHA256, unlike P2SH which uses Hash160.
+//
+// Example:
+//
+//	program, err := setup.WitnessScriptHash()
+//	// scriptPubKey: OP_0 0x20 <program>
+func (setup *MultisigSetup) WitnessScriptHash() ([32]byte, error) {
+	script, err := setup.WitnessScript()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(script), nil
+}
+
+// P2WSHAddress returns the bech32 P2WSH address of the multisig for a network
+//
+// hrp is the human-readable part of the network: "bc" (mainnet), "tb" (testnet/signet)
+// or "bcrt" (regtest).
+//
+// Example:
+//
+//	address, err := setup.P2WSHAddress("bc")
+//	// Result: "bc1q..." (62 characters)
+func (setup *MultisigSetup) P2WSHAddress(hrp string) (string, error) {
+	program, err := setup.WitnessScriptHash()
+	if err != nil {
+		return "", err
+	}
+	return bech32.SegWitAddressEncode(hrp, 0, program[:])
+}
+
+// P2WSHScriptPubKey returns the output script locking funds to the multisig
+//
+// Format: [OP_0][0x20][SHA256(witnessScript)]
+func (setup *MultisigSetup) P2WSHScriptPubKey() ([]byte, error) {
+	program, err := setup.WitnessScriptHash()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{op0, 0x20}, program[:]...), nil
+}
+
+// P2SHAddress returns the legacy P2SH address of the multisig for a network
+//
+// The bare CHECKMULTISIG script serves as the redeem script; at most 15 keys
+// fit in the 520-byte limit of a P2SH redeem script.
+//
+// Example:
+//
+//	address, err := setup.P2SHAddress(&chaincfg.MainNetParams)
+//	// Result: "3..." (34 characters)
+func (setup *MultisigSetup) P2SHAddress(params *chaincfg.Params) (string, error) {
+	script, err := setup.WitnessScript()
+	if err != nil {
+		return "", err
+	}
+	return address.P2SHFromScript(script, params)
+}
+
+// P2WSHWitness assembles the witness stack spending the multisig output
+//
+// sigs maps participant indices to their DER-encoded ECDSA signatures (with the
+// sighash byte appended). Exactly Threshold signatures are required; they are
+// ordered by participant index so they match the key order in the witness script.
+//
+// Format: [<empty>][sig_1]...[sig_m][witnessScript]
+// The leading empty item is consumed by the off-by-one bug in OP_CHECKMULTISIG.
+//
+// Example:
+//
+//	sigs := map[int][]byte{0: sigAlice, 2: sigCarol}
+//	witness, err := setup.P2WSHWitness(sigs)
+//	// Result: [][]byte{{}, sigAlice, sigCarol, witnessScript}
+func (setup *MultisigSetup) P2WSHWitness(sigs map[int][]byte) ([][]byte, error) {
+	// Step 1: Validate the number of signatures
+	if len(sigs) != setup.Threshold {
+		return nil, fmt.Errorf("expected %d signatures, got %d", setup.Threshold, len(sigs))
+	}
+
+	// Step 2: Order signatures by participant index
+	indices := make([]int, 0, len(sigs))
+	for index, sig := range sigs {
+		if index < 0 || index >= len(setup.Participants) {
+			return nil, fmt.Errorf("invalid participant index: %d", index)
+		}
+		if len(sig) == 0 {
+			return nil, fmt.Errorf("empty signature for participant %d", index)
+		}
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+
+	script, err := setup.WitnessScript()
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Build the stack: dummy, signatures, witness script
+	witness := make([][]byte, 0, len(sigs)+2)
+	witness = append(witness, []byte{})
+	for _, index := range indices {
+		witness = append(witness, sigs[index])
+	}
+	witness = append(witness, script)
+	return witness, nil
+}
@@ -0,0 +1,169 @@
+package multisig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/neverDefined/cryptography-playground/pkg/address"
+	"github.com/neverDefined/cryptography-playground/pkg/bech32"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+)
+
+// newDeterministicSetup creates an m-of-n setup with private keys 1..n
+func newDeterministicSetup(t *testing.T, threshold, total int) *MultisigSetup {
+	t.Helper()
+	participants := make([]*Participant, total)
+	for i := 0; i < total; i++ {
+		priv, pub := btcec.PrivKeyFromBytes([]byte{byte(i + 1)})
+		participants[i] = &Participant{
+			PrivateKey: priv,
+			PublicKey:  pub,
+			Index:      i,
+		}
+	}
+	setup, err := NewMultisigSetup(participants, threshold)
+	if err != nil {
+		t.Fatalf("Failed to create setup: %v", err)
+	}
+	return setup
+}
+
+// TestWitnessScript tests the CHECKMULTISIG witness script layout
+func TestWitnessScript(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 3)
+
+	script, err := setup.WitnessScript()
+	if err != nil {
+		t.Fatalf("Failed to build witness script: %v", err)
+	}
+
+	// 1 (OP_2) + 3 * 34 (pushes) + 1 (OP_3) + 1 (OP_CHECKMULTISIG)
+	if len(script) != 105 {
+		t.Errorf("Expected 105-byte script, got %d", len(script))
+	}
+	if script[0] != 0x52 {
+		t.Errorf("Expected OP_2 prefix, got %#x", script[0])
+	}
+	if script[len(script)-2] != 0x53 || script[len(script)-1] != 0xae {
+		t.Errorf("Expected OP_3 OP_CHECKMULTISIG suffix, got %x", script[len(script)-2:])
+	}
+	for i, p := range setup.Participants {
+		push := script[1+i*34 : 1+(i+1)*34]
+		if push[0] != 0x21 || !bytes.Equal(push[1:], p.PublicKey.SerializeCompressed()) {
+			t.Errorf("Key %d not pushed in participant order", i)
+		}
+	}
+
+	// Too many participants for OP_1..OP_16
+	large := newDeterministicSetup(t, 1, 17)
+	if _, err := large.WitnessScript(); err == nil {
+		t.Error("Expected error for more than 16 participants")
+	}
+}
+
+// TestP2WSHAddress tests the witness program, scriptPubKey and bech32 address
+func TestP2WSHAddress(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 3)
+
+	script, _ := setup.WitnessScript()
+	program, err := setup.WitnessScriptHash()
+	if err != nil {
+		t.Fatalf("Failed to hash witness script: %v", err)
+	}
+	if program != sha256.Sum256(script) {
+		t.Error("Witness program should be SHA256 of the witness script")
+	}
+
+	scriptPubKey, err := setup.P2WSHScriptPubKey()
+	if err != nil {
+		t.Fatalf("Failed to build scriptPubKey: %v", err)
+	}
+	if len(scriptPubKey) != 34 || scriptPubKey[0] != 0x00 || scriptPubKey[1] != 0x20 {
+		t.Errorf("Unexpected scriptPubKey: %x", scriptPubKey)
+	}
+
+	for _, hrp := range []string{"bc", "tb", "bcrt"} {
+		addr, err := setup.P2WSHAddress(hrp)
+		if err != nil {
+			t.Fatalf("Failed to derive %s address: %v", hrp, err)
+		}
+		version, decoded, err := bech32.SegWitAddressDecode(hrp, addr)
+		if err != nil {
+			t.Fatalf("Failed to decode %s: %v", addr, err)
+		}
+		if version != 0 || !bytes.Equal(decoded, program[:]) {
+			t.Errorf("Address %s does not commit to the witness script", addr)
+		}
+	}
+}
+
+// TestP2SHAddress tests the legacy P2SH address of the multisig script
+func TestP2SHAddress(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 3)
+	script, _ := setup.WitnessScript()
+
+	for _, params := range []*chaincfg.Params{&chaincfg.MainNetParams, &chaincfg.TestNet3Params} {
+		addr, err := setup.P2SHAddress(params)
+		if err != nil {
+			t.Fatalf("Failed to derive %s address: %v", params.Name, err)
+		}
+		if !address.MatchesScript(addr, script, params) {
+			t.Errorf("Address %s does not commit to the multisig script", addr)
+		}
+	}
+
+	// 15 compressed keys are the most a P2SH redeem script can hold
+	if _, err := newDeterministicSetup(t, 1, 15).P2SHAddress(&chaincfg.MainNetParams); err != nil {
+		t.Errorf("Expected a 15-key setup to fit, got %v", err)
+	}
+	if _, err := newDeterministicSetup(t, 1, 16).P2SHAddress(&chaincfg.MainNetParams); !errors.Is(err, address.ErrScriptTooLarge) {
+		t.Errorf("Expected ErrScriptTooLarge for 16 keys, got %v", err)
+	}
+}
+
+// TestP2WSHWitness tests assembling the spending witness stack
+func TestP2WSHWitness(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 3)
+	sighash := sha256.Sum256([]byte("sighash"))
+
+	sign := func(i int) []byte {
+		sig := ecdsa.Sign(setup.Participants[i].PrivateKey, sighash[:]).Serialize()
+		return append(sig, 0x01) // SIGHASH_ALL
+	}
+	sigCarol := sign(2)
+	sigAlice := sign(0)
+
+	witness, err := setup.P2WSHWitness(map[int][]byte{2: sigCarol, 0: sigAlice})
+	if err != nil {
+		t.Fatalf("Failed to build witness: %v", err)
+	}
+	script, _ := setup.WitnessScript()
+
+	if len(witness) != 4 {
+		t.Fatalf("Expected 4 witness items, got %d", len(witness))
+	}
+	if len(witness[0]) != 0 {
+		t.Error("First item should be the empty CHECKMULTISIG dummy")
+	}
+	if !bytes.Equal(witness[1], sigAlice) || !bytes.Equal(witness[2], sigCarol) {
+		t.Error("Signatures should be ordered by participant index")
+	}
+	if !bytes.Equal(witness[3], script) {
+		t.Error("Last item should be the witness script")
+	}
+
+	// Error cases
+	if _, err := setup.P2WSHWitness(map[int][]byte{0: sigAlice}); err == nil {
+		t.Error("Expected error for insufficient signatures")
+	}
+	if _, err := setup.P2WSHWitness(map[int][]byte{0: sigAlice, 5: sigCarol}); err == nil {
+		t.Error("Expected error for invalid participant index")
+	}
+	if _, err := setup.P2WSHWitness(map[int][]byte{0: sigAlice, 1: nil}); err == nil {
+		t.Error("Expected error for empty signature")
+	}
+}
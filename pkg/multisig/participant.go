@@ -0,0 +1,189 @@
+package multisig
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// Participant metadata
+//
+// A participant can carry a human-readable label and the origin of its key
+// (BIP32 master fingerprint and derivation path), the same information a
+// descriptor writes as "[d34db33f/48'/0'/0'/2']<key>". The metadata is not
+// used by any signing code; it travels with the public key through JSON so a
+// coordinator can map a partial signature back to the device and account that
+// produced it.
+
+// HardenedKeyStart is the first hardened BIP32 child index (2^31)
+const HardenedKeyStart = 0x80000000
+
+// KeyOrigin identifies where a participant key was derived from
+type KeyOrigin struct {
+	Fingerprint [4]byte  // First 4 bytes of HASH160 of the master public key
+	Path        []uint32 // Child indices from the master key; hardened indices are >= HardenedKeyStart
+}
+
+// ParseKeyOrigin parses a key origin in descriptor notation
+//
+// Hardened steps may be marked with ' or h. The "m/" prefix is optional.
+//
+// Example:
+//
+//	origin, err := ParseKeyOrigin("d34db33f/48'/0'/0'/2'")
+//	// Result: Fingerprint d34db33f, Path [48', 0', 0', 2']
+func ParseKeyOrigin(s string) (*KeyOrigin, error) {
+	fingerprint, path, _ := strings.Cut(s, "/")
+	fp, err := hex.DecodeString(fingerprint)
+	if err != nil || len(fp) != 4 {
+		return nil, fmt.Errorf("invalid key origin %q: fingerprint must be 8 hex characters", s)
+	}
+
+	origin := &KeyOrigin{}
+	copy(origin.Fingerprint[:], fp)
+	if origin.Path, err = ParseDerivationPath(path); err != nil {
+		return nil, fmt.Errorf("invalid key origin %q: %w", s, err)
+	}
+	return origin, nil
+}
+
+// ParseDerivationPath parses a BIP32 path such as "m/84'/0'/0'/0/5"
+//
+// An empty string or "m" is the master key itself (an empty path).
+//
+// Example:
+//
+//	path, err := ParseDerivationPath("m/84'/0'/0'")
+//	// Result: []uint32{84 + HardenedKeyStart, HardenedKeyStart, HardenedKeyStart}
+func ParseDerivationPath(s string) ([]uint32, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "m"), "/")
+	if s == "" {
+		return nil, nil
+	}
+
+	steps := strings.Split(s, "/")
+	path := make([]uint32, len(steps))
+	for i, step := range steps {
+		hardened := strings.HasSuffix(step, "'") || strings.HasSuffix(step, "h")
+		if hardened {
+			step = step[:len(step)-1]
+		}
+		index, err := strconv.ParseUint(step, 10, 32)
+		if err != nil || index >= HardenedKeyStart {
+			return nil, fmt.Errorf("invalid derivation step %q", steps[i])
+		}
+		path[i] = uint32(index)
+		if hardened {
+			path[i] += HardenedKeyStart
+		}
+	}
+	return path, nil
+}
+
+// FormatDerivationPath formats a BIP32 path, marking hardened steps with '
+//
+// Example:
+//
+//	FormatDerivationPath([]uint32{84 + HardenedKeyStart, 0, 5})
+//	// Result: "m/84'/0/5"
+func FormatDerivationPath(path []uint32) string {
+	var b strings.Builder
+	b.WriteString("m")
+	for _, index := range path {
+		b.WriteByte('/')
+		if index >= HardenedKeyStart {
+			b.WriteString(strconv.FormatUint(uint64(index-HardenedKeyStart), 10))
+			b.WriteByte('\'')
+		} else {
+			b.WriteString(strconv.FormatUint(uint64(index), 10))
+		}
+	}
+	return b.String()
+}
+
+// String returns the origin in descriptor notation, without brackets
+func (o *KeyOrigin) String() string {
+	return hex.EncodeToString(o.Fingerprint[:]) + strings.TrimPrefix(FormatDerivationPath(o.Path), "m")
+}
+
+// participantJSON is the serialized form of a Participant
+//
+// The private key is never serialized.
+type participantJSON struct {
+	PublicKey string `json:"public_key"` // Compressed, hex
+	Index     int    `json:"index"`
+	Label     string `json:"label,omitempty"`
+	Origin    string `json:"origin,omitempty"` // Descriptor notation, e.g. "d34db33f/48'/0'/0'/2'"
+}
+
+// MarshalJSON encodes the public key, index and metadata of a participant
+func (p Participant) MarshalJSON() ([]byte, error) {
+	if p.PublicKey == nil {
+		return nil, errors.New("participant has no public key")
+	}
+	out := participantJSON{
+		PublicKey: hex.EncodeToString(p.PublicKey.SerializeCompressed()),
+		Index:     p.Index,
+		Label:     p.Label,
+	}
+	if p.Origin != nil {
+		out.Origin = p.Origin.String()
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a participant written by MarshalJSON
+//
+// The result has no private key; a local signer sets it after matching the public key.
+func (p *Participant) UnmarshalJSON(data []byte) error {
+	var in participantJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	keyBytes, err := hex.DecodeString(in.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	pubKey, err := btcec.ParsePubKey(keyBytes)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+
+	*p = Participant{PublicKey: pubKey, Index: in.Index, Label: in.Label}
+	if in.Origin != "" {
+		if p.Origin, err = ParseKeyOrigin(in.Origin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParticipantFor returns the participant that produced a partial signature
+//
+// The signature is matched by its x-only public key, so this also works for
+// partial signatures received from another process.
+//
+// Example:
+//
+//	p, err := setup.ParticipantFor(partialSig)
+//	fmt.Printf("signed by %s [%s]\n", p.Label, p.Origin)
+func (setup *MultisigSetup) ParticipantFor(sig *PartialSignature) (*Participant, error) {
+	if sig == nil {
+		return nil, errors.New("partial signature cannot be nil")
+	}
+	for _, p := range setup.Participants {
+		if p.PublicKey == nil {
+			continue
+		}
+		if schnorr.XOnlyFromPub(p.PublicKey) == sig.PubKey {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("no participant with public key %s", sig.PubKey)
+}
@@ -0,0 +1,140 @@
+package multisig
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestParseDerivationPath tests BIP32 path parsing and formatting
+func TestParseDerivationPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []uint32
+		format   string
+		wantErr  bool
+	}{
+		{"master", "m", nil, "m", false},
+		{"empty", "", nil, "m", false},
+		{"bip84 account", "m/84'/0'/0'", []uint32{84 + HardenedKeyStart, HardenedKeyStart, HardenedKeyStart}, "m/84'/0'/0'", false},
+		{"h marker", "m/48h/1h/0h/2h", []uint32{48 + HardenedKeyStart, 1 + HardenedKeyStart, HardenedKeyStart, 2 + HardenedKeyStart}, "m/48'/1'/0'/2'", false},
+		{"no prefix", "0/5", []uint32{0, 5}, "m/0/5", false},
+		{"non-numeric", "m/a", nil, "", true},
+		{"index too large", "m/2147483648", nil, "", true},
+		{"empty step", "m/0//1", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := ParseDerivationPath(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDerivationPath(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(path, tt.expected) {
+				t.Errorf("Expected path %v, got %v", tt.expected, path)
+			}
+			if got := FormatDerivationPath(path); got != tt.format {
+				t.Errorf("Expected %q, got %q", tt.format, got)
+			}
+		})
+	}
+}
+
+// TestParseKeyOrigin tests descriptor-style key origins
+func TestParseKeyOrigin(t *testing.T) {
+	origin, err := ParseKeyOrigin("d34db33f/48'/0'/0'/2'")
+	if err != nil {
+		t.Fatalf("ParseKeyOrigin failed: %v", err)
+	}
+	if origin.Fingerprint != [4]byte{0xd3, 0x4d, 0xb3, 0x3f} {
+		t.Errorf("Unexpected fingerprint %x", origin.Fingerprint)
+	}
+	if got := origin.String(); got != "d34db33f/48'/0'/0'/2'" {
+		t.Errorf("Expected round trip, got %q", got)
+	}
+
+	master, err := ParseKeyOrigin("00000000")
+	if err != nil || len(master.Path) != 0 || master.String() != "00000000" {
+		t.Errorf("Expected a bare fingerprint to parse, got %v, %v", master, err)
+	}
+
+	for _, bad := range []string{"", "d34db3/0", "zzzzzzzz/0", "d34db33f/x"} {
+		if _, err := ParseKeyOrigin(bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+// TestParticipantJSON tests that metadata survives serialization and private keys do not
+func TestParticipantJSON(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 3)
+	origin, _ := ParseKeyOrigin("d34db33f/48'/0'/0'/2'")
+	setup.Participants[0].Label = "hardware wallet"
+	setup.Participants[0].Origin = origin
+
+	data, err := json.Marshal(setup.Participants)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded []*Participant
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	for i, p := range decoded {
+		if p.PrivateKey != nil {
+			t.Errorf("Participant %d: private key was serialized", i)
+		}
+		if !p.PublicKey.IsEqual(setup.Participants[i].PublicKey) {
+			t.Errorf("Participant %d: public key mismatch", i)
+		}
+		if p.Index != i {
+			t.Errorf("Participant %d: expected index %d, got %d", i, i, p.Index)
+		}
+	}
+	if decoded[0].Label != "hardware wallet" || decoded[0].Origin == nil || decoded[0].Origin.String() != origin.String() {
+		t.Errorf("Metadata not carried through: %+v", decoded[0])
+	}
+	if decoded[1].Label != "" || decoded[1].Origin != nil {
+		t.Errorf("Expected no metadata for participant 1, got %+v", decoded[1])
+	}
+
+	if err := json.Unmarshal([]byte(`{"public_key":"02ff","index":0}`), &Participant{}); err == nil {
+		t.Error("Expected error for an invalid public key")
+	}
+}
+
+// TestParticipantFor tests mapping partial signatures back to participants
+func TestParticipantFor(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 2)
+	setup.Participants[1].Label = "phone"
+	msg := []byte("who signed")
+
+	sessions := newSessions(t, setup, msg)
+	exchangeNonces(t, sessions)
+	partial, err := sessions[1].Sign()
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	p, err := setup.ParticipantFor(partial)
+	if err != nil {
+		t.Fatalf("ParticipantFor failed: %v", err)
+	}
+	if p.Label != "phone" {
+		t.Errorf("Expected participant \"phone\", got %q", p.Label)
+	}
+
+	unknown := *partial
+	unknown.PubKey = [32]byte{0x01}
+	if _, err := setup.ParticipantFor(&unknown); err == nil {
+		t.Error("Expected error for an unknown key")
+	}
+	if _, err := setup.ParticipantFor(nil); err == nil {
+		t.Error("Expected error for nil signature")
+	}
+}
@@ -0,0 +1,223 @@
+package multisig
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// Signature requests and approvals
+//
+// A co-signer should never sign a bare 32-byte digest. A coordinator sends a
+// SignatureRequest that says what is being signed: a human-readable summary,
+// the message digest the session will sign, the setup and policy it is signed
+// under, and until when the request is valid. A co-signer that agrees signs
+// the request hash with its participant key, producing an Approval the
+// coordinator can keep as a record of who agreed to what:
+//
+//	request  = TaggedHash("multisig/signature-request/v1", id || digest || setup || threshold ||
+//	                      expiry || len(summary) || summary || len(policy) || policy)
+//	approval = BIP340 signature over TaggedHash("multisig/approval/v1", request || approver || time)
+//
+// Before signing, a co-signer calls Matches to check that the session it is
+// about to sign in is the one the request described.
+
+const (
+	// requestTag and approvalTag are the tagged hash domains of requests and approvals
+	requestTag  = "multisig/signature-request/v1"
+	approvalTag = "multisig/approval/v1"
+)
+
+var (
+	// ErrRequestExpired is returned when a request is approved or checked after its expiry
+	ErrRequestExpired = errors.New("signature request expired")
+	// ErrInvalidApproval is returned when an approval does not verify for a request
+	ErrInvalidApproval = errors.New("invalid approval")
+	// ErrRequestMismatch is returned when a session does not match a request
+	ErrRequestMismatch = errors.New("session does not match signature request")
+)
+
+// SignatureRequest describes what a signing session is asked to sign
+type SignatureRequest struct {
+	SessionID [32]byte  `json:"session_id"`
+	Summary   string    `json:"summary"`         // Shown to the approver, e.g. "Pay 0.5 BTC to bc1q..."
+	Digest    [32]byte  `json:"digest"`          // SHA256 of the message, as signed by Session
+	Setup     [32]byte  `json:"setup"`           // Hash of the ordered participant keys
+	Threshold int       `json:"threshold"`       // Threshold of the setup
+	Policy    string    `json:"policy"`          // Policy context, e.g. "treasury: spends over 1 BTC"
+	Expiry    time.Time `json:"expiry,omitzero"` // Zero means the request does not expire
+}
+
+// Approval records that one participant agreed to a request
+type Approval struct {
+	Request    [32]byte               `json:"request"`     // SignatureRequest.Hash
+	Approver   schnorr.XOnlyPublicKey `json:"approver"`    // x-only key of the participant
+	ApprovedAt time.Time              `json:"approved_at"` // Stored with one-second precision
+	Signature  [64]byte               `json:"signature"`
+}
+
+// NewSignatureRequest creates a request for signing msg with setup
+//
+// Example:
+//
+//	req, err := NewSignatureRequest(setup, sessionID, "Pay 0.5 BTC to bc1q...", msg,
+//		"treasury: spends over 1 BTC", time.Now().Add(time.Hour))
+func NewSignatureRequest(setup *MultisigSetup, sessionID [32]byte, summary string, msg []byte, policy string, expiry time.Time) (*SignatureRequest, error) {
+	if setup == nil {
+		return nil, errors.New("setup cannot be nil")
+	}
+	if len(msg) == 0 {
+		return nil, errors.New("message cannot be empty")
+	}
+	if summary == "" {
+		return nil, errors.New("summary cannot be empty")
+	}
+	return &SignatureRequest{
+		SessionID: sessionID,
+		Summary:   summary,
+		Digest:    sha256.Sum256(msg),
+		Setup:     setupKeyList(setup),
+		Threshold: setup.Threshold,
+		Policy:    policy,
+		Expiry:    expiry,
+	}, nil
+}
+
+// Hash returns the digest that identifies the request
+func (r *SignatureRequest) Hash() [32]byte {
+	var fixed [12]byte
+	binary.BigEndian.PutUint32(fixed[0:4], uint32(r.Threshold))
+	if !r.Expiry.IsZero() {
+		binary.BigEndian.PutUint64(fixed[4:12], uint64(r.Expiry.Unix()))
+	}
+	return schnorr.TaggedHash(requestTag,
+		r.SessionID[:], r.Digest[:], r.Setup[:], fixed[:],
+		lengthPrefixed(r.Summary), lengthPrefixed(r.Policy))
+}
+
+// Expired reports whether the request is no longer valid at now
+func (r *SignatureRequest) Expired(now time.Time) bool {
+	return !r.Expiry.IsZero() && !now.Before(r.Expiry)
+}
+
+// Matches checks that session signs the message, under the setup, that the request describes
+//
+// Example:
+//
+//	if err := req.Matches(session); err != nil {
+//		return err // the coordinator asked for something else
+//	}
+//	partial, err := session.Sign()
+func (r *SignatureRequest) Matches(session *Session) error {
+	if session == nil {
+		return errors.New("session cannot be nil")
+	}
+	switch {
+	case session.id != r.SessionID:
+		return fmt.Errorf("%w: session ID", ErrRequestMismatch)
+	case session.msg != r.Digest:
+		return fmt.Errorf("%w: message digest", ErrRequestMismatch)
+	case setupKeyList(session.setup) != r.Setup || session.setup.Threshold != r.Threshold:
+		return fmt.Errorf("%w: setup", ErrRequestMismatch)
+	}
+	return nil
+}
+
+// Approve signs the request with a participant key
+//
+// now is recorded in the approval; approving an expired request fails.
+//
+// Example:
+//
+//	approval, err := req.Approve(participant.PrivateKey, time.Now())
+func (r *SignatureRequest) Approve(priv *btcec.PrivateKey, now time.Time) (*Approval, error) {
+	if priv == nil {
+		return nil, errors.New("private key cannot be nil")
+	}
+	return r.ApproveWith(schnorr.NewKeySigner(priv), now)
+}
+
+// ApproveWith signs the request with a schnorr.Signer, e.g. a hardware wallet
+func (r *SignatureRequest) ApproveWith(signer schnorr.Signer, now time.Time) (*Approval, error) {
+	if signer == nil {
+		return nil, errors.New("signer cannot be nil")
+	}
+	if r.Expired(now) {
+		return nil, ErrRequestExpired
+	}
+	a := &Approval{
+		Request:    r.Hash(),
+		Approver:   signer.PublicKey(),
+		ApprovedAt: time.Unix(now.Unix(), 0).UTC(),
+	}
+	sig, err := schnorr.SignDigestWith(a.statement(), signer)
+	if err != nil {
+		return nil, err
+	}
+	a.Signature = sig
+	return a, nil
+}
+
+// VerifyApproval checks an approval of req by a participant of setup
+//
+// The approval must sign this request, come from one of the setup's
+// participants, and be made before the request expired.
+//
+// Example:
+//
+//	for _, a := range approvals {
+//		if err := VerifyApproval(req, a, setup); err != nil {
+//			return err
+//		}
+//	}
+func VerifyApproval(req *SignatureRequest, approval *Approval, setup *MultisigSetup) error {
+	if req == nil || approval == nil || setup == nil {
+		return errors.New("request, approval and setup cannot be nil")
+	}
+
+	// Step 1: The approval is for this request, under this setup
+	if approval.Request != req.Hash() {
+		return fmt.Errorf("%w: approval is for another request", ErrInvalidApproval)
+	}
+	if setupKeyList(setup) != req.Setup {
+		return fmt.Errorf("%w: request is for another setup", ErrInvalidApproval)
+	}
+	if req.Expired(approval.ApprovedAt) {
+		return fmt.Errorf("%w: approved after expiry", ErrInvalidApproval)
+	}
+
+	// Step 2: The approver is a participant
+	var pub *btcec.PublicKey
+	for _, p := range setup.Participants {
+		if p.PublicKey != nil && schnorr.XOnlyFromPub(p.PublicKey) == approval.Approver {
+			pub = p.PublicKey
+			break
+		}
+	}
+	if pub == nil {
+		return fmt.Errorf("%w: approver is not a participant", ErrInvalidApproval)
+	}
+
+	// Step 3: The signature
+	if !schnorr.VerifyDigest(approval.statement(), pub, approval.Signature) {
+		return fmt.Errorf("%w: signature", ErrInvalidApproval)
+	}
+	return nil
+}
+
+// statement returns the digest the approver signs
+func (a *Approval) statement() [32]byte {
+	var approvedAt [8]byte
+	binary.BigEndian.PutUint64(approvedAt[:], uint64(a.ApprovedAt.Unix()))
+	return schnorr.TaggedHash(approvalTag, a.Request[:], a.Approver[:], approvedAt[:])
+}
+
+// lengthPrefixed encodes s with a 4-byte big-endian length
+func lengthPrefixed(s string) []byte {
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(s))), s...)
+}
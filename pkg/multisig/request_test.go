@@ -0,0 +1,120 @@
+package multisig
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+func TestSignatureRequestApproval(t *testing.T) {
+	setup := newDeterministicSetup(t, 3, 3)
+	msg := []byte("spend 0.5 BTC")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	req, err := NewSignatureRequest(setup, [32]byte{0x42}, "Pay 0.5 BTC to Bob", msg, "treasury", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("NewSignatureRequest failed: %v", err)
+	}
+
+	// Every participant approves and every approval verifies
+	for i, p := range setup.Participants {
+		approval, err := req.Approve(p.PrivateKey, now)
+		if err != nil {
+			t.Fatalf("Approve(%d) failed: %v", i, err)
+		}
+		if err := VerifyApproval(req, approval, setup); err != nil {
+			t.Errorf("Expected approval %d to verify, got %v", i, err)
+		}
+	}
+
+	// Requests and approvals survive a JSON round trip
+	approval, _ := req.Approve(setup.Participants[0].PrivateKey, now)
+	var decodedReq SignatureRequest
+	var decodedApproval Approval
+	data, _ := json.Marshal(req)
+	if err := json.Unmarshal(data, &decodedReq); err != nil {
+		t.Fatalf("Unmarshal request failed: %v", err)
+	}
+	data, _ = json.Marshal(approval)
+	if err := json.Unmarshal(data, &decodedApproval); err != nil {
+		t.Fatalf("Unmarshal approval failed: %v", err)
+	}
+	if err := VerifyApproval(&decodedReq, &decodedApproval, setup); err != nil {
+		t.Errorf("Expected decoded approval to verify, got %v", err)
+	}
+
+	// The request matches the sessions signing the message
+	for _, s := range newSessions(t, setup, msg) {
+		if err := req.Matches(s); err != nil {
+			t.Errorf("Expected session %d to match, got %v", s.SignerIndex(), err)
+		}
+	}
+	other := newSessions(t, setup, []byte("spend 5 BTC"))[0]
+	if err := req.Matches(other); !errors.Is(err, ErrRequestMismatch) {
+		t.Errorf("Expected ErrRequestMismatch for another message, got %v", err)
+	}
+}
+
+// TestApprovalRejects tests that every field of a request is bound by the approval
+func TestApprovalRejects(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 2)
+	outsider := newDeterministicSetup(t, 3, 3).Participants[2]
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	req, _ := NewSignatureRequest(setup, [32]byte{0x01}, "summary", []byte("msg"), "policy", now.Add(time.Minute))
+	approval, err := req.Approve(setup.Participants[1].PrivateKey, now)
+	if err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(r *SignatureRequest, a *Approval)
+	}{
+		{"summary", func(r *SignatureRequest, a *Approval) { r.Summary = "Pay 5 BTC" }},
+		{"policy", func(r *SignatureRequest, a *Approval) { r.Policy = "" }},
+		{"digest", func(r *SignatureRequest, a *Approval) { r.Digest[0] ^= 0x01 }},
+		{"expiry", func(r *SignatureRequest, a *Approval) { r.Expiry = time.Time{} }},
+		{"approval time", func(r *SignatureRequest, a *Approval) { a.ApprovedAt = a.ApprovedAt.Add(-time.Second) }},
+		{"approved after expiry", func(r *SignatureRequest, a *Approval) { a.ApprovedAt = r.Expiry }},
+		{"signature", func(r *SignatureRequest, a *Approval) { a.Signature[63] ^= 0x01 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, a := *req, *approval
+			tt.tamper(&r, &a)
+			if err := VerifyApproval(&r, &a, setup); !errors.Is(err, ErrInvalidApproval) {
+				t.Errorf("Expected ErrInvalidApproval, got %v", err)
+			}
+		})
+	}
+
+	// Approvals by non-participants or for another setup are rejected
+	byOutsider, _ := req.Approve(outsider.PrivateKey, now)
+	if err := VerifyApproval(req, byOutsider, setup); !errors.Is(err, ErrInvalidApproval) {
+		t.Errorf("Expected ErrInvalidApproval for an outsider, got %v", err)
+	}
+	if err := VerifyApproval(req, approval, newDeterministicSetup(t, 3, 3)); !errors.Is(err, ErrInvalidApproval) {
+		t.Errorf("Expected ErrInvalidApproval for another setup, got %v", err)
+	}
+
+	// Expired requests cannot be approved
+	if _, err := req.Approve(setup.Participants[0].PrivateKey, now.Add(time.Hour)); !errors.Is(err, ErrRequestExpired) {
+		t.Errorf("Expected ErrRequestExpired, got %v", err)
+	}
+
+	// Approvals can come from any schnorr.Signer
+	viaSigner, err := req.ApproveWith(schnorr.NewKeySigner(setup.Participants[0].PrivateKey), now)
+	if err != nil {
+		t.Fatalf("ApproveWith failed: %v", err)
+	}
+	if err := VerifyApproval(req, viaSigner, setup); err != nil {
+		t.Errorf("Expected the signer's approval to verify, got %v", err)
+	}
+
+	if _, err := NewSignatureRequest(setup, [32]byte{}, "", []byte("msg"), "", time.Time{}); err == nil {
+		t.Error("Expected error for an empty summary")
+	}
+}
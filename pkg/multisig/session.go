@@ -0,0 +1,802 @@
+package multisig
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+	"github.com/neverDefined/cryptography-playground/pkg/fsm"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// Interactive MuSig signing sessions
+//
+// A Session is run by one participant. All participants of the setup sign
+// (MuSig is n-of-n); the result is a single BIP340 signature valid for the
+// aggregated key returned by AggregatedKey.
+//
+// Two nonce modes are available:
+//
+//	NonceModeMuSig2:        round 1 exchange two nonce points, round 2 exchange partial signatures
+//	NonceModeCommitReveal:  round 1 exchange nonce commitments, round 2 reveal one nonce point,
+//	                        round 3 exchange partial signatures (MuSig1)
+//
+// Both defend against Wagner/ROS attacks on concurrent sessions: MuSig2 through
+// the second nonce, MuSig1 by forcing every signer to fix its nonce before
+// seeing anyone else's.
+
+// NonceMode selects how signers exchange nonces
+type NonceMode int
+
+const (
+	// NonceModeMuSig2 exchanges two nonce points per signer (two rounds in total)
+	NonceModeMuSig2 NonceMode = iota
+	// NonceModeCommitReveal exchanges hash commitments before single nonce points (three rounds)
+	NonceModeCommitReveal
+)
+
+// String returns the name of the nonce mode
+func (m NonceMode) String() string {
+	switch m {
+	case NonceModeMuSig2:
+		return "musig2"
+	case NonceModeCommitReveal:
+		return "commit-reveal"
+	default:
+		return fmt.Sprintf("NonceMode(%d)", int(m))
+	}
+}
+
+// SessionState is the round a session is currently in
+type SessionState int
+
+const (
+	// StateCommit waits for the nonce commitments of all signers (commit-reveal mode only)
+	StateCommit SessionState = iota
+	// StateNonce waits for the public nonces of all signers
+	StateNonce
+	// StateSign waits for the partial signatures of all signers
+	StateSign
+	// StateComplete means the final signature can be produced
+	StateComplete
+)
+
+// String returns the name of the session state
+func (s SessionState) String() string {
+	switch s {
+	case StateCommit:
+		return "commit"
+	case StateNonce:
+		return "nonce"
+	case StateSign:
+		return "sign"
+	case StateComplete:
+		return "complete"
+	default:
+		return fmt.Sprintf("SessionState(%d)", int(s))
+	}
+}
+
+// Session lifecycle events
+const (
+	evSkipCommit          = "skip-commit"
+	evAddCommitment       = "add-commitment"
+	evCommitmentsComplete = "commitments-complete"
+	evRevealNonce         = "reveal-nonce"
+	evAddNonce            = "add-nonce"
+	evNoncesComplete      = "nonces-complete"
+	evSign                = "sign"
+	evAddPartial          = "add-partial"
+	evPartialsComplete    = "partials-complete"
+	evFinalize            = "finalize"
+)
+
+// sessionLifecycle is the state machine every Session follows
+//
+// MuSig2 sessions skip the commitment round as soon as they are created.
+var sessionLifecycle = &fsm.Definition[SessionState]{
+	Name:    "musig-session",
+	Initial: StateCommit,
+	Final:   []SessionState{StateComplete},
+	Events: []fsm.Event[SessionState]{
+		{Name: evSkipCommit, From: []SessionState{StateCommit}, To: StateNonce},
+		{Name: evAddCommitment, From: []SessionState{StateCommit}, Stay: true},
+		{Name: evCommitmentsComplete, From: []SessionState{StateCommit}, To: StateNonce},
+		{Name: evRevealNonce, From: []SessionState{StateNonce, StateSign, StateComplete}, Stay: true},
+		{Name: evAddNonce, From: []SessionState{StateNonce}, Stay: true},
+		{Name: evNoncesComplete, From: []SessionState{StateNonce}, To: StateSign},
+		{Name: evSign, From: []SessionState{StateSign}, Stay: true},
+		{Name: evAddPartial, From: []SessionState{StateSign}, Stay: true},
+		{Name: evPartialsComplete, From: []SessionState{StateSign}, To: StateComplete},
+		{Name: evFinalize, From: []SessionState{StateComplete}, Stay: true},
+	},
+	Err: ErrWrongState,
+}
+
+var (
+	// ErrWrongState is returned when a session method is called in the wrong round
+	ErrWrongState = errors.New("operation not allowed in the current session state")
+	// ErrNonceReused is returned when a session is asked to sign a second time
+	ErrNonceReused = errors.New("secret nonce already used")
+	// ErrCommitmentMismatch is returned when a revealed nonce does not match its commitment
+	ErrCommitmentMismatch = errors.New("nonce does not match commitment")
+	// ErrInvalidPartialSignature is returned when a partial signature fails verification
+	ErrInvalidPartialSignature = errors.New("invalid partial signature")
+)
+
+const (
+	// pubNonceSize is the size of one compressed nonce point
+	pubNonceSize = 33
+)
+
+// sessionOptions holds the settings selected with SessionOption
+type sessionOptions struct {
+	mode       NonceMode
+	sessionID  *[32]byte
+	nonceStore NonceStore
+	rand       io.Reader
+
+	sessionStore SessionStore
+	storeKey     [32]byte
+}
+
+// SessionOption configures a Session
+type SessionOption func(*sessionOptions)
+
+// WithNonceMode selects the nonce exchange protocol (default NonceModeMuSig2)
+func WithNonceMode(mode NonceMode) SessionOption {
+	return func(o *sessionOptions) {
+		o.mode = mode
+	}
+}
+
+// WithNonceCommitments selects the three-round commit-then-reveal MuSig1 mode
+func WithNonceCommitments() SessionOption {
+	return WithNonceMode(NonceModeCommitReveal)
+}
+
+// WithSessionID sets the session identifier shared by all signers (default random)
+func WithSessionID(id [32]byte) SessionOption {
+	return func(o *sessionOptions) {
+		o.sessionID = &id
+	}
+}
+
+// WithRandReader sets the randomness source for the session ID and nonces (default crypto/rand)
+//
+// Only meant for reproducible tests and fixtures: a predictable reader makes the
+// nonces predictable, which leaks the private key.
+func WithRandReader(r io.Reader) SessionOption {
+	return func(o *sessionOptions) {
+		o.rand = r
+	}
+}
+
+// WithNonceStore records used sessions and nonces in store and refuses to sign twice
+//
+// Use a persistent store (e.g. FileNonceStore) so the protection survives restarts.
+func WithNonceStore(store NonceStore) SessionOption {
+	return func(o *sessionOptions) {
+		o.nonceStore = store
+	}
+}
+
+// Session is one participant's view of an interactive MuSig signing session
+type Session struct {
+	id      [32]byte
+	mode    NonceMode
+	machine *fsm.Machine[SessionState]
+	setup   *MultisigSetup
+	signer  int
+	msg     [32]byte // SHA256 of the message (the BIP340 message)
+
+	// Key aggregation
+	coefficients []btcec.ModNScalar
+	aggKey       *btcec.PublicKey
+	aggKeyOdd    bool
+
+	// Nonces
+	secNonces   []btcec.ModNScalar // Cleared after signing
+	nonceUsed   bool
+	nonceStore  NonceStore
+	commitments map[int][32]byte
+	pubNonces   map[int][]byte
+
+	// Persistence (WithSessionStore)
+	sessionStore SessionStore
+	storeKey     [32]byte
+
+	// Aggregated nonce and challenge (set once all nonces are known)
+	finalNonce *btcec.PublicKey
+	nonceCoef  btcec.ModNScalar
+	challenge  btcec.ModNScalar
+
+	partials map[int]*PartialSignature
+}
+
+// NewSession starts a signing session for the participant at signerIndex
+//
+// Example:
+//
+//	session, err := NewSession(setup, 0, msg, WithSessionID(id))
+//	nonce, _ := session.PublicNonce() // send to every other signer
+func NewSession(setup *MultisigSetup, signerIndex int, msg []byte, opts ...SessionOption) (*Session, error) {
+	if setup == nil {
+		return nil, errors.New("setup cannot be nil")
+	}
+	if len(msg) == 0 {
+		return nil, errors.New("message cannot be empty")
+	}
+	if signerIndex < 0 || signerIndex >= len(setup.Participants) {
+		return nil, fmt.Errorf("invalid signer index: %d", signerIndex)
+	}
+	if setup.Participants[signerIndex].PrivateKey == nil {
+		return nil, errors.New("signer has no private key")
+	}
+
+	options := &sessionOptions{mode: NonceModeMuSig2, rand: rand.Reader}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.mode != NonceModeMuSig2 && options.mode != NonceModeCommitReveal {
+		return nil, fmt.Errorf("unsupported nonce mode: %s", options.mode)
+	}
+	if options.sessionStore != nil && options.nonceStore == nil {
+		return nil, ErrNonceStoreRequired
+	}
+
+	s := &Session{
+		mode:         options.mode,
+		machine:      fsm.New(sessionLifecycle),
+		nonceStore:   options.nonceStore,
+		sessionStore: options.sessionStore,
+		storeKey:     options.storeKey,
+		setup:        setup,
+		signer:       signerIndex,
+		msg:          sha256.Sum256(msg),
+		commitments:  make(map[int][32]byte),
+		pubNonces:    make(map[int][]byte),
+		partials:     make(map[int]*PartialSignature),
+	}
+
+	// Step 1: Session identifier
+	if options.sessionID != nil {
+		s.id = *options.sessionID
+	} else if _, err := io.ReadFull(options.rand, s.id[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+
+	// Step 2: Refuse sessions this key already signed in
+	if s.nonceStore != nil {
+		pubKey := setup.Participants[signerIndex].PublicKey.SerializeCompressed()
+		used, err := s.nonceStore.HasSession(pubKey, s.id)
+		if err != nil {
+			return nil, fmt.Errorf("nonce store: %w", err)
+		}
+		if used {
+			return nil, fmt.Errorf("%w: key already signed in session %x", ErrNonceReused, s.id)
+		}
+	}
+
+	// Step 3: Key aggregation coefficients and aggregated key
+	var err error
+	s.coefficients, s.aggKey, err = keyAggregation(setup)
+	if err != nil {
+		return nil, err
+	}
+	s.aggKeyOdd = !arithmetic.HasEvenY(arithmetic.PointFromPubKey(s.aggKey))
+
+	// Step 4: Generate our secret nonce(s)
+	if err := s.generateNonces(options.rand); err != nil {
+		return nil, err
+	}
+
+	// Step 5: Record our own contribution for the first round
+	if s.mode == NonceModeCommitReveal {
+		s.commitments[s.signer] = nonceCommitment(s.id, s.signer, s.ownPubNonce())
+	} else {
+		_ = s.machine.Fire(evSkipCommit)
+		s.pubNonces[s.signer] = s.ownPubNonce()
+	}
+
+	// Step 6: Save the first round, so a crash from here on can resume
+	if err := s.persist(); err != nil {
+		s.discardNonces()
+		return nil, err
+	}
+	return s, nil
+}
+
+// keyAggregation computes the BIP327 key aggregation coefficients and aggregated key
+//
+// Formula:
+//
+//	L   = TaggedHash("KeyAgg list", P_1 || ... || P_n)
+//	a_i = TaggedHash("KeyAgg coefficient", L || P_i)   (a_i = 1 for the second distinct key)
+//	Q   = a_1*P_1 + ... + a_n*P_n
+func keyAggregation(setup *MultisigSetup) ([]btcec.ModNScalar, *btcec.PublicKey, error) {
+	// Step 1: Hash the ordered key list
+	keys := make([][]byte, len(setup.Participants))
+	for i, p := range setup.Participants {
+		if p.PublicKey == nil {
+			return nil, nil, errors.New("all participants must have a public key")
+		}
+		keys[i] = p.PublicKey.SerializeCompressed()
+	}
+	list := schnorr.TaggedHash("KeyAgg list", keys...)
+
+	// Step 2: Find the second distinct key, whose coefficient is 1
+	second := -1
+	for i, key := range keys {
+		if string(key) != string(keys[0]) {
+			second = i
+			break
+		}
+	}
+
+	// Step 3: Q = Σ a_i*P_i
+	coefficients := make([]btcec.ModNScalar, len(keys))
+	var q btcec.JacobianPoint
+	for i, p := range setup.Participants {
+		if i == second {
+			coefficients[i].SetInt(1)
+		} else {
+			coefficient := schnorr.TaggedHash("KeyAgg coefficient", list[:], keys[i])
+			coefficients[i].SetBytes(&coefficient)
+		}
+
+		var point, term btcec.JacobianPoint
+		p.PublicKey.AsJacobian(&point)
+		btcec.ScalarMultNonConst(&coefficients[i], &point, &term)
+		btcec.AddNonConst(&q, &term, &q)
+	}
+	if isInfinity(&q) {
+		return nil, nil, errors.New("aggregated key is the point at infinity")
+	}
+	q.ToAffine()
+	return coefficients, btcec.NewPublicKey(&q.X, &q.Y), nil
+}
+
+// isInfinity reports whether a Jacobian point is the point at infinity
+func isInfinity(p *btcec.JacobianPoint) bool {
+	return (p.X.IsZero() && p.Y.IsZero()) || p.Z.IsZero()
+}
+
+// nonceCommitment binds a public nonce to the session and signer
+//
+// Formula: TaggedHash("MuSig1/commitment", sessionID || index || pubNonce)
+func nonceCommitment(sessionID [32]byte, index int, pubNonce []byte) [32]byte {
+	idx := []byte{byte(index >> 24), byte(index >> 16), byte(index >> 8), byte(index)}
+	return schnorr.TaggedHash("MuSig1/commitment", sessionID[:], idx, pubNonce)
+}
+
+// generateNonces derives fresh secret nonces from randomness, the key and the session
+//
+// Mixing the secret key, aggregated key, message and session ID into the
+// randomness means a weak RNG alone does not lead to nonce reuse.
+func (s *Session) generateNonces(randReader io.Reader) error {
+	count := s.nonceCount()
+
+	var random [32]byte
+	defer arithmetic.ZeroBytes(random[:])
+	if _, err := io.ReadFull(randReader, random[:]); err != nil {
+		return fmt.Errorf("failed to generate nonce randomness: %w", err)
+	}
+	secKey := s.setup.Participants[s.signer].PrivateKey.Key.Bytes()
+	defer arithmetic.ZeroBytes(secKey[:])
+	aggX := btcschnorr.SerializePubKey(s.aggKey)
+
+	s.secNonces = make([]btcec.ModNScalar, count)
+	for j := 0; j < count; j++ {
+		h := schnorr.TaggedHash("MuSig/nonce", random[:], secKey[:], aggX, s.msg[:], s.id[:], []byte{byte(j)})
+		s.secNonces[j].SetBytes(&h)
+		arithmetic.ZeroBytes(h[:])
+		if s.secNonces[j].IsZero() {
+			return errors.New("generated a zero nonce")
+		}
+	}
+	return nil
+}
+
+// ownPubNonce returns the public nonce points k_j*G of this signer
+func (s *Session) ownPubNonce() []byte {
+	out := make([]byte, 0, pubNonceSize*len(s.secNonces))
+	for j := range s.secNonces {
+		var r btcec.JacobianPoint
+		btcec.ScalarBaseMultNonConst(&s.secNonces[j], &r)
+		r.ToAffine()
+		out = append(out, btcec.NewPublicKey(&r.X, &r.Y).SerializeCompressed()...)
+	}
+	return out
+}
+
+// ID returns the session identifier
+func (s *Session) ID() [32]byte {
+	return s.id
+}
+
+// Mode returns the nonce exchange mode of the session
+func (s *Session) Mode() NonceMode {
+	return s.mode
+}
+
+// State returns the current round of the session
+func (s *Session) State() SessionState {
+	return s.machine.State()
+}
+
+// SignerIndex returns the index of the participant running this session
+func (s *Session) SignerIndex() int {
+	return s.signer
+}
+
+// AggregatedKey returns the x-only aggregated key the final signature is valid for
+func (s *Session) AggregatedKey() schnorr.XOnlyPublicKey {
+	return schnorr.XOnlyFromPub(s.aggKey)
+}
+
+// NonceCommitment returns this signer's nonce commitment (commit-reveal mode only)
+func (s *Session) NonceCommitment() ([32]byte, error) {
+	if s.mode != NonceModeCommitReveal {
+		return [32]byte{}, errors.New("nonce commitments are only used in commit-reveal mode")
+	}
+	return s.commitments[s.signer], nil
+}
+
+// AddNonceCommitment records another signer's nonce commitment
+//
+// Once the commitments of all signers are known the session moves to StateNonce
+// and public nonces can be revealed.
+func (s *Session) AddNonceCommitment(index int, commitment [32]byte) error {
+	if s.mode != NonceModeCommitReveal {
+		return errors.New("nonce commitments are only used in commit-reveal mode")
+	}
+	if err := s.machine.Check(evAddCommitment); err != nil {
+		return err
+	}
+	if err := s.checkIndex(index); err != nil {
+		return err
+	}
+	if _, exists := s.commitments[index]; exists {
+		return fmt.Errorf("duplicate commitment from signer %d", index)
+	}
+
+	s.commitments[index] = commitment
+	if len(s.commitments) == len(s.setup.Participants) {
+		_ = s.machine.Fire(evCommitmentsComplete)
+		s.pubNonces[s.signer] = s.ownPubNonce()
+	}
+	return s.persist()
+}
+
+// PublicNonce returns this signer's public nonce to send to the other signers
+//
+// In commit-reveal mode the nonce is withheld until every commitment was received.
+//
+// Format: 66 bytes (two compressed points) for MuSig2, 33 bytes for commit-reveal
+func (s *Session) PublicNonce() ([]byte, error) {
+	if err := s.machine.Check(evRevealNonce); err != nil {
+		return nil, fmt.Errorf("all nonce commitments must be received before revealing: %w", err)
+	}
+	return append([]byte{}, s.pubNonces[s.signer]...), nil
+}
+
+// AddPublicNonce records another signer's public nonce
+//
+// In commit-reveal mode the nonce must match the commitment received earlier.
+// Once all nonces are known the session moves to StateSign.
+func (s *Session) AddPublicNonce(index int, pubNonce []byte) error {
+	if err := s.machine.Check(evAddNonce); err != nil {
+		return err
+	}
+	if err := s.checkIndex(index); err != nil {
+		return err
+	}
+	if _, exists := s.pubNonces[index]; exists {
+		return fmt.Errorf("duplicate nonce from signer %d", index)
+	}
+
+	// Step 1: Validate the encoding
+	if len(pubNonce) != pubNonceSize*s.nonceCount() {
+		return fmt.Errorf("expected %d-byte nonce, got %d", pubNonceSize*s.nonceCount(), len(pubNonce))
+	}
+	for j := 0; j < len(pubNonce); j += pubNonceSize {
+		if _, err := btcec.ParsePubKey(pubNonce[j : j+pubNonceSize]); err != nil {
+			return fmt.Errorf("invalid nonce point: %w", err)
+		}
+	}
+
+	// Step 2: Check the commitment
+	if s.mode == NonceModeCommitReveal && nonceCommitment(s.id, index, pubNonce) != s.commitments[index] {
+		return fmt.Errorf("%w: signer %d", ErrCommitmentMismatch, index)
+	}
+
+	s.pubNonces[index] = append([]byte{}, pubNonce...)
+	if len(s.pubNonces) == len(s.setup.Participants) {
+		if err := s.aggregateNonces(); err != nil {
+			return err
+		}
+		_ = s.machine.Fire(evNoncesComplete)
+	}
+	return s.persist()
+}
+
+// nonceCount returns the number of nonces per signer: two for MuSig2, one for commit-reveal
+func (s *Session) nonceCount() int {
+	if s.mode == NonceModeCommitReveal {
+		return 1
+	}
+	return 2
+}
+
+// aggregateNonces computes the final nonce R, the nonce coefficient b and the challenge e
+//
+// Formula:
+//
+//	R_j = Σ R_{i,j}
+//	b   = TaggedHash("MuSig/noncecoef", R_1 || R_2 || x(Q) || m)   (MuSig2 only)
+//	R   = R_1 + b*R_2
+//	e   = TaggedHash("BIP0340/challenge", x(R) || x(Q) || m)
+func (s *Session) aggregateNonces() error {
+	// Step 1: Sum the nonce points of every signer, per nonce slot
+	aggregated := make([]btcec.JacobianPoint, s.nonceCount())
+	for i := range s.setup.Participants {
+		nonce := s.pubNonces[i]
+		for j := range aggregated {
+			point, _ := btcec.ParsePubKey(nonce[j*pubNonceSize : (j+1)*pubNonceSize])
+			var r btcec.JacobianPoint
+			point.AsJacobian(&r)
+			btcec.AddNonConst(&aggregated[j], &r, &aggregated[j])
+		}
+	}
+
+	aggX := btcschnorr.SerializePubKey(s.aggKey)
+
+	// Step 2: Combine the slots into R
+	var final btcec.JacobianPoint
+	if s.mode == NonceModeMuSig2 {
+		encoded := make([]byte, 0, 2*pubNonceSize)
+		for j := range aggregated {
+			encoded = append(encoded, encodePoint(&aggregated[j])...)
+		}
+		b := schnorr.TaggedHash("MuSig/noncecoef", encoded, aggX, s.msg[:])
+		s.nonceCoef.SetBytes(&b)
+
+		var bR2 btcec.JacobianPoint
+		btcec.ScalarMultNonConst(&s.nonceCoef, &aggregated[1], &bR2)
+		btcec.AddNonConst(&aggregated[0], &bR2, &final)
+	} else {
+		final = aggregated[0]
+	}
+
+	// An infinite nonce can only be caused by a malicious signer; fall back to G
+	if isInfinity(&final) {
+		var one btcec.ModNScalar
+		one.SetInt(1)
+		btcec.ScalarBaseMultNonConst(&one, &final)
+	}
+	final.ToAffine()
+	s.finalNonce = btcec.NewPublicKey(&final.X, &final.Y)
+
+	// Step 3: BIP340 challenge
+	e := schnorr.TaggedHash("BIP0340/challenge", btcschnorr.SerializePubKey(s.finalNonce), aggX, s.msg[:])
+	s.challenge.SetBytes(&e)
+	return nil
+}
+
+// encodePoint serializes a Jacobian point compressed, or as 33 zero bytes for infinity
+func encodePoint(p *btcec.JacobianPoint) []byte {
+	if isInfinity(p) {
+		return make([]byte, pubNonceSize)
+	}
+	affine := *p
+	affine.ToAffine()
+	return btcec.NewPublicKey(&affine.X, &affine.Y).SerializeCompressed()
+}
+
+// finalNonceOdd reports whether the final nonce R has an odd y coordinate
+func (s *Session) finalNonceOdd() bool {
+	return !arithmetic.HasEvenY(arithmetic.PointFromPubKey(s.finalNonce))
+}
+
+// Sign produces this signer's partial signature
+//
+// The secret nonces are erased afterwards; a session can sign only once. With a
+// NonceStore the use is recorded durably before the signature is computed.
+//
+// Formula:
+//
+//	s_i = k_1 + b*k_2 + e*a_i*g*d_i   (k negated if R has odd y, g = -1 if Q has odd y)
+func (s *Session) Sign() (*PartialSignature, error) {
+	if s.nonceUsed {
+		return nil, ErrNonceReused
+	}
+	if err := s.machine.Check(evSign); err != nil {
+		return nil, err
+	}
+
+	// Step 1: Record the nonce as used before it can leave the session
+	if s.nonceStore != nil {
+		pubKey := s.setup.Participants[s.signer].PublicKey.SerializeCompressed()
+		if err := s.nonceStore.MarkUsed(pubKey, s.id, s.pubNonces[s.signer]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 2: Effective nonce k = k_1 + b*k_2, negated if R has odd y
+	var k btcec.ModNScalar
+	k.Set(&s.secNonces[0])
+	if len(s.secNonces) == 2 {
+		var bk2 btcec.ModNScalar
+		bk2.Mul2(&s.nonceCoef, &s.secNonces[1])
+		k.Add(&bk2)
+	}
+	if s.finalNonceOdd() {
+		k.Negate()
+	}
+
+	// Step 3: Effective key g*d_i
+	var d btcec.ModNScalar
+	d.Set(&s.setup.Participants[s.signer].PrivateKey.Key)
+	if s.aggKeyOdd {
+		d.Negate()
+	}
+
+	// Step 4: s_i = k + e*a_i*d
+	var sig btcec.ModNScalar
+	sig.Mul2(&s.challenge, &s.coefficients[s.signer]).Mul(&d).Add(&k)
+
+	// Step 5: Erase the secret nonces so they can never be used again
+	for j := range s.secNonces {
+		s.secNonces[j].Zero()
+	}
+	s.secNonces = nil
+	s.nonceUsed = true
+	k.Zero()
+	d.Zero()
+
+	partial := s.newPartialSignature(s.signer, &sig)
+	s.partials[s.signer] = partial
+	s.advanceIfComplete()
+
+	// Step 6: Save the nonce-free state before the signature leaves the session
+	if err := s.persist(); err != nil {
+		return nil, err
+	}
+	return partial, nil
+}
+
+// newPartialSignature wraps a partial s value
+func (s *Session) newPartialSignature(index int, sig *btcec.ModNScalar) *PartialSignature {
+	partial := &PartialSignature{Index: index}
+	copy(partial.R[:], btcschnorr.SerializePubKey(s.finalNonce))
+	partial.S = sig.Bytes()
+	partial.PubKey = schnorr.XOnlyFromPub(s.setup.Participants[index].PublicKey)
+	return partial
+}
+
+// AddPartialSignature verifies and records another signer's partial signature
+//
+// Formula: s_i*G == R_i' + e*a_i*g*P_i   (R_i' = R_{i,1} + b*R_{i,2}, negated if R has odd y)
+func (s *Session) AddPartialSignature(partial *PartialSignature) error {
+	if err := s.machine.Check(evAddPartial); err != nil {
+		return err
+	}
+	if partial == nil {
+		return errors.New("partial signature cannot be nil")
+	}
+	if err := s.checkIndex(partial.Index); err != nil {
+		return err
+	}
+	if _, exists := s.partials[partial.Index]; exists {
+		return fmt.Errorf("duplicate partial signature from signer %d", partial.Index)
+	}
+	if !s.verifyPartial(partial) {
+		return fmt.Errorf("%w: signer %d", ErrInvalidPartialSignature, partial.Index)
+	}
+
+	s.partials[partial.Index] = partial
+	s.advanceIfComplete()
+	return s.persist()
+}
+
+// verifyPartial checks a partial signature against the signer's public key and nonce
+func (s *Session) verifyPartial(partial *PartialSignature) bool {
+	var sig btcec.ModNScalar
+	if overflow := sig.SetBytes(&partial.S); overflow != 0 {
+		return false
+	}
+
+	// Step 1: Left side s_i*G
+	var lhs btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&sig, &lhs)
+
+	// Step 2: Signer's effective nonce R_i'
+	nonce := s.pubNonces[partial.Index]
+	var r btcec.JacobianPoint
+	for j := 0; j*pubNonceSize < len(nonce); j++ {
+		point, err := btcec.ParsePubKey(nonce[j*pubNonceSize : (j+1)*pubNonceSize])
+		if err != nil {
+			return false
+		}
+		var p btcec.JacobianPoint
+		point.AsJacobian(&p)
+		if j == 1 {
+			var bp btcec.JacobianPoint
+			btcec.ScalarMultNonConst(&s.nonceCoef, &p, &bp)
+			p = bp
+		}
+		btcec.AddNonConst(&r, &p, &r)
+	}
+	if s.finalNonceOdd() {
+		r.ToAffine()
+		r.Y.Negate(1).Normalize()
+	}
+
+	// Step 3: e*a_i*g*P_i
+	var factor btcec.ModNScalar
+	factor.Mul2(&s.challenge, &s.coefficients[partial.Index])
+	if s.aggKeyOdd {
+		factor.Negate()
+	}
+	var pub, ePub, rhs btcec.JacobianPoint
+	s.setup.Participants[partial.Index].PublicKey.AsJacobian(&pub)
+	btcec.ScalarMultNonConst(&factor, &pub, &ePub)
+	btcec.AddNonConst(&r, &ePub, &rhs)
+
+	// Step 4: Compare in affine coordinates
+	lhs.ToAffine()
+	rhs.ToAffine()
+	return lhs.X.Equals(&rhs.X) && lhs.Y.Equals(&rhs.Y)
+}
+
+// advanceIfComplete moves to StateComplete once all partial signatures are known
+func (s *Session) advanceIfComplete() {
+	if len(s.partials) == len(s.setup.Participants) {
+		_ = s.machine.Fire(evPartialsComplete)
+	}
+}
+
+// Finalize sums the partial signatures into the final BIP340 signature
+//
+// Example:
+//
+//	complete, err := session.Finalize()
+//	valid := complete.VerifyAgainstAggregatedKey(msg, session.AggregatedKey())
+func (s *Session) Finalize() (*CompleteSignature, error) {
+	if err := s.machine.Check(evFinalize); err != nil {
+		return nil, err
+	}
+
+	// Step 1: s = Σ s_i
+	var total btcec.ModNScalar
+	complete := &CompleteSignature{}
+	for i := range s.setup.Participants {
+		var si btcec.ModNScalar
+		si.SetBytes(&s.partials[i].S)
+		total.Add(&si)
+		complete.PubKeys = append(complete.PubKeys, s.partials[i].PubKey)
+		complete.Indices = append(complete.Indices, i)
+	}
+
+	// Step 2: Signature = (x(R), s)
+	copy(complete.R[:], btcschnorr.SerializePubKey(s.finalNonce))
+	complete.S = total.Bytes()
+	return complete, nil
+}
+
+// checkIndex validates that index refers to another signer of the setup
+func (s *Session) checkIndex(index int) error {
+	if index < 0 || index >= len(s.setup.Participants) {
+		return fmt.Errorf("invalid signer index: %d", index)
+	}
+	if index == s.signer {
+		return errors.New("cannot add data for the session's own signer")
+	}
+	return nil
+}
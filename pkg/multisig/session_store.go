@@ -0,0 +1,214 @@
+package multisig
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Session persistence and crash recovery
+//
+// With WithSessionStore a session saves itself as an encrypted token (see
+// Session.Export) after every round, so a coordinator or signer that crashes
+// mid-ceremony can continue with ResumeSession instead of starting a new
+// session and exchanging nonces again.
+//
+// Resuming brings the secret nonces back, which is only safe if they can never
+// sign twice: a store rolled back to an older token, or a session resumed on two
+// machines, would otherwise sign different aggregated nonces with the same k.
+// Persistence therefore requires a NonceStore, which records each signing before
+// the partial signature exists, and a resumed session is refused once its key
+// has signed in that session ID.
+
+// ErrNonceStoreRequired is returned when sessions are persisted without a NonceStore
+var ErrNonceStoreRequired = errors.New("session persistence requires a nonce store")
+
+// SessionStore keeps the latest encrypted token of each session
+//
+// Tokens are filed by session ID only, so each signer needs its own store.
+type SessionStore interface {
+	// SaveSession replaces the token stored for the session
+	SaveSession(sessionID [32]byte, token []byte) error
+	// LoadSession returns the stored token, or ErrSessionNotFound
+	LoadSession(sessionID [32]byte) ([]byte, error)
+	// DeleteSession forgets a session; deleting an unknown session is not an error
+	DeleteSession(sessionID [32]byte) error
+}
+
+// WithSessionStore saves the session to store after every round, encrypted under key
+//
+// Requires WithNonceStore. A failed save is returned by the method that
+// changed the session; Sign then withholds the partial signature.
+//
+// Example:
+//
+//	session, err := NewSession(setup, 0, msg,
+//		WithNonceStore(nonces), WithSessionStore(sessions, storageKey))
+//	// ... after a crash ...
+//	session, err = ResumeSession(setup, sessions, storageKey, id, WithNonceStore(nonces))
+func WithSessionStore(store SessionStore, key [32]byte) SessionOption {
+	return func(o *sessionOptions) {
+		o.sessionStore = store
+		o.storeKey = key
+	}
+}
+
+// ResumeSession restores a session saved with WithSessionStore
+//
+// setup and the options follow ImportSession; WithNonceStore is required and
+// the session keeps saving itself to store.
+//
+// Example:
+//
+//	session, err := ResumeSession(setup, store, storageKey, id, WithNonceStore(nonces))
+//	if errors.Is(err, ErrNonceReused) {
+//		// this key already signed in the session; start a new one
+//	}
+func ResumeSession(setup *MultisigSetup, store SessionStore, key [32]byte, sessionID [32]byte, opts ...SessionOption) (*Session, error) {
+	if store == nil {
+		return nil, errors.New("session store cannot be nil")
+	}
+
+	// Step 1: Load the latest token
+	token, err := store.LoadSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Import it with the store attached, so later rounds are saved too
+	opts = append(opts[:len(opts):len(opts)], WithSessionStore(store, key))
+	s, err := ImportSession(setup, token, key, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if s.id != sessionID {
+		s.discardNonces()
+		return nil, fmt.Errorf("%w: stored under session %x but belongs to %x", ErrInvalidToken, sessionID, s.id)
+	}
+	return s, nil
+}
+
+// persist saves the session to its store, if any
+func (s *Session) persist() error {
+	if s.sessionStore == nil {
+		return nil
+	}
+	token, err := s.Export(s.storeKey)
+	if err != nil {
+		return err
+	}
+	if err := s.sessionStore.SaveSession(s.id, token); err != nil {
+		return fmt.Errorf("session store: %w", err)
+	}
+	return nil
+}
+
+// MemorySessionStore is a SessionStore that lives only as long as the process
+//
+// Useful for tests; it does not survive the crash it is meant to recover from.
+type MemorySessionStore struct {
+	mu     sync.Mutex
+	tokens map[[32]byte][]byte
+}
+
+// NewMemorySessionStore creates an empty in-memory session store
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{tokens: make(map[[32]byte][]byte)}
+}
+
+// SaveSession stores a copy of the token
+func (m *MemorySessionStore) SaveSession(sessionID [32]byte, token []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[sessionID] = append([]byte(nil), token...)
+	return nil
+}
+
+// LoadSession returns a copy of the stored token
+func (m *MemorySessionStore) LoadSession(sessionID [32]byte) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.tokens[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %x", ErrSessionNotFound, sessionID)
+	}
+	return append([]byte(nil), token...), nil
+}
+
+// DeleteSession forgets a session
+func (m *MemorySessionStore) DeleteSession(sessionID [32]byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, sessionID)
+	return nil
+}
+
+// FileSessionStore is a SessionStore keeping one file per session in a directory
+//
+// Files are named <session ID hex>.session and replaced atomically (write to a
+// temporary file, sync, rename), so a crash during a save leaves the previous
+// round's token intact.
+//
+// Example:
+//
+//	store, err := NewFileSessionStore("/var/lib/signer/sessions")
+type FileSessionStore struct {
+	dir string
+}
+
+// NewFileSessionStore opens (or creates) the session directory
+func NewFileSessionStore(dir string) (*FileSessionStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create session store: %w", err)
+	}
+	return &FileSessionStore{dir: dir}, nil
+}
+
+// path returns the file of a session
+func (f *FileSessionStore) path(sessionID [32]byte) string {
+	return filepath.Join(f.dir, hex.EncodeToString(sessionID[:])+".session")
+}
+
+// SaveSession atomically replaces the session file
+func (f *FileSessionStore) SaveSession(sessionID [32]byte, token []byte) error {
+	// Step 1: Write and sync a temporary file
+	tmp, err := os.CreateTemp(f.dir, ".session-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+	if _, err := tmp.Write(token); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// Step 2: Move it into place
+	return os.Rename(tmp.Name(), f.path(sessionID))
+}
+
+// LoadSession reads the session file
+func (f *FileSessionStore) LoadSession(sessionID [32]byte) ([]byte, error) {
+	token, err := os.ReadFile(f.path(sessionID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %x", ErrSessionNotFound, sessionID)
+	}
+	return token, err
+}
+
+// DeleteSession removes the session file
+func (f *FileSessionStore) DeleteSession(sessionID [32]byte) error {
+	if err := os.Remove(f.path(sessionID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package multisig
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestResumeSession tests a signer that crashes after every round and resumes from disk
+func TestResumeSession(t *testing.T) {
+	setup := newDeterministicSetup(t, 3, 3)
+	msg := []byte("crash recovery")
+	key := [32]byte{0x07}
+
+	for _, mode := range []NonceMode{NonceModeMuSig2, NonceModeCommitReveal} {
+		t.Run(mode.String(), func(t *testing.T) {
+			dir := t.TempDir()
+			store, err := NewFileSessionStore(filepath.Join(dir, "sessions"))
+			if err != nil {
+				t.Fatalf("NewFileSessionStore failed: %v", err)
+			}
+			nonces, err := NewFileNonceStore(filepath.Join(dir, "nonces.log"))
+			if err != nil {
+				t.Fatalf("NewFileNonceStore failed: %v", err)
+			}
+			defer nonces.Close()
+
+			sessions := newSessions(t, setup, msg, WithNonceMode(mode))
+			first, err := NewSession(setup, 0, msg, WithNonceMode(mode), WithSessionID(sessions[0].ID()),
+				WithNonceStore(nonces), WithSessionStore(store, key))
+			if err != nil {
+				t.Fatalf("NewSession failed: %v", err)
+			}
+			sessions[0] = first
+
+			// resume throws signer 0 away and restores it from the store
+			resume := func() {
+				t.Helper()
+				s, err := ResumeSession(setup, store, key, sessions[0].ID(), WithNonceStore(nonces))
+				if err != nil {
+					t.Fatalf("ResumeSession failed: %v", err)
+				}
+				sessions[0] = s
+			}
+
+			resume()
+			if mode == NonceModeCommitReveal {
+				exchangeCommitments(t, sessions)
+				resume()
+			}
+			exchangeNonces(t, sessions)
+			resume()
+			signAll(t, sessions)
+			resume()
+
+			complete, err := sessions[0].Finalize()
+			if err != nil {
+				t.Fatalf("Finalize failed: %v", err)
+			}
+			if !complete.VerifyAgainstAggregatedKey(msg, sessions[0].AggregatedKey()) {
+				t.Error("Signature from a resumed session does not verify")
+			}
+		})
+	}
+}
+
+// TestResumeSessionNonceSafety tests that a resumed session can never sign twice
+func TestResumeSessionNonceSafety(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 2)
+	msg := []byte("rollback")
+	key := [32]byte{0x08}
+	store := NewMemorySessionStore()
+	nonces := NewMemoryNonceStore()
+
+	sessions := newSessions(t, setup, msg, WithNonceStore(nonces))
+	id := sessions[0].ID()
+	first, err := NewSession(setup, 0, msg, WithSessionID(id), WithNonceStore(nonces), WithSessionStore(store, key))
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	sessions[0] = first
+
+	// Keep the token from before the nonce exchange, as a rolled back disk would
+	stale, err := store.LoadSession(id)
+	if err != nil {
+		t.Fatalf("LoadSession failed: %v", err)
+	}
+	exchangeNonces(t, sessions)
+	if _, err := sessions[0].Sign(); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	// The latest token carries no nonces; the stale one is refused by the nonce store
+	resumed, err := ResumeSession(setup, store, key, id, WithNonceStore(nonces))
+	if err != nil {
+		t.Fatalf("ResumeSession failed: %v", err)
+	}
+	if _, err := resumed.Sign(); !errors.Is(err, ErrNonceReused) {
+		t.Errorf("Expected ErrNonceReused after signing, got %v", err)
+	}
+	if err := store.SaveSession(id, stale); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+	if _, err := ResumeSession(setup, store, key, id, WithNonceStore(nonces)); !errors.Is(err, ErrNonceReused) {
+		t.Errorf("Expected ErrNonceReused for a rolled back token, got %v", err)
+	}
+}
+
+func TestResumeSessionErrors(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 2)
+	key := [32]byte{0x09}
+	store := NewMemorySessionStore()
+	nonces := NewMemoryNonceStore()
+
+	// Persistence without a nonce store is refused
+	if _, err := NewSession(setup, 0, []byte("msg"), WithSessionStore(store, key)); !errors.Is(err, ErrNonceStoreRequired) {
+		t.Errorf("Expected ErrNonceStoreRequired, got %v", err)
+	}
+	if _, err := ResumeSession(setup, store, key, [32]byte{0x01}); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+
+	s, err := NewSession(setup, 0, []byte("msg"), WithSessionID([32]byte{0x01}), WithNonceStore(nonces), WithSessionStore(store, key))
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	if _, err := ResumeSession(setup, store, key, s.ID()); !errors.Is(err, ErrNonceStoreRequired) {
+		t.Errorf("Expected ErrNonceStoreRequired on resume, got %v", err)
+	}
+	if _, err := ResumeSession(setup, store, [32]byte{0xff}, s.ID(), WithNonceStore(nonces)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for the wrong key, got %v", err)
+	}
+
+	// A token filed under another session ID is rejected
+	token, _ := store.LoadSession(s.ID())
+	store.SaveSession([32]byte{0x02}, token)
+	if _, err := ResumeSession(setup, store, key, [32]byte{0x02}, WithNonceStore(nonces)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a misfiled token, got %v", err)
+	}
+}
+
+// TestFileSessionStore tests save, overwrite, load and delete
+func TestFileSessionStore(t *testing.T) {
+	store, err := NewFileSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSessionStore failed: %v", err)
+	}
+	id := [32]byte{0xab}
+
+	for _, token := range [][]byte{[]byte("round 1"), []byte("round 2")} {
+		if err := store.SaveSession(id, token); err != nil {
+			t.Fatalf("SaveSession failed: %v", err)
+		}
+		got, err := store.LoadSession(id)
+		if err != nil {
+			t.Fatalf("LoadSession failed: %v", err)
+		}
+		if string(got) != string(token) {
+			t.Errorf("Expected %q, got %q", token, got)
+		}
+	}
+
+	if err := store.DeleteSession(id); err != nil {
+		t.Fatalf("DeleteSession failed: %v", err)
+	}
+	if err := store.DeleteSession(id); err != nil {
+		t.Errorf("Expected deleting twice to succeed, got %v", err)
+	}
+	if _, err := store.LoadSession(id); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
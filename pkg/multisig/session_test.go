@@ -0,0 +1,241 @@
+package multisig
+
+import (
+	"errors"
+	"testing"
+
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/fsm"
+)
+
+// newSessions starts one session per participant with a shared session ID
+func newSessions(t *testing.T, setup *MultisigSetup, msg []byte, opts ...SessionOption) []*Session {
+	t.Helper()
+	opts = append(opts, WithSessionID([32]byte{0x42}))
+	sessions := make([]*Session, len(setup.Participants))
+	for i := range sessions {
+		s, err := NewSession(setup, i, msg, opts...)
+		if err != nil {
+			t.Fatalf("NewSession(%d) failed: %v", i, err)
+		}
+		sessions[i] = s
+	}
+	return sessions
+}
+
+// exchangeCommitments delivers every signer's nonce commitment to every other signer
+func exchangeCommitments(t *testing.T, sessions []*Session) {
+	t.Helper()
+	for _, from := range sessions {
+		commitment, err := from.NonceCommitment()
+		if err != nil {
+			t.Fatalf("NonceCommitment failed: %v", err)
+		}
+		for _, to := range sessions {
+			if to != from {
+				if err := to.AddNonceCommitment(from.SignerIndex(), commitment); err != nil {
+					t.Fatalf("AddNonceCommitment failed: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// exchangeNonces delivers every signer's public nonce to every other signer
+func exchangeNonces(t *testing.T, sessions []*Session) {
+	t.Helper()
+	for _, from := range sessions {
+		nonce, err := from.PublicNonce()
+		if err != nil {
+			t.Fatalf("PublicNonce failed: %v", err)
+		}
+		for _, to := range sessions {
+			if to != from {
+				if err := to.AddPublicNonce(from.SignerIndex(), nonce); err != nil {
+					t.Fatalf("AddPublicNonce failed: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// signAll creates every partial signature and delivers it to every other signer
+func signAll(t *testing.T, sessions []*Session) {
+	t.Helper()
+	partials := make([]*PartialSignature, len(sessions))
+	for i, s := range sessions {
+		partial, err := s.Sign()
+		if err != nil {
+			t.Fatalf("Sign(%d) failed: %v", i, err)
+		}
+		partials[i] = partial
+	}
+	for i, partial := range partials {
+		for j, s := range sessions {
+			if i != j {
+				if err := s.AddPartialSignature(partial); err != nil {
+					t.Fatalf("AddPartialSignature(%d -> %d) failed: %v", i, j, err)
+				}
+			}
+		}
+	}
+}
+
+// TestSessionSigning runs complete sessions in both nonce modes
+func TestSessionSigning(t *testing.T) {
+	msg := []byte("Hello, MuSig!")
+
+	tests := []struct {
+		name  string
+		total int
+		opts  []SessionOption
+	}{
+		{"musig2 2 signers", 2, nil},
+		{"musig2 5 signers", 5, nil},
+		{"commit-reveal 3 signers", 3, []SessionOption{WithNonceCommitments()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := newDeterministicSetup(t, tt.total, tt.total)
+			sessions := newSessions(t, setup, msg, tt.opts...)
+
+			if sessions[0].Mode() == NonceModeCommitReveal {
+				if sessions[0].State() != StateCommit {
+					t.Fatalf("Expected commit state, got %s", sessions[0].State())
+				}
+				exchangeCommitments(t, sessions)
+			}
+			exchangeNonces(t, sessions)
+			signAll(t, sessions)
+
+			// Every signer ends up with the same valid signature
+			aggKey, err := setup.AggregatedKey()
+			if err != nil {
+				t.Fatalf("AggregatedKey failed: %v", err)
+			}
+			var expectedKey [32]byte
+			copy(expectedKey[:], btcschnorr.SerializePubKey(aggKey))
+
+			var first [64]byte
+			for i, s := range sessions {
+				if s.AggregatedKey() != expectedKey {
+					t.Errorf("Session %d aggregated key does not match BIP327 KeyAgg", i)
+				}
+				complete, err := s.Finalize()
+				if err != nil {
+					t.Fatalf("Finalize(%d) failed: %v", i, err)
+				}
+				if !complete.VerifyAgainstAggregatedKey(msg, expectedKey) {
+					t.Errorf("Signature from session %d does not verify", i)
+				}
+				if i == 0 {
+					first = complete.Serialize()
+				} else if complete.Serialize() != first {
+					t.Errorf("Session %d produced a different signature", i)
+				}
+			}
+		})
+	}
+}
+
+// TestSessionCommitReveal tests the ordering and binding guarantees of commit-reveal mode
+func TestSessionCommitReveal(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 2)
+	msg := []byte("commit first")
+
+	sessions := newSessions(t, setup, msg, WithNonceCommitments())
+
+	// Nonces cannot be revealed before all commitments arrived
+	if _, err := sessions[0].PublicNonce(); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState revealing early, got %v", err)
+	}
+
+	exchangeCommitments(t, sessions)
+
+	// A nonce that differs from the commitment is rejected
+	other := newSessions(t, setup, msg, WithNonceCommitments())
+	exchangeCommitments(t, other)
+	substitute, _ := other[1].PublicNonce()
+	if err := sessions[0].AddPublicNonce(1, substitute); !errors.Is(err, ErrCommitmentMismatch) {
+		t.Errorf("Expected ErrCommitmentMismatch, got %v", err)
+	}
+
+	// MuSig2 sessions do not use commitments
+	musig2 := newSessions(t, setup, msg)
+	if _, err := musig2[0].NonceCommitment(); err == nil {
+		t.Error("Expected error requesting a commitment in MuSig2 mode")
+	}
+}
+
+// TestSessionSafeguards tests nonce reuse protection and partial signature verification
+func TestSessionSafeguards(t *testing.T) {
+	setup := newDeterministicSetup(t, 3, 3)
+	msg := []byte("sign once")
+
+	sessions := newSessions(t, setup, msg)
+
+	// Signing before all nonces are known is not allowed
+	if _, err := sessions[0].Sign(); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState, got %v", err)
+	}
+
+	exchangeNonces(t, sessions)
+
+	partial, err := sessions[0].Sign()
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if _, err := sessions[0].Sign(); !errors.Is(err, ErrNonceReused) {
+		t.Errorf("Expected ErrNonceReused, got %v", err)
+	}
+
+	// A tampered partial signature is rejected
+	tampered := *partial
+	tampered.S[31] ^= 0x01
+	if err := sessions[1].AddPartialSignature(&tampered); !errors.Is(err, ErrInvalidPartialSignature) {
+		t.Errorf("Expected ErrInvalidPartialSignature, got %v", err)
+	}
+	if err := sessions[1].AddPartialSignature(partial); err != nil {
+		t.Errorf("Valid partial signature rejected: %v", err)
+	}
+
+	// Not complete yet
+	if _, err := sessions[1].Finalize(); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState finalizing early, got %v", err)
+	}
+
+	// Invalid inputs
+	if err := sessions[1].AddPartialSignature(partial); err == nil {
+		t.Error("Expected error for duplicate partial signature")
+	}
+	if _, err := NewSession(setup, 5, msg); err == nil {
+		t.Error("Expected error for invalid signer index")
+	}
+	if _, err := NewSession(setup, 0, nil); err == nil {
+		t.Error("Expected error for empty message")
+	}
+	if _, err := NewSession(setup, 0, msg, WithNonceMode(NonceMode(7))); err == nil {
+		t.Error("Expected error for unknown nonce mode")
+	}
+}
+
+// TestSessionLifecycle checks the session state machine and that wrong-round calls match both sentinels
+func TestSessionLifecycle(t *testing.T) {
+	if err := sessionLifecycle.Validate(); err != nil {
+		t.Fatalf("Invalid session lifecycle: %v", err)
+	}
+
+	setup := newDeterministicSetup(t, 2, 2)
+	sessions := newSessions(t, setup, []byte("lifecycle"), WithNonceMode(NonceModeCommitReveal))
+	if sessions[0].State() != StateCommit {
+		t.Fatalf("Expected a commit-reveal session to start in StateCommit, got %s", sessions[0].State())
+	}
+	_, err := sessions[0].Finalize()
+	if !errors.Is(err, ErrWrongState) || !errors.Is(err, fsm.ErrIllegalTransition) {
+		t.Errorf("Expected ErrWrongState and fsm.ErrIllegalTransition, got %v", err)
+	}
+	if err := sessions[0].AddPublicNonce(1, make([]byte, 33)); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState for a nonce before the commitments, got %v", err)
+	}
+}
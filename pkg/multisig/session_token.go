@@ -0,0 +1,236 @@
+package multisig
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+	"github.com/neverDefined/cryptography-playground/pkg/fsm"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// Encrypted session tokens
+//
+// A constrained signer (HSM-like, serverless) can export its session after a
+// round as an opaque AES-256-GCM token, hand it to the coordinator, and import
+// it again when the next round's data arrives. The token holds the secret
+// nonces, so it is encrypted and authenticated under a key only the signer
+// knows; the private key itself is never included and must be supplied via
+// the setup on import.
+//
+// A token taken before Sign can be imported twice. Use WithNonceStore on import
+// so the second Sign is refused, otherwise the private key can leak.
+
+// sessionTokenVersion is the first byte of every token
+const sessionTokenVersion = 0x01
+
+// sessionTokenAAD is bound to every token as additional authenticated data
+const sessionTokenAAD = "multisig/session-token/v1"
+
+// ErrInvalidToken is returned when a token cannot be decrypted or does not match the setup
+var ErrInvalidToken = errors.New("invalid session token")
+
+// sessionSnapshot is the serialized form of a Session
+type sessionSnapshot struct {
+	ID          [32]byte            `json:"id"`
+	Mode        NonceMode           `json:"mode"`
+	State       SessionState        `json:"state"`
+	Signer      int                 `json:"signer"`
+	Msg         [32]byte            `json:"msg"`
+	KeyList     [32]byte            `json:"key_list"` // Binds the token to the setup
+	SecNonces   [][32]byte          `json:"sec_nonces,omitempty"`
+	NonceUsed   bool                `json:"nonce_used"`
+	Commitments map[int][32]byte    `json:"commitments"`
+	PubNonces   map[int][]byte      `json:"pub_nonces"`
+	Partials    []*PartialSignature `json:"partials,omitempty"`
+}
+
+// Export serializes the session into an encrypted token
+//
+// key is a 32-byte secret of the signer (e.g. derived from its own storage key).
+//
+// Example:
+//
+//	token, _ := session.Export(key)
+//	// ... later, possibly in another process ...
+//	session, _ = multisig.ImportSession(setup, token, key, multisig.WithNonceStore(store))
+func (s *Session) Export(key [32]byte) ([]byte, error) {
+	// Step 1: Snapshot the session state
+	snapshot := sessionSnapshot{
+		ID:          s.id,
+		Mode:        s.mode,
+		State:       s.machine.State(),
+		Signer:      s.signer,
+		Msg:         s.msg,
+		KeyList:     setupKeyList(s.setup),
+		NonceUsed:   s.nonceUsed,
+		Commitments: s.commitments,
+		PubNonces:   s.pubNonces,
+	}
+	for j := range s.secNonces {
+		snapshot.SecNonces = append(snapshot.SecNonces, s.secNonces[j].Bytes())
+	}
+	for i := range s.setup.Participants {
+		if partial, ok := s.partials[i]; ok {
+			snapshot.Partials = append(snapshot.Partials, partial)
+		}
+	}
+
+	plaintext, err := json.Marshal(&snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session: %w", err)
+	}
+	defer clear(plaintext)
+	for j := range snapshot.SecNonces {
+		clear(snapshot.SecNonces[j][:])
+	}
+
+	// Step 2: Encrypt as version || nonce || AES-256-GCM(snapshot)
+	aead, err := newTokenAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	token := make([]byte, 1+aead.NonceSize(), 1+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	token[0] = sessionTokenVersion
+	if _, err := rand.Read(token[1:]); err != nil {
+		return nil, fmt.Errorf("failed to generate token nonce: %w", err)
+	}
+	return aead.Seal(token, token[1:], plaintext, []byte(sessionTokenAAD)), nil
+}
+
+// ImportSession restores a session exported with Session.Export
+//
+// setup must be the same setup (same keys, same order) the session was created
+// with, and must hold the signer's private key. Only WithNonceStore and
+// WithSessionStore are honoured among the options; the nonce store is checked
+// before the session is returned.
+func ImportSession(setup *MultisigSetup, token []byte, key [32]byte, opts ...SessionOption) (*Session, error) {
+	if setup == nil {
+		return nil, errors.New("setup cannot be nil")
+	}
+
+	// Step 1: Decrypt and authenticate
+	aead, err := newTokenAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(token) < 1+aead.NonceSize()+aead.Overhead() || token[0] != sessionTokenVersion {
+		return nil, ErrInvalidToken
+	}
+	nonce := token[1 : 1+aead.NonceSize()]
+	plaintext, err := aead.Open(nil, nonce, token[1+aead.NonceSize():], []byte(sessionTokenAAD))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	defer clear(plaintext)
+
+	var snapshot sessionSnapshot
+	if err := json.Unmarshal(plaintext, &snapshot); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	// Step 2: Check the token belongs to this setup and signer
+	if snapshot.KeyList != setupKeyList(setup) {
+		return nil, fmt.Errorf("%w: setup does not match", ErrInvalidToken)
+	}
+	if snapshot.Signer < 0 || snapshot.Signer >= len(setup.Participants) || setup.Participants[snapshot.Signer].PrivateKey == nil {
+		return nil, fmt.Errorf("%w: signer has no private key in setup", ErrInvalidToken)
+	}
+
+	options := &sessionOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.sessionStore != nil && options.nonceStore == nil {
+		return nil, ErrNonceStoreRequired
+	}
+
+	// Step 3: Rebuild the session, recomputing everything derived from the setup
+	machine, err := fsm.Restore(sessionLifecycle, fsm.Snapshot[SessionState]{Protocol: sessionLifecycle.Name, State: snapshot.State})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	s := &Session{
+		id:           snapshot.ID,
+		mode:         snapshot.Mode,
+		machine:      machine,
+		setup:        setup,
+		signer:       snapshot.Signer,
+		msg:          snapshot.Msg,
+		nonceUsed:    snapshot.NonceUsed,
+		nonceStore:   options.nonceStore,
+		commitments:  snapshot.Commitments,
+		sessionStore: options.sessionStore,
+		storeKey:     options.storeKey,
+		pubNonces:    snapshot.PubNonces,
+		partials:     make(map[int]*PartialSignature),
+	}
+	if s.commitments == nil {
+		s.commitments = make(map[int][32]byte)
+	}
+	if s.pubNonces == nil {
+		s.pubNonces = make(map[int][]byte)
+	}
+	for _, partial := range snapshot.Partials {
+		s.partials[partial.Index] = partial
+	}
+	if len(snapshot.SecNonces) > 0 {
+		s.secNonces = make([]btcec.ModNScalar, len(snapshot.SecNonces))
+	}
+	for j := range snapshot.SecNonces {
+		s.secNonces[j].SetBytes(&snapshot.SecNonces[j])
+		clear(snapshot.SecNonces[j][:])
+	}
+
+	s.coefficients, s.aggKey, err = keyAggregation(setup)
+	if err != nil {
+		return nil, err
+	}
+	s.aggKeyOdd = !arithmetic.HasEvenY(arithmetic.PointFromPubKey(s.aggKey))
+	if s.State() >= StateSign {
+		if len(s.pubNonces) != len(setup.Participants) {
+			return nil, fmt.Errorf("%w: missing public nonces", ErrInvalidToken)
+		}
+		if err := s.aggregateNonces(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 4: Refuse tokens of sessions this key already signed in
+	if s.nonceStore != nil && !s.nonceUsed {
+		pubKey := setup.Participants[s.signer].PublicKey.SerializeCompressed()
+		used, err := s.nonceStore.HasSession(pubKey, s.id)
+		if err != nil {
+			return nil, fmt.Errorf("nonce store: %w", err)
+		}
+		if used {
+			return nil, fmt.Errorf("%w: key already signed in session %x", ErrNonceReused, s.id)
+		}
+	}
+	return s, nil
+}
+
+// newTokenAEAD creates the AES-256-GCM cipher for session tokens
+func newTokenAEAD(key [32]byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// setupKeyList hashes the ordered participant keys of a setup
+func setupKeyList(setup *MultisigSetup) [32]byte {
+	keys := make([][]byte, 0, len(setup.Participants))
+	for _, p := range setup.Participants {
+		if p.PublicKey != nil {
+			keys = append(keys, p.PublicKey.SerializeCompressed())
+		}
+	}
+	return schnorr.TaggedHash("KeyAgg list", keys...)
+}
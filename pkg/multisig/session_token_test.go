@@ -0,0 +1,111 @@
+package multisig
+
+import (
+	"errors"
+	"testing"
+)
+
+// roundTrip exports a session and imports it again, as a stateless signer would
+func roundTrip(t *testing.T, s *Session, key [32]byte, opts ...SessionOption) *Session {
+	t.Helper()
+	token, err := s.Export(key)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	imported, err := ImportSession(s.setup, token, key, opts...)
+	if err != nil {
+		t.Fatalf("ImportSession failed: %v", err)
+	}
+	return imported
+}
+
+// TestSessionTokenSigning tests a signer that keeps no state between rounds
+func TestSessionTokenSigning(t *testing.T) {
+	setup := newDeterministicSetup(t, 3, 3)
+	msg := []byte("stateless signer")
+	key := [32]byte{0x01, 0x02, 0x03}
+
+	for _, mode := range []NonceMode{NonceModeMuSig2, NonceModeCommitReveal} {
+		t.Run(mode.String(), func(t *testing.T) {
+			sessions := newSessions(t, setup, msg, WithNonceMode(mode))
+
+			// Signer 0 is restored from a token after every round
+			sessions[0] = roundTrip(t, sessions[0], key)
+			if mode == NonceModeCommitReveal {
+				exchangeCommitments(t, sessions)
+				sessions[0] = roundTrip(t, sessions[0], key)
+			}
+			exchangeNonces(t, sessions)
+			sessions[0] = roundTrip(t, sessions[0], key)
+			if sessions[0].State() != StateSign {
+				t.Fatalf("Imported state = %s, expected sign", sessions[0].State())
+			}
+			signAll(t, sessions)
+			sessions[0] = roundTrip(t, sessions[0], key)
+
+			complete, err := sessions[0].Finalize()
+			if err != nil {
+				t.Fatalf("Finalize failed: %v", err)
+			}
+			if !complete.VerifyAgainstAggregatedKey(msg, sessions[0].AggregatedKey()) {
+				t.Error("Signature from a restored session does not verify")
+			}
+
+			// A token taken after signing carries no nonces and cannot sign again
+			if _, err := sessions[0].Sign(); !errors.Is(err, ErrNonceReused) {
+				t.Errorf("Expected ErrNonceReused, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSessionTokenErrors(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 2)
+	key := [32]byte{0xaa}
+	sessions := newSessions(t, setup, []byte("tokens"))
+
+	token, err := sessions[0].Export(key)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	// Wrong key, tampering and truncation are all rejected
+	if _, err := ImportSession(setup, token, [32]byte{0xbb}); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for the wrong key, got %v", err)
+	}
+	tampered := append([]byte(nil), token...)
+	tampered[len(tampered)-1] ^= 0x01
+	if _, err := ImportSession(setup, tampered, key); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a tampered token, got %v", err)
+	}
+	if _, err := ImportSession(setup, token[:10], key); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a truncated token, got %v", err)
+	}
+
+	// Tokens are randomized, so equal states do not produce equal tokens
+	again, _ := sessions[0].Export(key)
+	if string(again) == string(token) {
+		t.Error("Two exports produced the same token")
+	}
+
+	// The setup must match
+	other := newDeterministicSetup(t, 3, 3)
+	if _, err := ImportSession(other, token, key); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for another setup, got %v", err)
+	}
+
+	// Replaying a pre-signing token is refused by the nonce store
+	store := NewMemoryNonceStore()
+	exchangeNonces(t, sessions)
+	beforeSign, _ := sessions[0].Export(key)
+	first, err := ImportSession(setup, beforeSign, key, WithNonceStore(store))
+	if err != nil {
+		t.Fatalf("ImportSession failed: %v", err)
+	}
+	if _, err := first.Sign(); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if _, err := ImportSession(setup, beforeSign, key, WithNonceStore(store)); !errors.Is(err, ErrNonceReused) {
+		t.Errorf("Expected ErrNonceReused for a replayed token, got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package multisig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
+	"github.com/neverDefined/cryptography-playground/pkg/bech32"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+// Taproot multisig: MuSig2 aggregated key on the key path, multi_a tapscript leaf on the script path
+//
+// The n-of-n key path is cheap and private (it looks like a single-key spend).
+// If not everyone is available, any k participants can spend through the
+// OP_CHECKSIGADD leaf instead (BIP342).
+
+const (
+	// opCheckSig (OP_CHECKSIG) verifies a BIP340 signature in tapscript
+	opCheckSig = 0xac
+	// opCheckSigAdd (OP_CHECKSIGADD) adds 1 to a counter if the signature is valid
+	opCheckSigAdd = 0xba
+	// opNumEqual (OP_NUMEQUAL) compares the counter against the threshold
+	opNumEqual = 0x9c
+
+	// maxTapscriptMultisigKeys is the standardness limit for multi_a keys
+	maxTapscriptMultisigKeys = 999
+)
+
+// MultiAScript builds the k-of-n OP_CHECKSIGADD tapscript leaf (descriptor multi_a)
+//
+// Keys are serialized x-only (32 bytes) in participant order.
+//
+// Format: [0x20][x_1][OP_CHECKSIG][0x20][x_2][OP_CHECKSIGADD]...[0x20][x_n][OP_CHECKSIGADD][k][OP_NUMEQUAL]
+//
+// Example:
+//
+//	setup, _ := NewMultisigSetup(participants, 2) // 2-of-3
+//	leaf, err := setup.MultiAScript()
+//	// Result: 20 <x1> ac 20 <x2> ba 20 <x3> ba 52 9c (104 bytes)
+func (setup *MultisigSetup) MultiAScript() ([]byte, error) {
+	// Step 1: Validate the key count and threshold
+	if len(setup.Participants) == 0 {
+		return nil, errors.New("at least one participant is required")
+	}
+	if len(setup.Participants) > maxTapscriptMultisigKeys {
+		return nil, fmt.Errorf("at most %d participants are supported", maxTapscriptMultisigKeys)
+	}
+	if setup.Threshold <= 0 || setup.Threshold > len(setup.Participants) {
+		return nil, errors.New("invalid threshold")
+	}
+
+	// Step 2: First key uses OP_CHECKSIG, the rest accumulate with OP_CHECKSIGADD
+	script := make([]byte, 0, 34*len(setup.Participants)+4)
+	for i, p := range setup.Participants {
+		if p.PublicKey == nil {
+			return nil, errors.New("all participants must have a public key")
+		}
+		script = append(script, 0x20)
+		script = append(script, btcschnorr.SerializePubKey(p.PublicKey)...)
+		if i == 0 {
+			script = append(script, opCheckSig)
+		} else {
+			script = append(script, opCheckSigAdd)
+		}
+	}
+
+	// Step 3: Require exactly k valid signatures
+	script = append(script, tx.ScriptNum(int64(setup.Threshold))...)
+	script = append(script, opNumEqual)
+	return script, nil
+}
+
+// TapLeafHash returns the BIP341 leaf hash of the multi_a script
+//
+// Formula: TaggedHash("TapLeaf", 0xc0 || compactSize(len(script)) || script)
+func (setup *MultisigSetup) TapLeafHash() ([32]byte, error) {
+	script, err := setup.MultiAScript()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return hash.TapLeafHash(hash.TapLeafVersion, script), nil
+}
+
+// AggregatedKey returns the MuSig2 (BIP327) aggregate of all participant keys
+//
+// Keys are aggregated in participant order, without sorting. This is the
+// taproot internal key used for the n-of-n key path.
+func (setup *MultisigSetup) AggregatedKey() (*btcec.PublicKey, error) {
+	if len(setup.Participants) == 0 {
+		return nil, errors.New("at least one participant is required")
+	}
+	keys := make([]*btcec.PublicKey, len(setup.Participants))
+	for i, p := range setup.Participants {
+		if p.PublicKey == nil {
+			return nil, errors.New("all participants must have a public key")
+		}
+		keys[i] = p.PublicKey
+	}
+
+	aggKey, _, _, err := musig2.AggregateKeys(keys, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate keys: %w", err)
+	}
+	return aggKey.PreTweakedKey, nil
+}
+
+// TaprootOutput describes a P2TR output with a key path and a multi_a script path
+type TaprootOutput struct {
+	InternalKey  schnorr.XOnlyPublicKey // x-only MuSig2 aggregated key (key path)
+	OutputKey    schnorr.XOnlyPublicKey // x-only tweaked key Q = P + t*G committed in the scriptPubKey
+	OutputKeyOdd bool                   // Parity of Q's y coordinate (encoded in the control block)
+	MerkleRoot   [32]byte               // Script tree root (the single multi_a leaf hash)
+	LeafScript   []byte                 // The multi_a tapscript
+	ControlBlock []byte                 // Control block revealing the internal key for a script path spend
+}
+
+// TaprootOutput builds the P2TR output for the setup
+//
+// The internal key is the MuSig2 aggregate of all participants; the script tree
+// holds a single multi_a leaf, so the merkle root equals the leaf hash.
+//
+// Formula:
+//
+//	t = TaggedHash("TapTweak", P || merkleRoot)
+//	Q = P + t*G
+//
+// Example:
+//
+//	out, err := setup.TaprootOutput()
+//	address, _ := out.Address("bc") // "bc1p..."
+func (setup *MultisigSetup) TaprootOutput() (*TaprootOutput, error) {
+	// Step 1: Build the leaf and its hash
+	leaf, err := setup.MultiAScript()
+	if err != nil {
+		return nil, err
+	}
+	root := hash.TapLeafHash(hash.TapLeafVersion, leaf)
+
+	// Step 2: Aggregate the keys into the internal key (lifted to even y)
+	aggKey, err := setup.AggregatedKey()
+	if err != nil {
+		return nil, err
+	}
+	internal := schnorr.XOnlyFromPub(aggKey)
+
+	// Step 3: Q = P + TaggedHash("TapTweak", P || root)*G
+	outputKey, oddY, err := schnorr.TweakPubKey(aggKey, root[:])
+	if err != nil {
+		return nil, err
+	}
+
+	out := &TaprootOutput{
+		InternalKey:  internal,
+		OutputKey:    outputKey,
+		OutputKeyOdd: oddY,
+		MerkleRoot:   root,
+		LeafScript:   leaf,
+	}
+
+	// Step 4: Control block = (leaf version | parity) || internal key (no merkle path)
+	controlByte := byte(hash.TapLeafVersion)
+	if out.OutputKeyOdd {
+		controlByte |= 0x01
+	}
+	out.ControlBlock = append([]byte{controlByte}, internal[:]...)
+	return out, nil
+}
+
+// ScriptPubKey returns the output script locking funds to the taproot output
+//
+// Format: [OP_1][0x20][outputKey]
+func (out *TaprootOutput) ScriptPubKey() []byte {
+	return append([]byte{op1, 0x20}, out.OutputKey[:]...)
+}
+
+// Address returns the bech32m P2TR address for a network ("bc", "tb" or "bcrt")
+func (out *TaprootOutput) Address(hrp string) (string, error) {
+	return bech32.SegWitAddressEncode(hrp, 1, out.OutputKey[:])
+}
+
+// TapscriptWitness assembles the witness stack for a multi_a script path spend
+//
+// sigs maps participant indices to 64/65-byte BIP340 signatures. Exactly Threshold
+// signatures are required; participants that do not sign get an empty item.
+// Items are in reverse key order because the first key in the script consumes
+// the top of the stack.
+//
+// Format: [sig_n or <>]...[sig_1 or <>][leafScript][controlBlock]
+//
+// Example:
+//
+//	witness, err := setup.TapscriptWitness(map[int][]byte{0: sigAlice, 2: sigCarol})
+//	// Result: [][]byte{sigCarol, {}, sigAlice, leafScript, controlBlock}
+func (setup *MultisigSetup) TapscriptWitness(sigs map[int][]byte) ([][]byte, error) {
+	// Step 1: Validate the signatures
+	if len(sigs) != setup.Threshold {
+		return nil, fmt.Errorf("expected %d signatures, got %d", setup.Threshold, len(sigs))
+	}
+	for index, sig := range sigs {
+		if index < 0 || index >= len(setup.Participants) {
+			return nil, fmt.Errorf("invalid participant index: %d", index)
+		}
+		if len(sig) != btcschnorr.SignatureSize && len(sig) != btcschnorr.SignatureSize+1 {
+			return nil, fmt.Errorf("invalid signature length for participant %d: %d", index, len(sig))
+		}
+	}
+
+	out, err := setup.TaprootOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: One item per key, last participant first
+	witness := make([][]byte, 0, len(setup.Participants)+2)
+	for i := len(setup.Participants) - 1; i >= 0; i-- {
+		if sig, ok := sigs[i]; ok {
+			witness = append(witness, sig)
+		} else {
+			witness = append(witness, []byte{})
+		}
+	}
+
+	// Step 3: Reveal the script and the control block
+	witness = append(witness, out.LeafScript, out.ControlBlock)
+	return witness, nil
+}
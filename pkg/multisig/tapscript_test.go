@@ -0,0 +1,146 @@
+package multisig
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
+)
+
+// TestMultiAScript tests the OP_CHECKSIGADD leaf layout
+func TestMultiAScript(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 3)
+
+	script, err := setup.MultiAScript()
+	if err != nil {
+		t.Fatalf("Failed to build multi_a script: %v", err)
+	}
+
+	// 3 * (1 + 32 + 1) + OP_2 + OP_NUMEQUAL
+	if len(script) != 104 {
+		t.Errorf("Expected 104-byte script, got %d", len(script))
+	}
+	for i, p := range setup.Participants {
+		chunk := script[i*34 : (i+1)*34]
+		if chunk[0] != 0x20 || !bytes.Equal(chunk[1:33], btcschnorr.SerializePubKey(p.PublicKey)) {
+			t.Errorf("Key %d not pushed x-only in participant order", i)
+		}
+		expectedOp := byte(0xba)
+		if i == 0 {
+			expectedOp = 0xac
+		}
+		if chunk[33] != expectedOp {
+			t.Errorf("Key %d followed by %#x, expected %#x", i, chunk[33], expectedOp)
+		}
+	}
+	if !bytes.Equal(script[len(script)-2:], []byte{0x52, 0x9c}) {
+		t.Errorf("Expected OP_2 OP_NUMEQUAL suffix, got %x", script[len(script)-2:])
+	}
+
+	invalid := newDeterministicSetup(t, 2, 3)
+	invalid.Threshold = 4
+	if _, err := invalid.MultiAScript(); err == nil {
+		t.Error("Expected error for threshold above participant count")
+	}
+}
+
+// TestTaprootOutput cross-checks the tweaked output key against MuSig2 taproot key aggregation
+func TestTaprootOutput(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 3)
+
+	out, err := setup.TaprootOutput()
+	if err != nil {
+		t.Fatalf("Failed to build taproot output: %v", err)
+	}
+
+	leafHash, err := setup.TapLeafHash()
+	if err != nil {
+		t.Fatalf("Failed to hash leaf: %v", err)
+	}
+	if out.MerkleRoot != leafHash {
+		t.Error("Merkle root of a single-leaf tree should equal the leaf hash")
+	}
+
+	// Internal key is the untweaked MuSig2 aggregate
+	aggKey, err := setup.AggregatedKey()
+	if err != nil {
+		t.Fatalf("Failed to aggregate keys: %v", err)
+	}
+	if !bytes.Equal(out.InternalKey[:], btcschnorr.SerializePubKey(aggKey)) {
+		t.Error("Internal key does not match the aggregated key")
+	}
+
+	// Output key must match musig2's BIP341 tweak of the same aggregate
+	keys := []*btcec.PublicKey{}
+	for _, p := range setup.Participants {
+		keys = append(keys, p.PublicKey)
+	}
+	tweaked, _, _, err := musig2.AggregateKeys(keys, false, musig2.WithTaprootKeyTweak(leafHash[:]))
+	if err != nil {
+		t.Fatalf("musig2.AggregateKeys failed: %v", err)
+	}
+	if !bytes.Equal(out.OutputKey[:], btcschnorr.SerializePubKey(tweaked.FinalKey)) {
+		t.Errorf("Output key %s does not match musig2 tweaked key %x", out.OutputKey, btcschnorr.SerializePubKey(tweaked.FinalKey))
+	}
+	oddY := tweaked.FinalKey.SerializeCompressed()[0] == 0x03
+	if out.OutputKeyOdd != oddY {
+		t.Error("Output key parity does not match")
+	}
+
+	// Control block: leaf version with parity bit, then the internal key
+	if len(out.ControlBlock) != 33 || out.ControlBlock[0]&0xfe != 0xc0 {
+		t.Errorf("Unexpected control block %x", out.ControlBlock)
+	}
+	if (out.ControlBlock[0]&0x01 == 1) != out.OutputKeyOdd {
+		t.Error("Control block parity bit does not match the output key")
+	}
+
+	// scriptPubKey and address
+	script := out.ScriptPubKey()
+	if len(script) != 34 || script[0] != 0x51 || script[1] != 0x20 {
+		t.Errorf("Unexpected scriptPubKey %x", script)
+	}
+	address, err := out.Address("bc")
+	if err != nil {
+		t.Fatalf("Failed to encode address: %v", err)
+	}
+	if !strings.HasPrefix(address, "bc1p") || len(address) != 62 {
+		t.Errorf("Unexpected address %s", address)
+	}
+}
+
+// TestTapscriptWitness tests the reverse key ordering of the script path witness
+func TestTapscriptWitness(t *testing.T) {
+	setup := newDeterministicSetup(t, 2, 3)
+	sigAlice := bytes.Repeat([]byte{0xaa}, 64)
+	sigCarol := bytes.Repeat([]byte{0xcc}, 65)
+
+	witness, err := setup.TapscriptWitness(map[int][]byte{0: sigAlice, 2: sigCarol})
+	if err != nil {
+		t.Fatalf("Failed to build witness: %v", err)
+	}
+	if len(witness) != 5 {
+		t.Fatalf("Expected 5 witness items, got %d", len(witness))
+	}
+	if !bytes.Equal(witness[0], sigCarol) || len(witness[1]) != 0 || !bytes.Equal(witness[2], sigAlice) {
+		t.Error("Signatures are not in reverse key order")
+	}
+
+	out, _ := setup.TaprootOutput()
+	if !bytes.Equal(witness[3], out.LeafScript) || !bytes.Equal(witness[4], out.ControlBlock) {
+		t.Error("Witness must end with the leaf script and control block")
+	}
+
+	if _, err := setup.TapscriptWitness(map[int][]byte{0: sigAlice}); err == nil {
+		t.Error("Expected error for too few signatures")
+	}
+	if _, err := setup.TapscriptWitness(map[int][]byte{0: sigAlice, 1: {0x01}}); err == nil {
+		t.Error("Expected error for an invalid signature length")
+	}
+	if _, err := setup.TapscriptWitness(map[int][]byte{0: sigAlice, 5: sigCarol}); err == nil {
+		t.Error("Expected error for an invalid participant index")
+	}
+}
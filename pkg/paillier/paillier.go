@@ -0,0 +1,183 @@
+package paillier
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// Paillier is an additively homomorphic public-key encryption scheme
+//
+// For public key n (= p*q) and g = n + 1:
+//
+//	Enc(m; r)   = g^m * r^n mod n²
+//	Dec(c)      = L(c^λ mod n²) * μ mod n,  L(x) = (x - 1) / n
+//
+// Homomorphic properties used by threshold ECDSA:
+//
+//	Enc(a) * Enc(b)  = Enc(a + b)
+//	Enc(a)^k         = Enc(k * a)
+
+var one = big.NewInt(1)
+
+// ErrMessageTooLarge is returned when a plaintext is not in [0, n)
+var ErrMessageTooLarge = errors.New("message must be in [0, n)")
+
+// PublicKey is a Paillier public key
+type PublicKey struct {
+	N        *big.Int // Modulus n = p*q
+	NSquared *big.Int // Cached n²
+}
+
+// PrivateKey is a Paillier private key
+type PrivateKey struct {
+	PublicKey
+	Lambda *big.Int // λ = lcm(p-1, q-1)
+	Mu     *big.Int // μ = λ⁻¹ mod n
+}
+
+// NewPublicKey creates a public key from its modulus
+func NewPublicKey(n *big.Int) (*PublicKey, error) {
+	if n == nil || n.Sign() <= 0 || n.Bit(0) == 0 {
+		return nil, errors.New("modulus must be a positive odd integer")
+	}
+	return &PublicKey{N: new(big.Int).Set(n), NSquared: new(big.Int).Mul(n, n)}, nil
+}
+
+// GenerateKey creates a Paillier key pair with a bits-bit modulus
+//
+// Example:
+//
+//	priv, err := paillier.GenerateKey(rand.Reader, 2048)
+//	c, _ := priv.Encrypt(rand.Reader, big.NewInt(42))
+//	m, _ := priv.Decrypt(c) // 42
+func GenerateKey(random io.Reader, bits int) (*PrivateKey, error) {
+	if bits < 512 {
+		return nil, errors.New("modulus must be at least 512 bits")
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+
+	for {
+		// Step 1: Two distinct primes of half the size
+		p, err := rand.Prime(random, bits/2)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate prime: %w", err)
+		}
+		q, err := rand.Prime(random, bits-bits/2)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate prime: %w", err)
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+
+		n := new(big.Int).Mul(p, q)
+		if n.BitLen() != bits {
+			continue
+		}
+
+		// Step 2: gcd(n, (p-1)(q-1)) must be 1 (always true for equal-size primes, checked anyway)
+		pm1 := new(big.Int).Sub(p, one)
+		qm1 := new(big.Int).Sub(q, one)
+		phi := new(big.Int).Mul(pm1, qm1)
+		if new(big.Int).GCD(nil, nil, n, phi).Cmp(one) != 0 {
+			continue
+		}
+
+		// Step 3: λ = lcm(p-1, q-1) = (p-1)(q-1) / gcd(p-1, q-1)
+		gcd := new(big.Int).GCD(nil, nil, pm1, qm1)
+		lambda := new(big.Int).Div(phi, gcd)
+
+		// Step 4: With g = n+1, L(g^λ mod n²) = λ mod n, so μ = λ⁻¹ mod n
+		mu := new(big.Int).ModInverse(lambda, n)
+		if mu == nil {
+			continue
+		}
+
+		pub, _ := NewPublicKey(n)
+		return &PrivateKey{PublicKey: *pub, Lambda: lambda, Mu: mu}, nil
+	}
+}
+
+// Encrypt encrypts m ∈ [0, n) with fresh randomness
+func (pk *PublicKey) Encrypt(random io.Reader, m *big.Int) (*big.Int, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+
+	// r must be a unit mod n
+	for {
+		r, err := rand.Int(random, pk.N)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate randomness: %w", err)
+		}
+		if r.Sign() == 0 || new(big.Int).GCD(nil, nil, r, pk.N).Cmp(one) != 0 {
+			continue
+		}
+		return pk.EncryptWithNonce(m, r)
+	}
+}
+
+// EncryptWithNonce encrypts m with the given randomness r
+//
+// Formula: c = (1 + m*n) * r^n mod n²   (since g^m = (1+n)^m = 1 + m*n mod n²)
+func (pk *PublicKey) EncryptWithNonce(m, r *big.Int) (*big.Int, error) {
+	if m == nil || m.Sign() < 0 || m.Cmp(pk.N) >= 0 {
+		return nil, ErrMessageTooLarge
+	}
+	if r == nil || r.Sign() <= 0 || r.Cmp(pk.N) >= 0 {
+		return nil, errors.New("nonce must be in (0, n)")
+	}
+
+	gm := new(big.Int).Mul(m, pk.N)
+	gm.Add(gm, one)
+	gm.Mod(gm, pk.NSquared)
+
+	rn := new(big.Int).Exp(r, pk.N, pk.NSquared)
+	return gm.Mul(gm, rn).Mod(gm, pk.NSquared), nil
+}
+
+// Add returns a ciphertext of the sum of the plaintexts of c1 and c2
+//
+// Formula: Enc(a) * Enc(b) mod n² = Enc(a + b mod n)
+func (pk *PublicKey) Add(c1, c2 *big.Int) *big.Int {
+	sum := new(big.Int).Mul(c1, c2)
+	return sum.Mod(sum, pk.NSquared)
+}
+
+// MulConst returns a ciphertext of k times the plaintext of c
+//
+// Formula: Enc(a)^k mod n² = Enc(k * a mod n)
+func (pk *PublicKey) MulConst(c, k *big.Int) *big.Int {
+	return new(big.Int).Exp(c, k, pk.NSquared)
+}
+
+// ValidateCiphertext checks that c is in [1, n²) and a unit mod n²
+func (pk *PublicKey) ValidateCiphertext(c *big.Int) error {
+	if c == nil || c.Sign() <= 0 || c.Cmp(pk.NSquared) >= 0 {
+		return errors.New("ciphertext out of range")
+	}
+	if new(big.Int).GCD(nil, nil, c, pk.N).Cmp(one) != 0 {
+		return errors.New("ciphertext is not a unit")
+	}
+	return nil
+}
+
+// Decrypt recovers the plaintext of c
+//
+// Formula: m = L(c^λ mod n²) * μ mod n
+func (sk *PrivateKey) Decrypt(c *big.Int) (*big.Int, error) {
+	if err := sk.ValidateCiphertext(c); err != nil {
+		return nil, err
+	}
+
+	x := new(big.Int).Exp(c, sk.Lambda, sk.NSquared)
+	x.Sub(x, one)
+	x.Div(x, sk.N)
+	x.Mul(x, sk.Mu)
+	return x.Mod(x, sk.N), nil
+}
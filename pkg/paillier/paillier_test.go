@@ -0,0 +1,102 @@
+package paillier
+
+import (
+	"crypto/rand"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+var (
+	testKeyOnce sync.Once
+	testKey     *PrivateKey
+)
+
+// newTestKey returns a shared 1024-bit key (generation is slow)
+func newTestKey(t *testing.T) *PrivateKey {
+	t.Helper()
+	testKeyOnce.Do(func() {
+		key, err := GenerateKey(rand.Reader, 1024)
+		if err != nil {
+			t.Fatalf("GenerateKey failed: %v", err)
+		}
+		testKey = key
+	})
+	if testKey == nil {
+		t.Fatal("test key unavailable")
+	}
+	return testKey
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := newTestKey(t)
+	if key.N.BitLen() != 1024 {
+		t.Errorf("Expected 1024-bit modulus, got %d", key.N.BitLen())
+	}
+
+	maxPlain := new(big.Int).Sub(key.N, big.NewInt(1))
+	for _, m := range []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(42), maxPlain} {
+		c, err := key.Encrypt(rand.Reader, m)
+		if err != nil {
+			t.Fatalf("Encrypt(%s) failed: %v", m, err)
+		}
+		got, err := key.Decrypt(c)
+		if err != nil {
+			t.Fatalf("Decrypt failed: %v", err)
+		}
+		if got.Cmp(m) != 0 {
+			t.Errorf("Decrypt(Encrypt(%s)) = %s", m, got)
+		}
+	}
+
+	// Encryption is randomized
+	c1, _ := key.Encrypt(rand.Reader, big.NewInt(7))
+	c2, _ := key.Encrypt(rand.Reader, big.NewInt(7))
+	if c1.Cmp(c2) == 0 {
+		t.Error("Two encryptions of the same message should differ")
+	}
+
+	if _, err := key.Encrypt(rand.Reader, key.N); err != ErrMessageTooLarge {
+		t.Errorf("Expected ErrMessageTooLarge, got %v", err)
+	}
+	if _, err := key.Decrypt(big.NewInt(0)); err == nil {
+		t.Error("Expected error decrypting an invalid ciphertext")
+	}
+}
+
+func TestHomomorphism(t *testing.T) {
+	key := newTestKey(t)
+	pub, err := NewPublicKey(key.N)
+	if err != nil {
+		t.Fatalf("NewPublicKey failed: %v", err)
+	}
+
+	a, b, k := big.NewInt(123456789), big.NewInt(987654321), big.NewInt(1000003)
+	ca, _ := pub.Encrypt(rand.Reader, a)
+	cb, _ := pub.Encrypt(rand.Reader, b)
+
+	sum, _ := key.Decrypt(pub.Add(ca, cb))
+	if expected := new(big.Int).Add(a, b); sum.Cmp(expected) != 0 {
+		t.Errorf("Dec(Enc(a)*Enc(b)) = %s, expected %s", sum, expected)
+	}
+
+	product, _ := key.Decrypt(pub.MulConst(ca, k))
+	if expected := new(big.Int).Mul(a, k); product.Cmp(expected) != 0 {
+		t.Errorf("Dec(Enc(a)^k) = %s, expected %s", product, expected)
+	}
+
+	// Sums wrap around modulo n
+	nm1 := new(big.Int).Sub(pub.N, big.NewInt(1))
+	cn, _ := pub.Encrypt(rand.Reader, nm1)
+	wrapped, _ := key.Decrypt(pub.Add(cn, cb))
+	if expected := new(big.Int).Sub(b, big.NewInt(1)); wrapped.Cmp(expected) != 0 {
+		t.Errorf("Expected wrap-around to %s, got %s", expected, wrapped)
+	}
+
+	if _, err := NewPublicKey(big.NewInt(10)); err == nil {
+		t.Error("Expected error for an even modulus")
+	}
+	if _, err := GenerateKey(rand.Reader, 256); err == nil {
+		t.Error("Expected error for a too small modulus")
+	}
+}
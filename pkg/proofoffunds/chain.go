@@ -0,0 +1,79 @@
+package proofoffunds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/neverDefined/cryptography-playground/pkg/chain"
+)
+
+// Chain lookups
+//
+// A report only proves control of addresses. Whether the listed outputs exist
+// and are still unspent comes from a chain.Backend: the prover uses FetchUTXOs
+// to build the report, and the auditor uses CheckUnspent on their own backend.
+
+// ErrSpentOutput is returned when a claimed output is not in the backend's UTXO set
+var ErrSpentOutput = errors.New("output is spent or unknown")
+
+// FetchUTXOs looks up the unspent outputs of addresses for NewReport
+//
+// Example:
+//
+//	utxos, _ := FetchUTXOs(ctx, chain.NewEsplora(url, nil), []string{"bc1q...", "bc1p..."})
+//	report, _ := NewReport(&chaincfg.MainNetParams, challenge, utxos)
+func FetchUTXOs(ctx context.Context, backend chain.Backend, addresses []string) ([]UTXO, error) {
+	var utxos []UTXO
+	for _, address := range addresses {
+		found, err := backend.GetUTXOs(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", address, err)
+		}
+		for _, u := range found {
+			utxos = append(utxos, UTXO{
+				TxID:    chain.FormatHash(u.OutPoint.Hash),
+				Vout:    u.OutPoint.Index,
+				Address: address,
+				Value:   u.Value,
+			})
+		}
+	}
+	return utxos, nil
+}
+
+// CheckUnspent confirms that every output in the report is unspent with the stated value
+//
+// Call it after Verify; it trusts the backend, so an auditor should use their own.
+//
+// Example:
+//
+//	if err := report.CheckUnspent(ctx, backend); errors.Is(err, ErrSpentOutput) {
+//		// funds moved since the report was made
+//	}
+func (r *Report) CheckUnspent(ctx context.Context, backend chain.Backend) error {
+	for _, proof := range r.Addresses {
+		// Step 1: Index the address's current UTXO set
+		found, err := backend.GetUTXOs(ctx, proof.Address)
+		if err != nil {
+			return fmt.Errorf("%s: %w", proof.Address, err)
+		}
+		unspent := make(map[string]int64, len(found))
+		for _, u := range found {
+			unspent[u.OutPoint.String()] = u.Value
+		}
+
+		// Step 2: Every claimed output must be in it, with the same value
+		for _, u := range proof.UTXOs {
+			outpoint := outpointKey(u)
+			value, ok := unspent[outpoint]
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrSpentOutput, outpoint)
+			}
+			if value != u.Value {
+				return fmt.Errorf("%w: %s holds %d, report says %d", ErrBalanceMismatch, outpoint, value, u.Value)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package proofoffunds
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/chain"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+// utxoBackend answers GetUTXOs from a map and nothing else
+type utxoBackend struct {
+	chain.Backend
+	utxos map[string][]chain.UTXO
+}
+
+func (b *utxoBackend) GetUTXOs(_ context.Context, address string) ([]chain.UTXO, error) {
+	return b.utxos[address], nil
+}
+
+// backendFor serves the outputs of the test wallet
+func backendFor(t *testing.T, w *wallet) *utxoBackend {
+	t.Helper()
+	b := &utxoBackend{utxos: make(map[string][]chain.UTXO)}
+	for _, u := range w.utxos() {
+		txid, err := chain.ParseHash(u.TxID)
+		if err != nil {
+			t.Fatalf("ParseHash failed: %v", err)
+		}
+		b.utxos[u.Address] = append(b.utxos[u.Address], chain.UTXO{OutPoint: tx.OutPoint{Hash: txid, Index: u.Vout}, Value: u.Value})
+	}
+	return b
+}
+
+func TestFetchAndCheckUnspent(t *testing.T) {
+	ctx := context.Background()
+	w := newWallet(t)
+	backend := backendFor(t, w)
+
+	utxos, err := FetchUTXOs(ctx, backend, []string{w.segwit, w.taproot})
+	if err != nil {
+		t.Fatalf("FetchUTXOs failed: %v", err)
+	}
+	report, err := NewReport(&chaincfg.RegressionNetParams, challenge, utxos)
+	if err != nil {
+		t.Fatalf("NewReport failed: %v", err)
+	}
+	if report.Total != 195000 {
+		t.Errorf("Expected total 195000, got %d", report.Total)
+	}
+	signer, _ := btcec.NewPrivateKey()
+	report.Prove(w.segwit, w.segwitKey)
+	report.Prove(w.taproot, w.taprootKey)
+	report.Sign(signer)
+	if err := report.Verify(); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if err := report.CheckUnspent(ctx, backend); err != nil {
+		t.Errorf("CheckUnspent failed: %v", err)
+	}
+
+	// One output is spent
+	backend.utxos[w.segwit] = backend.utxos[w.segwit][1:]
+	if err := report.CheckUnspent(ctx, backend); !errors.Is(err, ErrSpentOutput) {
+		t.Errorf("Expected ErrSpentOutput, got %v", err)
+	}
+
+	// The backend disagrees about a value
+	backend = backendFor(t, w)
+	backend.utxos[w.taproot][0].Value--
+	if err := report.CheckUnspent(ctx, backend); !errors.Is(err, ErrBalanceMismatch) {
+		t.Errorf("Expected ErrBalanceMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,322 @@
+package proofoffunds
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/bip322"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// Proof-of-funds reports
+//
+// A report lists the unspent outputs a party claims to control, grouped by
+// address, with one BIP322 signature per address over an auditor-chosen
+// challenge. The whole document is then signed with a domain-separated Schnorr
+// signature and a timestamp, so an auditor can check:
+//
+//  1. each address signature proves control of the address (BIP322)
+//  2. the balances add up to the stated total
+//  3. the document was not altered after it was signed
+//
+// The report does not prove the outputs are unspent: the auditor must look them
+// up on chain (CheckUnspent does this through a chain.Backend). Addresses are given explicitly; descriptor expansion is left to
+// the caller.
+
+// ReportVersion is the format version written by NewReport
+const ReportVersion = 1
+
+// reportDomain is the SignDomain domain of report signatures
+const reportDomain = "cryptography-playground/proof-of-funds/v1"
+
+var (
+	// ErrUnprovenAddress is returned when an address has no valid BIP322 proof
+	ErrUnprovenAddress = errors.New("address has no ownership proof")
+	// ErrBalanceMismatch is returned when balances do not add up
+	ErrBalanceMismatch = errors.New("balances do not match the outputs")
+	// ErrInvalidReportSignature is returned when the report signature does not verify
+	ErrInvalidReportSignature = errors.New("invalid report signature")
+	// ErrDuplicateEntry is returned when an output or an address is listed twice
+	ErrDuplicateEntry = errors.New("duplicate report entry")
+)
+
+// UTXO is an unspent output claimed by the report
+type UTXO struct {
+	TxID    string `json:"txid"` // Display (big-endian) hex
+	Vout    uint32 `json:"vout"`
+	Address string `json:"address"`
+	Value   int64  `json:"value"` // Satoshis
+}
+
+// AddressProof is the balance of one address and the proof of its control
+type AddressProof struct {
+	Address   string `json:"address"`
+	Balance   int64  `json:"balance"`
+	UTXOs     []UTXO `json:"utxos"`
+	Signature string `json:"signature,omitempty"` // BIP322 simple signature over the challenge
+}
+
+// Report is a signed, timestamped proof-of-funds document
+type Report struct {
+	Version   int            `json:"version"`
+	Network   string         `json:"network"`   // chaincfg network name
+	Challenge string         `json:"challenge"` // Message signed for every address
+	Timestamp time.Time      `json:"timestamp"`
+	Addresses []AddressProof `json:"addresses"`
+	Total     int64          `json:"total"`
+	SignerKey string         `json:"signer_key,omitempty"` // x-only, hex
+	Signature string         `json:"signature,omitempty"`  // SignDomain signature, hex
+}
+
+// NewReport groups the outputs by address and sums the balances
+//
+// The challenge should be chosen by the auditor (e.g. a nonce and a date) so
+// proofs cannot be reused from an earlier report.
+//
+// Example:
+//
+//	report, _ := NewReport(&chaincfg.MainNetParams, "audit 2024-06-30 #81f3", utxos)
+//	report.Prove("bc1q...", privateKey)
+//	report.Sign(reportKey)
+func NewReport(params *chaincfg.Params, challenge string, utxos []UTXO) (*Report, error) {
+	if params == nil {
+		return nil, errors.New("network params are required")
+	}
+	if challenge == "" {
+		return nil, errors.New("challenge cannot be empty")
+	}
+	if len(utxos) == 0 {
+		return nil, errors.New("at least one output is required")
+	}
+
+	// Step 1: Group outputs by address, in first-seen order
+	report := &Report{
+		Version:   ReportVersion,
+		Network:   params.Name,
+		Challenge: challenge,
+		Timestamp: time.Now().UTC().Truncate(time.Second),
+	}
+	index := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, u := range utxos {
+		if u.Value <= 0 {
+			return nil, fmt.Errorf("output %s:%d has no value", u.TxID, u.Vout)
+		}
+		if err := checkTxID(u.TxID); err != nil {
+			return nil, err
+		}
+		outpoint := outpointKey(u)
+		if seen[outpoint] {
+			return nil, fmt.Errorf("%w: output %s", ErrDuplicateEntry, outpoint)
+		}
+		seen[outpoint] = true
+
+		i, ok := index[u.Address]
+		if !ok {
+			i = len(report.Addresses)
+			index[u.Address] = i
+			report.Addresses = append(report.Addresses, AddressProof{Address: u.Address})
+		}
+		report.Addresses[i].UTXOs = append(report.Addresses[i].UTXOs, u)
+		report.Addresses[i].Balance += u.Value
+		report.Total += u.Value
+	}
+	return report, nil
+}
+
+// Prove adds the BIP322 ownership proof of an address, signing with its key
+func (r *Report) Prove(address string, priv *btcec.PrivateKey) error {
+	params, err := chaincfg.ParamsForName(r.Network)
+	if err != nil {
+		return err
+	}
+	sig, err := bip322.Sign([]byte(r.Challenge), address, priv, params)
+	if err != nil {
+		return err
+	}
+	return r.AddProof(address, sig)
+}
+
+// AddProof adds a BIP322 signature produced elsewhere (e.g. by a hardware wallet)
+//
+// The signature is verified before it is added.
+func (r *Report) AddProof(address, signature string) error {
+	params, err := chaincfg.ParamsForName(r.Network)
+	if err != nil {
+		return err
+	}
+	proof := r.proof(address)
+	if proof == nil {
+		return fmt.Errorf("address %s is not in the report", address)
+	}
+	if err := bip322.Verify([]byte(r.Challenge), address, signature, params); err != nil {
+		return err
+	}
+	proof.Signature = signature
+	r.Signature = "" // Any earlier report signature no longer covers the document
+	return nil
+}
+
+// Sign signs the complete report
+//
+// Every address must be proven first.
+func (r *Report) Sign(priv *btcec.PrivateKey) error {
+	if priv == nil {
+		return errors.New("private key cannot be nil")
+	}
+	for _, proof := range r.Addresses {
+		if proof.Signature == "" {
+			return fmt.Errorf("%w: %s", ErrUnprovenAddress, proof.Address)
+		}
+	}
+
+	xOnly := schnorr.XOnlyFromPub(priv.PubKey())
+	r.SignerKey = hex.EncodeToString(xOnly[:])
+	payload, err := r.signingPayload()
+	if err != nil {
+		return err
+	}
+	sig, err := schnorr.SignDomain(reportDomain, payload, priv)
+	if err != nil {
+		return err
+	}
+	r.Signature = hex.EncodeToString(sig[:])
+	return nil
+}
+
+// Verify checks the report signature, every ownership proof and the balances
+//
+// It does not check that the outputs exist or are unspent (see CheckUnspent),
+// nor the age of the report; compare Timestamp against the auditor's own policy.
+//
+// Example:
+//
+//	report, _ := ReadReport(file)
+//	if err := report.Verify(); err != nil {
+//		log.Fatalf("report rejected: %v", err)
+//	}
+func (r *Report) Verify() error {
+	if r.Version != ReportVersion {
+		return fmt.Errorf("unsupported report version %d", r.Version)
+	}
+	params, err := chaincfg.ParamsForName(r.Network)
+	if err != nil {
+		return err
+	}
+
+	// Step 1: Report signature over everything else
+	keyBytes, err := hex.DecodeString(r.SignerKey)
+	if err != nil || len(keyBytes) != 32 {
+		return fmt.Errorf("%w: invalid signer key", ErrInvalidReportSignature)
+	}
+	sigBytes, err := hex.DecodeString(r.Signature)
+	if err != nil || len(sigBytes) != 64 {
+		return fmt.Errorf("%w: invalid signature encoding", ErrInvalidReportSignature)
+	}
+	pub, err := schnorr.ParseXOnly([32]byte(keyBytes))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReportSignature, err)
+	}
+	payload, err := r.signingPayload()
+	if err != nil {
+		return err
+	}
+	if !schnorr.VerifyDomain(reportDomain, payload, pub, [64]byte(sigBytes)) {
+		return ErrInvalidReportSignature
+	}
+
+	// Step 2: Ownership proofs and balances; each address and output counts once
+	var total int64
+	addresses := make(map[string]bool, len(r.Addresses))
+	outpoints := make(map[string]bool)
+	for _, proof := range r.Addresses {
+		if addresses[proof.Address] {
+			return fmt.Errorf("%w: address %s", ErrDuplicateEntry, proof.Address)
+		}
+		addresses[proof.Address] = true
+		if proof.Signature == "" {
+			return fmt.Errorf("%w: %s", ErrUnprovenAddress, proof.Address)
+		}
+		if err := bip322.Verify([]byte(r.Challenge), proof.Address, proof.Signature, params); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrUnprovenAddress, proof.Address, err)
+		}
+
+		var balance int64
+		for _, u := range proof.UTXOs {
+			if u.Address != proof.Address || u.Value <= 0 {
+				return fmt.Errorf("%w: output %s:%d", ErrBalanceMismatch, u.TxID, u.Vout)
+			}
+			if err := checkTxID(u.TxID); err != nil {
+				return err
+			}
+			outpoint := outpointKey(u)
+			if outpoints[outpoint] {
+				return fmt.Errorf("%w: output %s", ErrDuplicateEntry, outpoint)
+			}
+			outpoints[outpoint] = true
+			balance += u.Value
+		}
+		if balance != proof.Balance {
+			return fmt.Errorf("%w: %s", ErrBalanceMismatch, proof.Address)
+		}
+		total += balance
+	}
+	if total != r.Total {
+		return fmt.Errorf("%w: total", ErrBalanceMismatch)
+	}
+	return nil
+}
+
+// ReadReport parses a JSON report
+func ReadReport(rd io.Reader) (*Report, error) {
+	var report Report
+	if err := json.NewDecoder(rd).Decode(&report); err != nil {
+		return nil, fmt.Errorf("invalid report: %w", err)
+	}
+	return &report, nil
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// signingPayload is the JSON encoding of the report without its signature
+func (r *Report) signingPayload() ([]byte, error) {
+	unsigned := *r
+	unsigned.Signature = ""
+	return json.Marshal(&unsigned)
+}
+
+// proof returns the entry of an address, or nil
+func (r *Report) proof(address string) *AddressProof {
+	for i := range r.Addresses {
+		if r.Addresses[i].Address == address {
+			return &r.Addresses[i]
+		}
+	}
+	return nil
+}
+
+// outpointKey is "txid:vout" with the txid in lowercase, so hex case cannot make one output look like two
+func outpointKey(u UTXO) string {
+	return fmt.Sprintf("%s:%d", strings.ToLower(u.TxID), u.Vout)
+}
+
+// checkTxID checks that a txid is 64 hex characters
+func checkTxID(txid string) error {
+	b, err := hex.DecodeString(txid)
+	if err != nil || len(b) != 32 {
+		return fmt.Errorf("invalid txid %q", txid)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package proofoffunds
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/bech32"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+const challenge = "audit 2024-06-30 nonce 81f3"
+
+// wallet holds the keys of a P2WPKH and a P2TR address
+type wallet struct {
+	segwitKey, taprootKey *btcec.PrivateKey
+	segwit, taproot       string
+}
+
+func newWallet(t *testing.T) *wallet {
+	t.Helper()
+	w := &wallet{}
+	w.segwitKey, _ = btcec.PrivKeyFromBytes(bytes.Repeat([]byte{0x21}, 32))
+	w.taprootKey, _ = btcec.PrivKeyFromBytes(bytes.Repeat([]byte{0x22}, 32))
+
+	keyHash := hash.Hash160(w.segwitKey.PubKey().SerializeCompressed())
+	var err error
+	if w.segwit, err = bech32.SegWitAddressEncode("bcrt", 0, keyHash[:]); err != nil {
+		t.Fatalf("SegWitAddressEncode failed: %v", err)
+	}
+
+	// Key path only taproot output: Q = P + H_TapTweak(P)·G
+	xOnly := btcschnorr.SerializePubKey(w.taprootKey.PubKey())
+	tweak := schnorr.TaggedHash("TapTweak", xOnly)
+	var tweakScalar btcec.ModNScalar
+	tweakScalar.SetBytes(&tweak)
+	var p, tG, q btcec.JacobianPoint
+	internal, _ := btcschnorr.ParsePubKey(xOnly)
+	internal.AsJacobian(&p)
+	btcec.ScalarBaseMultNonConst(&tweakScalar, &tG)
+	btcec.AddNonConst(&p, &tG, &q)
+	q.ToAffine()
+	if w.taproot, err = bech32.SegWitAddressEncode("bcrt", 1, btcschnorr.SerializePubKey(btcec.NewPublicKey(&q.X, &q.Y))); err != nil {
+		t.Fatalf("SegWitAddressEncode failed: %v", err)
+	}
+	return w
+}
+
+func (w *wallet) utxos() []UTXO {
+	return []UTXO{
+		{TxID: strings.Repeat("ab", 32), Vout: 0, Address: w.segwit, Value: 50000},
+		{TxID: strings.Repeat("22", 32), Vout: 3, Address: w.taproot, Value: 120000},
+		{TxID: strings.Repeat("33", 32), Vout: 1, Address: w.segwit, Value: 25000},
+	}
+}
+
+// signedReport builds a fully proven and signed report
+func signedReport(t *testing.T, w *wallet, signer *btcec.PrivateKey) *Report {
+	t.Helper()
+	report, err := NewReport(&chaincfg.RegressionNetParams, challenge, w.utxos())
+	if err != nil {
+		t.Fatalf("NewReport failed: %v", err)
+	}
+	if err := report.Prove(w.segwit, w.segwitKey); err != nil {
+		t.Fatalf("Prove(segwit) failed: %v", err)
+	}
+	if err := report.Prove(w.taproot, w.taprootKey); err != nil {
+		t.Fatalf("Prove(taproot) failed: %v", err)
+	}
+	if err := report.Sign(signer); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	return report
+}
+
+func TestReport(t *testing.T) {
+	w := newWallet(t)
+	signer, _ := btcec.NewPrivateKey()
+	report := signedReport(t, w, signer)
+
+	if report.Total != 195000 {
+		t.Errorf("Expected total 195000, got %d", report.Total)
+	}
+	if len(report.Addresses) != 2 || report.Addresses[0].Address != w.segwit || report.Addresses[0].Balance != 75000 {
+		t.Errorf("Unexpected grouping: %+v", report.Addresses)
+	}
+	if err := report.Verify(); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	// The report survives a JSON round trip
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	decoded, err := ReadReport(&buf)
+	if err != nil {
+		t.Fatalf("ReadReport failed: %v", err)
+	}
+	if err := decoded.Verify(); err != nil {
+		t.Errorf("Verify after round trip failed: %v", err)
+	}
+}
+
+func TestReportTampering(t *testing.T) {
+	w := newWallet(t)
+	signer, _ := btcec.NewPrivateKey()
+
+	tests := []struct {
+		name     string
+		tamper   func(r *Report)
+		expected error
+	}{
+		{"inflated total", func(r *Report) { r.Total++ }, ErrInvalidReportSignature},
+		{"changed timestamp", func(r *Report) { r.Timestamp = r.Timestamp.Add(1) }, ErrInvalidReportSignature},
+		{"changed challenge", func(r *Report) { r.Challenge = "another audit" }, ErrInvalidReportSignature},
+		{"added output", func(r *Report) {
+			r.Addresses[1].UTXOs = append(r.Addresses[1].UTXOs, UTXO{TxID: strings.Repeat("44", 32), Address: w.taproot, Value: 1})
+		}, ErrInvalidReportSignature},
+		{"missing signature", func(r *Report) { r.Signature = "" }, ErrInvalidReportSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := signedReport(t, w, signer)
+			tt.tamper(report)
+			if err := report.Verify(); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	// A consistent but re-signed report with a swapped proof fails the ownership check
+	report := signedReport(t, w, signer)
+	report.Addresses[0].Signature, report.Addresses[1].Signature = report.Addresses[1].Signature, report.Addresses[0].Signature
+	if err := report.Sign(signer); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if err := report.Verify(); !errors.Is(err, ErrUnprovenAddress) {
+		t.Errorf("Expected ErrUnprovenAddress, got %v", err)
+	}
+
+	// Listing an output or an address twice inflates the total; re-signing does not help
+	duplicates := []struct {
+		name   string
+		tamper func(r *Report)
+	}{
+		{"duplicate output", func(r *Report) {
+			r.Addresses[0].UTXOs = append(r.Addresses[0].UTXOs, r.Addresses[0].UTXOs[0])
+			r.Addresses[0].Balance += r.Addresses[0].UTXOs[0].Value
+			r.Total += r.Addresses[0].UTXOs[0].Value
+		}},
+		{"duplicate output in upper case", func(r *Report) {
+			u := r.Addresses[0].UTXOs[0]
+			u.TxID = strings.ToUpper(u.TxID)
+			r.Addresses[0].UTXOs = append(r.Addresses[0].UTXOs, u)
+			r.Addresses[0].Balance += u.Value
+			r.Total += u.Value
+		}},
+		{"duplicate address", func(r *Report) {
+			r.Addresses = append(r.Addresses, r.Addresses[1])
+			r.Total += r.Addresses[1].Balance
+		}},
+	}
+	for _, tt := range duplicates {
+		t.Run(tt.name, func(t *testing.T) {
+			report := signedReport(t, w, signer)
+			tt.tamper(report)
+			if err := report.Sign(signer); err != nil {
+				t.Fatalf("Sign failed: %v", err)
+			}
+			if err := report.Verify(); !errors.Is(err, ErrDuplicateEntry) {
+				t.Errorf("Expected ErrDuplicateEntry, got %v", err)
+			}
+		})
+	}
+
+	// Balances that do not add up are rejected even when signed
+	report = signedReport(t, w, signer)
+	report.Addresses[0].Balance++
+	report.Total++
+	if err := report.Sign(signer); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if err := report.Verify(); !errors.Is(err, ErrBalanceMismatch) {
+		t.Errorf("Expected ErrBalanceMismatch, got %v", err)
+	}
+}
+
+func TestReportErrors(t *testing.T) {
+	w := newWallet(t)
+	params := &chaincfg.RegressionNetParams
+
+	if _, err := NewReport(params, "", w.utxos()); err == nil {
+		t.Error("Expected error for empty challenge")
+	}
+	if _, err := NewReport(params, challenge, nil); err == nil {
+		t.Error("Expected error for no outputs")
+	}
+	duplicate := append(w.utxos(), w.utxos()[0])
+	if _, err := NewReport(params, challenge, duplicate); !errors.Is(err, ErrDuplicateEntry) {
+		t.Errorf("Expected ErrDuplicateEntry for a duplicate output, got %v", err)
+	}
+	upper := w.utxos()[0]
+	upper.TxID = strings.ToUpper(upper.TxID)
+	if _, err := NewReport(params, challenge, append(w.utxos(), upper)); !errors.Is(err, ErrDuplicateEntry) {
+		t.Errorf("Expected ErrDuplicateEntry for a duplicate output in upper case, got %v", err)
+	}
+	if _, err := NewReport(params, challenge, []UTXO{{TxID: "xyz", Address: w.segwit, Value: 1}}); err == nil {
+		t.Error("Expected error for an invalid txid")
+	}
+
+	report, _ := NewReport(params, challenge, w.utxos())
+	signer, _ := btcec.NewPrivateKey()
+	if err := report.Sign(signer); !errors.Is(err, ErrUnprovenAddress) {
+		t.Errorf("Expected ErrUnprovenAddress signing an unproven report, got %v", err)
+	}
+	if err := report.Prove(w.segwit, w.taprootKey); err == nil {
+		t.Error("Expected error proving with the wrong key")
+	}
+	if err := report.AddProof("bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080", "AA=="); err == nil {
+		t.Error("Expected error for an address outside the report")
+	}
+}
@@ -0,0 +1,277 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+)
+
+// Adaptor signatures
+//
+// An adaptor signature is a BIP340 signature "locked" by an adaptor point
+// T = t·G: anyone can check it is valid for T, but only someone who knows t can
+// turn it into a real signature, and whoever sees both the adaptor signature and
+// the real one learns t. This is the building block of scriptless atomic swaps,
+// PTLCs and DLCs.
+//
+//	R  = k·G + T                   (the final nonce; k is negated if R has odd Y)
+//	e  = TaggedHash("BIP0340/challenge", x(R) || x(P) || m)
+//	s' = k + e·d                   (pre-signature)
+//	s  = s' + t  or  s' − t        (final signature (x(R), s); minus if R had odd Y)
+//
+// Publishing the final signature reveals t = ±(s − s'). Never make two adaptor
+// signatures with the same k: AdaptorSign draws fresh randomness for every call.
+
+// ErrInvalidAdaptorSecret is returned when a secret does not match the adaptor point
+var ErrInvalidAdaptorSecret = errors.New("secret does not match the adaptor point")
+
+// AdaptorSignature is a pre-signature locked by an adaptor point
+type AdaptorSignature struct {
+	R [33]byte // Compressed final nonce R = k·G + T (parity included)
+	T [33]byte // Compressed adaptor point
+	S [32]byte // s' = k + e·d
+}
+
+// AdaptorSign produces an adaptor signature over msg, locked by T
+//
+// The message is hashed with SHA256 like SignBIP340.
+//
+// Example:
+//
+//	adaptorSig, err := AdaptorSign(msg, alicePriv, T)
+//	// Bob checks VerifyAdaptor(msg, alicePub, T, adaptorSig) before going ahead
+func AdaptorSign(msg []byte, priv *btcec.PrivateKey, T *btcec.PublicKey) (*AdaptorSignature, error) {
+	if len(msg) == 0 {
+		return nil, errors.New("message cannot be empty")
+	}
+	return AdaptorSignDigest(sha256.Sum256(msg), priv, T)
+}
+
+// AdaptorSignDigest is AdaptorSign over a 32-byte digest, e.g. a taproot sighash
+//
+// Example:
+//
+//	sighash, _ := tx.TaprootScriptSigHash(redeemTx, 0, prevouts, tx.SigHashDefault, leafHash)
+//	adaptorSig, err := AdaptorSignDigest(sighash, alicePriv, T)
+func AdaptorSignDigest(m [32]byte, priv *btcec.PrivateKey, T *btcec.PublicKey) (*AdaptorSignature, error) {
+	// Step 1: Validate inputs
+	if priv == nil || T == nil {
+		return nil, errors.New("private key and adaptor point cannot be nil")
+	}
+	pub := priv.PubKey()
+	var d btcec.ModNScalar
+	d.Set(&priv.Key)
+	if !arithmetic.HasEvenY(arithmetic.PointFromPubKey(pub)) {
+		d.Negate()
+	}
+	defer d.Zero()
+
+	for {
+		// Step 2: Nonce from fresh randomness, bound to the key, T and message
+		var aux [32]byte
+		if _, err := rand.Read(aux[:]); err != nil {
+			return nil, fmt.Errorf("failed to read randomness: %w", err)
+		}
+		dBytes := d.Bytes()
+		kHash := TaggedHash("cryptography-playground/adaptor/nonce", aux[:], dBytes[:], T.SerializeCompressed(), m[:])
+		arithmetic.ZeroBytes(dBytes[:])
+		var k btcec.ModNScalar
+		overflow := k.SetBytes(&kHash)
+		arithmetic.ZeroBytes(kHash[:])
+		if overflow != 0 || k.IsZero() {
+			continue
+		}
+
+		// Step 3: R = k·G + T; retry in the (negligible) case R is infinity
+		var kG, tj, R btcec.JacobianPoint
+		btcec.ScalarBaseMultNonConst(&k, &kG)
+		T.AsJacobian(&tj)
+		btcec.AddNonConst(&kG, &tj, &R)
+		if R.Z.IsZero() {
+			k.Zero()
+			continue
+		}
+		R.ToAffine()
+		if R.Y.IsOdd() {
+			k.Negate()
+		}
+
+		// Step 4: s' = k + e·d
+		rx := xOnly(&R)
+		e := challenge(rx, XOnlyFromPub(pub), m)
+		var s btcec.ModNScalar
+		s.Mul2(&e, &d).Add(&k)
+		k.Zero()
+
+		adaptor := &AdaptorSignature{S: s.Bytes()}
+		copy(adaptor.R[:], btcec.NewPublicKey(&R.X, &R.Y).SerializeCompressed())
+		copy(adaptor.T[:], T.SerializeCompressed())
+		return adaptor, nil
+	}
+}
+
+// VerifyAdaptor checks that an adaptor signature becomes a valid signature once T's secret is added
+//
+// Formula: s'·G == ±(R − T) + e·P   (minus if R has odd Y)
+//
+// Example:
+//
+//	if !VerifyAdaptor(msg, alicePub, T, adaptorSig) {
+//		// do not lock funds against this signature
+//	}
+func VerifyAdaptor(msg []byte, pub *btcec.PublicKey, T *btcec.PublicKey, adaptor *AdaptorSignature) bool {
+	if len(msg) == 0 {
+		return false
+	}
+	return VerifyAdaptorDigest(sha256.Sum256(msg), pub, T, adaptor)
+}
+
+// VerifyAdaptorDigest is VerifyAdaptor over a 32-byte digest
+func VerifyAdaptorDigest(m [32]byte, pub *btcec.PublicKey, T *btcec.PublicKey, adaptor *AdaptorSignature) bool {
+	// Step 1: Validate inputs
+	if pub == nil || T == nil || adaptor == nil {
+		return false
+	}
+	if [33]byte(T.SerializeCompressed()) != adaptor.T {
+		return false
+	}
+	R, err := btcec.ParsePubKey(adaptor.R[:])
+	if err != nil {
+		return false
+	}
+	var s btcec.ModNScalar
+	if overflow := s.SetBytes(&adaptor.S); overflow != 0 {
+		return false
+	}
+
+	// Step 2: R' = ±(R − T)
+	var rj, tj, kG btcec.JacobianPoint
+	R.AsJacobian(&rj)
+	T.AsJacobian(&tj)
+	tj.Y.Negate(1).Normalize()
+	btcec.AddNonConst(&rj, &tj, &kG)
+	if adaptor.R[0] == 0x03 {
+		kG.Y.Negate(1).Normalize()
+	}
+
+	// Step 3: s'·G − e·P must equal R'
+	xP := XOnlyFromPub(pub)
+	evenP, err := btcschnorr.ParsePubKey(xP[:])
+	if err != nil {
+		return false
+	}
+	e := challenge([32]byte(adaptor.R[1:]), xP, m)
+	e.Negate()
+	var sG, eP, pj, expected btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&s, &sG)
+	evenP.AsJacobian(&pj)
+	btcec.ScalarMultNonConst(&e, &pj, &eP)
+	btcec.AddNonConst(&sG, &eP, &expected)
+
+	if kG.Z.IsZero() || expected.Z.IsZero() {
+		return false
+	}
+	kG.ToAffine()
+	expected.ToAffine()
+	return kG.X.Equals(&expected.X) && kG.Y.Equals(&expected.Y)
+}
+
+// Adapt completes an adaptor signature with the secret t of its adaptor point
+//
+// Example:
+//
+//	sig, err := adaptorSig.Adapt(t)
+//	// sig is a plain BIP340 signature: VerifyBIP340(msg, alicePub, sig) == true
+func (a *AdaptorSignature) Adapt(secret [32]byte) (Signature, error) {
+	// Step 1: The secret must open T
+	t, err := a.checkSecret(secret)
+	if err != nil {
+		return [64]byte{}, err
+	}
+	defer t.Zero()
+
+	// Step 2: s = s' ± t
+	var s btcec.ModNScalar
+	if overflow := s.SetBytes(&a.S); overflow != 0 {
+		return [64]byte{}, errors.New("invalid adaptor signature")
+	}
+	if a.R[0] == 0x03 {
+		t.Negate()
+	}
+	s.Add(t)
+
+	var r [32]byte
+	copy(r[:], a.R[1:])
+	return JoinSig(r, s.Bytes()), nil
+}
+
+// ExtractSecret recovers t from a completed signature and its adaptor signature
+//
+// Example:
+//
+//	// Alice published sig on chain; Bob held adaptorSig
+//	t, err := ExtractSecret(sig, adaptorSig)
+func ExtractSecret(sig [64]byte, adaptor *AdaptorSignature) ([32]byte, error) {
+	// Step 1: The signature must use the adaptor's nonce
+	if adaptor == nil {
+		return [32]byte{}, errors.New("adaptor signature cannot be nil")
+	}
+	if [32]byte(sig[:32]) != [32]byte(adaptor.R[1:]) {
+		return [32]byte{}, errors.New("signature does not complete this adaptor signature")
+	}
+
+	// Step 2: t = ±(s − s')
+	var s, sPre btcec.ModNScalar
+	if overflow := s.SetByteSlice(sig[32:]); overflow {
+		return [32]byte{}, errors.New("invalid signature")
+	}
+	if overflow := sPre.SetBytes(&adaptor.S); overflow != 0 {
+		return [32]byte{}, errors.New("invalid adaptor signature")
+	}
+	sPre.Negate()
+	t := *s.Add(&sPre)
+	if adaptor.R[0] == 0x03 {
+		t.Negate()
+	}
+	secret := t.Bytes()
+	if _, err := adaptor.checkSecret(secret); err != nil {
+		return [32]byte{}, err
+	}
+	return secret, nil
+}
+
+// checkSecret parses t and checks t·G == T
+func (a *AdaptorSignature) checkSecret(secret [32]byte) (*btcec.ModNScalar, error) {
+	var t btcec.ModNScalar
+	if overflow := t.SetBytes(&secret); overflow != 0 || t.IsZero() {
+		return nil, ErrInvalidAdaptorSecret
+	}
+	var tG btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&t, &tG)
+	tG.ToAffine()
+	if [33]byte(btcec.NewPublicKey(&tG.X, &tG.Y).SerializeCompressed()) != a.T {
+		t.Zero()
+		return nil, ErrInvalidAdaptorSecret
+	}
+	return &t, nil
+}
+
+// xOnly returns the x coordinate of an affine point
+func xOnly(p *btcec.JacobianPoint) [32]byte {
+	var out [32]byte
+	p.X.PutBytes(&out)
+	return out
+}
+
+// challenge computes the BIP340 challenge e = TaggedHash("BIP0340/challenge", r || P || m)
+func challenge(r, pub, m [32]byte) btcec.ModNScalar {
+	h := TaggedHash("BIP0340/challenge", r[:], pub[:], m[:])
+	var e btcec.ModNScalar
+	e.SetBytes(&h)
+	return e
+}
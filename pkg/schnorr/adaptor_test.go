@@ -0,0 +1,138 @@
+package schnorr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// TestAdaptorSignature runs the adapt / extract cycle of an atomic swap
+func TestAdaptorSignature(t *testing.T) {
+	msg := []byte("pay Bob 1 BTC")
+
+	// Cover both parities of the signing key and of R
+	for i := 0; i < 16; i++ {
+		priv, _ := btcec.NewPrivateKey()
+		secretKey, _ := btcec.NewPrivateKey()
+		secret := secretKey.Key.Bytes()
+		T := secretKey.PubKey()
+
+		adaptor, err := AdaptorSign(msg, priv, T)
+		if err != nil {
+			t.Fatalf("AdaptorSign failed: %v", err)
+		}
+		if !VerifyAdaptor(msg, priv.PubKey(), T, adaptor) {
+			t.Fatal("Expected adaptor signature to verify")
+		}
+
+		// The pre-signature alone is not a valid signature
+		var r [32]byte
+		copy(r[:], adaptor.R[1:])
+		if VerifyBIP340(msg, priv.PubKey(), JoinSig(r, adaptor.S)) {
+			t.Error("Expected the pre-signature not to verify as a BIP340 signature")
+		}
+
+		sig, err := adaptor.Adapt(secret)
+		if err != nil {
+			t.Fatalf("Adapt failed: %v", err)
+		}
+		if !VerifyBIP340(msg, priv.PubKey(), sig) {
+			t.Fatal("Expected adapted signature to verify")
+		}
+
+		extracted, err := ExtractSecret(sig, adaptor)
+		if err != nil {
+			t.Fatalf("ExtractSecret failed: %v", err)
+		}
+		if extracted != secret {
+			t.Errorf("Expected secret %x, got %x", secret, extracted)
+		}
+	}
+}
+
+// TestAdaptorSignDigest tests that digest adaptor signatures complete to VerifyDigest signatures
+func TestAdaptorSignDigest(t *testing.T) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{31: 0x0d})
+	secretKey, _ := btcec.PrivKeyFromBytes([]byte{31: 0x0e})
+	digest := TaggedHash("TapSighash", []byte("sighash"))
+
+	adaptor, err := AdaptorSignDigest(digest, priv, secretKey.PubKey())
+	if err != nil {
+		t.Fatalf("AdaptorSignDigest failed: %v", err)
+	}
+	if !VerifyAdaptorDigest(digest, priv.PubKey(), secretKey.PubKey(), adaptor) {
+		t.Fatal("Expected the adaptor signature to verify")
+	}
+	if VerifyAdaptorDigest([32]byte{}, priv.PubKey(), secretKey.PubKey(), adaptor) {
+		t.Error("Expected the adaptor signature to be bound to the digest")
+	}
+	sig, err := adaptor.Adapt(secretKey.Key.Bytes())
+	if err != nil {
+		t.Fatalf("Adapt failed: %v", err)
+	}
+	if !VerifyDigest(digest, priv.PubKey(), sig) {
+		t.Error("Expected the adapted signature to verify over the digest")
+	}
+}
+
+// TestVerifyAdaptorRejects tests that adaptor signatures are bound to their key, message and point
+func TestVerifyAdaptorRejects(t *testing.T) {
+	priv, _ := btcec.NewPrivateKey()
+	other, _ := btcec.NewPrivateKey()
+	secretKey, _ := btcec.NewPrivateKey()
+	T := secretKey.PubKey()
+	msg := []byte("pay Bob 1 BTC")
+
+	adaptor, err := AdaptorSign(msg, priv, T)
+	if err != nil {
+		t.Fatalf("AdaptorSign failed: %v", err)
+	}
+	tampered := *adaptor
+	tampered.S[31] ^= 0x01
+
+	tests := []struct {
+		name    string
+		msg     []byte
+		pub     *btcec.PublicKey
+		T       *btcec.PublicKey
+		adaptor *AdaptorSignature
+	}{
+		{"other message", []byte("pay Bob 2 BTC"), priv.PubKey(), T, adaptor},
+		{"other key", msg, other.PubKey(), T, adaptor},
+		{"other adaptor point", msg, priv.PubKey(), other.PubKey(), adaptor},
+		{"tampered s", msg, priv.PubKey(), T, &tampered},
+		{"nil adaptor", msg, priv.PubKey(), T, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if VerifyAdaptor(tt.msg, tt.pub, tt.T, tt.adaptor) {
+				t.Error("Expected VerifyAdaptor to fail")
+			}
+		})
+	}
+}
+
+func TestAdaptorErrors(t *testing.T) {
+	priv, _ := btcec.NewPrivateKey()
+	secretKey, _ := btcec.NewPrivateKey()
+	msg := []byte("msg")
+
+	if _, err := AdaptorSign(nil, priv, secretKey.PubKey()); err == nil {
+		t.Error("Expected error for empty message")
+	}
+	if _, err := AdaptorSign(msg, priv, nil); err == nil {
+		t.Error("Expected error for nil adaptor point")
+	}
+
+	adaptor, _ := AdaptorSign(msg, priv, secretKey.PubKey())
+	if _, err := adaptor.Adapt(priv.Key.Bytes()); !errors.Is(err, ErrInvalidAdaptorSecret) {
+		t.Errorf("Expected ErrInvalidAdaptorSecret for the wrong secret, got %v", err)
+	}
+
+	// A signature with another nonce says nothing about t
+	unrelated, _ := SignBIP340(msg, priv)
+	if _, err := ExtractSecret(unrelated, adaptor); err == nil {
+		t.Error("Expected error for an unrelated signature")
+	}
+}
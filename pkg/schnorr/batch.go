@@ -0,0 +1,369 @@
+package schnorr
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math"
+	"math/big"
+	"math/bits"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+)
+
+// Batch verification
+//
+// Verifying one BIP340 signature checks s·G == R + e·P. For a batch, pick
+// random a_i (a_1 = 1) and check a single combined equation:
+//
+//	(Σ a_i·s_i)·G == Σ a_i·R_i + Σ (a_i·e_i)·P_i
+//
+// If any signature is invalid the equation fails except with probability about
+// 2^-128, because the a_i are unknown to whoever produced the signatures. The
+// right-hand side is one multi-scalar multiplication (Pippenger's bucket
+// method), whose cost per point shrinks as the batch grows: a few hundred
+// signatures verify about twice as fast as in a loop, thousands faster still.
+// Splitting the 256-bit scalars with the GLV endomorphism and using signed
+// digits makes that multiplication another quarter faster.
+//
+// VerifyBatchParallel splits very large batches into chunks, verifies the chunks
+// on a pool of goroutines, and only checks the signatures of a rejected chunk
+// one by one to find the first invalid one.
+
+// batchWeightSize is the size in bytes of the random weights a_i
+const batchWeightSize = 16
+
+// defaultBatchChunkSize is the number of signatures a worker verifies at once
+const defaultBatchChunkSize = 1024
+
+// VerifyBatch verifies many BIP340 signatures at once
+//
+// msgs[i] is hashed with SHA256 exactly as in VerifyBIP340. Returns true only if
+// every signature is valid; it does not say which one failed, so callers that
+// need to know fall back to VerifyBIP340 for a batch that is rejected. An empty
+// batch is valid, mismatched slice lengths are not.
+//
+// Example:
+//
+//	ok := VerifyBatch(msgs, pubs, sigs)
+//	// Result: true if all len(msgs) signatures are valid
+func VerifyBatch(msgs [][]byte, pubs []*btcec.PublicKey, sigs [][64]byte) bool {
+	// Step 1: Validate inputs
+	if len(msgs) != len(pubs) || len(msgs) != len(sigs) {
+		return false
+	}
+	if len(msgs) == 0 {
+		return true
+	}
+	if len(msgs) == 1 {
+		return VerifyBIP340(msgs[0], pubs[0], sigs[0])
+	}
+
+	points := make([]btcec.JacobianPoint, 0, 2*len(msgs))
+	scalars := make([]btcec.ModNScalar, 0, 2*len(msgs))
+	var sum btcec.ModNScalar
+	for i := range msgs {
+		if len(msgs[i]) == 0 || pubs[i] == nil {
+			return false
+		}
+
+		// Step 2: Parse R (even-Y lift of r, rejects r >= p) and s (rejects s >= n)
+		r, err := btcschnorr.ParsePubKey(sigs[i][:32])
+		if err != nil {
+			return false
+		}
+		var s btcec.ModNScalar
+		sBytes := [32]byte(sigs[i][32:])
+		if overflow := s.SetBytes(&sBytes); overflow != 0 {
+			return false
+		}
+
+		// Step 3: P is the even-Y lift of the key's x coordinate
+		px := XOnlyFromPub(pubs[i])
+		p, err := btcschnorr.ParsePubKey(px[:])
+		if err != nil {
+			return false
+		}
+
+		// Step 4: e = H_challenge(r || P.x || m)
+		messageHash := sha256.Sum256(msgs[i])
+		e := challenge([32]byte(sigs[i][:32]), px, messageHash)
+
+		// Step 5: Weight a_i (a_1 = 1), accumulate a_i·s_i and the MSM terms
+		var a btcec.ModNScalar
+		if i == 0 {
+			a.SetInt(1)
+		} else if !randomWeight(&a) {
+			return false
+		}
+		sum.Add(new(btcec.ModNScalar).Mul2(&a, &s))
+
+		var rPoint, pPoint btcec.JacobianPoint
+		r.AsJacobian(&rPoint)
+		p.AsJacobian(&pPoint)
+		points = append(points, rPoint, pPoint)
+		scalars = append(scalars, a, *e.Mul(&a))
+	}
+
+	// Step 6: Compare (Σ a_i·s_i)·G with the multi-scalar multiplication
+	var lhs btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&sum, &lhs)
+	rhs := multiScalarMult(points, scalars)
+	lhs.ToAffine()
+	rhs.ToAffine()
+	return lhs.X.Equals(&rhs.X) && lhs.Y.Equals(&rhs.Y) && lhs.Z.Equals(&rhs.Z)
+}
+
+// batchOptions holds the settings selected with BatchOption
+type batchOptions struct {
+	workers   int
+	chunkSize int
+}
+
+// BatchOption configures VerifyBatchParallel
+type BatchOption func(*batchOptions)
+
+// WithBatchWorkers sets the number of verifying goroutines
+//
+// The default (or n <= 0) is runtime.GOMAXPROCS(0).
+func WithBatchWorkers(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.workers = n
+	}
+}
+
+// WithBatchChunkSize sets the number of signatures verified together by one worker
+//
+// Larger chunks make each multi-scalar multiplication cheaper per signature,
+// smaller ones spread the work more evenly and make cancellation quicker. The
+// default (or n <= 0) is 1024.
+func WithBatchChunkSize(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.chunkSize = n
+	}
+}
+
+// VerifyBatchParallel verifies many BIP340 signatures on a worker pool
+//
+// It returns the index of the first invalid signature, or -1 if all of them
+// are valid. If ctx is cancelled before every chunk needed for the answer was
+// verified, it returns -1 and ctx.Err(). Mismatched slice lengths are an error.
+//
+// Example:
+//
+//	bad, err := VerifyBatchParallel(ctx, msgs, pubs, sigs, WithBatchWorkers(4))
+//	// Result: bad == -1 if all signatures are valid, else the first invalid index
+func VerifyBatchParallel(ctx context.Context, msgs [][]byte, pubs []*btcec.PublicKey, sigs [][64]byte, opts ...BatchOption) (int, error) {
+	// Step 1: Validate inputs and apply options
+	if len(msgs) != len(pubs) || len(msgs) != len(sigs) {
+		return -1, errors.New("msgs, pubs and sigs must have the same length")
+	}
+	o := batchOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.workers <= 0 {
+		o.workers = runtime.GOMAXPROCS(0)
+	}
+	if o.chunkSize <= 0 {
+		o.chunkSize = defaultBatchChunkSize
+	}
+	chunks := (len(msgs) + o.chunkSize - 1) / o.chunkSize
+	workers := min(o.workers, chunks)
+
+	// Step 2: Workers claim chunks in order, skipping those after a known failure
+	var next atomic.Int64
+	var firstBad, firstSkipped atomic.Int64
+	firstBad.Store(math.MaxInt64)
+	firstSkipped.Store(math.MaxInt64)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				c := int(next.Add(1) - 1)
+				start := c * o.chunkSize
+				if c >= chunks || int64(start) > firstBad.Load() {
+					return
+				}
+				if ctx.Err() != nil {
+					storeMin(&firstSkipped, int64(start))
+					return
+				}
+				end := min(start+o.chunkSize, len(msgs))
+				if VerifyBatch(msgs[start:end], pubs[start:end], sigs[start:end]) {
+					continue
+				}
+
+				// Step 3: Find the failing signature of a rejected chunk
+				for i := start; i < end; i++ {
+					if !VerifyBIP340(msgs[i], pubs[i], sigs[i]) {
+						storeMin(&firstBad, int64(i))
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Step 4: A failure only counts once every chunk before it was verified
+	bad, skipped := firstBad.Load(), firstSkipped.Load()
+	if bad < skipped {
+		return int(bad), nil
+	}
+	if skipped != math.MaxInt64 {
+		return -1, ctx.Err()
+	}
+	return -1, nil
+}
+
+// storeMin lowers v to n if n is smaller
+func storeMin(v *atomic.Int64, n int64) {
+	for {
+		old := v.Load()
+		if n >= old || v.CompareAndSwap(old, n) {
+			return
+		}
+	}
+}
+
+// randomWeight sets a to a random non-zero 128-bit scalar
+func randomWeight(a *btcec.ModNScalar) bool {
+	var buf [32]byte
+	for a.IsZero() {
+		if _, err := rand.Read(buf[32-batchWeightSize:]); err != nil {
+			return false
+		}
+		a.SetBytes(&buf)
+	}
+	return true
+}
+
+// multiScalarMult computes Σ scalars[i]·points[i] with Pippenger's bucket method
+//
+// Step 1 halves the scalars with the GLV endomorphism: a 256-bit k·P becomes
+// k₁·P + k₂·φ(P) with 128-bit k₁, k₂ (arithmetic.SplitScalar), and scalars
+// that already fit in 128 bits (the weights a_i) are kept as they are. The
+// scalars are then cut into signed c-bit windows from the top, digits in
+// [-2^(c-1), 2^(c-1)], so a negative digit adds -P to the bucket of |d| and
+// only 2^(c-1) buckets are needed. For each window, every point is added to
+// the bucket of its digit, and Σ d·bucket_d is computed with two running
+// sums, so a window costs about len(terms) + 2^c additions, over about 130
+// bits instead of 256.
+func multiScalarMult(points []btcec.JacobianPoint, scalars []btcec.ModNScalar) btcec.JacobianPoint {
+	// Step 1: Split long scalars, making every scalar non-negative by negating its point
+	terms := make([]btcec.JacobianPoint, 0, 2*len(points))
+	magnitudes := make([][32]byte, 0, 2*len(points))
+	maxBits := 0
+	addTerm := func(p btcec.JacobianPoint, k *big.Int) {
+		if k.Sign() < 0 {
+			p.Y.Normalize().Negate(1).Normalize()
+			k.Neg(k)
+		}
+		var m [32]byte
+		k.FillBytes(m[:])
+		terms = append(terms, p)
+		magnitudes = append(magnitudes, m)
+		maxBits = max(maxBits, k.BitLen())
+	}
+	for i := range points {
+		b := scalars[i].Bytes()
+		k := new(big.Int).SetBytes(b[:])
+		if k.BitLen() <= 128 {
+			addTerm(points[i], k)
+			continue
+		}
+		k1, k2 := arithmetic.SplitScalar(k)
+		phi := points[i]
+		phi.X.Mul(&betaField).Normalize()
+		addTerm(points[i], k1)
+		addTerm(phi, k2)
+	}
+
+	// Step 2: Negated copies for negative digits
+	negTerms := make([]btcec.JacobianPoint, len(terms))
+	for i := range terms {
+		negTerms[i] = terms[i]
+		negTerms[i].Y.Normalize().Negate(1).Normalize()
+	}
+
+	// Step 3: Signed digits; one window more than maxBits needs, for the last carry
+	c := bits.Len(uint(len(terms))) - 2
+	c = max(2, min(c, 13))
+	windows := (maxBits + c) / c
+	digits := make([]int32, len(terms)*windows)
+	for i := range terms {
+		carry := 0
+		for w := 0; w < windows; w++ {
+			d := windowDigit(&magnitudes[i], w*c, c) + carry
+			carry = 0
+			if d > 1<<(c-1) {
+				d -= 1 << c
+				carry = 1
+			}
+			digits[i*windows+w] = int32(d)
+		}
+	}
+	buckets := make([]btcec.JacobianPoint, 1<<(c-1)+1)
+
+	var result btcec.JacobianPoint
+	for window := windows - 1; window >= 0; window-- {
+		// Step 4: result *= 2^c
+		for j := 0; j < c; j++ {
+			btcec.DoubleNonConst(&result, &result)
+		}
+
+		// Step 5: Sort points into buckets by |digit|, negated for negative digits
+		for j := range buckets {
+			buckets[j] = btcec.JacobianPoint{}
+		}
+		for i := range terms {
+			switch d := digits[i*windows+window]; {
+			case d > 0:
+				btcec.AddNonConst(&buckets[d], &terms[i], &buckets[d])
+			case d < 0:
+				btcec.AddNonConst(&buckets[-d], &negTerms[i], &buckets[-d])
+			}
+		}
+
+		// Step 6: Σ d·bucket_d = Σ_d (bucket_top + ... + bucket_d)
+		var running, sum btcec.JacobianPoint
+		for d := len(buckets) - 1; d > 0; d-- {
+			btcec.AddNonConst(&running, &buckets[d], &running)
+			btcec.AddNonConst(&sum, &running, &sum)
+		}
+		btcec.AddNonConst(&result, &sum, &result)
+	}
+	return result
+}
+
+// betaField is arithmetic.Beta as a field element: φ(x, y) = (β·x, y)
+var betaField = func() btcec.FieldVal {
+	var f btcec.FieldVal
+	f.SetByteSlice(arithmetic.Beta.Bytes())
+	return f
+}()
+
+// windowDigit returns the c bits of a big-endian 256-bit scalar starting at bit offset
+func windowDigit(scalar *[32]byte, offset, c int) int {
+	digit := 0
+	for b := 0; b < c; b++ {
+		bit := offset + b
+		if bit >= 256 {
+			break
+		}
+		if scalar[31-bit/8]>>(bit%8)&1 == 1 {
+			digit |= 1 << b
+		}
+	}
+	return digit
+}
@@ -0,0 +1,232 @@
+package schnorr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/fixtures"
+)
+
+// batchFixture signs n distinct messages with n deterministic keys
+func batchFixture(t testing.TB, n int) ([][]byte, []*btcec.PublicKey, [][64]byte) {
+	t.Helper()
+	rng := fixtures.DeterministicReader("schnorr-batch")
+	msgs := make([][]byte, n)
+	pubs := make([]*btcec.PublicKey, n)
+	sigs := make([][64]byte, n)
+	for i := 0; i < n; i++ {
+		var seed [32]byte
+		if _, err := rng.Read(seed[:]); err != nil {
+			t.Fatalf("Failed to read seed: %v", err)
+		}
+		priv, _ := btcec.PrivKeyFromBytes(seed[:])
+		msgs[i] = []byte(fmt.Sprintf("batch message %d", i))
+		pubs[i] = priv.PubKey()
+		sig, err := SignBIP340(msgs[i], priv)
+		if err != nil {
+			t.Fatalf("SignBIP340 failed: %v", err)
+		}
+		sigs[i] = sig
+	}
+	return msgs, pubs, sigs
+}
+
+func TestVerifyBatch(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 64} {
+		msgs, pubs, sigs := batchFixture(t, n)
+		if !VerifyBatch(msgs, pubs, sigs) {
+			t.Errorf("Expected a valid batch of %d signatures", n)
+		}
+	}
+}
+
+func TestVerifyBatchRejects(t *testing.T) {
+	const n = 16
+	tests := []struct {
+		name   string
+		mutate func(msgs [][]byte, pubs []*btcec.PublicKey, sigs [][64]byte) ([][]byte, []*btcec.PublicKey, [][64]byte)
+	}{
+		{"flipped s", func(m [][]byte, p []*btcec.PublicKey, s [][64]byte) ([][]byte, []*btcec.PublicKey, [][64]byte) {
+			s[9][63] ^= 0x01
+			return m, p, s
+		}},
+		{"flipped r", func(m [][]byte, p []*btcec.PublicKey, s [][64]byte) ([][]byte, []*btcec.PublicKey, [][64]byte) {
+			s[0][5] ^= 0x01
+			return m, p, s
+		}},
+		{"wrong message", func(m [][]byte, p []*btcec.PublicKey, s [][64]byte) ([][]byte, []*btcec.PublicKey, [][64]byte) {
+			m[3] = []byte("something else")
+			return m, p, s
+		}},
+		{"swapped keys", func(m [][]byte, p []*btcec.PublicKey, s [][64]byte) ([][]byte, []*btcec.PublicKey, [][64]byte) {
+			p[1], p[2] = p[2], p[1]
+			return m, p, s
+		}},
+		{"swapped signatures", func(m [][]byte, p []*btcec.PublicKey, s [][64]byte) ([][]byte, []*btcec.PublicKey, [][64]byte) {
+			s[4], s[5] = s[5], s[4]
+			return m, p, s
+		}},
+		{"s above order", func(m [][]byte, p []*btcec.PublicKey, s [][64]byte) ([][]byte, []*btcec.PublicKey, [][64]byte) {
+			for i := 32; i < 64; i++ {
+				s[7][i] = 0xff
+			}
+			return m, p, s
+		}},
+		{"nil key", func(m [][]byte, p []*btcec.PublicKey, s [][64]byte) ([][]byte, []*btcec.PublicKey, [][64]byte) {
+			p[6] = nil
+			return m, p, s
+		}},
+		{"empty message", func(m [][]byte, p []*btcec.PublicKey, s [][64]byte) ([][]byte, []*btcec.PublicKey, [][64]byte) {
+			m[8] = nil
+			return m, p, s
+		}},
+		{"length mismatch", func(m [][]byte, p []*btcec.PublicKey, s [][64]byte) ([][]byte, []*btcec.PublicKey, [][64]byte) {
+			return m, p, s[:n-1]
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, pubs, sigs := tt.mutate(batchFixture(t, n))
+			if VerifyBatch(msgs, pubs, sigs) {
+				t.Error("Expected the batch to be rejected")
+			}
+		})
+	}
+}
+
+func TestVerifyBatchParallel(t *testing.T) {
+	const n = 600
+	msgs, pubs, sigs := batchFixture(t, n)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		bad      []int
+		opts     []BatchOption
+		expected int
+	}{
+		{"all valid", nil, nil, -1},
+		{"all valid, small chunks", nil, []BatchOption{WithBatchChunkSize(64), WithBatchWorkers(8)}, -1},
+		{"one worker", []int{599}, []BatchOption{WithBatchChunkSize(100), WithBatchWorkers(1)}, 599},
+		{"first of several", []int{450, 123, 124}, []BatchOption{WithBatchChunkSize(50)}, 123},
+		{"first signature", []int{0, 599}, []BatchOption{WithBatchChunkSize(7)}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bad := make([][64]byte, n)
+			copy(bad, sigs)
+			for _, i := range tt.bad {
+				bad[i][63] ^= 0x01
+			}
+			got, err := VerifyBatchParallel(ctx, msgs, pubs, bad, tt.opts...)
+			if err != nil {
+				t.Fatalf("VerifyBatchParallel failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected index %d, got %d", tt.expected, got)
+			}
+		})
+	}
+
+	if got, err := VerifyBatchParallel(ctx, nil, nil, nil); got != -1 || err != nil {
+		t.Errorf("Expected an empty batch to be valid, got %d, %v", got, err)
+	}
+	if _, err := VerifyBatchParallel(ctx, msgs, pubs, sigs[:n-1]); err == nil {
+		t.Error("Expected error for mismatched lengths")
+	}
+}
+
+func TestVerifyBatchParallelCancelled(t *testing.T) {
+	msgs, pubs, sigs := batchFixture(t, 32)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	got, err := VerifyBatchParallel(ctx, msgs, pubs, sigs, WithBatchChunkSize(8))
+	if !errors.Is(err, context.Canceled) || got != -1 {
+		t.Errorf("Expected context.Canceled, got %d, %v", got, err)
+	}
+}
+
+// TestMultiScalarMult checks the bucket method against naive scalar multiplication
+func TestMultiScalarMult(t *testing.T) {
+	rng := fixtures.DeterministicReader("msm")
+	for _, n := range []int{1, 3, 10, 100, 1000} {
+		points := make([]btcec.JacobianPoint, n)
+		scalars := make([]btcec.ModNScalar, n)
+		var want btcec.JacobianPoint
+		for i := 0; i < n; i++ {
+			var buf [32]byte
+			rng.Read(buf[:])
+			priv, _ := btcec.PrivKeyFromBytes(buf[:])
+			priv.PubKey().AsJacobian(&points[i])
+			rng.Read(buf[:])
+			if i%2 == 1 {
+				// Batch weights are 128-bit and skip the GLV split
+				clear(buf[:32-batchWeightSize])
+			}
+			scalars[i].SetBytes(&buf)
+
+			var term btcec.JacobianPoint
+			btcec.ScalarMultNonConst(&scalars[i], &points[i], &term)
+			btcec.AddNonConst(&want, &term, &want)
+		}
+		got := multiScalarMult(points, scalars)
+		want.ToAffine()
+		got.ToAffine()
+		if !want.X.Equals(&got.X) || !want.Y.Equals(&got.Y) {
+			t.Errorf("multiScalarMult mismatch for %d points", n)
+		}
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	msgs, pubs, sigs := batchFixture(b, 256)
+	b.Run("loop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range msgs {
+				if !VerifyBIP340(msgs[j], pubs[j], sigs[j]) {
+					b.Fatal("invalid signature")
+				}
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if !VerifyBatch(msgs, pubs, sigs) {
+				b.Fatal("invalid batch")
+			}
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if bad, _ := VerifyBatchParallel(context.Background(), msgs, pubs, sigs, WithBatchChunkSize(64)); bad != -1 {
+				b.Fatal("invalid batch")
+			}
+		}
+	})
+}
+
+func BenchmarkMultiScalarMult(b *testing.B) {
+	// The shape of a 256-signature batch: 128-bit weights for R, 256-bit for P
+	rng := fixtures.DeterministicReader("msm-bench")
+	points := make([]btcec.JacobianPoint, 512)
+	scalars := make([]btcec.ModNScalar, 512)
+	for i := range points {
+		var buf [32]byte
+		rng.Read(buf[:])
+		priv, _ := btcec.PrivKeyFromBytes(buf[:])
+		priv.PubKey().AsJacobian(&points[i])
+		rng.Read(buf[:])
+		if i%2 == 0 {
+			clear(buf[:32-batchWeightSize])
+		}
+		scalars[i].SetBytes(&buf)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		multiScalarMult(points, scalars)
+	}
+}
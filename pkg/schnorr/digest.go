@@ -0,0 +1,67 @@
+package schnorr
+
+import (
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+// Pre-hashed messages
+//
+// BIP340 itself signs a 32-byte message. SignBIP340 and SignDomain derive that
+// message by hashing; SignDigest signs a digest the caller already computed,
+// such as a transaction sighash (tx.TaprootSigHash) or a hash from another
+// protocol. The caller is responsible for the digest being a real hash: signing
+// attacker-chosen 32-byte values is safe, but a digest that does not commit to
+// its context can be replayed in another one.
+
+// SignDigest produces a BIP340 signature over a 32-byte digest, without hashing it again
+//
+// Example:
+//
+//	sighash, _ := tx.TaprootSigHash(spendTx, 0, prevouts, tx.SigHashDefault)
+//	sig, err := SignDigest(sighash, tweakedKey)
+//	// Result: [64]byte signature for the key path witness
+func SignDigest(digest [32]byte, priv *btcec.PrivateKey, opts ...SignOption) (Signature, error) {
+	// Step 1: Validate inputs
+	if priv == nil {
+		return [64]byte{}, errors.New("private key cannot be nil")
+	}
+
+	// Step 2: Sign with the selected nonce options
+	var options signOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	signOpts, err := options.btcecOptions(digest, XOnlyFromPub(priv.PubKey()))
+	if err != nil {
+		return [64]byte{}, err
+	}
+	sig, err := btcschnorr.Sign(priv, digest[:], signOpts...)
+	if err != nil {
+		return [64]byte{}, err
+	}
+
+	// Step 3: Serialize as [r (32 bytes)][s (32 bytes)]
+	var out [64]byte
+	copy(out[:], sig.Serialize())
+	return out, nil
+}
+
+// VerifyDigest verifies a BIP340 signature over a 32-byte digest
+//
+// Example:
+//
+//	isValid := VerifyDigest(sighash, outputKey, sig)
+//	// Result: true if sig signs exactly sighash
+func VerifyDigest(digest [32]byte, pub *btcec.PublicKey, sigBz [64]byte) bool {
+	if pub == nil {
+		return false
+	}
+	sig, err := btcschnorr.ParseSignature(sigBz[:])
+	if err != nil {
+		return false
+	}
+	return sig.Verify(digest[:], pub)
+}
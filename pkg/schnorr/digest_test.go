@@ -0,0 +1,77 @@
+package schnorr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// TestSignDigestBIP340Vectors signs the official BIP340 vectors, whose messages are 32-byte digests
+func TestSignDigestBIP340Vectors(t *testing.T) {
+	tests := []struct {
+		secKey, aux, msg, sig string
+	}{
+		{
+			"0000000000000000000000000000000000000000000000000000000000000003",
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"e907831f80848d1069a5371b402410364bdf1c5f8307b0084c55f1ce2dca821525f66a4a85ea8b71e482a74f382d2ce5ebeee8fdb2172f477df4900d310536c0",
+		},
+		{
+			"b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfef",
+			"0000000000000000000000000000000000000000000000000000000000000001",
+			"243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+			"6896bd60eeae296db48a229ff71dfe071bde413e6d43f917dc8dcf8c78de33418906d11ac976abccb20b091292bff4ea897efcb639ea871cfa95f6de339e4b0a",
+		},
+	}
+	for i, tt := range tests {
+		keyBytes, _ := hex.DecodeString(tt.secKey)
+		priv, _ := btcec.PrivKeyFromBytes(keyBytes)
+		aux, _ := hex.DecodeString(tt.aux)
+		msg, _ := hex.DecodeString(tt.msg)
+
+		sig, err := SignDigest([32]byte(msg), priv, WithAuxRand([32]byte(aux)))
+		if err != nil {
+			t.Fatalf("Vector %d: SignDigest failed: %v", i, err)
+		}
+		if got := hex.EncodeToString(sig[:]); got != tt.sig {
+			t.Errorf("Vector %d: expected signature %s, got %s", i, tt.sig, got)
+		}
+		if !VerifyDigest([32]byte(msg), priv.PubKey(), sig) {
+			t.Errorf("Vector %d: expected VerifyDigest to accept", i)
+		}
+	}
+}
+
+func TestSignDigestMatchesSignBIP340(t *testing.T) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{31: 0x05})
+	msg := []byte("hash me once")
+	digest := sha256.Sum256(msg)
+
+	viaMessage, _ := SignBIP340(msg, priv)
+	viaDigest, err := SignDigest(digest, priv)
+	if err != nil {
+		t.Fatalf("SignDigest failed: %v", err)
+	}
+	if viaMessage != viaDigest {
+		t.Error("Expected SignBIP340(msg) == SignDigest(SHA256(msg))")
+	}
+
+	// The digest is not hashed again
+	if VerifyBIP340(digest[:], priv.PubKey(), viaDigest) {
+		t.Error("Expected VerifyBIP340 over the digest bytes to fail")
+	}
+	tampered := digest
+	tampered[0] ^= 0x01
+	if VerifyDigest(tampered, priv.PubKey(), viaDigest) {
+		t.Error("Expected VerifyDigest to reject another digest")
+	}
+	if VerifyDigest(digest, nil, viaDigest) {
+		t.Error("Expected VerifyDigest to reject a nil key")
+	}
+	if _, err := SignDigest(digest, nil); err == nil {
+		t.Error("Expected error for nil private key")
+	}
+}
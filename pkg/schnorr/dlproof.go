@@ -0,0 +1,155 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+)
+
+// Proofs of knowledge of a discrete logarithm
+//
+// A DLProof shows that the prover knows x with P = x·G without producing a
+// signature over anything: it is the Schnorr identification protocol made
+// non-interactive with Fiat–Shamir:
+//
+//	R = k·G
+//	e = TaggedHash("cryptography-playground/dlproof/v1", P || R || context)
+//	s = k + e·x
+//
+// and the verifier checks s·G == R + e·P. The challenge uses its own tag and
+// the full (compressed) key, so a proof can never be replayed as a BIP340
+// signature or the other way around. context binds the proof to its use, e.g.
+// a verifier's nonce, so it cannot be replayed in another session.
+
+// dlProofTag is the tagged hash domain of the challenge
+const dlProofTag = "cryptography-playground/dlproof/v1"
+
+// DLProofSize is the length of a serialized DLProof
+const DLProofSize = 33 + 32
+
+// DLProof is a non-interactive proof of knowledge of a private key
+type DLProof struct {
+	R [33]byte // Compressed commitment R = k·G
+	S [32]byte // s = k + e·x
+}
+
+// ProveDL proves knowledge of priv for its public key, bound to context
+//
+// Example:
+//
+//	proof, err := ProveDL(priv, verifierNonce)
+//	// The verifier checks VerifyDL(priv.PubKey(), proof, verifierNonce)
+func ProveDL(priv *btcec.PrivateKey, context []byte) (*DLProof, error) {
+	// Step 1: Validate inputs
+	if priv == nil || priv.Key.IsZero() {
+		return nil, errors.New("private key cannot be nil or zero")
+	}
+	pub := priv.PubKey().SerializeCompressed()
+
+	for {
+		// Step 2: Nonce from fresh randomness, bound to the key and context
+		var aux [32]byte
+		if _, err := rand.Read(aux[:]); err != nil {
+			return nil, fmt.Errorf("failed to read randomness: %w", err)
+		}
+		xBytes := priv.Key.Bytes()
+		kHash := TaggedHash("cryptography-playground/dlproof/nonce", aux[:], xBytes[:], pub, context)
+		arithmetic.ZeroBytes(xBytes[:])
+		var k btcec.ModNScalar
+		overflow := k.SetBytes(&kHash)
+		arithmetic.ZeroBytes(kHash[:])
+		if overflow != 0 || k.IsZero() {
+			continue
+		}
+
+		// Step 3: Commitment R = k·G
+		var R btcec.JacobianPoint
+		btcec.ScalarBaseMultNonConst(&k, &R)
+		R.ToAffine()
+		var proof DLProof
+		copy(proof.R[:], btcec.NewPublicKey(&R.X, &R.Y).SerializeCompressed())
+
+		// Step 4: s = k + e·x
+		e := dlChallenge(pub, proof.R, context)
+		var s btcec.ModNScalar
+		s.Mul2(&e, &priv.Key).Add(&k)
+		k.Zero()
+		proof.S = s.Bytes()
+		return &proof, nil
+	}
+}
+
+// VerifyDL checks a proof of knowledge of pub's private key for context
+//
+// Formula: s·G − e·P == R
+//
+// Example:
+//
+//	if !VerifyDL(pub, proof, verifierNonce) {
+//		// the prover does not own pub
+//	}
+func VerifyDL(pub *btcec.PublicKey, proof *DLProof, context []byte) bool {
+	// Step 1: Validate inputs
+	if pub == nil || proof == nil {
+		return false
+	}
+	R, err := btcec.ParsePubKey(proof.R[:])
+	if err != nil {
+		return false
+	}
+	var s btcec.ModNScalar
+	if overflow := s.SetBytes(&proof.S); overflow != 0 {
+		return false
+	}
+
+	// Step 2: s·G − e·P
+	e := dlChallenge(pub.SerializeCompressed(), proof.R, context)
+	e.Negate()
+	var sG, eP, pj, got btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&s, &sG)
+	pub.AsJacobian(&pj)
+	btcec.ScalarMultNonConst(&e, &pj, &eP)
+	btcec.AddNonConst(&sG, &eP, &got)
+	if got.Z.IsZero() {
+		return false
+	}
+
+	// Step 3: Compare with R
+	var rj btcec.JacobianPoint
+	R.AsJacobian(&rj)
+	got.ToAffine()
+	return got.X.Equals(&rj.X) && got.Y.Equals(&rj.Y)
+}
+
+// Serialize returns R || s
+func (p *DLProof) Serialize() [DLProofSize]byte {
+	var out [DLProofSize]byte
+	copy(out[:33], p.R[:])
+	copy(out[33:], p.S[:])
+	return out
+}
+
+// ParseDLProof parses a proof serialized with Serialize
+func ParseDLProof(b []byte) (*DLProof, error) {
+	if len(b) != DLProofSize {
+		return nil, fmt.Errorf("proof must be %d bytes, got %d", DLProofSize, len(b))
+	}
+	var proof DLProof
+	copy(proof.R[:], b[:33])
+	copy(proof.S[:], b[33:])
+	if _, err := btcec.ParsePubKey(proof.R[:]); err != nil {
+		return nil, fmt.Errorf("invalid commitment: %w", err)
+	}
+	return &proof, nil
+}
+
+// dlChallenge computes e = TaggedHash(dlProofTag, P || R || context) mod n
+func dlChallenge(pub []byte, R [33]byte, context []byte) btcec.ModNScalar {
+	h := TaggedHash(dlProofTag, pub, R[:], context)
+	var e btcec.ModNScalar
+	e.SetBytes(&h)
+	return e
+}
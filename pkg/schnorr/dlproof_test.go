@@ -0,0 +1,89 @@
+package schnorr
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+func TestProveDL(t *testing.T) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{31: 0x07})
+	other, _ := btcec.PrivKeyFromBytes([]byte{31: 0x08})
+	context := []byte("verifier nonce 1")
+
+	proof, err := ProveDL(priv, context)
+	if err != nil {
+		t.Fatalf("ProveDL failed: %v", err)
+	}
+	if !VerifyDL(priv.PubKey(), proof, context) {
+		t.Fatal("Expected the proof to verify")
+	}
+
+	// Proofs survive serialization
+	ser := proof.Serialize()
+	parsed, err := ParseDLProof(ser[:])
+	if err != nil {
+		t.Fatalf("ParseDLProof failed: %v", err)
+	}
+	if !VerifyDL(priv.PubKey(), parsed, context) {
+		t.Error("Expected the parsed proof to verify")
+	}
+
+	// Proofs are bound to the key, the context and every byte of the proof
+	tests := []struct {
+		name    string
+		pub     *btcec.PublicKey
+		proof   func() *DLProof
+		context []byte
+	}{
+		{"other key", other.PubKey(), func() *DLProof { return proof }, context},
+		{"other context", priv.PubKey(), func() *DLProof { return proof }, []byte("verifier nonce 2")},
+		{"tampered s", priv.PubKey(), func() *DLProof { p := *proof; p.S[31] ^= 0x01; return &p }, context},
+		{"tampered R", priv.PubKey(), func() *DLProof { p := *proof; p.R[0] ^= 0x01; return &p }, context},
+		{"nil proof", priv.PubKey(), func() *DLProof { return nil }, context},
+		{"nil key", nil, func() *DLProof { return proof }, context},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if VerifyDL(tt.pub, tt.proof(), tt.context) {
+				t.Error("Expected the proof to be rejected")
+			}
+		})
+	}
+}
+
+// TestDLProofIsNotASignature tests that proofs and BIP340 signatures cannot be swapped
+func TestDLProofIsNotASignature(t *testing.T) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{31: 0x07})
+	context := []byte("message")
+
+	proof, _ := ProveDL(priv, context)
+	var sig [64]byte
+	copy(sig[:32], proof.R[1:])
+	copy(sig[32:], proof.S[:])
+	if VerifyBIP340(context, priv.PubKey(), sig) {
+		t.Error("Expected a proof not to verify as a signature")
+	}
+
+	sig, _ = SignBIP340(context, priv)
+	R, err := btcec.ParsePubKey(append([]byte{0x02}, sig[:32]...))
+	if err != nil {
+		t.Fatalf("ParsePubKey failed: %v", err)
+	}
+	asProof := &DLProof{R: [33]byte(R.SerializeCompressed()), S: [32]byte(sig[32:])}
+	if VerifyDL(priv.PubKey(), asProof, context) {
+		t.Error("Expected a signature not to verify as a proof")
+	}
+}
+
+func TestDLProofErrors(t *testing.T) {
+	if _, err := ProveDL(nil, nil); err == nil {
+		t.Error("Expected error for a nil key")
+	}
+	if _, err := ParseDLProof(make([]byte, DLProofSize-1)); err == nil {
+		t.Error("Expected error for a short proof")
+	}
+	if _, err := ParseDLProof(make([]byte, DLProofSize)); err == nil {
+		t.Error("Expected error for an invalid commitment")
+	}
+}
@@ -0,0 +1,74 @@
+package schnorr
+
+import (
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// Domain-separated signing
+//
+// An application that signs several kinds of messages (login challenges,
+// payment authorizations, ...) with the same key must make sure a signature for
+// one kind is never valid for another. SignDomain bakes the kind into the hash:
+//
+//	digest = SHA256(SHA256(domain) || SHA256(domain) || msg)
+//
+// which is the BIP340 tagged hash with the domain as tag. Digests of different
+// domains are independent, so a signature only verifies with VerifyDomain and
+// the domain it was made for.
+//
+// The separation only holds between domains: SignBIP340 over the bytes
+// SHA256(domain) || SHA256(domain) || msg produces the same digest. Applications
+// should sign everything through SignDomain and never expose plain SignBIP340
+// with the same key.
+
+// ErrEmptyDomain is returned when a domain-separated operation is given an empty domain
+var ErrEmptyDomain = errors.New("domain cannot be empty")
+
+// SignDomain produces a BIP340 signature over msg in the given domain
+//
+// Example:
+//
+//	sig, err := SignDomain("example.com/auth/v1", challenge, privateKey)
+//	// Result: [64]byte signature, valid only with VerifyDomain("example.com/auth/v1", ...)
+func SignDomain(domain string, msg []byte, priv *btcec.PrivateKey) (Signature, error) {
+	// Step 1: Validate inputs
+	if domain == "" {
+		return [64]byte{}, ErrEmptyDomain
+	}
+	if len(msg) == 0 {
+		return [64]byte{}, errors.New("message cannot be empty")
+	}
+	if priv == nil {
+		return [64]byte{}, errors.New("private key cannot be nil")
+	}
+
+	// Step 2: Hash the message with the domain as tag
+	digest := TaggedHash(domain, msg)
+
+	// Step 3: Sign the digest directly (it is already 32 bytes)
+	return SignDigest(digest, priv)
+}
+
+// VerifyDomain verifies a signature produced by SignDomain
+//
+// Returns false if the signature was made for another domain, including plain
+// SignBIP340 signatures over the same message.
+//
+// Example:
+//
+//	isValid := VerifyDomain("example.com/auth/v1", challenge, publicKey, sig)
+//	// Result: true if sig was made with SignDomain in the same domain
+func VerifyDomain(domain string, msg []byte, pub *btcec.PublicKey, sigBz [64]byte) bool {
+	// Step 1: Validate inputs
+	if domain == "" || len(msg) == 0 || pub == nil {
+		return false
+	}
+
+	// Step 2: Recompute the domain digest
+	digest := TaggedHash(domain, msg)
+
+	// Step 3: Verify as a plain BIP340 signature over the digest
+	return VerifyDigest(digest, pub, sigBz)
+}
@@ -0,0 +1,88 @@
+package schnorr
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// TestSignDomain tests that domain-separated signatures only verify in their own domain
+func TestSignDomain(t *testing.T) {
+	priv, err := btcec.NewPrivateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate private key: %v", err)
+	}
+	pub := priv.PubKey()
+	msg := []byte("transfer 1 BTC to bob")
+
+	sig, err := SignDomain("example/payment/v1", msg, priv)
+	if err != nil {
+		t.Fatalf("SignDomain failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		domain   string
+		msg      []byte
+		expected bool
+	}{
+		{"same domain", "example/payment/v1", msg, true},
+		{"other domain", "example/auth/v1", msg, false},
+		{"other version", "example/payment/v2", msg, false},
+		{"other message", "example/payment/v1", []byte("transfer 2 BTC to bob"), false},
+		{"empty domain", "", msg, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyDomain(tt.domain, tt.msg, pub, sig); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	// A domain signature is not a plain BIP340 signature and vice versa
+	if VerifyBIP340(msg, pub, sig) {
+		t.Error("Expected domain signature to fail plain verification")
+	}
+	plain, _ := SignBIP340(msg, priv)
+	if VerifyDomain("example/payment/v1", msg, pub, plain) {
+		t.Error("Expected plain signature to fail domain verification")
+	}
+}
+
+// TestSignDomainDigest tests the digest against the BIP340 tagged hash definition
+func TestSignDomainDigest(t *testing.T) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{0x01})
+	msg := []byte("hello")
+
+	sig, err := SignDomain("tag", msg, priv)
+	if err != nil {
+		t.Fatalf("SignDomain failed: %v", err)
+	}
+
+	// SignBIP340 hashes with SHA256 once; feeding it tag||tag||msg gives the same digest
+	tag := sha256.Sum256([]byte("tag"))
+	preimage := append(append(append([]byte(nil), tag[:]...), tag[:]...), msg...)
+	if !VerifyBIP340(preimage, priv.PubKey(), sig) {
+		t.Error("Expected digest to be SHA256(SHA256(tag) || SHA256(tag) || msg)")
+	}
+}
+
+func TestSignDomainErrors(t *testing.T) {
+	priv, _ := btcec.NewPrivateKey()
+
+	if _, err := SignDomain("", []byte("msg"), priv); !errors.Is(err, ErrEmptyDomain) {
+		t.Errorf("Expected ErrEmptyDomain, got %v", err)
+	}
+	if _, err := SignDomain("d", nil, priv); err == nil {
+		t.Error("Expected error for empty message")
+	}
+	if _, err := SignDomain("d", []byte("msg"), nil); err == nil {
+		t.Error("Expected error for nil private key")
+	}
+	if VerifyDomain("d", []byte("msg"), nil, [64]byte{}) {
+		t.Error("Expected false for nil public key")
+	}
+}
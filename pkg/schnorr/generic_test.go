@@ -0,0 +1,112 @@
+package schnorr
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/group"
+)
+
+func TestScheme(t *testing.T) {
+	for _, g := range []group.Group{group.Secp256k1(), group.P256(), group.Edwards25519()} {
+		t.Run(g.Name(), func(t *testing.T) {
+			s := NewScheme(g)
+			priv, pub, err := s.GenerateKey()
+			if err != nil {
+				t.Fatalf("GenerateKey failed: %v", err)
+			}
+			msg := []byte("same protocol, different group")
+
+			sig, err := s.Sign(priv, msg)
+			if err != nil {
+				t.Fatalf("Sign failed: %v", err)
+			}
+			if len(sig) != s.SignatureSize() {
+				t.Fatalf("Expected %d-byte signature, got %d", s.SignatureSize(), len(sig))
+			}
+			if !s.Verify(pub, msg, sig) {
+				t.Fatal("Expected signature to verify")
+			}
+
+			// Round trip the public key through its encoding
+			parsed, err := g.ParsePoint(pub.Bytes())
+			if err != nil || !s.Verify(parsed, msg, sig) {
+				t.Errorf("Expected parsed key to verify: %v", err)
+			}
+
+			// Tampering
+			if s.Verify(pub, []byte("other message"), sig) {
+				t.Error("Expected verification to fail for a different message")
+			}
+			bad := bytes.Clone(sig)
+			bad[len(bad)-1] ^= 1
+			if s.Verify(pub, msg, bad) {
+				t.Error("Expected verification to fail for a modified s")
+			}
+			_, other, _ := s.GenerateKey()
+			if s.Verify(other, msg, sig) {
+				t.Error("Expected verification to fail for a different key")
+			}
+			if s.Verify(pub, msg, sig[:len(sig)-1]) {
+				t.Error("Expected verification to fail for a short signature")
+			}
+			if s.Verify(g.Identity(), msg, sig) {
+				t.Error("Expected verification to fail for the identity key")
+			}
+
+			// s ≥ n is rejected rather than reduced
+			high := bytes.Clone(sig)
+			sc := new(big.Int).SetBytes(sig[g.PointSize():])
+			sc.Add(sc, g.Order())
+			if sc.BitLen() <= 8*group.ScalarSize(g) {
+				sc.FillBytes(high[g.PointSize():])
+				if s.Verify(pub, msg, high) {
+					t.Error("Expected verification to fail for s + n")
+				}
+			}
+		})
+	}
+}
+
+func TestSchemeDefaultsToSecp256k1(t *testing.T) {
+	if name := NewScheme(nil).Group().Name(); name != "secp256k1" {
+		t.Errorf("Expected secp256k1, got %s", name)
+	}
+}
+
+func TestSchemeSignErrors(t *testing.T) {
+	s := NewScheme(group.P256())
+	tests := []struct {
+		name string
+		priv *big.Int
+	}{
+		{"nil", nil},
+		{"zero", big.NewInt(0)},
+		{"negative", big.NewInt(-1)},
+		{"order", s.Group().Order()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Sign(tt.priv, []byte("msg")); !errors.Is(err, ErrInvalidScalar) {
+				t.Errorf("Expected ErrInvalidScalar, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSchemeGroupSeparation(t *testing.T) {
+	// The same scalar on secp256k1 and P-256 gives same-sized keys and
+	// signatures, but the group-specific tags keep them from cross-verifying.
+	k1, p256 := NewScheme(group.Secp256k1()), NewScheme(group.P256())
+	priv := big.NewInt(0x1234)
+	msg := []byte("domain separation")
+	sig, err := k1.Sign(priv, msg)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if p256.Verify(p256.Group().ScalarBaseMult(priv), msg, sig) {
+		t.Error("Expected a secp256k1 signature not to verify over P-256")
+	}
+}
@@ -0,0 +1,122 @@
+package schnorr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// Key pairs
+//
+// A *btcec.PrivateKey prints its scalar with %v and %x, so a debug log line or
+// an error message can leak it. KeyPair holds the private key behind methods
+// that never print it: String and GoString show the x-only public key and a
+// redaction marker, and JSON encoding yields an empty object. Zeroize
+// overwrites the scalar once the key is no longer needed; afterwards the pair
+// refuses to sign.
+
+// ErrKeyZeroized is returned when a zeroized KeyPair is asked to sign
+var ErrKeyZeroized = errors.New("key pair has been zeroized")
+
+// KeyPair is a private key with its public key
+//
+// It implements Signer.
+type KeyPair struct {
+	priv *btcec.PrivateKey
+	pub  *btcec.PublicKey
+}
+
+// NewKeyPair wraps a private key
+//
+// The pair keeps a reference to priv, so Zeroize also clears the caller's key.
+func NewKeyPair(priv *btcec.PrivateKey) (*KeyPair, error) {
+	if priv == nil || priv.Key.IsZero() {
+		return nil, errors.New("private key cannot be nil or zero")
+	}
+	return &KeyPair{priv: priv, pub: priv.PubKey()}, nil
+}
+
+// GenerateKeyPair creates a key pair from a fresh random private key
+//
+// Example:
+//
+//	kp, err := GenerateKeyPair()
+//	fmt.Println(kp) // KeyPair(3c72…, private key redacted)
+//	defer kp.Zeroize()
+func GenerateKeyPair() (*KeyPair, error) {
+	priv, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+	return NewKeyPair(priv)
+}
+
+// KeyPairFromBytes parses a 32-byte private key (1 <= d < n)
+func KeyPairFromBytes(b []byte) (*KeyPair, error) {
+	if len(b) != 32 {
+		return nil, fmt.Errorf("private key must be 32 bytes, got %d", len(b))
+	}
+	var d btcec.ModNScalar
+	if overflow := d.SetByteSlice(b); overflow || d.IsZero() {
+		return nil, errors.New("private key must be between 1 and the curve order")
+	}
+	return NewKeyPair(btcec.PrivKeyFromScalar(&d))
+}
+
+// PrivateKey returns the private key, or nil once zeroized
+func (k *KeyPair) PrivateKey() *btcec.PrivateKey {
+	return k.priv
+}
+
+// PubKey returns the full public key
+func (k *KeyPair) PubKey() *btcec.PublicKey {
+	return k.pub
+}
+
+// PublicKey implements Signer: it returns the x-only public key
+func (k *KeyPair) PublicKey() XOnlyPublicKey {
+	return XOnlyFromPub(k.pub)
+}
+
+// Compressed returns the 33-byte compressed public key
+func (k *KeyPair) Compressed() [33]byte {
+	return [33]byte(k.pub.SerializeCompressed())
+}
+
+// Sign implements Signer: it signs a 32-byte digest with BIP340
+func (k *KeyPair) Sign(digest [32]byte) ([64]byte, error) {
+	if k.priv == nil {
+		return [64]byte{}, ErrKeyZeroized
+	}
+	return SignDigest(digest, k.priv)
+}
+
+// Zeroize overwrites the private scalar and drops the reference to it
+//
+// The public key stays available. Calling Zeroize twice is harmless.
+func (k *KeyPair) Zeroize() {
+	if k.priv != nil {
+		k.priv.Zero()
+		k.priv = nil
+	}
+}
+
+// String returns the x-only public key; the private key is never printed
+func (k *KeyPair) String() string {
+	if k == nil {
+		return "KeyPair(nil)"
+	}
+	if k.priv == nil {
+		return fmt.Sprintf("KeyPair(%s, zeroized)", k.PublicKey())
+	}
+	return fmt.Sprintf("KeyPair(%s, private key redacted)", k.PublicKey())
+}
+
+// GoString is used by %#v; like String it never prints the private key
+func (k *KeyPair) GoString() string {
+	if k == nil {
+		return "(*schnorr.KeyPair)(nil)"
+	}
+	return fmt.Sprintf("&schnorr.KeyPair{PublicKey: %q, PrivateKey: REDACTED}", k.PublicKey())
+}
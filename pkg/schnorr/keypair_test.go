@@ -0,0 +1,100 @@
+package schnorr
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestKeyPair(t *testing.T) {
+	secret := make([]byte, 32)
+	secret[31] = 0x07
+	kp, err := KeyPairFromBytes(secret)
+	if err != nil {
+		t.Fatalf("KeyPairFromBytes failed: %v", err)
+	}
+
+	// The derived forms agree with each other
+	compressed := kp.Compressed()
+	if XOnlyPublicKey(compressed[1:]) != kp.PublicKey() || kp.PubKey() == nil {
+		t.Error("Expected the x-only key to be the compressed key without its prefix")
+	}
+
+	// Signatures verify under the x-only key, through the Signer interface
+	sig, err := SignMessage([]byte("key pair"), kp)
+	if err != nil {
+		t.Fatalf("SignMessage failed: %v", err)
+	}
+	if !VerifyBIP340([]byte("key pair"), kp.PubKey(), sig) {
+		t.Error("Expected a valid signature")
+	}
+
+	// Zeroize clears the scalar and stops signing
+	priv := kp.PrivateKey()
+	kp.Zeroize()
+	kp.Zeroize()
+	if !priv.Key.IsZero() || kp.PrivateKey() != nil {
+		t.Error("Expected the private scalar to be cleared")
+	}
+	if _, err := kp.Sign([32]byte{}); !errors.Is(err, ErrKeyZeroized) {
+		t.Errorf("Expected ErrKeyZeroized, got %v", err)
+	}
+	if !strings.Contains(kp.String(), "zeroized") {
+		t.Errorf("Expected the zeroized state in %q", kp.String())
+	}
+}
+
+// TestKeyPairRedaction checks that no formatting verb prints the private scalar
+func TestKeyPairRedaction(t *testing.T) {
+	secret, _ := hex.DecodeString("b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfef")
+	kp, err := KeyPairFromBytes(secret)
+	if err != nil {
+		t.Fatalf("KeyPairFromBytes failed: %v", err)
+	}
+	hexSecret := hex.EncodeToString(secret)
+
+	outputs := map[string]string{}
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%x", "%X", "%q"} {
+		outputs[verb] = fmt.Sprintf(verb, kp)
+		outputs[verb+" (value)"] = fmt.Sprintf(verb, *kp)
+	}
+	data, _ := json.Marshal(kp)
+	outputs["json"] = string(data)
+
+	for name, out := range outputs {
+		if strings.Contains(strings.ToLower(out), hexSecret) || strings.Contains(out, string(secret)) {
+			t.Errorf("%s leaks the private key: %s", name, out)
+		}
+	}
+	if !strings.Contains(outputs["%v"], kp.PublicKey().String()) || !strings.Contains(outputs["%#v"], "REDACTED") {
+		t.Errorf("Expected the public key and a redaction marker, got %q and %q", outputs["%v"], outputs["%#v"])
+	}
+}
+
+func TestKeyPairErrors(t *testing.T) {
+	order, _ := hex.DecodeString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	tests := []struct {
+		name string
+		key  []byte
+	}{
+		{"short", make([]byte, 31)},
+		{"zero", make([]byte, 32)},
+		{"curve order", order},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := KeyPairFromBytes(tt.key); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+	if _, err := NewKeyPair(nil); err == nil {
+		t.Error("Expected error for a nil key")
+	}
+	if kp, err := GenerateKeyPair(); err != nil || kp.PrivateKey() == nil {
+		t.Errorf("GenerateKeyPair failed: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+// Nonce options for SignBIP340
+//
+// BIP340 mixes 32 bytes of auxiliary randomness into the nonce:
+//
+//	t = d XOR hash_BIP0340/aux(a)
+//	k = hash_BIP0340/nonce(t || P.x || m)
+//
+// With fresh random a, repeated signatures of the same message differ, which
+// makes power and fault side-channel attacks on the nonce much harder. The
+// nonce stays safe even if a is predictable, so randomness is a hardening
+// measure, not a requirement. Without aux randomness, SignBIP340 derives the
+// nonce deterministically (RFC6979 over the key and message), so the same key
+// and message always give the same signature.
+//
+// A NonceProvider chooses a per signature instead, for example from a test
+// vector file or from a protocol that derives it from its own transcript.
+// Because the provider only supplies a, the nonce is still bound to the key and
+// message: a buggy provider can make signatures deterministic, but it cannot
+// make two different messages share a nonce.
+
+// signOptions holds the settings selected with SignOption
+type signOptions struct {
+	auxRand  *[32]byte
+	provider NonceProvider
+}
+
+// SignOption configures SignBIP340
+type SignOption func(*signOptions)
+
+// WithAuxRand sets the BIP340 auxiliary randomness a
+//
+// Example:
+//
+//	var aux [32]byte
+//	rand.Read(aux[:])
+//	sig, err := SignBIP340(msg, priv, WithAuxRand(aux))
+func WithAuxRand(aux [32]byte) SignOption {
+	return func(o *signOptions) {
+		o.auxRand, o.provider = &aux, nil
+	}
+}
+
+// WithDeterministic selects the deterministic nonce (the default)
+//
+// It overrides an earlier WithAuxRand or WithNonceProvider, so callers can
+// build option lists conditionally.
+func WithDeterministic() SignOption {
+	return func(o *signOptions) {
+		o.auxRand, o.provider = nil, nil
+	}
+}
+
+// NonceProvider supplies the BIP340 auxiliary randomness for each signature
+//
+// digest is the 32-byte message being signed and pub the signer's x-only key.
+type NonceProvider interface {
+	AuxRand(digest, pub [32]byte) ([32]byte, error)
+}
+
+// NonceFunc adapts a function to the NonceProvider interface
+type NonceFunc func(digest, pub [32]byte) ([32]byte, error)
+
+// AuxRand calls f(digest, pub)
+func (f NonceFunc) AuxRand(digest, pub [32]byte) ([32]byte, error) {
+	return f(digest, pub)
+}
+
+// RandomNonces draws fresh auxiliary randomness from crypto/rand for every signature
+var RandomNonces NonceProvider = NonceFunc(func(_, _ [32]byte) ([32]byte, error) {
+	var aux [32]byte
+	_, err := rand.Read(aux[:])
+	return aux, err
+})
+
+// WithNonceProvider asks provider for the auxiliary randomness of each signature
+//
+// Example:
+//
+//	sig, err := SignBIP340(msg, priv, WithNonceProvider(RandomNonces))
+//
+//	// Replay the aux_rand column of a test vector file
+//	vectors := NonceFunc(func(digest, _ [32]byte) ([32]byte, error) { return auxByMsg[digest], nil })
+//	sig, err = SignDigest(digest, priv, WithNonceProvider(vectors))
+func WithNonceProvider(provider NonceProvider) SignOption {
+	return func(o *signOptions) {
+		o.auxRand, o.provider = nil, provider
+	}
+}
+
+// btcecOptions translates the options for btcschnorr.Sign
+func (o *signOptions) btcecOptions(digest, pub [32]byte) ([]btcschnorr.SignOption, error) {
+	aux := o.auxRand
+	if o.provider != nil {
+		a, err := o.provider.AuxRand(digest, pub)
+		if err != nil {
+			return nil, fmt.Errorf("nonce provider: %w", err)
+		}
+		aux = &a
+	}
+	if aux == nil {
+		return nil, nil
+	}
+	return []btcschnorr.SignOption{btcschnorr.CustomNonce(*aux)}, nil
+}
@@ -0,0 +1,98 @@
+package schnorr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+func TestSignBIP340Options(t *testing.T) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{31: 0x03})
+	msg := []byte("aux randomness")
+
+	base, err := SignBIP340(msg, priv)
+	if err != nil {
+		t.Fatalf("SignBIP340 failed: %v", err)
+	}
+	sign := func(opts ...SignOption) [64]byte {
+		sig, err := SignBIP340(msg, priv, opts...)
+		if err != nil {
+			t.Fatalf("SignBIP340 failed: %v", err)
+		}
+		if !VerifyBIP340(msg, priv.PubKey(), sig) {
+			t.Fatal("Expected the signature to verify")
+		}
+		return sig
+	}
+
+	auxA := sign(WithAuxRand([32]byte{0x01}))
+	auxB := sign(WithAuxRand([32]byte{0x02}))
+	tests := []struct {
+		name  string
+		got   [64]byte
+		want  [64]byte
+		equal bool
+	}{
+		{"deterministic is the default", sign(WithDeterministic()), base, true},
+		{"deterministic overrides aux", sign(WithAuxRand([32]byte{0x01}), WithDeterministic()), base, true},
+		{"same aux, same signature", sign(WithAuxRand([32]byte{0x01})), auxA, true},
+		{"aux changes the nonce", auxA, base, false},
+		{"different aux, different signature", auxA, auxB, false},
+	}
+	for _, tt := range tests {
+		if (tt.got == tt.want) != tt.equal {
+			t.Errorf("%s: expected equal=%v", tt.name, tt.equal)
+		}
+	}
+}
+
+// TestNonceProvider tests WithNonceProvider against the official BIP340 vector 1
+func TestNonceProvider(t *testing.T) {
+	secKey, _ := hex.DecodeString("b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfef")
+	msgBytes, _ := hex.DecodeString("243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89")
+	expected, _ := hex.DecodeString("6896bd60eeae296db48a229ff71dfe071bde413e6d43f917dc8dcf8c78de3341" +
+		"8906d11ac976abccb20b091292bff4ea897efcb639ea871cfa95f6de339e4b0a")
+	priv, _ := btcec.PrivKeyFromBytes(secKey)
+	digest := [32]byte(msgBytes)
+
+	// The provider sees the digest and key, and its aux_rand is used as is
+	var gotDigest, gotPub [32]byte
+	vector := NonceFunc(func(digest, pub [32]byte) ([32]byte, error) {
+		gotDigest, gotPub = digest, pub
+		return [32]byte{31: 0x01}, nil
+	})
+	sig, err := SignDigest(digest, priv, WithNonceProvider(vector))
+	if err != nil {
+		t.Fatalf("SignDigest failed: %v", err)
+	}
+	if !bytes.Equal(sig[:], expected) {
+		t.Errorf("Expected vector signature %x, got %x", expected, sig)
+	}
+	if gotDigest != digest || gotPub != XOnlyFromPub(priv.PubKey()) {
+		t.Error("Expected the provider to receive the digest and x-only key")
+	}
+
+	// Later options override earlier ones
+	if sig, _ := SignDigest(digest, priv, WithNonceProvider(vector), WithDeterministic()); bytes.Equal(sig[:], expected) {
+		t.Error("Expected WithDeterministic to override the provider")
+	}
+	if sig, _ := SignDigest(digest, priv, WithAuxRand([32]byte{0x07}), WithNonceProvider(vector)); !bytes.Equal(sig[:], expected) {
+		t.Error("Expected the provider to override WithAuxRand")
+	}
+
+	// Random nonces give different, valid signatures
+	a, _ := SignDigest(digest, priv, WithNonceProvider(RandomNonces))
+	b, _ := SignDigest(digest, priv, WithNonceProvider(RandomNonces))
+	if a == b || !VerifyDigest(digest, priv.PubKey(), a) || !VerifyDigest(digest, priv.PubKey(), b) {
+		t.Error("Expected two valid, different signatures with random nonces")
+	}
+
+	// Provider errors are returned
+	failing := NonceFunc(func(_, _ [32]byte) ([32]byte, error) { return [32]byte{}, errors.New("device unplugged") })
+	if _, err := SignDigest(digest, priv, WithNonceProvider(failing)); err == nil {
+		t.Error("Expected the provider error")
+	}
+}
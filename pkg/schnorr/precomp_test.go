@@ -0,0 +1,117 @@
+package schnorr
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/fixtures"
+)
+
+// TestVerifierContext checks the precomputed path agrees with VerifyDigest on valid and tampered signatures
+func TestVerifierContext(t *testing.T) {
+	rng := fixtures.DeterministicReader("verifier-context")
+	for k := 0; k < 4; k++ {
+		var seed [32]byte
+		rng.Read(seed[:])
+		priv, _ := btcec.PrivKeyFromBytes(seed[:])
+		pub := priv.PubKey()
+		v, err := NewVerifierContext(XOnlyFromPub(pub))
+		if err != nil {
+			t.Fatalf("NewVerifierContext failed: %v", err)
+		}
+
+		for i := 0; i < 16; i++ {
+			digest := sha256.Sum256([]byte(fmt.Sprintf("message %d/%d", k, i)))
+			sig, err := SignDigest(digest, priv)
+			if err != nil {
+				t.Fatalf("SignDigest failed: %v", err)
+			}
+			if !v.Verify(digest, sig) {
+				t.Fatalf("Key %d, message %d: Expected a valid signature", k, i)
+			}
+
+			// Every single-bit flip must give the same answer as VerifyDigest
+			for bit := 0; bit < 512; bit += 37 {
+				tampered := sig
+				tampered[bit/8] ^= 1 << (bit % 8)
+				if v.Verify(digest, tampered) != VerifyDigest(digest, pub, tampered) {
+					t.Fatalf("Key %d, message %d, bit %d: verifiers disagree", k, i, bit)
+				}
+			}
+			other := digest
+			other[0] ^= 0x01
+			if v.Verify(other, sig) {
+				t.Errorf("Key %d, message %d: Expected rejection for another digest", k, i)
+			}
+		}
+	}
+}
+
+func TestVerifierContextRejects(t *testing.T) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{31: 0x07})
+	other, _ := btcec.PrivKeyFromBytes([]byte{31: 0x08})
+	v, _ := NewVerifierContext(XOnlyFromPub(priv.PubKey()))
+	msg := []byte("oracle attestation")
+	sig, _ := SignBIP340(msg, priv)
+	otherSig, _ := SignBIP340(msg, other)
+
+	if !v.VerifyBIP340(msg, sig) {
+		t.Fatal("Expected a valid signature")
+	}
+	var rAboveP, sAboveN [64]byte
+	copy(rAboveP[:], sig[:])
+	copy(sAboveN[:], sig[:])
+	for i := 0; i < 32; i++ {
+		rAboveP[i] = 0xff
+		sAboveN[32+i] = 0xff
+	}
+
+	tests := []struct {
+		name string
+		msg  []byte
+		sig  [64]byte
+	}{
+		{"other key", msg, otherSig},
+		{"empty message", nil, sig},
+		{"r above p", msg, rAboveP},
+		{"s above n", msg, sAboveN},
+		{"zero signature", msg, [64]byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if v.VerifyBIP340(tt.msg, tt.sig) {
+				t.Error("Expected the signature to be rejected")
+			}
+		})
+	}
+
+	if _, err := NewVerifierContext(XOnlyPublicKey{}); err == nil {
+		t.Error("Expected error for a key not on the curve")
+	}
+	var _ Verifier = v
+}
+
+func BenchmarkVerifierContext(b *testing.B) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{31: 0x07})
+	digest := sha256.Sum256([]byte("benchmark"))
+	sig, _ := SignDigest(digest, priv)
+	v, _ := NewVerifierContext(XOnlyFromPub(priv.PubKey()))
+
+	b.Run("VerifyDigest", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			VerifyDigest(digest, priv.PubKey(), sig)
+		}
+	})
+	b.Run("VerifierContext", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			v.Verify(digest, sig)
+		}
+	})
+	b.Run("NewVerifierContext", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = NewVerifierContext(XOnlyFromPub(priv.PubKey()))
+		}
+	})
+}
@@ -16,13 +16,16 @@ import (
 // The signature format is: [r (32 bytes)][s (32 bytes)] where r and s are field elements
 // The message is hashed to 32 bytes using SHA256 before signing (BIP340 requirement)
 //
+// Signatures are deterministic unless WithAuxRand supplies BIP340 auxiliary
+// randomness (see options.go).
+//
 // Example:
 //
 //	privateKey, _ := btcec.NewPrivateKey()
 //	message := []byte("Hello, Bitcoin!")
 //	signature, err := SignBIP340(message, privateKey)
 //	// Result: [64]byte{0x12, 0x34, 0x56, ...} (64-byte signature)
-func SignBIP340(msg []byte, priv *btcec.PrivateKey) ([64]byte, error) {
+func SignBIP340(msg []byte, priv *btcec.PrivateKey, opts ...SignOption) (Signature, error) {
 	// Step 1: Validate inputs
 	if len(msg) == 0 {
 		return [64]byte{}, errors.New("message cannot be empty")
@@ -35,18 +38,9 @@ func SignBIP340(msg []byte, priv *btcec.PrivateKey) ([64]byte, error) {
 	// BIP340 Schnorr signatures work on 32-byte message hashes
 	messageHash := sha256.Sum256(msg)
 
-	// Step 3: Create BIP340 Schnorr signature using the btcec library
-	// This handles deterministic nonce derivation and tagged hashing internally
-	sig, err := btcschnorr.Sign(priv, messageHash[:])
-	if err != nil {
-		return [64]byte{}, err
-	}
-
-	// Step 4: Serialize the signature to 64 bytes
-	// Format: [r (32 bytes)][s (32 bytes)]
-	var out [64]byte
-	copy(out[:], sig.Serialize())
-	return out, nil
+	// Step 3: Sign the hash as a BIP340 digest
+	// This handles nonce derivation and tagged hashing internally
+	return SignDigest(messageHash, priv, opts...)
 }
 
 // VerifyBIP340 verifies a BIP340 Schnorr signature over a message using a full public key
@@ -77,16 +71,9 @@ func VerifyBIP340(msg []byte, pub *btcec.PublicKey, sigBz [64]byte) bool {
 	// BIP340 Schnorr signatures work on 32-byte message hashes
 	messageHash := sha256.Sum256(msg)
 
-	// Step 3: Parse the 64-byte signature into a signature object
-	// This validates the signature format and extracts r and s components
-	sig, err := btcschnorr.ParseSignature(sigBz[:])
-	if err != nil {
-		return false
-	}
-
-	// Step 4: Verify the signature against the message hash and public key
+	// Step 3: Verify the signature against the message hash and public key
 	// This performs the Schnorr verification algorithm
-	return sig.Verify(messageHash[:], pub)
+	return VerifyDigest(messageHash, pub, sigBz)
 }
 
 // XOnlyFromPub extracts the x-only public key from a full Bitcoin public key
@@ -103,15 +90,15 @@ func VerifyBIP340(msg []byte, pub *btcec.PublicKey, sigBz [64]byte) bool {
 //
 //	publicKey := privateKey.PubKey()
 //	xOnly := XOnlyFromPub(publicKey)
-//	// Result: [32]byte{0x12, 0x34, 0x56, ...} (x-only part of public key)
-func XOnlyFromPub(pub *btcec.PublicKey) [32]byte {
+//	// Result: XOnlyPublicKey{0x12, 0x34, 0x56, ...} (x-only part of public key)
+func XOnlyFromPub(pub *btcec.PublicKey) XOnlyPublicKey {
 	// Step 1: Get the compressed public key (33 bytes)
 	// Format: [0x02 or 0x03][x (32 bytes)]
 	compressed := pub.SerializeCompressed()
 
 	// Step 2: Extract the x-coordinate (skip the first byte)
 	// The first byte is the prefix (0x02 for even y, 0x03 for odd y)
-	var out [32]byte
+	var out XOnlyPublicKey
 	copy(out[:], compressed[1:]) // Skip prefix byte, take next 32 bytes
 	return out
 }
@@ -128,7 +115,7 @@ func XOnlyFromPub(pub *btcec.PublicKey) [32]byte {
 //	signature := [64]byte{0x12, 0x34, 0x56, ...}
 //	isValid, err := VerifyWithXOnly(message, signature, xOnly)
 //	// Result: true if signature is valid
-func VerifyWithXOnly(msg []byte, sigBz [64]byte, xOnly [32]byte) (bool, error) {
+func VerifyWithXOnly(msg []byte, sigBz [64]byte, xOnly XOnlyPublicKey) (bool, error) {
 	// Step 1: Validate inputs
 	if len(msg) == 0 {
 		return false, errors.New("message cannot be empty")
@@ -152,10 +139,10 @@ func VerifyWithXOnly(msg []byte, sigBz [64]byte, xOnly [32]byte) (bool, error) {
 //
 // Example:
 //
-//	xOnly := [32]byte{0x12, 0x34, 0x56, ...}
+//	xOnly := XOnlyPublicKey{0x12, 0x34, 0x56, ...}
 //	publicKey, err := ParseXOnly(xOnly)
 //	// Result: Full public key with even Y coordinate
-func ParseXOnly(x32 [32]byte) (*btcec.PublicKey, error) {
+func ParseXOnly(x32 XOnlyPublicKey) (*btcec.PublicKey, error) {
 	// Parse the x-only key using BIP340 even-Y lift convention
 	pub, err := btcschnorr.ParsePubKey(x32[:])
 	if err != nil {
@@ -194,7 +181,7 @@ func ParseSignature(sigBz [64]byte) (*btcschnorr.Signature, error) {
 //	s := [32]byte{0x78, 0x9a, 0xbc, ...} // Second 32 bytes
 //	signature := JoinSig(r, s)
 //	// Result: [64]byte{r (32 bytes) + s (32 bytes)}
-func JoinSig(r, s [32]byte) [64]byte {
+func JoinSig(r, s [32]byte) Signature {
 	var out [64]byte
 	copy(out[:32], r[:]) // First 32 bytes: r component
 	copy(out[32:], s[:]) // Second 32 bytes: s component
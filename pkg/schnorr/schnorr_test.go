@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/fixtures"
 )
 
 // TestSchnorrSignAndVerify tests the complete Schnorr signature workflow
@@ -250,7 +251,7 @@ func TestTaprootWorkflow(t *testing.T) {
 
 	// Step 2: Extract x-only public key (BIP340 uses 32-byte x)
 	xonly := XOnlyFromPub(pub)
-	t.Logf("X-only public key: %x", xonly)
+	t.Logf("X-only public key: %s", xonly)
 
 	// Step 3: Sign arbitrary message bytes (BIP340 takes arbitrary-length msg)
 	msg := []byte("Taproot says hello")
@@ -329,7 +330,7 @@ func TestXOnlyPublicKey(t *testing.T) {
 	// Test x-only extraction
 	xOnly := XOnlyFromPub(publicKey)
 	t.Logf("Full public key (compressed): %x", publicKey.SerializeCompressed())
-	t.Logf("X-only public key: %x", xOnly)
+	t.Logf("X-only public key: %s", xOnly)
 
 	// Verify the x-only key is 32 bytes
 	if len(xOnly) != 32 {
@@ -372,3 +373,33 @@ func compareBytes(a, b []byte) bool {
 	}
 	return true
 }
+
+// TestSignBIP340Golden snapshots deterministic BIP340 signatures for fixed keys
+func TestSignBIP340Golden(t *testing.T) {
+	type entry struct {
+		PrivateKey string `json:"private_key"`
+		XOnly      string `json:"x_only"`
+		Message    string `json:"message"`
+		Signature  string `json:"signature"`
+	}
+
+	var entries []entry
+	for i, msg := range []string{"a", "hello", "cryptography-playground"} {
+		privBytes := make([]byte, 32)
+		privBytes[31] = byte(i + 1)
+		priv, pub := btcec.PrivKeyFromBytes(privBytes)
+
+		sig, err := SignBIP340([]byte(msg), priv)
+		if err != nil {
+			t.Fatalf("SignBIP340 failed: %v", err)
+		}
+		xOnly := XOnlyFromPub(pub)
+		entries = append(entries, entry{
+			PrivateKey: hex.EncodeToString(privBytes),
+			XOnly:      hex.EncodeToString(xOnly[:]),
+			Message:    msg,
+			Signature:  hex.EncodeToString(sig[:]),
+		})
+	}
+	fixtures.GoldenJSON(t, "bip340_signatures.golden.json", entries)
+}
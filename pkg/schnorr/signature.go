@@ -0,0 +1,89 @@
+package schnorr
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// Signatures
+//
+// Like XOnlyPublicKey, Signature is a plain byte array with methods: the
+// signing functions return it, and it converts implicitly wherever a [64]byte
+// is expected, so existing code keeps compiling. It prints and marshals as hex,
+// so a struct holding one encodes to readable JSON:
+//
+//	{"sig": "e907831f80848d10...3f6a4c0f"}
+
+// Signature is a 64-byte BIP340 signature: x(R) || s
+type Signature [64]byte
+
+// ParseSignatureBytes checks the length of a raw signature
+//
+// It does not check that r and s are in range; verification does.
+func ParseSignatureBytes(b []byte) (Signature, error) {
+	if len(b) != 64 {
+		return Signature{}, fmt.Errorf("signature must be 64 bytes, got %d", len(b))
+	}
+	return Signature(b), nil
+}
+
+// ParseSignatureHex decodes a hex signature
+//
+// Example:
+//
+//	sig, err := ParseSignatureHex("e907831f80848d10...")
+//	ok := key.Verify(digest, sig)
+func ParseSignatureHex(s string) (Signature, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return Signature{}, fmt.Errorf("invalid signature hex: %w", err)
+	}
+	return ParseSignatureBytes(b)
+}
+
+// R returns the x-coordinate of the nonce point
+func (s Signature) R() [32]byte {
+	return [32]byte(s[:32])
+}
+
+// S returns the scalar s
+func (s Signature) S() [32]byte {
+	return [32]byte(s[32:])
+}
+
+// Serialize returns the 64-byte encoding
+func (s Signature) Serialize() []byte {
+	return append([]byte(nil), s[:]...)
+}
+
+// String returns the signature in hex
+func (s Signature) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// Format prints the signature in hex for every verb, so %x and %v agree
+//
+// Without it, %x would hex-encode the hex string returned by String.
+func (s Signature) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'x', 'X':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), s[:])
+	default:
+		fmt.Fprintf(f, fmt.FormatString(f, verb), s.String())
+	}
+}
+
+// MarshalText encodes the signature as hex, so JSON holds a string rather than 64 numbers
+func (s Signature) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a hex signature
+func (s *Signature) UnmarshalText(text []byte) error {
+	parsed, err := ParseSignatureHex(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
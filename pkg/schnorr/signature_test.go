@@ -0,0 +1,82 @@
+package schnorr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+func TestSignature(t *testing.T) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{31: 0x03})
+	msg := []byte("signature type")
+	sig, err := SignBIP340(msg, priv)
+	if err != nil {
+		t.Fatalf("SignBIP340 failed: %v", err)
+	}
+
+	// Accessors split x(R) and s
+	if JoinSig(sig.R(), sig.S()) != sig {
+		t.Error("Expected R and S to join back into the signature")
+	}
+
+	// Hex round trip
+	parsed, err := ParseSignatureHex(sig.String())
+	if err != nil || parsed != sig {
+		t.Fatalf("ParseSignatureHex failed: %v", err)
+	}
+	for _, verb := range []string{"%x", "%s", "%v"} {
+		if got := fmt.Sprintf(verb, sig); got != sig.String() {
+			t.Errorf("%s: Expected %s, got %s", verb, sig, got)
+		}
+	}
+	if got := fmt.Sprintf("%X", sig); got != strings.ToUpper(sig.String()) {
+		t.Errorf("Expected upper-case hex, got %s", got)
+	}
+
+	// JSON embeds the signature as a hex string
+	type attestation struct {
+		Sig Signature `json:"sig"`
+	}
+	data, err := json.Marshal(attestation{Sig: sig})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"sig":"`+sig.String()+`"}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+	var decoded attestation
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Sig != sig {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	// Still usable where a [64]byte is expected
+	if !VerifyBIP340(msg, priv.PubKey(), sig) {
+		t.Error("Expected the signature to verify")
+	}
+}
+
+func TestParseSignatureErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"not hex", strings.Repeat("zz", 64)},
+		{"short", strings.Repeat("00", 63)},
+		{"long", strings.Repeat("00", 65)},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSignatureHex(tt.input); err == nil {
+				t.Error("Expected error")
+			}
+			var sig Signature
+			if err := sig.UnmarshalText([]byte(tt.input)); err == nil {
+				t.Error("Expected UnmarshalText error")
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package schnorr
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// Signers and verifiers
+//
+// Code that only needs signatures should not need the private key. A Signer
+// produces BIP340 signatures over 32-byte digests for one key; it may be a key
+// in memory (KeySigner), a hardware wallet, an HSM or a remote service. A
+// Verifier checks them; XOnlyPublicKey is one.
+//
+// SignMessage, SignDomainWith and SignDigestWith check every signature a
+// Signer returns against its public key before handing it on, so a faulty or
+// misconfigured device is caught at signing time rather than on chain.
+
+// ErrSignerMismatch is returned when a Signer's signature does not verify under its public key
+var ErrSignerMismatch = errors.New("signer returned a signature that does not verify")
+
+// Signer signs 32-byte digests with one BIP340 key
+type Signer interface {
+	// Sign returns a BIP340 signature over digest
+	Sign(digest [32]byte) ([64]byte, error)
+	// PublicKey returns the x-only key the signatures verify under
+	PublicKey() XOnlyPublicKey
+}
+
+// Verifier checks BIP340 signatures over 32-byte digests
+type Verifier interface {
+	Verify(digest [32]byte, sig [64]byte) bool
+}
+
+// KeySigner is a Signer holding a private key in memory
+type KeySigner struct {
+	priv *btcec.PrivateKey
+	opts []SignOption
+}
+
+// NewKeySigner wraps a private key as a Signer; opts apply to every signature
+//
+// Example:
+//
+//	signer := NewKeySigner(priv, WithNonceProvider(RandomNonces))
+//	sig, err := SignMessage(msg, signer)
+func NewKeySigner(priv *btcec.PrivateKey, opts ...SignOption) *KeySigner {
+	return &KeySigner{priv: priv, opts: opts}
+}
+
+// Sign implements Signer
+func (k *KeySigner) Sign(digest [32]byte) ([64]byte, error) {
+	return SignDigest(digest, k.priv, k.opts...)
+}
+
+// PublicKey implements Signer
+func (k *KeySigner) PublicKey() XOnlyPublicKey {
+	if k.priv == nil {
+		return XOnlyPublicKey{}
+	}
+	return XOnlyFromPub(k.priv.PubKey())
+}
+
+// Verify implements Verifier: it checks sig over digest under the key
+func (x XOnlyPublicKey) Verify(digest [32]byte, sig [64]byte) bool {
+	pub, err := x.PublicKey()
+	if err != nil {
+		return false
+	}
+	return VerifyDigest(digest, pub, sig)
+}
+
+// SignDigestWith signs a digest with a Signer and checks the result
+//
+// Example:
+//
+//	sig, err := SignDigestWith(sighash, hardwareWallet)
+func SignDigestWith(digest [32]byte, signer Signer) (Signature, error) {
+	if signer == nil {
+		return [64]byte{}, errors.New("signer cannot be nil")
+	}
+	sig, err := signer.Sign(digest)
+	if err != nil {
+		return [64]byte{}, fmt.Errorf("signer: %w", err)
+	}
+	if !signer.PublicKey().Verify(digest, sig) {
+		return [64]byte{}, ErrSignerMismatch
+	}
+	return sig, nil
+}
+
+// SignMessage is SignBIP340 with a Signer: it signs SHA256(msg)
+//
+// Example:
+//
+//	sig, err := SignMessage([]byte("Hello, Bitcoin!"), signer)
+//	// VerifyBIP340(msg, pub, sig) == true
+func SignMessage(msg []byte, signer Signer) (Signature, error) {
+	if len(msg) == 0 {
+		return [64]byte{}, errors.New("message cannot be empty")
+	}
+	return SignDigestWith(sha256.Sum256(msg), signer)
+}
+
+// SignDomainWith is SignDomain with a Signer
+//
+// Example:
+//
+//	sig, err := SignDomainWith("example.com/auth/v1", challenge, signer)
+func SignDomainWith(domain string, msg []byte, signer Signer) (Signature, error) {
+	if domain == "" {
+		return [64]byte{}, ErrEmptyDomain
+	}
+	if len(msg) == 0 {
+		return [64]byte{}, errors.New("message cannot be empty")
+	}
+	return SignDigestWith(TaggedHash(domain, msg), signer)
+}
@@ -0,0 +1,97 @@
+package schnorr
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// XOnlyPublicKey is the Verifier of the package
+var _ Verifier = XOnlyPublicKey{}
+
+// faultySigner signs with one key but claims another
+type faultySigner struct {
+	KeySigner
+	claimed XOnlyPublicKey
+	err     error
+}
+
+func (f *faultySigner) Sign(digest [32]byte) ([64]byte, error) {
+	if f.err != nil {
+		return [64]byte{}, f.err
+	}
+	return f.KeySigner.Sign(digest)
+}
+
+func (f *faultySigner) PublicKey() XOnlyPublicKey { return f.claimed }
+
+func TestKeySigner(t *testing.T) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{31: 0x05})
+	signer := NewKeySigner(priv)
+	msg := []byte("remote signer")
+
+	// Same signatures as the private key functions
+	sig, err := SignMessage(msg, signer)
+	if err != nil {
+		t.Fatalf("SignMessage failed: %v", err)
+	}
+	want, _ := SignBIP340(msg, priv)
+	if sig != want {
+		t.Error("Expected SignMessage to match SignBIP340")
+	}
+	domainSig, err := SignDomainWith("example.com/auth/v1", msg, signer)
+	if err != nil {
+		t.Fatalf("SignDomainWith failed: %v", err)
+	}
+	if want, _ := SignDomain("example.com/auth/v1", msg, priv); domainSig != want {
+		t.Error("Expected SignDomainWith to match SignDomain")
+	}
+
+	// The public key verifies them
+	if signer.PublicKey() != XOnlyFromPub(priv.PubKey()) {
+		t.Error("Expected the signer's x-only key")
+	}
+	if !signer.PublicKey().Verify(sha256.Sum256(msg), sig) {
+		t.Error("Expected XOnlyPublicKey.Verify to accept the signature")
+	}
+	if signer.PublicKey().Verify(sha256.Sum256([]byte("other")), sig) {
+		t.Error("Expected XOnlyPublicKey.Verify to reject another message")
+	}
+
+	// Options are applied to every signature
+	randomized := NewKeySigner(priv, WithNonceProvider(RandomNonces))
+	a, _ := SignMessage(msg, randomized)
+	b, _ := SignMessage(msg, randomized)
+	if a == b {
+		t.Error("Expected random nonces to give different signatures")
+	}
+}
+
+func TestSignerFailures(t *testing.T) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{31: 0x05})
+	other, _ := btcec.PrivKeyFromBytes([]byte{31: 0x06})
+	msg := []byte("remote signer")
+
+	wrongKey := &faultySigner{KeySigner: *NewKeySigner(priv), claimed: XOnlyFromPub(other.PubKey())}
+	if _, err := SignMessage(msg, wrongKey); !errors.Is(err, ErrSignerMismatch) {
+		t.Errorf("Expected ErrSignerMismatch, got %v", err)
+	}
+	unplugged := &faultySigner{claimed: XOnlyFromPub(priv.PubKey()), err: errors.New("device unplugged")}
+	if _, err := SignMessage(msg, unplugged); err == nil || errors.Is(err, ErrSignerMismatch) {
+		t.Errorf("Expected the device error, got %v", err)
+	}
+	if _, err := SignMessage(msg, nil); err == nil {
+		t.Error("Expected error for a nil signer")
+	}
+	if _, err := SignMessage(nil, NewKeySigner(priv)); err == nil {
+		t.Error("Expected error for an empty message")
+	}
+	if _, err := SignDomainWith("", msg, NewKeySigner(priv)); !errors.Is(err, ErrEmptyDomain) {
+		t.Errorf("Expected ErrEmptyDomain, got %v", err)
+	}
+	if _, err := SignMessage(msg, NewKeySigner(nil)); err == nil {
+		t.Error("Expected error for a signer without a key")
+	}
+}
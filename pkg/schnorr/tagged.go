@@ -0,0 +1,27 @@
+package schnorr
+
+import "github.com/neverDefined/cryptography-playground/pkg/hash"
+
+// Tagged hashes
+//
+// BIP340 separates the hashes used in different places (nonce derivation,
+// challenges, taproot tweaks, MuSig2 key aggregation, ...) by prefixing the data
+// with the hash of a tag, twice:
+//
+//	TaggedHash(tag, data) = SHA256(SHA256(tag) || SHA256(tag) || data)
+//
+// The prefix is exactly one SHA256 block, so a hash of one tag can never be
+// confused with a hash of another tag or with a plain SHA256.
+
+// TaggedHash computes the BIP340 tagged hash of the concatenation of data
+//
+// It is hash.TaggedSHA256, kept here because most tagged hashes are computed
+// next to signatures.
+//
+// Example:
+//
+//	tweak := TaggedHash("TapTweak", xOnlyInternalKey)
+//	e := TaggedHash("BIP0340/challenge", r[:], xOnlyPub, msg[:])
+func TaggedHash(tag string, data ...[]byte) [32]byte {
+	return hash.TaggedSHA256(tag, data...)
+}
@@ -0,0 +1,46 @@
+package schnorr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+// TestTaggedHash tests TaggedHash against known BIP322 message hashes and the definition
+func TestTaggedHash(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      string
+		data     [][]byte
+		expected string
+	}{
+		// BIP322 test vectors
+		{"bip322 empty", "BIP0322-signed-message", nil, "c90c269c4f8fcbe6880f72a721ddfbf1914268a794cbb21cfafee13770ae19f1"},
+		{"bip322 hello", "BIP0322-signed-message", [][]byte{[]byte("Hello World")}, "f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a"},
+		{"bip322 split", "BIP0322-signed-message", [][]byte{[]byte("Hello"), nil, []byte(" World")}, "f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TaggedHash(tt.tag, tt.data...)
+			if hex.EncodeToString(got[:]) != tt.expected {
+				t.Errorf("Expected %s, got %x", tt.expected, got)
+			}
+		})
+	}
+
+	// Every tag, cached or not, must follow SHA256(SHA256(tag) || SHA256(tag) || data)
+	for _, tag := range []string{"TapTweak", "TapTweak", "BIP0340/challenge", ""} {
+		tagHash := sha256.Sum256([]byte(tag))
+		expected := sha256.Sum256(append(append(tagHash[:], tagHash[:]...), "data"...))
+		if got := TaggedHash(tag, []byte("data")); got != expected {
+			t.Errorf("Expected %x for tag %q, got %x", expected, tag, got)
+		}
+	}
+}
+
+func BenchmarkTaggedHash(b *testing.B) {
+	data := make([]byte, 32)
+	for i := 0; i < b.N; i++ {
+		TaggedHash("TapTweak", data)
+	}
+}
@@ -0,0 +1,127 @@
+package schnorr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+)
+
+// Taproot key tweaking (BIP341)
+//
+// A taproot output does not pay to the internal key P but to the output key
+//
+//	Q = P + t·G    with t = TaggedHash("TapTweak", x(P) || merkleRoot)
+//
+// where P is the even-Y lift of x(P) and merkleRoot is omitted for outputs
+// without a script tree (BIP86). The holder of d (P = d·G) spends through the
+// key path by signing for Q with d' = d + t (d negated first if d·G has odd Y).
+
+// TweakPubKey computes the taproot output key for an internal key
+//
+// merkleRoot is the 32-byte script tree root, or nil for a key-path-only output.
+// oddY reports the parity of Q, needed in script path control blocks.
+//
+// Example:
+//
+//	outputKey, _, err := TweakPubKey(internalKey, nil)
+//	address, _ := bech32.SegWitAddressEncode("bc", 1, outputKey[:])
+func TweakPubKey(internalKey *btcec.PublicKey, merkleRoot []byte) (outputKey XOnlyPublicKey, oddY bool, err error) {
+	// Step 1: Validate inputs
+	if internalKey == nil {
+		return XOnlyPublicKey{}, false, errors.New("internal key cannot be nil")
+	}
+	xOnly := XOnlyFromPub(internalKey)
+	tweak, err := tapTweak(xOnly, merkleRoot)
+	if err != nil {
+		return XOnlyPublicKey{}, false, err
+	}
+
+	// Step 2: P is the even-Y member of ±internalKey
+	p := arithmetic.PointFromPubKey(internalKey)
+	if !arithmetic.HasEvenY(p) {
+		p = arithmetic.Negate(p)
+	}
+	evenKey, err := p.PubKey()
+	if err != nil {
+		return XOnlyPublicKey{}, false, err
+	}
+
+	// Step 3: Q = P + t·G
+	var pj, tG, q btcec.JacobianPoint
+	evenKey.AsJacobian(&pj)
+	btcec.ScalarBaseMultNonConst(&tweak, &tG)
+	btcec.AddNonConst(&pj, &tG, &q)
+	if q.Z.IsZero() {
+		return XOnlyPublicKey{}, false, errors.New("tweaked key is the point at infinity")
+	}
+	q.ToAffine()
+	q.X.PutBytes((*[32]byte)(&outputKey))
+	return outputKey, q.Y.IsOdd(), nil
+}
+
+// TweakPrivKey computes the private key of the taproot output key
+//
+// The result's x-only public key equals the output key from TweakPubKey.
+//
+// Example:
+//
+//	tweaked, err := TweakPrivKey(priv, nil)
+//	// XOnlyFromPub(tweaked.PubKey()) == outputKey
+func TweakPrivKey(priv *btcec.PrivateKey, merkleRoot []byte) (*btcec.PrivateKey, error) {
+	// Step 1: Validate inputs
+	if priv == nil {
+		return nil, errors.New("private key cannot be nil")
+	}
+	pub := priv.PubKey()
+	tweak, err := tapTweak(XOnlyFromPub(pub), merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: d' = ±d + t, with d negated if d·G has odd Y
+	d := priv.Key
+	if !arithmetic.HasEvenY(arithmetic.PointFromPubKey(pub)) {
+		d.Negate()
+	}
+	d.Add(&tweak)
+	if d.IsZero() {
+		return nil, errors.New("tweaked private key is zero")
+	}
+	return btcec.PrivKeyFromScalar(&d), nil
+}
+
+// SignWithTaprootTweak produces a key path signature for the taproot output of priv
+//
+// The message is hashed with SHA256 like SignBIP340; the signature verifies
+// against the output key returned by TweakPubKey(priv.PubKey(), merkleRoot).
+//
+// Example:
+//
+//	sig, err := SignWithTaprootTweak(msg, priv, nil)
+//	outputKey, _, _ := TweakPubKey(priv.PubKey(), nil)
+//	ok, _ := VerifyWithXOnly(msg, sig, outputKey)
+//	// Result: true
+func SignWithTaprootTweak(msg []byte, priv *btcec.PrivateKey, merkleRoot []byte, opts ...SignOption) (Signature, error) {
+	tweaked, err := TweakPrivKey(priv, merkleRoot)
+	if err != nil {
+		return [64]byte{}, err
+	}
+	defer tweaked.Zero()
+	return SignBIP340(msg, tweaked, opts...)
+}
+
+// tapTweak computes t = TaggedHash("TapTweak", x(P) || merkleRoot) as a scalar
+func tapTweak(xOnly [32]byte, merkleRoot []byte) (btcec.ModNScalar, error) {
+	var tweak btcec.ModNScalar
+	if merkleRoot != nil && len(merkleRoot) != 32 {
+		return tweak, fmt.Errorf("merkle root must be 32 bytes, got %d", len(merkleRoot))
+	}
+	h := hash.TapTweakHash(xOnly, merkleRoot)
+	if overflow := tweak.SetBytes(&h); overflow != 0 {
+		return tweak, errors.New("tweak exceeds curve order")
+	}
+	return tweak, nil
+}
@@ -0,0 +1,120 @@
+package schnorr
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// TestTweakPubKey tests TweakPubKey against the BIP341 wallet test vectors
+func TestTweakPubKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		internalKey string
+		merkleRoot  string // Empty for no script tree
+		expected    string
+	}{
+		{
+			"key path only",
+			"d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+			"",
+			"53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+		},
+		{
+			"single leaf",
+			"187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+			"5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+			"147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, _ := hex.DecodeString(tt.internalKey)
+			internalKey, err := ParseXOnly([32]byte(x))
+			if err != nil {
+				t.Fatalf("ParseXOnly failed: %v", err)
+			}
+			var merkleRoot []byte
+			if tt.merkleRoot != "" {
+				merkleRoot, _ = hex.DecodeString(tt.merkleRoot)
+			}
+			outputKey, _, err := TweakPubKey(internalKey, merkleRoot)
+			if err != nil {
+				t.Fatalf("TweakPubKey failed: %v", err)
+			}
+			if hex.EncodeToString(outputKey[:]) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, outputKey)
+			}
+		})
+	}
+
+	if _, _, err := TweakPubKey(nil, nil); err == nil {
+		t.Error("Expected error for nil internal key")
+	}
+	priv, _ := btcec.NewPrivateKey()
+	if _, _, err := TweakPubKey(priv.PubKey(), make([]byte, 31)); err == nil {
+		t.Error("Expected error for a 31-byte merkle root")
+	}
+}
+
+// TestTweakPrivKey tests the BIP341 key path vector and agreement with TweakPubKey
+func TestTweakPrivKey(t *testing.T) {
+	internal, _ := hex.DecodeString("6b973d88838f27366ed61c9ad6367663045cb456e28335c109e30717ae0c6baa")
+	priv, _ := btcec.PrivKeyFromBytes(internal)
+	tweaked, err := TweakPrivKey(priv, nil)
+	if err != nil {
+		t.Fatalf("TweakPrivKey failed: %v", err)
+	}
+	expected := "2405b971772ad26915c8dcdf10f238753a9b837e5f8e6a86fd7c0cce5b7296d9"
+	if got := hex.EncodeToString(tweaked.Serialize()); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+
+	// Keys with either parity, with and without a script tree
+	root := TaggedHash("TapLeaf", []byte("script"))
+	for i := 0; i < 8; i++ {
+		priv, _ := btcec.NewPrivateKey()
+		for _, merkleRoot := range [][]byte{nil, root[:]} {
+			tweaked, err := TweakPrivKey(priv, merkleRoot)
+			if err != nil {
+				t.Fatalf("TweakPrivKey failed: %v", err)
+			}
+			outputKey, oddY, err := TweakPubKey(priv.PubKey(), merkleRoot)
+			if err != nil {
+				t.Fatalf("TweakPubKey failed: %v", err)
+			}
+			if XOnlyFromPub(tweaked.PubKey()) != outputKey {
+				t.Error("Expected the tweaked private key to match the output key")
+			}
+			if oddY != (tweaked.PubKey().SerializeCompressed()[0] == 0x03) {
+				t.Error("Expected oddY to match the tweaked key's parity")
+			}
+		}
+	}
+}
+
+// TestSignWithTaprootTweak tests that key path signatures verify only against the output key
+func TestSignWithTaprootTweak(t *testing.T) {
+	priv, _ := btcec.NewPrivateKey()
+	msg := []byte("spend through the key path")
+	root := TaggedHash("TapLeaf", []byte("script"))
+
+	for _, merkleRoot := range [][]byte{nil, root[:]} {
+		sig, err := SignWithTaprootTweak(msg, priv, merkleRoot)
+		if err != nil {
+			t.Fatalf("SignWithTaprootTweak failed: %v", err)
+		}
+		outputKey, _, _ := TweakPubKey(priv.PubKey(), merkleRoot)
+		if ok, err := VerifyWithXOnly(msg, sig, outputKey); err != nil || !ok {
+			t.Errorf("Expected signature to verify against the output key, got %v (%v)", ok, err)
+		}
+		if VerifyBIP340(msg, priv.PubKey(), sig) {
+			t.Error("Expected signature not to verify against the internal key")
+		}
+	}
+
+	if _, err := SignWithTaprootTweak(msg, nil, nil); err == nil {
+		t.Error("Expected error for nil private key")
+	}
+}
@@ -0,0 +1,450 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// Verifiable encryption of signatures
+//
+// An EncryptedSignature carries a BIP340 signature (R, s) whose s is encrypted
+// to a recipient key Y, together with a zero-knowledge proof that it decrypts
+// to a valid signature over a given message and key. The recipient (e.g. the
+// arbiter of a fair exchange) can decrypt it; everyone else can only check
+// that it would verify. R is public and s·G = R + e·P is computable by
+// anyone, so it is enough to prove that the ciphertext encrypts the discrete
+// log of S = s·G.
+//
+// Each of the 256 bits s_i of s is encrypted with ElGamal in the exponent:
+//
+//	A_i = r_i·G,  B_i = s_i·G + r_i·Y
+//
+// with a Chaum–Pedersen OR proof that s_i is 0 or 1. The homomorphic sums
+// A = Σ 2^i·A_i and B = Σ 2^i·B_i then encrypt s itself with r = Σ 2^i·r_i,
+// and a single Chaum–Pedersen proof shows A = r·G and B − S = r·Y. Decrypting
+// a bit is a comparison of B_i − y·A_i against the identity and G, so no
+// discrete logs have to be searched.
+//
+// The ciphertext is large (256 bit encryptions, about 50 KB) and verification
+// costs around a thousand scalar multiplications: this is a readable
+// construction, not a compact one.
+
+// encTag is the tagged hash domain of the proofs
+const encTag = "cryptography-playground/verifiable-encryption/v1"
+
+// encBits is the number of encrypted bits of s
+const encBits = 256
+
+// ErrInvalidEncryption is returned when an encrypted signature fails to decrypt to a valid signature
+var ErrInvalidEncryption = errors.New("encrypted signature is invalid")
+
+// EncryptedBit is the encryption of one bit of s with its 0-or-1 proof
+type EncryptedBit struct {
+	A  [33]byte // r·G
+	B  [33]byte // bit·G + r·Y
+	C0 [32]byte // Challenge of the "bit is 0" branch
+	C1 [32]byte // Challenge of the "bit is 1" branch
+	Z0 [32]byte // Response of the "bit is 0" branch
+	Z1 [32]byte // Response of the "bit is 1" branch
+}
+
+// EncryptedSignature is a BIP340 signature encrypted to a recipient key
+type EncryptedSignature struct {
+	R    [32]byte              // x(R), the public half of the signature
+	Bits [encBits]EncryptedBit // Bit i of s, least significant first
+	C    [32]byte              // Challenge of the proof linking the bits to s·G
+	Z    [32]byte              // Response of the proof linking the bits to s·G
+}
+
+// EncryptSignature encrypts a signature over msg by pub to the recipient key
+//
+// The signature is checked first, so a valid EncryptedSignature always
+// decrypts to a signature that verifies.
+//
+// Example:
+//
+//	sig, _ := SignBIP340(msg, alicePriv)
+//	enc, err := EncryptSignature(msg, alicePriv.PubKey(), sig, arbiterPub)
+//	// Bob checks VerifyEncryptedSignature(msg, alicePub, arbiterPub, enc)
+func EncryptSignature(msg []byte, pub *btcec.PublicKey, sig [64]byte, recipient *btcec.PublicKey) (*EncryptedSignature, error) {
+	// Step 1: Validate inputs
+	if pub == nil || recipient == nil {
+		return nil, errors.New("public key and recipient cannot be nil")
+	}
+	if !VerifyBIP340(msg, pub, sig) {
+		return nil, errors.New("signature does not verify")
+	}
+	sBytes := [32]byte(sig[32:])
+	enc := &EncryptedSignature{R: [32]byte(sig[:32])}
+	transcript := enc.transcript(msg, pub, recipient)
+
+	var Y btcec.JacobianPoint
+	recipient.AsJacobian(&Y)
+
+	// Step 2: Encrypt every bit with its OR proof, accumulating r = Σ 2^i·r_i
+	var r, weight btcec.ModNScalar
+	weight.SetInt(1)
+	for i := 0; i < encBits; i++ {
+		bit := sBytes[31-i/8] >> (i % 8) & 1
+		ri, err := encryptBit(&enc.Bits[i], bit, &Y, transcript, i)
+		if err != nil {
+			return nil, err
+		}
+		var term btcec.ModNScalar
+		term.Mul2(&ri, &weight)
+		r.Add(&term)
+		ri.Zero()
+		weight.Add(&weight)
+	}
+	clear(sBytes[:])
+
+	// Step 3: Prove A = r·G and B − S = r·Y
+	k, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	var T1, T2 btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&k, &T1)
+	btcec.ScalarMultNonConst(&k, &Y, &T2)
+	c := linkChallenge(transcript, &T1, &T2)
+	var z btcec.ModNScalar
+	z.Mul2(&c, &r).Add(&k)
+	k.Zero()
+	r.Zero()
+	enc.C, enc.Z = c.Bytes(), z.Bytes()
+	return enc, nil
+}
+
+// VerifyEncryptedSignature checks that enc decrypts, with the recipient's key, to a valid signature over msg by pub
+//
+// Example:
+//
+//	if !VerifyEncryptedSignature(msg, alicePub, arbiterPub, enc) {
+//		// do not go ahead with the exchange
+//	}
+func VerifyEncryptedSignature(msg []byte, pub, recipient *btcec.PublicKey, enc *EncryptedSignature) bool {
+	// Step 1: Validate inputs and compute S = R + e·P
+	if pub == nil || recipient == nil || enc == nil {
+		return false
+	}
+	S, ok := sigPoint(msg, pub, enc.R)
+	if !ok {
+		return false
+	}
+	transcript := enc.transcript(msg, pub, recipient)
+	var Y btcec.JacobianPoint
+	recipient.AsJacobian(&Y)
+
+	// Step 2: Check every bit proof
+	var As, Bs [encBits]*btcec.JacobianPoint
+	for i := range enc.Bits {
+		var ok bool
+		if As[i], Bs[i], ok = verifyBit(&enc.Bits[i], &Y, transcript, i); !ok {
+			return false
+		}
+	}
+
+	// Step 3: A = Σ 2^i·A_i and B = Σ 2^i·B_i (Horner, most significant bit first)
+	sumA, sumB := weightedSum(As[:]), weightedSum(Bs[:])
+
+	// Step 4: Check the link z·G == T1 + c·A and z·Y == T2 + c·(B − S)
+	var c, z btcec.ModNScalar
+	if c.SetBytes(&enc.C) != 0 || z.SetBytes(&enc.Z) != 0 {
+		return false
+	}
+	negate(S)
+	var BminusS btcec.JacobianPoint
+	btcec.AddNonConst(sumB, S, &BminusS)
+	T1 := recompute(&z, nil, &c, sumA)
+	T2 := recompute(&z, &Y, &c, &BminusS)
+	got := linkChallenge(transcript, T1, T2)
+	return got.Equals(&c)
+}
+
+// DecryptSignature recovers the signature with the recipient's private key
+//
+// The encrypted signature should have been checked with
+// VerifyEncryptedSignature; the result is verified again before it is returned.
+//
+// Example:
+//
+//	sig, err := DecryptSignature(msg, alicePub, enc, arbiterPriv)
+func DecryptSignature(msg []byte, pub *btcec.PublicKey, enc *EncryptedSignature, recipient *btcec.PrivateKey) (Signature, error) {
+	// Step 1: Validate inputs
+	if pub == nil || enc == nil || recipient == nil {
+		return [64]byte{}, errors.New("public key, encrypted signature and recipient cannot be nil")
+	}
+	var G btcec.JacobianPoint
+	var one btcec.ModNScalar
+	one.SetInt(1)
+	btcec.ScalarBaseMultNonConst(&one, &G)
+	G.ToAffine()
+
+	// Step 2: Decrypt every bit: B_i − y·A_i is the identity (0) or G (1)
+	var s [32]byte
+	for i := range enc.Bits {
+		A, errA := parsePoint(enc.Bits[i].A)
+		B, errB := parsePoint(enc.Bits[i].B)
+		if errA != nil || errB != nil {
+			return [64]byte{}, fmt.Errorf("%w: bit %d", ErrInvalidEncryption, i)
+		}
+		var yA, D btcec.JacobianPoint
+		btcec.ScalarMultNonConst(&recipient.Key, A, &yA)
+		negate(&yA)
+		btcec.AddNonConst(B, &yA, &D)
+		switch {
+		case isInfinity(&D):
+		case equalAffine(&D, &G):
+			s[31-i/8] |= 1 << (i % 8)
+		default:
+			return [64]byte{}, fmt.Errorf("%w: bit %d is neither 0 nor 1", ErrInvalidEncryption, i)
+		}
+	}
+
+	// Step 3: Reduce s mod n and check the signature
+	var sc btcec.ModNScalar
+	sc.SetBytes(&s)
+	var sig [64]byte
+	copy(sig[:32], enc.R[:])
+	sBytes := sc.Bytes()
+	copy(sig[32:], sBytes[:])
+	if !VerifyBIP340(msg, pub, sig) {
+		return [64]byte{}, fmt.Errorf("%w: decrypted signature does not verify", ErrInvalidEncryption)
+	}
+	return sig, nil
+}
+
+// transcript binds the proofs to the message, keys and R
+func (enc *EncryptedSignature) transcript(msg []byte, pub, recipient *btcec.PublicKey) [32]byte {
+	m := sha256.Sum256(msg)
+	xP := XOnlyFromPub(pub)
+	return TaggedHash(encTag, m[:], xP[:], recipient.SerializeCompressed(), enc.R[:])
+}
+
+// encryptBit fills out with the encryption of bit and its OR proof, returning r
+func encryptBit(out *EncryptedBit, bit byte, Y *btcec.JacobianPoint, transcript [32]byte, index int) (btcec.ModNScalar, error) {
+	// Step 1: A = r·G, B = bit·G + r·Y; retry in the (negligible) case B is the identity
+	var r btcec.ModNScalar
+	var A, B btcec.JacobianPoint
+	for {
+		var err error
+		if r, err = randomScalar(); err != nil {
+			return r, err
+		}
+		btcec.ScalarBaseMultNonConst(&r, &A)
+		btcec.ScalarMultNonConst(&r, Y, &B)
+		if bit == 1 {
+			var G btcec.JacobianPoint
+			var one btcec.ModNScalar
+			one.SetInt(1)
+			btcec.ScalarBaseMultNonConst(&one, &G)
+			btcec.AddNonConst(&B, &G, &B)
+		}
+		if !isInfinity(&B) {
+			break
+		}
+	}
+	out.A, out.B = compress(&A), compress(&B)
+
+	// Step 2: Simulate the false branch with random challenge and response
+	fake := 1 - bit
+	cFake, err := randomScalar()
+	if err != nil {
+		return r, err
+	}
+	zFake, err := randomScalar()
+	if err != nil {
+		return r, err
+	}
+	target := bitTarget(&B, fake)
+	fakeT1 := recompute(&zFake, nil, &cFake, &A)
+	fakeT2 := recompute(&zFake, Y, &cFake, target)
+
+	// Step 3: Commit honestly on the true branch
+	k, err := randomScalar()
+	if err != nil {
+		return r, err
+	}
+	var T1, T2 btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&k, &T1)
+	btcec.ScalarMultNonConst(&k, Y, &T2)
+
+	// Step 4: c = H(...), c_true = c − c_fake, z_true = k + c_true·r
+	var c btcec.ModNScalar
+	if bit == 0 {
+		c = bitChallenge(transcript, index, out, &T1, &T2, fakeT1, fakeT2)
+	} else {
+		c = bitChallenge(transcript, index, out, fakeT1, fakeT2, &T1, &T2)
+	}
+	var negFake, cTrue, zTrue btcec.ModNScalar
+	negFake.NegateVal(&cFake)
+	cTrue.Add2(&c, &negFake)
+	zTrue.Mul2(&cTrue, &r).Add(&k)
+	k.Zero()
+	if bit == 0 {
+		out.C0, out.Z0, out.C1, out.Z1 = cTrue.Bytes(), zTrue.Bytes(), cFake.Bytes(), zFake.Bytes()
+	} else {
+		out.C0, out.Z0, out.C1, out.Z1 = cFake.Bytes(), zFake.Bytes(), cTrue.Bytes(), zTrue.Bytes()
+	}
+	return r, nil
+}
+
+// verifyBit checks the OR proof of one bit and returns its A and B
+func verifyBit(bit *EncryptedBit, Y *btcec.JacobianPoint, transcript [32]byte, index int) (*btcec.JacobianPoint, *btcec.JacobianPoint, bool) {
+	A, errA := parsePoint(bit.A)
+	B, errB := parsePoint(bit.B)
+	if errA != nil || errB != nil {
+		return nil, nil, false
+	}
+	var c0, c1, z0, z1 btcec.ModNScalar
+	if c0.SetBytes(&bit.C0) != 0 || c1.SetBytes(&bit.C1) != 0 || z0.SetBytes(&bit.Z0) != 0 || z1.SetBytes(&bit.Z1) != 0 {
+		return nil, nil, false
+	}
+
+	// T1_b = z_b·G − c_b·A, T2_b = z_b·Y − c_b·(B − b·G); c0 + c1 must be the hash
+	T01 := recompute(&z0, nil, &c0, A)
+	T02 := recompute(&z0, Y, &c0, bitTarget(B, 0))
+	T11 := recompute(&z1, nil, &c1, A)
+	T12 := recompute(&z1, Y, &c1, bitTarget(B, 1))
+	c := bitChallenge(transcript, index, bit, T01, T02, T11, T12)
+	var sum btcec.ModNScalar
+	sum.Add2(&c0, &c1)
+	return A, B, sum.Equals(&c)
+}
+
+// bitTarget returns B − b·G, which is r·Y if B encrypts b
+func bitTarget(B *btcec.JacobianPoint, b byte) *btcec.JacobianPoint {
+	if b == 0 {
+		return B
+	}
+	var G, out btcec.JacobianPoint
+	var minusOne btcec.ModNScalar
+	minusOne.SetInt(1).Negate()
+	btcec.ScalarBaseMultNonConst(&minusOne, &G)
+	btcec.AddNonConst(B, &G, &out)
+	return &out
+}
+
+// recompute returns z·base − c·X, with base = G when base is nil
+func recompute(z *btcec.ModNScalar, base *btcec.JacobianPoint, c *btcec.ModNScalar, X *btcec.JacobianPoint) *btcec.JacobianPoint {
+	var zB, cX, out btcec.JacobianPoint
+	if base == nil {
+		btcec.ScalarBaseMultNonConst(z, &zB)
+	} else {
+		btcec.ScalarMultNonConst(z, base, &zB)
+	}
+	var negC btcec.ModNScalar
+	negC.NegateVal(c)
+	btcec.ScalarMultNonConst(&negC, X, &cX)
+	btcec.AddNonConst(&zB, &cX, &out)
+	return &out
+}
+
+// bitChallenge hashes the commitments of both branches of a bit proof
+func bitChallenge(transcript [32]byte, index int, bit *EncryptedBit, T01, T02, T11, T12 *btcec.JacobianPoint) btcec.ModNScalar {
+	var idx [2]byte
+	binary.BigEndian.PutUint16(idx[:], uint16(index))
+	t01, t02, t11, t12 := compressOrZero(T01), compressOrZero(T02), compressOrZero(T11), compressOrZero(T12)
+	h := TaggedHash(encTag+"/bit", transcript[:], idx[:], bit.A[:], bit.B[:], t01[:], t02[:], t11[:], t12[:])
+	var c btcec.ModNScalar
+	c.SetBytes(&h)
+	return c
+}
+
+// linkChallenge hashes the commitments of the proof linking the bits to s·G
+func linkChallenge(transcript [32]byte, T1, T2 *btcec.JacobianPoint) btcec.ModNScalar {
+	t1, t2 := compressOrZero(T1), compressOrZero(T2)
+	h := TaggedHash(encTag+"/link", transcript[:], t1[:], t2[:])
+	var c btcec.ModNScalar
+	c.SetBytes(&h)
+	return c
+}
+
+// sigPoint computes S = s·G = R + e·P for a signature with x(R) = rx
+func sigPoint(msg []byte, pub *btcec.PublicKey, rx [32]byte) (*btcec.JacobianPoint, bool) {
+	R, err := XOnlyPublicKey(rx).PublicKey()
+	if err != nil {
+		return nil, false
+	}
+	xP := XOnlyFromPub(pub)
+	P, err := xP.PublicKey()
+	if err != nil {
+		return nil, false
+	}
+	e := challenge(rx, xP, sha256.Sum256(msg))
+	var rj, pj, eP, S btcec.JacobianPoint
+	R.AsJacobian(&rj)
+	P.AsJacobian(&pj)
+	btcec.ScalarMultNonConst(&e, &pj, &eP)
+	btcec.AddNonConst(&rj, &eP, &S)
+	return &S, true
+}
+
+// randomScalar returns a uniformly random non-zero scalar
+func randomScalar() (btcec.ModNScalar, error) {
+	for {
+		var b [32]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return btcec.ModNScalar{}, fmt.Errorf("failed to read randomness: %w", err)
+		}
+		var k btcec.ModNScalar
+		if overflow := k.SetBytes(&b); overflow == 0 && !k.IsZero() {
+			return k, nil
+		}
+	}
+}
+
+// weightedSum returns Σ 2^i·points[i]
+func weightedSum(points []*btcec.JacobianPoint) *btcec.JacobianPoint {
+	var sum btcec.JacobianPoint
+	for i := len(points) - 1; i >= 0; i-- {
+		var doubled btcec.JacobianPoint
+		btcec.DoubleNonConst(&sum, &doubled)
+		btcec.AddNonConst(&doubled, points[i], &sum)
+	}
+	return &sum
+}
+
+// negate replaces p with −p
+func negate(p *btcec.JacobianPoint) {
+	p.ToAffine()
+	p.Y.Negate(1).Normalize()
+}
+
+func isInfinity(p *btcec.JacobianPoint) bool {
+	return (p.X.IsZero() && p.Y.IsZero()) || p.Z.IsZero()
+}
+
+func equalAffine(p, q *btcec.JacobianPoint) bool {
+	p.ToAffine()
+	q.ToAffine()
+	return p.X.Equals(&q.X) && p.Y.Equals(&q.Y)
+}
+
+func parsePoint(b [33]byte) (*btcec.JacobianPoint, error) {
+	pub, err := btcec.ParsePubKey(b[:])
+	if err != nil {
+		return nil, err
+	}
+	var p btcec.JacobianPoint
+	pub.AsJacobian(&p)
+	return &p, nil
+}
+
+func compress(p *btcec.JacobianPoint) [33]byte {
+	p.ToAffine()
+	return [33]byte(btcec.NewPublicKey(&p.X, &p.Y).SerializeCompressed())
+}
+
+// compressOrZero compresses p, mapping the identity to 33 zero bytes
+func compressOrZero(p *btcec.JacobianPoint) [33]byte {
+	if isInfinity(p) {
+		return [33]byte{}
+	}
+	return compress(p)
+}
@@ -0,0 +1,86 @@
+package schnorr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+func TestEncryptSignature(t *testing.T) {
+	alice, _ := btcec.PrivKeyFromBytes([]byte{31: 0x0a})
+	arbiter, _ := btcec.PrivKeyFromBytes([]byte{31: 0x0b})
+	msg := []byte("pay Bob 1 BTC")
+
+	sig, _ := SignBIP340(msg, alice)
+	enc, err := EncryptSignature(msg, alice.PubKey(), sig, arbiter.PubKey())
+	if err != nil {
+		t.Fatalf("EncryptSignature failed: %v", err)
+	}
+	if !VerifyEncryptedSignature(msg, alice.PubKey(), arbiter.PubKey(), enc) {
+		t.Fatal("Expected the encrypted signature to verify")
+	}
+
+	// The arbiter recovers the exact signature
+	got, err := DecryptSignature(msg, alice.PubKey(), enc, arbiter)
+	if err != nil {
+		t.Fatalf("DecryptSignature failed: %v", err)
+	}
+	if got != sig {
+		t.Error("Expected the decrypted signature to be the original one")
+	}
+
+	// Nobody else can decrypt it
+	if _, err := DecryptSignature(msg, alice.PubKey(), enc, alice); !errors.Is(err, ErrInvalidEncryption) {
+		t.Errorf("Expected ErrInvalidEncryption with the wrong key, got %v", err)
+	}
+}
+
+// TestVerifyEncryptedSignatureRejects tests that the proof binds every input
+func TestVerifyEncryptedSignatureRejects(t *testing.T) {
+	alice, _ := btcec.PrivKeyFromBytes([]byte{31: 0x0a})
+	arbiter, _ := btcec.PrivKeyFromBytes([]byte{31: 0x0b})
+	other, _ := btcec.PrivKeyFromBytes([]byte{31: 0x0c})
+	msg := []byte("pay Bob 1 BTC")
+	sig, _ := SignBIP340(msg, alice)
+	enc, err := EncryptSignature(msg, alice.PubKey(), sig, arbiter.PubKey())
+	if err != nil {
+		t.Fatalf("EncryptSignature failed: %v", err)
+	}
+
+	tamper := func(f func(e *EncryptedSignature)) *EncryptedSignature {
+		e := *enc
+		f(&e)
+		return &e
+	}
+	tests := []struct {
+		name      string
+		msg       []byte
+		pub       *btcec.PublicKey
+		recipient *btcec.PublicKey
+		enc       *EncryptedSignature
+	}{
+		{"other message", []byte("pay Bob 2 BTC"), alice.PubKey(), arbiter.PubKey(), enc},
+		{"other signer", msg, other.PubKey(), arbiter.PubKey(), enc},
+		{"other recipient", msg, alice.PubKey(), other.PubKey(), enc},
+		{"tampered R", msg, alice.PubKey(), arbiter.PubKey(), tamper(func(e *EncryptedSignature) { e.R[31] ^= 0x01 })},
+		{"swapped bits", msg, alice.PubKey(), arbiter.PubKey(), tamper(func(e *EncryptedSignature) { e.Bits[0], e.Bits[1] = e.Bits[1], e.Bits[0] })},
+		{"tampered bit proof", msg, alice.PubKey(), arbiter.PubKey(), tamper(func(e *EncryptedSignature) { e.Bits[7].Z0[31] ^= 0x01 })},
+		{"tampered link", msg, alice.PubKey(), arbiter.PubKey(), tamper(func(e *EncryptedSignature) { e.Z[31] ^= 0x01 })},
+		{"nil", msg, alice.PubKey(), arbiter.PubKey(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if VerifyEncryptedSignature(tt.msg, tt.pub, tt.recipient, tt.enc) {
+				t.Error("Expected the encrypted signature to be rejected")
+			}
+		})
+	}
+
+	// Invalid signatures are not encrypted
+	bad := sig
+	bad[63] ^= 0x01
+	if _, err := EncryptSignature(msg, alice.PubKey(), bad, arbiter.PubKey()); err == nil {
+		t.Error("Expected error for an invalid signature")
+	}
+}
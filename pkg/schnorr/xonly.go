@@ -0,0 +1,103 @@
+package schnorr
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+)
+
+// X-only public keys
+//
+// BIP340 and Taproot identify a key by its x-coordinate alone; the point is the
+// one with that x and an even Y. XOnlyPublicKey is a [32]byte with methods, so
+// plain [32]byte values and XOnlyPublicKey convert to each other implicitly and
+// existing code keeps compiling. The Y parity of a full key is not part of an
+// x-only key: XOnlyWithParity and Tweak return it separately, for the places
+// (MuSig key aggregation, taproot control blocks) where it still matters.
+
+// XOnlyPublicKey is a BIP340 public key: the x-coordinate of an even-Y point
+type XOnlyPublicKey [32]byte
+
+// XOnlyWithParity returns the x-only key of pub and whether pub has an odd Y
+//
+// Example:
+//
+//	x, oddY := XOnlyWithParity(pub)
+//	// pub equals x.PublicKey() when oddY is false, its negation otherwise
+func XOnlyWithParity(pub *btcec.PublicKey) (XOnlyPublicKey, bool) {
+	p := arithmetic.PointFromPubKey(pub)
+	var x XOnlyPublicKey
+	p.X().FillBytes(x[:])
+	return x, !arithmetic.HasEvenY(p)
+}
+
+// ParseXOnlyPublicKey parses and validates a 32-byte x-only key
+//
+// Example:
+//
+//	x, err := ParseXOnlyPublicKey(witnessProgram)
+//	// err != nil if the bytes are not the x-coordinate of a curve point
+func ParseXOnlyPublicKey(b []byte) (XOnlyPublicKey, error) {
+	if len(b) != 32 {
+		return XOnlyPublicKey{}, fmt.Errorf("x-only key must be 32 bytes, got %d", len(b))
+	}
+	x := XOnlyPublicKey(b)
+	if _, err := ParseXOnly(x); err != nil {
+		return XOnlyPublicKey{}, err
+	}
+	return x, nil
+}
+
+// PublicKey returns the full even-Y public key
+func (x XOnlyPublicKey) PublicKey() (*btcec.PublicKey, error) {
+	return ParseXOnly(x)
+}
+
+// Serialize returns the 32-byte encoding
+func (x XOnlyPublicKey) Serialize() []byte {
+	return append([]byte(nil), x[:]...)
+}
+
+// Equal reports whether two keys are the same
+func (x XOnlyPublicKey) Equal(other XOnlyPublicKey) bool {
+	return x == other
+}
+
+// Tweak computes the taproot output key, see TweakPubKey
+//
+// Example:
+//
+//	outputKey, oddY, err := internalKey.Tweak(merkleRoot[:])
+func (x XOnlyPublicKey) Tweak(merkleRoot []byte) (XOnlyPublicKey, bool, error) {
+	pub, err := x.PublicKey()
+	if err != nil {
+		return XOnlyPublicKey{}, false, err
+	}
+	return TweakPubKey(pub, merkleRoot)
+}
+
+// String returns the key in hex
+func (x XOnlyPublicKey) String() string {
+	return hex.EncodeToString(x[:])
+}
+
+// MarshalText encodes the key as hex, so JSON holds a string rather than 32 numbers
+func (x XOnlyPublicKey) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText decodes and validates a hex key
+func (x *XOnlyPublicKey) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid x-only key: %w", err)
+	}
+	parsed, err := ParseXOnlyPublicKey(b)
+	if err != nil {
+		return err
+	}
+	*x = parsed
+	return nil
+}
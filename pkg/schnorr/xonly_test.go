@@ -0,0 +1,89 @@
+package schnorr
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+func TestXOnlyPublicKeyMethods(t *testing.T) {
+	// Generator point: x = 79be66..., even Y
+	priv, pub := btcec.PrivKeyFromBytes([]byte{0x01})
+	x, oddY := XOnlyWithParity(pub)
+	if oddY {
+		t.Error("Expected G to have an even Y")
+	}
+	if x.String() != "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" {
+		t.Errorf("Unexpected x-only key %s", x)
+	}
+	if !x.Equal(XOnlyFromPub(pub)) || !bytes.Equal(x.Serialize(), x[:]) {
+		t.Error("Expected XOnlyWithParity, XOnlyFromPub and Serialize to agree")
+	}
+
+	// Negating the key flips the parity but keeps the x-only key
+	var neg btcec.ModNScalar
+	neg.Set(&priv.Key).Negate()
+	negX, negOdd := XOnlyWithParity(btcec.PrivKeyFromScalar(&neg).PubKey())
+	if !negOdd || negX != x {
+		t.Error("Expected -G to have the same x and an odd Y")
+	}
+
+	// PublicKey is the even-Y lift
+	lifted, err := x.PublicKey()
+	if err != nil {
+		t.Fatalf("PublicKey failed: %v", err)
+	}
+	if !lifted.IsEqual(pub) {
+		t.Error("Expected the even-Y lift to be G")
+	}
+
+	// Tweak agrees with TweakPubKey
+	got, gotOdd, err := x.Tweak(nil)
+	want, wantOdd, _ := TweakPubKey(pub, nil)
+	if err != nil || got != want || gotOdd != wantOdd {
+		t.Errorf("Expected Tweak to match TweakPubKey, got %s (%v)", got, err)
+	}
+}
+
+func TestXOnlyPublicKeyEncoding(t *testing.T) {
+	_, pub := btcec.PrivKeyFromBytes([]byte{0x02})
+	x := XOnlyFromPub(pub)
+
+	// JSON uses hex strings
+	data, err := json.Marshal(struct{ Key XOnlyPublicKey }{x})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"Key":"`+x.String()+`"}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+	var decoded struct{ Key XOnlyPublicKey }
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Key != x {
+		t.Errorf("Expected JSON round trip, got %s (%v)", decoded.Key, err)
+	}
+
+	// Invalid keys are rejected
+	notOnCurve := bytes.Repeat([]byte{0xff}, 32)
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"not hex", "zz"},
+		{"short", x.String()[:62]},
+		{"not on the curve", string(bytes.Repeat([]byte("ff"), 32))},
+	}
+	for _, tt := range tests {
+		var k XOnlyPublicKey
+		if err := k.UnmarshalText([]byte(tt.text)); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+	if _, err := ParseXOnlyPublicKey(notOnCurve); err == nil {
+		t.Error("Expected error for a value that is not a curve x-coordinate")
+	}
+	if parsed, err := ParseXOnlyPublicKey(x[:]); err != nil || parsed != x {
+		t.Errorf("Expected ParseXOnlyPublicKey to accept %s, got %v", x, err)
+	}
+}
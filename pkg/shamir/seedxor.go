@@ -0,0 +1,96 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Seed XOR
+//
+// The simpler alternative to Shamir for backing up a BIP39 seed: the entropy
+// E is split into n parts whose XOR is E,
+//
+//	E = S_1 ⊕ S_2 ⊕ ... ⊕ S_n,   S_1..S_{n-1} random
+//
+// Every part has the length of E, so it is itself valid BIP39 entropy and can
+// be written down as an ordinary mnemonic of the same word count (BIP39 only
+// adds a checksum). Unlike Shamir, Seed XOR is n-of-n: one lost part loses the
+// seed, while any n-1 parts reveal nothing about it. In exchange it needs no
+// field arithmetic and can be recombined with pen and paper.
+
+// ErrEntropyLength is returned for entropy that is not 16, 20, 24, 28 or 32 bytes
+var ErrEntropyLength = errors.New("entropy must be 16, 20, 24, 28 or 32 bytes")
+
+// SplitXOR divides BIP39 entropy into parts XOR shares, all of which recover it
+//
+// Example:
+//
+//	parts, err := SplitXOR(entropy, 3, nil)
+//	// Result: 3 entropies of len(entropy) bytes, each encodable as a mnemonic
+func SplitXOR(entropy []byte, parts int, random io.Reader) ([][]byte, error) {
+	// Step 1: Validate inputs
+	if err := checkEntropy(entropy); err != nil {
+		return nil, err
+	}
+	if parts < 2 || parts > maxShares {
+		return nil, fmt.Errorf("parts must be between 2 and %d, got %d", maxShares, parts)
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+
+	// Step 2: n-1 random parts, the last one makes the XOR equal the entropy
+	shares := make([][]byte, parts)
+	last := append([]byte(nil), entropy...)
+	for i := 0; i < parts-1; i++ {
+		shares[i] = make([]byte, len(entropy))
+		if _, err := io.ReadFull(random, shares[i]); err != nil {
+			return nil, fmt.Errorf("failed to generate part %d: %w", i+1, err)
+		}
+		xorInto(last, shares[i])
+	}
+	shares[parts-1] = last
+	return shares, nil
+}
+
+// CombineXOR recovers the entropy from all of its XOR parts
+//
+// Any order works, but every part is needed; with one missing the result is
+// a different, valid looking entropy.
+//
+// Example:
+//
+//	entropy, err := CombineXOR(parts)
+func CombineXOR(parts [][]byte) ([]byte, error) {
+	if len(parts) < 2 {
+		return nil, errors.New("at least 2 parts are required")
+	}
+	entropy := make([]byte, len(parts[0]))
+	for i, part := range parts {
+		if err := checkEntropy(part); err != nil {
+			return nil, fmt.Errorf("part %d: %w", i+1, err)
+		}
+		if len(part) != len(entropy) {
+			return nil, fmt.Errorf("part %d has %d bytes, expected %d", i+1, len(part), len(entropy))
+		}
+		xorInto(entropy, part)
+	}
+	return entropy, nil
+}
+
+// checkEntropy checks that b has a BIP39 entropy length
+func checkEntropy(b []byte) error {
+	if len(b) < 16 || len(b) > 32 || len(b)%4 != 0 {
+		return ErrEntropyLength
+	}
+	return nil
+}
+
+// xorInto sets dst to dst ⊕ src
+func xorInto(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
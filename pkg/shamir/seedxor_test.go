@@ -0,0 +1,74 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSplitAndCombineXOR(t *testing.T) {
+	tests := []struct {
+		entropyLen, parts int
+	}{
+		{16, 2}, {20, 3}, {24, 3}, {32, 5},
+	}
+	for _, tt := range tests {
+		entropy := bytes.Repeat([]byte{0x5a}, tt.entropyLen)
+		parts, err := SplitXOR(entropy, tt.parts, nil)
+		if err != nil {
+			t.Fatalf("SplitXOR(%d, %d) failed: %v", tt.entropyLen, tt.parts, err)
+		}
+		if len(parts) != tt.parts {
+			t.Fatalf("Expected %d parts, got %d", tt.parts, len(parts))
+		}
+		for i, p := range parts {
+			if len(p) != tt.entropyLen {
+				t.Errorf("Expected part %d to be valid %d-byte entropy, got %d bytes", i, tt.entropyLen, len(p))
+			}
+			if bytes.Equal(p, entropy) {
+				t.Errorf("Expected part %d to differ from the entropy", i)
+			}
+		}
+
+		// All parts, in any order, recover the entropy
+		reversed := make([][]byte, len(parts))
+		for i := range parts {
+			reversed[i] = parts[len(parts)-1-i]
+		}
+		for _, set := range [][][]byte{parts, reversed} {
+			got, err := CombineXOR(set)
+			if err != nil {
+				t.Fatalf("CombineXOR failed: %v", err)
+			}
+			if !bytes.Equal(got, entropy) {
+				t.Errorf("%d parts did not recover the entropy", tt.parts)
+			}
+		}
+
+		// Unlike Shamir, dropping a part gives a different entropy
+		if tt.parts > 2 {
+			got, _ := CombineXOR(parts[1:])
+			if bytes.Equal(got, entropy) {
+				t.Error("Expected n-1 parts not to recover the entropy")
+			}
+		}
+	}
+}
+
+func TestXORErrors(t *testing.T) {
+	if _, err := SplitXOR(make([]byte, 15), 2, nil); !errors.Is(err, ErrEntropyLength) {
+		t.Errorf("Expected ErrEntropyLength, got %v", err)
+	}
+	if _, err := SplitXOR(make([]byte, 16), 1, nil); err == nil {
+		t.Error("Expected error for a single part")
+	}
+	if _, err := SplitXOR(make([]byte, 16), 2, bytes.NewReader(nil)); err == nil {
+		t.Error("Expected error for a failing random source")
+	}
+	if _, err := CombineXOR([][]byte{make([]byte, 16)}); err == nil {
+		t.Error("Expected error for a single part")
+	}
+	if _, err := CombineXOR([][]byte{make([]byte, 16), make([]byte, 32)}); err == nil {
+		t.Error("Expected error for parts of different lengths")
+	}
+}
@@ -0,0 +1,207 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// Shamir secret sharing over the secp256k1 scalar field, with Feldman commitments
+//
+// The secret is the constant term of a random polynomial f of degree t-1;
+// share i is (i, f(i)). Any t shares determine f, and so the secret f(0), by
+// Lagrange interpolation; fewer reveal nothing. Working modulo the curve order
+// n lets the dealer publish commitments C_k = a_k·G to the coefficients, so
+// every holder can check their share without learning anything about the
+// others:
+//
+//	f(i)·G == Σ i^k · C_k
+
+// maxShares bounds the number of shares (indices are small positive integers)
+const maxShares = 255
+
+var (
+	// ErrInvalidShare is returned when a share does not match the commitments
+	ErrInvalidShare = errors.New("share does not match commitments")
+	// ErrDuplicateShare is returned when two shares have the same index
+	ErrDuplicateShare = errors.New("duplicate share index")
+)
+
+// Share is one point (Index, f(Index)) of the sharing polynomial
+type Share struct {
+	Index int      `json:"index"` // 1-based
+	Value [32]byte `json:"value"` // f(Index), big-endian scalar
+}
+
+// Split divides a 32-byte secret into total shares, any threshold of which recover it
+//
+// The secret must be a valid non-zero scalar modulo the curve order (any
+// secp256k1 private key is). commitments are the compressed points C_k = a_k·G,
+// with C_0 = secret·G the public key of the secret.
+//
+// Example:
+//
+//	shares, commitments, err := Split(privateKey, 2, 3, rand.Reader)
+//	// Result: 3 shares, any 2 of which recover privateKey
+func Split(secret [32]byte, threshold, total int, random io.Reader) ([]Share, [][]byte, error) {
+	// Step 1: Validate inputs
+	if threshold < 1 || threshold > total {
+		return nil, nil, fmt.Errorf("invalid threshold %d of %d", threshold, total)
+	}
+	if total > maxShares {
+		return nil, nil, fmt.Errorf("at most %d shares are supported", maxShares)
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+	coefficients := make([]btcec.ModNScalar, threshold)
+	defer func() {
+		for i := range coefficients {
+			coefficients[i].Zero()
+		}
+	}()
+	if overflow := coefficients[0].SetBytes(&secret); overflow != 0 || coefficients[0].IsZero() {
+		return nil, nil, errors.New("secret must be a non-zero scalar below the curve order")
+	}
+
+	// Step 2: Random coefficients a_1..a_{t-1}
+	for k := 1; k < threshold; k++ {
+		var buf [32]byte
+		for {
+			if _, err := io.ReadFull(random, buf[:]); err != nil {
+				return nil, nil, fmt.Errorf("failed to read randomness: %w", err)
+			}
+			if overflow := coefficients[k].SetBytes(&buf); overflow == 0 && !coefficients[k].IsZero() {
+				break
+			}
+		}
+		clear(buf[:])
+	}
+
+	// Step 3: Commitments C_k = a_k·G
+	commitments := make([][]byte, threshold)
+	for k := range coefficients {
+		var point btcec.JacobianPoint
+		btcec.ScalarBaseMultNonConst(&coefficients[k], &point)
+		point.ToAffine()
+		commitments[k] = btcec.NewPublicKey(&point.X, &point.Y).SerializeCompressed()
+	}
+
+	// Step 4: Shares f(i) for i = 1..total (Horner's rule)
+	shares := make([]Share, total)
+	for i := range shares {
+		var x, y btcec.ModNScalar
+		x.SetInt(uint32(i + 1))
+		for k := threshold - 1; k >= 0; k-- {
+			y.Mul(&x).Add(&coefficients[k])
+		}
+		shares[i] = Share{Index: i + 1, Value: y.Bytes()}
+		y.Zero()
+	}
+	return shares, commitments, nil
+}
+
+// VerifyShare checks a share against the dealer's commitments
+//
+// Example:
+//
+//	if err := VerifyShare(share, commitments); err != nil {
+//		// the dealer handed out an inconsistent share
+//	}
+func VerifyShare(share Share, commitments [][]byte) error {
+	if share.Index < 1 || share.Index > maxShares {
+		return fmt.Errorf("%w: index %d out of range", ErrInvalidShare, share.Index)
+	}
+	if len(commitments) == 0 {
+		return errors.New("no commitments")
+	}
+
+	// Step 1: f(i)·G
+	var value btcec.ModNScalar
+	if overflow := value.SetBytes(&share.Value); overflow != 0 {
+		return fmt.Errorf("%w: value out of range", ErrInvalidShare)
+	}
+	var lhs btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&value, &lhs)
+	value.Zero()
+
+	// Step 2: Σ i^k · C_k
+	var x, power btcec.ModNScalar
+	x.SetInt(uint32(share.Index))
+	power.SetInt(1)
+	var rhs btcec.JacobianPoint
+	for k, encoded := range commitments {
+		c, err := btcec.ParsePubKey(encoded)
+		if err != nil {
+			return fmt.Errorf("invalid commitment %d: %w", k, err)
+		}
+		var point, term btcec.JacobianPoint
+		c.AsJacobian(&point)
+		btcec.ScalarMultNonConst(&power, &point, &term)
+		btcec.AddNonConst(&rhs, &term, &rhs)
+		power.Mul(&x)
+	}
+
+	lhs.ToAffine()
+	rhs.ToAffine()
+	if !lhs.X.Equals(&rhs.X) || !lhs.Y.Equals(&rhs.Y) {
+		return ErrInvalidShare
+	}
+	return nil
+}
+
+// Combine recovers the secret from shares by Lagrange interpolation at 0
+//
+// At least threshold distinct shares must be given; with fewer the result is
+// a meaningless value, which callers detect by comparing against C_0.
+//
+// Example:
+//
+//	secret, err := Combine([]Share{shares[0], shares[2]})
+func Combine(shares []Share) ([32]byte, error) {
+	if len(shares) == 0 {
+		return [32]byte{}, errors.New("no shares")
+	}
+	seen := make(map[int]bool, len(shares))
+	for _, s := range shares {
+		if s.Index < 1 || s.Index > maxShares {
+			return [32]byte{}, fmt.Errorf("%w: index %d out of range", ErrInvalidShare, s.Index)
+		}
+		if seen[s.Index] {
+			return [32]byte{}, fmt.Errorf("%w: %d", ErrDuplicateShare, s.Index)
+		}
+		seen[s.Index] = true
+	}
+
+	// secret = Σ y_i · Π_{j≠i} x_j / (x_j - x_i)
+	var secret btcec.ModNScalar
+	for _, si := range shares {
+		var num, den btcec.ModNScalar
+		num.SetInt(1)
+		den.SetInt(1)
+		for _, sj := range shares {
+			if sj.Index == si.Index {
+				continue
+			}
+			var xj, diff btcec.ModNScalar
+			xj.SetInt(uint32(sj.Index))
+			num.Mul(&xj)
+			diff.SetInt(uint32(si.Index)).Negate().Add(&xj)
+			den.Mul(&diff)
+		}
+		var y btcec.ModNScalar
+		if overflow := y.SetBytes(&si.Value); overflow != 0 {
+			return [32]byte{}, fmt.Errorf("%w: value out of range", ErrInvalidShare)
+		}
+		den.InverseNonConst()
+		secret.Add(y.Mul(&num).Mul(&den))
+		y.Zero()
+	}
+
+	out := secret.Bytes()
+	secret.Zero()
+	return out, nil
+}
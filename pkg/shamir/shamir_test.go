@@ -0,0 +1,122 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/fixtures"
+)
+
+func TestSplitAndCombine(t *testing.T) {
+	priv, _ := btcec.NewPrivateKey()
+	secret := priv.Key.Bytes()
+
+	tests := []struct {
+		threshold, total int
+	}{
+		{1, 1}, {1, 3}, {2, 3}, {3, 5}, {5, 5},
+	}
+	for _, tt := range tests {
+		shares, commitments, err := Split(secret, tt.threshold, tt.total, nil)
+		if err != nil {
+			t.Fatalf("Split(%d, %d) failed: %v", tt.threshold, tt.total, err)
+		}
+		if len(shares) != tt.total || len(commitments) != tt.threshold {
+			t.Fatalf("Expected %d shares and %d commitments, got %d and %d", tt.total, tt.threshold, len(shares), len(commitments))
+		}
+
+		// C_0 is the public key of the secret
+		if !bytes.Equal(commitments[0], priv.PubKey().SerializeCompressed()) {
+			t.Errorf("Expected C_0 to be the public key")
+		}
+		for _, s := range shares {
+			if err := VerifyShare(s, commitments); err != nil {
+				t.Errorf("VerifyShare(%d) failed: %v", s.Index, err)
+			}
+		}
+
+		// Every window of threshold shares recovers the secret
+		for start := 0; start+tt.threshold <= tt.total; start++ {
+			got, err := Combine(shares[start : start+tt.threshold])
+			if err != nil {
+				t.Fatalf("Combine failed: %v", err)
+			}
+			if got != secret {
+				t.Errorf("%d-of-%d: shares %d.. did not recover the secret", tt.threshold, tt.total, start+1)
+			}
+		}
+
+		// One share fewer does not
+		if tt.threshold > 1 {
+			got, _ := Combine(shares[:tt.threshold-1])
+			if got == secret {
+				t.Errorf("%d-of-%d: recovered the secret below the threshold", tt.threshold, tt.total)
+			}
+		}
+	}
+}
+
+func TestSplitDeterministic(t *testing.T) {
+	secret := [32]byte{31: 0x2a}
+	a, _, err := Split(secret, 2, 3, fixtures.DeterministicReader("shamir"))
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	b, _, _ := Split(secret, 2, 3, fixtures.DeterministicReader("shamir"))
+	for i := range a {
+		if a[i] != b[i] {
+			t.Errorf("Expected identical shares from identical randomness")
+		}
+	}
+}
+
+func TestVerifyShareRejects(t *testing.T) {
+	secret := [32]byte{31: 0x07}
+	shares, commitments, _ := Split(secret, 2, 3, nil)
+
+	tampered := shares[0]
+	tampered.Value[31] ^= 0x01
+	if err := VerifyShare(tampered, commitments); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("Expected ErrInvalidShare for a tampered value, got %v", err)
+	}
+	moved := shares[0]
+	moved.Index = 2
+	if err := VerifyShare(moved, commitments); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("Expected ErrInvalidShare for a wrong index, got %v", err)
+	}
+	if err := VerifyShare(Share{Index: 0}, commitments); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("Expected ErrInvalidShare for index 0, got %v", err)
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	valid := [32]byte{31: 0x01}
+	tests := []struct {
+		name             string
+		secret           [32]byte
+		threshold, total int
+	}{
+		{"zero threshold", valid, 0, 3},
+		{"threshold above total", valid, 4, 3},
+		{"too many shares", valid, 2, 256},
+		{"zero secret", [32]byte{}, 2, 3},
+		{"secret above order", [32]byte{0: 0xff, 1: 0xff, 2: 0xff, 3: 0xff, 4: 0xff, 5: 0xff, 6: 0xff, 7: 0xff, 8: 0xff, 9: 0xff, 10: 0xff, 11: 0xff, 12: 0xff, 13: 0xff, 14: 0xff, 15: 0xff, 16: 0xff}, 2, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Split(tt.secret, tt.threshold, tt.total, nil); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	shares, _, _ := Split(valid, 2, 3, nil)
+	if _, err := Combine([]Share{shares[0], shares[0]}); !errors.Is(err, ErrDuplicateShare) {
+		t.Errorf("Expected ErrDuplicateShare, got %v", err)
+	}
+	if _, err := Combine(nil); err == nil {
+		t.Error("Expected error for no shares")
+	}
+}
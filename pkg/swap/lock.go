@@ -0,0 +1,206 @@
+package swap
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+	"github.com/neverDefined/cryptography-playground/pkg/txsize"
+)
+
+// Lock outputs
+//
+// Each party locks its coins in a P2TR output whose internal key is the BIP341
+// NUMS point (so there is no key path) and whose script tree has two leaves:
+//
+//	redeem: <claimer> OP_CHECKSIGVERIFY <owner> OP_CHECKSIG
+//	refund: <timeout> OP_CHECKSEQUENCEVERIFY OP_DROP <owner> OP_CHECKSIG
+//
+// The claimer can only redeem with the owner's signature, which the owner hands
+// over as an adaptor signature; the owner gets the coins back once the output
+// is timeout blocks deep.
+
+// numsKey is the BIP341 point H = lift_x(SHA256(G)), whose discrete log is unknown
+var numsKey = mustXOnly("50929b74c1a04954b78b4b6035e97a5e078a5a0f28ec96d547bfee9ace803ac0")
+
+// LockOutput is the P2TR output one party locks its side of the swap in
+type LockOutput struct {
+	Owner        schnorr.XOnlyPublicKey // Party that funds the output and can refund it
+	Claimer      schnorr.XOnlyPublicKey // Party that redeems it with the owner's signature
+	Timeout      uint16                 // Relative timelock of the refund path, in blocks
+	Value        int64                  // Amount locked, in satoshis
+	OutputKey    schnorr.XOnlyPublicKey // Tweaked key committed in the scriptPubKey
+	OutputKeyOdd bool                   // Parity of the output key (encoded in control blocks)
+	RedeemScript []byte
+	RefundScript []byte
+}
+
+// NewLockOutput builds the lock output of owner's coins, redeemable by claimer
+//
+// Example:
+//
+//	lock, err := NewLockOutput(alice, bob, 144, 100000)
+//	address, _ := lock.Address(&chaincfg.MainNetParams) // "bc1p..."
+func NewLockOutput(owner, claimer schnorr.XOnlyPublicKey, timeout uint16, value int64) (*LockOutput, error) {
+	// Step 1: Validate inputs
+	if owner == claimer {
+		return nil, errors.New("owner and claimer must be different keys")
+	}
+	if timeout == 0 {
+		return nil, errors.New("refund timeout must be at least one block")
+	}
+	if value <= 0 {
+		return nil, errors.New("locked value must be positive")
+	}
+
+	// Step 2: Build both leaves
+	lock := &LockOutput{
+		Owner:        owner,
+		Claimer:      claimer,
+		Timeout:      timeout,
+		Value:        value,
+		RedeemScript: RedeemScript(owner, claimer),
+		RefundScript: RefundScript(owner, timeout),
+	}
+
+	// Step 3: Q = H + TaggedHash("TapTweak", H || root)·G
+	internal, err := numsKey.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	root := hash.TapBranchHash(hash.TapLeafHash(hash.TapLeafVersion, lock.RedeemScript), hash.TapLeafHash(hash.TapLeafVersion, lock.RefundScript))
+	lock.OutputKey, lock.OutputKeyOdd, err = schnorr.TweakPubKey(internal, root[:])
+	if err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// RedeemScript returns the leaf spent by the claimer: both signatures are required
+//
+// Format: [0x20][claimer][OP_CHECKSIGVERIFY][0x20][owner][OP_CHECKSIG]
+func RedeemScript(owner, claimer schnorr.XOnlyPublicKey) []byte {
+	script := make([]byte, 0, 68)
+	script = append(script, 0x20)
+	script = append(script, claimer[:]...)
+	script = append(script, tx.OP_CHECKSIGVERIFY, 0x20)
+	script = append(script, owner[:]...)
+	return append(script, tx.OP_CHECKSIG)
+}
+
+// RefundScript returns the leaf spent by the owner after timeout blocks
+//
+// Format: [timeout][OP_CHECKSEQUENCEVERIFY][OP_DROP][0x20][owner][OP_CHECKSIG]
+func RefundScript(owner schnorr.XOnlyPublicKey, timeout uint16) []byte {
+	script := tx.ScriptNum(int64(timeout))
+	script = append(script, tx.OP_CHECKSEQUENCEVERIFY, tx.OP_DROP, 0x20)
+	script = append(script, owner[:]...)
+	return append(script, tx.OP_CHECKSIG)
+}
+
+// PkScript returns the scriptPubKey of the lock output
+//
+// Format: [OP_1][0x20][outputKey]
+func (l *LockOutput) PkScript() []byte {
+	return append([]byte{tx.OP_1, 0x20}, l.OutputKey[:]...)
+}
+
+// Address returns the bech32m address of the lock output
+func (l *LockOutput) Address(params *chaincfg.Params) (string, error) {
+	return tx.ExtractAddress(l.PkScript(), params)
+}
+
+// RedeemTemplate builds the unsigned transaction paying the locked coins (minus fee) to payout
+//
+// It returns the template and the BIP342 digest both parties sign.
+func (l *LockOutput) RedeemTemplate(outpoint tx.OutPoint, payout []byte, fee int64) (*tx.Template, [32]byte, error) {
+	return l.spend(outpoint, l.RedeemScript, l.RefundScript, 2, 0, payout, fee)
+}
+
+// RefundTemplate builds the unsigned transaction returning the locked coins (minus fee) to refund
+//
+// The input's sequence is the timeout, so the transaction is only valid once
+// the lock output is Timeout blocks deep.
+func (l *LockOutput) RefundTemplate(outpoint tx.OutPoint, refund []byte, fee int64) (*tx.Template, [32]byte, error) {
+	return l.spend(outpoint, l.RefundScript, l.RedeemScript, 1, uint32(l.Timeout), refund, fee)
+}
+
+// spend builds a script path spend of leaf with signers signature slots
+func (l *LockOutput) spend(outpoint tx.OutPoint, leaf, sibling []byte, signers int, sequence uint32, payout []byte, fee int64) (*tx.Template, [32]byte, error) {
+	// Step 1: Validate inputs
+	if len(payout) == 0 {
+		return nil, [32]byte{}, errors.New("payout script cannot be empty")
+	}
+	if fee < 0 || fee >= l.Value {
+		return nil, [32]byte{}, fmt.Errorf("fee %d must be between 0 and the locked value %d", fee, l.Value)
+	}
+
+	// Step 2: One input spending the leaf, one output
+	b := tx.NewBuilder()
+	b.AddInput(tx.TemplateInput{
+		OutPoint: outpoint,
+		Value:    l.Value,
+		Sequence: sequence,
+		Template: txsize.InputTemplate{
+			Type:           txsize.P2TRScriptPath,
+			Threshold:      signers,
+			Total:          signers,
+			LeafScriptSize: len(leaf),
+			MerkleDepth:    1,
+		},
+		Script:       leaf,
+		ControlBlock: l.controlBlock(sibling),
+	})
+	b.AddOutput(l.Value-fee, payout)
+	tmpl, err := b.Build()
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+
+	// Step 3: BIP342 digest of the leaf
+	prevouts := []*tx.TxOut{{Value: l.Value, PkScript: l.PkScript()}}
+	digest, err := tx.TaprootScriptSigHash(tmpl.Tx, 0, prevouts, tx.SigHashDefault, hash.TapLeafHash(hash.TapLeafVersion, leaf))
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+	return tmpl, digest, nil
+}
+
+// controlBlock reveals the internal key and the sibling leaf's hash
+//
+// Format: [0xc0 | parity][internal key][hash of the other leaf]
+func (l *LockOutput) controlBlock(sibling []byte) []byte {
+	control := byte(hash.TapLeafVersion)
+	if l.OutputKeyOdd {
+		control |= 0x01
+	}
+	siblingHash := hash.TapLeafHash(hash.TapLeafVersion, sibling)
+	block := append([]byte{control}, numsKey[:]...)
+	return append(block, siblingHash[:]...)
+}
+
+// signDigest signs a BIP342 digest with a private key
+func signDigest(digest [32]byte, priv *btcec.PrivateKey) ([]byte, error) {
+	sig, err := schnorr.SignDigest(digest, priv)
+	if err != nil {
+		return nil, err
+	}
+	return sig[:], nil
+}
+
+func mustXOnly(s string) schnorr.XOnlyPublicKey {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	key, err := schnorr.ParseXOnlyPublicKey(b)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
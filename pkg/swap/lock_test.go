@@ -0,0 +1,136 @@
+package swap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+func testKey(b byte) (*btcec.PrivateKey, schnorr.XOnlyPublicKey) {
+	priv, _ := btcec.PrivKeyFromBytes([]byte{31: b})
+	return priv, schnorr.XOnlyFromPub(priv.PubKey())
+}
+
+func TestNewLockOutput(t *testing.T) {
+	_, alice := testKey(0x01)
+	_, bob := testKey(0x02)
+
+	lock, err := NewLockOutput(alice, bob, 144, 100000)
+	if err != nil {
+		t.Fatalf("NewLockOutput failed: %v", err)
+	}
+
+	// The refund leaf starts with the timeout and ends with the owner's CHECKSIG
+	expectedRefund := append([]byte{0x02, 0x90, 0x00, tx.OP_CHECKSEQUENCEVERIFY, tx.OP_DROP, 0x20}, alice[:]...)
+	expectedRefund = append(expectedRefund, tx.OP_CHECKSIG)
+	if !bytes.Equal(lock.RefundScript, expectedRefund) {
+		t.Errorf("Expected refund script %x, got %x", expectedRefund, lock.RefundScript)
+	}
+	if len(lock.RedeemScript) != 68 || !bytes.Equal(lock.RedeemScript[1:33], bob[:]) {
+		t.Errorf("Expected the redeem script to check the claimer first, got %x", lock.RedeemScript)
+	}
+
+	// The output key is the NUMS key tweaked by the tree, with the parity the control blocks carry
+	internal, err := numsKey.PublicKey()
+	if err != nil {
+		t.Fatalf("NUMS key does not parse: %v", err)
+	}
+	root := hash.TapBranchHash(hash.TapLeafHash(hash.TapLeafVersion, lock.RefundScript), hash.TapLeafHash(hash.TapLeafVersion, lock.RedeemScript))
+	outputKey, odd, _ := schnorr.TweakPubKey(internal, root[:])
+	if outputKey != lock.OutputKey || odd != lock.OutputKeyOdd {
+		t.Error("Expected the output key to commit to both leaves in either order")
+	}
+	control := lock.controlBlock(lock.RefundScript)
+	if len(control) != 65 || control[0]&0xfe != hash.TapLeafVersion || (control[0]&0x01 == 1) != lock.OutputKeyOdd {
+		t.Errorf("Expected a 65-byte control block with leaf version and parity, got %x", control)
+	}
+
+	address, err := lock.Address(&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("Address failed: %v", err)
+	}
+	if address[:4] != "bc1p" {
+		t.Errorf("Expected a P2TR address, got %s", address)
+	}
+
+	// Swapping roles gives a different output
+	other, _ := NewLockOutput(bob, alice, 144, 100000)
+	if other.OutputKey == lock.OutputKey {
+		t.Error("Expected owner and claimer to matter")
+	}
+}
+
+func TestNewLockOutputErrors(t *testing.T) {
+	_, alice := testKey(0x01)
+	_, bob := testKey(0x02)
+
+	tests := []struct {
+		name    string
+		claimer schnorr.XOnlyPublicKey
+		timeout uint16
+		value   int64
+	}{
+		{"same keys", alice, 144, 1000},
+		{"zero timeout", bob, 0, 1000},
+		{"zero value", bob, 144, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLockOutput(alice, tt.claimer, tt.timeout, tt.value); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestLockTemplates(t *testing.T) {
+	alicePriv, alice := testKey(0x01)
+	_, bob := testKey(0x02)
+	lock, _ := NewLockOutput(alice, bob, 144, 100000)
+	outpoint := tx.OutPoint{Hash: [32]byte{0xaa}, Index: 1}
+	payout := append([]byte{tx.OP_0, 0x14}, make([]byte, 20)...)
+
+	redeem, redeemDigest, err := lock.RedeemTemplate(outpoint, payout, 500)
+	if err != nil {
+		t.Fatalf("RedeemTemplate failed: %v", err)
+	}
+	refund, refundDigest, err := lock.RefundTemplate(outpoint, payout, 500)
+	if err != nil {
+		t.Fatalf("RefundTemplate failed: %v", err)
+	}
+	if redeemDigest == refundDigest {
+		t.Error("Expected the two leaves to have different digests")
+	}
+	if redeem.Tx.Inputs[0].Sequence != 0xffffffff || refund.Tx.Inputs[0].Sequence != 144 {
+		t.Error("Expected only the refund to carry the relative timelock")
+	}
+	if redeem.Tx.Outputs[0].Value != 99500 {
+		t.Errorf("Expected 99500 sat paid out, got %d", redeem.Tx.Outputs[0].Value)
+	}
+
+	// A signed refund has the witness <sig> <refund script> <control block>
+	sig, _ := schnorr.SignDigest(refundDigest, alicePriv)
+	if err := refund.SetSignature(0, 0, sig[:]); err != nil {
+		t.Fatalf("SetSignature failed: %v", err)
+	}
+	signed, err := refund.Finalize()
+	if err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+	witness := signed.Inputs[0].Witness
+	if len(witness) != 3 || !bytes.Equal(witness[1], lock.RefundScript) || !bytes.Equal(witness[2], lock.controlBlock(lock.RedeemScript)) {
+		t.Errorf("Unexpected refund witness: %x", witness)
+	}
+
+	if _, _, err := lock.RedeemTemplate(outpoint, payout, 100000); err == nil {
+		t.Error("Expected error for a fee eating the whole value")
+	}
+	if _, _, err := lock.RedeemTemplate(outpoint, nil, 500); err == nil {
+		t.Error("Expected error for an empty payout")
+	}
+}
@@ -0,0 +1,567 @@
+package swap
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/fsm"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+// Atomic swaps with adaptor signatures
+//
+// The initiator (Alice) swaps coins she holds for coins the participant (Bob)
+// holds, on the same chain or on two chains. Alice picks a secret t and shares
+// T = t·G; each side locks its coins in a LockOutput redeemable by the other:
+//
+//	Alice → Bob:   Offer     terms, Alice's key, T, where Alice wants Bob's coins
+//	Bob → Alice:   Accept    Bob's key, where Bob wants Alice's coins
+//	Alice:         lock A    (Alice's coins, refundable after InitiatorTimeout)
+//	Alice → Bob:   Locked    outpoint of A, Alice's adaptor signature on Bob's redeem of A
+//	Bob:           lock B    (Bob's coins, refundable after ParticipantTimeout)
+//	Bob → Alice:   Locked    outpoint of B, Bob's adaptor signature on Alice's redeem of B
+//	Alice:         redeem B  completing Bob's signature with t
+//	Bob:           redeem A  extracting t from Alice's redeem of B
+//
+// Publishing the redeem of B reveals t, so Alice cannot take B without giving
+// Bob what he needs to take A. If Bob never locks, Alice refunds A; if Alice
+// never redeems B, both refund. Bob's timeout must expire first, so that he
+// can always redeem A after Alice redeems B; for swaps across chains with
+// different block intervals, choose both timeouts in wall-clock terms.
+//
+// The Swap object only handles keys, messages and transactions; funding the
+// lock outputs, checking them on chain and broadcasting are up to the caller
+// (see pkg/chain).
+
+// Role is the side of the swap a Swap object plays
+type Role int
+
+const (
+	// Initiator holds the secret and locks first
+	Initiator Role = iota
+	// Participant learns the secret from the initiator's redeem transaction
+	Participant
+)
+
+// String returns the name of the role
+func (r Role) String() string {
+	if r == Initiator {
+		return "initiator"
+	}
+	return "participant"
+}
+
+// State is the progress of a swap
+type State int
+
+const (
+	// StateOffered: the initiator sent an offer
+	StateOffered State = iota
+	// StateAccepted: both parties know the terms and keys
+	StateAccepted
+	// StateLocked: our coins are locked, the counterparty's are not
+	StateLocked
+	// StateCounterpartyLocked: the counterparty's coins are locked, ours are not
+	StateCounterpartyLocked
+	// StateReady: both sides are locked and we hold the counterparty's adaptor signature
+	StateReady
+	// StateRedeemed: we built the redeem of the counterparty's coins
+	StateRedeemed
+	// StateRefunded: we built the refund of our coins
+	StateRefunded
+)
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case StateOffered:
+		return "offered"
+	case StateAccepted:
+		return "accepted"
+	case StateLocked:
+		return "locked"
+	case StateCounterpartyLocked:
+		return "counterparty-locked"
+	case StateReady:
+		return "ready"
+	case StateRedeemed:
+		return "redeemed"
+	case StateRefunded:
+		return "refunded"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// swapLifecycle is the state machine both parties follow
+//
+// The initiator locks first ("lock-first", then "locked-second" on the
+// participant's Locked); the participant receives first ("locked-first", then
+// "lock-second").
+var swapLifecycle = &fsm.Definition[State]{
+	Name:    "atomic-swap",
+	Initial: StateOffered,
+	Final:   []State{StateRedeemed, StateRefunded},
+	Events: []fsm.Event[State]{
+		{Name: "accept", From: []State{StateOffered}, To: StateAccepted},
+		{Name: "lock-first", From: []State{StateAccepted}, To: StateLocked},
+		{Name: "locked-first", From: []State{StateAccepted}, To: StateCounterpartyLocked},
+		{Name: "lock-second", From: []State{StateCounterpartyLocked}, To: StateReady},
+		{Name: "locked-second", From: []State{StateLocked}, To: StateReady},
+		{Name: "learn-secret", From: []State{StateReady}, Stay: true},
+		{Name: "redeem", From: []State{StateReady}, To: StateRedeemed},
+		{Name: "refund", From: []State{StateLocked, StateReady}, To: StateRefunded},
+	},
+	Err: ErrWrongState,
+}
+
+var (
+	// ErrWrongState is returned when a protocol step is called out of order or by the wrong role
+	ErrWrongState = errors.New("protocol step called out of order")
+	// ErrSwapMismatch is returned for messages of another swap
+	ErrSwapMismatch = errors.New("message belongs to another swap")
+	// ErrInvalidPreSignature is returned when a counterparty's adaptor signature does not verify
+	ErrInvalidPreSignature = errors.New("invalid adaptor signature")
+	// ErrSecretNotFound is returned when a transaction does not reveal the swap secret
+	ErrSecretNotFound = errors.New("transaction does not reveal the swap secret")
+)
+
+// Terms are the amounts, timelocks and fee both parties agree on
+type Terms struct {
+	InitiatorAmount    int64  `json:"initiator_amount"`    // Satoshis the initiator locks
+	ParticipantAmount  int64  `json:"participant_amount"`  // Satoshis the participant locks
+	InitiatorTimeout   uint16 `json:"initiator_timeout"`   // Blocks before the initiator can refund
+	ParticipantTimeout uint16 `json:"participant_timeout"` // Blocks before the participant can refund
+	Fee                int64  `json:"fee"`                 // Fee of every redeem and refund transaction
+}
+
+// Validate checks the terms
+func (t Terms) Validate() error {
+	if t.InitiatorAmount <= t.Fee || t.ParticipantAmount <= t.Fee {
+		return errors.New("both amounts must exceed the fee")
+	}
+	if t.Fee < 0 {
+		return errors.New("fee cannot be negative")
+	}
+	if t.ParticipantTimeout == 0 || t.InitiatorTimeout <= t.ParticipantTimeout {
+		return errors.New("the initiator's timeout must be longer than the participant's")
+	}
+	return nil
+}
+
+// Offer is the initiator's first message
+type Offer struct {
+	ID           string                 `json:"id"`
+	Terms        Terms                  `json:"terms"`
+	InitiatorKey schnorr.XOnlyPublicKey `json:"initiator_key"`
+	AdaptorPoint []byte                 `json:"adaptor_point"` // Compressed T = t·G
+	Payout       []byte                 `json:"payout"`        // scriptPubKey paid by the participant's lock
+}
+
+// Accept is the participant's answer to an Offer
+type Accept struct {
+	ID             string                 `json:"id"`
+	ParticipantKey schnorr.XOnlyPublicKey `json:"participant_key"`
+	Payout         []byte                 `json:"payout"` // scriptPubKey paid by the initiator's lock
+}
+
+// Locked announces a funded lock output and carries the owner's adaptor signature on its redeem
+type Locked struct {
+	ID           string `json:"id"`
+	TxID         string `json:"txid"` // Funding transaction, in display order
+	Vout         uint32 `json:"vout"`
+	PreSignature []byte `json:"pre_signature"` // R (33) || T (33) || s' (32)
+}
+
+// Swap is one party's view of an atomic swap
+type Swap struct {
+	role    Role
+	machine *fsm.Machine[State]
+	id      string
+	terms   Terms
+	priv    *btcec.PrivateKey
+
+	secret       [32]byte
+	hasSecret    bool
+	adaptorPoint *btcec.PublicKey
+
+	ownKey, counterKey       schnorr.XOnlyPublicKey
+	ownPayout, counterPayout []byte
+	ownLock, counterLock     *LockOutput
+	ownOutPoint              tx.OutPoint
+	counterOutPoint          tx.OutPoint
+	ownPreSig                *schnorr.AdaptorSignature // Sent: our signature on the counterparty's redeem
+	counterPreSig            *schnorr.AdaptorSignature // Received: the counterparty's signature on our redeem
+}
+
+// NewInitiator starts a swap and returns the offer to send
+//
+// payout is the scriptPubKey the participant's coins are paid to.
+//
+// Example:
+//
+//	alice, offer, err := swap.NewInitiator(aliceKey, terms, alicePayout)
+//	// send offer to Bob
+func NewInitiator(priv *btcec.PrivateKey, terms Terms, payout []byte) (*Swap, *Offer, error) {
+	// Step 1: Validate inputs
+	if priv == nil {
+		return nil, nil, errors.New("private key cannot be nil")
+	}
+	if err := terms.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if len(payout) == 0 {
+		return nil, nil, errors.New("payout script cannot be empty")
+	}
+
+	// Step 2: Random swap ID and secret t
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate swap id: %w", err)
+	}
+	t, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate swap secret: %w", err)
+	}
+
+	s := &Swap{
+		role:         Initiator,
+		machine:      fsm.New(swapLifecycle),
+		id:           hex.EncodeToString(id[:]),
+		terms:        terms,
+		priv:         priv,
+		secret:       t.Key.Bytes(),
+		hasSecret:    true,
+		adaptorPoint: t.PubKey(),
+		ownKey:       schnorr.XOnlyFromPub(priv.PubKey()),
+		ownPayout:    append([]byte(nil), payout...),
+	}
+	offer := &Offer{
+		ID:           s.id,
+		Terms:        terms,
+		InitiatorKey: s.ownKey,
+		AdaptorPoint: s.adaptorPoint.SerializeCompressed(),
+		Payout:       s.ownPayout,
+	}
+	return s, offer, nil
+}
+
+// NewParticipant accepts an offer and returns the answer to send
+//
+// payout is the scriptPubKey the initiator's coins are paid to.
+func NewParticipant(priv *btcec.PrivateKey, offer *Offer, payout []byte) (*Swap, *Accept, error) {
+	// Step 1: Validate inputs
+	if priv == nil || offer == nil {
+		return nil, nil, errors.New("private key and offer cannot be nil")
+	}
+	if err := offer.Terms.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if len(payout) == 0 || len(offer.Payout) == 0 {
+		return nil, nil, errors.New("payout scripts cannot be empty")
+	}
+	T, err := btcec.ParsePubKey(offer.AdaptorPoint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid adaptor point: %w", err)
+	}
+
+	// Step 2: Both lock outputs are now determined
+	s := &Swap{
+		role:          Participant,
+		machine:       fsm.New(swapLifecycle),
+		id:            offer.ID,
+		terms:         offer.Terms,
+		priv:          priv,
+		adaptorPoint:  T,
+		ownKey:        schnorr.XOnlyFromPub(priv.PubKey()),
+		counterKey:    offer.InitiatorKey,
+		ownPayout:     append([]byte(nil), payout...),
+		counterPayout: append([]byte(nil), offer.Payout...),
+	}
+	if err := s.buildLocks(); err != nil {
+		return nil, nil, err
+	}
+	_ = s.machine.Fire("accept")
+	return s, &Accept{ID: s.id, ParticipantKey: s.ownKey, Payout: s.ownPayout}, nil
+}
+
+// HandleAccept records the participant's answer (initiator only)
+func (s *Swap) HandleAccept(accept *Accept) error {
+	if s.role != Initiator {
+		return ErrWrongState
+	}
+	if err := s.machine.Check("accept"); err != nil {
+		return err
+	}
+	if accept == nil || accept.ID != s.id {
+		return ErrSwapMismatch
+	}
+	if len(accept.Payout) == 0 {
+		return errors.New("payout script cannot be empty")
+	}
+	s.counterKey = accept.ParticipantKey
+	s.counterPayout = append([]byte(nil), accept.Payout...)
+	if err := s.buildLocks(); err != nil {
+		return err
+	}
+	return s.machine.Fire("accept")
+}
+
+// LockOutput returns the output our coins must be sent to
+func (s *Swap) LockOutput() (*LockOutput, error) {
+	if s.ownLock == nil {
+		return nil, ErrWrongState
+	}
+	return s.ownLock, nil
+}
+
+// CounterpartyLockOutput returns the output the counterparty must fund; check it on chain before going ahead
+func (s *Swap) CounterpartyLockOutput() (*LockOutput, error) {
+	if s.counterLock == nil {
+		return nil, ErrWrongState
+	}
+	return s.counterLock, nil
+}
+
+// Lock records the funding of our lock output and returns the message to send
+//
+// The message carries our adaptor signature on the counterparty's redeem
+// transaction; the participant may only lock after the initiator did.
+//
+// Example:
+//
+//	lock, _ := alice.LockOutput()
+//	// fund lock.Address(params) with lock.Value satoshis, then:
+//	msg, err := alice.Lock(tx.OutPoint{Hash: fundingTxID, Index: 0})
+func (s *Swap) Lock(outpoint tx.OutPoint) (*Locked, error) {
+	// Step 1: The initiator locks first
+	event := "lock-first"
+	if s.role == Participant {
+		event = "lock-second"
+	}
+	if err := s.machine.Check(event); err != nil {
+		return nil, err
+	}
+
+	// Step 2: Adaptor-sign the counterparty's redeem of our coins
+	_, digest, err := s.ownLock.RedeemTemplate(outpoint, s.counterPayout, s.terms.Fee)
+	if err != nil {
+		return nil, err
+	}
+	preSig, err := schnorr.AdaptorSignDigest(digest, s.priv, s.adaptorPoint)
+	if err != nil {
+		return nil, err
+	}
+	s.ownOutPoint = outpoint
+	s.ownPreSig = preSig
+	_ = s.machine.Fire(event)
+
+	txid := hash.Reverse32(outpoint.Hash)
+	return &Locked{
+		ID:           s.id,
+		TxID:         hex.EncodeToString(txid[:]),
+		Vout:         outpoint.Index,
+		PreSignature: serializeAdaptor(preSig),
+	}, nil
+}
+
+// HandleLocked records the counterparty's lock and checks its adaptor signature on our redeem
+func (s *Swap) HandleLocked(msg *Locked) error {
+	// Step 1: Check the state and message
+	event := "locked-second"
+	if s.role == Participant {
+		event = "locked-first"
+	}
+	if err := s.machine.Check(event); err != nil {
+		return err
+	}
+	if msg == nil || msg.ID != s.id {
+		return ErrSwapMismatch
+	}
+	outpoint, err := parseOutPoint(msg.TxID, msg.Vout)
+	if err != nil {
+		return err
+	}
+	preSig, err := parseAdaptor(msg.PreSignature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPreSignature, err)
+	}
+
+	// Step 2: The adaptor signature must be the counterparty's, over our redeem, locked by T
+	_, digest, err := s.counterLock.RedeemTemplate(outpoint, s.ownPayout, s.terms.Fee)
+	if err != nil {
+		return err
+	}
+	counterPub, err := s.counterKey.PublicKey()
+	if err != nil {
+		return err
+	}
+	if !schnorr.VerifyAdaptorDigest(digest, counterPub, s.adaptorPoint, preSig) {
+		return ErrInvalidPreSignature
+	}
+	s.counterOutPoint = outpoint
+	s.counterPreSig = preSig
+	return s.machine.Fire(event)
+}
+
+// LearnSecret extracts t from the initiator's published redeem of our coins (participant only)
+//
+// Example:
+//
+//	// Alice's redeem of Bob's lock output appeared on chain
+//	if err := bob.LearnSecret(aliceRedeem); err == nil {
+//		redeemA, _ := bob.Redeem()
+//	}
+func (s *Swap) LearnSecret(published *tx.Transaction) error {
+	if s.role != Participant {
+		return ErrWrongState
+	}
+	if err := s.machine.Check("learn-secret"); err != nil {
+		return err
+	}
+	if published == nil {
+		return ErrSecretNotFound
+	}
+
+	// Our completed signature is the first witness item of the input spending our lock
+	for _, in := range published.Inputs {
+		if in.PreviousOutPoint != s.ownOutPoint || len(in.Witness) != 4 || len(in.Witness[0]) != 64 {
+			continue
+		}
+		secret, err := schnorr.ExtractSecret([64]byte(in.Witness[0]), s.ownPreSig)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrSecretNotFound, err)
+		}
+		s.secret, s.hasSecret = secret, true
+		return nil
+	}
+	return ErrSecretNotFound
+}
+
+// Redeem builds the signed transaction taking the counterparty's coins
+//
+// The initiator can redeem as soon as the swap is ready; the participant
+// first needs the secret from LearnSecret.
+func (s *Swap) Redeem() (*tx.Transaction, error) {
+	// Step 1: Check the state
+	if err := s.machine.Check("redeem"); err != nil {
+		return nil, err
+	}
+	if !s.hasSecret {
+		return nil, fmt.Errorf("%w: the secret is not known yet", ErrWrongState)
+	}
+
+	// Step 2: Complete the counterparty's signature and add ours
+	tmpl, digest, err := s.counterLock.RedeemTemplate(s.counterOutPoint, s.ownPayout, s.terms.Fee)
+	if err != nil {
+		return nil, err
+	}
+	ownerSig, err := s.counterPreSig.Adapt(s.secret)
+	if err != nil {
+		return nil, err
+	}
+	claimerSig, err := signDigest(digest, s.priv)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Witness: <owner sig> <claimer sig> <redeem script> <control block>
+	if err := tmpl.SetSignature(0, 0, ownerSig[:]); err != nil {
+		return nil, err
+	}
+	if err := tmpl.SetSignature(0, 1, claimerSig); err != nil {
+		return nil, err
+	}
+	redeem, err := tmpl.Finalize()
+	if err != nil {
+		return nil, err
+	}
+	_ = s.machine.Fire("redeem")
+	return redeem, nil
+}
+
+// Refund builds the signed transaction returning our coins to refund after the timeout
+//
+// It can be called any time after Lock unless we redeemed; the transaction is
+// only accepted by the network once the lock output is old enough.
+func (s *Swap) Refund(refund []byte) (*tx.Transaction, error) {
+	// Step 1: Check the state
+	if err := s.machine.Check("refund"); err != nil {
+		return nil, err
+	}
+
+	// Step 2: Sign the refund leaf
+	tmpl, digest, err := s.ownLock.RefundTemplate(s.ownOutPoint, refund, s.terms.Fee)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signDigest(digest, s.priv)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.SetSignature(0, 0, sig); err != nil {
+		return nil, err
+	}
+	refundTx, err := tmpl.Finalize()
+	if err != nil {
+		return nil, err
+	}
+	_ = s.machine.Fire("refund")
+	return refundTx, nil
+}
+
+// Role returns the side of the swap
+func (s *Swap) Role() Role { return s.role }
+
+// State returns the progress of the swap
+func (s *Swap) State() State { return s.machine.State() }
+
+// ID returns the swap identifier shared by both parties
+func (s *Swap) ID() string { return s.id }
+
+// buildLocks derives both lock outputs from the terms and keys
+func (s *Swap) buildLocks() error {
+	ownAmount, ownTimeout := s.terms.InitiatorAmount, s.terms.InitiatorTimeout
+	counterAmount, counterTimeout := s.terms.ParticipantAmount, s.terms.ParticipantTimeout
+	if s.role == Participant {
+		ownAmount, counterAmount = counterAmount, ownAmount
+		ownTimeout, counterTimeout = counterTimeout, ownTimeout
+	}
+
+	var err error
+	if s.ownLock, err = NewLockOutput(s.ownKey, s.counterKey, ownTimeout, ownAmount); err != nil {
+		return err
+	}
+	s.counterLock, err = NewLockOutput(s.counterKey, s.ownKey, counterTimeout, counterAmount)
+	return err
+}
+
+// serializeAdaptor encodes an adaptor signature as R || T || s'
+func serializeAdaptor(a *schnorr.AdaptorSignature) []byte {
+	out := make([]byte, 0, 98)
+	out = append(out, a.R[:]...)
+	out = append(out, a.T[:]...)
+	return append(out, a.S[:]...)
+}
+
+// parseAdaptor decodes an adaptor signature serialized with serializeAdaptor
+func parseAdaptor(b []byte) (*schnorr.AdaptorSignature, error) {
+	if len(b) != 98 {
+		return nil, fmt.Errorf("adaptor signature must be 98 bytes, got %d", len(b))
+	}
+	return &schnorr.AdaptorSignature{R: [33]byte(b[:33]), T: [33]byte(b[33:66]), S: [32]byte(b[66:])}, nil
+}
+
+// parseOutPoint parses a display-order txid and output index
+func parseOutPoint(txid string, vout uint32) (tx.OutPoint, error) {
+	b, err := hex.DecodeString(txid)
+	if err != nil || len(b) != 32 {
+		return tx.OutPoint{}, fmt.Errorf("invalid txid %q", txid)
+	}
+	return tx.OutPoint{Hash: hash.Reverse32([32]byte(b)), Index: vout}, nil
+}
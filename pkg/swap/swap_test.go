@@ -0,0 +1,262 @@
+package swap
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/fsm"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+)
+
+var testTerms = Terms{
+	InitiatorAmount:    100000,
+	ParticipantAmount:  250000,
+	InitiatorTimeout:   288,
+	ParticipantTimeout: 144,
+	Fee:                500,
+}
+
+// roundTrip sends a message through JSON like a real transport would
+func roundTrip[T any](t *testing.T, msg *T) *T {
+	t.Helper()
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var out T
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	return &out
+}
+
+// setupSwap runs the protocol up to both parties being ready
+func setupSwap(t *testing.T) (alice, bob *Swap) {
+	t.Helper()
+	alicePriv, _ := testKey(0x01)
+	bobPriv, _ := testKey(0x02)
+	alicePayout := append([]byte{tx.OP_0, 0x14}, make([]byte, 20)...)
+	bobPayout := append([]byte{tx.OP_1, 0x20}, make([]byte, 32)...)
+
+	alice, offer, err := NewInitiator(alicePriv, testTerms, alicePayout)
+	if err != nil {
+		t.Fatalf("NewInitiator failed: %v", err)
+	}
+	bob, accept, err := NewParticipant(bobPriv, roundTrip(t, offer), bobPayout)
+	if err != nil {
+		t.Fatalf("NewParticipant failed: %v", err)
+	}
+	if err := alice.HandleAccept(roundTrip(t, accept)); err != nil {
+		t.Fatalf("HandleAccept failed: %v", err)
+	}
+
+	// Both parties derive the same two lock outputs
+	aliceLock, _ := alice.LockOutput()
+	bobView, _ := bob.CounterpartyLockOutput()
+	if aliceLock.OutputKey != bobView.OutputKey || aliceLock.Value != testTerms.InitiatorAmount {
+		t.Fatal("Expected both parties to agree on the initiator's lock output")
+	}
+
+	// Alice locks first, then Bob
+	lockedA, err := alice.Lock(tx.OutPoint{Hash: [32]byte{0xaa}, Index: 0})
+	if err != nil {
+		t.Fatalf("Lock (initiator) failed: %v", err)
+	}
+	if _, err := bob.Lock(tx.OutPoint{Hash: [32]byte{0xbb}}); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState for the participant locking first, got %v", err)
+	}
+	if err := bob.HandleLocked(roundTrip(t, lockedA)); err != nil {
+		t.Fatalf("HandleLocked (participant) failed: %v", err)
+	}
+	lockedB, err := bob.Lock(tx.OutPoint{Hash: [32]byte{0xbb}, Index: 1})
+	if err != nil {
+		t.Fatalf("Lock (participant) failed: %v", err)
+	}
+	if err := alice.HandleLocked(roundTrip(t, lockedB)); err != nil {
+		t.Fatalf("HandleLocked (initiator) failed: %v", err)
+	}
+	if alice.State() != StateReady || bob.State() != StateReady {
+		t.Fatalf("Expected both parties ready, got %s and %s", alice.State(), bob.State())
+	}
+	return alice, bob
+}
+
+// checkRedeem checks both signatures of a redeem transaction against the lock output it spends
+func checkRedeem(t *testing.T, redeem *tx.Transaction, lock *LockOutput, outpoint tx.OutPoint, payout []byte) {
+	t.Helper()
+	_, digest, err := lock.RedeemTemplate(outpoint, payout, testTerms.Fee)
+	if err != nil {
+		t.Fatalf("RedeemTemplate failed: %v", err)
+	}
+	witness := redeem.Inputs[0].Witness
+	if len(witness) != 4 {
+		t.Fatalf("Expected 4 witness items, got %d", len(witness))
+	}
+	owner, _ := lock.Owner.PublicKey()
+	claimer, _ := lock.Claimer.PublicKey()
+	if !schnorr.VerifyDigest(digest, owner, [64]byte(witness[0])) {
+		t.Error("Expected a valid owner signature")
+	}
+	if !schnorr.VerifyDigest(digest, claimer, [64]byte(witness[1])) {
+		t.Error("Expected a valid claimer signature")
+	}
+}
+
+func TestSwapHappyPath(t *testing.T) {
+	alice, bob := setupSwap(t)
+
+	// Bob cannot redeem before Alice reveals the secret
+	if _, err := bob.Redeem(); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState before the secret is known, got %v", err)
+	}
+
+	// Alice redeems Bob's coins
+	redeemB, err := alice.Redeem()
+	if err != nil {
+		t.Fatalf("Redeem (initiator) failed: %v", err)
+	}
+	checkRedeem(t, redeemB, bob.ownLock, bob.ownOutPoint, alice.ownPayout)
+	if alice.State() != StateRedeemed {
+		t.Errorf("Expected state redeemed, got %s", alice.State())
+	}
+
+	// Bob learns t from the published transaction and redeems Alice's coins
+	if err := bob.LearnSecret(redeemB); err != nil {
+		t.Fatalf("LearnSecret failed: %v", err)
+	}
+	if bob.secret != alice.secret {
+		t.Fatal("Expected Bob to learn Alice's secret")
+	}
+	redeemA, err := bob.Redeem()
+	if err != nil {
+		t.Fatalf("Redeem (participant) failed: %v", err)
+	}
+	checkRedeem(t, redeemA, alice.ownLock, alice.ownOutPoint, bob.ownPayout)
+	if redeemA.Outputs[0].Value != testTerms.InitiatorAmount-testTerms.Fee {
+		t.Errorf("Expected Bob to receive %d, got %d", testTerms.InitiatorAmount-testTerms.Fee, redeemA.Outputs[0].Value)
+	}
+
+	// Redeeming twice is a protocol error
+	if _, err := alice.Redeem(); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState, got %v", err)
+	}
+}
+
+func TestSwapRefund(t *testing.T) {
+	alicePriv, _ := testKey(0x01)
+	bobPriv, _ := testKey(0x02)
+	payout := append([]byte{tx.OP_0, 0x14}, make([]byte, 20)...)
+
+	// Bob never locks: Alice takes her coins back
+	alice, offer, _ := NewInitiator(alicePriv, testTerms, payout)
+	_, accept, _ := NewParticipant(bobPriv, offer, payout)
+	_ = alice.HandleAccept(accept)
+	if _, err := alice.Refund(payout); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState before locking, got %v", err)
+	}
+	outpoint := tx.OutPoint{Hash: [32]byte{0xaa}}
+	if _, err := alice.Lock(outpoint); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	refund, err := alice.Refund(payout)
+	if err != nil {
+		t.Fatalf("Refund failed: %v", err)
+	}
+	lock, _ := alice.LockOutput()
+	_, digest, _ := lock.RefundTemplate(outpoint, payout, testTerms.Fee)
+	if refund.Inputs[0].Sequence != uint32(testTerms.InitiatorTimeout) {
+		t.Errorf("Expected sequence %d, got %d", testTerms.InitiatorTimeout, refund.Inputs[0].Sequence)
+	}
+	if !schnorr.VerifyDigest(digest, alicePriv.PubKey(), [64]byte(refund.Inputs[0].Witness[0])) {
+		t.Error("Expected a valid refund signature")
+	}
+	if alice.State() != StateRefunded {
+		t.Errorf("Expected state refunded, got %s", alice.State())
+	}
+}
+
+func TestSwapRejectsBadMessages(t *testing.T) {
+	alicePriv, _ := testKey(0x01)
+	bobPriv, _ := testKey(0x02)
+	payout := append([]byte{tx.OP_0, 0x14}, make([]byte, 20)...)
+
+	newPair := func() (*Swap, *Swap, *Locked) {
+		alice, offer, _ := NewInitiator(alicePriv, testTerms, payout)
+		bob, accept, _ := NewParticipant(bobPriv, offer, payout)
+		_ = alice.HandleAccept(accept)
+		locked, _ := alice.Lock(tx.OutPoint{Hash: [32]byte{0xaa}})
+		return alice, bob, locked
+	}
+
+	tests := []struct {
+		name     string
+		tamper   func(*Locked)
+		expected error
+	}{
+		{"other swap", func(m *Locked) { m.ID = "00" }, ErrSwapMismatch},
+		{"tampered s", func(m *Locked) { m.PreSignature[97] ^= 0x01 }, ErrInvalidPreSignature},
+		{"other outpoint", func(m *Locked) { m.Vout = 7 }, ErrInvalidPreSignature},
+		{"truncated", func(m *Locked) { m.PreSignature = m.PreSignature[:64] }, ErrInvalidPreSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, bob, locked := newPair()
+			tt.tamper(locked)
+			if err := bob.HandleLocked(locked); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if bob.State() != StateAccepted {
+				t.Errorf("Expected the state to stay accepted, got %s", bob.State())
+			}
+		})
+	}
+
+	// A secret is only found in a transaction spending our lock output
+	alice, bob := setupSwap(t)
+	refund, _ := alice.Refund(payout)
+	if err := bob.LearnSecret(refund); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Expected ErrSecretNotFound, got %v", err)
+	}
+}
+
+func TestTermsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Terms)
+	}{
+		{"amount below fee", func(t *Terms) { t.InitiatorAmount = 400 }},
+		{"negative fee", func(t *Terms) { t.Fee = -1 }},
+		{"zero participant timeout", func(t *Terms) { t.ParticipantTimeout = 0 }},
+		{"participant timeout not shorter", func(t *Terms) { t.ParticipantTimeout = t.InitiatorTimeout }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms := testTerms
+			tt.modify(&terms)
+			if err := terms.Validate(); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+	if err := testTerms.Validate(); err != nil {
+		t.Errorf("Expected valid terms, got %v", err)
+	}
+}
+
+func TestSwapLifecycle(t *testing.T) {
+	if err := swapLifecycle.Validate(); err != nil {
+		t.Fatalf("Invalid swap lifecycle: %v", err)
+	}
+
+	// A role cannot take the other role's path through the states
+	alice, bob := setupSwap(t)
+	if err := alice.LearnSecret(nil); !errors.Is(err, ErrWrongState) {
+		t.Errorf("Expected ErrWrongState for the initiator learning the secret, got %v", err)
+	}
+	if err := bob.HandleAccept(&Accept{ID: bob.ID()}); !errors.Is(err, ErrWrongState) || !errors.Is(bob.HandleLocked(nil), fsm.ErrIllegalTransition) {
+		t.Errorf("Expected illegal transitions once ready, got %v", err)
+	}
+}
@@ -0,0 +1,323 @@
+package tecdsa
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+	"github.com/neverDefined/cryptography-playground/pkg/fsm"
+	"github.com/neverDefined/cryptography-playground/pkg/paillier"
+)
+
+// KeyGenRound1 is broadcast by every party in the first key generation round
+type KeyGenRound1 struct {
+	From        int      `json:"from"`
+	PaillierN   *big.Int `json:"paillier_n"`
+	Commitments [][]byte `json:"commitments"` // C_ik = a_ik*G, compressed, k = 0..t-1
+}
+
+// KeyGenRound2 carries party From's share f_From(To) to party To
+type KeyGenRound2 struct {
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+	Share []byte `json:"share"` // 32-byte big-endian scalar
+}
+
+// KeyGenState is the round a key generation is in
+type KeyGenState int
+
+const (
+	// KeyGenStart waits for Round1
+	KeyGenStart KeyGenState = iota
+	// KeyGenCommitted sent the commitments and waits for everyone else's
+	KeyGenCommitted
+	// KeyGenShared sent the shares and waits for everyone else's
+	KeyGenShared
+	// KeyGenDone produced the key share
+	KeyGenDone
+)
+
+// String returns the name of the key generation state
+func (s KeyGenState) String() string {
+	switch s {
+	case KeyGenStart:
+		return "start"
+	case KeyGenCommitted:
+		return "committed"
+	case KeyGenShared:
+		return "shared"
+	case KeyGenDone:
+		return "done"
+	default:
+		return fmt.Sprintf("KeyGenState(%d)", int(s))
+	}
+}
+
+// keyGenLifecycle is the state machine every KeyGen follows
+var keyGenLifecycle = &fsm.Definition[KeyGenState]{
+	Name:    "tecdsa-keygen",
+	Initial: KeyGenStart,
+	Final:   []KeyGenState{KeyGenDone},
+	Events: []fsm.Event[KeyGenState]{
+		{Name: "round1", From: []KeyGenState{KeyGenStart}, To: KeyGenCommitted},
+		{Name: "round2", From: []KeyGenState{KeyGenCommitted}, To: KeyGenShared},
+		{Name: "finish", From: []KeyGenState{KeyGenShared}, To: KeyGenDone},
+	},
+	Err: ErrWrongRound,
+}
+
+// KeyGen is one party's state during distributed key generation
+type KeyGen struct {
+	params       Params
+	index        int
+	machine      *fsm.Machine[KeyGenState]
+	paillier     *paillier.PrivateKey
+	coefficients []*big.Int
+	round1       map[int]*KeyGenRound1
+}
+
+// Key is one party's long-term share of a threshold key
+type Key struct {
+	Params       Params
+	Index        int
+	share        *big.Int
+	paillier     *paillier.PrivateKey
+	paillierKeys map[int]*paillier.PublicKey // Other parties' Paillier keys
+	publicShares map[int]*btcec.JacobianPoint
+	publicKey    *btcec.JacobianPoint
+}
+
+// NewKeyGen creates the key generation state for party index (1-based)
+//
+// Example:
+//
+//	params := tecdsa.Params{Threshold: 2, Parties: 3}
+//	kg, _ := tecdsa.NewKeyGen(params, 1, tecdsa.DefaultPaillierBits)
+//	r1, _ := kg.Round1()           // broadcast
+//	shares, _ := kg.Round2(all r1) // send shares[j] to its To party
+//	key, _ := kg.Finish(received shares)
+func NewKeyGen(params Params, index int, paillierBits int) (*KeyGen, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if index < 1 || index > params.Parties {
+		return nil, fmt.Errorf("party index %d out of range [1, %d]", index, params.Parties)
+	}
+	if paillierBits < minPaillierBits {
+		return nil, fmt.Errorf("paillier modulus must be at least %d bits", minPaillierBits)
+	}
+
+	sk, err := paillier.GenerateKey(rand.Reader, paillierBits)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyGen{params: params, index: index, machine: fsm.New(keyGenLifecycle), paillier: sk}, nil
+}
+
+// Round1 samples the secret polynomial and returns its Feldman commitments
+func (kg *KeyGen) Round1() (*KeyGenRound1, error) {
+	if err := kg.machine.Check("round1"); err != nil {
+		return nil, err
+	}
+
+	// Step 1: Random polynomial f_i of degree t-1; f_i(0) is this party's contribution
+	msg := &KeyGenRound1{From: kg.index, PaillierN: new(big.Int).Set(kg.paillier.N)}
+	for k := 0; k < kg.params.Threshold; k++ {
+		a, err := arithmetic.RandScalar()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate coefficient: %w", err)
+		}
+		kg.coefficients = append(kg.coefficients, a)
+
+		// Step 2: Commit to each coefficient
+		msg.Commitments = append(msg.Commitments, serializePoint(baseMult(a)))
+	}
+
+	_ = kg.machine.Fire("round1")
+	return msg, nil
+}
+
+// Round2 records every party's commitments and returns the shares for the other parties
+func (kg *KeyGen) Round2(msgs []*KeyGenRound1) ([]*KeyGenRound2, error) {
+	if err := kg.machine.Check("round2"); err != nil {
+		return nil, err
+	}
+
+	// Step 1: Exactly one well-formed message from every party
+	round1 := make(map[int]*KeyGenRound1, kg.params.Parties)
+	for _, msg := range msgs {
+		if msg == nil || msg.From < 1 || msg.From > kg.params.Parties {
+			return nil, fmt.Errorf("invalid round 1 message sender")
+		}
+		if _, dup := round1[msg.From]; dup {
+			return nil, fmt.Errorf("duplicate round 1 message from party %d", msg.From)
+		}
+		if len(msg.Commitments) != kg.params.Threshold {
+			return nil, fmt.Errorf("party %d sent %d commitments, expected %d", msg.From, len(msg.Commitments), kg.params.Threshold)
+		}
+		if msg.PaillierN == nil || msg.PaillierN.BitLen() < minPaillierBits {
+			return nil, fmt.Errorf("party %d sent a too small Paillier modulus", msg.From)
+		}
+		round1[msg.From] = msg
+	}
+	if len(round1) != kg.params.Parties {
+		return nil, fmt.Errorf("expected %d round 1 messages, got %d", kg.params.Parties, len(round1))
+	}
+
+	// Step 2: Evaluate f_i(j) for every other party j
+	var shares []*KeyGenRound2
+	for j := 1; j <= kg.params.Parties; j++ {
+		if j == kg.index {
+			continue
+		}
+		share := kg.evaluate(j)
+		shares = append(shares, &KeyGenRound2{From: kg.index, To: j, Share: share.FillBytes(make([]byte, 32))})
+	}
+
+	kg.round1 = round1
+	_ = kg.machine.Fire("round2")
+	return shares, nil
+}
+
+// Finish verifies the received shares and assembles the key share
+func (kg *KeyGen) Finish(shares []*KeyGenRound2) (*Key, error) {
+	if err := kg.machine.Check("finish"); err != nil {
+		return nil, err
+	}
+
+	// Step 1: Own share f_i(i)
+	x := kg.evaluate(kg.index)
+	received := map[int]bool{kg.index: true}
+
+	// Step 2: Add every other party's share after checking it against their commitments
+	for _, msg := range shares {
+		if msg == nil || msg.To != kg.index || received[msg.From] || kg.round1[msg.From] == nil {
+			return nil, fmt.Errorf("unexpected key share message")
+		}
+		share := new(big.Int).SetBytes(msg.Share)
+		commitments, err := parseCommitments(kg.round1[msg.From].Commitments)
+		if err != nil {
+			return nil, fmt.Errorf("party %d: %w", msg.From, err)
+		}
+		expected := evaluateCommitments(commitments, kg.index)
+		actual := baseMult(share)
+		if !equalPoints(expected, actual) {
+			return nil, fmt.Errorf("party %d: %w", msg.From, ErrInvalidShare)
+		}
+		x = arithmetic.AddModN(x, share)
+		received[msg.From] = true
+	}
+	if len(received) != kg.params.Parties {
+		return nil, fmt.Errorf("expected %d key shares, got %d", kg.params.Parties-1, len(received)-1)
+	}
+
+	// Step 3: Public key Q = Σ C_i0 and public shares X_j = Σ_i f_i(j)*G
+	key := &Key{
+		Params:       kg.params,
+		Index:        kg.index,
+		share:        x,
+		paillier:     kg.paillier,
+		paillierKeys: make(map[int]*paillier.PublicKey),
+		publicShares: make(map[int]*btcec.JacobianPoint),
+	}
+	all := make(map[int][]*btcec.JacobianPoint)
+	for i, msg := range kg.round1 {
+		commitments, err := parseCommitments(msg.Commitments)
+		if err != nil {
+			return nil, fmt.Errorf("party %d: %w", i, err)
+		}
+		all[i] = commitments
+		if key.publicKey == nil {
+			key.publicKey = commitments[0]
+		} else {
+			key.publicKey = addPoints(key.publicKey, commitments[0])
+		}
+		if i != kg.index {
+			pk, err := paillier.NewPublicKey(msg.PaillierN)
+			if err != nil {
+				return nil, fmt.Errorf("party %d: %w", i, err)
+			}
+			key.paillierKeys[i] = pk
+		}
+	}
+	for j := 1; j <= kg.params.Parties; j++ {
+		var xj *btcec.JacobianPoint
+		for _, commitments := range all {
+			point := evaluateCommitments(commitments, j)
+			if xj == nil {
+				xj = point
+			} else {
+				xj = addPoints(xj, point)
+			}
+		}
+		key.publicShares[j] = xj
+	}
+
+	// Step 4: Erase the polynomial
+	for _, a := range kg.coefficients {
+		arithmetic.ZeroBigInt(a)
+	}
+	_ = kg.machine.Fire("finish")
+	return key, nil
+}
+
+// State returns the current round of the key generation
+func (kg *KeyGen) State() KeyGenState {
+	return kg.machine.State()
+}
+
+// PublicKey returns the joint public key
+func (k *Key) PublicKey() *btcec.PublicKey {
+	return toPublicKey(k.publicKey)
+}
+
+// PublicShare returns X_j = x_j*G for party j, or nil if j is unknown
+func (k *Key) PublicShare(j int) *btcec.PublicKey {
+	p, ok := k.publicShares[j]
+	if !ok {
+		return nil
+	}
+	return toPublicKey(p)
+}
+
+// evaluate computes f_i(z) = Σ a_k z^k mod n
+func (kg *KeyGen) evaluate(z int) *big.Int {
+	result := new(big.Int)
+	zBig := big.NewInt(int64(z))
+	for k := len(kg.coefficients) - 1; k >= 0; k-- {
+		result = arithmetic.AddModN(arithmetic.MulModN(result, zBig), kg.coefficients[k])
+	}
+	return result
+}
+
+// parseCommitments parses a list of compressed commitment points
+func parseCommitments(data [][]byte) ([]*btcec.JacobianPoint, error) {
+	points := make([]*btcec.JacobianPoint, len(data))
+	for i, d := range data {
+		p, err := parsePoint(d)
+		if err != nil {
+			return nil, fmt.Errorf("invalid commitment %d: %w", i, err)
+		}
+		points[i] = p
+	}
+	return points, nil
+}
+
+// evaluateCommitments computes Σ C_k z^k, the public image of f(z)
+func evaluateCommitments(commitments []*btcec.JacobianPoint, z int) *btcec.JacobianPoint {
+	result := commitments[0]
+	power := big.NewInt(1)
+	zBig := big.NewInt(int64(z))
+	for k := 1; k < len(commitments); k++ {
+		power = arithmetic.MulModN(power, zBig)
+		result = addPoints(result, scalarMult(power, commitments[k]))
+	}
+	return result
+}
+
+// equalPoints compares two Jacobian points
+func equalPoints(a, b *btcec.JacobianPoint) bool {
+	return toPublicKey(a).IsEqual(toPublicKey(b))
+}
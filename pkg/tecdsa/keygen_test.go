@@ -0,0 +1,148 @@
+package tecdsa
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+)
+
+// testPaillierBits keeps key generation fast in tests
+const testPaillierBits = 1024
+
+// runKeyGen runs distributed key generation for all parties
+func runKeyGen(t *testing.T, params Params) []*Key {
+	t.Helper()
+	keygens := make([]*KeyGen, params.Parties)
+	var round1 []*KeyGenRound1
+	for i := range keygens {
+		kg, err := NewKeyGen(params, i+1, testPaillierBits)
+		if err != nil {
+			t.Fatalf("NewKeyGen failed: %v", err)
+		}
+		msg, err := kg.Round1()
+		if err != nil {
+			t.Fatalf("Round1 failed: %v", err)
+		}
+		keygens[i] = kg
+		round1 = append(round1, msg)
+	}
+
+	inbox := make(map[int][]*KeyGenRound2)
+	for _, kg := range keygens {
+		shares, err := kg.Round2(round1)
+		if err != nil {
+			t.Fatalf("Round2 failed: %v", err)
+		}
+		for _, share := range shares {
+			inbox[share.To] = append(inbox[share.To], share)
+		}
+	}
+
+	keys := make([]*Key, params.Parties)
+	for i, kg := range keygens {
+		key, err := kg.Finish(inbox[i+1])
+		if err != nil {
+			t.Fatalf("Finish failed for party %d: %v", i+1, err)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// mulAdd computes a*x + b*y mod n
+func mulAdd(a, x, b, y *big.Int) *big.Int {
+	return arithmetic.AddModN(arithmetic.MulModN(a, x), arithmetic.MulModN(b, y))
+}
+
+func TestKeyGen(t *testing.T) {
+	params := Params{Threshold: 2, Parties: 3}
+	keys := runKeyGen(t, params)
+
+	// Every party agrees on the joint key and the public shares
+	for _, key := range keys[1:] {
+		if !key.PublicKey().IsEqual(keys[0].PublicKey()) {
+			t.Errorf("Party %d derived a different public key", key.Index)
+		}
+		for j := 1; j <= params.Parties; j++ {
+			if !key.PublicShare(j).IsEqual(keys[0].PublicShare(j)) {
+				t.Errorf("Party %d disagrees on public share %d", key.Index, j)
+			}
+		}
+	}
+
+	// Each public share matches the private share
+	for _, key := range keys {
+		if !equalPoints(baseMult(key.share), key.publicShares[key.Index]) {
+			t.Errorf("Public share of party %d does not match its private share", key.Index)
+		}
+	}
+	if keys[0].PublicShare(99) != nil {
+		t.Error("Expected nil for an unknown party")
+	}
+
+	// Any two shares interpolate the same secret, whose public key is Q
+	for _, pair := range [][]int{{1, 2}, {1, 3}, {2, 3}} {
+		x := keys[pair[0]-1].share
+		x = mulAdd(lagrangeCoefficient(pair[0], pair), x, lagrangeCoefficient(pair[1], pair), keys[pair[1]-1].share)
+		if !toPublicKey(baseMult(x)).IsEqual(keys[0].PublicKey()) {
+			t.Errorf("Shares %v do not interpolate the joint key", pair)
+		}
+	}
+}
+
+func TestKeyGenErrors(t *testing.T) {
+	params := Params{Threshold: 2, Parties: 2}
+	if _, err := NewKeyGen(params, 3, testPaillierBits); err == nil {
+		t.Error("Expected error for an out of range index")
+	}
+	if _, err := NewKeyGen(params, 1, 512); err == nil {
+		t.Error("Expected error for a too small Paillier modulus")
+	}
+	if _, err := NewKeyGen(Params{Threshold: 3, Parties: 2}, 1, testPaillierBits); err == nil {
+		t.Error("Expected error for invalid params")
+	}
+
+	kg1, _ := NewKeyGen(params, 1, testPaillierBits)
+	kg2, _ := NewKeyGen(params, 2, testPaillierBits)
+	if _, err := kg1.Round2(nil); !errors.Is(err, ErrWrongRound) {
+		t.Errorf("Expected ErrWrongRound, got %v", err)
+	}
+	m1, _ := kg1.Round1()
+	m2, _ := kg2.Round1()
+	if _, err := kg1.Round1(); !errors.Is(err, ErrWrongRound) {
+		t.Errorf("Expected ErrWrongRound for a repeated round, got %v", err)
+	}
+	if err := keyGenLifecycle.Validate(); err != nil || kg1.State() != KeyGenCommitted {
+		t.Errorf("Expected a valid lifecycle in state committed, got %v in state %s", err, kg1.State())
+	}
+	if _, err := kg1.Round2([]*KeyGenRound1{m1}); err == nil {
+		t.Error("Expected error for a missing round 1 message")
+	}
+	if _, err := kg1.Round2([]*KeyGenRound1{m1, m1}); err == nil {
+		t.Error("Expected error for a duplicate round 1 message")
+	}
+
+	if _, err := kg1.Round2([]*KeyGenRound1{m1, m2}); err != nil {
+		t.Fatalf("Round2 failed: %v", err)
+	}
+	shares, err := kg2.Round2([]*KeyGenRound1{m1, m2})
+	if err != nil {
+		t.Fatalf("Round2 failed: %v", err)
+	}
+
+	// A tampered share fails the Feldman check
+	tampered := *shares[0]
+	tampered.Share = append([]byte(nil), tampered.Share...)
+	tampered.Share[31] ^= 0x01
+	if _, err := kg1.Finish([]*KeyGenRound2{&tampered}); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("Expected ErrInvalidShare, got %v", err)
+	}
+	if _, err := kg1.Finish(nil); err == nil {
+		t.Error("Expected error for missing shares")
+	}
+	if _, err := kg1.Finish(shares); err != nil {
+		t.Errorf("Finish failed: %v", err)
+	}
+}
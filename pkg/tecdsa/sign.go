@@ -0,0 +1,388 @@
+package tecdsa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+	"github.com/neverDefined/cryptography-playground/pkg/paillier"
+)
+
+// SignRound1 is broadcast by every signer: a commitment to Γ_i and Enc_i(k_i)
+type SignRound1 struct {
+	From       int      `json:"from"`
+	Commitment []byte   `json:"commitment"`
+	EncryptedK *big.Int `json:"encrypted_k"`
+}
+
+// SignRound2 carries the MtA responses of party From to party To
+type SignRound2 struct {
+	From            int      `json:"from"`
+	To              int      `json:"to"`
+	GammaCiphertext *big.Int `json:"gamma_ciphertext"` // Enc_To(k_To*γ_From + β')
+	WCiphertext     *big.Int `json:"w_ciphertext"`     // Enc_To(k_To*w_From + ν')
+}
+
+// SignRound3 is broadcast by every signer: its share δ_i of k*γ
+type SignRound3 struct {
+	From  int    `json:"from"`
+	Delta []byte `json:"delta"`
+}
+
+// SignRound4 is broadcast by every signer: Γ_i, opening the round 1 commitment
+type SignRound4 struct {
+	From  int    `json:"from"`
+	Gamma []byte `json:"gamma"`
+}
+
+// SignRound5 is broadcast by every signer: its signature share s_i
+type SignRound5 struct {
+	From int    `json:"from"`
+	S    []byte `json:"s"`
+}
+
+// Signing is one signer's state for producing a single signature
+type Signing struct {
+	key     *Key
+	signers []int
+	hash    [32]byte
+	round   int
+
+	w          *big.Int // λ_i * x_i
+	k          *big.Int
+	gamma      *big.Int
+	gammaPoint *btcec.JacobianPoint
+
+	commitments map[int][]byte
+	beta        *big.Int // Σ β_ij, this party's side of the k_j*γ_i products
+	nu          *big.Int // Σ ν_ij, this party's side of the k_j*w_i products
+	delta       *big.Int
+	sigma       *big.Int
+	deltaSum    *big.Int
+	r           *big.Int
+	share       *big.Int // s_i
+}
+
+// NewSigning creates the signing state for key's owner within the signer set
+//
+// signers lists the party indices taking part; it must contain exactly
+// Threshold parties including key.Index.
+//
+// Example:
+//
+//	s, _ := tecdsa.NewSigning(key, []int{1, 3}, hash)
+//	r1, _ := s.Round1()
+//	r2, _ := s.Round2(all r1)      // p2p, route by To
+//	r3, _ := s.Round3(received r2)
+//	r4, _ := s.Round4(all r3)
+//	r5, _ := s.Round5(all r4)
+//	sig, _ := s.Finalize(all r5)   // *ecdsa.Signature
+func NewSigning(key *Key, signers []int, hash [32]byte) (*Signing, error) {
+	if key == nil {
+		return nil, errors.New("key cannot be nil")
+	}
+	if len(signers) != key.Params.Threshold {
+		return nil, fmt.Errorf("expected %d signers, got %d", key.Params.Threshold, len(signers))
+	}
+	sorted := slices.Clone(signers)
+	slices.Sort(sorted)
+	if len(slices.Compact(slices.Clone(sorted))) != len(sorted) {
+		return nil, errors.New("duplicate signer")
+	}
+	if sorted[0] < 1 || sorted[len(sorted)-1] > key.Params.Parties {
+		return nil, errors.New("signer index out of range")
+	}
+	if !slices.Contains(sorted, key.Index) {
+		return nil, fmt.Errorf("party %d is not in the signer set", key.Index)
+	}
+
+	return &Signing{
+		key:     key,
+		signers: sorted,
+		hash:    hash,
+		w:       arithmetic.MulModN(lagrangeCoefficient(key.Index, sorted), key.share),
+	}, nil
+}
+
+// Round1 samples k_i and γ_i and returns the commitment to Γ_i with Enc_i(k_i)
+func (s *Signing) Round1() (*SignRound1, error) {
+	if s.round != 0 {
+		return nil, ErrWrongRound
+	}
+
+	var err error
+	if s.k, err = arithmetic.RandScalar(); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	if s.gamma, err = arithmetic.RandScalar(); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	s.gammaPoint = baseMult(s.gamma)
+
+	encryptedK, err := s.key.paillier.Encrypt(rand.Reader, s.k)
+	if err != nil {
+		return nil, err
+	}
+
+	s.round = 1
+	return &SignRound1{
+		From:       s.key.Index,
+		Commitment: gammaCommitment(serializePoint(s.gammaPoint)),
+		EncryptedK: encryptedK,
+	}, nil
+}
+
+// Round2 answers every other signer's Enc_j(k_j) with the two MtA responses
+func (s *Signing) Round2(msgs []*SignRound1) ([]*SignRound2, error) {
+	if s.round != 1 {
+		return nil, ErrWrongRound
+	}
+	round1, err := collect(s, msgs, func(m *SignRound1) int { return m.From })
+	if err != nil {
+		return nil, err
+	}
+
+	s.commitments = make(map[int][]byte)
+	s.beta, s.nu = new(big.Int), new(big.Int)
+
+	var out []*SignRound2
+	for _, j := range s.others() {
+		msg := round1[j]
+		pk := s.key.paillierKeys[j]
+		if err := pk.ValidateCiphertext(msg.EncryptedK); err != nil {
+			return nil, fmt.Errorf("party %d: %w", j, err)
+		}
+		s.commitments[j] = msg.Commitment
+
+		// MtA: Enc_j(k_j)^γ_i * Enc_j(β') = Enc_j(k_j*γ_i + β'); this party keeps β = -β'
+		gammaCiphertext, beta, err := mta(pk, msg.EncryptedK, s.gamma)
+		if err != nil {
+			return nil, err
+		}
+		wCiphertext, nu, err := mta(pk, msg.EncryptedK, s.w)
+		if err != nil {
+			return nil, err
+		}
+		s.beta = arithmetic.AddModN(s.beta, beta)
+		s.nu = arithmetic.AddModN(s.nu, nu)
+
+		out = append(out, &SignRound2{From: s.key.Index, To: j, GammaCiphertext: gammaCiphertext, WCiphertext: wCiphertext})
+	}
+
+	s.round = 2
+	return out, nil
+}
+
+// Round3 decrypts the MtA responses and returns δ_i
+func (s *Signing) Round3(msgs []*SignRound2) (*SignRound3, error) {
+	if s.round != 2 {
+		return nil, ErrWrongRound
+	}
+
+	// Only the messages addressed to this party matter
+	var mine []*SignRound2
+	for _, msg := range msgs {
+		if msg != nil && msg.To == s.key.Index {
+			mine = append(mine, msg)
+		}
+	}
+	round2, err := collect(s, mine, func(m *SignRound2) int { return m.From })
+	if err != nil {
+		return nil, err
+	}
+
+	// δ_i = k_i*γ_i + Σ α_ij + Σ β_ij,  σ_i = k_i*w_i + Σ μ_ij + Σ ν_ij
+	delta := arithmetic.AddModN(arithmetic.MulModN(s.k, s.gamma), s.beta)
+	sigma := arithmetic.AddModN(arithmetic.MulModN(s.k, s.w), s.nu)
+	for _, j := range s.others() {
+		alpha, err := s.key.paillier.Decrypt(round2[j].GammaCiphertext)
+		if err != nil {
+			return nil, fmt.Errorf("party %d: %w", j, err)
+		}
+		mu, err := s.key.paillier.Decrypt(round2[j].WCiphertext)
+		if err != nil {
+			return nil, fmt.Errorf("party %d: %w", j, err)
+		}
+		delta = arithmetic.AddModN(delta, alpha)
+		sigma = arithmetic.AddModN(sigma, mu)
+		arithmetic.ZeroBigInt(alpha)
+		arithmetic.ZeroBigInt(mu)
+	}
+	s.delta, s.sigma = delta, sigma
+
+	s.round = 3
+	return &SignRound3{From: s.key.Index, Delta: delta.FillBytes(make([]byte, 32))}, nil
+}
+
+// Round4 sums the δ shares and opens the commitment to Γ_i
+func (s *Signing) Round4(msgs []*SignRound3) (*SignRound4, error) {
+	if s.round != 3 {
+		return nil, ErrWrongRound
+	}
+	round3, err := collect(s, msgs, func(m *SignRound3) int { return m.From })
+	if err != nil {
+		return nil, err
+	}
+
+	s.deltaSum = new(big.Int).Set(s.delta)
+	for _, j := range s.others() {
+		s.deltaSum = arithmetic.AddModN(s.deltaSum, new(big.Int).SetBytes(round3[j].Delta))
+	}
+	if s.deltaSum.Sign() == 0 {
+		return nil, errors.New("δ = 0, restart signing")
+	}
+
+	s.round = 4
+	return &SignRound4{From: s.key.Index, Gamma: serializePoint(s.gammaPoint)}, nil
+}
+
+// Round5 checks every Γ_j, computes R = δ⁻¹ * Σ Γ_j and returns s_i
+func (s *Signing) Round5(msgs []*SignRound4) (*SignRound5, error) {
+	if s.round != 4 {
+		return nil, ErrWrongRound
+	}
+	round4, err := collect(s, msgs, func(m *SignRound4) int { return m.From })
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 1: Open the commitments and sum Γ = Σ Γ_j
+	gamma := s.gammaPoint
+	for _, j := range s.others() {
+		if string(gammaCommitment(round4[j].Gamma)) != string(s.commitments[j]) {
+			return nil, fmt.Errorf("party %d: %w", j, ErrCommitmentMismatch)
+		}
+		point, err := parsePoint(round4[j].Gamma)
+		if err != nil {
+			return nil, fmt.Errorf("party %d: %w", j, err)
+		}
+		gamma = addPoints(gamma, point)
+	}
+
+	// Step 2: R = δ⁻¹ * Γ = (kγ)⁻¹ * γG = k⁻¹G, r = R.x mod n
+	deltaInv, err := arithmetic.InvModN(s.deltaSum)
+	if err != nil {
+		return nil, err
+	}
+	bigR := toPublicKey(scalarMult(deltaInv, gamma))
+	s.r = arithmetic.ModN(new(big.Int).Set(bigR.X()))
+	if s.r.Sign() == 0 {
+		return nil, errors.New("nonce produced r = 0, restart signing")
+	}
+
+	// Step 3: s_i = m*k_i + r*σ_i
+	m := arithmetic.ModN(new(big.Int).SetBytes(s.hash[:]))
+	s.share = arithmetic.AddModN(arithmetic.MulModN(m, s.k), arithmetic.MulModN(s.r, s.sigma))
+
+	// Step 4: Erase the nonce material
+	for _, x := range []*big.Int{s.k, s.gamma, s.w, s.sigma, s.beta, s.nu} {
+		arithmetic.ZeroBigInt(x)
+	}
+
+	s.round = 5
+	return &SignRound5{From: s.key.Index, S: s.share.FillBytes(make([]byte, 32))}, nil
+}
+
+// Finalize sums the signature shares into a low-S signature and verifies it
+func (s *Signing) Finalize(msgs []*SignRound5) (*ecdsa.Signature, error) {
+	if s.round != 5 {
+		return nil, ErrWrongRound
+	}
+	round5, err := collect(s, msgs, func(m *SignRound5) int { return m.From })
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 1: s = Σ s_i
+	sum := new(big.Int).Set(s.share)
+	for _, j := range s.others() {
+		sum = arithmetic.AddModN(sum, new(big.Int).SetBytes(round5[j].S))
+	}
+
+	// Step 2: Normalize to the lower half (BIP62)
+	if sum.Cmp(new(big.Int).Rsh(arithmetic.GetCurveOrder(), 1)) > 0 {
+		sum = arithmetic.NegModN(sum)
+	}
+
+	// Step 3: Build and check the standard signature
+	var rScalar, sScalar btcec.ModNScalar
+	rScalar.SetByteSlice(s.r.Bytes())
+	sScalar.SetByteSlice(sum.Bytes())
+	signature := ecdsa.NewSignature(&rScalar, &sScalar)
+	if !signature.Verify(s.hash[:], s.key.PublicKey()) {
+		return nil, ErrInvalidSignature
+	}
+
+	s.round = 6
+	return signature, nil
+}
+
+// others returns the signer set without this party
+func (s *Signing) others() []int {
+	others := make([]int, 0, len(s.signers)-1)
+	for _, j := range s.signers {
+		if j != s.key.Index {
+			others = append(others, j)
+		}
+	}
+	return others
+}
+
+// collect indexes msgs by sender, requiring one message from every signer
+//
+// This party's own message may be included but is not required.
+func collect[T any](s *Signing, msgs []*T, from func(*T) int) (map[int]*T, error) {
+	out := make(map[int]*T, len(s.signers))
+	for _, msg := range msgs {
+		if msg == nil {
+			return nil, errors.New("nil message")
+		}
+		j := from(msg)
+		if !slices.Contains(s.signers, j) {
+			return nil, fmt.Errorf("message from party %d outside the signer set", j)
+		}
+		if _, dup := out[j]; dup {
+			return nil, fmt.Errorf("duplicate message from party %d", j)
+		}
+		out[j] = msg
+	}
+	for _, j := range s.others() {
+		if _, ok := out[j]; !ok {
+			return nil, fmt.Errorf("missing message from party %d", j)
+		}
+	}
+	return out, nil
+}
+
+// mta computes the sender side of a multiplicative-to-additive conversion
+//
+// Given c = Enc(a) and the secret b, returns Enc(a*b + β') and β = -β' mod n,
+// so that the receiver's Dec(...) + β = a*b mod n. β' < n² * 2^maskBits hides a*b.
+func mta(pk *paillier.PublicKey, c, b *big.Int) (*big.Int, *big.Int, error) {
+	n := arithmetic.GetCurveOrder()
+	bound := new(big.Int).Lsh(new(big.Int).Mul(n, n), maskBits)
+	betaPrime, err := rand.Int(rand.Reader, bound)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate mask: %w", err)
+	}
+	masked, err := pk.Encrypt(rand.Reader, betaPrime)
+	if err != nil {
+		return nil, nil, err
+	}
+	response := pk.Add(pk.MulConst(c, b), masked)
+	return response, arithmetic.NegModN(arithmetic.ModN(betaPrime)), nil
+}
+
+// gammaCommitment computes SHA256("tecdsa/commitment" || Γ_i)
+func gammaCommitment(gamma []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte("tecdsa/commitment"))
+	h.Write(gamma)
+	return h.Sum(nil)
+}
@@ -0,0 +1,186 @@
+package tecdsa
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+)
+
+// relay round-trips messages through JSON, as a network transport would
+func relay[T any](t *testing.T, msgs []*T) []*T {
+	t.Helper()
+	data, err := json.Marshal(msgs)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var out []*T
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	return out
+}
+
+// runSigning runs all signing rounds for the given signers
+func runSigning(t *testing.T, keys []*Key, signers []int, hash [32]byte) []*ecdsa.Signature {
+	t.Helper()
+	sessions := make([]*Signing, len(signers))
+	for i, j := range signers {
+		s, err := NewSigning(keys[j-1], signers, hash)
+		if err != nil {
+			t.Fatalf("NewSigning failed: %v", err)
+		}
+		sessions[i] = s
+	}
+
+	var round1 []*SignRound1
+	for _, s := range sessions {
+		msg, err := s.Round1()
+		if err != nil {
+			t.Fatalf("Round1 failed: %v", err)
+		}
+		round1 = append(round1, msg)
+	}
+	round1 = relay(t, round1)
+
+	var round2 []*SignRound2
+	for _, s := range sessions {
+		msgs, err := s.Round2(round1)
+		if err != nil {
+			t.Fatalf("Round2 failed: %v", err)
+		}
+		round2 = append(round2, msgs...)
+	}
+	round2 = relay(t, round2)
+
+	var round3 []*SignRound3
+	for _, s := range sessions {
+		msg, err := s.Round3(round2)
+		if err != nil {
+			t.Fatalf("Round3 failed: %v", err)
+		}
+		round3 = append(round3, msg)
+	}
+	round3 = relay(t, round3)
+
+	var round4 []*SignRound4
+	for _, s := range sessions {
+		msg, err := s.Round4(round3)
+		if err != nil {
+			t.Fatalf("Round4 failed: %v", err)
+		}
+		round4 = append(round4, msg)
+	}
+	round4 = relay(t, round4)
+
+	var round5 []*SignRound5
+	for _, s := range sessions {
+		msg, err := s.Round5(round4)
+		if err != nil {
+			t.Fatalf("Round5 failed: %v", err)
+		}
+		round5 = append(round5, msg)
+	}
+	round5 = relay(t, round5)
+
+	var sigs []*ecdsa.Signature
+	for _, s := range sessions {
+		sig, err := s.Finalize(round5)
+		if err != nil {
+			t.Fatalf("Finalize failed: %v", err)
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs
+}
+
+// TestThresholdSigning tests that every t-subset produces a standard DER signature
+func TestThresholdSigning(t *testing.T) {
+	keys := runKeyGen(t, Params{Threshold: 2, Parties: 3})
+	hash := sha256.Sum256([]byte("threshold ecdsa"))
+
+	for _, signers := range [][]int{{1, 2}, {1, 3}, {3, 2}} {
+		sigs := runSigning(t, keys, signers, hash)
+		for _, sig := range sigs[1:] {
+			if !sig.IsEqual(sigs[0]) {
+				t.Errorf("Signers %v produced different signatures", signers)
+			}
+		}
+
+		parsed, err := ecdsa.ParseDERSignature(sigs[0].Serialize())
+		if err != nil {
+			t.Fatalf("ParseDERSignature failed: %v", err)
+		}
+		if !parsed.Verify(hash[:], keys[0].PublicKey()) {
+			t.Errorf("Signature by %v does not verify", signers)
+		}
+	}
+}
+
+// TestThreeOfFive tests a larger signer set
+func TestThreeOfFive(t *testing.T) {
+	keys := runKeyGen(t, Params{Threshold: 3, Parties: 5})
+	hash := sha256.Sum256([]byte("3-of-5"))
+	sigs := runSigning(t, keys, []int{2, 4, 5}, hash)
+	if !sigs[0].Verify(hash[:], keys[0].PublicKey()) {
+		t.Error("3-of-5 signature does not verify")
+	}
+}
+
+func TestSigningErrors(t *testing.T) {
+	keys := runKeyGen(t, Params{Threshold: 2, Parties: 3})
+	hash := sha256.Sum256([]byte("errors"))
+
+	tests := []struct {
+		name    string
+		signers []int
+	}{
+		{name: "too few signers", signers: []int{1}},
+		{name: "duplicate signer", signers: []int{1, 1}},
+		{name: "out of range", signers: []int{1, 4}},
+		{name: "not a signer", signers: []int{2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSigning(keys[0], tt.signers, hash); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	// Rounds must run in order
+	s1, _ := NewSigning(keys[0], []int{1, 2}, hash)
+	s2, _ := NewSigning(keys[1], []int{1, 2}, hash)
+	if _, err := s1.Round3(nil); !errors.Is(err, ErrWrongRound) {
+		t.Errorf("Expected ErrWrongRound, got %v", err)
+	}
+
+	// A Γ that does not match its commitment is rejected
+	m1, _ := s1.Round1()
+	m2, _ := s2.Round1()
+	r21, _ := s1.Round2([]*SignRound1{m1, m2})
+	r22, _ := s2.Round2([]*SignRound1{m1, m2})
+	if _, err := s1.Round2([]*SignRound1{m1, m2}); !errors.Is(err, ErrWrongRound) {
+		t.Errorf("Expected ErrWrongRound for a repeated round, got %v", err)
+	}
+	all := append(r21, r22...)
+	d1, _ := s1.Round3(all)
+	d2, _ := s2.Round3(all)
+	g1, _ := s1.Round4([]*SignRound3{d1, d2})
+	if _, err := s2.Round4([]*SignRound3{d1, d2}); err != nil {
+		t.Fatalf("Round4 failed: %v", err)
+	}
+	forged := &SignRound4{From: 1, Gamma: serializePoint(baseMult(big.NewInt(5)))}
+	if _, err := s2.Round5([]*SignRound4{forged}); !errors.Is(err, ErrCommitmentMismatch) {
+		t.Errorf("Expected ErrCommitmentMismatch, got %v", err)
+	}
+	if _, err := s2.Round5([]*SignRound4{{From: 3, Gamma: g1.Gamma}}); err == nil {
+		t.Error("Expected error for a message from outside the signer set")
+	}
+	if _, err := s1.Finalize(nil); !errors.Is(err, ErrWrongRound) {
+		t.Errorf("Expected ErrWrongRound, got %v", err)
+	}
+}
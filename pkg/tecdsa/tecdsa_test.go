@@ -0,0 +1,47 @@
+package tecdsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
+)
+
+func TestParamsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  Params
+		wantErr bool
+	}{
+		{name: "2-of-2", params: Params{Threshold: 2, Parties: 2}},
+		{name: "3-of-5", params: Params{Threshold: 3, Parties: 5}},
+		{name: "1-of-3", params: Params{Threshold: 1, Parties: 3}, wantErr: true},
+		{name: "4-of-3", params: Params{Threshold: 4, Parties: 3}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.params.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestLagrangeCoefficient tests that the coefficients interpolate f(0)
+func TestLagrangeCoefficient(t *testing.T) {
+	// f(z) = 7 + 3z + 5z²
+	f := func(z int64) *big.Int {
+		return big.NewInt(7 + 3*z + 5*z*z)
+	}
+
+	for _, signers := range [][]int{{1, 2, 3}, {1, 3, 5}, {2, 4, 5}} {
+		sum := new(big.Int)
+		for _, i := range signers {
+			sum = arithmetic.AddModN(sum, arithmetic.MulModN(lagrangeCoefficient(i, signers), f(int64(i))))
+		}
+		if sum.Int64() != 7 {
+			t.Errorf("Interpolation over %v = %s, expected 7", signers, sum)
+		}
+	}
+}
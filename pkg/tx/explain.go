@@ -0,0 +1,354 @@
+package tx
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+)
+
+// Transaction explanation: a structured, human-readable breakdown for teaching and debugging
+
+// Sighash types (BIP143/BIP341)
+const (
+	SigHashDefault      = 0x00
+	SigHashAll          = 0x01
+	SigHashNone         = 0x02
+	SigHashSingle       = 0x03
+	SigHashAnyoneCanPay = 0x80
+)
+
+// SigHashName returns the name of a sighash type byte
+//
+// Example:
+//
+//	SigHashName(0x81) // "ALL|ANYONECANPAY"
+func SigHashName(hashType byte) string {
+	if hashType == SigHashDefault {
+		return "DEFAULT"
+	}
+
+	var name string
+	switch hashType &^ SigHashAnyoneCanPay {
+	case SigHashAll:
+		name = "ALL"
+	case SigHashNone:
+		name = "NONE"
+	case SigHashSingle:
+		name = "SINGLE"
+	default:
+		return fmt.Sprintf("UNKNOWN(%#x)", hashType)
+	}
+	if hashType&SigHashAnyoneCanPay != 0 {
+		name += "|ANYONECANPAY"
+	}
+	return name
+}
+
+// InputExplanation describes one input of an explained transaction
+type InputExplanation struct {
+	Index        int      `json:"index"`
+	OutPoint     string   `json:"outpoint"`
+	Sequence     uint32   `json:"sequence"`
+	ScriptType   string   `json:"script_type"`
+	Inferred     bool     `json:"inferred"` // True if the type was guessed from scriptSig/witness
+	Value        *int64   `json:"value,omitempty"`
+	Address      string   `json:"address,omitempty"`
+	SigHashTypes []string `json:"sighash_types,omitempty"`
+	WitnessItems int      `json:"witness_items"`
+}
+
+// OutputExplanation describes one output of an explained transaction
+type OutputExplanation struct {
+	Index      int    `json:"index"`
+	Value      int64  `json:"value"`
+	ScriptType string `json:"script_type"`
+	Address    string `json:"address,omitempty"`
+	Script     string `json:"script"`
+}
+
+// Explanation is a structured breakdown of a transaction
+type Explanation struct {
+	TxID     string              `json:"txid"`
+	WTxID    string              `json:"wtxid"`
+	Version  int32               `json:"version"`
+	LockTime uint32              `json:"locktime"`
+	Size     int                 `json:"size"`
+	Weight   int                 `json:"weight"`
+	VSize    int                 `json:"vsize"`
+	Inputs   []InputExplanation  `json:"inputs"`
+	Outputs  []OutputExplanation `json:"outputs"`
+	TotalIn  *int64              `json:"total_in,omitempty"`
+	TotalOut int64               `json:"total_out"`
+	Fee      *int64              `json:"fee,omitempty"`
+	FeeRate  float64             `json:"fee_rate,omitempty"` // sat/vB
+}
+
+// Explain produces a structured breakdown of a transaction
+//
+// prevouts are optional: when provided (one per input, in order), input types are
+// resolved from the spent scriptPubKey and the fee is computed. Without them, input
+// types are inferred from the scriptSig and witness shape.
+//
+// Example:
+//
+//	tx, _ := DeserializeHex(rawHex)
+//	explanation, err := Explain(tx, nil, &chaincfg.MainNetParams)
+//	fmt.Println(explanation)
+func Explain(tx *Transaction, prevouts []*TxOut, params *chaincfg.Params) (*Explanation, error) {
+	if tx == nil {
+		return nil, errors.New("transaction cannot be nil")
+	}
+	if params == nil {
+		return nil, errors.New("network params are required")
+	}
+	if prevouts != nil && len(prevouts) != len(tx.Inputs) {
+		return nil, fmt.Errorf("expected %d prevouts, got %d", len(tx.Inputs), len(prevouts))
+	}
+
+	// Step 1: Identifiers and sizes
+	txid := tx.TxIDHex()
+	wtxidDisplay := hash.Reverse32(tx.WTxID())
+	e := &Explanation{
+		TxID:     txid,
+		WTxID:    hex.EncodeToString(wtxidDisplay[:]),
+		Version:  tx.Version,
+		LockTime: tx.LockTime,
+		Size:     len(tx.Serialize()),
+		Weight:   tx.Weight(),
+		VSize:    tx.VSize(),
+	}
+
+	// Step 2: Inputs, resolved from prevouts when available
+	var totalIn int64
+	for i, in := range tx.Inputs {
+		ie := InputExplanation{
+			Index:        i,
+			OutPoint:     in.PreviousOutPoint.String(),
+			Sequence:     in.Sequence,
+			WitnessItems: len(in.Witness),
+		}
+
+		var class ScriptClass
+		if prevouts != nil && prevouts[i] != nil {
+			class = ClassifyScript(prevouts[i].PkScript)
+			ie.ScriptType = class.String()
+			value := prevouts[i].Value
+			ie.Value = &value
+			totalIn += value
+			if addr, err := ExtractAddress(prevouts[i].PkScript, params); err == nil {
+				ie.Address = addr
+			}
+		} else {
+			ie.ScriptType = inferInputType(in)
+			ie.Inferred = true
+			if ie.ScriptType == WitnessV1Taproot.String() {
+				class = WitnessV1Taproot
+			}
+		}
+
+		ie.SigHashTypes = sigHashTypes(in, class == WitnessV1Taproot)
+		e.Inputs = append(e.Inputs, ie)
+	}
+
+	// Step 3: Outputs
+	for i, out := range tx.Outputs {
+		oe := OutputExplanation{
+			Index:      i,
+			Value:      out.Value,
+			ScriptType: ClassifyScript(out.PkScript).String(),
+			Script:     Disassemble(out.PkScript),
+		}
+		if addr, err := ExtractAddress(out.PkScript, params); err == nil {
+			oe.Address = addr
+		}
+		e.TotalOut += out.Value
+		e.Outputs = append(e.Outputs, oe)
+	}
+
+	// Step 4: Fee, only if every input's value is known
+	if prevouts != nil && allKnown(prevouts) {
+		fee := totalIn - e.TotalOut
+		e.TotalIn = &totalIn
+		e.Fee = &fee
+		if e.VSize > 0 {
+			e.FeeRate = float64(fee) / float64(e.VSize)
+		}
+	}
+
+	return e, nil
+}
+
+// String renders the explanation as indented text
+func (e *Explanation) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Transaction %s\n", e.TxID)
+	if e.WTxID != e.TxID {
+		fmt.Fprintf(&sb, "  wtxid: %s\n", e.WTxID)
+	}
+	fmt.Fprintf(&sb, "  version: %d, locktime: %d\n", e.Version, e.LockTime)
+	fmt.Fprintf(&sb, "  size: %d bytes, weight: %d WU, vsize: %d vB\n", e.Size, e.Weight, e.VSize)
+
+	fmt.Fprintf(&sb, "Inputs (%d):\n", len(e.Inputs))
+	for _, in := range e.Inputs {
+		fmt.Fprintf(&sb, "  #%d %s\n", in.Index, in.OutPoint)
+		scriptType := in.ScriptType
+		if in.Inferred {
+			scriptType += " (inferred)"
+		}
+		fmt.Fprintf(&sb, "     type: %s\n", scriptType)
+		if in.Value != nil {
+			fmt.Fprintf(&sb, "     value: %d sat\n", *in.Value)
+		}
+		if in.Address != "" {
+			fmt.Fprintf(&sb, "     address: %s\n", in.Address)
+		}
+		fmt.Fprintf(&sb, "     sequence: %#08x\n", in.Sequence)
+		if len(in.SigHashTypes) > 0 {
+			fmt.Fprintf(&sb, "     sighash: %s\n", strings.Join(in.SigHashTypes, ", "))
+		}
+		if in.WitnessItems > 0 {
+			fmt.Fprintf(&sb, "     witness items: %d\n", in.WitnessItems)
+		}
+	}
+
+	fmt.Fprintf(&sb, "Outputs (%d):\n", len(e.Outputs))
+	for _, out := range e.Outputs {
+		fmt.Fprintf(&sb, "  #%d %d sat %s\n", out.Index, out.Value, out.ScriptType)
+		if out.Address != "" {
+			fmt.Fprintf(&sb, "     address: %s\n", out.Address)
+		}
+		fmt.Fprintf(&sb, "     script: %s\n", out.Script)
+	}
+
+	fmt.Fprintf(&sb, "Total out: %d sat\n", e.TotalOut)
+	if e.Fee != nil {
+		fmt.Fprintf(&sb, "Total in: %d sat\n", *e.TotalIn)
+		fmt.Fprintf(&sb, "Fee: %d sat (%.2f sat/vB)\n", *e.Fee, e.FeeRate)
+	}
+	return sb.String()
+}
+
+// inferInputType guesses the spent script type from the scriptSig and witness shape
+func inferInputType(in *TxIn) string {
+	// Step 1: Coinbase inputs spend the null outpoint
+	if in.PreviousOutPoint.Hash == [32]byte{} && in.PreviousOutPoint.Index == 0xffffffff {
+		return "coinbase"
+	}
+
+	pushes, err := ParsePushes(in.SignatureScript)
+	if err != nil {
+		return NonStandard.String()
+	}
+
+	// Step 2: Witness inputs (native or nested in P2SH)
+	if len(in.Witness) > 0 {
+		if len(pushes) == 1 {
+			switch ClassifyScript(pushes[0]) {
+			case WitnessV0PubKeyHash:
+				return "p2sh-p2wpkh"
+			case WitnessV0ScriptHash:
+				return "p2sh-p2wsh"
+			}
+		}
+		if len(pushes) != 0 {
+			return NonStandard.String()
+		}
+		stack := in.Witness
+		if len(stack) >= 2 && len(stack[len(stack)-1]) > 0 && stack[len(stack)-1][0] == 0x50 {
+			stack = stack[:len(stack)-1] // Drop the taproot annex
+		}
+		switch {
+		case len(stack) == 1 && (len(stack[0]) == 64 || len(stack[0]) == 65):
+			return WitnessV1Taproot.String()
+		case len(stack) >= 2 && isControlBlock(stack[len(stack)-1]):
+			return WitnessV1Taproot.String()
+		case len(stack) == 2 && (len(stack[1]) == 33) && isDERSignature(stack[0]):
+			return WitnessV0PubKeyHash.String()
+		default:
+			return WitnessV0ScriptHash.String()
+		}
+	}
+
+	// Step 3: Legacy inputs
+	switch {
+	case len(pushes) == 2 && (len(pushes[1]) == 33 || len(pushes[1]) == 65) && isDERSignature(pushes[0]):
+		return PubKeyHash.String()
+	case len(pushes) == 1 && isDERSignature(pushes[0]):
+		return PubKey.String()
+	case len(pushes) >= 2 && len(pushes[0]) == 0:
+		return ScriptHash.String()
+	default:
+		return NonStandard.String()
+	}
+}
+
+// sigHashTypes extracts the sighash type of every signature found in an input
+func sigHashTypes(in *TxIn, taproot bool) []string {
+	var types []string
+
+	// Step 1: Taproot signatures are 64 bytes (DEFAULT) or 65 bytes (explicit type)
+	if taproot {
+		stack := in.Witness
+		if len(stack) >= 2 && len(stack[len(stack)-1]) > 0 && stack[len(stack)-1][0] == 0x50 {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) >= 2 {
+			stack = stack[:len(stack)-2] // Script path: drop the leaf script and control block
+		}
+		for _, item := range stack {
+			switch len(item) {
+			case 64:
+				types = append(types, SigHashName(SigHashDefault))
+			case 65:
+				types = append(types, SigHashName(item[64]))
+			}
+		}
+		return types
+	}
+
+	// Step 2: ECDSA signatures are DER followed by one sighash byte
+	items := in.Witness
+	if pushes, err := ParsePushes(in.SignatureScript); err == nil {
+		items = append(append([][]byte{}, pushes...), in.Witness...)
+	}
+	for _, item := range items {
+		if isDERSignature(item) {
+			types = append(types, SigHashName(item[len(item)-1]))
+		}
+	}
+	return types
+}
+
+// isDERSignature reports whether b looks like a DER ECDSA signature plus a sighash byte
+//
+// Format: 0x30 [total-len] 0x02 [R-len] [R] 0x02 [S-len] [S] [sighash]
+func isDERSignature(b []byte) bool {
+	if len(b) < 9 || len(b) > 73 || b[0] != 0x30 || int(b[1]) != len(b)-3 {
+		return false
+	}
+	rLen := int(b[3])
+	if b[2] != 0x02 || rLen == 0 || 5+rLen >= len(b) {
+		return false
+	}
+	sLen := int(b[5+rLen])
+	return b[4+rLen] == 0x02 && sLen != 0 && 6+rLen+sLen == len(b)-1
+}
+
+// isControlBlock reports whether b has the shape of a taproot control block
+func isControlBlock(b []byte) bool {
+	return len(b) >= 33 && (len(b)-33)%32 == 0 && len(b) <= 33+32*128 && b[0]&0xfe == hash.TapLeafVersion
+}
+
+// allKnown reports whether every prevout is present
+func allKnown(prevouts []*TxOut) bool {
+	for _, p := range prevouts {
+		if p == nil {
+			return false
+		}
+	}
+	return true
+}
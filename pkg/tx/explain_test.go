@@ -0,0 +1,154 @@
+package tx
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+func TestSigHashName(t *testing.T) {
+	tests := map[byte]string{
+		0x00: "DEFAULT",
+		0x01: "ALL",
+		0x02: "NONE",
+		0x03: "SINGLE",
+		0x81: "ALL|ANYONECANPAY",
+		0x83: "SINGLE|ANYONECANPAY",
+		0x04: "UNKNOWN(0x4)",
+	}
+	for hashType, expected := range tests {
+		if got := SigHashName(hashType); got != expected {
+			t.Errorf("SigHashName(%#x) = %s, expected %s", hashType, got, expected)
+		}
+	}
+}
+
+// newExplainTx builds a transaction spending a P2WPKH and a P2TR output with real signatures
+func newExplainTx(t *testing.T) (*Transaction, []*TxOut) {
+	t.Helper()
+	priv, pub := btcec.PrivKeyFromBytes([]byte{0x01})
+	digest := sha256.Sum256([]byte("not a real sighash"))
+
+	ecdsaSig := append(ecdsa.Sign(priv, digest[:]).Serialize(), SigHashAll|SigHashAnyoneCanPay)
+	schnorrSig, err := schnorr.SignBIP340([]byte("not a real sighash"), priv)
+	if err != nil {
+		t.Fatalf("SignBIP340 failed: %v", err)
+	}
+
+	pubKeyHash := hash.Hash160(pub.SerializeCompressed())
+	xOnly := schnorr.XOnlyFromPub(pub)
+	prevouts := []*TxOut{
+		{Value: 60000, PkScript: append([]byte{OP_0, 0x14}, pubKeyHash[:]...)},
+		{Value: 40000, PkScript: append([]byte{OP_1, 0x20}, xOnly[:]...)},
+	}
+
+	tx := &Transaction{
+		Version: 2,
+		Inputs: []*TxIn{
+			{PreviousOutPoint: OutPoint{Hash: [32]byte{0x01}}, Witness: [][]byte{ecdsaSig, pub.SerializeCompressed()}, Sequence: 0xffffffff},
+			{PreviousOutPoint: OutPoint{Hash: [32]byte{0x02}, Index: 3}, Witness: [][]byte{schnorrSig[:]}, Sequence: 0xffffffff},
+		},
+		Outputs: []*TxOut{
+			{Value: 90000, PkScript: append([]byte{OP_0, 0x14}, pubKeyHash[:]...)},
+			{Value: 0, PkScript: []byte{OP_RETURN, 0x02, 0x68, 0x69}},
+		},
+	}
+	return tx, prevouts
+}
+
+func TestExplainWithPrevouts(t *testing.T) {
+	tx, prevouts := newExplainTx(t)
+
+	e, err := Explain(tx, prevouts, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+
+	if e.TxID != tx.TxIDHex() {
+		t.Errorf("TxID = %s, expected %s", e.TxID, tx.TxIDHex())
+	}
+	if e.VSize != tx.VSize() || e.Weight != tx.Weight() {
+		t.Error("Sizes do not match the transaction")
+	}
+
+	// Inputs are resolved from the prevouts
+	if e.Inputs[0].ScriptType != "p2wpkh" || e.Inputs[0].Inferred {
+		t.Errorf("Input 0 type = %s (inferred %v), expected resolved p2wpkh", e.Inputs[0].ScriptType, e.Inputs[0].Inferred)
+	}
+	if e.Inputs[1].ScriptType != "p2tr" {
+		t.Errorf("Input 1 type = %s, expected p2tr", e.Inputs[1].ScriptType)
+	}
+	if !strings.HasPrefix(e.Inputs[0].Address, "bc1q") || !strings.HasPrefix(e.Inputs[1].Address, "bc1p") {
+		t.Errorf("Unexpected input addresses: %s, %s", e.Inputs[0].Address, e.Inputs[1].Address)
+	}
+	if len(e.Inputs[0].SigHashTypes) != 1 || e.Inputs[0].SigHashTypes[0] != "ALL|ANYONECANPAY" {
+		t.Errorf("Input 0 sighash = %v, expected [ALL|ANYONECANPAY]", e.Inputs[0].SigHashTypes)
+	}
+	if len(e.Inputs[1].SigHashTypes) != 1 || e.Inputs[1].SigHashTypes[0] != "DEFAULT" {
+		t.Errorf("Input 1 sighash = %v, expected [DEFAULT]", e.Inputs[1].SigHashTypes)
+	}
+
+	// Outputs and fee
+	if e.Outputs[1].ScriptType != "nulldata" || e.Outputs[1].Address != "" {
+		t.Errorf("Output 1 should be an address-less nulldata output, got %+v", e.Outputs[1])
+	}
+	if e.Fee == nil || *e.Fee != 10000 {
+		t.Fatalf("Fee = %v, expected 10000", e.Fee)
+	}
+	if e.FeeRate <= 0 {
+		t.Error("Fee rate should be positive")
+	}
+
+	text := e.String()
+	for _, want := range []string{"Inputs (2)", "Outputs (2)", "Fee: 10000 sat", "ALL|ANYONECANPAY"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("String() missing %q", want)
+		}
+	}
+}
+
+func TestExplainInferred(t *testing.T) {
+	tx, _ := newExplainTx(t)
+
+	e, err := Explain(tx, nil, &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	if e.Inputs[0].ScriptType != "p2wpkh" || !e.Inputs[0].Inferred {
+		t.Errorf("Input 0 = %s (inferred %v), expected inferred p2wpkh", e.Inputs[0].ScriptType, e.Inputs[0].Inferred)
+	}
+	if e.Inputs[1].ScriptType != "p2tr" {
+		t.Errorf("Input 1 = %s, expected p2tr", e.Inputs[1].ScriptType)
+	}
+	if e.Fee != nil {
+		t.Error("Fee should be unknown without prevouts")
+	}
+	if !strings.HasPrefix(e.Outputs[0].Address, "tb1q") {
+		t.Errorf("Expected testnet address, got %s", e.Outputs[0].Address)
+	}
+
+	coinbase, _ := DeserializeHex(genesisCoinbaseHex)
+	e, err = Explain(coinbase, nil, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	if e.Inputs[0].ScriptType != "coinbase" {
+		t.Errorf("Genesis input = %s, expected coinbase", e.Inputs[0].ScriptType)
+	}
+	if e.Outputs[0].ScriptType != "p2pk" {
+		t.Errorf("Genesis output = %s, expected p2pk", e.Outputs[0].ScriptType)
+	}
+
+	if _, err := Explain(tx, []*TxOut{nil}, &chaincfg.MainNetParams); err == nil {
+		t.Error("Expected error for prevout count mismatch")
+	}
+	if _, err := Explain(nil, nil, &chaincfg.MainNetParams); err == nil {
+		t.Error("Expected error for nil transaction")
+	}
+}
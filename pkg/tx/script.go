@@ -0,0 +1,420 @@
+package tx
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neverDefined/cryptography-playground/pkg/base58"
+	"github.com/neverDefined/cryptography-playground/pkg/bech32"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+)
+
+// Bitcoin script opcodes used by the standard output templates
+const (
+	OP_0                   = 0x00
+	OP_PUSHDATA1           = 0x4c
+	OP_PUSHDATA2           = 0x4d
+	OP_PUSHDATA4           = 0x4e
+	OP_1NEGATE             = 0x4f
+	OP_1                   = 0x51
+	OP_16                  = 0x60
+	OP_RETURN              = 0x6a
+	OP_DROP                = 0x75
+	OP_DUP                 = 0x76
+	OP_EQUAL               = 0x87
+	OP_EQUALVERIFY         = 0x88
+	OP_HASH160             = 0xa9
+	OP_CHECKSIG            = 0xac
+	OP_CHECKSIGVERIFY      = 0xad
+	OP_CHECKMULTISIG       = 0xae
+	OP_CHECKSEQUENCEVERIFY = 0xb2
+)
+
+// ScriptNum encodes n (>= 0) as a minimal script number push
+//
+// 0..16 become OP_0..OP_16; larger values are pushed as little-endian bytes,
+// with a zero byte appended when the top bit would read as a sign.
+//
+// Example:
+//
+//	ScriptNum(144) // 02 90 00: push 2 bytes, 144 with a cleared sign bit
+func ScriptNum(n int64) []byte {
+	if n == 0 {
+		return []byte{OP_0}
+	}
+	if n <= 16 {
+		return []byte{byte(OP_1 + n - 1)}
+	}
+
+	// Little-endian magnitude; add a zero byte if the sign bit is set
+	var num []byte
+	for v := n; v > 0; v >>= 8 {
+		num = append(num, byte(v))
+	}
+	if num[len(num)-1]&0x80 != 0 {
+		num = append(num, 0x00)
+	}
+	return append([]byte{byte(len(num))}, num...)
+}
+
+// ScriptClass identifies a standard scriptPubKey template
+type ScriptClass int
+
+const (
+	// NonStandard is any script that does not match a known template
+	NonStandard ScriptClass = iota
+	// PubKey is a bare <pubkey> OP_CHECKSIG output (P2PK)
+	PubKey
+	// PubKeyHash is OP_DUP OP_HASH160 <20 bytes> OP_EQUALVERIFY OP_CHECKSIG (P2PKH)
+	PubKeyHash
+	// ScriptHash is OP_HASH160 <20 bytes> OP_EQUAL (P2SH)
+	ScriptHash
+	// WitnessV0PubKeyHash is OP_0 <20 bytes> (P2WPKH)
+	WitnessV0PubKeyHash
+	// WitnessV0ScriptHash is OP_0 <32 bytes> (P2WSH)
+	WitnessV0ScriptHash
+	// WitnessV1Taproot is OP_1 <32 bytes> (P2TR)
+	WitnessV1Taproot
+	// WitnessUnknown is any other witness program (future segwit versions)
+	WitnessUnknown
+	// Multisig is a bare OP_m <pubkeys> OP_n OP_CHECKMULTISIG output
+	Multisig
+	// NullData is an OP_RETURN data carrier output
+	NullData
+)
+
+// String returns the conventional name of the script class
+func (c ScriptClass) String() string {
+	switch c {
+	case PubKey:
+		return "p2pk"
+	case PubKeyHash:
+		return "p2pkh"
+	case ScriptHash:
+		return "p2sh"
+	case WitnessV0PubKeyHash:
+		return "p2wpkh"
+	case WitnessV0ScriptHash:
+		return "p2wsh"
+	case WitnessV1Taproot:
+		return "p2tr"
+	case WitnessUnknown:
+		return "witness_unknown"
+	case Multisig:
+		return "multisig"
+	case NullData:
+		return "nulldata"
+	default:
+		return "nonstandard"
+	}
+}
+
+// ClassifyScript determines the standard template of a scriptPubKey
+//
+// Example:
+//
+//	script, _ := hex.DecodeString("0014751e76e8199196d454941c45d1b3a323f1433bd6")
+//	class := ClassifyScript(script)
+//	// Result: WitnessV0PubKeyHash
+func ClassifyScript(script []byte) ScriptClass {
+	switch {
+	case len(script) == 25 && script[0] == OP_DUP && script[1] == OP_HASH160 &&
+		script[2] == 0x14 && script[23] == OP_EQUALVERIFY && script[24] == OP_CHECKSIG:
+		return PubKeyHash
+	case len(script) == 23 && script[0] == OP_HASH160 && script[1] == 0x14 && script[22] == OP_EQUAL:
+		return ScriptHash
+	case len(script) == 22 && script[0] == OP_0 && script[1] == 0x14:
+		return WitnessV0PubKeyHash
+	case len(script) == 34 && script[0] == OP_0 && script[1] == 0x20:
+		return WitnessV0ScriptHash
+	case len(script) == 34 && script[0] == OP_1 && script[1] == 0x20:
+		return WitnessV1Taproot
+	case isWitnessProgram(script):
+		return WitnessUnknown
+	case (len(script) == 35 && script[0] == 0x21 || len(script) == 67 && script[0] == 0x41) &&
+		script[len(script)-1] == OP_CHECKSIG:
+		return PubKey
+	case len(script) > 0 && script[0] == OP_RETURN:
+		return NullData
+	case isMultisig(script):
+		return Multisig
+	default:
+		return NonStandard
+	}
+}
+
+// isWitnessProgram reports whether script is OP_n <2..40 bytes> (BIP141)
+func isWitnessProgram(script []byte) bool {
+	if len(script) < 4 || len(script) > 42 {
+		return false
+	}
+	if script[0] != OP_0 && (script[0] < OP_1 || script[0] > OP_16) {
+		return false
+	}
+	return int(script[1])+2 == len(script)
+}
+
+// isMultisig reports whether script is OP_m <pubkeys> OP_n OP_CHECKMULTISIG
+func isMultisig(script []byte) bool {
+	if len(script) < 3 || script[len(script)-1] != OP_CHECKMULTISIG {
+		return false
+	}
+	m, n := script[0], script[len(script)-2]
+	if m < OP_1 || m > OP_16 || n < OP_1 || n > OP_16 || m > n {
+		return false
+	}
+	pushes, err := ParsePushes(script[1 : len(script)-2])
+	if err != nil || len(pushes) != int(n-OP_1+1) {
+		return false
+	}
+	for _, p := range pushes {
+		if len(p) != 33 && len(p) != 65 {
+			return false
+		}
+	}
+	return true
+}
+
+// ParsePushes splits a push-only script (such as a scriptSig) into its data items
+//
+// Returns an error if the script contains any opcode other than a data push.
+// OP_0 yields an empty item and OP_1..OP_16 yield the small integer as one byte.
+//
+// Example:
+//
+//	items, err := ParsePushes(scriptSig)
+//	// P2PKH scriptSig result: [][]byte{signature, publicKey}
+func ParsePushes(script []byte) ([][]byte, error) {
+	var items [][]byte
+	for i := 0; i < len(script); {
+		op := script[i]
+		i++
+
+		// Step 1: Determine the length of the pushed data
+		var n int
+		switch {
+		case op == OP_0:
+			items = append(items, []byte{})
+			continue
+		case op < OP_PUSHDATA1:
+			n = int(op)
+		case op == OP_PUSHDATA1:
+			if i+1 > len(script) {
+				return nil, errors.New("truncated OP_PUSHDATA1")
+			}
+			n = int(script[i])
+			i++
+		case op == OP_PUSHDATA2:
+			if i+2 > len(script) {
+				return nil, errors.New("truncated OP_PUSHDATA2")
+			}
+			n = int(binary.LittleEndian.Uint16(script[i:]))
+			i += 2
+		case op == OP_PUSHDATA4:
+			if i+4 > len(script) {
+				return nil, errors.New("truncated OP_PUSHDATA4")
+			}
+			n = int(binary.LittleEndian.Uint32(script[i:]))
+			i += 4
+		case op == OP_1NEGATE:
+			items = append(items, []byte{0x81})
+			continue
+		case op >= OP_1 && op <= OP_16:
+			items = append(items, []byte{op - OP_1 + 1})
+			continue
+		default:
+			return nil, fmt.Errorf("non-push opcode %#x at position %d", op, i-1)
+		}
+
+		// Step 2: Extract the data
+		if n < 0 || i+n > len(script) {
+			return nil, errors.New("push exceeds script length")
+		}
+		items = append(items, script[i:i+n])
+		i += n
+	}
+	return items, nil
+}
+
+// opcodeNames maps the opcodes printed by Disassemble to their names
+var opcodeNames = map[byte]string{
+	OP_1NEGATE:       "OP_1NEGATE",
+	OP_RETURN:        "OP_RETURN",
+	OP_DUP:           "OP_DUP",
+	OP_EQUAL:         "OP_EQUAL",
+	OP_EQUALVERIFY:   "OP_EQUALVERIFY",
+	OP_HASH160:       "OP_HASH160",
+	OP_CHECKSIG:      "OP_CHECKSIG",
+	OP_CHECKMULTISIG: "OP_CHECKMULTISIG",
+	0x61:             "OP_NOP",
+	0x63:             "OP_IF",
+	0x64:             "OP_NOTIF",
+	0x67:             "OP_ELSE",
+	0x68:             "OP_ENDIF",
+	0x69:             "OP_VERIFY",
+	0x75:             "OP_DROP",
+	0x7c:             "OP_SWAP",
+	0x82:             "OP_SIZE",
+	0x9c:             "OP_NUMEQUAL",
+	0x9d:             "OP_NUMEQUALVERIFY",
+	0xa8:             "OP_SHA256",
+	0xaa:             "OP_HASH256",
+	0xad:             "OP_CHECKSIGVERIFY",
+	0xaf:             "OP_CHECKMULTISIGVERIFY",
+	0xb1:             "OP_CHECKLOCKTIMEVERIFY",
+	0xb2:             "OP_CHECKSEQUENCEVERIFY",
+	0xba:             "OP_CHECKSIGADD",
+}
+
+// Disassemble renders a script in the human-readable form used by Bitcoin Core
+//
+// Example:
+//
+//	Disassemble(p2pkhScript)
+//	// Result: "OP_DUP OP_HASH160 751e76e8...3bd6 OP_EQUALVERIFY OP_CHECKSIG"
+func Disassemble(script []byte) string {
+	var parts []string
+	for i := 0; i < len(script); {
+		op := script[i]
+
+		// Step 1: Data pushes are printed as hex
+		if op > OP_0 && op <= OP_PUSHDATA4 {
+			items, err := ParsePushes(script[i:nextOpcode(script, i)])
+			if err != nil || len(items) != 1 {
+				parts = append(parts, "[error]")
+				break
+			}
+			parts = append(parts, hex.EncodeToString(items[0]))
+			i = nextOpcode(script, i)
+			continue
+		}
+
+		// Step 2: Everything else is printed by name
+		switch {
+		case op == OP_0:
+			parts = append(parts, "0")
+		case op >= OP_1 && op <= OP_16:
+			parts = append(parts, fmt.Sprintf("OP_%d", op-OP_1+1))
+		default:
+			if name, ok := opcodeNames[op]; ok {
+				parts = append(parts, name)
+			} else {
+				parts = append(parts, fmt.Sprintf("OP_UNKNOWN_%#x", op))
+			}
+		}
+		i++
+	}
+	return strings.Join(parts, " ")
+}
+
+// nextOpcode returns the position after the push opcode at position i (clamped to the script length)
+func nextOpcode(script []byte, i int) int {
+	op := script[i]
+	var n, header int
+	switch {
+	case op < OP_PUSHDATA1:
+		n, header = int(op), 1
+	case op == OP_PUSHDATA1 && i+2 <= len(script):
+		n, header = int(script[i+1]), 2
+	case op == OP_PUSHDATA2 && i+3 <= len(script):
+		n, header = int(binary.LittleEndian.Uint16(script[i+1:])), 3
+	case op == OP_PUSHDATA4 && i+5 <= len(script):
+		n, header = int(binary.LittleEndian.Uint32(script[i+1:])), 5
+	default:
+		return len(script)
+	}
+	if i+header+n > len(script) || n < 0 {
+		return len(script)
+	}
+	return i + header + n
+}
+
+// ExtractAddress renders a scriptPubKey as an address
+//
+// Returns an error for scripts that have no address form (P2PK, bare multisig,
+// OP_RETURN and non-standard scripts).
+//
+// Example:
+//
+//	addr, err := ExtractAddress(pkScript, &chaincfg.MainNetParams)
+//	// Result: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+func ExtractAddress(script []byte, params *chaincfg.Params) (string, error) {
+	if params == nil {
+		return "", errors.New("network params are required")
+	}
+	switch ClassifyScript(script) {
+	case PubKeyHash:
+		return base58.Base58CheckEncode(params.PubKeyHashAddrID, script[3:23]), nil
+	case ScriptHash:
+		return base58.Base58CheckEncode(params.ScriptHashAddrID, script[2:22]), nil
+	case WitnessV0PubKeyHash, WitnessV0ScriptHash, WitnessV1Taproot, WitnessUnknown:
+		if !params.SupportsSegwit() {
+			return "", fmt.Errorf("%s has no segwit addresses", params.Name)
+		}
+		version := script[0]
+		if version != OP_0 {
+			version -= OP_1 - 1
+		}
+		return bech32.SegWitAddressEncode(params.Bech32HRPSegwit, version, script[2:])
+	default:
+		return "", errors.New("script has no address form")
+	}
+}
+
+// AddressScript returns the scriptPubKey an address pays to (the inverse of ExtractAddress)
+//
+// Base58 addresses must carry the P2PKH or P2SH version of params; segwit
+// addresses must use its human-readable part.
+//
+// Example:
+//
+//	script, err := AddressScript("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", &chaincfg.MainNetParams)
+//	// Result: 0014751e76e8199196d454941c45d1b3a323f1433bd6
+func AddressScript(addr string, params *chaincfg.Params) ([]byte, error) {
+	if params == nil {
+		return nil, errors.New("network params are required")
+	}
+
+	// Step 1: Segwit addresses (bech32/bech32m)
+	if params.SupportsSegwit() && strings.HasPrefix(strings.ToLower(addr), params.Bech32HRPSegwit+"1") {
+		version, program, err := bech32.SegWitAddressDecode(params.Bech32HRPSegwit, addr)
+		if err != nil {
+			return nil, err
+		}
+		op := byte(OP_0)
+		if version > 0 {
+			op = OP_1 + version - 1
+		}
+		return append([]byte{op, byte(len(program))}, program...), nil
+	}
+
+	// Step 2: Base58 P2PKH/P2SH addresses
+	payload, version, err := base58.Base58CheckDecode(addr)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != 20 {
+		return nil, fmt.Errorf("address payload is %d bytes, expected 20", len(payload))
+	}
+	switch version {
+	case params.PubKeyHashAddrID:
+		return P2PKHScript(payload), nil
+	case params.ScriptHashAddrID:
+		return append(append([]byte{OP_HASH160, 0x14}, payload...), OP_EQUAL), nil
+	default:
+		return nil, fmt.Errorf("address version 0x%02x is not used by %s", version, params.Name)
+	}
+}
+
+// P2PKHScript returns OP_DUP OP_HASH160 <pubKeyHash> OP_EQUALVERIFY OP_CHECKSIG
+//
+// This is also the BIP143 scriptCode of a P2WPKH input.
+func P2PKHScript(pubKeyHash []byte) []byte {
+	script := make([]byte, 0, 25)
+	script = append(script, OP_DUP, OP_HASH160, byte(len(pubKeyHash)))
+	script = append(script, pubKeyHash...)
+	return append(script, OP_EQUALVERIFY, OP_CHECKSIG)
+}
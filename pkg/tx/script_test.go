@@ -0,0 +1,217 @@
+package tx
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+func TestClassifyAndExtractAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		class   ScriptClass
+		address string
+	}{
+		{
+			name:    "p2pkh",
+			script:  "76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac",
+			class:   PubKeyHash,
+			address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+		},
+		{
+			name:    "p2sh",
+			script:  "a914e9c3dd0c07aac76179ebc76a6c78d4d67c6c160a87",
+			class:   ScriptHash,
+			address: "3P14159f73E4gFr7JterCCQh9QjiTjiZrG",
+		},
+		{
+			name:    "p2wpkh",
+			script:  "0014751e76e8199196d454941c45d1b3a323f1433bd6",
+			class:   WitnessV0PubKeyHash,
+			address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		},
+		{
+			name:    "p2tr",
+			script:  "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			class:   WitnessV1Taproot,
+			address: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+		},
+		{
+			name:   "p2pk",
+			script: "2102aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaac",
+			class:  PubKey,
+		},
+		{
+			name:   "op_return",
+			script: "6a0b68656c6c6f20776f726c64",
+			class:  NullData,
+		},
+		{
+			name:   "bare 1-of-1 multisig",
+			script: "512102aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa51ae",
+			class:  Multisig,
+		},
+		{
+			name:   "nonstandard",
+			script: "75",
+			class:  NonStandard,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := mustHex(t, tt.script)
+			if got := ClassifyScript(script); got != tt.class {
+				t.Errorf("ClassifyScript() = %s, expected %s", got, tt.class)
+			}
+			addr, err := ExtractAddress(script, &chaincfg.MainNetParams)
+			if tt.address == "" {
+				if err == nil {
+					t.Errorf("ExtractAddress() expected error, got %s", addr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractAddress() error = %v", err)
+			}
+			if addr != tt.address {
+				t.Errorf("ExtractAddress() = %s, expected %s", addr, tt.address)
+			}
+			back, err := AddressScript(addr, &chaincfg.MainNetParams)
+			if err != nil {
+				t.Fatalf("AddressScript() error = %v", err)
+			}
+			if hex.EncodeToString(back) != tt.script {
+				t.Errorf("AddressScript() = %x, expected %s", back, tt.script)
+			}
+		})
+	}
+}
+
+func TestAddressScriptErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		addr   string
+		params *chaincfg.Params
+	}{
+		{"testnet address on mainnet", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", &chaincfg.MainNetParams},
+		{"mainnet p2pkh on testnet", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", &chaincfg.TestNet3Params},
+		{"bad checksum", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", &chaincfg.MainNetParams},
+		{"garbage", "not an address", &chaincfg.MainNetParams},
+		{"nil params", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if script, err := AddressScript(tt.addr, tt.params); err == nil {
+				t.Errorf("Expected error, got %x", script)
+			}
+		})
+	}
+}
+
+// TestScriptNum tests minimal number pushes around the OP_n and sign-bit boundaries
+func TestScriptNum(t *testing.T) {
+	tests := []struct {
+		n        int64
+		expected string
+	}{
+		{0, "00"},
+		{1, "51"},
+		{16, "60"},
+		{17, "0111"},
+		{127, "017f"},
+		{128, "028000"},
+		{144, "029000"},
+		{255, "02ff00"},
+		{256, "020001"},
+		{999, "02e703"},
+		{65535, "03ffff00"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(ScriptNum(tt.n)); got != tt.expected {
+			t.Errorf("ScriptNum(%d) = %s, expected %s", tt.n, got, tt.expected)
+		}
+	}
+}
+
+func TestParsePushes(t *testing.T) {
+	// OP_0, push 2 bytes, OP_PUSHDATA1 1 byte, OP_3
+	items, err := ParsePushes(mustHex(t, "000211224c013353"))
+	if err != nil {
+		t.Fatalf("ParsePushes() error = %v", err)
+	}
+	expected := []string{"", "1122", "33", "03"}
+	if len(items) != len(expected) {
+		t.Fatalf("Expected %d items, got %d", len(expected), len(items))
+	}
+	for i, want := range expected {
+		if hex.EncodeToString(items[i]) != want {
+			t.Errorf("item %d = %x, expected %s", i, items[i], want)
+		}
+	}
+
+	for _, bad := range []string{"76", "0511", "4c"} {
+		if _, err := ParsePushes(mustHex(t, bad)); err == nil {
+			t.Errorf("ParsePushes(%s) expected error", bad)
+		}
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	tests := []struct {
+		script   string
+		expected string
+	}{
+		{"76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac", "OP_DUP OP_HASH160 62e907b15cbf27d5425399ebf6f0fb50ebb88f18 OP_EQUALVERIFY OP_CHECKSIG"},
+		{"0014751e76e8199196d454941c45d1b3a323f1433bd6", "0 751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"6a0b68656c6c6f20776f726c64", "OP_RETURN 68656c6c6f20776f726c64"},
+		{"52ae", "OP_2 OP_CHECKMULTISIG"},
+		{"05aa", "[error]"},
+	}
+
+	for _, tt := range tests {
+		if got := Disassemble(mustHex(t, tt.script)); got != tt.expected {
+			t.Errorf("Disassemble(%s) = %q, expected %q", tt.script, got, tt.expected)
+		}
+	}
+}
+
+func TestExtractAddressAltcoins(t *testing.T) {
+	p2pkh := mustHex(t, "76a914751e76e8199196d454941c45d1b3a323f1433bd688ac")
+	p2wpkh := mustHex(t, "0014751e76e8199196d454941c45d1b3a323f1433bd6")
+
+	tests := []struct {
+		params *chaincfg.Params
+		script []byte
+		prefix string
+	}{
+		{&chaincfg.LitecoinMainNetParams, p2pkh, "L"},
+		{&chaincfg.LitecoinMainNetParams, p2wpkh, "ltc1q"},
+		{&chaincfg.DogecoinMainNetParams, p2pkh, "D"},
+	}
+	for _, tt := range tests {
+		addr, err := ExtractAddress(tt.script, tt.params)
+		if err != nil {
+			t.Fatalf("ExtractAddress(%s) error = %v", tt.params.Name, err)
+		}
+		if !strings.HasPrefix(addr, tt.prefix) {
+			t.Errorf("ExtractAddress(%s) = %s, expected prefix %s", tt.params.Name, addr, tt.prefix)
+		}
+	}
+
+	if _, err := ExtractAddress(p2wpkh, &chaincfg.DogecoinMainNetParams); err == nil {
+		t.Error("Expected error rendering a segwit address on Dogecoin")
+	}
+}
@@ -0,0 +1,207 @@
+package tx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+)
+
+// Signature hashes: the digest a signature commits to for one input
+//
+// Segwit v0 inputs use BIP143, which commits to the spent amount so hardware
+// signers can verify the fee. Taproot inputs use BIP341, which additionally
+// commits to every spent amount and scriptPubKey. Only the key path is
+// covered here (no annex, no tapscript extension).
+
+// WitnessV0SigHash computes the BIP143 signature hash for input idx
+//
+// scriptCode is the script being executed: for P2WPKH the P2PKH script of the
+// key hash, for P2WSH the witness script. value is the amount of the spent output.
+//
+// Example:
+//
+//	scriptCode := P2PKHScript(pubKeyHash) // OP_DUP OP_HASH160 <20 bytes> OP_EQUALVERIFY OP_CHECKSIG
+//	digest, err := WitnessV0SigHash(tx, 0, scriptCode, 100000, SigHashAll)
+func WitnessV0SigHash(tx *Transaction, idx int, scriptCode []byte, value int64, hashType byte) ([32]byte, error) {
+	if idx < 0 || idx >= len(tx.Inputs) {
+		return [32]byte{}, fmt.Errorf("input index %d out of range", idx)
+	}
+	base := hashType &^ SigHashAnyoneCanPay
+	anyoneCanPay := hashType&SigHashAnyoneCanPay != 0
+
+	// Step 1: Commitments to all inputs and outputs, zeroed where the type excludes them
+	var hashPrevouts, hashSequence, hashOutputs [32]byte
+	if !anyoneCanPay {
+		var buf bytes.Buffer
+		for _, in := range tx.Inputs {
+			writeOutPoint(&buf, in.PreviousOutPoint)
+		}
+		hashPrevouts = hash.SHA256D(buf.Bytes())
+	}
+	if !anyoneCanPay && base != SigHashSingle && base != SigHashNone {
+		var buf bytes.Buffer
+		for _, in := range tx.Inputs {
+			writeUint32(&buf, in.Sequence)
+		}
+		hashSequence = hash.SHA256D(buf.Bytes())
+	}
+	switch {
+	case base != SigHashSingle && base != SigHashNone:
+		var buf bytes.Buffer
+		for _, out := range tx.Outputs {
+			writeTxOut(&buf, out)
+		}
+		hashOutputs = hash.SHA256D(buf.Bytes())
+	case base == SigHashSingle && idx < len(tx.Outputs):
+		var buf bytes.Buffer
+		writeTxOut(&buf, tx.Outputs[idx])
+		hashOutputs = hash.SHA256D(buf.Bytes())
+	}
+
+	// Step 2: Serialize the preimage
+	in := tx.Inputs[idx]
+	var buf bytes.Buffer
+	writeUint32(&buf, uint32(tx.Version))
+	buf.Write(hashPrevouts[:])
+	buf.Write(hashSequence[:])
+	writeOutPoint(&buf, in.PreviousOutPoint)
+	WriteVarBytes(&buf, scriptCode)
+	writeUint64(&buf, uint64(value))
+	writeUint32(&buf, in.Sequence)
+	buf.Write(hashOutputs[:])
+	writeUint32(&buf, tx.LockTime)
+	writeUint32(&buf, uint32(hashType))
+
+	return hash.SHA256D(buf.Bytes()), nil
+}
+
+// TaprootSigHash computes the BIP341 key path signature hash for input idx
+//
+// prevouts holds the output spent by every input, in input order: BIP341
+// commits to all amounts and scriptPubKeys, not only the signed input's.
+//
+// Example:
+//
+//	digest, err := TaprootSigHash(tx, 0, []*TxOut{spent}, SigHashDefault)
+//	sig, _ := schnorr.Sign(tweakedKey, digest[:])
+func TaprootSigHash(tx *Transaction, idx int, prevouts []*TxOut, hashType byte) ([32]byte, error) {
+	return taprootSigHash(tx, idx, prevouts, hashType, nil)
+}
+
+// TaprootScriptSigHash computes the BIP342 signature hash for a script path spend of input idx
+//
+// It is TaprootSigHash with the extension of BIP342: the hash also commits to
+// the executed leaf (leafHash), key version 0 and no OP_CODESEPARATOR.
+//
+// Example:
+//
+//	leafHash := hash.TaggedSHA256("TapLeaf", []byte{0xc0}, compactSize, leafScript)
+//	digest, err := TaprootScriptSigHash(tx, 0, []*TxOut{spent}, SigHashDefault, leafHash)
+func TaprootScriptSigHash(tx *Transaction, idx int, prevouts []*TxOut, hashType byte, leafHash [32]byte) ([32]byte, error) {
+	return taprootSigHash(tx, idx, prevouts, hashType, &leafHash)
+}
+
+// taprootSigHash computes the BIP341 signature hash, with the BIP342 extension when leafHash is set
+func taprootSigHash(tx *Transaction, idx int, prevouts []*TxOut, hashType byte, leafHash *[32]byte) ([32]byte, error) {
+	if idx < 0 || idx >= len(tx.Inputs) {
+		return [32]byte{}, fmt.Errorf("input index %d out of range", idx)
+	}
+	if len(prevouts) != len(tx.Inputs) {
+		return [32]byte{}, errors.New("one previous output per input is required")
+	}
+	base := hashType &^ SigHashAnyoneCanPay
+	anyoneCanPay := hashType&SigHashAnyoneCanPay != 0
+	if hashType != SigHashDefault && (base < SigHashAll || base > SigHashSingle) {
+		return [32]byte{}, fmt.Errorf("invalid taproot sighash type %#x", hashType)
+	}
+	if base == SigHashSingle && idx >= len(tx.Outputs) {
+		return [32]byte{}, errors.New("SIGHASH_SINGLE without a matching output")
+	}
+
+	// Step 1: Epoch, hash type and transaction data
+	var msg bytes.Buffer
+	msg.WriteByte(0x00)
+	msg.WriteByte(hashType)
+	writeUint32(&msg, uint32(tx.Version))
+	writeUint32(&msg, tx.LockTime)
+
+	// Step 2: Single-SHA256 commitments to every input (not for ANYONECANPAY)
+	if !anyoneCanPay {
+		var outpoints, amounts, scripts, sequences bytes.Buffer
+		for i, in := range tx.Inputs {
+			writeOutPoint(&outpoints, in.PreviousOutPoint)
+			writeUint64(&amounts, uint64(prevouts[i].Value))
+			WriteVarBytes(&scripts, prevouts[i].PkScript)
+			writeUint32(&sequences, in.Sequence)
+		}
+		for _, b := range []*bytes.Buffer{&outpoints, &amounts, &scripts, &sequences} {
+			h := sha256.Sum256(b.Bytes())
+			msg.Write(h[:])
+		}
+	}
+
+	// Step 3: Commitment to every output (ALL and DEFAULT only)
+	if base != SigHashNone && base != SigHashSingle {
+		var outputs bytes.Buffer
+		for _, out := range tx.Outputs {
+			writeTxOut(&outputs, out)
+		}
+		h := sha256.Sum256(outputs.Bytes())
+		msg.Write(h[:])
+	}
+
+	// Step 4: Data about this input (spend type: ext_flag·2, no annex)
+	if leafHash == nil {
+		msg.WriteByte(0x00)
+	} else {
+		msg.WriteByte(0x02)
+	}
+	if anyoneCanPay {
+		in := tx.Inputs[idx]
+		writeOutPoint(&msg, in.PreviousOutPoint)
+		writeUint64(&msg, uint64(prevouts[idx].Value))
+		WriteVarBytes(&msg, prevouts[idx].PkScript)
+		writeUint32(&msg, in.Sequence)
+	} else {
+		writeUint32(&msg, uint32(idx))
+	}
+
+	// Step 5: Data about this output (SINGLE only)
+	if base == SigHashSingle {
+		var output bytes.Buffer
+		writeTxOut(&output, tx.Outputs[idx])
+		h := sha256.Sum256(output.Bytes())
+		msg.Write(h[:])
+	}
+
+	// Step 6: Script path extension (BIP342): leaf hash, key version, no code separator
+	if leafHash != nil {
+		msg.Write(leafHash[:])
+		msg.WriteByte(0x00)
+		writeUint32(&msg, 0xffffffff)
+	}
+
+	return hash.TaggedSHA256("TapSighash", msg.Bytes()), nil
+}
+
+// writeOutPoint writes an outpoint as [txid (32 bytes)][index (4 bytes LE)]
+func writeOutPoint(buf *bytes.Buffer, o OutPoint) {
+	buf.Write(o.Hash[:])
+	writeUint32(buf, o.Index)
+}
+
+// writeTxOut writes an output as [value (8 bytes LE)][scriptPubKey]
+func writeTxOut(buf *bytes.Buffer, out *TxOut) {
+	writeUint64(buf, uint64(out.Value))
+	WriteVarBytes(buf, out.PkScript)
+}
+
+func writeUint64(buf *bytes.Buffer, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	buf.Write(b[:])
+}
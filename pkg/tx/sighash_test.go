@@ -0,0 +1,114 @@
+package tx
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// TestWitnessV0SigHash checks the native P2WPKH example from BIP143
+func TestWitnessV0SigHash(t *testing.T) {
+	unsigned, err := DeserializeHex("0100000002fff7f7881a8099afa6940d42d1e7f6362bec38171ea3edf433541db4e4ad969f0000000000eeffffffef51e1b804cc89d182d279655c3aa89e815b1b309fe287d9b2b55d57b90ec68a0100000000ffffffff02202cb206000000001976a9148280b37df378db99f66f85c95a783a76ac7a6d5988ac9093510d000000001976a9143bde42dbee7e4dbe6a21b2d50ce2f0167faa815988ac11000000")
+	if err != nil {
+		t.Fatalf("DeserializeHex failed: %v", err)
+	}
+	scriptCode := P2PKHScript(mustHex(t, "1d0f172a0ecb48aee1be1f2687d2963ae33f71a1"))
+
+	digest, err := WitnessV0SigHash(unsigned, 1, scriptCode, 600000000, SigHashAll)
+	if err != nil {
+		t.Fatalf("WitnessV0SigHash failed: %v", err)
+	}
+	if got := hex.EncodeToString(digest[:]); got != "c37af31116d1b27caf68aae9e3ac82f1477929014d5b917657d0eb49478cb670" {
+		t.Errorf("Expected BIP143 sighash, got %s", got)
+	}
+
+	if _, err := WitnessV0SigHash(unsigned, 2, scriptCode, 0, SigHashAll); err == nil {
+		t.Error("Expected error for input index out of range")
+	}
+}
+
+// TestTaprootSigHash tests the BIP341 hash type handling
+//
+// The digest itself is checked against the BIP322 taproot vector in pkg/bip322.
+func TestTaprootSigHash(t *testing.T) {
+	spend := &Transaction{
+		Version: 2,
+		Inputs: []*TxIn{
+			{PreviousOutPoint: OutPoint{Hash: [32]byte{0x01}}, Sequence: 0xffffffff},
+			{PreviousOutPoint: OutPoint{Hash: [32]byte{0x02}, Index: 1}, Sequence: 0xfffffffd},
+		},
+		Outputs: []*TxOut{{Value: 1000, PkScript: []byte{OP_RETURN}}},
+	}
+	prevouts := []*TxOut{
+		{Value: 5000, PkScript: mustHex(t, "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")},
+		{Value: 7000, PkScript: mustHex(t, "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")},
+	}
+
+	// Every valid hash type gives a distinct digest
+	seen := map[[32]byte]string{}
+	for _, hashType := range []byte{0x00, 0x01, 0x02, 0x03, 0x81, 0x82, 0x83} {
+		digest, err := TaprootSigHash(spend, 0, prevouts, hashType)
+		if err != nil {
+			t.Fatalf("TaprootSigHash(%s) failed: %v", SigHashName(hashType), err)
+		}
+		if other, ok := seen[digest]; ok {
+			t.Errorf("%s and %s produced the same digest", SigHashName(hashType), other)
+		}
+		seen[digest] = SigHashName(hashType)
+	}
+
+	// ANYONECANPAY does not commit to the other inputs' amounts
+	acp, _ := TaprootSigHash(spend, 0, prevouts, SigHashAll|SigHashAnyoneCanPay)
+	changed := []*TxOut{prevouts[0], {Value: 1, PkScript: prevouts[1].PkScript}}
+	acpChanged, _ := TaprootSigHash(spend, 0, changed, SigHashAll|SigHashAnyoneCanPay)
+	if acp != acpChanged {
+		t.Error("Expected ANYONECANPAY digest to ignore other inputs")
+	}
+	all, _ := TaprootSigHash(spend, 0, prevouts, SigHashAll)
+	allChanged, _ := TaprootSigHash(spend, 0, changed, SigHashAll)
+	if all == allChanged {
+		t.Error("Expected SIGHASH_ALL digest to commit to every spent amount")
+	}
+
+	tests := []struct {
+		name     string
+		idx      int
+		prevouts []*TxOut
+		hashType byte
+	}{
+		{"invalid hash type", 0, prevouts, 0x04},
+		{"invalid hash type with anyonecanpay", 0, prevouts, 0x80},
+		{"single without output", 1, prevouts, SigHashSingle},
+		{"missing prevout", 0, prevouts[:1], SigHashDefault},
+		{"index out of range", 2, prevouts, SigHashDefault},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := TaprootSigHash(spend, tt.idx, tt.prevouts, tt.hashType); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+// TestTaprootScriptSigHash tests that script path digests commit to the leaf
+func TestTaprootScriptSigHash(t *testing.T) {
+	spend := &Transaction{
+		Version: 2,
+		Inputs:  []*TxIn{{PreviousOutPoint: OutPoint{Hash: [32]byte{0x01}}, Sequence: 144}},
+		Outputs: []*TxOut{{Value: 1000, PkScript: []byte{OP_RETURN}}},
+	}
+	prevouts := []*TxOut{{Value: 5000, PkScript: mustHex(t, "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")}}
+
+	keyPath, _ := TaprootSigHash(spend, 0, prevouts, SigHashDefault)
+	leafA, err := TaprootScriptSigHash(spend, 0, prevouts, SigHashDefault, [32]byte{0xaa})
+	if err != nil {
+		t.Fatalf("TaprootScriptSigHash failed: %v", err)
+	}
+	leafB, _ := TaprootScriptSigHash(spend, 0, prevouts, SigHashDefault, [32]byte{0xbb})
+	if leafA == keyPath || leafA == leafB {
+		t.Error("Expected distinct digests for the key path and each leaf")
+	}
+	if _, err := TaprootScriptSigHash(spend, 0, prevouts, 0x04, [32]byte{0xaa}); err == nil {
+		t.Error("Expected error for an invalid hash type")
+	}
+}
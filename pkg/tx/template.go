@@ -0,0 +1,368 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/txsize"
+)
+
+// Transaction templates: unsigned transactions with correctly sized placeholder signatures
+//
+// A coordinator builds the template, agrees on the fee using the placeholder size,
+// distributes it for signing and then swaps the real signatures in. Placeholders are
+// the maximum signature size, so the final transaction is never larger than the
+// template and the agreed fee rate is always met.
+
+// TemplateInput describes an input to be spent and the data needed to assemble its witness
+type TemplateInput struct {
+	OutPoint OutPoint
+	Value    int64                // Amount of the spent output in satoshis
+	Sequence uint32               // nSequence (0 is replaced by 0xffffffff)
+	Template txsize.InputTemplate // Script type and threshold parameters
+
+	// PubKey is the 33-byte compressed key for P2PKH, P2WPKH and P2SH-P2WPKH
+	PubKey []byte
+	// Script is the redeem script (P2SH), witness script (P2WSH) or leaf script (P2TR script path)
+	Script []byte
+	// ControlBlock is the taproot control block (P2TR script path only)
+	ControlBlock []byte
+}
+
+// Template is an unsigned transaction whose signature slots hold placeholders
+type Template struct {
+	Tx     *Transaction
+	inputs []TemplateInput
+	sigs   [][][]byte // Signature slots of every input
+	filled [][]bool   // Whether each slot holds a real signature
+}
+
+// Builder assembles a Template
+//
+// Example:
+//
+//	b := NewBuilder()
+//	b.AddInput(TemplateInput{OutPoint: op, Value: 100000, PubKey: pub,
+//		Template: txsize.InputTemplate{Type: txsize.P2WPKH}})
+//	b.AddOutput(90000, destinationScript)
+//	tmpl, err := b.Build()
+type Builder struct {
+	Version  int32
+	LockTime uint32
+	inputs   []TemplateInput
+	outputs  []*TxOut
+}
+
+// NewBuilder creates a builder for a version 2 transaction
+func NewBuilder() *Builder {
+	return &Builder{Version: 2}
+}
+
+// AddInput appends an input to the transaction
+func (b *Builder) AddInput(in TemplateInput) {
+	b.inputs = append(b.inputs, in)
+}
+
+// AddOutput appends an output to the transaction
+func (b *Builder) AddOutput(value int64, pkScript []byte) {
+	b.outputs = append(b.outputs, &TxOut{Value: value, PkScript: pkScript})
+}
+
+// Build validates the inputs and creates the template with placeholder signatures
+func (b *Builder) Build() (*Template, error) {
+	if len(b.inputs) == 0 {
+		return nil, errors.New("at least one input is required")
+	}
+	if len(b.outputs) == 0 {
+		return nil, errors.New("at least one output is required")
+	}
+
+	// Step 1: Create the unsigned transaction skeleton
+	t := &Template{
+		Tx:     &Transaction{Version: b.Version, LockTime: b.LockTime},
+		inputs: append([]TemplateInput{}, b.inputs...),
+		sigs:   make([][][]byte, len(b.inputs)),
+		filled: make([][]bool, len(b.inputs)),
+	}
+	for _, out := range b.outputs {
+		t.Tx.Outputs = append(t.Tx.Outputs, &TxOut{Value: out.Value, PkScript: out.PkScript})
+	}
+
+	// Step 2: Fill every signature slot with a maximum-size placeholder
+	for i, in := range t.inputs {
+		slots, err := placeholderSlots(in)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", i, err)
+		}
+		t.sigs[i] = slots
+		t.filled[i] = make([]bool, len(slots))
+
+		sequence := in.Sequence
+		if sequence == 0 {
+			sequence = 0xffffffff
+		}
+		t.Tx.Inputs = append(t.Tx.Inputs, &TxIn{PreviousOutPoint: in.OutPoint, Sequence: sequence})
+		t.assemble(i)
+	}
+
+	return t, nil
+}
+
+// placeholderSlots validates an input and returns its placeholder signature slots
+func placeholderSlots(in TemplateInput) ([][]byte, error) {
+	tmpl := in.Template
+	ecdsaPlaceholder := func() []byte { return make([]byte, txsize.MaxECDSASigSize) }
+	schnorrPlaceholder := func() []byte {
+		if tmpl.SighashType {
+			return make([]byte, txsize.SchnorrSigWithHashTypeSize)
+		}
+		return make([]byte, txsize.SchnorrSigSize)
+	}
+
+	switch tmpl.Type {
+	case txsize.P2PKH, txsize.P2WPKH, txsize.P2SHP2WPKH:
+		if len(in.PubKey) != txsize.CompressedPubKeySize {
+			return nil, errors.New("a 33-byte compressed public key is required")
+		}
+		return [][]byte{ecdsaPlaceholder()}, nil
+
+	case txsize.P2SHMultisig, txsize.P2WSHMultisig:
+		if tmpl.Threshold <= 0 || tmpl.Threshold > tmpl.Total {
+			return nil, errors.New("invalid multisig threshold")
+		}
+		if len(in.Script) != txsize.MultisigScriptSize(tmpl.Total) {
+			return nil, fmt.Errorf("expected a %d-byte multisig script", txsize.MultisigScriptSize(tmpl.Total))
+		}
+		slots := make([][]byte, tmpl.Threshold)
+		for i := range slots {
+			slots[i] = ecdsaPlaceholder()
+		}
+		return slots, nil
+
+	case txsize.P2TRKeyPath:
+		return [][]byte{schnorrPlaceholder()}, nil
+
+	case txsize.P2TRScriptPath:
+		if tmpl.Threshold <= 0 || tmpl.Threshold > tmpl.Total {
+			return nil, errors.New("invalid script path threshold")
+		}
+		if len(in.Script) == 0 || len(in.ControlBlock) < 33 {
+			return nil, errors.New("leaf script and control block are required")
+		}
+		// One slot per key; only Threshold of them will carry signatures
+		slots := make([][]byte, tmpl.Total)
+		for i := range slots {
+			if i < tmpl.Threshold {
+				slots[i] = schnorrPlaceholder()
+			} else {
+				slots[i] = []byte{}
+			}
+		}
+		return slots, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported script type: %s", tmpl.Type)
+	}
+}
+
+// assemble rebuilds the scriptSig and witness of input i from its current slots
+func (t *Template) assemble(i int) {
+	in, txIn, slots := t.inputs[i], t.Tx.Inputs[i], t.sigs[i]
+	txIn.SignatureScript, txIn.Witness = nil, nil
+
+	switch in.Template.Type {
+	case txsize.P2PKH:
+		// scriptSig: <sig> <pubkey>
+		txIn.SignatureScript = appendPush(appendPush(nil, slots[0]), in.PubKey)
+
+	case txsize.P2WPKH:
+		// witness: <sig> <pubkey>
+		txIn.Witness = [][]byte{slots[0], in.PubKey}
+
+	case txsize.P2SHP2WPKH:
+		// scriptSig: <0 <hash160(pubkey)>>, witness: <sig> <pubkey>
+		pubKeyHash := hash.Hash160(in.PubKey)
+		program := append([]byte{OP_0, 0x14}, pubKeyHash[:]...)
+		txIn.SignatureScript = appendPush(nil, program)
+		txIn.Witness = [][]byte{slots[0], in.PubKey}
+
+	case txsize.P2SHMultisig:
+		// scriptSig: OP_0 <sig_1> ... <sig_m> <redeemScript>
+		script := []byte{OP_0}
+		for _, sig := range slots {
+			script = appendPush(script, sig)
+		}
+		txIn.SignatureScript = appendPush(script, in.Script)
+
+	case txsize.P2WSHMultisig:
+		// witness: <> <sig_1> ... <sig_m> <witnessScript>
+		txIn.Witness = append([][]byte{{}}, slots...)
+		txIn.Witness = append(txIn.Witness, in.Script)
+
+	case txsize.P2TRKeyPath:
+		// witness: <sig>
+		txIn.Witness = [][]byte{slots[0]}
+
+	case txsize.P2TRScriptPath:
+		// witness: <sig or empty per key> <leafScript> <controlBlock>
+		txIn.Witness = append([][]byte{}, slots...)
+		txIn.Witness = append(txIn.Witness, in.Script, in.ControlBlock)
+	}
+}
+
+// appendPush appends a minimal data push of data to script
+func appendPush(script, data []byte) []byte {
+	switch n := len(data); {
+	case n < OP_PUSHDATA1:
+		script = append(script, byte(n))
+	case n <= 0xff:
+		script = append(script, OP_PUSHDATA1, byte(n))
+	default:
+		script = append(script, OP_PUSHDATA2, byte(n), byte(n>>8))
+	}
+	return append(script, data...)
+}
+
+// InputValue returns the total value of all inputs
+func (t *Template) InputValue() int64 {
+	var total int64
+	for _, in := range t.inputs {
+		total += in.Value
+	}
+	return total
+}
+
+// OutputValue returns the total value of all outputs
+func (t *Template) OutputValue() int64 {
+	var total int64
+	for _, out := range t.Tx.Outputs {
+		total += out.Value
+	}
+	return total
+}
+
+// Fee returns the fee currently paid by the template (inputs - outputs)
+func (t *Template) Fee() int64 {
+	return t.InputValue() - t.OutputValue()
+}
+
+// VSize returns the virtual size of the template; the signed transaction is never larger
+func (t *Template) VSize() int {
+	return t.Tx.VSize()
+}
+
+// ApplyFeeRate sets the value of the change output so the template pays satPerVByte
+//
+// This must be done before signing: changing an output invalidates every signature.
+//
+// Example:
+//
+//	tmpl.Tx.Outputs[1] is the change output
+//	err := tmpl.ApplyFeeRate(1, 10) // 10 sat/vB
+func (t *Template) ApplyFeeRate(changeIndex int, satPerVByte int64) error {
+	if changeIndex < 0 || changeIndex >= len(t.Tx.Outputs) {
+		return fmt.Errorf("invalid change output index: %d", changeIndex)
+	}
+	if t.hasSignatures() {
+		return errors.New("cannot change outputs after signatures were added")
+	}
+
+	// Step 1: Fee from the placeholder size (an upper bound of the final size)
+	fee := int64(t.VSize()) * satPerVByte
+
+	// Step 2: Change = inputs - other outputs - fee
+	change := t.InputValue() - (t.OutputValue() - t.Tx.Outputs[changeIndex].Value) - fee
+	if change < 0 {
+		return fmt.Errorf("insufficient funds: short by %d sat", -change)
+	}
+	t.Tx.Outputs[changeIndex].Value = change
+	return nil
+}
+
+// hasSignatures reports whether any real signature was added
+func (t *Template) hasSignatures() bool {
+	for _, filled := range t.filled {
+		for _, f := range filled {
+			if f {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetSignature swaps a real signature into a slot of an input
+//
+// Slots are numbered in witness/scriptSig order: for multisig, slot j is the j-th
+// signature in key order; for a P2TR script path, slot j is the j-th witness item
+// (use an empty signature for keys that do not sign).
+//
+// Example:
+//
+//	err := tmpl.SetSignature(0, 0, append(derSig, SigHashAll))
+func (t *Template) SetSignature(inputIndex, slot int, sig []byte) error {
+	if inputIndex < 0 || inputIndex >= len(t.inputs) {
+		return fmt.Errorf("invalid input index: %d", inputIndex)
+	}
+	if slot < 0 || slot >= len(t.sigs[inputIndex]) {
+		return fmt.Errorf("invalid signature slot: %d", slot)
+	}
+
+	// Step 1: A real signature may not exceed its placeholder
+	placeholder := placeholderSize(t.inputs[inputIndex].Template)
+	if len(sig) > placeholder {
+		return fmt.Errorf("signature is %d bytes, exceeds %d-byte placeholder", len(sig), placeholder)
+	}
+	if len(sig) == 0 && t.inputs[inputIndex].Template.Type != txsize.P2TRScriptPath {
+		return errors.New("signature cannot be empty")
+	}
+
+	// Step 2: Store it and rebuild the scriptSig/witness
+	t.sigs[inputIndex][slot] = append([]byte{}, sig...)
+	t.filled[inputIndex][slot] = true
+	t.assemble(inputIndex)
+	return nil
+}
+
+// placeholderSize returns the placeholder signature size of an input template
+func placeholderSize(tmpl txsize.InputTemplate) int {
+	switch tmpl.Type {
+	case txsize.P2TRKeyPath, txsize.P2TRScriptPath:
+		if tmpl.SighashType {
+			return txsize.SchnorrSigWithHashTypeSize
+		}
+		return txsize.SchnorrSigSize
+	default:
+		return txsize.MaxECDSASigSize
+	}
+}
+
+// Finalize returns the signed transaction once every placeholder has been replaced
+//
+// For a P2TR script path, exactly Threshold slots must hold non-empty signatures.
+func (t *Template) Finalize() (*Transaction, error) {
+	for i, in := range t.inputs {
+		if in.Template.Type == txsize.P2TRScriptPath {
+			signatures := 0
+			for j, sig := range t.sigs[i] {
+				if !t.filled[i][j] && len(sig) > 0 {
+					return nil, fmt.Errorf("input %d: slot %d still holds a placeholder", i, j)
+				}
+				if len(sig) > 0 {
+					signatures++
+				}
+			}
+			if signatures != in.Template.Threshold {
+				return nil, fmt.Errorf("input %d: expected %d signatures, got %d", i, in.Template.Threshold, signatures)
+			}
+			continue
+		}
+		for j, filled := range t.filled[i] {
+			if !filled {
+				return nil, fmt.Errorf("input %d: slot %d still holds a placeholder", i, j)
+			}
+		}
+	}
+	return t.Tx, nil
+}
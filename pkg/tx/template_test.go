@@ -0,0 +1,190 @@
+package tx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/neverDefined/cryptography-playground/pkg/txsize"
+)
+
+// multisigScript builds a bare m-of-n OP_CHECKMULTISIG script from private keys 1..n
+func multisigScript(m, n int) []byte {
+	script := []byte{OP_1 + byte(m-1)}
+	for i := 1; i <= n; i++ {
+		_, pub := btcec.PrivKeyFromBytes([]byte{byte(i)})
+		script = appendPush(script, pub.SerializeCompressed())
+	}
+	return append(script, OP_1+byte(n-1), OP_CHECKMULTISIG)
+}
+
+// newTemplateInputs returns one input of every supported script type
+func newTemplateInputs() []TemplateInput {
+	_, pub := btcec.PrivKeyFromBytes([]byte{0x01})
+	compressed := pub.SerializeCompressed()
+	leaf := bytes.Repeat([]byte{0x51}, 70)
+
+	return []TemplateInput{
+		{OutPoint: OutPoint{Hash: [32]byte{1}}, Value: 10000, PubKey: compressed, Template: txsize.InputTemplate{Type: txsize.P2PKH}},
+		{OutPoint: OutPoint{Hash: [32]byte{2}}, Value: 10000, PubKey: compressed, Template: txsize.InputTemplate{Type: txsize.P2WPKH}},
+		{OutPoint: OutPoint{Hash: [32]byte{3}}, Value: 10000, PubKey: compressed, Template: txsize.InputTemplate{Type: txsize.P2SHP2WPKH}},
+		{OutPoint: OutPoint{Hash: [32]byte{4}}, Value: 10000, Script: multisigScript(2, 3), Template: txsize.InputTemplate{Type: txsize.P2SHMultisig, Threshold: 2, Total: 3}},
+		{OutPoint: OutPoint{Hash: [32]byte{5}}, Value: 10000, Script: multisigScript(2, 3), Template: txsize.InputTemplate{Type: txsize.P2WSHMultisig, Threshold: 2, Total: 3}},
+		{OutPoint: OutPoint{Hash: [32]byte{6}}, Value: 10000, Template: txsize.InputTemplate{Type: txsize.P2TRKeyPath}},
+		{
+			OutPoint: OutPoint{Hash: [32]byte{7}}, Value: 10000, Script: leaf, ControlBlock: make([]byte, 33+32),
+			Template: txsize.InputTemplate{Type: txsize.P2TRScriptPath, Threshold: 2, Total: 3, LeafScriptSize: len(leaf), MerkleDepth: 1},
+		},
+	}
+}
+
+func TestTemplateMatchesEstimate(t *testing.T) {
+	b := NewBuilder()
+	var templates []txsize.InputTemplate
+	for _, in := range newTemplateInputs() {
+		b.AddInput(in)
+		templates = append(templates, in.Template)
+	}
+	b.AddOutput(50000, make([]byte, 22))
+	b.AddOutput(0, make([]byte, 34))
+
+	tmpl, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	est, err := txsize.EstimateTx(templates, []int{22, 34})
+	if err != nil {
+		t.Fatalf("EstimateTx() error = %v", err)
+	}
+	if tmpl.Tx.Weight() != est.Weight {
+		t.Errorf("Template weight = %d, expected estimate %d", tmpl.Tx.Weight(), est.Weight)
+	}
+	if tmpl.VSize() != est.VSize() {
+		t.Errorf("Template vsize = %d, expected estimate %d", tmpl.VSize(), est.VSize())
+	}
+
+	// The placeholder transaction serializes and parses like any other
+	parsed, err := Deserialize(tmpl.Tx.Serialize())
+	if err != nil {
+		t.Fatalf("Deserialize() error = %v", err)
+	}
+	if parsed.TxID() != tmpl.Tx.TxID() {
+		t.Error("Round trip changed the txid")
+	}
+}
+
+func TestTemplateSignAndFinalize(t *testing.T) {
+	priv, pub := btcec.PrivKeyFromBytes([]byte{0x01})
+
+	b := NewBuilder()
+	b.AddInput(TemplateInput{OutPoint: OutPoint{Hash: [32]byte{1}}, Value: 100000, PubKey: pub.SerializeCompressed(), Template: txsize.InputTemplate{Type: txsize.P2WPKH}})
+	b.AddInput(TemplateInput{OutPoint: OutPoint{Hash: [32]byte{2}}, Value: 50000, Script: multisigScript(2, 3), Template: txsize.InputTemplate{Type: txsize.P2WSHMultisig, Threshold: 2, Total: 3}})
+	b.AddOutput(120000, make([]byte, 22))
+	b.AddOutput(0, make([]byte, 34))
+
+	tmpl, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	// Step 1: Agree on the fee before signing
+	if err := tmpl.ApplyFeeRate(1, 10); err != nil {
+		t.Fatalf("ApplyFeeRate() error = %v", err)
+	}
+	placeholderVSize := tmpl.VSize()
+	if tmpl.Fee() != int64(placeholderVSize)*10 {
+		t.Errorf("Fee = %d, expected %d", tmpl.Fee(), placeholderVSize*10)
+	}
+	if tmpl.Tx.Outputs[1].Value != 150000-120000-tmpl.Fee() {
+		t.Errorf("Unexpected change value %d", tmpl.Tx.Outputs[1].Value)
+	}
+
+	if _, err := tmpl.Finalize(); err == nil {
+		t.Error("Expected error finalizing with placeholders")
+	}
+
+	// Step 2: Swap real signatures in
+	digest := sha256.Sum256([]byte("not a real sighash"))
+	sig := append(ecdsa.Sign(priv, digest[:]).Serialize(), SigHashAll)
+	for _, slot := range []struct{ input, slot int }{{0, 0}, {1, 0}, {1, 1}} {
+		if err := tmpl.SetSignature(slot.input, slot.slot, sig); err != nil {
+			t.Fatalf("SetSignature(%d, %d) error = %v", slot.input, slot.slot, err)
+		}
+	}
+
+	if err := tmpl.ApplyFeeRate(1, 20); err == nil {
+		t.Error("Expected error changing the fee after signing")
+	}
+
+	signed, err := tmpl.Finalize()
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if !bytes.Equal(signed.Inputs[0].Witness[0], sig) || !bytes.Equal(signed.Inputs[1].Witness[2], sig) {
+		t.Error("Signatures were not placed in the witness")
+	}
+	if signed.VSize() > placeholderVSize {
+		t.Errorf("Signed vsize %d exceeds placeholder vsize %d", signed.VSize(), placeholderVSize)
+	}
+}
+
+func TestTemplateScriptPathFinalize(t *testing.T) {
+	in := newTemplateInputs()[6]
+	b := NewBuilder()
+	b.AddInput(in)
+	b.AddOutput(9000, make([]byte, 34))
+	tmpl, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	sig := bytes.Repeat([]byte{0xaa}, 64)
+	_ = tmpl.SetSignature(0, 0, sig)
+	_ = tmpl.SetSignature(0, 2, sig)
+	if _, err := tmpl.Finalize(); err == nil || !strings.Contains(err.Error(), "placeholder") {
+		t.Errorf("Expected placeholder error, got %v", err)
+	}
+
+	// Key 1 does not sign
+	_ = tmpl.SetSignature(0, 1, nil)
+	if _, err := tmpl.Finalize(); err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(tmpl.Tx.Inputs[0].Witness[1]) != 0 {
+		t.Error("Expected an empty witness item for the non-signing key")
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	if _, err := NewBuilder().Build(); err == nil {
+		t.Error("Expected error for a builder without inputs")
+	}
+
+	b := NewBuilder()
+	b.AddInput(TemplateInput{Template: txsize.InputTemplate{Type: txsize.P2WPKH}, PubKey: []byte{0x02}})
+	b.AddOutput(1000, make([]byte, 22))
+	if _, err := b.Build(); err == nil {
+		t.Error("Expected error for an invalid public key")
+	}
+
+	b = NewBuilder()
+	b.AddInput(newTemplateInputs()[1])
+	b.AddOutput(1000, make([]byte, 22))
+	tmpl, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if err := tmpl.SetSignature(0, 0, make([]byte, 74)); err == nil {
+		t.Error("Expected error for a signature larger than its placeholder")
+	}
+	if err := tmpl.SetSignature(0, 1, make([]byte, 72)); err == nil {
+		t.Error("Expected error for an invalid slot")
+	}
+	if err := tmpl.ApplyFeeRate(0, 1000); err == nil {
+		t.Error("Expected insufficient funds error")
+	}
+}
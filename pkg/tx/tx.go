@@ -0,0 +1,387 @@
+package tx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+)
+
+// Bitcoin transactions: inputs spend previous outputs, outputs lock amounts to scripts
+//
+// Serialization format (BIP144 for segwit):
+// [version][marker 0x00][flag 0x01][inputs][outputs][witnesses][locktime]
+// The marker, flag and witnesses are omitted when no input has witness data.
+
+const (
+	// WitnessScaleFactor is the weight multiplier for non-witness bytes (BIP141)
+	WitnessScaleFactor = 4
+
+	// maxItems bounds the number of inputs, outputs or witness items accepted when parsing
+	maxItems = 100000
+	// maxItemSize bounds the size of a single script or witness item accepted when parsing
+	maxItemSize = 4000000
+)
+
+// OutPoint identifies a previous transaction output
+type OutPoint struct {
+	Hash  [32]byte // Txid of the previous transaction (internal byte order)
+	Index uint32   // Output index within the previous transaction
+}
+
+// String returns the outpoint as "txid:index" with the txid in display order
+func (o OutPoint) String() string {
+	display := hash.Reverse32(o.Hash)
+	return fmt.Sprintf("%s:%d", hex.EncodeToString(display[:]), o.Index)
+}
+
+// TxIn is a transaction input
+type TxIn struct {
+	PreviousOutPoint OutPoint
+	SignatureScript  []byte   // scriptSig
+	Witness          [][]byte // Segwit witness stack (empty for legacy inputs)
+	Sequence         uint32
+}
+
+// TxOut is a transaction output
+type TxOut struct {
+	Value    int64  // Amount in satoshis
+	PkScript []byte // scriptPubKey
+}
+
+// Transaction is a Bitcoin transaction
+type Transaction struct {
+	Version  int32
+	Inputs   []*TxIn
+	Outputs  []*TxOut
+	LockTime uint32
+}
+
+// HasWitness reports whether any input carries witness data
+func (tx *Transaction) HasWitness() bool {
+	for _, in := range tx.Inputs {
+		if len(in.Witness) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Serialize encodes the transaction, including witness data when present
+//
+// Example:
+//
+//	raw := tx.Serialize()
+//	fmt.Printf("%x\n", raw)
+func (tx *Transaction) Serialize() []byte {
+	var buf bytes.Buffer
+	tx.serialize(&buf, tx.HasWitness())
+	return buf.Bytes()
+}
+
+// SerializeNoWitness encodes the transaction without witness data (used for the txid)
+func (tx *Transaction) SerializeNoWitness() []byte {
+	var buf bytes.Buffer
+	tx.serialize(&buf, false)
+	return buf.Bytes()
+}
+
+// serialize writes the transaction to buf, optionally in the BIP144 witness format
+func (tx *Transaction) serialize(buf *bytes.Buffer, witness bool) {
+	// Step 1: Version (4 bytes, little-endian)
+	writeUint32(buf, uint32(tx.Version))
+
+	// Step 2: Segwit marker and flag
+	if witness {
+		buf.Write([]byte{0x00, 0x01})
+	}
+
+	// Step 3: Inputs: [outpoint][scriptSig][sequence]
+	WriteVarInt(buf, uint64(len(tx.Inputs)))
+	for _, in := range tx.Inputs {
+		buf.Write(in.PreviousOutPoint.Hash[:])
+		writeUint32(buf, in.PreviousOutPoint.Index)
+		WriteVarBytes(buf, in.SignatureScript)
+		writeUint32(buf, in.Sequence)
+	}
+
+	// Step 4: Outputs: [value][scriptPubKey]
+	WriteVarInt(buf, uint64(len(tx.Outputs)))
+	for _, out := range tx.Outputs {
+		var value [8]byte
+		binary.LittleEndian.PutUint64(value[:], uint64(out.Value))
+		buf.Write(value[:])
+		WriteVarBytes(buf, out.PkScript)
+	}
+
+	// Step 5: Witness stacks, one per input
+	if witness {
+		for _, in := range tx.Inputs {
+			WriteVarInt(buf, uint64(len(in.Witness)))
+			for _, item := range in.Witness {
+				WriteVarBytes(buf, item)
+			}
+		}
+	}
+
+	// Step 6: Locktime (4 bytes, little-endian)
+	writeUint32(buf, tx.LockTime)
+}
+
+// TxID returns the transaction id: SHA256D of the non-witness serialization (internal byte order)
+//
+// Block explorers display txids in reverse byte order; use TxIDHex for that form.
+func (tx *Transaction) TxID() [32]byte {
+	return hash.SHA256D(tx.SerializeNoWitness())
+}
+
+// TxIDHex returns the txid in display (big-endian) hex, as shown by block explorers
+func (tx *Transaction) TxIDHex() string {
+	display := hash.Reverse32(tx.TxID())
+	return hex.EncodeToString(display[:])
+}
+
+// WTxID returns the witness transaction id: SHA256D of the full serialization
+func (tx *Transaction) WTxID() [32]byte {
+	return hash.SHA256D(tx.Serialize())
+}
+
+// Weight returns the BIP141 weight: non-witness size * 3 + total size
+func (tx *Transaction) Weight() int {
+	base := len(tx.SerializeNoWitness())
+	total := len(tx.Serialize())
+	return base*(WitnessScaleFactor-1) + total
+}
+
+// VSize returns the virtual size in vbytes (weight / 4, rounded up)
+func (tx *Transaction) VSize() int {
+	return (tx.Weight() + WitnessScaleFactor - 1) / WitnessScaleFactor
+}
+
+// Deserialize parses a raw transaction in legacy or BIP144 witness format
+//
+// Example:
+//
+//	raw, _ := hex.DecodeString("0100000001...")
+//	tx, err := Deserialize(raw)
+func Deserialize(raw []byte) (*Transaction, error) {
+	r := bytes.NewReader(raw)
+	tx := &Transaction{}
+
+	// Step 1: Version
+	version, err := readUint32(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading version: %w", err)
+	}
+	tx.Version = int32(version)
+
+	// Step 2: Input count, or the segwit marker (0x00) followed by the flag (0x01)
+	inputCount, err := ReadVarInt(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading input count: %w", err)
+	}
+	witness := false
+	if inputCount == 0 {
+		flag, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("reading segwit flag: %w", err)
+		}
+		if flag != 0x01 {
+			return nil, fmt.Errorf("invalid segwit flag: %#x", flag)
+		}
+		witness = true
+		if inputCount, err = ReadVarInt(r); err != nil {
+			return nil, fmt.Errorf("reading input count: %w", err)
+		}
+	}
+	if inputCount > maxItems {
+		return nil, fmt.Errorf("too many inputs: %d", inputCount)
+	}
+
+	// Step 3: Inputs
+	tx.Inputs = make([]*TxIn, inputCount)
+	for i := range tx.Inputs {
+		in := &TxIn{}
+		if _, err := io.ReadFull(r, in.PreviousOutPoint.Hash[:]); err != nil {
+			return nil, fmt.Errorf("input %d: reading outpoint: %w", i, err)
+		}
+		if in.PreviousOutPoint.Index, err = readUint32(r); err != nil {
+			return nil, fmt.Errorf("input %d: reading outpoint: %w", i, err)
+		}
+		if in.SignatureScript, err = ReadVarBytes(r); err != nil {
+			return nil, fmt.Errorf("input %d: reading scriptSig: %w", i, err)
+		}
+		if in.Sequence, err = readUint32(r); err != nil {
+			return nil, fmt.Errorf("input %d: reading sequence: %w", i, err)
+		}
+		tx.Inputs[i] = in
+	}
+
+	// Step 4: Outputs
+	outputCount, err := ReadVarInt(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading output count: %w", err)
+	}
+	if outputCount > maxItems {
+		return nil, fmt.Errorf("too many outputs: %d", outputCount)
+	}
+	tx.Outputs = make([]*TxOut, outputCount)
+	for i := range tx.Outputs {
+		var value [8]byte
+		if _, err := io.ReadFull(r, value[:]); err != nil {
+			return nil, fmt.Errorf("output %d: reading value: %w", i, err)
+		}
+		out := &TxOut{Value: int64(binary.LittleEndian.Uint64(value[:]))}
+		if out.PkScript, err = ReadVarBytes(r); err != nil {
+			return nil, fmt.Errorf("output %d: reading scriptPubKey: %w", i, err)
+		}
+		tx.Outputs[i] = out
+	}
+
+	// Step 5: Witness stacks
+	if witness {
+		for i, in := range tx.Inputs {
+			count, err := ReadVarInt(r)
+			if err != nil {
+				return nil, fmt.Errorf("input %d: reading witness count: %w", i, err)
+			}
+			if count > maxItems {
+				return nil, fmt.Errorf("input %d: too many witness items: %d", i, count)
+			}
+			in.Witness = make([][]byte, count)
+			for j := range in.Witness {
+				if in.Witness[j], err = ReadVarBytes(r); err != nil {
+					return nil, fmt.Errorf("input %d: reading witness item %d: %w", i, j, err)
+				}
+			}
+		}
+		if !tx.HasWitness() {
+			return nil, errors.New("segwit marker present but all witnesses are empty")
+		}
+	}
+
+	// Step 6: Locktime, and nothing may follow it
+	if tx.LockTime, err = readUint32(r); err != nil {
+		return nil, fmt.Errorf("reading locktime: %w", err)
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after transaction", r.Len())
+	}
+
+	return tx, nil
+}
+
+// DeserializeHex parses a raw transaction from a hex string
+func DeserializeHex(s string) (*Transaction, error) {
+	raw, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex: %w", err)
+	}
+	return Deserialize(raw)
+}
+
+// WriteVarInt writes Bitcoin's CompactSize encoding of n
+//
+// Format:
+// - n < 0xfd: 1 byte
+// - n <= 0xffff: 0xfd + 2 bytes
+// - n <= 0xffffffff: 0xfe + 4 bytes
+// - otherwise: 0xff + 8 bytes
+func WriteVarInt(buf *bytes.Buffer, n uint64) {
+	switch {
+	case n < 0xfd:
+		buf.WriteByte(byte(n))
+	case n <= 0xffff:
+		buf.WriteByte(0xfd)
+		var b [2]byte
+		binary.LittleEndian.PutUint16(b[:], uint16(n))
+		buf.Write(b[:])
+	case n <= 0xffffffff:
+		buf.WriteByte(0xfe)
+		writeUint32(buf, uint32(n))
+	default:
+		buf.WriteByte(0xff)
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], n)
+		buf.Write(b[:])
+	}
+}
+
+// ReadVarInt reads a CompactSize integer, rejecting non-canonical encodings
+func ReadVarInt(r io.Reader) (uint64, error) {
+	var prefix [1]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return 0, err
+	}
+
+	var n, minValue uint64
+	switch prefix[0] {
+	case 0xfd:
+		var b [2]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, err
+		}
+		n, minValue = uint64(binary.LittleEndian.Uint16(b[:])), 0xfd
+	case 0xfe:
+		var b [4]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, err
+		}
+		n, minValue = uint64(binary.LittleEndian.Uint32(b[:])), 0x10000
+	case 0xff:
+		var b [8]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, err
+		}
+		n, minValue = binary.LittleEndian.Uint64(b[:]), 0x100000000
+	default:
+		return uint64(prefix[0]), nil
+	}
+
+	if n < minValue {
+		return 0, fmt.Errorf("non-canonical varint: %d", n)
+	}
+	return n, nil
+}
+
+// WriteVarBytes writes a length-prefixed byte slice
+func WriteVarBytes(buf *bytes.Buffer, b []byte) {
+	WriteVarInt(buf, uint64(len(b)))
+	buf.Write(b)
+}
+
+// ReadVarBytes reads a length-prefixed byte slice
+func ReadVarBytes(r io.Reader) ([]byte, error) {
+	n, err := ReadVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxItemSize {
+		return nil, fmt.Errorf("item too large: %d bytes", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// writeUint32 writes a little-endian uint32
+func writeUint32(buf *bytes.Buffer, v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	buf.Write(b[:])
+}
+
+// readUint32 reads a little-endian uint32
+func readUint32(r io.Reader) (uint32, error) {
+	var b [4]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b[:]), nil
+}
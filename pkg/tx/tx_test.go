@@ -0,0 +1,148 @@
+package tx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// genesisCoinbaseHex is the coinbase transaction of the Bitcoin genesis block
+const genesisCoinbaseHex = "01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000"
+
+// newSegwitTx builds a one-input P2WPKH spend with a fake signature for testing
+func newSegwitTx() *Transaction {
+	sig := append(bytes.Repeat([]byte{0x30}, 1), bytes.Repeat([]byte{0x01}, 71)...)
+	pub := append([]byte{0x02}, bytes.Repeat([]byte{0xaa}, 32)...)
+	return &Transaction{
+		Version: 2,
+		Inputs: []*TxIn{{
+			PreviousOutPoint: OutPoint{Hash: [32]byte{0x01}, Index: 1},
+			Witness:          [][]byte{sig, pub},
+			Sequence:         0xfffffffd,
+		}},
+		Outputs: []*TxOut{
+			{Value: 50000, PkScript: append([]byte{0x00, 0x14}, bytes.Repeat([]byte{0xbb}, 20)...)},
+			{Value: 40000, PkScript: append([]byte{0x51, 0x20}, bytes.Repeat([]byte{0xcc}, 32)...)},
+		},
+		LockTime: 800000,
+	}
+}
+
+func TestDeserializeGenesisCoinbase(t *testing.T) {
+	tx, err := DeserializeHex(genesisCoinbaseHex)
+	if err != nil {
+		t.Fatalf("DeserializeHex() error = %v", err)
+	}
+
+	if tx.TxIDHex() != "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b" {
+		t.Errorf("TxIDHex() = %s, expected genesis txid", tx.TxIDHex())
+	}
+	if len(tx.Inputs) != 1 || len(tx.Outputs) != 1 {
+		t.Fatalf("Expected 1 input and 1 output, got %d and %d", len(tx.Inputs), len(tx.Outputs))
+	}
+	if tx.Outputs[0].Value != 5000000000 {
+		t.Errorf("Output value = %d, expected 5000000000", tx.Outputs[0].Value)
+	}
+	if !bytes.Contains(tx.Inputs[0].SignatureScript, []byte("Chancellor on brink of second bailout for banks")) {
+		t.Error("Coinbase scriptSig should contain the genesis headline")
+	}
+	if tx.HasWitness() {
+		t.Error("Genesis coinbase has no witness")
+	}
+
+	// Round trip
+	if hex.EncodeToString(tx.Serialize()) != genesisCoinbaseHex {
+		t.Error("Serialize() did not reproduce the original bytes")
+	}
+	if tx.Weight() != 4*len(tx.Serialize()) {
+		t.Errorf("Legacy weight = %d, expected 4 * size", tx.Weight())
+	}
+}
+
+func TestSegwitRoundTrip(t *testing.T) {
+	tx := newSegwitTx()
+	raw := tx.Serialize()
+
+	// Marker and flag follow the version
+	if raw[4] != 0x00 || raw[5] != 0x01 {
+		t.Fatalf("Expected segwit marker and flag, got %x", raw[4:6])
+	}
+
+	parsed, err := Deserialize(raw)
+	if err != nil {
+		t.Fatalf("Deserialize() error = %v", err)
+	}
+	if !bytes.Equal(parsed.Serialize(), raw) {
+		t.Error("Round trip changed the serialization")
+	}
+	if parsed.TxID() != tx.TxID() {
+		t.Error("Round trip changed the txid")
+	}
+	if tx.TxID() == tx.WTxID() {
+		t.Error("txid and wtxid should differ for a segwit transaction")
+	}
+	if parsed.Inputs[0].Sequence != 0xfffffffd || parsed.LockTime != 800000 {
+		t.Error("Sequence or locktime not preserved")
+	}
+
+	// Weight: witness bytes (marker, flag, stack) count once
+	base := len(tx.SerializeNoWitness())
+	witness := len(raw) - base
+	if tx.Weight() != 4*base+witness {
+		t.Errorf("Weight() = %d, expected %d", tx.Weight(), 4*base+witness)
+	}
+}
+
+func TestDeserializeErrors(t *testing.T) {
+	valid, _ := hex.DecodeString(genesisCoinbaseHex)
+	segwit := newSegwitTx().Serialize()
+
+	tests := []struct {
+		name string
+		raw  []byte
+	}{
+		{"empty", []byte{}},
+		{"truncated", valid[:len(valid)-1]},
+		{"trailing bytes", append(append([]byte{}, valid...), 0x00)},
+		{"invalid segwit flag", append(append([]byte{}, segwit[:5]...), append([]byte{0x02}, segwit[6:]...)...)},
+		{"non-canonical varint", []byte{0x01, 0x00, 0x00, 0x00, 0xfd, 0x01, 0x00}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Deserialize(tt.raw); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	if _, err := DeserializeHex("zz"); err == nil {
+		t.Error("Expected error for invalid hex")
+	}
+}
+
+func TestVarInt(t *testing.T) {
+	tests := []struct {
+		n        uint64
+		expected string
+	}{
+		{0, "00"},
+		{0xfc, "fc"},
+		{0xfd, "fdfd00"},
+		{0xffff, "fdffff"},
+		{0x10000, "fe00000100"},
+		{0x100000000, "ff0000000001000000"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		WriteVarInt(&buf, tt.n)
+		if hex.EncodeToString(buf.Bytes()) != tt.expected {
+			t.Errorf("WriteVarInt(%d) = %x, expected %s", tt.n, buf.Bytes(), tt.expected)
+		}
+		got, err := ReadVarInt(bytes.NewReader(buf.Bytes()))
+		if err != nil || got != tt.n {
+			t.Errorf("ReadVarInt() = %d, %v, expected %d", got, err, tt.n)
+		}
+	}
+}
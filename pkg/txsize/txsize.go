@@ -0,0 +1,447 @@
+package txsize
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Bitcoin transaction size estimation: predict scriptSig and witness sizes before signing
+//
+// Fees are paid per virtual byte, but the final size of a transaction is only known
+// once every signature has been produced. This package predicts the size of each
+// input from a script template, so fees can be agreed on before the signing ceremony.
+//
+// All estimates are upper bounds: ECDSA signatures are assumed to be the maximum
+// low-S DER encoding (71 bytes) plus the sighash byte. Standard signers always
+// produce low-S signatures (BIP146), so s never needs the extra padding byte.
+
+const (
+	// MaxECDSASigSize is the largest low-S DER-encoded ECDSA signature plus the sighash byte
+	MaxECDSASigSize = 72
+	// SchnorrSigSize is a BIP340 signature using SIGHASH_DEFAULT (no sighash byte)
+	SchnorrSigSize = 64
+	// SchnorrSigWithHashTypeSize is a BIP340 signature with an explicit sighash byte
+	SchnorrSigWithHashTypeSize = 65
+	// CompressedPubKeySize is the size of a compressed secp256k1 public key
+	CompressedPubKeySize = 33
+	// UncompressedPubKeySize is the size of an uncompressed secp256k1 public key
+	UncompressedPubKeySize = 65
+
+	// WitnessScaleFactor is the weight multiplier for non-witness bytes (BIP141)
+	WitnessScaleFactor = 4
+
+	// outpointSize is the previous txid (32 bytes) and output index (4 bytes)
+	outpointSize = 36
+	// sequenceSize is the nSequence field of an input
+	sequenceSize = 4
+	// txOverheadSize is the version (4 bytes) and locktime (4 bytes) fields
+	txOverheadSize = 8
+	// segwitMarkerWeight is the marker and flag bytes of a segwit transaction
+	segwitMarkerWeight = 2
+	// outputValueSize is the amount field of an output
+	outputValueSize = 8
+)
+
+// ScriptType identifies the kind of output being spent
+type ScriptType int
+
+const (
+	// P2PKH spends a pay-to-pubkey-hash output: scriptSig = <sig> <pubkey>
+	P2PKH ScriptType = iota
+	// P2SHMultisig spends a bare m-of-n CHECKMULTISIG wrapped in P2SH
+	P2SHMultisig
+	// P2WPKH spends a native segwit v0 pubkey-hash output
+	P2WPKH
+	// P2SHP2WPKH spends a P2WPKH program nested inside P2SH
+	P2SHP2WPKH
+	// P2WSHMultisig spends an m-of-n CHECKMULTISIG witness script
+	P2WSHMultisig
+	// P2TRKeyPath spends a taproot output through the key path
+	P2TRKeyPath
+	// P2TRScriptPath spends a taproot output through a tapscript leaf
+	P2TRScriptPath
+)
+
+// String returns the conventional name of the script type
+func (t ScriptType) String() string {
+	switch t {
+	case P2PKH:
+		return "p2pkh"
+	case P2SHMultisig:
+		return "p2sh-multisig"
+	case P2WPKH:
+		return "p2wpkh"
+	case P2SHP2WPKH:
+		return "p2sh-p2wpkh"
+	case P2WSHMultisig:
+		return "p2wsh-multisig"
+	case P2TRKeyPath:
+		return "p2tr-keypath"
+	case P2TRScriptPath:
+		return "p2tr-scriptpath"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(t))
+	}
+}
+
+// InputTemplate describes an input whose signatures do not exist yet
+//
+// Only the fields relevant to Type are used:
+// - Threshold/Total: P2SHMultisig and P2WSHMultisig (m-of-n)
+// - Uncompressed: P2PKH with a 65-byte public key
+// - SighashType: P2TRKeyPath/P2TRScriptPath with a non-default sighash (65-byte signatures)
+// - LeafScriptSize/MerkleDepth/Threshold/Total: P2TRScriptPath (k signatures out of n keys)
+//
+// Example:
+//
+//	tmpl := InputTemplate{Type: P2WSHMultisig, Threshold: 2, Total: 3}
+type InputTemplate struct {
+	Type           ScriptType
+	Threshold      int  // Number of signatures provided (m)
+	Total          int  // Number of public keys in the script (n)
+	Uncompressed   bool // Use a 65-byte public key (P2PKH only)
+	SighashType    bool // Append a sighash byte to Schnorr signatures
+	LeafScriptSize int  // Size of the tapscript leaf being executed
+	MerkleDepth    int  // Depth of the leaf in the taproot script tree
+}
+
+// InputEstimate is the predicted size of a single input
+type InputEstimate struct {
+	Index         int        // Position of the input in the transaction
+	Type          ScriptType // Script type being spent
+	ScriptSigSize int        // Size of the scriptSig in bytes (without its length prefix)
+	WitnessSize   int        // Size of the serialized witness stack (0 if none)
+	Weight        int        // Total weight units contributed by this input
+}
+
+// HasWitness reports whether the input carries witness data
+func (e InputEstimate) HasWitness() bool {
+	return e.WitnessSize > 0
+}
+
+// Estimate is the predicted size of a whole transaction with a per-input breakdown
+type Estimate struct {
+	Inputs      []InputEstimate
+	OutputsSize int // Serialized size of all outputs in bytes
+	Weight      int // Total transaction weight
+}
+
+// VSize returns the virtual size of the transaction (weight / 4, rounded up)
+func (e *Estimate) VSize() int {
+	return (e.Weight + WitnessScaleFactor - 1) / WitnessScaleFactor
+}
+
+// Fee returns the fee required for the estimated size at the given rate
+//
+// Example:
+//
+//	est, _ := EstimateTx(inputs, outputs)
+//	fee := est.Fee(10) // 10 sat/vB
+func (e *Estimate) Fee(satPerVByte int64) int64 {
+	return int64(e.VSize()) * satPerVByte
+}
+
+// VarIntSize returns the size of Bitcoin's CompactSize encoding of n
+func VarIntSize(n int) int {
+	switch {
+	case n < 0xfd:
+		return 1
+	case n <= 0xffff:
+		return 3
+	case int64(n) <= 0xffffffff:
+		return 5
+	default:
+		return 9
+	}
+}
+
+// PushDataSize returns the size of a script push of n bytes including its opcode
+//
+// Example:
+//
+//	PushDataSize(33)  // 34 (OP_DATA_33 + 33 bytes)
+//	PushDataSize(105) // 107 (OP_PUSHDATA1 + length + 105 bytes)
+func PushDataSize(n int) int {
+	switch {
+	case n <= 75:
+		return 1 + n
+	case n <= 0xff:
+		return 2 + n
+	case n <= 0xffff:
+		return 3 + n
+	default:
+		return 5 + n
+	}
+}
+
+// MultisigScriptSize returns the size of an m-of-n CHECKMULTISIG script with compressed keys
+//
+// Script format: OP_m <pubkey_1> ... <pubkey_n> OP_n OP_CHECKMULTISIG
+func MultisigScriptSize(total int) int {
+	return 1 + total*(1+CompressedPubKeySize) + 1 + 1
+}
+
+// witnessSize returns the serialized size of a witness stack with the given item sizes
+func witnessSize(items ...int) int {
+	size := VarIntSize(len(items))
+	for _, item := range items {
+		size += VarIntSize(item) + item
+	}
+	return size
+}
+
+// validateMultisig checks the threshold parameters of a multisig template
+func validateMultisig(tmpl InputTemplate) error {
+	if tmpl.Threshold <= 0 {
+		return errors.New("threshold must be positive")
+	}
+	if tmpl.Total <= 0 {
+		return errors.New("total must be positive")
+	}
+	if tmpl.Threshold > tmpl.Total {
+		return errors.New("threshold cannot exceed total")
+	}
+	return nil
+}
+
+// EstimateInput predicts the scriptSig and witness size of a single input
+//
+// Example:
+//
+//	est, err := EstimateInput(InputTemplate{Type: P2WPKH})
+//	// Result: est.ScriptSigSize = 0, est.WitnessSize = 108, est.Weight = 272
+func EstimateInput(tmpl InputTemplate) (InputEstimate, error) {
+	var scriptSig, witness int
+
+	// Step 1: Compute the scriptSig and witness sizes for the template
+	switch tmpl.Type {
+	case P2PKH:
+		// scriptSig: <sig> <pubkey>
+		pubKeySize := CompressedPubKeySize
+		if tmpl.Uncompressed {
+			pubKeySize = UncompressedPubKeySize
+		}
+		scriptSig = PushDataSize(MaxECDSASigSize) + PushDataSize(pubKeySize)
+
+	case P2SHMultisig:
+		// scriptSig: OP_0 <sig_1> ... <sig_m> <redeemScript>
+		if err := validateMultisig(tmpl); err != nil {
+			return InputEstimate{}, err
+		}
+		scriptSig = 1 + tmpl.Threshold*PushDataSize(MaxECDSASigSize) + PushDataSize(MultisigScriptSize(tmpl.Total))
+
+	case P2WPKH:
+		// witness: <sig> <pubkey>
+		witness = witnessSize(MaxECDSASigSize, CompressedPubKeySize)
+
+	case P2SHP2WPKH:
+		// scriptSig: <0 <20-byte-hash>>, witness: <sig> <pubkey>
+		scriptSig = PushDataSize(22)
+		witness = witnessSize(MaxECDSASigSize, CompressedPubKeySize)
+
+	case P2WSHMultisig:
+		// witness: <> <sig_1> ... <sig_m> <witnessScript>
+		if err := validateMultisig(tmpl); err != nil {
+			return InputEstimate{}, err
+		}
+		items := []int{0}
+		for i := 0; i < tmpl.Threshold; i++ {
+			items = append(items, MaxECDSASigSize)
+		}
+		items = append(items, MultisigScriptSize(tmpl.Total))
+		witness = witnessSize(items...)
+
+	case P2TRKeyPath:
+		// witness: <sig>
+		witness = witnessSize(schnorrSigSize(tmpl))
+
+	case P2TRScriptPath:
+		// witness: <sig or empty for each key (reverse order)> <leafScript> <controlBlock>
+		if err := validateMultisig(tmpl); err != nil {
+			return InputEstimate{}, err
+		}
+		if tmpl.LeafScriptSize <= 0 {
+			return InputEstimate{}, errors.New("leaf script size must be positive")
+		}
+		if tmpl.MerkleDepth < 0 || tmpl.MerkleDepth > 128 {
+			return InputEstimate{}, errors.New("merkle depth must be between 0 and 128")
+		}
+		items := make([]int, 0, tmpl.Total+2)
+		for i := 0; i < tmpl.Total; i++ {
+			if i < tmpl.Threshold {
+				items = append(items, schnorrSigSize(tmpl))
+			} else {
+				items = append(items, 0)
+			}
+		}
+		items = append(items, tmpl.LeafScriptSize, 33+32*tmpl.MerkleDepth)
+		witness = witnessSize(items...)
+
+	default:
+		return InputEstimate{}, fmt.Errorf("unsupported script type: %s", tmpl.Type)
+	}
+
+	// Step 2: Non-witness bytes count four times, witness bytes count once
+	// Non-witness part: [outpoint][scriptSig length][scriptSig][sequence]
+	base := outpointSize + VarIntSize(scriptSig) + scriptSig + sequenceSize
+
+	return InputEstimate{
+		Type:          tmpl.Type,
+		ScriptSigSize: scriptSig,
+		WitnessSize:   witness,
+		Weight:        base*WitnessScaleFactor + witness,
+	}, nil
+}
+
+// schnorrSigSize returns the signature size for a taproot template
+func schnorrSigSize(tmpl InputTemplate) int {
+	if tmpl.SighashType {
+		return SchnorrSigWithHashTypeSize
+	}
+	return SchnorrSigSize
+}
+
+// EstimateTx predicts the size of a transaction from input templates and output script sizes
+//
+// outputScriptSizes contains the scriptPubKey length of every output
+// (e.g. 22 for P2WPKH, 34 for P2WSH/P2TR, 25 for P2PKH).
+//
+// Example:
+//
+//	inputs := []InputTemplate{{Type: P2WSHMultisig, Threshold: 2, Total: 3}}
+//	est, err := EstimateTx(inputs, []int{22, 34})
+//	fee := est.Fee(5)
+func EstimateTx(inputs []InputTemplate, outputScriptSizes []int) (*Estimate, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("at least one input is required")
+	}
+
+	// Step 1: Estimate each input and check whether any carries witness data
+	est := &Estimate{Inputs: make([]InputEstimate, len(inputs))}
+	hasWitness := false
+	for i, tmpl := range inputs {
+		in, err := EstimateInput(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", i, err)
+		}
+		in.Index = i
+		est.Inputs[i] = in
+		hasWitness = hasWitness || in.HasWitness()
+	}
+
+	// Step 2: Outputs are [amount][script length][script]
+	for i, size := range outputScriptSizes {
+		if size < 0 {
+			return nil, fmt.Errorf("output %d: script size cannot be negative", i)
+		}
+		est.OutputsSize += outputValueSize + VarIntSize(size) + size
+	}
+
+	// Step 3: Transaction overhead: version, locktime and the input/output counts
+	base := txOverheadSize + VarIntSize(len(inputs)) + VarIntSize(len(outputScriptSizes)) + est.OutputsSize
+	est.Weight = base * WitnessScaleFactor
+
+	// Step 4: Add the inputs; in a segwit transaction every input has a witness
+	// field, so inputs without witness data still need a 1-byte empty stack
+	for _, in := range est.Inputs {
+		est.Weight += in.Weight
+		if hasWitness && !in.HasWitness() {
+			est.Weight++
+		}
+	}
+	if hasWitness {
+		est.Weight += segwitMarkerWeight
+	}
+
+	return est, nil
+}
+
+// TemplateFromDescriptor derives an input template from the shape of an output descriptor
+//
+// Only the structure of the descriptor is inspected; key expressions are not parsed
+// and descriptor checksums ("#...") are ignored. Supported forms:
+// pkh(KEY), wpkh(KEY), sh(wpkh(KEY)), tr(KEY), sh(multi(k,...)), wsh(multi(k,...))
+// and the sortedmulti variants.
+//
+// Example:
+//
+//	tmpl, err := TemplateFromDescriptor("wsh(sortedmulti(2,xpubA,xpubB,xpubC))")
+//	// Result: InputTemplate{Type: P2WSHMultisig, Threshold: 2, Total: 3}
+func TemplateFromDescriptor(desc string) (InputTemplate, error) {
+	// Step 1: Strip the optional checksum
+	if i := strings.IndexByte(desc, '#'); i >= 0 {
+		desc = desc[:i]
+	}
+	desc = strings.TrimSpace(desc)
+
+	// Step 2: Match the outer script wrapper
+	switch {
+	case isWrapped(desc, "pkh"):
+		return InputTemplate{Type: P2PKH}, nil
+	case isWrapped(desc, "wpkh"):
+		return InputTemplate{Type: P2WPKH}, nil
+	case isWrapped(desc, "tr"):
+		inner := unwrap(desc, "tr")
+		if strings.Contains(inner, ",") {
+			return InputTemplate{}, errors.New("tr() descriptors with script trees are not supported")
+		}
+		return InputTemplate{Type: P2TRKeyPath}, nil
+	case isWrapped(desc, "sh"):
+		inner := unwrap(desc, "sh")
+		if isWrapped(inner, "wpkh") {
+			return InputTemplate{Type: P2SHP2WPKH}, nil
+		}
+		threshold, total, err := parseMulti(inner)
+		if err != nil {
+			return InputTemplate{}, err
+		}
+		return InputTemplate{Type: P2SHMultisig, Threshold: threshold, Total: total}, nil
+	case isWrapped(desc, "wsh"):
+		threshold, total, err := parseMulti(unwrap(desc, "wsh"))
+		if err != nil {
+			return InputTemplate{}, err
+		}
+		return InputTemplate{Type: P2WSHMultisig, Threshold: threshold, Total: total}, nil
+	default:
+		return InputTemplate{}, fmt.Errorf("unsupported descriptor: %s", desc)
+	}
+}
+
+// isWrapped reports whether s has the form name(...)
+func isWrapped(s, name string) bool {
+	return strings.HasPrefix(s, name+"(") && strings.HasSuffix(s, ")")
+}
+
+// unwrap returns the contents of name(...)
+func unwrap(s, name string) string {
+	return s[len(name)+1 : len(s)-1]
+}
+
+// parseMulti extracts k and n from multi(k,KEY,...) or sortedmulti(k,KEY,...)
+func parseMulti(s string) (int, int, error) {
+	var inner string
+	switch {
+	case isWrapped(s, "multi"):
+		inner = unwrap(s, "multi")
+	case isWrapped(s, "sortedmulti"):
+		inner = unwrap(s, "sortedmulti")
+	default:
+		return 0, 0, fmt.Errorf("unsupported script expression: %s", s)
+	}
+
+	parts := strings.Split(inner, ",")
+	if len(parts) < 2 {
+		return 0, 0, errors.New("multi() requires a threshold and at least one key")
+	}
+	threshold, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid multi() threshold: %w", err)
+	}
+	total := len(parts) - 1
+	if threshold <= 0 || threshold > total {
+		return 0, 0, fmt.Errorf("invalid multi() threshold %d for %d keys", threshold, total)
+	}
+	return threshold, total, nil
+}
@@ -0,0 +1,255 @@
+package txsize
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+func TestEstimateInput(t *testing.T) {
+	tests := []struct {
+		name          string
+		tmpl          InputTemplate
+		wantScriptSig int
+		wantWitness   int
+		wantWeight    int
+	}{
+		{
+			name:          "p2pkh compressed",
+			tmpl:          InputTemplate{Type: P2PKH},
+			wantScriptSig: 107,
+			wantWitness:   0,
+			wantWeight:    (36 + 1 + 107 + 4) * 4,
+		},
+		{
+			name:          "p2wpkh",
+			tmpl:          InputTemplate{Type: P2WPKH},
+			wantScriptSig: 0,
+			wantWitness:   108,
+			wantWeight:    272,
+		},
+		{
+			name:          "p2sh-p2wpkh",
+			tmpl:          InputTemplate{Type: P2SHP2WPKH},
+			wantScriptSig: 23,
+			wantWitness:   108,
+			wantWeight:    (36+1+23+4)*4 + 108,
+		},
+		{
+			name:          "p2wsh 2-of-3",
+			tmpl:          InputTemplate{Type: P2WSHMultisig, Threshold: 2, Total: 3},
+			wantScriptSig: 0,
+			wantWitness:   1 + 1 + 2*73 + 1 + 105,
+			wantWeight:    41*4 + 254,
+		},
+		{
+			name:          "p2sh 2-of-3",
+			tmpl:          InputTemplate{Type: P2SHMultisig, Threshold: 2, Total: 3},
+			wantScriptSig: 1 + 2*73 + 2 + 105,
+			wantWitness:   0,
+			wantWeight:    (36 + 3 + 254 + 4) * 4,
+		},
+		{
+			name:          "p2tr key path",
+			tmpl:          InputTemplate{Type: P2TRKeyPath},
+			wantScriptSig: 0,
+			wantWitness:   66,
+			wantWeight:    230,
+		},
+		{
+			name:          "p2tr key path with sighash byte",
+			tmpl:          InputTemplate{Type: P2TRKeyPath, SighashType: true},
+			wantScriptSig: 0,
+			wantWitness:   67,
+			wantWeight:    231,
+		},
+		{
+			name:          "p2tr script path 2-of-3",
+			tmpl:          InputTemplate{Type: P2TRScriptPath, Threshold: 2, Total: 3, LeafScriptSize: 104, MerkleDepth: 1},
+			wantScriptSig: 0,
+			wantWitness:   1 + 2*65 + 1 + 105 + 66,
+			wantWeight:    164 + 303,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est, err := EstimateInput(tt.tmpl)
+			if err != nil {
+				t.Fatalf("EstimateInput() error = %v", err)
+			}
+			if est.ScriptSigSize != tt.wantScriptSig {
+				t.Errorf("ScriptSigSize = %d, expected %d", est.ScriptSigSize, tt.wantScriptSig)
+			}
+			if est.WitnessSize != tt.wantWitness {
+				t.Errorf("WitnessSize = %d, expected %d", est.WitnessSize, tt.wantWitness)
+			}
+			if est.Weight != tt.wantWeight {
+				t.Errorf("Weight = %d, expected %d", est.Weight, tt.wantWeight)
+			}
+		})
+	}
+}
+
+func TestEstimateInputErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl InputTemplate
+	}{
+		{"zero threshold", InputTemplate{Type: P2WSHMultisig, Threshold: 0, Total: 3}},
+		{"threshold exceeds total", InputTemplate{Type: P2SHMultisig, Threshold: 4, Total: 3}},
+		{"missing leaf script", InputTemplate{Type: P2TRScriptPath, Threshold: 1, Total: 1}},
+		{"merkle depth too large", InputTemplate{Type: P2TRScriptPath, Threshold: 1, Total: 1, LeafScriptSize: 34, MerkleDepth: 129}},
+		{"unknown type", InputTemplate{Type: ScriptType(99)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := EstimateInput(tt.tmpl); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestEstimateTx(t *testing.T) {
+	// Well-known sizes: 1-in/2-out P2WPKH is 141 vB, 1-in/1-out P2TR key path is 111 vB
+	est, err := EstimateTx([]InputTemplate{{Type: P2WPKH}}, []int{22, 22})
+	if err != nil {
+		t.Fatalf("EstimateTx() error = %v", err)
+	}
+	if est.VSize() != 141 {
+		t.Errorf("P2WPKH vsize = %d, expected 141", est.VSize())
+	}
+	if est.Fee(10) != 1410 {
+		t.Errorf("Fee = %d, expected 1410", est.Fee(10))
+	}
+
+	est, err = EstimateTx([]InputTemplate{{Type: P2TRKeyPath}}, []int{34})
+	if err != nil {
+		t.Fatalf("EstimateTx() error = %v", err)
+	}
+	if est.VSize() != 111 {
+		t.Errorf("P2TR vsize = %d, expected 111", est.VSize())
+	}
+
+	// A legacy-only transaction has no marker, flag or empty witnesses
+	est, err = EstimateTx([]InputTemplate{{Type: P2PKH}}, []int{25})
+	if err != nil {
+		t.Fatalf("EstimateTx() error = %v", err)
+	}
+	if est.Weight%4 != 0 {
+		t.Errorf("Legacy weight should be a multiple of 4, got %d", est.Weight)
+	}
+	if est.VSize() != 8+1+1+(36+1+107+4)+(8+1+25) {
+		t.Errorf("Legacy vsize = %d, unexpected", est.VSize())
+	}
+
+	// Mixed: the legacy input needs an empty witness in a segwit transaction
+	mixed, err := EstimateTx([]InputTemplate{{Type: P2PKH}, {Type: P2WPKH}}, []int{25})
+	if err != nil {
+		t.Fatalf("EstimateTx() error = %v", err)
+	}
+	want := (8+1+1+(8+1+25))*4 + (36+1+107+4)*4 + 1 + 272 + 2
+	if mixed.Weight != want {
+		t.Errorf("Mixed weight = %d, expected %d", mixed.Weight, want)
+	}
+	if len(mixed.Inputs) != 2 || mixed.Inputs[1].Index != 1 {
+		t.Error("Expected a per-input breakdown with indices")
+	}
+
+	if _, err := EstimateTx(nil, []int{22}); err == nil {
+		t.Error("Expected error for no inputs")
+	}
+	if _, err := EstimateTx([]InputTemplate{{Type: P2WPKH}}, []int{-1}); err == nil {
+		t.Error("Expected error for negative output size")
+	}
+}
+
+// TestPredictionsMatchRealWitnesses signs real inputs with random keys and checks the estimates are upper bounds
+func TestPredictionsMatchRealWitnesses(t *testing.T) {
+	// minECDSASigSize is the shortest valid DER signature plus the sighash byte (BIP66),
+	// so each signature can fall at most this far short of MaxECDSASigSize
+	const minECDSASigSize = 9
+	const slackPerSig = MaxECDSASigSize - minECDSASigSize
+
+	digest := sha256.Sum256([]byte("sighash placeholder"))
+	ecdsaSig := func(priv *btcec.PrivateKey) int {
+		return len(ecdsa.Sign(priv, digest[:]).Serialize()) + 1 // + sighash byte
+	}
+	newKey := func() *btcec.PrivateKey {
+		priv, err := btcec.NewPrivateKey()
+		if err != nil {
+			t.Fatalf("NewPrivateKey failed: %v", err)
+		}
+		return priv
+	}
+	checkBound := func(t *testing.T, actual, estimate, sigs int) {
+		t.Helper()
+		if actual > estimate || estimate-actual > sigs*slackPerSig {
+			t.Errorf("Actual witness %d, estimated %d", actual, estimate)
+		}
+	}
+
+	for i := 0; i < 32; i++ {
+		t.Run("p2wpkh", func(t *testing.T) {
+			key := newKey()
+			actual := witnessSize(ecdsaSig(key), len(key.PubKey().SerializeCompressed()))
+			est, _ := EstimateInput(InputTemplate{Type: P2WPKH})
+			checkBound(t, actual, est.WitnessSize, 1)
+		})
+
+		t.Run("p2wsh 2-of-3", func(t *testing.T) {
+			actual := witnessSize(0, ecdsaSig(newKey()), ecdsaSig(newKey()), MultisigScriptSize(3))
+			est, _ := EstimateInput(InputTemplate{Type: P2WSHMultisig, Threshold: 2, Total: 3})
+			checkBound(t, actual, est.WitnessSize, 2)
+		})
+
+		t.Run("p2tr key path", func(t *testing.T) {
+			sig, err := schnorr.SignBIP340([]byte("spend"), newKey())
+			if err != nil {
+				t.Fatalf("SignBIP340 failed: %v", err)
+			}
+			actual := witnessSize(len(sig))
+			est, _ := EstimateInput(InputTemplate{Type: P2TRKeyPath})
+			if actual != est.WitnessSize {
+				t.Errorf("Actual witness %d, estimated %d", actual, est.WitnessSize)
+			}
+		})
+	}
+}
+
+func TestTemplateFromDescriptor(t *testing.T) {
+	tests := []struct {
+		desc string
+		want InputTemplate
+	}{
+		{"pkh(02aa)", InputTemplate{Type: P2PKH}},
+		{"wpkh(xpub/0/*)#abcd1234", InputTemplate{Type: P2WPKH}},
+		{"sh(wpkh(02aa))", InputTemplate{Type: P2SHP2WPKH}},
+		{"tr(02aa)", InputTemplate{Type: P2TRKeyPath}},
+		{"sh(multi(1,02aa,02bb))", InputTemplate{Type: P2SHMultisig, Threshold: 1, Total: 2}},
+		{"wsh(sortedmulti(2,A,B,C))", InputTemplate{Type: P2WSHMultisig, Threshold: 2, Total: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := TemplateFromDescriptor(tt.desc)
+			if err != nil {
+				t.Fatalf("TemplateFromDescriptor() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("TemplateFromDescriptor() = %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+
+	for _, desc := range []string{"raw(00)", "wsh(multi(4,A,B,C))", "wsh(multi(x,A))", "tr(K,{pk(A),pk(B)})", "wsh(pk(A))"} {
+		if _, err := TemplateFromDescriptor(desc); err == nil {
+			t.Errorf("Expected error for %q", desc)
+		}
+	}
+}
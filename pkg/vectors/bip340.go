@@ -0,0 +1,257 @@
+package vectors
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// BIP340 test vectors
+//
+// The BIP340 repository publishes its test vectors as test-vectors.csv with
+// the columns
+//
+//	index,secret key,public key,aux_rand,message,signature,verification result,comment
+//
+// ReadBIP340CSV loads that file as published; ReadBIP340JSON loads the same
+// rows as a JSON array (keys in snake case, e.g. "secret_key"). RunBIP340
+// signs every vector with a secret key and verifies every vector, so a
+// downstream test suite can assert interop with a single call:
+//
+//	report := vectors.RunBIP340(vs)
+//	if !report.OK() { ... }
+//
+// SignDigest and VerifyDigest take 32-byte messages; vectors with messages of
+// other lengths (added to the BIP in 2022) are reported as skipped.
+
+// bip340Header is the header row of the published CSV
+var bip340Header = []string{"index", "secret key", "public key", "aux_rand", "message", "signature", "verification result", "comment"}
+
+// BIP340Vector is one row of the BIP340 test vectors; all byte fields are hex
+type BIP340Vector struct {
+	Index     int    `json:"index"`
+	SecretKey string `json:"secret_key,omitempty"` // Empty for verification-only vectors
+	PublicKey string `json:"public_key"`
+	AuxRand   string `json:"aux_rand,omitempty"`
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+	Valid     bool   `json:"verification_result"`
+	Comment   string `json:"comment,omitempty"`
+}
+
+// BIP340Result is the outcome of one vector
+type BIP340Result struct {
+	Vector  BIP340Vector
+	Signed  bool  // The vector had a secret key and its signature was reproduced
+	Skipped bool  // The vector was not run (see Err for why)
+	Err     error // Why the vector failed or was skipped; nil if it passed
+}
+
+// BIP340Report summarizes a run over BIP340 vectors
+type BIP340Report struct {
+	Results []BIP340Result
+	Passed  int
+	Skipped int
+	Failed  int
+}
+
+// OK reports whether no vector failed
+func (r *BIP340Report) OK() bool {
+	return r.Failed == 0
+}
+
+// Failures returns the results of the failed vectors
+func (r *BIP340Report) Failures() []BIP340Result {
+	var failures []BIP340Result
+	for _, res := range r.Results {
+		if res.Err != nil && !res.Skipped {
+			failures = append(failures, res)
+		}
+	}
+	return failures
+}
+
+// errUnsupportedMessage marks vectors whose message is not 32 bytes
+var errUnsupportedMessage = errors.New("only 32-byte messages are supported")
+
+// ReadBIP340CSV parses the BIP340 test-vectors.csv
+//
+// Example:
+//
+//	file, _ := os.Open("test-vectors.csv")
+//	vs, err := vectors.ReadBIP340CSV(file)
+func ReadBIP340CSV(r io.Reader) ([]BIP340Vector, error) {
+	// Step 1: Check the header
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(bip340Header)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid BIP340 vectors: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("invalid BIP340 vectors: empty file")
+	}
+	for i, name := range bip340Header {
+		if strings.TrimSpace(records[0][i]) != name {
+			return nil, fmt.Errorf("invalid BIP340 vectors: column %d is %q, expected %q", i, records[0][i], name)
+		}
+	}
+
+	// Step 2: Parse the rows
+	vs := make([]BIP340Vector, 0, len(records)-1)
+	for line, record := range records[1:] {
+		index, err := strconv.Atoi(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid BIP340 vectors: line %d: bad index %q", line+2, record[0])
+		}
+		var valid bool
+		switch strings.ToUpper(record[6]) {
+		case "TRUE":
+			valid = true
+		case "FALSE":
+		default:
+			return nil, fmt.Errorf("invalid BIP340 vectors: line %d: bad verification result %q", line+2, record[6])
+		}
+		vs = append(vs, BIP340Vector{
+			Index:     index,
+			SecretKey: record[1],
+			PublicKey: record[2],
+			AuxRand:   record[3],
+			Message:   record[4],
+			Signature: record[5],
+			Valid:     valid,
+			Comment:   record[7],
+		})
+	}
+	return vs, nil
+}
+
+// ReadBIP340JSON parses BIP340 vectors from a JSON array of BIP340Vector
+func ReadBIP340JSON(r io.Reader) ([]BIP340Vector, error) {
+	var vs []BIP340Vector
+	if err := json.NewDecoder(r).Decode(&vs); err != nil {
+		return nil, fmt.Errorf("invalid BIP340 vectors: %w", err)
+	}
+	return vs, nil
+}
+
+// RunBIP340 signs and verifies every vector with the schnorr package
+//
+// A vector passes if signing with its secret key and aux_rand (when it has a
+// secret key) reproduces its public key and signature, and verification
+// agrees with its verification result.
+//
+// Example:
+//
+//	report := vectors.RunBIP340(vs)
+//	for _, f := range report.Failures() {
+//		t.Errorf("BIP340 vector %d (%s): %v", f.Vector.Index, f.Vector.Comment, f.Err)
+//	}
+func RunBIP340(vs []BIP340Vector) *BIP340Report {
+	report := &BIP340Report{Results: make([]BIP340Result, 0, len(vs))}
+	for _, v := range vs {
+		res := runBIP340Vector(v)
+		switch {
+		case res.Skipped:
+			report.Skipped++
+		case res.Err != nil:
+			report.Failed++
+		default:
+			report.Passed++
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+// runBIP340Vector checks a single vector
+func runBIP340Vector(v BIP340Vector) BIP340Result {
+	res := BIP340Result{Vector: v}
+
+	// Step 1: Decode the fields every vector has
+	msg, err := hex.DecodeString(v.Message)
+	if err != nil {
+		res.Err = fmt.Errorf("bad message: %w", err)
+		return res
+	}
+	if len(msg) != 32 {
+		res.Skipped = true
+		res.Err = fmt.Errorf("%w: message is %d bytes", errUnsupportedMessage, len(msg))
+		return res
+	}
+	sig, err := decodeFixed(v.Signature, 64)
+	if err != nil {
+		res.Err = fmt.Errorf("bad signature: %w", err)
+		return res
+	}
+	pubBytes, err := hex.DecodeString(v.PublicKey)
+	if err != nil {
+		res.Err = fmt.Errorf("bad public key: %w", err)
+		return res
+	}
+	digest := [32]byte(msg)
+
+	// Step 2: Reproduce the signature from the secret key
+	if v.SecretKey != "" {
+		if err := checkBIP340Signing(v, digest, pubBytes, [64]byte(sig)); err != nil {
+			res.Err = err
+			return res
+		}
+		res.Signed = true
+	}
+
+	// Step 3: Verify; keys that do not parse never verify
+	verified := false
+	if pub, err := schnorr.ParseXOnlyPublicKey(pubBytes); err == nil {
+		verified = pub.Verify(digest, [64]byte(sig))
+	}
+	if verified != v.Valid {
+		res.Err = fmt.Errorf("verification returned %t, expected %t", verified, v.Valid)
+	}
+	return res
+}
+
+// checkBIP340Signing signs digest with the vector's secret key and aux_rand
+func checkBIP340Signing(v BIP340Vector, digest [32]byte, pubBytes []byte, want [64]byte) error {
+	secret, err := decodeFixed(v.SecretKey, 32)
+	if err != nil {
+		return fmt.Errorf("bad secret key: %w", err)
+	}
+	aux, err := decodeFixed(v.AuxRand, 32)
+	if err != nil {
+		return fmt.Errorf("bad aux_rand: %w", err)
+	}
+	priv, _ := btcec.PrivKeyFromBytes(secret)
+	if pub := schnorr.XOnlyFromPub(priv.PubKey()); !bytes.Equal(pub[:], pubBytes) {
+		return fmt.Errorf("secret key gives public key %s", pub)
+	}
+	got, err := schnorr.SignDigest(digest, priv, schnorr.WithAuxRand([32]byte(aux)))
+	if err != nil {
+		return fmt.Errorf("signing failed: %w", err)
+	}
+	if got != want {
+		return fmt.Errorf("signature %X, expected %X", got, want)
+	}
+	return nil
+}
+
+// decodeFixed decodes a hex field of exactly n bytes
+func decodeFixed(s string, n int) ([]byte, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != n {
+		return nil, fmt.Errorf("expected %d bytes, got %d", n, len(b))
+	}
+	return b, nil
+}
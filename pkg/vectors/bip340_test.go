@@ -0,0 +1,116 @@
+package vectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/fixtures"
+)
+
+// loadBIP340 reads the published BIP340 test vectors
+func loadBIP340(t *testing.T) []BIP340Vector {
+	t.Helper()
+	file, err := os.Open(fixtures.Path("bip340.csv"))
+	if err != nil {
+		t.Fatalf("Failed to open vectors: %v", err)
+	}
+	defer file.Close()
+	vs, err := ReadBIP340CSV(file)
+	if err != nil {
+		t.Fatalf("ReadBIP340CSV failed: %v", err)
+	}
+	return vs
+}
+
+func TestRunBIP340(t *testing.T) {
+	vs := loadBIP340(t)
+	report := RunBIP340(vs)
+	for _, f := range report.Failures() {
+		t.Errorf("Vector %d (%s): %v", f.Vector.Index, f.Vector.Comment, f.Err)
+	}
+	if report.Passed != len(vs) {
+		t.Errorf("Passed = %d, expected %d", report.Passed, len(vs))
+	}
+
+	// Vectors 0-3 have secret keys and are signed
+	signed := 0
+	for _, res := range report.Results {
+		if res.Signed {
+			signed++
+		}
+	}
+	if signed != 4 {
+		t.Errorf("Expected 4 signed vectors, got %d", signed)
+	}
+
+	// The JSON form gives the same results
+	data, _ := json.Marshal(vs)
+	fromJSON, err := ReadBIP340JSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadBIP340JSON failed: %v", err)
+	}
+	if again := RunBIP340(fromJSON); again.Passed != report.Passed {
+		t.Errorf("Expected the JSON vectors to pass like the CSV ones, got %d", again.Passed)
+	}
+}
+
+// TestRunBIP340Failures tests that disagreeing vectors are reported
+func TestRunBIP340Failures(t *testing.T) {
+	good := loadBIP340(t)[1]
+
+	tests := []struct {
+		name    string
+		tamper  func(v *BIP340Vector)
+		skipped bool
+	}{
+		{"wrong expectation", func(v *BIP340Vector) { v.Valid = false }, false},
+		{"wrong signature", func(v *BIP340Vector) { v.Signature = strings.Repeat("00", 64) }, false},
+		{"wrong aux_rand", func(v *BIP340Vector) { v.AuxRand = strings.Repeat("00", 32) }, false},
+		{"wrong public key", func(v *BIP340Vector) { v.SecretKey = strings.Repeat("00", 31) + "03" }, false},
+		{"bad hex", func(v *BIP340Vector) { v.Message = "zz" }, false},
+		{"short message", func(v *BIP340Vector) { v.Message = "00" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := good
+			tt.tamper(&v)
+			report := RunBIP340([]BIP340Vector{v})
+			if tt.skipped {
+				if report.Skipped != 1 || !report.OK() {
+					t.Errorf("Expected a skipped vector, got %+v", report)
+				}
+				return
+			}
+			if report.OK() || len(report.Failures()) != 1 {
+				t.Errorf("Expected a failure, got %+v", report)
+			}
+		})
+	}
+}
+
+func TestReadBIP340CSV(t *testing.T) {
+	header := "index,secret key,public key,aux_rand,message,signature,verification result,comment\n"
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "header only", input: header},
+		{name: "empty", input: "", wantErr: true},
+		{name: "wrong header", input: strings.Replace(header, "aux_rand", "aux", 1), wantErr: true},
+		{name: "missing column", input: header + "0,,,,,,TRUE\n", wantErr: true},
+		{name: "bad index", input: header + "x,,,,,,TRUE,\n", wantErr: true},
+		{name: "bad result", input: header + "0,,,,,,MAYBE,\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadBIP340CSV(strings.NewReader(tt.input)); (err != nil) != tt.wantErr {
+				t.Errorf("ReadBIP340CSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
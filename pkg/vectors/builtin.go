@@ -0,0 +1,318 @@
+package vectors
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neverDefined/cryptography-playground/pkg/base58"
+	"github.com/neverDefined/cryptography-playground/pkg/bech32"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/tx"
+	"github.com/neverDefined/cryptography-playground/pkg/wif"
+)
+
+func init() {
+	for kind, checker := range map[string]Checker{
+		KindBase58Check: checkBase58Check,
+		KindWIF:         checkWIF,
+		KindSegWit:      checkSegWit,
+		KindBase58:      checkBase58,
+		KindAddress:     checkAddress,
+	} {
+		if err := Register(kind, checker); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// errAccepted is returned when an invalid vector was not rejected
+var errAccepted = errors.New("invalid vector was accepted")
+
+// Export produces the vector suite for this module's encoders
+//
+// Valid vectors are generated by the encoders from fixed inputs; invalid ones
+// are mutations of them that every conforming decoder must reject.
+//
+// Example:
+//
+//	vectors.Export().WriteJSON(os.Stdout)
+func Export() *Suite {
+	suite := &Suite{Name: "cryptography-playground interop vectors", Version: SuiteVersion}
+	suite.Vectors = append(suite.Vectors, base58CheckVectors()...)
+	suite.Vectors = append(suite.Vectors, wifVectors()...)
+	suite.Vectors = append(suite.Vectors, segwitVectors()...)
+	return suite
+}
+
+func base58CheckVectors() []Vector {
+	hash160 := mustHex("751e76e8199196d454941c45d1b3a323f1433bd6")
+	var out []Vector
+	for _, c := range []struct {
+		version byte
+		payload []byte
+	}{
+		{0x00, hash160},
+		{0x05, hash160},
+		{0x6f, hash160},
+		{0x00, nil},
+		{0x80, bytes.Repeat([]byte{0xff}, 32)},
+	} {
+		out = append(out, Vector{
+			Kind:    KindBase58Check,
+			Encoded: base58.Base58CheckEncode(c.version, c.payload),
+			Valid:   true,
+			Payload: hex.EncodeToString(c.payload),
+			Version: intPtr(int(c.version)),
+		})
+	}
+
+	valid := out[0].Encoded
+	out = append(out,
+		Vector{Kind: KindBase58Check, Encoded: flipLast(valid, base58Alphabet), Reason: "checksum mismatch"},
+		Vector{Kind: KindBase58Check, Encoded: valid[:len(valid)-1] + "0", Reason: "character outside the base58 alphabet"},
+		Vector{Kind: KindBase58Check, Encoded: base58.Encode([]byte{0x00, 0x01, 0x02}), Reason: "shorter than version and checksum"},
+		Vector{Kind: KindBase58Check, Encoded: "", Reason: "empty string"},
+	)
+	return out
+}
+
+func wifVectors() []Vector {
+	key := mustHex("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	var out []Vector
+	for _, params := range []*chaincfg.Params{&chaincfg.MainNetParams, &chaincfg.TestNet3Params} {
+		for _, compressed := range []bool{false, true} {
+			encoded, err := wif.EncodeWithParams(key, compressed, params)
+			if err != nil {
+				panic(err)
+			}
+			out = append(out, Vector{
+				Kind:       KindWIF,
+				Encoded:    encoded,
+				Valid:      true,
+				Payload:    hex.EncodeToString(key),
+				Compressed: boolPtr(compressed),
+				Network:    params.Name,
+			})
+		}
+	}
+
+	badFlag := base58.Base58CheckEncode(chaincfg.MainNetParams.PrivateKeyID, append(append([]byte(nil), key...), 0x02))
+	shortKey := base58.Base58CheckEncode(chaincfg.MainNetParams.PrivateKeyID, key[:31])
+	out = append(out,
+		Vector{Kind: KindWIF, Encoded: out[0].Encoded, Network: chaincfg.TestNet3Params.Name, Reason: "mainnet key decoded as testnet"},
+		Vector{Kind: KindWIF, Encoded: badFlag, Network: chaincfg.MainNetParams.Name, Reason: "compression flag is not 0x01"},
+		Vector{Kind: KindWIF, Encoded: shortKey, Network: chaincfg.MainNetParams.Name, Reason: "private key shorter than 32 bytes"},
+		Vector{Kind: KindWIF, Encoded: flipLast(out[1].Encoded, base58Alphabet), Network: chaincfg.MainNetParams.Name, Reason: "checksum mismatch"},
+	)
+	return out
+}
+
+func segwitVectors() []Vector {
+	program20 := mustHex("751e76e8199196d454941c45d1b3a323f1433bd6")
+	program32 := mustHex("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	var out []Vector
+	for _, c := range []struct {
+		params  *chaincfg.Params
+		version byte
+		program []byte
+	}{
+		{&chaincfg.MainNetParams, 0, program20},
+		{&chaincfg.MainNetParams, 0, program32},
+		{&chaincfg.MainNetParams, 1, program32},
+		{&chaincfg.TestNet3Params, 0, program20},
+		{&chaincfg.TestNet3Params, 1, program32},
+	} {
+		encoded, err := bech32.SegWitAddressEncode(c.params.Bech32HRPSegwit, c.version, c.program)
+		if err != nil {
+			panic(err)
+		}
+		out = append(out, Vector{
+			Kind:    KindSegWit,
+			Encoded: encoded,
+			Valid:   true,
+			Payload: hex.EncodeToString(c.program),
+			Version: intPtr(int(c.version)),
+			Network: c.params.Name,
+		})
+	}
+
+	// A v1 program encoded with the v0 checksum, and a v0 program with the wrong length
+	v1Bech32, _ := bech32.Encode("bc", append([]byte{1}, mustConvert(program32)...), bech32.Bech32)
+	v0Short, _ := bech32.Encode("bc", append([]byte{0}, mustConvert(program20[:16])...), bech32.Bech32)
+	out = append(out,
+		Vector{Kind: KindSegWit, Encoded: out[0].Encoded, Network: chaincfg.TestNet3Params.Name, Reason: "human-readable part of another network"},
+		Vector{Kind: KindSegWit, Encoded: flipLast(out[0].Encoded, bech32Charset), Network: chaincfg.MainNetParams.Name, Reason: "checksum mismatch"},
+		Vector{Kind: KindSegWit, Encoded: v1Bech32, Network: chaincfg.MainNetParams.Name, Reason: "witness v1 with bech32 instead of bech32m checksum"},
+		Vector{Kind: KindSegWit, Encoded: v0Short, Network: chaincfg.MainNetParams.Name, Reason: "witness v0 program must be 20 or 32 bytes"},
+		Vector{Kind: KindSegWit, Encoded: "bc1qW508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", Network: chaincfg.MainNetParams.Name, Reason: "mixed case"},
+	)
+	return out
+}
+
+func checkBase58Check(v Vector) error {
+	payload, version, err := base58.Base58CheckDecode(v.Encoded)
+	if !v.Valid {
+		return rejected(err)
+	}
+	if err != nil {
+		return err
+	}
+	if v.Version == nil || int(version) != *v.Version {
+		return fmt.Errorf("version 0x%02x does not match vector", version)
+	}
+	if err := samePayload(payload, v.Payload); err != nil {
+		return err
+	}
+	return sameEncoding(base58.Base58CheckEncode(version, payload), v.Encoded)
+}
+
+func checkWIF(v Vector) error {
+	params, err := chaincfg.ParamsForName(v.Network)
+	if err != nil {
+		return err
+	}
+	key, compressed, err := wif.DecodeWithParams(v.Encoded, params)
+	if !v.Valid {
+		return rejected(err)
+	}
+	if err != nil {
+		return err
+	}
+	if v.Compressed == nil || compressed != *v.Compressed {
+		return fmt.Errorf("compressed = %v does not match vector", compressed)
+	}
+	if err := samePayload(key[:], v.Payload); err != nil {
+		return err
+	}
+	encoded, err := wif.EncodeWithParams(key[:], compressed, params)
+	if err != nil {
+		return err
+	}
+	return sameEncoding(encoded, v.Encoded)
+}
+
+func checkSegWit(v Vector) error {
+	params, err := chaincfg.ParamsForName(v.Network)
+	if err != nil {
+		return err
+	}
+	version, program, err := bech32.SegWitAddressDecode(params.Bech32HRPSegwit, v.Encoded)
+	if !v.Valid {
+		return rejected(err)
+	}
+	if err != nil {
+		return err
+	}
+	if v.Version == nil || int(version) != *v.Version {
+		return fmt.Errorf("witness version %d does not match vector", version)
+	}
+	if err := samePayload(program, v.Payload); err != nil {
+		return err
+	}
+	encoded, err := bech32.SegWitAddressEncode(params.Bech32HRPSegwit, version, program)
+	if err != nil {
+		return err
+	}
+	// Bech32 strings may be all uppercase (BIP173); the encoder always writes lowercase
+	return sameEncoding(encoded, strings.ToLower(v.Encoded))
+}
+
+func checkBase58(v Vector) error {
+	payload, err := base58.Decode(v.Encoded)
+	if !v.Valid {
+		return rejected(err)
+	}
+	if err != nil {
+		return err
+	}
+	if err := samePayload(payload, v.Payload); err != nil {
+		return err
+	}
+	return sameEncoding(base58.Encode(payload), v.Encoded)
+}
+
+func checkAddress(v Vector) error {
+	params, err := chaincfg.ParamsForName(v.Network)
+	if err != nil {
+		return err
+	}
+	script, err := tx.AddressScript(v.Encoded, params)
+	if !v.Valid {
+		return rejected(err)
+	}
+	if err != nil {
+		return err
+	}
+	if err := samePayload(script, v.Payload); err != nil {
+		return err
+	}
+	encoded, err := tx.ExtractAddress(script, params)
+	if err != nil {
+		return err
+	}
+	expected := v.Encoded
+	if params.SupportsSegwit() && strings.HasPrefix(strings.ToLower(expected), params.Bech32HRPSegwit+"1") {
+		expected = strings.ToLower(expected)
+	}
+	return sameEncoding(encoded, expected)
+}
+
+const (
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	bech32Charset  = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+// rejected turns the decode error of an invalid vector into a check result
+func rejected(err error) error {
+	if err == nil {
+		return errAccepted
+	}
+	return nil
+}
+
+func samePayload(got []byte, expectedHex string) error {
+	if hex.EncodeToString(got) != expectedHex {
+		return fmt.Errorf("payload %x does not match vector %s", got, expectedHex)
+	}
+	return nil
+}
+
+func sameEncoding(got, expected string) error {
+	if got != expected {
+		return fmt.Errorf("re-encoding gives %s, expected %s", got, expected)
+	}
+	return nil
+}
+
+// flipLast replaces the last character of s with the next one in alphabet
+func flipLast(s, alphabet string) string {
+	last := s[len(s)-1]
+	for i := 0; i < len(alphabet); i++ {
+		if alphabet[i] == last {
+			return s[:len(s)-1] + string(alphabet[(i+1)%len(alphabet)])
+		}
+	}
+	panic("character not in alphabet")
+}
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func mustConvert(program []byte) []byte {
+	conv, err := bech32.ConvertBits(program, 8, 5, true)
+	if err != nil {
+		panic(err)
+	}
+	return conv
+}
+
+func intPtr(i int) *int    { return &i }
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,62 @@
+package vectors
+
+import (
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/fixtures"
+)
+
+// TestExport tests that the exported suite passes its own checks
+func TestExport(t *testing.T) {
+	suite := Export()
+	report := Run(suite)
+	for _, f := range report.Failures {
+		t.Errorf("Vector #%d (%s %q): %v", f.Index, f.Vector.Kind, f.Vector.Encoded, f.Err)
+	}
+	if report.Skipped != 0 {
+		t.Errorf("Expected no skipped vectors, got %d", report.Skipped)
+	}
+
+	// Every kind has valid and invalid cases, and invalid cases say why
+	valid := map[string]int{}
+	invalid := map[string]int{}
+	for _, v := range suite.Vectors {
+		if v.Valid {
+			valid[v.Kind]++
+			continue
+		}
+		invalid[v.Kind]++
+		if v.Reason == "" {
+			t.Errorf("Invalid vector %q has no reason", v.Encoded)
+		}
+	}
+	for _, kind := range []string{KindBase58Check, KindWIF, KindSegWit} {
+		if valid[kind] == 0 || invalid[kind] == 0 {
+			t.Errorf("Kind %s has %d valid and %d invalid vectors", kind, valid[kind], invalid[kind])
+		}
+	}
+}
+
+// TestExportGolden snapshots the exported suite so encoder changes show up as a diff
+func TestExportGolden(t *testing.T) {
+	fixtures.GoldenJSON(t, "interop.golden.json", Export())
+}
+
+// TestCheckersDetectTampering tests that flipping a vector's expectations fails it
+func TestCheckersDetectTampering(t *testing.T) {
+	for i, v := range Export().Vectors {
+		tampered := v
+		tampered.Valid = !v.Valid
+		if tampered.Valid {
+			// An invalid string claimed as valid must not decode to matching fields
+			tampered.Payload = "00"
+			version := 0
+			compressed := false
+			tampered.Version, tampered.Compressed = &version, &compressed
+		}
+		report := Run(&Suite{Version: SuiteVersion, Vectors: []Vector{tampered}})
+		if report.OK() {
+			t.Errorf("Vector #%d (%s %q) passed after flipping Valid", i, v.Kind, v.Encoded)
+		}
+	}
+}
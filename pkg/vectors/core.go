@@ -0,0 +1,144 @@
+package vectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+)
+
+// Bitcoin Core test vectors
+//
+// Bitcoin Core publishes the vectors its own encoders are tested against in
+// src/test/data:
+//
+//	base58_encode_decode.json  [hex, base58] pairs
+//	key_io_valid.json          [encoded, hex, {"chain", "isPrivkey", "isCompressed", "tryCaseFlip"}]
+//	key_io_invalid.json        [encoded] strings that are neither a key nor an address on any chain
+//
+// The readers below convert them into Suites of base58, wif and address
+// vectors, so that Run checks the base58, wif and tx (address) packages
+// against the reference implementation:
+//
+//	suite, err := vectors.ReadCoreKeyIOValid(file)
+//	report := vectors.Run(suite)
+
+// coreChains maps Bitcoin Core chain names to network names
+var coreChains = map[string]string{
+	"main":     chaincfg.MainNetParams.Name,
+	"test":     chaincfg.TestNet3Params.Name,
+	"testnet4": chaincfg.TestNet4Params.Name,
+	"signet":   chaincfg.SigNetParams.Name,
+	"regtest":  chaincfg.RegressionNetParams.Name,
+}
+
+// coreKeyIOMeta is the metadata object of a key_io_valid.json entry
+type coreKeyIOMeta struct {
+	Chain        string `json:"chain"`
+	IsPrivkey    bool   `json:"isPrivkey"`
+	IsCompressed bool   `json:"isCompressed"`
+	TryCaseFlip  bool   `json:"tryCaseFlip"`
+}
+
+// ReadCoreBase58 converts Bitcoin Core's base58_encode_decode.json into a Suite
+//
+// Example:
+//
+//	file, _ := os.Open("base58_encode_decode.json")
+//	suite, err := vectors.ReadCoreBase58(file)
+func ReadCoreBase58(r io.Reader) (*Suite, error) {
+	var entries [][]string
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("invalid base58 vectors: %w", err)
+	}
+	suite := &Suite{Name: "bitcoin core base58_encode_decode", Version: SuiteVersion}
+	for i, entry := range entries {
+		if len(entry) != 2 {
+			return nil, fmt.Errorf("invalid base58 vectors: entry %d has %d fields, expected 2", i, len(entry))
+		}
+		suite.Vectors = append(suite.Vectors, Vector{
+			Kind:    KindBase58,
+			Encoded: entry[1],
+			Valid:   true,
+			Payload: strings.ToLower(entry[0]),
+		})
+	}
+	return suite, nil
+}
+
+// ReadCoreKeyIOValid converts Bitcoin Core's key_io_valid.json into a Suite
+//
+// Private keys become wif vectors and addresses become address vectors;
+// entries flagged tryCaseFlip are also added in upper case.
+func ReadCoreKeyIOValid(r io.Reader) (*Suite, error) {
+	// Step 1: Decode the heterogeneous arrays
+	var entries [][]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("invalid key_io vectors: %w", err)
+	}
+
+	// Step 2: Convert every entry
+	suite := &Suite{Name: "bitcoin core key_io_valid", Version: SuiteVersion}
+	for i, entry := range entries {
+		if len(entry) != 3 {
+			return nil, fmt.Errorf("invalid key_io vectors: entry %d has %d fields, expected 3", i, len(entry))
+		}
+		var encoded, payload string
+		var meta coreKeyIOMeta
+		if err := json.Unmarshal(entry[0], &encoded); err != nil {
+			return nil, fmt.Errorf("invalid key_io vectors: entry %d: %w", i, err)
+		}
+		if err := json.Unmarshal(entry[1], &payload); err != nil {
+			return nil, fmt.Errorf("invalid key_io vectors: entry %d: %w", i, err)
+		}
+		if err := json.Unmarshal(entry[2], &meta); err != nil {
+			return nil, fmt.Errorf("invalid key_io vectors: entry %d: %w", i, err)
+		}
+		network, ok := coreChains[meta.Chain]
+		if !ok {
+			return nil, fmt.Errorf("invalid key_io vectors: entry %d: unknown chain %q", i, meta.Chain)
+		}
+
+		v := Vector{Kind: KindAddress, Encoded: encoded, Valid: true, Payload: strings.ToLower(payload), Network: network}
+		if meta.IsPrivkey {
+			v.Kind = KindWIF
+			v.Compressed = boolPtr(meta.IsCompressed)
+		}
+		suite.Vectors = append(suite.Vectors, v)
+		if meta.TryCaseFlip {
+			v.Encoded = strings.ToUpper(encoded)
+			suite.Vectors = append(suite.Vectors, v)
+		}
+	}
+	return suite, nil
+}
+
+// ReadCoreKeyIOInvalid converts Bitcoin Core's key_io_invalid.json into a Suite
+//
+// Every string must be rejected as a private key and as an address on every
+// chain, so each one gives a wif and an address vector per network.
+func ReadCoreKeyIOInvalid(r io.Reader) (*Suite, error) {
+	var entries [][]string
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("invalid key_io vectors: %w", err)
+	}
+	suite := &Suite{Name: "bitcoin core key_io_invalid", Version: SuiteVersion}
+	for i, entry := range entries {
+		if len(entry) != 1 {
+			return nil, fmt.Errorf("invalid key_io vectors: entry %d has %d fields, expected 1", i, len(entry))
+		}
+		for _, chain := range []string{"main", "test", "signet", "regtest"} {
+			for _, kind := range []string{KindWIF, KindAddress} {
+				suite.Vectors = append(suite.Vectors, Vector{
+					Kind:    kind,
+					Encoded: entry[0],
+					Network: coreChains[chain],
+					Reason:  "listed in key_io_invalid.json",
+				})
+			}
+		}
+	}
+	return suite, nil
+}
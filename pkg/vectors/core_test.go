@@ -0,0 +1,65 @@
+package vectors
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/fixtures"
+)
+
+// TestCoreVectors runs excerpts of Bitcoin Core's base58 and key_io vectors
+func TestCoreVectors(t *testing.T) {
+	tests := []struct {
+		file string
+		read func(io.Reader) (*Suite, error)
+	}{
+		{"core_base58_encode_decode.json", ReadCoreBase58},
+		{"core_key_io_valid.json", ReadCoreKeyIOValid},
+		{"core_key_io_invalid.json", ReadCoreKeyIOInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			file, err := os.Open(fixtures.Path(tt.file))
+			if err != nil {
+				t.Fatalf("Failed to open vectors: %v", err)
+			}
+			defer file.Close()
+
+			suite, err := tt.read(file)
+			if err != nil {
+				t.Fatalf("Reading vectors failed: %v", err)
+			}
+			report := Run(suite)
+			for _, f := range report.Failures {
+				t.Errorf("Vector #%d (%s %s %q): %v", f.Index, f.Vector.Kind, f.Vector.Network, f.Vector.Encoded, f.Err)
+			}
+			if report.Skipped != 0 || report.Passed != len(suite.Vectors) {
+				t.Errorf("Passed %d and skipped %d of %d vectors", report.Passed, report.Skipped, len(suite.Vectors))
+			}
+		})
+	}
+}
+
+func TestReadCoreVectorsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		read  func(io.Reader) (*Suite, error)
+		input string
+	}{
+		{"base58 not json", ReadCoreBase58, `[`},
+		{"base58 short entry", ReadCoreBase58, `[["61"]]`},
+		{"valid short entry", ReadCoreKeyIOValid, `[["1AGNa15ZQXAZUgFiqJ2i7Z2DPU2J6hW62i", "76a9"]]`},
+		{"valid unknown chain", ReadCoreKeyIOValid, `[["x", "00", {"chain": "moon"}]]`},
+		{"valid bad metadata", ReadCoreKeyIOValid, `[["x", "00", "main"]]`},
+		{"invalid long entry", ReadCoreKeyIOInvalid, `[["x", "y"]]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.read(strings.NewReader(tt.input)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package vectors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Interoperability test vectors
+//
+// A Suite is a machine-readable list of encoding test cases, each either valid
+// (decodes to the given fields and re-encodes to the same string) or invalid
+// (must be rejected, with a human-readable reason). Export produces a suite
+// from this module's encoders; Run checks any suite, including ones produced
+// by other implementations, against this module's decoders.
+
+// SuiteVersion is the format version written by Export
+const SuiteVersion = 1
+
+// Vector kinds understood by the built-in checkers
+const (
+	KindBase58Check = "base58check"
+	KindWIF         = "wif"
+	KindSegWit      = "segwit"
+	KindBase58      = "base58"
+	KindAddress     = "address"
+)
+
+// Vector is a single test case
+//
+// Only the fields relevant to Kind are set:
+//
+//	base58check: Payload, Version
+//	wif:         Payload (private key), Compressed, Network
+//	segwit:      Payload (witness program), Version (witness version), Network
+//	base58:      Payload (decoded bytes)
+//	address:     Payload (scriptPubKey), Network
+type Vector struct {
+	Kind       string `json:"kind"`
+	Encoded    string `json:"encoded"`
+	Valid      bool   `json:"valid"`
+	Reason     string `json:"reason,omitempty"`  // Why an invalid vector must be rejected
+	Payload    string `json:"payload,omitempty"` // Hex
+	Version    *int   `json:"version,omitempty"`
+	Compressed *bool  `json:"compressed,omitempty"`
+	Network    string `json:"network,omitempty"` // chaincfg network name
+}
+
+// Suite is a versioned collection of vectors
+type Suite struct {
+	Name    string   `json:"name"`
+	Version int      `json:"version"`
+	Vectors []Vector `json:"vectors"`
+}
+
+// Checker checks one vector against an implementation
+//
+// It returns nil if the implementation agrees with the vector: a valid vector
+// decodes to its fields and re-encodes to Encoded, an invalid one is rejected.
+type Checker func(v Vector) error
+
+var (
+	checkersMu sync.RWMutex
+	checkers   = map[string]Checker{}
+)
+
+// ErrDuplicateKind is returned when a checker is registered twice for a kind
+var ErrDuplicateKind = errors.New("duplicate vector kind")
+
+// Register adds a checker for a vector kind
+//
+// Example:
+//
+//	vectors.Register("p2sh", func(v vectors.Vector) error { ... })
+func Register(kind string, checker Checker) error {
+	checkersMu.Lock()
+	defer checkersMu.Unlock()
+	if _, ok := checkers[kind]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateKind, kind)
+	}
+	checkers[kind] = checker
+	return nil
+}
+
+// Kinds returns the registered vector kinds, sorted
+func Kinds() []string {
+	checkersMu.RLock()
+	defer checkersMu.RUnlock()
+	kinds := make([]string, 0, len(checkers))
+	for kind := range checkers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Failure is a vector the implementation disagreed with
+type Failure struct {
+	Index  int
+	Vector Vector
+	Err    error
+}
+
+// Report summarizes a run over a suite
+type Report struct {
+	Passed   int
+	Skipped  int // Vectors of kinds without a checker
+	Failures []Failure
+}
+
+// OK reports whether no vector failed
+func (r *Report) OK() bool {
+	return len(r.Failures) == 0
+}
+
+// Run checks every vector of the suite with the registered checkers
+//
+// Example:
+//
+//	suite, _ := vectors.ReadSuite(file)
+//	report := vectors.Run(suite)
+//	for _, f := range report.Failures {
+//		fmt.Printf("#%d %s %q: %v\n", f.Index, f.Vector.Kind, f.Vector.Encoded, f.Err)
+//	}
+func Run(suite *Suite) *Report {
+	report := &Report{}
+	for i, v := range suite.Vectors {
+		checkersMu.RLock()
+		checker, ok := checkers[v.Kind]
+		checkersMu.RUnlock()
+		if !ok {
+			report.Skipped++
+			continue
+		}
+		if err := checker(v); err != nil {
+			report.Failures = append(report.Failures, Failure{Index: i, Vector: v, Err: err})
+			continue
+		}
+		report.Passed++
+	}
+	return report
+}
+
+// ReadSuite parses a JSON suite
+func ReadSuite(r io.Reader) (*Suite, error) {
+	var suite Suite
+	if err := json.NewDecoder(r).Decode(&suite); err != nil {
+		return nil, fmt.Errorf("invalid suite: %w", err)
+	}
+	if suite.Version != SuiteVersion {
+		return nil, fmt.Errorf("unsupported suite version %d", suite.Version)
+	}
+	return &suite, nil
+}
+
+// WriteJSON writes the suite as indented JSON
+func (s *Suite) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
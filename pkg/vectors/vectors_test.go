@@ -0,0 +1,102 @@
+package vectors
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/fixtures"
+)
+
+// TestRunExternalSuite tests the loader against vectors from other implementations
+func TestRunExternalSuite(t *testing.T) {
+	file, err := os.Open(fixtures.Path("external.json"))
+	if err != nil {
+		t.Fatalf("Failed to open suite: %v", err)
+	}
+	defer file.Close()
+
+	suite, err := ReadSuite(file)
+	if err != nil {
+		t.Fatalf("ReadSuite failed: %v", err)
+	}
+	report := Run(suite)
+	for _, f := range report.Failures {
+		t.Errorf("Vector #%d (%s %q): %v", f.Index, f.Vector.Kind, f.Vector.Encoded, f.Err)
+	}
+	if report.Skipped != 1 {
+		t.Errorf("Expected 1 skipped vector of an unknown kind, got %d", report.Skipped)
+	}
+	if report.Passed != len(suite.Vectors)-1 {
+		t.Errorf("Passed = %d, expected %d", report.Passed, len(suite.Vectors)-1)
+	}
+}
+
+func TestReadSuite(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "valid", input: `{"name":"x","version":1,"vectors":[]}`},
+		{name: "unknown version", input: `{"name":"x","version":2,"vectors":[]}`, wantErr: true},
+		{name: "not json", input: `vectors`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadSuite(strings.NewReader(tt.input)); (err != nil) != tt.wantErr {
+				t.Errorf("ReadSuite() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := Register(KindWIF, checkWIF); !errors.Is(err, ErrDuplicateKind) {
+		t.Errorf("Expected ErrDuplicateKind, got %v", err)
+	}
+
+	kind := "test-upper"
+	if err := Register(kind, func(v Vector) error {
+		if (strings.ToUpper(v.Encoded) == v.Encoded) != v.Valid {
+			return errors.New("mismatch")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	found := false
+	for _, k := range Kinds() {
+		found = found || k == kind
+	}
+	if !found {
+		t.Errorf("Kinds() = %v, expected it to contain %q", Kinds(), kind)
+	}
+
+	report := Run(&Suite{Version: SuiteVersion, Vectors: []Vector{
+		{Kind: kind, Encoded: "ABC", Valid: true},
+		{Kind: kind, Encoded: "abc", Valid: true},
+	}})
+	if report.Passed != 1 || len(report.Failures) != 1 || report.Failures[0].Index != 1 || report.OK() {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
+
+func TestWriteJSONRoundTrip(t *testing.T) {
+	suite := Export()
+	var buf bytes.Buffer
+	if err := suite.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	parsed, err := ReadSuite(&buf)
+	if err != nil {
+		t.Fatalf("ReadSuite failed: %v", err)
+	}
+	if len(parsed.Vectors) != len(suite.Vectors) {
+		t.Errorf("Round trip kept %d of %d vectors", len(parsed.Vectors), len(suite.Vectors))
+	}
+}
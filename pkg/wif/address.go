@@ -0,0 +1,162 @@
+package wif
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/base58"
+	"github.com/neverDefined/cryptography-playground/pkg/bech32"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
+	"github.com/neverDefined/cryptography-playground/pkg/schnorr"
+)
+
+// Addresses from a WIF
+//
+// Importing a WIF usually ends with showing the addresses it controls. The
+// compression flag decides which public key the wallet used:
+//
+//	P2PKH   Base58Check(PubKeyHashAddrID, HASH160(pubkey)), with the 33- or 65-byte key the flag selects
+//	P2WPKH  bech32 v0 of HASH160(compressed pubkey); segwit forbids uncompressed keys, so compressed WIFs only
+//	P2TR    bech32m v1 of the BIP86 output key (the internal key tweaked with no script tree)
+//
+// Taproot keys are x-only, so P2TR does not depend on the flag; wallets only
+// pair it with compressed WIFs, but the address is the same either way.
+
+// ErrUncompressedSegwit is returned when asking for the P2WPKH address of an uncompressed WIF
+var ErrUncompressedSegwit = errors.New("segwit addresses require a compressed key")
+
+// Addresses holds the addresses of one key on one network
+//
+// A field is empty when the address does not exist: P2WPKH for an
+// uncompressed WIF, and both segwit fields on networks without segwit.
+type Addresses struct {
+	P2PKH  string
+	P2WPKH string
+	P2TR   string
+}
+
+// DeriveAddresses returns every address of the key in a WIF
+//
+// params selects the network. If it is nil, the network is taken from the
+// version byte, which only works when a single registered network uses it
+// (mainnet, Litecoin, Dogecoin); testnet, signet and regtest WIFs share 0xEF
+// and need explicit params.
+//
+// Example:
+//
+//	addrs, err := DeriveAddresses("KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", nil)
+//	// Result: addrs.P2PKH = "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"
+//	//         addrs.P2WPKH = "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+func DeriveAddresses(wif string, params *chaincfg.Params) (*Addresses, error) {
+	// Step 1: Decode the key for the requested (or inferred) network
+	pub, compressed, params, err := decodePubKey(wif, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: P2PKH exists on every network
+	addrs := &Addresses{P2PKH: p2pkh(pub, compressed, params)}
+	if !params.SupportsSegwit() {
+		return addrs, nil
+	}
+
+	// Step 3: Segwit addresses
+	if compressed {
+		if addrs.P2WPKH, err = p2wpkh(pub, params); err != nil {
+			return nil, err
+		}
+	}
+	if addrs.P2TR, err = p2tr(pub, params); err != nil {
+		return nil, err
+	}
+	return addrs, nil
+}
+
+// P2PKHAddress returns the legacy address of the key in a WIF (see DeriveAddresses for params)
+func P2PKHAddress(wif string, params *chaincfg.Params) (string, error) {
+	pub, compressed, params, err := decodePubKey(wif, params)
+	if err != nil {
+		return "", err
+	}
+	return p2pkh(pub, compressed, params), nil
+}
+
+// P2WPKHAddress returns the native segwit v0 address of the key in a compressed WIF
+func P2WPKHAddress(wif string, params *chaincfg.Params) (string, error) {
+	pub, compressed, params, err := decodePubKey(wif, params)
+	if err != nil {
+		return "", err
+	}
+	if !compressed {
+		return "", ErrUncompressedSegwit
+	}
+	return p2wpkh(pub, params)
+}
+
+// P2TRAddress returns the BIP86 key-path taproot address of the key in a WIF
+func P2TRAddress(wif string, params *chaincfg.Params) (string, error) {
+	pub, _, params, err := decodePubKey(wif, params)
+	if err != nil {
+		return "", err
+	}
+	return p2tr(pub, params)
+}
+
+// decodePubKey decodes a WIF, resolves its network and returns the public key
+func decodePubKey(wif string, params *chaincfg.Params) (*btcec.PublicKey, bool, *chaincfg.Params, error) {
+	// Step 1: Infer the network from the version byte if none was given
+	if params == nil {
+		nets, err := Network(wif)
+		if err != nil {
+			return nil, false, nil, err
+		}
+		if len(nets) > 1 {
+			names := make([]string, len(nets))
+			for i, net := range nets {
+				names[i] = net.Name
+			}
+			return nil, false, nil, fmt.Errorf("WIF version byte is shared by %s: network params are required", strings.Join(names, ", "))
+		}
+		params = nets[0]
+	}
+
+	// Step 2: Decode against that network and derive the public key
+	privateKey, compressed, err := DecodeWithParams(wif, params)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	defer Zeroize(privateKey[:])
+	key, err := privKeyFromBytes(&privateKey)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	return key.PubKey(), compressed, params, nil
+}
+
+// p2pkh encodes HASH160 of the serialization selected by the compression flag
+func p2pkh(pub *btcec.PublicKey, compressed bool, params *chaincfg.Params) string {
+	serialized := pub.SerializeUncompressed()
+	if compressed {
+		serialized = pub.SerializeCompressed()
+	}
+	pubKeyHash := hash.Hash160(serialized)
+	return base58.Base58CheckEncode(params.PubKeyHashAddrID, pubKeyHash[:])
+}
+
+// p2wpkh encodes HASH160 of the compressed key as a segwit v0 program
+func p2wpkh(pub *btcec.PublicKey, params *chaincfg.Params) (string, error) {
+	pubKeyHash := hash.Hash160(pub.SerializeCompressed())
+	return bech32.SegWitAddressEncode(params.Bech32HRPSegwit, 0, pubKeyHash[:])
+}
+
+// p2tr encodes the BIP86 output key as a segwit v1 program
+func p2tr(pub *btcec.PublicKey, params *chaincfg.Params) (string, error) {
+	outputKey, _, err := schnorr.TweakPubKey(pub, nil)
+	if err != nil {
+		return "", err
+	}
+	return bech32.SegWitAddressEncode(params.Bech32HRPSegwit, 1, outputKey[:])
+}
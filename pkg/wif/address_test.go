@@ -0,0 +1,80 @@
+package wif
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+)
+
+// TestDeriveAddresses uses the key 1, whose addresses are well known
+func TestDeriveAddresses(t *testing.T) {
+	tests := []struct {
+		name     string
+		wif      string
+		params   *chaincfg.Params
+		expected Addresses
+	}{
+		{
+			"compressed mainnet", "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", nil,
+			Addresses{
+				P2PKH:  "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+				P2WPKH: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+				P2TR:   "bc1pmfr3p9j00pfxjh0zmgp99y8zftmd3s5pmedqhyptwy6lm87hf5sspknck9",
+			},
+		},
+		{
+			"uncompressed mainnet", "5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf", nil,
+			Addresses{
+				P2PKH: "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm",
+				P2TR:  "bc1pmfr3p9j00pfxjh0zmgp99y8zftmd3s5pmedqhyptwy6lm87hf5sspknck9",
+			},
+		},
+		{
+			"compressed regtest", "cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA", &chaincfg.RegressionNetParams,
+			Addresses{
+				P2PKH:  "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r",
+				P2WPKH: "bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080",
+				P2TR:   "bcrt1pmfr3p9j00pfxjh0zmgp99y8zftmd3s5pmedqhyptwy6lm87hf5ssm803es",
+			},
+		},
+		{
+			"dogecoin has no segwit", "QNcdLVw8fHkixm6NNyN6nVwxKek4u7qrioRbQmjxac5TVoTtZuot", nil,
+			Addresses{P2PKH: "DFpN6QqFfUm3gKNaxN6tNcab1FArL9cZLE"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addrs, err := DeriveAddresses(tt.wif, tt.params)
+			if err != nil {
+				t.Fatalf("DeriveAddresses failed: %v", err)
+			}
+			if *addrs != tt.expected {
+				t.Errorf("DeriveAddresses() = %+v, expected %+v", *addrs, tt.expected)
+			}
+
+			if got, err := P2PKHAddress(tt.wif, tt.params); err != nil || got != tt.expected.P2PKH {
+				t.Errorf("P2PKHAddress() = %s, %v, expected %s", got, err, tt.expected.P2PKH)
+			}
+			if got, err := P2WPKHAddress(tt.wif, tt.params); tt.expected.P2WPKH != "" && (err != nil || got != tt.expected.P2WPKH) {
+				t.Errorf("P2WPKHAddress() = %s, %v, expected %s", got, err, tt.expected.P2WPKH)
+			}
+			if got, err := P2TRAddress(tt.wif, tt.params); tt.expected.P2TR != "" && (err != nil || got != tt.expected.P2TR) {
+				t.Errorf("P2TRAddress() = %s, %v, expected %s", got, err, tt.expected.P2TR)
+			}
+		})
+	}
+
+	// Uncompressed keys have no P2WPKH address
+	if _, err := P2WPKHAddress("5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf", nil); !errors.Is(err, ErrUncompressedSegwit) {
+		t.Errorf("Expected ErrUncompressedSegwit, got %v", err)
+	}
+	// The shared testnet version byte needs explicit params
+	if _, err := DeriveAddresses("cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA", nil); err == nil {
+		t.Error("Expected an error for an ambiguous network")
+	}
+	// Params must match the WIF
+	if _, err := DeriveAddresses("KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", &chaincfg.TestNet3Params); err == nil {
+		t.Error("Expected an error for a mainnet WIF with testnet params")
+	}
+}
@@ -0,0 +1,285 @@
+package wif
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/neverDefined/cryptography-playground/pkg/hash/kdf"
+)
+
+// Keystores
+//
+// Moving keys between wallets means moving many WIFs at once, each with a
+// label saying what it is for. Two file formats are supported:
+//
+//	Lines  one "<wif> <label>" per line; blank lines and lines starting with
+//	       '#' are ignored. Easy to write by hand, never encrypted, and
+//	       without lifecycle metadata.
+//	JSON   {"version": 1, "keys": [{"label": ..., "wif": ...}]}, or, with a
+//	       passphrase, {"version": 1, "kdf": {...}, "salt": ..., "nonce": ...,
+//	       "ciphertext": ...} where the ciphertext is the AES-256-GCM
+//	       encryption of the "keys" array under a kdf.Derive key.
+//
+// The KDF parameters are stored in the file, so files written with today's
+// defaults stay readable after the defaults are raised. Every WIF is checked
+// with Decode on import and export, so a typo fails loudly with its position
+// instead of producing a wallet with a missing key.
+
+const (
+	// keystoreVersion is the "version" field of JSON keystores
+	keystoreVersion = 1
+
+	// keystoreAAD binds the ciphertext to this file format
+	keystoreAAD = "cryptography-playground wif keystore v1"
+)
+
+var (
+	// ErrPassphraseRequired is returned when importing an encrypted keystore without a passphrase
+	ErrPassphraseRequired = errors.New("keystore is encrypted: passphrase required")
+	// ErrWrongPassphrase is returned when the keystore does not decrypt (wrong passphrase or modified file)
+	ErrWrongPassphrase = errors.New("wrong passphrase or corrupted keystore")
+	// ErrInvalidKeystore is returned for malformed keystore files
+	ErrInvalidKeystore = errors.New("invalid keystore")
+)
+
+// Entry is a labelled key in a keystore, with its lifecycle metadata (see Use)
+type Entry struct {
+	Label     string    `json:"label,omitempty"`
+	WIF       string    `json:"wif"`
+	Purpose   string    `json:"purpose,omitempty"`   // Empty means the key may be used for anything
+	CreatedAt time.Time `json:"created_at,omitzero"` // When the key was generated or imported
+	ExpiresAt time.Time `json:"expires_at,omitzero"` // Zero means the key does not expire
+	LastUsed  time.Time `json:"last_used,omitzero"`  // Set by Use
+	UseCount  uint64    `json:"use_count,omitempty"` // Incremented by Use
+	MaxUses   uint64    `json:"max_uses,omitempty"`  // Zero means no limit
+}
+
+// keystoreOptions holds the settings selected with KeystoreOption
+type keystoreOptions struct {
+	passphrase []byte
+	params     *kdf.Params
+}
+
+// KeystoreOption configures ExportJSON and ImportJSON
+type KeystoreOption func(*keystoreOptions)
+
+// WithPassphrase encrypts the keystore on export and decrypts it on import
+func WithPassphrase(passphrase []byte) KeystoreOption {
+	return func(o *keystoreOptions) {
+		o.passphrase = passphrase
+	}
+}
+
+// WithKDF sets the key derivation of an encrypted export (default: kdf.DefaultParams(kdf.Argon2id))
+//
+// Import always uses the parameters stored in the file.
+func WithKDF(params kdf.Params) KeystoreOption {
+	return func(o *keystoreOptions) {
+		o.params = &params
+	}
+}
+
+// keystoreFile is the JSON layout; Keys is set for plaintext files, the rest for encrypted ones
+type keystoreFile struct {
+	Version    int         `json:"version"`
+	Keys       []Entry     `json:"keys,omitempty"`
+	KDF        *kdf.Params `json:"kdf,omitempty"`
+	Salt       []byte      `json:"salt,omitempty"`
+	Nonce      []byte      `json:"nonce,omitempty"`
+	Ciphertext []byte      `json:"ciphertext,omitempty"`
+}
+
+// ExportLines writes entries as "<wif> <label>" lines
+//
+// Example:
+//
+//	err := ExportLines(os.Stdout, []Entry{{Label: "cold storage", WIF: wif}})
+//	// Output: KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn cold storage
+func ExportLines(w io.Writer, entries []Entry) error {
+	// Step 1: Validate everything before writing anything
+	if err := checkEntries(entries); err != nil {
+		return err
+	}
+	for i, entry := range entries {
+		if strings.ContainsAny(entry.Label, "\r\n") {
+			return fmt.Errorf("%w: label of entry %d contains a line break", ErrInvalidKeystore, i)
+		}
+		// Dropping a policy would quietly lift it on import; use ExportJSON instead
+		if entry.hasPolicy() {
+			return fmt.Errorf("%w: entry %d has a usage policy, which lines cannot store", ErrInvalidKeystore, i)
+		}
+	}
+
+	// Step 2: One line per key; a label is optional
+	bw := bufio.NewWriter(w)
+	for _, entry := range entries {
+		line := entry.WIF
+		if entry.Label != "" {
+			line += " " + entry.Label
+		}
+		if _, err := bw.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ImportLines reads "<wif> <label>" lines, skipping blank lines and '#' comments
+func ImportLines(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// The WIF is the first field, the label is the rest of the line
+		wif, label, _ := strings.Cut(line, " ")
+		entry := Entry{WIF: wif, Label: strings.TrimSpace(label)}
+		if _, _, _, err := Decode(entry.WIF); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ExportJSON writes entries as a JSON keystore, encrypted if WithPassphrase is given
+//
+// Example:
+//
+//	err := ExportJSON(f, entries, WithPassphrase([]byte("correct horse battery staple")))
+func ExportJSON(w io.Writer, entries []Entry, opts ...KeystoreOption) error {
+	// Step 1: Validate and apply options
+	if err := checkEntries(entries); err != nil {
+		return err
+	}
+	o := keystoreOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	file := keystoreFile{Version: keystoreVersion}
+
+	// Step 2: Without a passphrase the keys are stored as they are
+	if len(o.passphrase) == 0 {
+		file.Keys = entries
+		return json.NewEncoder(w).Encode(&file)
+	}
+
+	// Step 3: Derive the key from the passphrase and a fresh salt
+	if o.params == nil {
+		params, _ := kdf.DefaultParams(kdf.Argon2id)
+		o.params = &params
+	}
+	o.params.KeyLen = 32
+	salt, err := kdf.NewSalt()
+	if err != nil {
+		return err
+	}
+	aead, err := keystoreAEAD(o.passphrase, salt, *o.params)
+	if err != nil {
+		return err
+	}
+
+	// Step 4: Encrypt the entries
+	plaintext, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	defer Zeroize(plaintext)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	file.KDF, file.Salt, file.Nonce = o.params, salt, nonce
+	file.Ciphertext = aead.Seal(nil, nonce, plaintext, []byte(keystoreAAD))
+	return json.NewEncoder(w).Encode(&file)
+}
+
+// ImportJSON reads a JSON keystore written by ExportJSON
+//
+// Encrypted keystores need WithPassphrase; the KDF parameters come from the
+// file and are bounded by kdf.Params.Validate, so a hostile file cannot make
+// the import use unbounded memory or run for hours.
+func ImportJSON(r io.Reader, opts ...KeystoreOption) ([]Entry, error) {
+	// Step 1: Parse the file
+	var file keystoreFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeystore, err)
+	}
+	if file.Version != keystoreVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidKeystore, file.Version)
+	}
+	o := keystoreOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Step 2: Decrypt the entries if the file is encrypted
+	entries := file.Keys
+	if file.Ciphertext != nil {
+		if len(o.passphrase) == 0 {
+			return nil, ErrPassphraseRequired
+		}
+		if file.KDF == nil || file.KDF.KeyLen != 32 {
+			return nil, fmt.Errorf("%w: missing or unusable kdf parameters", ErrInvalidKeystore)
+		}
+		aead, err := keystoreAEAD(o.passphrase, file.Salt, *file.KDF)
+		if err != nil {
+			return nil, err
+		}
+		if len(file.Nonce) != aead.NonceSize() {
+			return nil, fmt.Errorf("%w: nonce is %d bytes", ErrInvalidKeystore, len(file.Nonce))
+		}
+		plaintext, err := aead.Open(nil, file.Nonce, file.Ciphertext, []byte(keystoreAAD))
+		if err != nil {
+			return nil, ErrWrongPassphrase
+		}
+		defer Zeroize(plaintext)
+		if err := json.Unmarshal(plaintext, &entries); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKeystore, err)
+		}
+	}
+
+	// Step 3: Every key must decode
+	if err := checkEntries(entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// checkEntries decodes every WIF and reports the first that fails
+func checkEntries(entries []Entry) error {
+	for i, entry := range entries {
+		if _, _, _, err := Decode(entry.WIF); err != nil {
+			return fmt.Errorf("entry %d (%q): %w", i, entry.Label, err)
+		}
+	}
+	return nil
+}
+
+// keystoreAEAD derives the AES-256-GCM cipher of an encrypted keystore
+func keystoreAEAD(passphrase, salt []byte, params kdf.Params) (cipher.AEAD, error) {
+	key, err := kdf.Derive(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+	defer Zeroize(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,95 @@
+package wif
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/hash/kdf"
+)
+
+var testEntries = []Entry{
+	{Label: "cold storage", WIF: "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn"},
+	{Label: "", WIF: "5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf"},
+	{Label: "litecoin hot wallet", WIF: "T33ydQRKp4FCW5LCLLUB7deioUMoveiwekdwUwyfRDeGZm76aUjV"},
+}
+
+// testKDF keeps the tests fast; real keystores use the Argon2id default
+var testKDF = kdf.Params{Algorithm: kdf.Scrypt, N: 1 << 10, R: 8, Parallelism: 1, KeyLen: 32}
+
+func TestKeystoreLines(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ExportLines(&buf, testEntries); err != nil {
+		t.Fatalf("ExportLines failed: %v", err)
+	}
+	if first, _, _ := strings.Cut(buf.String(), "\n"); first != "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn cold storage" {
+		t.Errorf("First line = %q", first)
+	}
+
+	// Comments and blank lines are skipped
+	input := "# exported keys\n\n" + buf.String()
+	entries, err := ImportLines(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ImportLines failed: %v", err)
+	}
+	if !slices.Equal(entries, testEntries) {
+		t.Errorf("ImportLines() = %+v, expected %+v", entries, testEntries)
+	}
+
+	// A bad key reports its line
+	if _, err := ImportLines(strings.NewReader("# header\nnot-a-wif label\n")); err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("Expected an error on line 2, got %v", err)
+	}
+	if err := ExportLines(&buf, []Entry{{Label: "two\nlines", WIF: testEntries[0].WIF}}); !errors.Is(err, ErrInvalidKeystore) {
+		t.Errorf("Expected ErrInvalidKeystore for a multi-line label, got %v", err)
+	}
+}
+
+func TestKeystoreJSON(t *testing.T) {
+	// Plaintext
+	var plain bytes.Buffer
+	if err := ExportJSON(&plain, testEntries); err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	entries, err := ImportJSON(bytes.NewReader(plain.Bytes()))
+	if err != nil || !slices.Equal(entries, testEntries) {
+		t.Errorf("ImportJSON() = %+v, %v", entries, err)
+	}
+
+	// Encrypted
+	passphrase := []byte("correct horse battery staple")
+	var sealed bytes.Buffer
+	if err := ExportJSON(&sealed, testEntries, WithPassphrase(passphrase), WithKDF(testKDF)); err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	if strings.Contains(sealed.String(), testEntries[0].WIF) || strings.Contains(sealed.String(), "cold storage") {
+		t.Error("Expected the encrypted keystore not to contain keys or labels")
+	}
+	entries, err = ImportJSON(bytes.NewReader(sealed.Bytes()), WithPassphrase(passphrase))
+	if err != nil || !slices.Equal(entries, testEntries) {
+		t.Errorf("ImportJSON() = %+v, %v", entries, err)
+	}
+
+	if _, err := ImportJSON(bytes.NewReader(sealed.Bytes())); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("Expected ErrPassphraseRequired, got %v", err)
+	}
+	if _, err := ImportJSON(bytes.NewReader(sealed.Bytes()), WithPassphrase([]byte("wrong"))); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Expected ErrWrongPassphrase, got %v", err)
+	}
+
+	// A hostile file cannot make the import derive for hours
+	hostile := strings.Replace(sealed.String(), `"algorithm":"scrypt"`, `"algorithm":"pbkdf2-sha512","iterations":4000000000`, 1)
+	if _, err := ImportJSON(strings.NewReader(hostile), WithPassphrase(passphrase)); !errors.Is(err, kdf.ErrInvalidParams) {
+		t.Errorf("Expected kdf.ErrInvalidParams, got %v", err)
+	}
+
+	// Invalid files and keys
+	if _, err := ImportJSON(strings.NewReader(`{"version": 2}`)); !errors.Is(err, ErrInvalidKeystore) {
+		t.Errorf("Expected ErrInvalidKeystore, got %v", err)
+	}
+	if err := ExportJSON(&plain, []Entry{{WIF: "not-a-wif"}}); err == nil {
+		t.Error("Expected an error for an invalid WIF")
+	}
+}
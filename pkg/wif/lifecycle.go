@@ -0,0 +1,95 @@
+package wif
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// Key lifecycle
+//
+// A key that lives for years should not be used for everything, forever.
+// Each keystore Entry can carry a small policy and a usage record:
+//
+//	Purpose    what the key is for, e.g. "payments" or "auth"; a signer must ask for it by name
+//	ExpiresAt  the key is retired at this time
+//	MaxUses    the key is retired after this many signatures
+//	CreatedAt, LastUsed, UseCount  the record, for audits and rotation reminders
+//
+// Use is the gate in front of the signing APIs: it checks the policy, records
+// the use and only then hands out the private key. The record lives in the
+// Entry, so write the keystore back (ExportJSON) after signing to keep it.
+
+var (
+	// ErrKeyExpired is returned by Use after the ExpiresAt of an entry
+	ErrKeyExpired = errors.New("key has expired")
+	// ErrKeyExhausted is returned by Use once an entry has been used MaxUses times
+	ErrKeyExhausted = errors.New("key has reached its maximum number of uses")
+	// ErrWrongPurpose is returned by Use when the purpose asked for is not the entry's
+	ErrWrongPurpose = errors.New("key is not meant for this purpose")
+)
+
+// NewEntry returns an entry for wif created at now, restricted to purpose if it is not empty
+//
+// Example:
+//
+//	entry, err := NewEntry("KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", "payments", time.Now())
+//	entry.MaxUses = 1000
+func NewEntry(wif, purpose string, now time.Time) (Entry, error) {
+	if _, _, _, err := Decode(wif); err != nil {
+		return Entry{}, err
+	}
+	return Entry{WIF: wif, Purpose: purpose, CreatedAt: now.UTC().Truncate(time.Second)}, nil
+}
+
+// CheckPolicy reports whether the entry may sign for purpose at now, without recording a use
+func (e *Entry) CheckPolicy(purpose string, now time.Time) error {
+	if e.Purpose != "" && purpose != e.Purpose {
+		return fmt.Errorf("%w: %q is for %q, not %q", ErrWrongPurpose, e.Label, e.Purpose, purpose)
+	}
+	if !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt) {
+		return fmt.Errorf("%w: %q expired at %s", ErrKeyExpired, e.Label, e.ExpiresAt.Format(time.RFC3339))
+	}
+	if e.MaxUses != 0 && e.UseCount >= e.MaxUses {
+		return fmt.Errorf("%w: %q has been used %d times", ErrKeyExhausted, e.Label, e.UseCount)
+	}
+	return nil
+}
+
+// Use checks the policy of the entry, records one use at now and returns its private key
+//
+// Call it once per signature and pass the key straight to the signing function.
+// A use is only recorded when the key is handed out.
+//
+// Example:
+//
+//	priv, err := entries[0].Use("payments", time.Now())
+//	if err != nil {
+//		return err // expired, exhausted or the wrong purpose
+//	}
+//	sig, err := schnorr.SignBIP340(msg, priv)
+//	// Then write entries back with ExportJSON to keep the usage record
+func (e *Entry) Use(purpose string, now time.Time) (*btcec.PrivateKey, error) {
+	// Step 1: The policy must allow this use
+	if err := e.CheckPolicy(purpose, now); err != nil {
+		return nil, err
+	}
+
+	// Step 2: Decode the key
+	key, _, _, err := DecodeToPrivKey(e.WIF)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Record the use
+	e.UseCount++
+	e.LastUsed = now.UTC().Truncate(time.Second)
+	return key, nil
+}
+
+// hasPolicy reports whether the entry restricts how its key may be used
+func (e *Entry) hasPolicy() bool {
+	return e.Purpose != "" || !e.ExpiresAt.IsZero() || e.MaxUses != 0
+}
@@ -0,0 +1,106 @@
+package wif
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+func TestEntryUse(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entry, err := NewEntry(testEntries[0].WIF, "payments", created)
+	if err != nil {
+		t.Fatalf("NewEntry failed: %v", err)
+	}
+	entry.Label = "hot wallet"
+	entry.ExpiresAt = created.Add(24 * time.Hour)
+	entry.MaxUses = 2
+
+	// The first uses hand out the key and are recorded
+	now := created.Add(time.Hour)
+	key, err := entry.Use("payments", now)
+	if err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if !key.PubKey().IsEqual(btcec.Generator()) {
+		t.Error("Expected the key 1")
+	}
+	if entry.UseCount != 1 || !entry.LastUsed.Equal(now) {
+		t.Errorf("UseCount = %d, LastUsed = %s", entry.UseCount, entry.LastUsed)
+	}
+
+	// Refused uses are not recorded
+	tests := []struct {
+		name    string
+		purpose string
+		now     time.Time
+		target  error
+	}{
+		{"wrong purpose", "auth", now, ErrWrongPurpose},
+		{"no purpose", "", now, ErrWrongPurpose},
+		{"expired", "payments", entry.ExpiresAt, ErrKeyExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := entry.Use(tt.purpose, tt.now); !errors.Is(err, tt.target) {
+				t.Errorf("Use() error = %v, expected %v", err, tt.target)
+			}
+			if entry.UseCount != 1 {
+				t.Errorf("Expected a refused use not to be counted, got %d", entry.UseCount)
+			}
+		})
+	}
+
+	if _, err := entry.Use("payments", now); err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if _, err := entry.Use("payments", now); !errors.Is(err, ErrKeyExhausted) {
+		t.Errorf("Expected ErrKeyExhausted after MaxUses, got %v", err)
+	}
+
+	// An entry without a policy can be used for anything, any number of times
+	free := Entry{WIF: testEntries[0].WIF}
+	for i := 0; i < 3; i++ {
+		if _, err := free.Use("", now); err != nil {
+			t.Fatalf("Use failed: %v", err)
+		}
+	}
+
+	if _, err := NewEntry("not-a-wif", "", created); err == nil {
+		t.Error("Expected NewEntry to reject an invalid WIF")
+	}
+}
+
+// TestEntryMetadataRoundTrip checks that JSON keystores keep the lifecycle metadata and lines refuse policies
+func TestEntryMetadataRoundTrip(t *testing.T) {
+	entry, _ := NewEntry(testEntries[0].WIF, "payments", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	entry.ExpiresAt = entry.CreatedAt.AddDate(1, 0, 0)
+	entry.MaxUses = 10
+	if _, err := entry.Use("payments", entry.CreatedAt.Add(time.Minute)); err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+
+	for _, opts := range [][]KeystoreOption{nil, {WithPassphrase([]byte("pass")), WithKDF(testKDF)}} {
+		var buf bytes.Buffer
+		if err := ExportJSON(&buf, []Entry{entry}, opts...); err != nil {
+			t.Fatalf("ExportJSON failed: %v", err)
+		}
+		entries, err := ImportJSON(&buf, opts...)
+		if err != nil {
+			t.Fatalf("ImportJSON failed: %v", err)
+		}
+		got := entries[0]
+		if got.Purpose != entry.Purpose || got.UseCount != 1 || got.MaxUses != 10 ||
+			!got.CreatedAt.Equal(entry.CreatedAt) || !got.ExpiresAt.Equal(entry.ExpiresAt) || !got.LastUsed.Equal(entry.LastUsed) {
+			t.Errorf("ImportJSON() = %+v, expected %+v", got, entry)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := ExportLines(&buf, []Entry{entry}); !errors.Is(err, ErrInvalidKeystore) {
+		t.Errorf("Expected ExportLines to refuse an entry with a policy, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package wif
+
+import (
+	"errors"
+
+	"github.com/neverDefined/cryptography-playground/pkg/base58"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+)
+
+// Strict and lenient decoding
+//
+// By default Decode is strict: the version byte must belong to a registered
+// network and the payload must be a 32-byte key, optionally followed by the
+// 0x01 compression flag. That is what a wallet importing a key wants.
+//
+// Recovery tools want the opposite. A WIF from an unregistered altcoin, or
+// one written by buggy software with a wrong flag byte or trailing garbage,
+// still holds a key as long as the checksum matches. WithLenient keeps only
+// that check: any version is returned as is, the key is the first 32 bytes
+// of the payload, and it is compressed if the 33rd byte is 0x01. DecodeRaw
+// returns the version and the whole payload for inspection.
+
+var (
+	// ErrUnknownVersion is returned in strict mode for a version byte no registered network
+	// uses, and by DecodeWithParams for a version byte of another network
+	ErrUnknownVersion = errors.New("unknown WIF version byte")
+	// ErrInvalidPayload is returned for a payload that is not a key with an optional 0x01 flag
+	ErrInvalidPayload = errors.New("invalid WIF payload")
+)
+
+// decodeOptions holds the settings selected with DecodeOption
+type decodeOptions struct {
+	lenient bool
+	params  *chaincfg.Params // Set by DecodeWithParams: the version must be this network's
+}
+
+// DecodeOption configures Decode, DecodeInto and DecodeRaw
+type DecodeOption func(*decodeOptions)
+
+// WithStrict rejects unknown version bytes and non-canonical payloads (the default)
+func WithStrict() DecodeOption {
+	return func(o *decodeOptions) {
+		o.lenient = false
+	}
+}
+
+// WithLenient accepts any version byte and any payload of at least 32 bytes
+//
+// Example:
+//
+//	key, compressed, version, err := Decode(oddWIF, WithLenient())
+//	// version is whatever the WIF carries, e.g. 0x41 for an unregistered coin
+func WithLenient() DecodeOption {
+	return func(o *decodeOptions) {
+		o.lenient = true
+	}
+}
+
+func newDecodeOptions(opts []DecodeOption) decodeOptions {
+	var o decodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Raw is a WIF split into its version byte and payload, without interpretation
+//
+// Payload holds key material; Zeroize it when done.
+type Raw struct {
+	Version byte
+	Payload []byte
+}
+
+// DecodeRaw verifies the checksum of a WIF and returns its version and payload
+//
+// In strict mode (the default) the payload and version are validated like
+// Decode does; with WithLenient nothing but the checksum is checked, so even
+// a payload too short for a key is returned.
+//
+// Example:
+//
+//	raw, err := DecodeRaw(suspicious, WithLenient())
+//	// raw.Version = 0x80, len(raw.Payload) = 34 (a key, the flag and a stray byte)
+func DecodeRaw(wif string, opts ...DecodeOption) (*Raw, error) {
+	payload, version, err := base58.Base58CheckDecode(wif)
+	if err != nil {
+		return nil, err
+	}
+	if o := newDecodeOptions(opts); !o.lenient {
+		if _, err := checkPayload(payload, version, o); err != nil {
+			Zeroize(payload)
+			return nil, err
+		}
+	}
+	return &Raw{Version: version, Payload: payload}, nil
+}
@@ -0,0 +1,86 @@
+package wif
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/neverDefined/cryptography-playground/pkg/base58"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+)
+
+func TestDecodeModes(t *testing.T) {
+	key := bytes.Repeat([]byte{0x11}, 32)
+	tests := []struct {
+		name       string
+		version    byte
+		payload    []byte
+		strictErr  error
+		compressed bool
+	}{
+		{"canonical", 0x80, append(bytes.Clone(key), 0x01), nil, true},
+		{"unknown version", 0x41, append(bytes.Clone(key), 0x01), ErrUnknownVersion, true},
+		{"bad flag", 0x80, append(bytes.Clone(key), 0x02), ErrInvalidPayload, false},
+		{"trailing byte", 0x80, append(bytes.Clone(key), 0x01, 0xff), ErrInvalidPayload, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wif := base58.Base58CheckEncode(tt.version, tt.payload)
+
+			// Strict is the default and matches WithStrict
+			for _, opts := range [][]DecodeOption{nil, {WithStrict()}} {
+				_, _, _, err := Decode(wif, opts...)
+				if tt.strictErr == nil && err != nil || tt.strictErr != nil && !errors.Is(err, tt.strictErr) {
+					t.Errorf("Decode() error = %v, expected %v", err, tt.strictErr)
+				}
+				if _, err := DecodeRaw(wif, opts...); tt.strictErr != nil && !errors.Is(err, tt.strictErr) {
+					t.Errorf("DecodeRaw() error = %v, expected %v", err, tt.strictErr)
+				}
+			}
+
+			// DecodeWithParams applies the same checks against a single network
+			_, _, err := DecodeWithParams(wif, &chaincfg.MainNetParams)
+			if tt.strictErr == nil && err != nil || tt.strictErr != nil && !errors.Is(err, tt.strictErr) {
+				t.Errorf("DecodeWithParams() error = %v, expected %v", err, tt.strictErr)
+			}
+
+			// Lenient mode returns the key, the raw version and the flag it finds
+			decoded, compressed, version, err := Decode(wif, WithLenient())
+			if err != nil {
+				t.Fatalf("Decode(WithLenient()) error = %v", err)
+			}
+			if !bytes.Equal(decoded[:], key) || compressed != tt.compressed || version != tt.version {
+				t.Errorf("Decode(WithLenient()) = %x, %v, 0x%02x", decoded, compressed, version)
+			}
+			raw, err := DecodeRaw(wif, WithLenient())
+			if err != nil || raw.Version != tt.version || !bytes.Equal(raw.Payload, tt.payload) {
+				t.Errorf("DecodeRaw(WithLenient()) = %+v, %v", raw, err)
+			}
+		})
+	}
+
+	// An unregistered network decodes with its own params, and only with them
+	custom := chaincfg.MainNetParams
+	custom.Name, custom.PrivateKeyID = "custom", 0x41
+	unregistered := base58.Base58CheckEncode(0x41, key)
+	if decoded, compressed, err := DecodeWithParams(unregistered, &custom); err != nil || compressed || !bytes.Equal(decoded[:], key) {
+		t.Errorf("DecodeWithParams(custom) = %x, %v, %v", decoded, compressed, err)
+	}
+	if _, _, err := DecodeWithParams(unregistered, &chaincfg.MainNetParams); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("Expected ErrUnknownVersion, got %v", err)
+	}
+
+	// A payload too short for a key only decodes raw
+	short := base58.Base58CheckEncode(0x80, key[:20])
+	if _, _, _, err := Decode(short, WithLenient()); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("Expected ErrInvalidPayload, got %v", err)
+	}
+	if raw, err := DecodeRaw(short, WithLenient()); err != nil || len(raw.Payload) != 20 {
+		t.Errorf("DecodeRaw(WithLenient()) = %+v, %v", raw, err)
+	}
+
+	// The checksum is checked in both modes
+	if _, err := DecodeRaw("KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWo", WithLenient()); !errors.Is(err, base58.ErrChecksumMismatch) {
+		t.Errorf("Expected ErrChecksumMismatch, got %v", err)
+	}
+}
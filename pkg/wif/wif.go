@@ -2,8 +2,13 @@ package wif
 
 import (
 	"errors"
+	"fmt"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/arithmetic"
 	"github.com/neverDefined/cryptography-playground/pkg/base58"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
+	"github.com/neverDefined/cryptography-playground/pkg/hash"
 )
 
 const (
@@ -21,75 +26,242 @@ const (
 //	wif, err := Encode(privateKey, true, false)  // compressed, mainnet
 //	// Result: "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn"
 func Encode(privateKey []byte, compressed bool, testnet bool) (string, error) {
+	// Choose network parameters: 0x80 = mainnet, 0xEF = testnet
+	params := &chaincfg.MainNetParams
+	if testnet {
+		params = &chaincfg.TestNet3Params
+	}
+	return EncodeWithParams(privateKey, compressed, params)
+}
+
+// EncodeWithParams converts a private key to WIF using the version byte of a network
+//
+// Example:
+//
+//	wif, err := EncodeWithParams(privateKey, true, &chaincfg.RegressionNetParams)
+//	// Result: "c..." (testnet-style compressed WIF)
+func EncodeWithParams(privateKey []byte, compressed bool, params *chaincfg.Params) (string, error) {
 	// Step 1: Validate private key length (must be exactly 32 bytes)
 	if len(privateKey) != 32 {
 		return "", errors.New("private key must be 32 bytes")
 	}
-
-	// Step 2: Choose version byte based on network
-	// 0x80 = mainnet, 0xEF = testnet
-	var version byte = MAINNET_VERSION
-	if testnet {
-		version = TESTNET_VERSION
+	if params == nil {
+		return "", errors.New("network params are required")
 	}
 
-	// Step 3: Build payload: [version][private_key][compression_flag]
-	// Start with private key (32 bytes)
-	payload := make([]byte, 32)
-	copy(payload, privateKey)
+	// Step 2: Build [version][private_key][compression_flag][checksum] in a stack buffer,
+	// instead of letting Base58CheckEncode make copies of the key that are never wiped
+	var buf [38]byte
+	defer Zeroize(buf[:])
+	data := append(buf[:0], params.PrivateKeyID)
+	data = append(data, privateKey...)
 
 	// Add compression flag if needed (0x01 for compressed public keys)
 	if compressed {
-		payload = append(payload, 0x01)
+		data = append(data, 0x01)
 	}
+	checksum := hash.Checksum4(data)
+	data = append(data, checksum[:]...)
 
-	// Step 4: Encode to Base58Check format
-	return base58.Base58CheckEncode(version, payload), nil
+	// Step 3: Encode to Base58 (the big.Int temporaries are wiped when released to the pool)
+	return base58.Encode(data), nil
 }
 
 // Decode converts a WIF (Wallet Import Format) string to a 32-byte private key and metadata
 //
 // WIF format: Base58Check decode → [version][private_key][compression_flag]
 //
+// The version byte must belong to a network in the chaincfg registry, which
+// includes Litecoin, Dogecoin and anything added with chaincfg.Register. Use
+// Network to find which one, or DecodeWithParams to require a specific one.
+// WithLenient accepts unknown versions and non-canonical payloads.
+//
 // Example:
 //
 //	wif := "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn"
 //	privateKey, compressed, version, err := Decode(wif)
 //	// Result: privateKey = [32]byte{0x12, 0x34, 0x56, ...}, compressed = true, version = 0x80
-func Decode(wif string) ([32]byte, bool, byte, error) {
+func Decode(wif string, opts ...DecodeOption) ([32]byte, bool, byte, error) {
+	var privateKey [32]byte
+	compressed, version, err := DecodeInto(&privateKey, wif, opts...)
+	if err != nil {
+		return [32]byte{}, false, 0, err
+	}
+	return privateKey, compressed, version, nil
+}
+
+// DecodeInto is Decode writing the private key into a caller-provided buffer
+//
+// The decoded payload is wiped before returning, so the only copy of the key
+// left by decoding is dst, which the caller can Zeroize when done. dst is
+// only written on success.
+//
+// Example:
+//
+//	var key [32]byte
+//	defer Zeroize(key[:])
+//	compressed, version, err := DecodeInto(&key, wif)
+func DecodeInto(dst *[32]byte, wif string, opts ...DecodeOption) (bool, byte, error) {
 	// Step 1: Decode Base58Check string to get payload and version
 	// This validates the checksum and extracts the raw bytes
 	payload, version, err := base58.Base58CheckDecode(wif)
 	if err != nil {
-		return [32]byte{}, false, 0, err
+		return false, 0, err
+	}
+	defer Zeroize(payload)
+
+	// Step 2: Validate the payload and version (or only what lenient mode needs)
+	compressed, err := checkPayload(payload, version, newDecodeOptions(opts))
+	if err != nil {
+		return false, 0, err
+	}
+
+	// Step 3: Extract the 32-byte private key
+	// For compressed: payload[0:32] (first 32 bytes)
+	// For uncompressed: payload[0:32] (all 32 bytes)
+	copy(dst[:], payload[:32])
+
+	return compressed, version, nil
+}
+
+// checkPayload validates a decoded payload and reports the compression flag
+func checkPayload(payload []byte, version byte, o decodeOptions) (bool, error) {
+	// Lenient mode: any version, and any payload that holds a key
+	if o.lenient {
+		if len(payload) < 32 {
+			return false, fmt.Errorf("%w: %d bytes is too short for a key", ErrInvalidPayload, len(payload))
+		}
+		return len(payload) > 32 && payload[32] == 0x01, nil
 	}
 
-	// Step 2: Determine compression and validate payload length
+	// Step 1: Determine compression and validate payload length
 	// WIF can be 32 bytes (uncompressed) or 33 bytes (compressed with 0x01 flag)
 	var compressed bool
 	if len(payload) == 33 {
-		// Step 2a: Check if the last byte is the compression flag (0x01)
+		// Step 1a: Check if the last byte is the compression flag (0x01)
 		flag := payload[len(payload)-1]
 		if flag != 0x01 {
-			return [32]byte{}, false, 0, errors.New("invalid compression flag: expected 0x01")
+			return false, fmt.Errorf("%w: invalid compression flag 0x%02x, expected 0x01", ErrInvalidPayload, flag)
 		}
 		compressed = true
 	} else if len(payload) != 32 {
-		// Step 2b: Payload must be exactly 32 or 33 bytes
-		return [32]byte{}, false, 0, errors.New("invalid payload length: expected 32 or 33 bytes")
+		// Step 1b: Payload must be exactly 32 or 33 bytes
+		return false, fmt.Errorf("%w: %d bytes, expected 32 or 33", ErrInvalidPayload, len(payload))
 	}
 
-	// Step 3: Extract the 32-byte private key
-	// For compressed: payload[0:32] (first 32 bytes)
-	// For uncompressed: payload[0:32] (all 32 bytes)
+	// Step 2: Validate version byte
+	// 0x80 = mainnet, 0xEF = testnet, or the PrivateKeyID of any registered network (0xB0 = Litecoin, ...)
+	if o.params != nil {
+		if version != o.params.PrivateKeyID {
+			return false, fmt.Errorf("%w: 0x%02x does not match %s (0x%02x)", ErrUnknownVersion, version, o.params.Name, o.params.PrivateKeyID)
+		}
+		return compressed, nil
+	}
+	if len(chaincfg.ParamsForPrivateKeyID(version)) == 0 {
+		return false, fmt.Errorf("%w: 0x%02x, no registered network uses it", ErrUnknownVersion, version)
+	}
+	return compressed, nil
+}
+
+// DecodeWithParams decodes a WIF string and checks that it belongs to a network
+//
+// Example:
+//
+//	privateKey, compressed, err := DecodeWithParams(wif, &chaincfg.MainNetParams)
+func DecodeWithParams(wif string, params *chaincfg.Params) ([32]byte, bool, error) {
+	if params == nil {
+		return [32]byte{}, false, errors.New("network params are required")
+	}
+
+	// Decode strictly, checking the version against params instead of the registry,
+	// so unregistered custom networks work too
 	var privateKey [32]byte
-	copy(privateKey[:], payload[:32])
+	compressed, _, err := DecodeInto(&privateKey, wif, func(o *decodeOptions) {
+		o.params = params
+	})
+	if err != nil {
+		return [32]byte{}, false, err
+	}
+	return privateKey, compressed, nil
+}
 
-	// Step 4: Validate version byte and return it
-	// 0x80 = mainnet, 0xEF = testnet
-	if version != MAINNET_VERSION && version != TESTNET_VERSION {
-		return [32]byte{}, false, 0, errors.New("invalid version byte: expected 0x80 (mainnet) or 0xEF (testnet)")
+// Network returns the registered networks a WIF string can belong to
+//
+// The checksum is verified, but not the payload. Bitcoin's test networks (and
+// Litecoin's testnet) share the version byte 0xEF, so a testnet WIF returns
+// all of them; a mainnet WIF returns exactly one network.
+//
+// Example:
+//
+//	nets, err := Network("T33ydQRKp4FCW5LCLLUB7deioUMoveiwekdwUwyfRDeGZm76aUjV")
+//	// Result: nets = [&chaincfg.LitecoinMainNetParams]
+func Network(wif string) ([]*chaincfg.Params, error) {
+	_, version, err := base58.Base58CheckDecode(wif)
+	if err != nil {
+		return nil, err
 	}
+	nets := chaincfg.ParamsForPrivateKeyID(version)
+	if len(nets) == 0 {
+		return nil, fmt.Errorf("%w: no network with WIF version byte 0x%02x", chaincfg.ErrUnknownNet, version)
+	}
+	return nets, nil
+}
 
-	return privateKey, compressed, version, nil
+// EncodePrivKey converts a btcec private key to WIF for a network
+//
+// Example:
+//
+//	key, _ := btcec.NewPrivateKey()
+//	wif, err := EncodePrivKey(key, true, &chaincfg.MainNetParams)
+//	// Result: "K..." or "L..." (compressed mainnet WIF)
+func EncodePrivKey(key *btcec.PrivateKey, compressed bool, params *chaincfg.Params) (string, error) {
+	if key == nil {
+		return "", errors.New("private key is required")
+	}
+	privateKey := key.Key.Bytes()
+	defer Zeroize(privateKey[:])
+	return EncodeWithParams(privateKey[:], compressed, params)
+}
+
+// DecodeToPrivKey converts a WIF string to a btcec private key, like Decode
+//
+// Unlike btcec.PrivKeyFromBytes, which reduces its input mod n, it rejects
+// keys that are zero or not below the curve order.
+//
+// Example:
+//
+//	key, compressed, version, err := DecodeToPrivKey("KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn")
+//	// Result: key.PubKey() is the generator G, compressed = true, version = 0x80
+func DecodeToPrivKey(wif string) (*btcec.PrivateKey, bool, byte, error) {
+	// Step 1: Decode the raw key and metadata
+	privateKey, compressed, version, err := Decode(wif)
+	if err != nil {
+		return nil, false, 0, err
+	}
+	defer Zeroize(privateKey[:])
+
+	// Step 2: The key must be a valid scalar in [1, n-1]
+	key, err := privKeyFromBytes(&privateKey)
+	if err != nil {
+		return nil, false, 0, err
+	}
+	return key, compressed, version, nil
+}
+
+// privKeyFromBytes converts a 32-byte key, rejecting zero and values not below the curve order
+func privKeyFromBytes(privateKey *[32]byte) (*btcec.PrivateKey, error) {
+	var scalar btcec.ModNScalar
+	defer scalar.Zero()
+	if overflow := scalar.SetBytes(privateKey); overflow != 0 || scalar.IsZero() {
+		return nil, errors.New("private key is not in the range [1, n-1]")
+	}
+	return btcec.PrivKeyFromScalar(&scalar), nil
+}
+
+// Zeroize overwrites b with zeros, like arithmetic.ZeroBytes
+//
+// Use it on key buffers once they are no longer needed. Go strings cannot be
+// wiped, so keep WIFs in []byte where possible.
+func Zeroize(b []byte) {
+	arithmetic.ZeroBytes(b)
 }
@@ -2,9 +2,12 @@ package wif
 
 import (
 	"encoding/hex"
+	"errors"
+	"slices"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/neverDefined/cryptography-playground/pkg/chaincfg"
 )
 
 // TestWIFEncodeDecodeRoundTrip tests the complete WIF workflow with proper Bitcoin key pairs
@@ -295,3 +298,214 @@ func ExampleDecode() {
 	println("Compressed:", compressed)
 	println("Version:", hex.EncodeToString([]byte{version}))
 }
+
+// TestWIFWithParams tests encoding and decoding against explicit network parameters
+func TestWIFWithParams(t *testing.T) {
+	privateKey := make([]byte, 32)
+	privateKey[31] = 0x01
+
+	tests := []struct {
+		params   *chaincfg.Params
+		expected string
+	}{
+		{&chaincfg.MainNetParams, "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn"},
+		{&chaincfg.TestNet3Params, "cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA"},
+		{&chaincfg.RegressionNetParams, "cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.params.Name, func(t *testing.T) {
+			wif, err := EncodeWithParams(privateKey, true, tt.params)
+			if err != nil {
+				t.Fatalf("EncodeWithParams failed: %v", err)
+			}
+			if wif != tt.expected {
+				t.Errorf("EncodeWithParams() = %s, expected %s", wif, tt.expected)
+			}
+
+			decoded, compressed, err := DecodeWithParams(wif, tt.params)
+			if err != nil {
+				t.Fatalf("DecodeWithParams failed: %v", err)
+			}
+			if !compressed || !compareBytes(decoded[:], privateKey) {
+				t.Error("Round trip mismatch")
+			}
+		})
+	}
+
+	// A mainnet key must not decode as testnet
+	if _, _, err := DecodeWithParams(tests[0].expected, &chaincfg.TestNet3Params); err == nil {
+		t.Error("Expected error decoding a mainnet WIF with testnet params")
+	}
+	if _, err := EncodeWithParams(privateKey, true, nil); err == nil {
+		t.Error("Expected error for nil params")
+	}
+}
+
+// TestWIFAltcoinPrefixes tests the leading characters produced by altcoin version bytes
+func TestWIFAltcoinPrefixes(t *testing.T) {
+	privateKey := make([]byte, 32)
+	privateKey[31] = 0x01
+
+	tests := []struct {
+		params       *chaincfg.Params
+		compressed   string
+		uncompressed string
+	}{
+		{&chaincfg.LitecoinMainNetParams, "T", "6"},
+		{&chaincfg.DogecoinMainNetParams, "Q", "6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.params.Name, func(t *testing.T) {
+			for _, compressed := range []bool{true, false} {
+				wif, err := EncodeWithParams(privateKey, compressed, tt.params)
+				if err != nil {
+					t.Fatalf("EncodeWithParams failed: %v", err)
+				}
+				expected := tt.uncompressed
+				if compressed {
+					expected = tt.compressed
+				}
+				if wif[:1] != expected {
+					t.Errorf("WIF %s should start with %s", wif, expected)
+				}
+				if _, _, err := DecodeWithParams(wif, tt.params); err != nil {
+					t.Errorf("DecodeWithParams failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// TestWIFNetworkRegistry tests that Decode accepts every registered network and Network identifies it
+func TestWIFNetworkRegistry(t *testing.T) {
+	privateKey := make([]byte, 32)
+	privateKey[31] = 0x01
+
+	tests := []struct {
+		wif      string
+		version  byte
+		networks []string
+	}{
+		{"KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", 0x80, []string{"mainnet"}},
+		{"T33ydQRKp4FCW5LCLLUB7deioUMoveiwekdwUwyfRDeGZm76aUjV", 0xb0, []string{"litecoin"}},
+		{"QNcdLVw8fHkixm6NNyN6nVwxKek4u7qrioRbQmjxac5TVoTtZuot", 0x9e, []string{"dogecoin"}},
+		{"cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA", 0xef, []string{"litecoin-testnet", "regtest", "signet", "testnet3", "testnet4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.networks[0], func(t *testing.T) {
+			decoded, compressed, version, err := Decode(tt.wif)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if version != tt.version || !compressed || !compareBytes(decoded[:], privateKey) {
+				t.Errorf("Decode() = %x, %v, 0x%02x", decoded, compressed, version)
+			}
+
+			nets, err := Network(tt.wif)
+			if err != nil {
+				t.Fatalf("Network failed: %v", err)
+			}
+			names := make([]string, len(nets))
+			for i, params := range nets {
+				names[i] = params.Name
+			}
+			for _, name := range tt.networks {
+				if !slices.Contains(names, name) {
+					t.Errorf("Network() = %v, expected it to include %s", names, name)
+				}
+			}
+		})
+	}
+
+	// A network added at runtime round-trips once registered
+	custom := chaincfg.RegressionNetParams
+	custom.Name = "wif-custom"
+	custom.PrivateKeyID = 0x42
+	wif, err := EncodeWithParams(privateKey, true, &custom)
+	if err != nil {
+		t.Fatalf("EncodeWithParams failed: %v", err)
+	}
+	if _, _, _, err := Decode(wif); err == nil {
+		t.Error("Expected an unregistered version byte to be rejected")
+	}
+	if _, err := Network(wif); !errors.Is(err, chaincfg.ErrUnknownNet) {
+		t.Errorf("Expected ErrUnknownNet, got %v", err)
+	}
+	if err := chaincfg.Register(&custom); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if _, _, version, err := Decode(wif); err != nil || version != 0x42 {
+		t.Errorf("Decode() = 0x%02x, %v, expected 0x42", version, err)
+	}
+}
+
+// TestWIFPrivKey tests encoding and decoding btcec keys directly
+func TestWIFPrivKey(t *testing.T) {
+	key, err := btcec.NewPrivateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate private key: %v", err)
+	}
+
+	for _, compressed := range []bool{true, false} {
+		wif, err := EncodePrivKey(key, compressed, &chaincfg.TestNet3Params)
+		if err != nil {
+			t.Fatalf("EncodePrivKey failed: %v", err)
+		}
+		decoded, gotCompressed, version, err := DecodeToPrivKey(wif)
+		if err != nil {
+			t.Fatalf("DecodeToPrivKey failed: %v", err)
+		}
+		if !decoded.PubKey().IsEqual(key.PubKey()) || gotCompressed != compressed || version != TESTNET_VERSION {
+			t.Errorf("Round trip mismatch for compressed = %v", compressed)
+		}
+	}
+
+	// The key 1 is the generator
+	one, _, _, err := DecodeToPrivKey("KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn")
+	if err != nil {
+		t.Fatalf("DecodeToPrivKey failed: %v", err)
+	}
+	if hex.EncodeToString(one.PubKey().SerializeCompressed()) != "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" {
+		t.Errorf("Expected G, got %x", one.PubKey().SerializeCompressed())
+	}
+
+	// Zero and the curve order are valid WIF payloads but not valid keys
+	order, _ := hex.DecodeString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	for _, raw := range [][]byte{make([]byte, 32), order} {
+		wif, _ := EncodeWithParams(raw, true, &chaincfg.MainNetParams)
+		if _, _, _, err := DecodeToPrivKey(wif); err == nil {
+			t.Errorf("Expected an error for the key %x", raw)
+		}
+	}
+	if _, err := EncodePrivKey(nil, true, &chaincfg.MainNetParams); err == nil {
+		t.Error("Expected an error for a nil key")
+	}
+}
+
+// TestWIFDecodeInto tests decoding into a caller buffer and zeroization
+func TestWIFDecodeInto(t *testing.T) {
+	var key [32]byte
+	compressed, version, err := DecodeInto(&key, "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn")
+	if err != nil {
+		t.Fatalf("DecodeInto failed: %v", err)
+	}
+	if key != [32]byte{31: 0x01} || !compressed || version != MAINNET_VERSION {
+		t.Errorf("DecodeInto() = %x, %v, 0x%02x", key, compressed, version)
+	}
+
+	// Errors leave dst untouched
+	sentinel := [32]byte{0xaa}
+	if _, _, err := DecodeInto(&sentinel, "invalid-wif-string"); err == nil {
+		t.Error("Expected error for invalid WIF string")
+	}
+	if sentinel != [32]byte{0xaa} {
+		t.Errorf("Expected dst to be unchanged, got %x", sentinel)
+	}
+
+	Zeroize(key[:])
+	if key != [32]byte{} {
+		t.Errorf("Zeroize() left %x", key)
+	}
+}